@@ -101,7 +101,8 @@ install_claude_hook() {
   # Create a temporary file for atomic update
   local temp_file=$(mktemp)
   
-  # Configure Claude Code hook - add Stop hook
+  # Configure Claude Code hook - add Stop and Notification hooks; the
+  # Notification hook marks the session as waiting for input
   jq --arg hook_path "$sandbox_hook" '
     .hooks.Stop = [
       {
@@ -112,12 +113,22 @@ install_claude_hook() {
           }
         ]
       }
+    ] |
+    .hooks.Notification = [
+      {
+        "hooks": [
+          {
+            "type": "command",
+            "command": $hook_path
+          }
+        ]
+      }
     ]
   ' "$claude_config" > "$temp_file"
   
   if [ $? -eq 0 ]; then
     mv "$temp_file" "$claude_config"
-    echo "Claude Code hook configured in sandbox: Stop -> $sandbox_hook"
+    echo "Claude Code hook configured in sandbox: Stop, Notification -> $sandbox_hook"
   else
     echo "Warning: Failed to configure Claude Code hook"
     rm -f "$temp_file"
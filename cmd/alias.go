@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage short names for work item IDs",
+	Long: `Manage per-repository aliases for work item IDs. Aliases are personal and
+stored in aliases.json in the state directory, keyed by repository, and
+can be used anywhere a work item ID is accepted:
+
+  sbs alias add fix1 github:1234
+  sbs attach fix1
+
+Sessions can also be addressed by the short index shown in 'sbs list':
+
+  sbs attach %3`,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:         "add <alias> <work-item-id>",
+	Short:       "Add or replace an alias",
+	Args:        cobra.ExactArgs(2),
+	RunE:        runAliasAdd,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:         "rm <alias>",
+	Aliases:     []string{"remove"},
+	Short:       "Remove an alias",
+	Args:        cobra.ExactArgs(1),
+	RunE:        runAliasRemove,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:         "list",
+	Aliases:     []string{"ls"},
+	Short:       "List the repository's aliases",
+	Args:        cobra.NoArgs,
+	RunE:        runAliasList,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasAddCmd, aliasRemoveCmd, aliasListCmd)
+}
+
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	name, target := args[0], args[1]
+	if err := config.ValidateAliasName(name); err != nil {
+		return err
+	}
+	parsed, err := inputsource.ParseWorkItemID(target)
+	if err != nil {
+		return err
+	}
+	target = parsed.FullID()
+
+	repoRoot, aliases, err := loadRepositoryAliases()
+	if err != nil {
+		return err
+	}
+
+	aliases[name] = target
+	if err := config.SaveAliases(repoRoot, aliases); err != nil {
+		return fmt.Errorf("failed to save aliases: %w", err)
+	}
+	fmt.Printf("Alias %s -> %s\n", name, target)
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	repoRoot, aliases, err := loadRepositoryAliases()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := aliases[args[0]]; !ok {
+		return fmt.Errorf("no alias named %s", args[0])
+	}
+	delete(aliases, args[0])
+	if err := config.SaveAliases(repoRoot, aliases); err != nil {
+		return fmt.Errorf("failed to save aliases: %w", err)
+	}
+	fmt.Printf("Removed alias %s\n", args[0])
+	return nil
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	_, aliases, err := loadRepositoryAliases()
+	if err != nil {
+		return err
+	}
+
+	if len(aliases) == 0 {
+		fmt.Println("No aliases defined. Add one with 'sbs alias add <alias> <work-item-id>'.")
+		return nil
+	}
+	for _, line := range formatAliases(aliases) {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// loadRepositoryAliases loads the aliases of the current repository
+func loadRepositoryAliases() (string, map[string]string, error) {
+	currentRepo, err := appServices().Repository()
+	if err != nil {
+		return "", nil, fmt.Errorf("aliases are per repository; must be run from within a git repository: %w", err)
+	}
+
+	aliases, err := config.LoadAliases(currentRepo.Root)
+	if err != nil {
+		return "", nil, err
+	}
+	return currentRepo.Root, aliases, nil
+}
+
+// formatAliases renders aliases as sorted, aligned "alias -> id" lines
+func formatAliases(aliases map[string]string) []string {
+	names := make([]string, 0, len(aliases))
+	width := 0
+	for name := range aliases {
+		names = append(names, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%-*s -> %s", width, name, aliases[name]))
+	}
+	return lines
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasCommand_Structure(t *testing.T) {
+	names := make([]string, 0, len(aliasCmd.Commands()))
+	for _, sub := range aliasCmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"add", "rm", "list"}, names)
+
+	assert.Error(t, aliasAddCmd.Args(aliasAddCmd, []string{"fix1"}))
+	assert.NoError(t, aliasAddCmd.Args(aliasAddCmd, []string{"fix1", "github:1234"}))
+	assert.Error(t, aliasRemoveCmd.Args(aliasRemoveCmd, []string{}))
+}
+
+func TestRunAliasAdd_RejectsInvalidInput(t *testing.T) {
+	err := runAliasAdd(aliasAddCmd, []string{"%1", "github:1234"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reserved for short session indexes")
+
+	err = runAliasAdd(aliasAddCmd, []string{"fix1", "1234"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected 'source:id' format")
+}
+
+func TestFormatAliases(t *testing.T) {
+	assert.Equal(t, []string{
+		"fix1     -> github:1234",
+		"refactor -> test:refactor",
+	}, formatAliases(map[string]string{"refactor": "test:refactor", "fix1": "github:1234"}))
+}
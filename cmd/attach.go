@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/spf13/cobra"
 	"sbs/pkg/config"
@@ -10,15 +9,24 @@ import (
 )
 
 var attachCmd = &cobra.Command{
-	Use:   "attach <work-item-id>",
+	Use:   "attach [work-item-id]",
 	Short: "Attach to an existing work session",
 	Long: `Attach to the tmux session for the specified work item.
 If the session doesn't exist, an error will be returned.
 
 Work item ID formats:
   sbs attach 123         # Primary work type
-  sbs attach test:my-test  # Test work type`,
-	Args: cobra.ExactArgs(1),
+  sbs attach test:my-test  # Test work type
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.
+
+Before attaching, the variables in attach_environment (by default DISPLAY,
+SSH_AUTH_SOCK, SSH_CONNECTION and XAUTHORITY) are copied from this terminal
+into the session, so panes opened after reattaching from a new SSH
+connection use its agent and display. Shells already running can pick them
+up with: eval "$(tmux show-environment -s)"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runAttach,
 }
 
@@ -27,7 +35,10 @@ func init() {
 }
 
 func runAttach(cmd *cobra.Command, args []string) error {
-	workItemID := args[0]
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
 
 	// Load sessions
 	sessions, err := config.LoadSessions()
@@ -44,11 +55,11 @@ func runAttach(cmd *cobra.Command, args []string) error {
 		}
 	}
 	if session == nil {
-		return fmt.Errorf("no session found for work item %s", workItemID)
+		return sessionNotFound(workItemID)
 	}
 
 	// Check if tmux session exists
-	tmuxManager := tmux.NewManager()
+	tmuxManager := appServices().TmuxManager()
 	exists, err := tmuxManager.SessionExists(session.TmuxSession)
 	if err != nil {
 		return fmt.Errorf("failed to check tmux session: %w", err)
@@ -59,14 +70,7 @@ func runAttach(cmd *cobra.Command, args []string) error {
 	}
 
 	// Update last activity
-	for i, s := range sessions {
-		if s.NamespacedID == workItemID {
-			// Update last activity timestamp
-			sessions[i].LastActivity = time.Now().Format(time.RFC3339)
-			break
-		}
-	}
-	if err := config.SaveSessions(sessions); err != nil {
+	if err := recordSessionActivity(workItemID); err != nil {
 		// Don't fail if we can't save - just log
 		fmt.Printf("Warning: failed to update session activity: %v\n", err)
 	}
@@ -82,3 +86,11 @@ func runAttach(cmd *cobra.Command, args []string) error {
 
 	return tmuxManager.AttachToSession(session.TmuxSession, tmuxEnv)
 }
+
+// recordSessionActivity stamps the last activity of a work item's session
+// under the sessions lock
+func recordSessionActivity(workItemID string) error {
+	return updateSession(workItemID, func(session *config.SessionMetadata) {
+		session.LastActivity = config.Timestamp()
+	})
+}
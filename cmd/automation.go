@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+)
+
+// confirmationSkipped reports whether --yes, or one of the command's older
+// no-prompt flags such as clean's --force, is set. It fails when the
+// automation_allow of the global config and org defaults doesn't let op run
+// without confirmation; repository configs can't widen it.
+func confirmationSkipped(cmd *cobra.Command, op string, aliases ...string) (bool, error) {
+	flag := ""
+	for _, name := range append([]string{"yes"}, aliases...) {
+		if set, err := cmd.Flags().GetBool(name); err == nil && set {
+			flag = name
+			break
+		}
+	}
+	if flag == "" {
+		return false, nil
+	}
+
+	automation := automationConfig()
+	if !automation.AutomationAllowed(op) {
+		return false, exitcode.Errorf(exitcode.Validation,
+			"--%s is not allowed for %s by automation_allow (allowed: %s); run without it to confirm interactively",
+			flag, op, strings.Join(automation.AutomationAllow, ", "))
+	}
+	return true, nil
+}
+
+// automationConfig returns the global config with the org defaults over it
+func automationConfig() *config.Config {
+	layers, err := config.LoadConfigLayers("")
+	if err != nil {
+		return appServices().Config()
+	}
+	return config.MergeLayers(layers)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+)
+
+func TestConfirmationSkipped(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(config.OrgConfigEnv, "")
+	writeConfig := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	newCmd := func(flags ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "clean"}
+		cmd.Flags().Bool("yes", false, "")
+		cmd.Flags().Bool("force", false, "")
+		require.NoError(t, cmd.ParseFlags(flags))
+		return cmd
+	}
+
+	skip, err := confirmationSkipped(newCmd(), "clean", "force")
+	require.NoError(t, err)
+	assert.False(t, skip, "without --yes the prompt stays")
+
+	skip, err = confirmationSkipped(newCmd("--force"), "clean", "force")
+	require.NoError(t, err)
+	assert.True(t, skip, "every operation is automatable without automation_allow")
+
+	writeConfig(filepath.Join(home, ".config", "sbs", "config.json"),
+		`{"worktree_base_path": "/w", "automation_allow": ["stop"]}`)
+	skip, err = confirmationSkipped(newCmd("--yes"), "stop")
+	require.NoError(t, err)
+	assert.True(t, skip)
+
+	_, err = confirmationSkipped(newCmd("--force"), "clean", "force")
+	assert.EqualError(t, err, "--force is not allowed for clean by automation_allow (allowed: stop); run without it to confirm interactively")
+	assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+
+	t.Run("org defaults override the global list", func(t *testing.T) {
+		orgPath := filepath.Join(home, "org.json")
+		t.Setenv(config.OrgConfigEnv, orgPath)
+		writeConfig(orgPath, `{"automation_allow": ["none"]}`)
+		_, err := confirmationSkipped(newCmd("--yes"), "stop")
+		assert.ErrorContains(t, err, "(allowed: none)")
+	})
+}
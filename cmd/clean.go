@@ -6,17 +6,26 @@ import (
 	"github.com/spf13/cobra"
 	"sbs/pkg/cleanup"
 	"sbs/pkg/config"
-	"sbs/pkg/git"
-	"sbs/pkg/repo"
-	"sbs/pkg/sandbox"
-	"sbs/pkg/tmux"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/messages"
+	"sbs/pkg/notify"
+	"sbs/pkg/oplock"
 )
 
 var cleanCmd = &cobra.Command{
 	Use:   "clean",
 	Short: "Clean up stale sessions and worktrees",
 	Long: `Remove stale sessions and their associated worktrees.
-A session is considered stale if its tmux session no longer exists.`,
+A session is considered stale if its tmux session no longer exists, unless the
+repository's .sbs/stalehook decides otherwise (exit 0: stale, exit 1: active).
+
+Use --only to restrict cleanup to some resource types, e.g. --only sandbox
+deletes sandboxes but keeps worktrees and session metadata. The flag can be
+repeated or given a comma-separated list of tmux, sandbox, worktree, branch.
+
+Use --repo-missing to remove the sessions of repositories whose directory was
+deleted (shown with status "repo missing"), with their tmux sessions,
+sandboxes and worktree directories, whether or not they are stale.`,
 	RunE: runClean,
 }
 
@@ -24,12 +33,16 @@ func init() {
 	rootCmd.AddCommand(cleanCmd)
 	cleanCmd.Flags().BoolP("dry-run", "n", false, "Show what would be cleaned without actually doing it")
 	cleanCmd.Flags().BoolP("force", "f", false, "Force cleanup without confirmation")
+	cleanCmd.Flags().BoolP("yes", "y", false, "Clean without asking for confirmation (same as --force)")
+	cleanCmd.Flags().Bool("snapshot", false, "Snapshot each sandbox before deleting it (default: snapshot_before_clean)")
 
 	// Enhanced cleanup modes
 	cleanCmd.Flags().Bool("stale", false, "Clean only stale sessions")
 	cleanCmd.Flags().Bool("orphaned", false, "Clean orphaned resources")
 	cleanCmd.Flags().Bool("branches", false, "Clean orphaned branches")
 	cleanCmd.Flags().Bool("all", false, "Clean all resource types")
+	cleanCmd.Flags().Bool("repo-missing", false, "Clean sessions whose repository directory no longer exists")
+	cleanCmd.Flags().StringSlice("only", nil, "Clean only these resources: tmux, sandbox, worktree, branch (repeatable)")
 }
 
 // CleanupMode represents the type of cleanup to perform
@@ -79,143 +92,537 @@ func determineCleanupMode(stale, orphaned, branches, all bool) CleanupMode {
 
 func runClean(cmd *cobra.Command, args []string) error {
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
-	force, _ := cmd.Flags().GetBool("force")
+	force, err := confirmationSkipped(cmd, "clean", "force")
+	if err != nil {
+		return err
+	}
 
 	// Get cleanup mode flags
 	staleOnly, _ := cmd.Flags().GetBool("stale")
 	orphanedOnly, _ := cmd.Flags().GetBool("orphaned")
 	branchesOnly, _ := cmd.Flags().GetBool("branches")
 	allResources, _ := cmd.Flags().GetBool("all")
+	repoMissing, _ := cmd.Flags().GetBool("repo-missing")
+	snapshot, _ := cmd.Flags().GetBool("snapshot")
+	snapshot = snapshot || appServices().Config().SnapshotBeforeClean
+
+	onlyValues, _ := cmd.Flags().GetStringSlice("only")
+	only, err := cleanup.ParseResourceMask(onlyValues)
+	if err != nil {
+		return exitcode.Errorf(exitcode.Validation, "invalid --only value: %w", err)
+	}
+
+	if repoMissing {
+		if staleOnly || orphanedOnly || branchesOnly || allResources {
+			return exitcode.Errorf(exitcode.Validation, "--repo-missing cannot be combined with --stale, --orphaned, --branches or --all")
+		}
+		if only != 0 && only&sessionResources == 0 {
+			return exitcode.Errorf(exitcode.Validation, "--repo-missing cleans tmux sessions, sandboxes and worktrees; branches went with the repository")
+		}
+		return executeRepoMissingCleanup(dryRun, force, only)
+	}
+
+	if branchesOnly && !allResources && !staleOnly && !only.Includes(cleanup.ResourceBranch) {
+		return exitcode.Errorf(exitcode.Validation, "--branches cannot be combined with --only %s", only)
+	}
 
 	// Determine cleanup mode
-	cleanupMode := determineCleanupMode(staleOnly, orphanedOnly, branchesOnly, allResources)
+	cleanupMode := applyResourceMask(determineCleanupMode(staleOnly, orphanedOnly, branchesOnly, allResources), only)
 
 	// Execute cleanup based on mode
-	return executeCleanup(cleanupMode, dryRun, force)
+	return executeCleanup(cleanupMode, dryRun, force, snapshot, only)
+}
+
+// sessionResources are the resources cleaned per stale session; branches are
+// cleaned separately by matching orphaned branch names
+const sessionResources = cleanup.ResourceTmux | cleanup.ResourceSandbox | cleanup.ResourceWorktree
+
+// applyResourceMask narrows the cleanup mode to the phases the --only mask
+// allows, so that e.g. "--only branch" cleans orphaned branches without a
+// separate --branches flag
+func applyResourceMask(mode CleanupMode, only cleanup.ResourceMask) CleanupMode {
+	if only == 0 {
+		return mode
+	}
+
+	cleanSessions := only&sessionResources != 0
+	cleanBranches := only&cleanup.ResourceBranch != 0
+
+	switch mode {
+	case CleanupModeDefault, CleanupModeStale, CleanupModeAll, CleanupModeStaleAndBranches:
+		switch {
+		case cleanSessions && cleanBranches:
+			return CleanupModeStaleAndBranches
+		case cleanBranches:
+			return CleanupModeBranches
+		default:
+			return CleanupModeStale
+		}
+	}
+	return mode
 }
 
 // executeCleanup performs the actual cleanup based on the specified mode
-func executeCleanup(mode CleanupMode, dryRun, force bool) error {
+func executeCleanup(mode CleanupMode, dryRun, force, snapshot bool, only cleanup.ResourceMask) error {
 	switch mode {
 	case CleanupModeDefault:
-		return executeDefaultCleanup(dryRun, force)
+		return executeDefaultCleanup(dryRun, force, snapshot, only)
 	case CleanupModeStale:
-		return executeStaleCleanup(dryRun, force)
+		return executeStaleCleanup(dryRun, force, snapshot, only)
 	case CleanupModeBranches:
 		return executeBranchCleanup(dryRun, force)
 	case CleanupModeAll:
-		return executeComprehensiveCleanup(dryRun, force)
+		return executeComprehensiveCleanup(dryRun, force, snapshot)
 	case CleanupModeStaleAndBranches:
 		// Execute both stale and branch cleanup
-		if err := executeStaleCleanup(dryRun, force); err != nil {
+		if err := executeStaleCleanup(dryRun, force, snapshot, only); err != nil {
 			return err
 		}
 		return executeBranchCleanup(dryRun, force)
 	default:
-		return executeDefaultCleanup(dryRun, force)
+		return executeDefaultCleanup(dryRun, force, snapshot, only)
 	}
 }
 
-// executeDefaultCleanup performs the original cleanup behavior using CleanupManager
-func executeDefaultCleanup(dryRun, force bool) error {
+// executeDefaultCleanup performs the original cleanup behavior using CleanupManager.
+// A non-zero mask limits which of each stale session's resources are removed;
+// with snapshot, sandboxes are snapshotted before they are deleted.
+func executeDefaultCleanup(dryRun, force, snapshot bool, only cleanup.ResourceMask) error {
 	// Load all sessions from all repositories
 	sessions, err := config.LoadAllRepositorySessions()
 	if err != nil {
 		return fmt.Errorf("failed to load sessions: %w", err)
 	}
 
+	// Empty worktree directories are pruned last, whatever was cleaned
+	finish := func() error {
+		if only.Includes(cleanup.ResourceWorktree) {
+			pruneEmptyWorktreeDirs(appServices().Config(), dryRun)
+		}
+		return nil
+	}
+
 	if len(sessions) == 0 {
 		fmt.Println("No sessions to clean.")
-		return nil
+		return finish()
 	}
 
-	// Initialize managers and cleanup manager
-	tmuxManager := tmux.NewManager()
-	sandboxManager := sandbox.NewManager()
-	cleanupManager := cleanup.NewCleanupManager(tmuxManager, sandboxManager, nil, nil)
+	cleanupManager := appServices().CleanupManager()
 
 	// Identify stale sessions
-	staleSessions, err := cleanupManager.IdentifyStaleSessionsInView(sessions, cleanup.ViewModeGlobal)
+	staleSessions, err := cleanupManager.IdentifyStaleSessionsInView(appServices().Context(), sessions, cleanup.ViewModeGlobal)
 	if err != nil {
 		return fmt.Errorf("failed to identify stale sessions: %w", err)
 	}
 
 	if len(staleSessions) == 0 {
 		fmt.Println("No stale sessions found.")
-		return nil
+		return finish()
 	}
 
 	// Show what will be cleaned
 	fmt.Printf("Found %d stale session(s):\n", len(staleSessions))
+	if only != 0 {
+		fmt.Printf("Only cleaning: %s\n", only&sessionResources)
+	}
 	for _, session := range staleSessions {
 		fmt.Printf("  Work Item %s: %s\n", session.NamespacedID, session.IssueTitle)
-		fmt.Printf("    Worktree: %s\n", session.WorktreePath)
-		fmt.Printf("    Tmux Session: %s\n", session.TmuxSession)
-		sandboxName := cleanupManager.ResolveSandboxName(session)
-		fmt.Printf("    Sandbox: %s\n", sandboxName)
+		if only.Includes(cleanup.ResourceWorktree) {
+			fmt.Printf("    Worktree: %s\n", session.WorktreePath)
+		}
+		if only.Includes(cleanup.ResourceTmux) {
+			fmt.Printf("    Tmux Session: %s\n", session.TmuxSession)
+		}
+		if only.Includes(cleanup.ResourceSandbox) {
+			sandboxName := cleanupManager.ResolveSandboxName(session)
+			if snapshot {
+				sandboxName += " (snapshotted first)"
+			}
+			fmt.Printf("    Sandbox: %s\n", sandboxName)
+		}
 	}
 
 	if dryRun {
 		fmt.Println("\nDry run - no changes made.")
-		return nil
+		return finish()
 	}
 
 	// Confirm unless forced
 	if !force {
-		fmt.Print("\nProceed with cleanup? (y/N): ")
+		fmt.Print("\n" + messages.Render(messages.CleanConfirm, nil))
 		var response string
 		fmt.Scanln(&response)
 		if response != "y" && response != "Y" {
-			fmt.Println("Cleanup cancelled.")
+			fmt.Println(messages.Render(messages.CleanCancelled, nil))
 			return nil
 		}
 	}
 
-	// Perform cleanup using CleanupManager
+	// Claim the sessions until their records are removed, so a concurrent
+	// start of the same work item can't be cleaned or overwritten
+	staleSessions, busy, release := claimSessionsForClean(staleSessions)
+	defer release()
+	for _, conflict := range busy {
+		fmt.Printf("Skipping: %v\n", conflict)
+	}
+	staleSessions, err = recheckStaleSessions(cleanupManager, staleSessions)
+	if err != nil {
+		return err
+	}
+
+	if snapshot && only.Includes(cleanup.ResourceSandbox) {
+		staleSessions = snapshotBeforeClean(appServices().SandboxManager(), staleSessions)
+	}
+
+	// Perform cleanup using CleanupManager; the sessions are already claimed
 	fmt.Println("\nCleaning up stale sessions...")
-	options := cleanupManager.BuildCLICleanupOptions(false, force, cleanup.CleanupModeDefault)
-	results, err := cleanupManager.CleanupSessions(staleSessions, options)
+	options := cleanupManager.BuildCLICleanupOptions(false, force, cleanup.CleanupModeDefault).WithOnly(only & sessionResources)
+	strictRepos := sandboxRequiredRepos(staleSessions)
+	if len(strictRepos) > 0 {
+		// Report sandbox failures instead of treating them as "no sandbox"
+		cleanupManager = cleanupManager.WithSandboxManager(appServices().SandboxManager().WithRequired(true))
+	}
+	results, err := cleanSessionsInBatch(cleanupManager, staleSessions, options)
 	if err != nil {
-		return fmt.Errorf("cleanup failed: %w", err)
+		return err
 	}
+	sandboxFailures := requiredSandboxFailures(results, staleSessions, strictRepos)
 
-	// Print detailed results from CleanupManager
-	for _, detail := range results.Details {
-		fmt.Printf("  %s\n", detail)
+	// Sessions keep their metadata while their worktree is kept
+	if !only.Includes(cleanup.ResourceWorktree) {
+		fmt.Printf("\nCleanup complete. Cleaned %s for %d stale session(s); session metadata kept.\n", only&sessionResources, results.CleanedSessions)
+		return sandboxFailureError(sandboxFailures)
 	}
 
-	// Handle any errors
-	if len(results.Errors) > 0 {
-		for _, cleanupErr := range results.Errors {
-			fmt.Printf("  Warning: %v\n", cleanupErr)
-		}
+	// Remove cleaned sessions from persistence, keeping any other process added
+	// meanwhile and those whose protected worktree was kept
+	kept := make(map[string]bool)
+	for _, id := range results.Protected() {
+		kept[id] = true
+	}
+	for _, failure := range sandboxFailures {
+		kept[failure.SessionID] = true
+	}
+	forgetCleanedSessions(staleSessions, kept)
+
+	if protected := len(kept) - len(sandboxFailures); protected > 0 {
+		fmt.Printf("Kept %d session(s) with a protected worktree; their metadata is kept too.\n", protected)
+	}
+	if len(busy) > 0 {
+		fmt.Printf("Skipped %d session(s) busy in another sbs process; run 'sbs clean' again once it finishes.\n", len(busy))
 	}
+	fmt.Printf("\nCleanup complete. Removed %d stale session(s).\n", results.CleanedSessions)
+	finish()
+	return cleanOutcomeError(sandboxFailures, busy, results.CleanedSessions)
+}
 
-	// Save active sessions (remove stale ones from persistence)
-	var activeSessions []config.SessionMetadata
-	staleSessionIDs := make(map[string]bool)
-	for _, staleSession := range staleSessions {
-		staleSessionIDs[staleSession.NamespacedID] = true
+// cleanSessionsInBatch cleans sessions that are already claimed, printing
+// each session's resource outcomes as soon as it finishes; failures render as
+// warnings. Interrupting sbs stops the batch before the next session.
+func cleanSessionsInBatch(cleanupManager *cleanup.CleanupManager, sessions []config.SessionMetadata, options cleanup.CleanupOptions) (cleanup.CleanupResults, error) {
+	batch := cleanupManager.WithSessionLocker(nil).StartBatch(appServices().Context(), sessions, options)
+	finished := 0
+	for progress := range batch.Progress() {
+		finished = progress.Done
+		fmt.Printf("  [%d/%d] %s\n", progress.Done, progress.Total, progress.Result.Session.NamespacedID)
+		for _, action := range progress.Result.Actions {
+			fmt.Printf("    %s\n", action)
+		}
+	}
+	results, err := batch.Wait()
+	if err != nil {
+		return results, fmt.Errorf("cleanup interrupted after %d of %d session(s): %w", finished, len(sessions), err)
 	}
+	return results, nil
+}
 
+// forgetCleanedSessions removes the metadata of cleaned sessions other than
+// those in kept, keeping any other process added meanwhile, archives them and
+// tells the notifiers. Failures only warn.
+func forgetCleanedSessions(sessions []config.SessionMetadata, kept map[string]bool) {
+	cleanedIDs := make(map[string]bool)
+	var cleanedSessions []config.SessionMetadata
 	for _, session := range sessions {
-		if !staleSessionIDs[session.NamespacedID] {
-			activeSessions = append(activeSessions, session)
+		if !kept[session.NamespacedID] {
+			cleanedIDs[session.NamespacedID] = true
+			cleanedSessions = append(cleanedSessions, session)
 		}
 	}
 
-	if err := config.SaveSessions(activeSessions); err != nil {
+	err := config.UpdateSessions(func(current []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		var remaining []config.SessionMetadata
+		for _, session := range current {
+			if !cleanedIDs[session.NamespacedID] {
+				remaining = append(remaining, session)
+			}
+		}
+		return remaining, nil
+	})
+	if err != nil {
 		fmt.Printf("Warning: failed to save updated sessions: %v\n", err)
+	} else if err := archiveCleanedSessions(cleanedSessions); err != nil {
+		// The archive only feeds reports, so cleanup still succeeded
+		fmt.Printf("Warning: failed to archive cleaned sessions: %v\n", err)
+	}
+	if len(cleanedSessions) > 0 {
+		if err := appServices().Notifications().Send(notify.CleanupEvent(cleanedSessions, config.Now())); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
 	}
+}
 
-	fmt.Printf("\nCleanup complete. Removed %d stale session(s).\n", results.CleanedSessions)
-	return nil
+// sandboxFailureError fails the cleanup when sandbox_required sessions had
+// sandbox failures, wrapping the first one
+func sandboxFailureError(failures []cleanup.Action) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return exitcode.Errorf(exitcode.Partial, "kept %d session(s) whose sandbox could not be checked or deleted with sandbox_required set (see 'sbs doctor'): %w",
+		len(failures), failures[0].Err)
+}
+
+// cleanOutcomeError ends a session cleanup with the partial-failure exit
+// code when sandboxes failed or sessions were skipped as busy, or the busy
+// one when every claimed session was skipped
+func cleanOutcomeError(failures []cleanup.Action, busy []*oplock.ConflictError, cleaned int) error {
+	if err := sandboxFailureError(failures); err != nil {
+		return err
+	}
+	if len(busy) == 0 {
+		return nil
+	}
+	if cleaned == 0 {
+		return exitcode.Errorf(exitcode.Busy, "skipped %d session(s) busy in another sbs process", len(busy))
+	}
+	return exitcode.Errorf(exitcode.Partial, "skipped %d session(s) busy in another sbs process", len(busy))
+}
+
+// sandboxRequiredRepos returns the repositories of sessions that
+// sandbox_required applies to
+func sandboxRequiredRepos(sessions []config.SessionMetadata) map[string]bool {
+	required := make(map[string]bool)
+	checked := make(map[string]bool)
+	for _, session := range sessions {
+		if checked[session.RepositoryRoot] {
+			continue
+		}
+		checked[session.RepositoryRoot] = true
+		if sandboxRequiredFor(session.RepositoryRoot) {
+			required[session.RepositoryRoot] = true
+		}
+	}
+	return required
+}
+
+// requiredSandboxFailures returns the failed sandbox actions of sessions that
+// sandbox_required applies to; those sessions must keep their metadata so
+// the sandbox isn't forgotten
+func requiredSandboxFailures(results cleanup.CleanupResults, sessions []config.SessionMetadata, strictRepos map[string]bool) []cleanup.Action {
+	repoOf := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		repoOf[session.NamespacedID] = session.RepositoryRoot
+	}
+	var failures []cleanup.Action
+	for _, action := range results.Failed(cleanup.ResourceSandbox) {
+		if strictRepos[repoOf[action.SessionID]] {
+			failures = append(failures, action)
+		}
+	}
+	return failures
+}
+
+// pruneEmptyWorktreeDirs removes the empty directories left beneath
+// worktree_base_path, such as per-repository directories whose last worktree
+// is gone, when prune_empty_worktree_dirs is set. Failures only warn.
+func pruneEmptyWorktreeDirs(cfg *config.Config, dryRun bool) {
+	if cfg == nil || !cfg.PruneEmptyWorktreeDirs {
+		return
+	}
+	base := cfg.WorktreeBasePath
+
+	if dryRun {
+		empty, err := cleanup.FindEmptyDirs(base)
+		if err != nil {
+			fmt.Printf("Warning: failed to find empty directories: %v\n", err)
+			return
+		}
+		for _, dir := range empty {
+			fmt.Printf("  Would remove empty directory: %s\n", dir)
+		}
+		return
+	}
+
+	pruned, err := cleanup.PruneEmptyDirs(base)
+	for _, dir := range pruned {
+		fmt.Printf("  Removed empty directory: %s\n", dir)
+	}
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if len(pruned) > 0 {
+		fmt.Printf("Pruned %d empty director(ies) under %s.\n", len(pruned), base)
+	}
+}
+
+// recheckStaleSessions reloads claimed sessions and keeps those still stale:
+// one may have been restarted before it was claimed, e.g. while the cleanup
+// prompt was open
+func recheckStaleSessions(cleanupManager *cleanup.CleanupManager, claimed []config.SessionMetadata) ([]config.SessionMetadata, error) {
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+	claimedIDs := make(map[string]bool, len(claimed))
+	for _, session := range claimed {
+		claimedIDs[session.NamespacedID] = true
+	}
+
+	sessions, err := config.LoadAllRepositorySessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload sessions: %w", err)
+	}
+	var current []config.SessionMetadata
+	for _, session := range sessions {
+		if claimedIDs[session.NamespacedID] {
+			current = append(current, session)
+		}
+	}
+
+	stale, err := cleanupManager.IdentifyStaleSessionsInView(appServices().Context(), current, cleanup.ViewModeGlobal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify stale sessions: %w", err)
+	}
+	return stale, nil
+}
+
+// archiveCleanedSessions moves cleaned sessions, marked deleted, to the
+// session archive that sbs history and reports read
+func archiveCleanedSessions(sessions []config.SessionMetadata) error {
+	archivePath, err := config.GlobalSessionsArchivePath()
+	if err != nil {
+		return err
+	}
+	deleted := make([]config.SessionMetadata, len(sessions))
+	for i, session := range sessions {
+		session.Status = config.SessionStatusDeleted
+		deleted[i] = session
+	}
+	return config.ArchiveSessions(archivePath, deleted, config.ArchiveReasonCleaned)
+}
+
+// executeRepoMissingCleanup removes the sessions whose repository directory
+// was deleted, with their tmux sessions, sandboxes and worktree directories.
+// A non-zero mask limits which resources are removed.
+func executeRepoMissingCleanup(dryRun, force bool, only cleanup.ResourceMask) error {
+	sessions, err := config.LoadAllRepositorySessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	// Empty worktree directories are pruned last, whatever was cleaned
+	finish := func() {
+		if only.Includes(cleanup.ResourceWorktree) {
+			pruneEmptyWorktreeDirs(appServices().Config(), dryRun)
+		}
+	}
+
+	repoMissing := cleanup.IdentifyRepoMissingSessions(sessions)
+	if len(repoMissing) == 0 {
+		fmt.Println("No sessions with a missing repository found.")
+		finish()
+		return nil
+	}
+
+	cleanupManager := appServices().CleanupManager()
+	fmt.Printf("Found %d session(s) whose repository no longer exists:\n", len(repoMissing))
+	if only != 0 {
+		fmt.Printf("Only cleaning: %s\n", only&sessionResources)
+	}
+	for _, session := range repoMissing {
+		fmt.Printf("  Work Item %s: %s\n", session.NamespacedID, session.IssueTitle)
+		fmt.Printf("    Repository: %s (missing)\n", session.RepositoryRoot)
+		if only.Includes(cleanup.ResourceWorktree) {
+			fmt.Printf("    Worktree: %s\n", session.WorktreePath)
+		}
+		if only.Includes(cleanup.ResourceTmux) {
+			fmt.Printf("    Tmux Session: %s\n", session.TmuxSession)
+		}
+		if only.Includes(cleanup.ResourceSandbox) {
+			fmt.Printf("    Sandbox: %s\n", cleanupManager.ResolveSandboxName(session))
+		}
+	}
+
+	if dryRun {
+		fmt.Println("\nDry run - no changes made.")
+		finish()
+		return nil
+	}
+
+	if !force {
+		fmt.Print("\n" + messages.Render(messages.CleanConfirm, nil))
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println(messages.Render(messages.CleanCancelled, nil))
+			return nil
+		}
+	}
+
+	// Claim the sessions until their records are removed, and skip any whose
+	// repository came back while the prompt was open
+	repoMissing, busy, release := claimSessionsForClean(repoMissing)
+	defer release()
+	for _, conflict := range busy {
+		fmt.Printf("Skipping: %v\n", conflict)
+	}
+	repoMissing = cleanup.IdentifyRepoMissingSessions(repoMissing)
+
+	fmt.Println("\nCleaning up sessions of missing repositories...")
+	options := cleanupManager.BuildCLICleanupOptions(false, force, cleanup.CleanupModeRepoMissing).WithOnly(only & sessionResources)
+	strictRepos := sandboxRequiredRepos(repoMissing)
+	if len(strictRepos) > 0 {
+		// Report sandbox failures instead of treating them as "no sandbox"
+		cleanupManager = cleanupManager.WithSandboxManager(appServices().SandboxManager().WithRequired(true))
+	}
+	results, err := cleanSessionsInBatch(cleanupManager, repoMissing, options)
+	if err != nil {
+		return err
+	}
+	sandboxFailures := requiredSandboxFailures(results, repoMissing, strictRepos)
+
+	if !only.Includes(cleanup.ResourceWorktree) {
+		fmt.Printf("\nCleanup complete. Cleaned %s for %d session(s); session metadata kept.\n", only&sessionResources, results.CleanedSessions)
+		return sandboxFailureError(sandboxFailures)
+	}
+
+	// Sessions whose worktree is still there keep their metadata, so the
+	// worktree isn't forgotten
+	kept := make(map[string]bool)
+	for _, id := range results.Protected() {
+		kept[id] = true
+	}
+	for _, failure := range results.Failed(cleanup.ResourceWorktree) {
+		kept[failure.SessionID] = true
+	}
+	for _, failure := range sandboxFailures {
+		kept[failure.SessionID] = true
+	}
+	forgetCleanedSessions(repoMissing, kept)
+
+	if len(kept) > 0 {
+		fmt.Printf("Kept %d session(s) with a protected worktree or a worktree or sandbox that could not be removed; their metadata is kept too.\n", len(kept))
+	}
+	if len(busy) > 0 {
+		fmt.Printf("Skipped %d session(s) busy in another sbs process; run 'sbs clean --repo-missing' again once it finishes.\n", len(busy))
+	}
+	fmt.Printf("\nCleanup complete. Removed %d session(s) of missing repositories.\n", len(repoMissing)-len(kept))
+	finish()
+	return cleanOutcomeError(sandboxFailures, busy, len(repoMissing)-len(kept))
 }
 
 // executeStaleCleanup performs cleanup of stale sessions only
-func executeStaleCleanup(dryRun, force bool) error {
+func executeStaleCleanup(dryRun, force, snapshot bool, only cleanup.ResourceMask) error {
 	fmt.Println("Cleaning up stale sessions only...")
-	return executeDefaultCleanup(dryRun, force)
+	return executeDefaultCleanup(dryRun, force, snapshot, only)
 }
 
 // executeBranchCleanup performs cleanup of orphaned branches
@@ -229,7 +636,7 @@ func executeBranchCleanup(dryRun, force bool) error {
 	}
 
 	// Get active issue numbers with robust active session detection
-	tmuxManager := tmux.NewManager()
+	tmuxManager := appServices().TmuxManager()
 	activeWorkItems := make([]string, 0, len(sessions))
 	for _, session := range sessions {
 		// Only include active sessions
@@ -241,17 +648,10 @@ func executeBranchCleanup(dryRun, force bool) error {
 		}
 	}
 
-	// Initialize repository manager to get current repo
-	repoManager := repo.NewManager()
-	currentRepo, err := repoManager.DetectCurrentRepository()
-	if err != nil {
-		return fmt.Errorf("must be run from within a git repository: %w", err)
-	}
-
-	// Initialize git manager
-	gitManager, err := git.NewManager(currentRepo.Root)
+	// Git manager for the current repository
+	gitManager, err := appServices().GitManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize git manager: %w", err)
+		return err
 	}
 
 	// Find orphaned branches
@@ -278,11 +678,11 @@ func executeBranchCleanup(dryRun, force bool) error {
 
 	// Confirm unless forced
 	if !force {
-		fmt.Print("\nProceed with branch cleanup? (y/N): ")
+		fmt.Print("\n" + messages.Render(messages.CleanConfirmBranches, nil))
 		var response string
 		fmt.Scanln(&response)
 		if response != "y" && response != "Y" {
-			fmt.Println("Branch cleanup cancelled.")
+			fmt.Println(messages.Render(messages.CleanBranchesCancelled, nil))
 			return nil
 		}
 	}
@@ -309,11 +709,11 @@ func executeBranchCleanup(dryRun, force bool) error {
 }
 
 // executeComprehensiveCleanup performs cleanup of all resource types
-func executeComprehensiveCleanup(dryRun, force bool) error {
+func executeComprehensiveCleanup(dryRun, force, snapshot bool) error {
 	fmt.Println("Performing comprehensive cleanup of all resources...")
 
 	// Execute stale session cleanup
-	if err := executeStaleCleanup(dryRun, force); err != nil {
+	if err := executeStaleCleanup(dryRun, force, snapshot, 0); err != nil {
 		fmt.Printf("Warning: stale session cleanup failed: %v\n", err)
 	}
 
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/cleanup"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/oplock"
 )
 
 func TestCleanCommand_EnhancedModes(t *testing.T) {
@@ -218,3 +226,106 @@ func TestCleanCommand_FlagStructure(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyResourceMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     CleanupMode
+		only     cleanup.ResourceMask
+		expected CleanupMode
+	}{
+		{name: "no_mask_keeps_mode", mode: CleanupModeAll, expected: CleanupModeAll},
+		{name: "session_resources", mode: CleanupModeDefault, only: cleanup.ResourceSandbox, expected: CleanupModeStale},
+		{name: "branch_only", mode: CleanupModeDefault, only: cleanup.ResourceBranch, expected: CleanupModeBranches},
+		{name: "sessions_and_branches", mode: CleanupModeDefault, only: cleanup.ResourceTmux | cleanup.ResourceBranch, expected: CleanupModeStaleAndBranches},
+		{name: "all_narrowed_to_tmux", mode: CleanupModeAll, only: cleanup.ResourceTmux, expected: CleanupModeStale},
+		{name: "branches_mode_unchanged", mode: CleanupModeBranches, only: cleanup.ResourceBranch, expected: CleanupModeBranches},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, applyResourceMask(tt.mode, tt.only))
+		})
+	}
+}
+
+func TestCleanCommand_OnlyFlag(t *testing.T) {
+	flag := cleanCmd.Flags().Lookup("only")
+	require.NotNil(t, flag)
+	assert.Equal(t, "stringSlice", flag.Value.Type())
+}
+
+func TestPruneEmptyWorktreeDirs(t *testing.T) {
+	base := t.TempDir()
+	leftover := filepath.Join(base, "old-repo")
+	require.NoError(t, os.Mkdir(leftover, 0755))
+
+	output := captureStdout(t, func() {
+		pruneEmptyWorktreeDirs(&config.Config{WorktreeBasePath: base}, false)
+	})
+	assert.Empty(t, output, "nothing happens unless prune_empty_worktree_dirs is set")
+	assert.DirExists(t, leftover)
+
+	cfg := &config.Config{WorktreeBasePath: base, PruneEmptyWorktreeDirs: true}
+	output = captureStdout(t, func() { pruneEmptyWorktreeDirs(cfg, true) })
+	assert.Contains(t, output, "Would remove empty directory: "+leftover)
+	assert.DirExists(t, leftover)
+
+	output = captureStdout(t, func() { pruneEmptyWorktreeDirs(cfg, false) })
+	assert.Contains(t, output, "Removed empty directory: "+leftover)
+	assert.Contains(t, output, "Pruned 1 empty director(ies) under "+base)
+	assert.NoDirExists(t, leftover)
+}
+
+func TestRequiredSandboxFailures(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", RepositoryRoot: "/src/strict"},
+		{NamespacedID: "github:2", RepositoryRoot: "/src/lenient"},
+	}
+	results := cleanup.CleanupResults{Actions: []cleanup.Action{
+		{SessionID: "github:1", Resource: cleanup.ResourceSandbox, Outcome: cleanup.OutcomeFailed, Err: errors.New("list failed")},
+		{SessionID: "github:2", Resource: cleanup.ResourceSandbox, Outcome: cleanup.OutcomeFailed, Err: errors.New("list failed")},
+	}}
+
+	failures := requiredSandboxFailures(results, sessions, map[string]bool{"/src/strict": true})
+	require.Len(t, failures, 1)
+	assert.Equal(t, "github:1", failures[0].SessionID)
+
+	err := sandboxFailureError(failures)
+	assert.ErrorContains(t, err, "kept 1 session(s) whose sandbox could not be checked or deleted")
+	assert.NoError(t, sandboxFailureError(nil))
+}
+
+func TestCleanCommand_RepoMissingFlag(t *testing.T) {
+	flag := cleanCmd.Flags().Lookup("repo-missing")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+
+	t.Run("rejects_other_modes", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.Flags().AddFlagSet(cleanCmd.Flags())
+		t.Cleanup(func() {
+			cleanCmd.Flags().Set("repo-missing", "false")
+			cleanCmd.Flags().Set("stale", "false")
+		})
+		require.NoError(t, cmd.Flags().Set("repo-missing", "true"))
+		require.NoError(t, cmd.Flags().Set("stale", "true"))
+
+		err := runClean(cmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be combined")
+	})
+}
+
+func TestCleanOutcomeError(t *testing.T) {
+	busy := []*oplock.ConflictError{{WorkItem: "github:1"}}
+
+	assert.NoError(t, cleanOutcomeError(nil, nil, 2))
+	assert.Equal(t, exitcode.Partial, exitcode.Of(cleanOutcomeError(nil, busy, 2)))
+	assert.Equal(t, exitcode.Busy, exitcode.Of(cleanOutcomeError(nil, busy, 0)))
+
+	failures := []cleanup.Action{{SessionID: "github:2", Err: assert.AnError}}
+	err := cleanOutcomeError(failures, busy, 1)
+	assert.Equal(t, exitcode.Partial, exitcode.Of(err))
+	assert.ErrorContains(t, err, "kept 1 session(s) whose sandbox")
+}
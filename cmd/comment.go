@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/inputsource"
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment [work-item-id] [message]",
+	Short: "Post a comment to a work item in its input source",
+	Long: `Post a comment to the work item in the tracker it came from, so agents
+and hooks can report progress from within the workflow.
+
+Work item ID formats:
+  sbs comment 123 "Tests are green"            # Primary work type
+  sbs comment github:123 --from-file notes.md  # Comment body from a file
+  some-check | sbs comment 123 --from-file -   # Comment body from stdin
+
+The comment goes to the repository owning the work item: the session's for
+a namespaced ID, the current repository's for a plain one.
+
+Run from inside a session worktree, the work item ID can be omitted:
+  sbs comment "Tests are green"                # Comment on the current session
+  sbs comment --from-file notes.md`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runComment,
+}
+
+func init() {
+	rootCmd.AddCommand(commentCmd)
+	commentCmd.Flags().StringP("from-file", "F", "", "Read the comment body from a file (use - for stdin)")
+}
+
+func runComment(cmd *cobra.Command, args []string) error {
+	fromFile, _ := cmd.Flags().GetString("from-file")
+
+	workItemID, messageArgs, err := splitCommentArgs(args, fromFile)
+	if err != nil {
+		return err
+	}
+
+	body, err := resolveCommentBody(messageArgs, fromFile)
+	if err != nil {
+		return err
+	}
+
+	source, id, err := resolveWorkItemSource(workItemID)
+	if err != nil {
+		return err
+	}
+
+	if err := inputsource.CommentOnWorkItem(source, id, body); err != nil {
+		return fmt.Errorf("failed to comment on work item %s: %w", workItemID, err)
+	}
+
+	fmt.Printf("Comment posted to work item %s:%s\n", source.GetType(), id)
+	return nil
+}
+
+// splitCommentArgs separates the work item ID from the message argument. A
+// single argument without --from-file is the message when the current directory
+// is a session worktree, and the work item ID otherwise.
+func splitCommentArgs(args []string, fromFile string) (string, []string, error) {
+	switch {
+	case len(args) == 2:
+		workItemID, err := resolveWorkItemID(args[0])
+		return workItemID, args[1:], err
+	case len(args) == 1 && fromFile != "":
+		workItemID, err := resolveWorkItemID(args[0])
+		return workItemID, nil, err
+	case len(args) == 1:
+		if workItemID, err := currentSessionWorkItemID(); err == nil {
+			return workItemID, args, nil
+		}
+		workItemID, err := resolveWorkItemID(args[0])
+		return workItemID, nil, err
+	}
+
+	workItemID, err := currentSessionWorkItemID()
+	if err != nil {
+		return "", nil, err
+	}
+	return workItemID, nil, nil
+}
+
+// resolveCommentBody determines the comment body from the message argument or --from-file
+func resolveCommentBody(messageArgs []string, fromFile string) (string, error) {
+	if fromFile != "" && len(messageArgs) > 0 {
+		return "", fmt.Errorf("cannot use both a message argument and --from-file")
+	}
+
+	if fromFile == "" {
+		if len(messageArgs) == 0 {
+			return "", fmt.Errorf("a message argument or --from-file is required")
+		}
+		return messageArgs[0], nil
+	}
+
+	var data []byte
+	var err error
+	if fromFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fromFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read comment file: %w", err)
+	}
+
+	return string(data), nil
+}
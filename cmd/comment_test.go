@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/app"
+	"sbs/pkg/config"
+)
+
+func TestCommentCommand_Structure(t *testing.T) {
+	t.Run("from_file_flag_exists", func(t *testing.T) {
+		flag := commentCmd.Flags().Lookup("from-file")
+		require.NotNil(t, flag)
+		assert.Equal(t, "F", flag.Shorthand)
+	})
+
+	t.Run("accepts_up_to_two_args", func(t *testing.T) {
+		// The work item ID may be omitted inside a session worktree
+		assert.NoError(t, commentCmd.Args(commentCmd, []string{}))
+		assert.NoError(t, commentCmd.Args(commentCmd, []string{"123"}))
+		assert.NoError(t, commentCmd.Args(commentCmd, []string{"123", "message"}))
+		assert.Error(t, commentCmd.Args(commentCmd, []string{"123", "a", "b"}))
+	})
+}
+
+func TestResolveCommentBody(t *testing.T) {
+	t.Run("message_argument", func(t *testing.T) {
+		body, err := resolveCommentBody([]string{"hello"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", body)
+	})
+
+	t.Run("from_file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "comment.md")
+		require.NoError(t, os.WriteFile(path, []byte("## Results\nAll green"), 0644))
+
+		body, err := resolveCommentBody(nil, path)
+		require.NoError(t, err)
+		assert.Equal(t, "## Results\nAll green", body)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		_, err := resolveCommentBody(nil, filepath.Join(t.TempDir(), "missing.md"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read comment file")
+	})
+
+	t.Run("both_sources", func(t *testing.T) {
+		_, err := resolveCommentBody([]string{"hello"}, "comment.md")
+		assert.Error(t, err)
+	})
+
+	t.Run("no_source", func(t *testing.T) {
+		_, err := resolveCommentBody(nil, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestRunComment_ActsOnSessionRepository(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	original := services
+	defer func() { services = original }()
+
+	// A fake gh records where it ran and with which arguments
+	bin := t.TempDir()
+	record := filepath.Join(bin, "gh.log")
+	script := "#!/bin/sh\necho \"$(pwd) $*\" >> " + record + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "gh"), []byte(script), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	sessionRepo, currentRepo := t.TempDir(), t.TempDir()
+	for _, dir := range []string{sessionRepo, currentRepo} {
+		require.NoError(t, exec.Command("git", "-C", dir, "init", "-q").Run())
+	}
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{
+		{NamespacedID: "github:123", SourceType: "github", RepositoryRoot: sessionRepo},
+	}))
+	t.Chdir(currentRepo)
+	services = app.NewContainer(&config.Config{})
+
+	captureStdout(t, func() {
+		require.NoError(t, runComment(commentCmd, []string{"github:123", "Tests are green"}))
+	})
+	logged, err := os.ReadFile(record)
+	require.NoError(t, err)
+	assert.Equal(t, sessionRepo+" issue comment 123 --body Tests are green", strings.TrimSpace(string(logged)),
+		"gh runs in the session's repository, not the current one")
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the sbs configuration",
+}
+
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Print the merged configuration and where each key comes from",
+	Long: `Print the configuration commands use in the current repository: the global
+config, the org defaults (SBS_ORG_CONFIG or org_config_path) over it, and the
+repository's .sbs/config.json over those. Each key is listed with the layer
+that sets it. Outside a repository only the global and org layers apply.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipToolValidation: "true"},
+	RunE:        runConfigEffective,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configEffectiveCmd)
+}
+
+func runConfigEffective(cmd *cobra.Command, args []string) error {
+	repoRoot := ""
+	if currentRepo, err := appServices().Repository(); err == nil {
+		repoRoot = currentRepo.Root
+	}
+	layers, err := config.LoadConfigLayers(repoRoot)
+	if err != nil {
+		return err
+	}
+	settings, err := config.EffectiveSettings(layers)
+	if err != nil {
+		return err
+	}
+	fmt.Print(formatEffectiveConfig(layers, settings))
+
+	if orgPath := config.OrgConfigPath(layers[0].Config); orgPath != "" && !hasLayer(layers, config.LayerOrg) {
+		fmt.Printf("Warning: org config %s does not exist\n", orgPath)
+	}
+	return nil
+}
+
+// formatEffectiveConfig renders the layers used and one aligned
+// "key value source" line per setting, with secrets redacted
+func formatEffectiveConfig(layers []config.Layer, settings []config.Setting) string {
+	var b strings.Builder
+	for _, layer := range layers {
+		fmt.Fprintf(&b, "# %s: %s\n", layer.Name, layer.Path)
+	}
+
+	keyWidth, valueWidth := 0, 0
+	values := make([]string, len(settings))
+	for i, setting := range settings {
+		values[i] = string(setting.Value)
+		if setting.Key == "github_token" && values[i] != `""` {
+			values[i] = `"` + redactedValue + `"`
+		}
+		keyWidth = max(keyWidth, len(setting.Key))
+		valueWidth = max(valueWidth, len(values[i]))
+	}
+	for i, setting := range settings {
+		fmt.Fprintf(&b, "%-*s  %-*s  %s\n", keyWidth, setting.Key, valueWidth, values[i], setting.Source)
+	}
+	return b.String()
+}
+
+// hasLayer reports whether the named layer was loaded
+func hasLayer(layers []config.Layer, name string) bool {
+	for _, layer := range layers {
+		if layer.Name == name {
+			return true
+		}
+	}
+	return false
+}
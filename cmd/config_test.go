@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+func TestFormatEffectiveConfig(t *testing.T) {
+	layers := []config.Layer{
+		{Name: config.LayerGlobal, Path: "/home/dev/.config/sbs/config.json"},
+		{Name: config.LayerOrg, Path: "/etc/sbs/org.json"},
+	}
+	settings := []config.Setting{
+		{Key: "clean_after_days", Value: json.RawMessage("14"), Source: config.LayerOrg},
+		{Key: "github_token", Value: json.RawMessage(`"ghp_secret"`), Source: config.LayerGlobal},
+	}
+
+	assert.Equal(t, ""+
+		"# global: /home/dev/.config/sbs/config.json\n"+
+		"# org: /etc/sbs/org.json\n"+
+		"clean_after_days  14            org\n"+
+		"github_token      \"[redacted]\"  global\n", formatEffectiveConfig(layers, settings))
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/worktreefiles"
+)
+
+var copyFromMainCmd = &cobra.Command{
+	Use:   "copy-from-main [work-item-id]",
+	Short: "Copy ignored files from the main checkout into a session worktree",
+	Long: `Provision the paths listed in copy_from_main (repository .sbs/config.json)
+from the main checkout into a session's worktree. This runs automatically when
+sbs start creates a worktree; use this command to preview the plan with
+--dry-run or to provision an existing worktree.
+
+Entries are copied unless they set "symlink": true. Copies larger than
+copy_from_main_max_bytes (default 100MB) and paths that already exist in the
+worktree are skipped.
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runCopyFromMain,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(copyFromMainCmd)
+	copyFromMainCmd.Flags().BoolP("dry-run", "n", false, "Show what would be copied or linked without doing it")
+}
+
+func runCopyFromMain(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var session *config.SessionMetadata
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return sessionNotFound(workItemID)
+	}
+	if session.RepositoryRoot == "" || session.WorktreePath == "" {
+		return fmt.Errorf("session for work item %s has no repository root or worktree path", workItemID)
+	}
+
+	repoConfig, err := config.LoadConfigWithWorktree(session.RepositoryRoot, session.WorktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if len(repoConfig.CopyFromMain) == 0 {
+		fmt.Println("No copy_from_main entries configured.")
+		return nil
+	}
+
+	actions := worktreefiles.Plan(session.RepositoryRoot, session.WorktreePath, repoConfig.CopyFromMain, repoConfig.CopyFromMainMaxBytes)
+	if dryRun {
+		fmt.Printf("Would provision %s from %s:\n", session.WorktreePath, session.RepositoryRoot)
+		for _, action := range actions {
+			fmt.Printf("  %s\n", action.Describe())
+		}
+		fmt.Println("\nDry run - no changes made.")
+		return nil
+	}
+
+	applied, err := worktreefiles.Apply(actions)
+	for _, action := range actions {
+		fmt.Printf("  %s\n", action.Describe())
+	}
+	fmt.Printf("Provisioned %d path(s) into %s\n", applied, session.WorktreePath)
+	return err
+}
+
+// provisionWorktreeFiles copies or links copy_from_main entries into a new
+// worktree. Failures are reported as warnings so they don't block the session.
+func provisionWorktreeFiles(cfg *config.Config, mainRoot, worktreePath string, verbose bool) {
+	if len(cfg.CopyFromMain) == 0 {
+		return
+	}
+
+	actions := worktreefiles.Plan(mainRoot, worktreePath, cfg.CopyFromMain, cfg.CopyFromMainMaxBytes)
+	applied, err := worktreefiles.Apply(actions)
+
+	for _, action := range actions {
+		if verbose || (action.Kind == worktreefiles.ActionSkip && action.Reason != worktreefiles.ReasonExists) {
+			fmt.Printf("  %s\n", action.Describe())
+		}
+	}
+	if applied > 0 {
+		fmt.Printf("Provisioned %d path(s) from the main checkout\n", applied)
+	}
+	if err != nil {
+		startWarningf("%v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/tui"
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show a live dashboard of sessions across all repositories",
+	Long: `Launch a full-screen dashboard combining the global session table,
+cumulative statistics (sessions per repository, worktree disk usage, stale count)
+and a live feed of session starts, removals and status changes.
+
+The dashboard is also available from the interactive TUI by pressing 'D'.`,
+	Args: cobra.NoArgs,
+	RunE: runDashboard,
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	// Warnings about config files loaded later would scribble over the screen
+	config.SetWarningOutput(io.Discard)
+	defer config.SetWarningOutput(nil)
+	container := appServices()
+	model := tui.NewDashboardModel(container)
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(container.Context()))
+
+	_, err := program.Run()
+	return err
+}
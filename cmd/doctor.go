@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/issue"
+	"sbs/pkg/paths"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/timing"
+	"sbs/pkg/tmux"
+	"sbs/pkg/validation"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment sbs depends on",
+	Long: `Diagnose problems outside session metadata that make sbs commands fail
+confusingly (see 'sbs fsck' for the metadata itself):
+
+  tmux server   A socket left behind by a dead server, or a server that
+                accepts connections but never answers
+  sandbox       With sandbox_required set, 'sandbox list' failing, which
+                makes start, stop and clean fail
+  GitHub login  gh not logged in, an invalid GH_TOKEN/GITHUB_TOKEN, or a
+                token without the repo scope (when the repository's input
+                source is github, the default)
+  permissions   config.json, session metadata, history, progress and the
+                command log readable by other users; they can hold tokens
+                and work item titles
+  state         With xdg_state set, state files still in the config
+                directory, where sbs no longer reads them
+  slow commands 'git worktree add' or pool sandbox creation running over
+                timing_budgets_seconds (30s and 60s by default) in at least
+                half of its recent runs, with a suggestion to speed it up;
+                these are warnings and don't make doctor fail
+
+With --fix, a dead socket is removed and an unresponsive tmux server is
+stopped with kill-server. Stopping the server ends every session on it, but
+those sessions were unreachable anyway. Overly permissive files are made
+private (0600) and leftover state files are moved to the state directory.
+
+sbs also removes a dead socket automatically when a tmux command fails
+because of one.
+
+Exits with an error while problems remain.`,
+	Args:        cobra.NoArgs,
+	RunE:        runDoctor,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().Bool("fix", false, "Attempt to repair the problems found")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	problems := 0
+	if !checkTmuxServer(appServices().TmuxManager(), fix) {
+		problems++
+	}
+	repoRoot := ""
+	if currentRepo, err := appServices().Repository(); err == nil {
+		repoRoot = currentRepo.Root
+	}
+	if !checkRequiredSandbox(appServices().SandboxManager().WithRequired(sandboxRequiredFor(repoRoot))) {
+		problems++
+	}
+	if !checkInputSourceLogin() {
+		problems++
+	}
+	if !checkConfigLocation(fix) {
+		problems++
+	}
+	if !checkStateLocation(fix) {
+		problems++
+	}
+	if !checkFilePermissions(sensitiveFiles(cfg), fix) {
+		problems++
+	}
+	checkSlowCommands(appServices().Timings(), timing.Budgets(cfg.TimingBudgets))
+
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) need attention", problems)
+	}
+	fmt.Println("No problems found")
+	return nil
+}
+
+// checkTmuxServer reports the tmux server's health, repairing it when fix is
+// set, and returns whether it is healthy afterwards
+func checkTmuxServer(tmuxManager *tmux.Manager, fix bool) bool {
+	health := tmuxManager.CheckServer()
+	if health.Healthy() {
+		fmt.Printf("ok    %s\n", health)
+		return true
+	}
+
+	if !fix {
+		fmt.Printf("FAIL  %s (run 'sbs doctor --fix')\n", health)
+		return false
+	}
+	action, err := tmuxManager.RecoverServer(health)
+	if err != nil {
+		fmt.Printf("FAIL  %s: %v\n", health, err)
+		return false
+	}
+	fmt.Printf("fixed %s: %s\n", health, action)
+	return true
+}
+
+// checkInputSourceLogin reports whether the current repository's input source
+// (github outside a repository) has working credentials. --fix can't log in
+// for the user, so failures only say how to.
+func checkInputSourceLogin() bool {
+	sourceType := "github"
+	if currentRepo, err := appServices().Repository(); err == nil {
+		if source, err := inputsource.NewInputSourceFactory().CreateFromProject(currentRepo.Root); err == nil {
+			sourceType = source.GetType()
+		}
+	}
+	if sourceType != "github" {
+		return true
+	}
+
+	if cfg := issue.CurrentClientConfig(); cfg.UsesAPI() {
+		repo, err := issue.NewAPIClient(cfg).CheckAccess()
+		if err != nil {
+			fmt.Printf("FAIL  %v\n", err)
+			return false
+		}
+		fmt.Printf("ok    GitHub API access to %s (github_client api)\n", repo)
+		return true
+	}
+
+	auth, err := validation.CheckGitHubAuth()
+	if err != nil {
+		fmt.Printf("FAIL  %v\n", err)
+		return false
+	}
+	fmt.Printf("ok    GitHub login: %s\n", auth)
+	return true
+}
+
+// checkRequiredSandbox reports whether sandboxes work when sandbox_required
+// is set; without it sandbox failures are tolerated and nothing is checked
+func checkRequiredSandbox(sandboxManager *sandbox.Manager) bool {
+	if !sandboxManager.Required() {
+		return true
+	}
+	if err := sandboxManager.CheckAvailable(); err != nil {
+		fmt.Printf("FAIL  %v\n", err)
+		fmt.Println("      sbs start, stop and clean refuse to run until it works; check the sandbox installation or unset sandbox_required")
+		return false
+	}
+	fmt.Println("ok    sandbox (sandbox_required)")
+	return true
+}
+
+// sensitiveFiles returns the files sbs writes that can hold tokens, work item
+// titles or paths: the global config, the state files and the command log.
+// Directories stand for the files anywhere inside them.
+func sensitiveFiles(cfg *config.Config) []string {
+	var files []string
+	if configPath, err := config.GlobalConfigPath(); err == nil {
+		files = append(files, configPath)
+	}
+	if stateDir, err := paths.StateDir(); err == nil {
+		for _, name := range paths.StateFiles {
+			files = append(files, filepath.Join(stateDir, name))
+		}
+	}
+	if cfg != nil && cfg.CommandLogPath != "" {
+		files = append(files, cfg.CommandLogPath)
+	}
+	return files
+}
+
+// checkFilePermissions reports files other users can read or write, making
+// them private when fix is set, and returns whether none are left
+func checkFilePermissions(files []string, fix bool) bool {
+	var exposed []string
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			if paths.TooOpen(info.Mode()) {
+				exposed = append(exposed, file)
+			}
+			continue
+		}
+		_ = filepath.WalkDir(file, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info, err := entry.Info(); err == nil && info.Mode().IsRegular() && paths.TooOpen(info.Mode()) {
+				exposed = append(exposed, path)
+			}
+			return nil
+		})
+	}
+
+	healthy := true
+	for _, file := range exposed {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		mode := info.Mode().Perm()
+		if !fix {
+			fmt.Printf("FAIL  %s is mode %04o, accessible to other users (run 'sbs doctor --fix')\n", file, mode)
+			healthy = false
+			continue
+		}
+		if err := os.Chmod(file, paths.PrivateFileMode); err != nil {
+			fmt.Printf("FAIL  %s is mode %04o: %v\n", file, mode, err)
+			healthy = false
+			continue
+		}
+		fmt.Printf("fixed %s was mode %04o, now %04o\n", file, mode, paths.PrivateFileMode)
+	}
+	if len(exposed) == 0 {
+		fmt.Println("ok    sbs files are private to you")
+	}
+	return healthy
+}
+
+// checkSlowCommands warns about operations that chronically run over their
+// budget. Slowness is advisory, so it never counts as a problem.
+func checkSlowCommands(store *timing.Store, budgets map[string]time.Duration) {
+	if store == nil {
+		return
+	}
+	history, err := store.Load()
+	if err != nil {
+		fmt.Printf("warn  %v\n", err)
+		return
+	}
+	findings := timing.Slow(history, budgets)
+	for _, finding := range findings {
+		fmt.Printf("warn  %s\n", finding)
+		if finding.Hint != "" {
+			fmt.Printf("      %s\n", finding.Hint)
+		}
+	}
+	if len(findings) == 0 {
+		fmt.Println("ok    no chronically slow commands")
+	}
+}
+
+// checkConfigLocation reports a config directory still in ~/.config/sbs
+// while XDG_CONFIG_HOME names another place, moving it there when fix is
+// set. sbs keeps using the old directory until then, so it is only a
+// warning.
+func checkConfigLocation(fix bool) bool {
+	if paths.ConfigFileOverride() != "" {
+		return true
+	}
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return true
+	}
+	xdgDir, err := paths.XDGConfigDir()
+	if err != nil || configDir == xdgDir || configDir != paths.LegacyConfigDir() {
+		return true
+	}
+
+	if !fix {
+		fmt.Printf("warn  config directory %s is used because %s doesn't exist (run 'sbs doctor --fix' to move it)\n", configDir, xdgDir)
+		return true
+	}
+	if err := os.MkdirAll(filepath.Dir(xdgDir), paths.PrivateDirMode); err != nil {
+		fmt.Printf("FAIL  failed to create %s: %v\n", filepath.Dir(xdgDir), err)
+		return false
+	}
+	if err := os.Rename(configDir, xdgDir); err != nil {
+		fmt.Printf("FAIL  failed to move %s to %s: %v\n", configDir, xdgDir, err)
+		return false
+	}
+	fmt.Printf("fixed %s moved to %s\n", configDir, xdgDir)
+	return true
+}
+
+// checkStateLocation reports state files left in the config directory after
+// xdg_state moved the state directory, moving them over when fix is set.
+// A file already in the state directory is never overwritten.
+func checkStateLocation(fix bool) bool {
+	if !paths.XDGState() {
+		return true
+	}
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return true
+	}
+	stateDir, err := paths.StateDir()
+	if err != nil || stateDir == configDir {
+		return true
+	}
+
+	healthy := true
+	for _, name := range paths.StateFiles {
+		legacy := filepath.Join(configDir, name)
+		if _, err := os.Stat(legacy); err != nil {
+			continue
+		}
+		target := filepath.Join(stateDir, name)
+		if _, err := os.Stat(target); err == nil {
+			fmt.Printf("FAIL  %s is unused since xdg_state keeps state in %s, which already has %s; merge or remove it by hand\n", legacy, stateDir, name)
+			healthy = false
+			continue
+		}
+		if !fix {
+			fmt.Printf("FAIL  %s is unused since xdg_state keeps state in %s (run 'sbs doctor --fix' to move it)\n", legacy, stateDir)
+			healthy = false
+			continue
+		}
+		if err := os.MkdirAll(stateDir, paths.PrivateDirMode); err != nil {
+			fmt.Printf("FAIL  failed to create %s: %v\n", stateDir, err)
+			return false
+		}
+		if err := os.Rename(legacy, target); err != nil {
+			fmt.Printf("FAIL  failed to move %s to %s: %v\n", legacy, stateDir, err)
+			healthy = false
+			continue
+		}
+		fmt.Printf("fixed %s moved to %s\n", legacy, target)
+	}
+	if healthy {
+		fmt.Printf("ok    state directory %s\n", stateDir)
+	}
+	return healthy
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/timing"
+	"sbs/pkg/tmux"
+)
+
+func TestRunDoctor_DeadTmuxSocket(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	previousCfg := cfg
+	cfg = config.DefaultConfig()
+	t.Cleanup(func() { cfg = previousCfg })
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "gh"), []byte("#!/bin/sh\nexit 0\n"), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	// Leave a socket behind with no server listening on it
+	socket := tmux.DefaultSocketPath()
+	require.NoError(t, os.MkdirAll(filepath.Dir(socket), 0700))
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	require.NoError(t, err)
+	listener.SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+
+	output := captureStdout(t, func() {
+		assert.Error(t, runDoctor(doctorCmd, nil))
+	})
+	assert.Contains(t, output, "FAIL  tmux server dead socket")
+	assert.FileExists(t, socket, "nothing is changed without --fix")
+
+	require.NoError(t, doctorCmd.Flags().Set("fix", "true"))
+	defer doctorCmd.Flags().Set("fix", "false")
+	output = captureStdout(t, func() {
+		assert.NoError(t, runDoctor(doctorCmd, nil))
+	})
+	assert.Contains(t, output, "fixed tmux server dead socket")
+	assert.NoFileExists(t, socket)
+}
+
+func TestCheckRequiredSandbox(t *testing.T) {
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "sandbox"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	output := captureStdout(t, func() {
+		assert.True(t, checkRequiredSandbox(sandbox.NewManager()), "nothing is checked without sandbox_required")
+	})
+	assert.Empty(t, output)
+
+	output = captureStdout(t, func() {
+		assert.False(t, checkRequiredSandbox(sandbox.NewManager().WithRequired(true)))
+	})
+	assert.Contains(t, output, "FAIL  sandbox_required is set but 'sandbox list' failed")
+	assert.Contains(t, output, "unset sandbox_required")
+}
+
+func TestCheckFilePermissions(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	sessionsPath := filepath.Join(dir, "sessions.json")
+	progressDir := filepath.Join(dir, "progress")
+	progressPath := filepath.Join(progressDir, "test_1.json")
+	require.NoError(t, os.WriteFile(configPath, []byte("{}"), 0600))
+	require.NoError(t, os.WriteFile(sessionsPath, []byte("[]"), 0600))
+	require.NoError(t, os.MkdirAll(progressDir, 0755))
+	require.NoError(t, os.WriteFile(progressPath, []byte("{}"), 0600))
+	require.NoError(t, os.Chmod(sessionsPath, 0644))
+	require.NoError(t, os.Chmod(progressPath, 0640))
+	archivePath := filepath.Join(dir, "loghook-archive", "test_1", "snapshot.log")
+	require.NoError(t, os.MkdirAll(filepath.Dir(archivePath), 0755))
+	require.NoError(t, os.WriteFile(archivePath, []byte("output"), 0644))
+	files := []string{configPath, sessionsPath, progressDir, filepath.Dir(filepath.Dir(archivePath)), filepath.Join(dir, "missing.json")}
+
+	output := captureStdout(t, func() {
+		assert.False(t, checkFilePermissions(files, false))
+	})
+	assert.Contains(t, output, "FAIL  "+sessionsPath+" is mode 0644, accessible to other users (run 'sbs doctor --fix')")
+	assert.Contains(t, output, "FAIL  "+progressPath+" is mode 0640")
+	assert.Contains(t, output, "FAIL  "+archivePath+" is mode 0644", "files in nested directories are checked")
+	assert.NotContains(t, output, configPath)
+	info, err := os.Stat(sessionsPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm(), "nothing is changed without --fix")
+
+	output = captureStdout(t, func() {
+		assert.True(t, checkFilePermissions(files, true))
+	})
+	assert.Contains(t, output, "fixed "+sessionsPath+" was mode 0644, now 0600")
+	for _, path := range []string{sessionsPath, progressPath, archivePath} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), path)
+	}
+
+	output = captureStdout(t, func() {
+		assert.True(t, checkFilePermissions(files, false))
+	})
+	assert.Equal(t, "ok    sbs files are private to you\n", output)
+}
+
+func TestCheckConfigLocation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	legacyDir := filepath.Join(home, ".config", "sbs")
+	xdgDir := filepath.Join(home, "xdg", "sbs")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Dir(xdgDir))
+	require.NoError(t, os.MkdirAll(legacyDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "sessions.json"), []byte("[]"), 0600))
+
+	output := captureStdout(t, func() {
+		assert.True(t, checkConfigLocation(false), "the old directory still works")
+	})
+	assert.Equal(t, "warn  config directory "+legacyDir+" is used because "+xdgDir+" doesn't exist (run 'sbs doctor --fix' to move it)\n", output)
+
+	output = captureStdout(t, func() {
+		assert.True(t, checkConfigLocation(true))
+	})
+	assert.Equal(t, "fixed "+legacyDir+" moved to "+xdgDir+"\n", output)
+	assert.FileExists(t, filepath.Join(xdgDir, "sessions.json"))
+	configDir, err := paths.ConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, xdgDir, configDir)
+
+	output = captureStdout(t, func() {
+		assert.True(t, checkConfigLocation(false))
+	})
+	assert.Empty(t, output)
+}
+
+func TestCheckStateLocation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Cleanup(func() { paths.SetXDGState(false) })
+	configDir := filepath.Join(home, ".config", "sbs")
+	stateDir := filepath.Join(home, ".local", "state", "sbs")
+	require.NoError(t, os.MkdirAll(configDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "sessions.json"), []byte("[]"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte("{}"), 0600))
+
+	output := captureStdout(t, func() {
+		assert.True(t, checkStateLocation(false), "nothing is checked without xdg_state")
+	})
+	assert.Empty(t, output)
+
+	paths.SetXDGState(true)
+	output = captureStdout(t, func() {
+		assert.False(t, checkStateLocation(false))
+	})
+	assert.Contains(t, output, "FAIL  "+filepath.Join(configDir, "sessions.json")+" is unused since xdg_state keeps state in "+stateDir)
+
+	output = captureStdout(t, func() {
+		assert.True(t, checkStateLocation(true))
+	})
+	assert.Contains(t, output, "fixed "+filepath.Join(configDir, "sessions.json")+" moved to "+filepath.Join(stateDir, "sessions.json"))
+	assert.FileExists(t, filepath.Join(stateDir, "sessions.json"))
+	assert.FileExists(t, filepath.Join(configDir, "config.json"), "the config file stays")
+
+	// A leftover is never moved over state already in place
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "sessions.json"), []byte("[]"), 0600))
+	output = captureStdout(t, func() {
+		assert.False(t, checkStateLocation(true))
+	})
+	assert.Contains(t, output, "merge or remove it by hand")
+	assert.FileExists(t, filepath.Join(configDir, "sessions.json"))
+}
+
+func TestCheckSlowCommands(t *testing.T) {
+	store := timing.NewStore(filepath.Join(t.TempDir(), "timings.json"))
+	output := captureStdout(t, func() {
+		checkSlowCommands(store, timing.Budgets(nil))
+	})
+	assert.Equal(t, "ok    no chronically slow commands\n", output)
+
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	for _, secs := range []int{20, 40, 50} {
+		require.NoError(t, store.Record(timing.OpWorktreeAdd, time.Duration(secs)*time.Second, now))
+	}
+	output = captureStdout(t, func() {
+		checkSlowCommands(store, timing.Budgets(nil))
+	})
+	assert.Contains(t, output, "warn  git worktree add took longer than 30s in 2 of the last 3 runs (median 40s)\n")
+	assert.Contains(t, output, "      large checkouts are slow to create")
+
+	output = captureStdout(t, func() {
+		checkSlowCommands(store, timing.Budgets(map[string]int{timing.OpWorktreeAdd: 60}))
+	})
+	assert.Equal(t, "ok    no chronically slow commands\n", output, "budgets come from timing_budgets_seconds")
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"sbs/pkg/inputsource"
+)
+
+var doneCmd = &cobra.Command{
+	Use:   "done [work-item-id]",
+	Short: "Mark a work item as done in its input source",
+	Long: `Mark the work item as done in the tracker it came from.
+For GitHub work items this closes the issue.
+
+This is a shortcut for 'sbs transition <work-item-id> --state done'.
+
+Work item ID formats:
+  sbs done 123           # Primary work type
+  sbs done github:123    # Namespaced work item
+  sbs done test:my-test  # Test work type
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDone,
+}
+
+func init() {
+	rootCmd.AddCommand(doneCmd)
+}
+
+func runDone(cmd *cobra.Command, args []string) error {
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+	return transitionWorkItem(workItemID, inputsource.StateDone)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/fsck"
+	"sbs/pkg/oplock"
+	"sbs/pkg/paths"
+)
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check session metadata for problems",
+	Long: `Validate the entries in sessions.json against reality: worktree and
+repository paths exist, namespaced IDs parse and are unique, timestamps
+parse, and the sessions match the checksum heading the file.
+
+With --repair, problems that can be fixed safely are fixed: source types
+are derived from the namespaced ID, sessions whose worktree is gone are
+marked stale, timestamps are rewritten as RFC 3339, duplicate entries are
+merged (the newest is kept, the rest archived to sessions-archive.json) and
+the checksum is rewritten. Everything else is reported and
+left for you to resolve.
+
+Exits with an error while problems remain.`,
+	Args:        cobra.NoArgs,
+	RunE:        runFsck,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(fsckCmd)
+	fsckCmd.Flags().Bool("repair", false, "Fix the problems that can be repaired safely")
+}
+
+func runFsck(cmd *cobra.Command, args []string) error {
+	repair, _ := cmd.Flags().GetBool("repair")
+
+	sessionsPath, err := config.GetGlobalSessionsPath()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions path: %w", err)
+	}
+
+	if repair {
+		// Hold the sessions lock from load to save, as config.UpdateSessions
+		// would, so repairs don't drop another process's changes
+		if err := os.MkdirAll(filepath.Dir(sessionsPath), paths.PrivateDirMode); err != nil {
+			return err
+		}
+		unlock, err := oplock.File(config.SessionsLockPath(sessionsPath))
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	checksumErr := config.VerifySessionsChecksum(sessionsPath)
+	if checksumErr != nil && !errors.Is(checksumErr, config.ErrSessionsChecksum) {
+		return checksumErr
+	}
+
+	sessions, err := config.LoadSessionsFromPathUnverified(sessionsPath)
+	if err != nil {
+		return fmt.Errorf("%s cannot be parsed and must be restored by hand: %w", sessionsPath, err)
+	}
+
+	result := fsck.Check(sessions, repair)
+	if checksumErr != nil {
+		// The content still parses, so saving it again records a fresh checksum
+		result.Problems = append([]fsck.Problem{{
+			Session:    sessionsPath,
+			Message:    "does not match its recorded checksum (interrupted write or hand edit)",
+			Repairable: true,
+			Repaired:   repair,
+		}}, result.Problems...)
+	}
+
+	for _, problem := range result.Problems {
+		fmt.Println(problem)
+	}
+
+	if repair && result.Changed() {
+		if err := config.SaveSessionsToPath(result.Sessions, sessionsPath); err != nil {
+			return fmt.Errorf("failed to save repaired sessions: %w", err)
+		}
+	}
+
+	fmt.Println(fsckSummary(len(sessions), result, repair))
+	if remaining := result.Unrepaired(); remaining > 0 {
+		return fmt.Errorf("%d problem(s) need attention", remaining)
+	}
+	return nil
+}
+
+// fsckSummary describes the outcome of a check in one line
+func fsckSummary(checked int, result *fsck.Result, repair bool) string {
+	if len(result.Problems) == 0 {
+		return fmt.Sprintf("Checked %d session(s): no problems found", checked)
+	}
+
+	repaired, repairable := 0, 0
+	for _, problem := range result.Problems {
+		if problem.Repaired {
+			repaired++
+		} else if problem.Repairable {
+			repairable++
+		}
+	}
+
+	summary := fmt.Sprintf("Checked %d session(s): %d problem(s)", checked, len(result.Problems))
+	if repair {
+		return summary + fmt.Sprintf(", %d repaired", repaired)
+	}
+	if repairable > 0 {
+		summary += fmt.Sprintf(", %d repairable with --repair", repairable)
+	}
+	return summary
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/fsck"
+)
+
+func TestFsckSummary(t *testing.T) {
+	assert.Equal(t, "Checked 2 session(s): no problems found", fsckSummary(2, &fsck.Result{}, false))
+
+	result := &fsck.Result{Problems: []fsck.Problem{
+		{Message: "a", Repairable: true},
+		{Message: "b"},
+	}}
+	assert.Equal(t, "Checked 3 session(s): 2 problem(s), 1 repairable with --repair", fsckSummary(3, result, false))
+
+	result.Problems[0].Repaired = true
+	assert.Equal(t, "Checked 3 session(s): 2 problem(s), 1 repaired", fsckSummary(3, result, true))
+}
+
+func TestRunFsck_RepairsChecksum(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	worktree := t.TempDir()
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{{
+		NamespacedID: "test:fsck",
+		SourceType:   "test",
+		Branch:       "issue-test-fsck",
+		TmuxSession:  "sbs-repo-test-fsck",
+		WorktreePath: worktree,
+		Status:       "active",
+	}}))
+
+	// Simulate a hand edit that leaves the checksum stale
+	sessionsPath, err := config.GetGlobalSessionsPath()
+	require.NoError(t, err)
+	data, err := os.ReadFile(sessionsPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(sessionsPath, bytes.Replace(data, []byte(`"active"`), []byte(`"stopped"`), 1), 0644))
+
+	_, err = config.LoadSessions()
+	require.ErrorIs(t, err, config.ErrSessionsChecksum)
+
+	require.NoError(t, fsckCmd.Flags().Set("repair", "false"))
+	assert.Error(t, runFsck(fsckCmd, nil), "problems remain without --repair")
+
+	require.NoError(t, fsckCmd.Flags().Set("repair", "true"))
+	t.Cleanup(func() { fsckCmd.Flags().Set("repair", "false") })
+	require.NoError(t, runFsck(fsckCmd, nil))
+
+	sessions, err := config.LoadSessions()
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/report"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse past work sessions",
+	Long: `List the sessions sbs clean removed, and starts that failed, newest first:
+when each was removed, its work item, how long it was in use, its branch and
+pull request. Removed sessions are kept in sessions-archive.json next to the
+sessions file.
+
+--purge-history trims that file instead, removing entries older than
+--older-than. Snapshots recorded on a purged session can no longer be
+restored by work item ID.
+
+Examples:
+  sbs history                         # All past sessions
+  sbs history --repo web              # Past sessions of one repository (name or path)
+  sbs history --purge-history --older-than 180d`,
+	Args:        cobra.NoArgs,
+	RunE:        runHistory,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().String("repo", "", "Only sessions of this repository, by name or path")
+	historyCmd.Flags().Bool("purge-history", false, "Remove history entries older than --older-than instead of listing them")
+	historyCmd.Flags().String("older-than", "90d", "Age of the entries --purge-history removes: days or weeks (90d, 12w), a duration or a date")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	repoFilter, _ := cmd.Flags().GetString("repo")
+	purge, _ := cmd.Flags().GetBool("purge-history")
+	olderThan, _ := cmd.Flags().GetString("older-than")
+
+	archivePath, err := config.GlobalSessionsArchivePath()
+	if err != nil {
+		return err
+	}
+
+	if purge {
+		cutoff, err := report.ParseSince(olderThan, config.Now())
+		if err != nil {
+			return exitcode.Errorf(exitcode.Validation, "invalid --older-than value %q (use e.g. 90d, 12w, 36h or 2025-08-01)", olderThan)
+		}
+		removed, err := config.PurgeArchivedSessions(archivePath, func(entry config.ArchivedSession) bool {
+			return inHistoryRepo(entry.Session, repoFilter) && archivedBefore(entry, cutoff)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to purge session history: %w", err)
+		}
+		fmt.Printf("Removed %d history entr%s from before %s\n", removed, pluralY(removed), cutoff.Format("2006-01-02"))
+		return nil
+	}
+
+	archived, err := config.LoadArchivedSessions(archivePath)
+	if err != nil {
+		return err
+	}
+	entries := historyEntries(archived, repoFilter)
+	if len(entries) == 0 {
+		fmt.Println("No past sessions.")
+		return nil
+	}
+	fmt.Print(formatHistory(entries))
+	return nil
+}
+
+// historyEntries returns the archived sessions of repoFilter (all when
+// empty) worth browsing, newest first. Entries archived as duplicates were
+// never separate sessions and are left out.
+func historyEntries(archived []config.ArchivedSession, repoFilter string) []config.ArchivedSession {
+	var entries []config.ArchivedSession
+	for _, entry := range archived {
+		if entry.Reason == config.ArchiveReasonDuplicate || !inHistoryRepo(entry.Session, repoFilter) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return archivedTime(entries[i]).After(archivedTime(entries[j]))
+	})
+	return entries
+}
+
+// inHistoryRepo reports whether a session belongs to repoFilter, a
+// repository name or path; an empty filter matches every session
+func inHistoryRepo(session config.SessionMetadata, repoFilter string) bool {
+	return repoFilter == "" || config.InRepositoryGroup(session, []string{repoFilter})
+}
+
+// formatHistory renders history entries as aligned columns
+func formatHistory(entries []config.ArchivedSession) string {
+	rows := [][]string{{"REMOVED", "WORK ITEM", "IN USE", "BRANCH", "PR", "TITLE"}}
+	for _, entry := range entries {
+		session := entry.Session
+		removed := "-"
+		if at := archivedTime(entry); !at.IsZero() {
+			removed = at.Local().Format("2006-01-02")
+		}
+		pr := "-"
+		if session.PullRequestNumber > 0 {
+			pr = fmt.Sprintf("#%d", session.PullRequestNumber)
+		}
+		title := session.IssueTitle
+		if entry.Reason == config.ArchiveReasonFailed {
+			title = strings.TrimSpace(title + " (start failed)")
+		}
+		rows = append(rows, []string{removed, session.NamespacedID, sessionInUse(session), orDash(session.Branch), pr, title})
+	}
+
+	widths := make([]int, len(rows[0])-1)
+	for _, row := range rows {
+		for i := range widths {
+			widths[i] = max(widths[i], len(row[i]))
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for i, width := range widths {
+			fmt.Fprintf(&b, "%-*s  ", width, row[i])
+		}
+		b.WriteString(row[len(row)-1])
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sessionInUse is how long a session was in use: from its creation to when
+// it was last used
+func sessionInUse(session config.SessionMetadata) string {
+	created, err := time.Parse(time.RFC3339, session.CreatedAt)
+	lastUsed := session.LastUsed()
+	if err != nil || lastUsed.Before(created) {
+		return "-"
+	}
+	return report.FormatDuration(lastUsed.Sub(created))
+}
+
+// archivedTime is when an entry was archived, zero when unknown
+func archivedTime(entry config.ArchivedSession) time.Time {
+	t, err := time.Parse(time.RFC3339, entry.ArchivedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// archivedBefore reports whether an entry was archived before cutoff;
+// entries without a valid time count as old
+func archivedBefore(entry config.ArchivedSession, cutoff time.Time) bool {
+	return archivedTime(entry).Before(cutoff)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
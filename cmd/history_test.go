@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+)
+
+func TestHistoryEntries(t *testing.T) {
+	archived := []config.ArchivedSession{
+		{ArchivedAt: "2026-03-01T10:00:00Z", Reason: config.ArchiveReasonCleaned, Session: config.SessionMetadata{
+			NamespacedID: "github:12", RepositoryName: "web", IssueTitle: "Fix login", Branch: "issue-github-12",
+			CreatedAt: "2026-02-20T09:00:00Z", LastActivity: "2026-02-20T12:30:00Z", PullRequestNumber: 45,
+		}},
+		{ArchivedAt: "2026-03-02T10:00:00Z", Reason: config.ArchiveReasonDuplicate, Session: config.SessionMetadata{NamespacedID: "github:12", RepositoryName: "web"}},
+		{ArchivedAt: "2026-03-03T10:00:00Z", Reason: config.ArchiveReasonFailed, Session: config.SessionMetadata{
+			NamespacedID: "github:13", RepositoryName: "web", IssueTitle: "Add search",
+		}},
+		{ArchivedAt: "2026-03-04T10:00:00Z", Reason: config.ArchiveReasonCleaned, Session: config.SessionMetadata{NamespacedID: "jira:API-1", RepositoryName: "api"}},
+	}
+
+	entries := historyEntries(archived, "web")
+	require.Len(t, entries, 2, "duplicates and other repositories are left out")
+	assert.Equal(t, "github:13", entries[0].Session.NamespacedID, "newest first")
+
+	output := formatHistory(entries)
+	assert.Contains(t, output, "REMOVED     WORK ITEM  IN USE  BRANCH           PR   TITLE\n")
+	assert.Contains(t, output, "github:13  -       -                -    Add search (start failed)\n")
+	assert.Contains(t, output, "github:12  3h 30m  issue-github-12  #45  Fix login\n")
+
+	assert.Len(t, historyEntries(archived, ""), 3)
+}
+
+func TestRunHistory_Purge(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { config.SetClock(nil) })
+	archivePath, err := config.GlobalSessionsArchivePath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(archivePath), 0700))
+
+	config.SetClock(clock.NewFake(time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)))
+	require.NoError(t, config.ArchiveSessions(archivePath, []config.SessionMetadata{{NamespacedID: "github:1", RepositoryName: "web"}}, config.ArchiveReasonCleaned))
+	config.SetClock(clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+	require.NoError(t, config.ArchiveSessions(archivePath, []config.SessionMetadata{{NamespacedID: "github:2", RepositoryName: "web"}}, config.ArchiveReasonCleaned))
+	config.SetClock(clock.NewFake(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)))
+
+	require.NoError(t, historyCmd.Flags().Set("purge-history", "true"))
+	t.Cleanup(func() { _ = historyCmd.Flags().Set("purge-history", "false") })
+	output := captureStdout(t, func() { require.NoError(t, runHistory(historyCmd, nil)) })
+	assert.Contains(t, output, "Removed 1 history entry from before 2025-12-04")
+
+	archived, err := config.LoadArchivedSessions(archivePath)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "github:2", archived[0].Session.NamespacedID)
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"sbs/pkg/exitcode"
 )
 
 var initCmd = &cobra.Command{
@@ -20,19 +21,35 @@ This command creates:
 - .sbs/claude-code-stop-hook.sh (copied from scripts/ directory)
 
 After running this command, 'sbs start' will use the local .sbs/start
-script if it exists, otherwise will start the session without any script.`,
-	RunE: runInit,
+script if it exists, otherwise will start the session without any script.
+
+With --check, nothing is created; instead the repository is inspected and a
+checklist printed, with a fix for each problem:
+- .sbs directory and the stop hook, installed and executable
+- .sbs/input-source.json, when present, naming a supported source
+- .sbs/loghook and .sbs/loghooks/ scripts present and executable, and a
+  readable .sbs/loghook.json
+- .sbs/config.json parsing and passing validation over the global config
+
+The check fails while any item does, so it can run in CI.`,
+	RunE:        runInit,
+	Annotations: map[string]string{skipToolValidation: "true"},
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().Bool("force", false, "Overwrite existing files")
 	initCmd.Flags().Bool("dry-run", false, "Show what would be created without making changes")
+	initCmd.Flags().Bool("check", false, "Check the repository's SBS setup and print a checklist with fixes")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	check, _ := cmd.Flags().GetBool("check")
+	if check && (force || dryRun) {
+		return exitcode.Errorf(exitcode.Validation, "--check cannot be combined with --force or --dry-run")
+	}
 
 	// Get current working directory
 	cwd, err := os.Getwd()
@@ -44,6 +61,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if !isGitRepository(cwd) {
 		return fmt.Errorf("must be run from within a git repository")
 	}
+	if check {
+		return runInitCheck(cwd)
+	}
 
 	sbsDir := filepath.Join(cwd, ".sbs")
 	startScript := filepath.Join(sbsDir, "start")
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/loghook"
+)
+
+// stopHookFile is the Claude Code stop hook sbs init copies into .sbs
+const stopHookFile = "claude-code-stop-hook.sh"
+
+// onboardingCheck is one line of the sbs init --check checklist
+type onboardingCheck struct {
+	Name   string
+	OK     bool
+	Detail string
+	// Fix is the command or edit that resolves a failed check
+	Fix string
+}
+
+// checkRepositoryOnboarding inspects a repository for what sbs sessions
+// need from it: the .sbs directory, the stop hook, a valid input source,
+// loghook scripts and sane config overrides
+func checkRepositoryOnboarding(repoRoot string, base *config.Config) []onboardingCheck {
+	sbsDir := filepath.Join(repoRoot, ".sbs")
+	if info, err := os.Stat(sbsDir); err != nil || !info.IsDir() {
+		return []onboardingCheck{{Name: ".sbs directory", Detail: "missing", Fix: "sbs init"}}
+	}
+
+	checks := []onboardingCheck{
+		{Name: ".sbs directory", OK: true, Detail: "present"},
+		checkStopHook(repoRoot),
+		checkInputSourceConfig(repoRoot),
+	}
+	checks = append(checks, checkLoghooks(repoRoot)...)
+	return append(checks, checkConfigOverrides(repoRoot, base))
+}
+
+// checkStopHook reports whether the stop hook is installed and executable
+func checkStopHook(repoRoot string) onboardingCheck {
+	check := onboardingCheck{Name: "stop hook"}
+	rel := filepath.Join(".sbs", stopHookFile)
+	info, err := os.Stat(filepath.Join(repoRoot, rel))
+	switch {
+	case err != nil:
+		check.Detail = rel + " is missing"
+		check.Fix = fmt.Sprintf("cp scripts/%s %s", stopHookFile, rel)
+		if !fileExists(filepath.Join(repoRoot, "scripts", stopHookFile)) {
+			check.Fix = "copy " + stopHookFile + " from the sbs repository's scripts/ directory to " + rel
+		}
+	case info.Mode().Perm()&0111 == 0:
+		check.Detail = rel + " is not executable"
+		check.Fix = "chmod +x " + rel
+	default:
+		check.OK = true
+		check.Detail = rel
+	}
+	return check
+}
+
+// checkInputSourceConfig reports whether .sbs/input-source.json, when
+// present, names a supported source
+func checkInputSourceConfig(repoRoot string) onboardingCheck {
+	check := onboardingCheck{Name: "input source"}
+	rel := filepath.Join(".sbs", "input-source.json")
+	if !fileExists(filepath.Join(repoRoot, rel)) {
+		check.OK = true
+		check.Detail = "github (default, no " + rel + ")"
+		return check
+	}
+	source, err := inputsource.NewInputSourceFactory().CreateFromProject(repoRoot)
+	if err != nil {
+		check.Detail = fmt.Sprintf("%s: %v", rel, err)
+		check.Fix = fmt.Sprintf(`edit %s, e.g. {"type": "github"}`, rel)
+		return check
+	}
+	check.OK = true
+	check.Detail = fmt.Sprintf("%s (%s)", source.GetType(), rel)
+	return check
+}
+
+// checkLoghooks reports the loghook scripts and their settings. Without
+// one the log view falls back to the tmux pane.
+func checkLoghooks(repoRoot string) []onboardingCheck {
+	sources, err := loghook.DiscoverSources(repoRoot)
+	if err != nil {
+		return []onboardingCheck{{Name: "loghook", Detail: err.Error(), Fix: "check the permissions of .sbs/" + loghook.SourcesDir}}
+	}
+	if len(sources) == 0 {
+		return []onboardingCheck{{
+			Name:   "loghook",
+			Detail: "no .sbs/loghook or .sbs/" + loghook.SourcesDir + "/ scripts; the log view shows the tmux pane instead",
+			Fix:    "create an executable .sbs/loghook that prints the session's log",
+		}}
+	}
+
+	var checks []onboardingCheck
+	for _, source := range sources {
+		rel, _ := filepath.Rel(repoRoot, source.Path)
+		check := onboardingCheck{Name: "loghook", OK: true, Detail: rel}
+		if err := loghook.ValidateScript(source.Path); err != nil {
+			check.OK = false
+			check.Detail = err.Error()
+			check.Fix = "chmod +x " + rel
+		}
+		checks = append(checks, check)
+	}
+	if _, err := loghook.LoadSettings(repoRoot); err != nil {
+		checks = append(checks, onboardingCheck{
+			Name:   "loghook",
+			Detail: err.Error(),
+			Fix:    "edit .sbs/" + loghook.SettingsFile,
+		})
+	}
+	return checks
+}
+
+// checkConfigOverrides reports whether .sbs/config.json parses and passes
+// validation over the global config
+func checkConfigOverrides(repoRoot string, base *config.Config) onboardingCheck {
+	check := onboardingCheck{Name: "config overrides"}
+	rel := filepath.Join(".sbs", "config.json")
+	if !fileExists(config.RepositoryConfigPath(repoRoot)) {
+		check.OK = true
+		check.Detail = "none (no " + rel + ")"
+		return check
+	}
+	if err := config.ValidateRepositoryConfig(base, repoRoot); err != nil {
+		check.Detail = err.Error()
+		check.Fix = "edit " + rel
+		return check
+	}
+	check.OK = true
+	check.Detail = rel
+	return check
+}
+
+// runInitCheck prints the onboarding checklist and fails when any check does
+func runInitCheck(repoRoot string) error {
+	fmt.Printf("Checking SBS setup of repository: %s\n\n", repoRoot)
+	failed := 0
+	for _, check := range checkRepositoryOnboarding(repoRoot, cfg) {
+		if check.OK {
+			fmt.Printf("ok    %-17s %s\n", check.Name, check.Detail)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL  %-17s %s\n", check.Name, check.Detail)
+		fmt.Printf("      fix: %s\n", check.Fix)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	fmt.Println("\nRepository is ready for SBS")
+	return nil
+}
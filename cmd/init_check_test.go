@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func writeRepoFile(t *testing.T, repoRoot, rel, content string, mode os.FileMode) {
+	t.Helper()
+	path := filepath.Join(repoRoot, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), mode))
+}
+
+func failedChecks(checks []onboardingCheck) map[string]onboardingCheck {
+	failed := make(map[string]onboardingCheck)
+	for _, check := range checks {
+		if !check.OK {
+			failed[check.Name] = check
+		}
+	}
+	return failed
+}
+
+func TestCheckRepositoryOnboarding_MissingSbsDir(t *testing.T) {
+	checks := checkRepositoryOnboarding(t.TempDir(), config.DefaultConfig())
+	require.Len(t, checks, 1)
+	assert.False(t, checks[0].OK)
+	assert.Equal(t, "sbs init", checks[0].Fix)
+}
+
+func TestCheckRepositoryOnboarding_Ready(t *testing.T) {
+	repoRoot := t.TempDir()
+	writeRepoFile(t, repoRoot, ".sbs/claude-code-stop-hook.sh", "#!/bin/sh\n", 0755)
+	writeRepoFile(t, repoRoot, ".sbs/input-source.json", `{"type": "test"}`, 0644)
+	writeRepoFile(t, repoRoot, ".sbs/loghook", "#!/bin/sh\n", 0755)
+	writeRepoFile(t, repoRoot, ".sbs/config.json", `{"tmux_command": "claude"}`, 0644)
+
+	checks := checkRepositoryOnboarding(repoRoot, config.DefaultConfig())
+	assert.Empty(t, failedChecks(checks))
+	assert.Len(t, checks, 5)
+}
+
+func TestCheckRepositoryOnboarding_Problems(t *testing.T) {
+	repoRoot := t.TempDir()
+	writeRepoFile(t, repoRoot, ".sbs/claude-code-stop-hook.sh", "#!/bin/sh\n", 0644)
+	writeRepoFile(t, repoRoot, ".sbs/input-source.json", `{"type": "trello"}`, 0644)
+	writeRepoFile(t, repoRoot, ".sbs/loghooks/tests", "#!/bin/sh\n", 0644)
+	writeRepoFile(t, repoRoot, ".sbs/config.json", `{"messages": {"start.bogus": "x"}}`, 0644)
+
+	failed := failedChecks(checkRepositoryOnboarding(repoRoot, config.DefaultConfig()))
+	assert.Equal(t, "chmod +x .sbs/claude-code-stop-hook.sh", failed["stop hook"].Fix)
+	assert.Contains(t, failed["input source"].Detail, "trello")
+	assert.Equal(t, "chmod +x .sbs/loghooks/tests", failed["loghook"].Fix)
+	assert.Equal(t, "edit .sbs/config.json", failed["config overrides"].Fix)
+}
+
+func TestCheckRepositoryOnboarding_MissingHookAndLoghook(t *testing.T) {
+	repoRoot := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repoRoot, ".sbs"), 0755))
+	writeRepoFile(t, repoRoot, "scripts/claude-code-stop-hook.sh", "#!/bin/sh\n", 0755)
+
+	failed := failedChecks(checkRepositoryOnboarding(repoRoot, config.DefaultConfig()))
+	assert.Equal(t, "cp scripts/claude-code-stop-hook.sh .sbs/claude-code-stop-hook.sh", failed["stop hook"].Fix)
+	assert.Contains(t, failed["loghook"].Detail, "no .sbs/loghook")
+	assert.NotContains(t, failed, "input source")
+	assert.NotContains(t, failed, "config overrides")
+}
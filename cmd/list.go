@@ -9,7 +9,14 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
+	"sbs/pkg/app"
 	"sbs/pkg/config"
+	"sbs/pkg/diffstat"
+	"sbs/pkg/expiry"
+	"sbs/pkg/inbox"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/messages"
+	"sbs/pkg/status"
 	"sbs/pkg/tui"
 )
 
@@ -18,61 +25,216 @@ var listCmd = &cobra.Command{
 	Short: "List all active work sessions in plain text format",
 	Long: `Display a plain text list of all active work sessions.
 Shows session details in a formatted table for easy parsing and scripting.
-Use the bare 'sbs' command to launch the interactive TUI instead.`,
-	RunE: runList,
+Use the bare 'sbs' command to launch the interactive TUI instead.
+
+Use --waiting to show only sessions whose agent is waiting for input, as
+reported by the Claude Code hook.
+
+Use --wide to add a CHANGES column with the lines added and deleted on each
+session branch against the repository's base branch (main or master).
+
+Use --group to show only sessions of the repositories in one of the
+repository_groups of the global config.`,
+	RunE:        runList,
+	Annotations: map[string]string{skipToolValidation: "true"},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolP("plain", "p", false, "Show plain text output (default behavior, kept for backward compatibility)")
+	listCmd.Flags().Bool("waiting", false, "Show only sessions waiting for input")
+	listCmd.Flags().Bool("wide", false, "Show the +adds/-dels of each session branch against its base branch")
+	listCmd.Flags().String("group", "", "Show only sessions of the repositories in this repository group")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
 	plain, _ := cmd.Flags().GetBool("plain")
+	waiting, _ := cmd.Flags().GetBool("waiting")
+	wide, _ := cmd.Flags().GetBool("wide")
+	group, _ := cmd.Flags().GetString("group")
+	if waiting {
+		return runWaitingList(wide, group)
+	}
 
 	// Default behavior is now plain text output
 	// The --plain flag is kept for backward compatibility but is redundant
 	if !plain {
 		// Always show plain text output (--plain flag is now redundant but kept for compatibility)
-		return runPlainList()
+		return runPlainList(wide, group)
 	}
 
 	// Still support --plain explicitly for backward compatibility
-	return runPlainList()
+	return runPlainList(wide, group)
 }
 
-func runPlainList() error {
+func runPlainList(wide bool, group string) error {
 	// Load sessions
-	sessions, err := config.LoadAllRepositorySessions()
+	endLoadSessions := app.Track("load sessions")
+	sessions, err := loadListSessions(group)
+	endLoadSessions()
 	if err != nil {
-		return fmt.Errorf("failed to load sessions: %w", err)
+		return err
 	}
 
 	if len(sessions) == 0 {
-		fmt.Println("No active work sessions found.")
+		if group != "" {
+			fmt.Printf("No active sessions in repository group %s.\n", group)
+			return nil
+		}
+		fmt.Println(messages.Render(messages.NoSessions, nil))
 		return nil
 	}
+	config.MarkMissingRepositories(sessions)
+	collectInbox(sessions)
 
 	// Determine if we should use global view (if sessions from multiple repos)
 	useGlobalView := shouldUseGlobalView(sessions)
 
 	// Print summary line
 	printSummaryLine(sessions, useGlobalView)
+	printCurrentSessionLine(sessions)
 	fmt.Println() // Empty line after summary
 
+	var changes map[string]diffstat.Stat
+	if wide {
+		changes = listDiffstats(sessions)
+	}
+	expiries := listExpiries(sessions)
+	remotes := listRemotes(sessions)
+
 	// Get terminal width for column calculations
+	defer app.Track("render list")()
 	terminalWidth := getTerminalWidth()
 
 	// Print header and sessions using new aesthetic format
 	if useGlobalView {
-		printGlobalViewSessions(sessions, terminalWidth)
+		printGlobalViewSessions(sessions, terminalWidth, listSourceBadges(), changes, expiries, remotes)
 	} else {
-		printRepositoryViewSessions(sessions, terminalWidth)
+		printRepositoryViewSessions(sessions, terminalWidth, listSourceBadges(), changes, expiries, remotes)
 	}
 
 	return nil
 }
 
+// loadListSessions loads every repository's sessions, or only those of the
+// repositories in group when it is set
+func loadListSessions(group string) ([]config.SessionMetadata, error) {
+	var members []string
+	if group != "" {
+		var err error
+		if members, err = appServices().Config().RepositoryGroup(group); err != nil {
+			return nil, err
+		}
+	}
+	sessions, err := config.LoadAllRepositorySessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	if group != "" {
+		sessions = config.FilterRepositoryGroup(sessions, members)
+	}
+	return sessions, nil
+}
+
+// collectInbox folds status updates pushed into the sessions' worktree
+// inboxes into their remote status
+func collectInbox(sessions []config.SessionMetadata) {
+	if _, err := inbox.Collect(sessions); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// listDiffstats returns the branch diffstats shown by --wide, computing only
+// those missing from or outdated in the diffstat cache
+func listDiffstats(sessions []config.SessionMetadata) map[string]diffstat.Stat {
+	defer app.Track("diffstats")()
+	cache := appServices().DiffstatCache()
+	if cache == nil {
+		return map[string]diffstat.Stat{}
+	}
+	stats, _ := cache.Refresh(sessions, config.Now())
+	return stats
+}
+
+// listExpiries returns the EXPIRES column of sessions close to expiring
+// under clean_after_days, or nil when no policy is set and the column is
+// hidden. With expiry_notify, sessions newly flagged are also announced.
+func listExpiries(sessions []config.SessionMetadata) map[string]string {
+	policy := expiry.FromConfig(appServices().Config())
+	now := config.Now()
+	if notifier := appServices().ExpiryNotifier(); notifier != nil {
+		if _, err := notifier.Notify(sessions, policy, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return policy.Labels(sessions, now)
+}
+
+// runWaitingList lists the sessions whose agent is waiting for input, with
+// what it is waiting for when the hook reported it
+func runWaitingList(wide bool, group string) error {
+	sessions, err := loadListSessions(group)
+	if err != nil {
+		return err
+	}
+
+	waiting, messages := filterWaitingSessions(sessions, appServices().StatusDetector())
+	if len(waiting) == 0 {
+		fmt.Println("No sessions are waiting for input.")
+		return nil
+	}
+
+	var changes map[string]diffstat.Stat
+	if wide {
+		changes = listDiffstats(waiting)
+	}
+
+	expiries := listExpiries(waiting)
+	remotes := listRemotes(waiting)
+
+	useGlobalView := shouldUseGlobalView(waiting)
+	terminalWidth := getTerminalWidth()
+	if useGlobalView {
+		printGlobalViewSessions(waiting, terminalWidth, listSourceBadges(), changes, expiries, remotes)
+	} else {
+		printRepositoryViewSessions(waiting, terminalWidth, listSourceBadges(), changes, expiries, remotes)
+	}
+
+	if len(messages) > 0 {
+		fmt.Println()
+	}
+	for _, session := range waiting {
+		if message := messages[session.NamespacedID]; message != "" {
+			fmt.Printf("%s: %s\n", session.NamespacedID, message)
+		}
+	}
+	return nil
+}
+
+// sessionStatusDetector detects a session's live status
+type sessionStatusDetector interface {
+	DetectSessionStatus(session config.SessionMetadata) status.SessionStatus
+}
+
+// filterWaitingSessions returns the sessions waiting for input, with their
+// status shown as "waiting", and the messages reported for them by ID
+func filterWaitingSessions(sessions []config.SessionMetadata, detector sessionStatusDetector) ([]config.SessionMetadata, map[string]string) {
+	var waiting []config.SessionMetadata
+	messages := make(map[string]string)
+	for _, session := range sessions {
+		sessionStatus := detector.DetectSessionStatus(session)
+		if sessionStatus.Status != "waiting" {
+			continue
+		}
+		session.Status = "waiting"
+		waiting = append(waiting, session)
+		if sessionStatus.Message != "" {
+			messages[session.NamespacedID] = sessionStatus.Message
+		}
+	}
+	return waiting, messages
+}
+
 func printSummaryLine(sessions []config.SessionMetadata, useGlobalView bool) {
 	count := len(sessions)
 	sessionWord := "session"
@@ -99,38 +261,160 @@ func printSummaryLine(sessions []config.SessionMetadata, useGlobalView bool) {
 	}
 }
 
-func printRepositoryViewSessions(sessions []config.SessionMetadata, terminalWidth int) {
+// printCurrentSessionLine notes which session owns the working directory when
+// list is run from inside a session worktree
+func printCurrentSessionLine(sessions []config.SessionMetadata) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	matches := config.FindSessionsByWorktreePath(sessions, cwd)
+	if len(matches) != 1 {
+		return
+	}
+	session := matches[0]
+
+	fmt.Printf("Current session: %s (repository %s)\n", sessionWorkItemID(*session), session.RepositoryRoot)
+}
+
+// shortIDWidth fits short session indexes up to %999
+const shortIDWidth = 4
+
+// changesWidth is the width of the --wide CHANGES column
+const changesWidth = 13
+
+// changesHeader returns the CHANGES column header, or "" without --wide
+func changesHeader(changes map[string]diffstat.Stat) string {
+	if changes == nil {
+		return ""
+	}
+	return " " + underlineText(padString("CHANGES", changesWidth))
+}
+
+// changesCell returns a session's CHANGES cell, or "" without --wide.
+// Sessions whose branch couldn't be compared show "-".
+func changesCell(changes map[string]diffstat.Stat, session config.SessionMetadata) string {
+	if changes == nil {
+		return ""
+	}
+	stat, ok := changes[session.NamespacedID]
+	if !ok {
+		return " " + padString("-", changesWidth)
+	}
+	return " " + padString(stat.String(), changesWidth)
+}
+
+// expiresWidth is the width of the EXPIRES column
+const expiresWidth = 9
+
+// expiresHeader returns the EXPIRES column header, or "" without an expiry
+// policy
+func expiresHeader(expiries map[string]string) string {
+	if expiries == nil {
+		return ""
+	}
+	return " " + underlineText(padString("EXPIRES", expiresWidth))
+}
+
+// expiresCell returns a session's EXPIRES cell, blank unless it is close to
+// expiring, or "" without an expiry policy
+func expiresCell(expiries map[string]string, session config.SessionMetadata) string {
+	if expiries == nil {
+		return ""
+	}
+	return " " + padString(expiries[session.NamespacedID], expiresWidth)
+}
+
+// remoteWidth is the width of the REMOTE column
+const remoteWidth = 14
+
+// listRemotes returns the REMOTE column: the pull request check status
+// recorded by sbs pr or the status pushed into the worktree's inbox, or nil
+// when no session has either and the column is hidden
+func listRemotes(sessions []config.SessionMetadata) map[string]string {
+	var remotes map[string]string
+	for _, session := range sessions {
+		if session.PullRequestURL == "" && session.RemoteStatus == "" {
+			continue
+		}
+		if remotes == nil {
+			remotes = map[string]string{}
+		}
+		remotes[session.NamespacedID] = session.RemoteStatus
+	}
+	return remotes
+}
+
+// remoteHeader returns the REMOTE column header, or "" when it is hidden
+func remoteHeader(remotes map[string]string) string {
+	if remotes == nil {
+		return ""
+	}
+	return " " + underlineText(padString("REMOTE", remoteWidth))
+}
+
+// remoteCell returns a session's REMOTE cell, blank without a pull request,
+// or "" when the column is hidden
+func remoteCell(remotes map[string]string, session config.SessionMetadata) string {
+	if remotes == nil {
+		return ""
+	}
+	return " " + padString(remotes[session.NamespacedID], remoteWidth)
+}
+
+// listTableWidth is the width left for the standard columns once the CHANGES,
+// EXPIRES and REMOTE columns are shown
+func listTableWidth(terminalWidth int, changes map[string]diffstat.Stat, expiries, remotes map[string]string) int {
+	if changes != nil {
+		terminalWidth -= changesWidth + 1
+	}
+	if expiries != nil {
+		terminalWidth -= expiresWidth + 1
+	}
+	if remotes != nil {
+		terminalWidth -= remoteWidth + 1
+	}
+	return terminalWidth
+}
+
+func printRepositoryViewSessions(sessions []config.SessionMetadata, terminalWidth int, badges tui.SourceBadges, changes map[string]diffstat.Stat, expiries, remotes map[string]string) {
 	// Calculate column widths with new aesthetic approach
-	widths := calculateAestheticRepositoryWidths(terminalWidth)
+	widths := calculateAestheticRepositoryWidths(listTableWidth(terminalWidth, changes, expiries, remotes))
 
 	// Create properly sized and underlined header columns
+	shortHeader := underlineText(padString("#", shortIDWidth))
 	idHeader := underlineText(padString("ID", widths.Issue))
 	titleHeader := underlineText(padString("TITLE", widths.Title))
 	statusHeader := underlineText(padString("STATUS", widths.Status))
 	updatedHeader := underlineText(padString("UPDATED", widths.LastActivity))
 
 	// Print header
-	fmt.Printf("%s %s %s %s\n", idHeader, titleHeader, statusHeader, updatedHeader)
+	fmt.Printf("%s %s %s %s %s%s%s%s\n", shortHeader, idHeader, titleHeader, statusHeader, updatedHeader, changesHeader(changes), expiresHeader(expiries), remoteHeader(remotes))
 
 	// Print sessions
-	for _, session := range sessions {
+	for i, session := range sessions {
 		lastActivity := formatRelativeTime(session.LastActivity)
 		// Pad first, then colorize to avoid ANSI code alignment issues
-		paddedID := fmt.Sprintf("%-*s", widths.Issue, session.NamespacedID)
-		coloredID := colorizeID(paddedID)
-		fmt.Printf("%s %-*s %-*s %-*s\n",
+		coloredID := formatListID(session, widths.Issue, badges)
+		fmt.Printf("%-*s %s %-*s %-*s %-*s%s%s%s\n",
+			shortIDWidth, shortID(i),
 			coloredID,
 			widths.Title, tui.TruncateString(session.IssueTitle, widths.Title),
 			widths.Status, session.Status,
-			widths.LastActivity, lastActivity)
+			widths.LastActivity, lastActivity,
+			changesCell(changes, session),
+			expiresCell(expiries, session),
+			remoteCell(remotes, session))
 	}
 }
 
-func printGlobalViewSessions(sessions []config.SessionMetadata, terminalWidth int) {
+func printGlobalViewSessions(sessions []config.SessionMetadata, terminalWidth int, badges tui.SourceBadges, changes map[string]diffstat.Stat, expiries, remotes map[string]string) {
 	// Calculate column widths with new aesthetic approach
-	widths := calculateAestheticGlobalWidths(terminalWidth)
+	widths := calculateAestheticGlobalWidths(listTableWidth(terminalWidth, changes, expiries, remotes))
 
 	// Create properly sized and underlined header columns
+	shortHeader := underlineText(padString("#", shortIDWidth))
 	idHeader := underlineText(padString("ID", widths.Issue))
 	titleHeader := underlineText(padString("TITLE", widths.Title))
 	repoHeader := underlineText(padString("REPOSITORY", widths.Repository))
@@ -138,29 +422,32 @@ func printGlobalViewSessions(sessions []config.SessionMetadata, terminalWidth in
 	updatedHeader := underlineText(padString("UPDATED", widths.LastActivity))
 
 	// Print header
-	fmt.Printf("%s %s %s %s %s\n", idHeader, titleHeader, repoHeader, statusHeader, updatedHeader)
+	fmt.Printf("%s %s %s %s %s %s%s%s%s\n", shortHeader, idHeader, titleHeader, repoHeader, statusHeader, updatedHeader, changesHeader(changes), expiresHeader(expiries), remoteHeader(remotes))
 
 	// Print sessions
-	for _, session := range sessions {
+	for i, session := range sessions {
 		lastActivity := formatRelativeTime(session.LastActivity)
 		// Pad first, then colorize to avoid ANSI code alignment issues
-		paddedID := fmt.Sprintf("%-*s", widths.Issue, session.NamespacedID)
-		coloredID := colorizeID(paddedID)
-		fmt.Printf("%s %-*s %-*s %-*s %-*s\n",
+		coloredID := formatListID(session, widths.Issue, badges)
+		fmt.Printf("%-*s %s %-*s %-*s %-*s %-*s%s%s%s\n",
+			shortIDWidth, shortID(i),
 			coloredID,
 			widths.Title, tui.TruncateString(session.IssueTitle, widths.Title),
 			widths.Repository, tui.TruncateString(session.RepositoryName, widths.Repository),
 			widths.Status, session.Status,
-			widths.LastActivity, lastActivity)
+			widths.LastActivity, lastActivity,
+			changesCell(changes, session),
+			expiresCell(expiries, session),
+			remoteCell(remotes, session))
 	}
 }
 
 func calculateAestheticRepositoryWidths(terminalWidth int) tui.ColumnWidths {
 	// Reserve space for "UPDATED" column (estimate ~20 chars for relative time like "about 2 weeks ago")
 	updatedWidth := 20
-	// Account for spaces between columns (3 spaces between 4 columns)
-	spacingWidth := 3
-	availableWidth := terminalWidth - updatedWidth - spacingWidth
+	// Account for the short index column and spaces between columns (4 spaces between 5 columns)
+	spacingWidth := 4
+	availableWidth := terminalWidth - shortIDWidth - updatedWidth - spacingWidth
 
 	// Allocate remaining width
 	const (
@@ -189,9 +476,9 @@ func calculateAestheticRepositoryWidths(terminalWidth int) tui.ColumnWidths {
 func calculateAestheticGlobalWidths(terminalWidth int) tui.ColumnWidths {
 	// Reserve space for "UPDATED" column
 	updatedWidth := 20
-	// Account for spaces between columns (4 spaces between 5 columns)
-	spacingWidth := 4
-	availableWidth := terminalWidth - updatedWidth - spacingWidth
+	// Account for the short index column and spaces between columns (5 spaces between 6 columns)
+	spacingWidth := 5
+	availableWidth := terminalWidth - shortIDWidth - updatedWidth - spacingWidth
 
 	// Allocate remaining width
 	const (
@@ -239,6 +526,25 @@ func colorizeID(id string) string {
 	return idStyle.Render(id)
 }
 
+// listSourceBadges returns the source badge renderer for the loaded config
+func listSourceBadges() tui.SourceBadges {
+	return tui.NewSourceBadges(appServices().Config())
+}
+
+// formatListID renders the ID column: the source badge and the ID, padded
+// and truncated to width before coloring so ANSI codes don't skew alignment
+func formatListID(session config.SessionMetadata, width int, badges tui.SourceBadges) string {
+	source := inputsource.SessionSource(session)
+	label := badges.Label(source, session.NamespacedID)
+	padded := fmt.Sprintf("%-*s", width, tui.TruncateString(label, width))
+
+	prefix := strings.TrimSuffix(label, session.NamespacedID) // e.g. "GH ", or "" without badges
+	if prefix == "" || !strings.HasPrefix(padded, prefix) {
+		return colorizeID(padded)
+	}
+	return badges.Colorize(prefix, source) + colorizeID(padded[len(prefix):])
+}
+
 func padString(text string, width int) string {
 	// Pad string to exact width, left-aligned
 	if len(text) >= width {
@@ -262,7 +568,7 @@ func formatRelativeTime(timeStr string) string {
 		return timeStr
 	}
 
-	now := time.Now()
+	now := config.Now()
 	duration := now.Sub(t)
 
 	// Format like GitHub: "about X ago" or "X ago"
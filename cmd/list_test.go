@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/app"
+	"sbs/pkg/config"
+	"sbs/pkg/diffstat"
+	"sbs/pkg/status"
+	"sbs/pkg/tui"
 )
 
 func TestListCommand_DefaultPlainOutput(t *testing.T) {
@@ -117,3 +125,151 @@ func TestListCommand_HelpText_Updated(t *testing.T) {
 		assert.Contains(t, listCmd.Long, "Display")
 	})
 }
+
+type fakeStatusDetector map[string]status.SessionStatus
+
+func (f fakeStatusDetector) DetectSessionStatus(session config.SessionMetadata) status.SessionStatus {
+	return f[session.NamespacedID]
+}
+
+func TestFilterWaitingSessions(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", Status: "active"},
+		{NamespacedID: "github:2", Status: "active"},
+		{NamespacedID: "github:3", Status: "active"},
+	}
+	detector := fakeStatusDetector{
+		"github:1": {Status: "active"},
+		"github:2": {Status: "waiting", Message: "Claude needs your permission to use Bash"},
+		"github:3": {Status: "waiting"},
+	}
+
+	waiting, messages := filterWaitingSessions(sessions, detector)
+
+	require.Len(t, waiting, 2)
+	assert.Equal(t, "github:2", waiting[0].NamespacedID)
+	assert.Equal(t, "waiting", waiting[0].Status)
+	assert.Equal(t, map[string]string{"github:2": "Claude needs your permission to use Bash"}, messages)
+}
+
+func TestFormatListID(t *testing.T) {
+	session := config.SessionMetadata{NamespacedID: "github:123", SourceType: "github"}
+
+	id := ansi.Strip(formatListID(session, 20, tui.NewSourceBadges(nil)))
+	assert.Equal(t, "GH github:123       ", id)
+
+	id = ansi.Strip(formatListID(session, 20, tui.NewSourceBadges(&config.Config{SourceBadgeStyle: "none"})))
+	assert.Equal(t, "github:123          ", id)
+
+	long := config.SessionMetadata{NamespacedID: "jira:PLATFORM-123456", SourceType: "jira"}
+	id = ansi.Strip(formatListID(long, 16, tui.NewSourceBadges(nil)))
+	assert.Equal(t, "JR jira:PLATF...", id, "the badged ID is truncated to the column")
+}
+
+func TestListCommand_WideFlag(t *testing.T) {
+	flag := listCmd.Flags().Lookup("wide")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+}
+
+func TestPrintRepositoryViewSessions_Changes(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", IssueTitle: "Add diffstat", Status: "active"},
+		{NamespacedID: "github:2", IssueTitle: "Deleted branch", Status: "stale"},
+	}
+	badges := tui.NewSourceBadges(&config.Config{SourceBadgeStyle: "none"})
+
+	narrow := ansi.Strip(captureStdout(t, func() {
+		printRepositoryViewSessions(sessions, 120, badges, nil, nil, nil)
+	}))
+	assert.NotContains(t, narrow, "CHANGES", "the column is only shown with --wide")
+
+	changes := map[string]diffstat.Stat{"github:1": {Additions: 120, Deletions: 30}}
+	wide := ansi.Strip(captureStdout(t, func() {
+		printRepositoryViewSessions(sessions, 120, badges, changes, nil, nil)
+	}))
+	lines := strings.Split(strings.TrimRight(wide, "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "CHANGES")
+	assert.Contains(t, lines[1], "+120/-30")
+	assert.Equal(t, "-", strings.Fields(lines[2])[len(strings.Fields(lines[2]))-1], "sessions without a stat show a placeholder")
+	assert.Equal(t, len(lines[0]), len(lines[1]), "the header and rows line up")
+}
+
+func TestPrintRepositoryViewSessions_Expires(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", IssueTitle: "Old work", Status: "active"},
+		{NamespacedID: "github:2", IssueTitle: "Fresh work", Status: "active"},
+	}
+	badges := tui.NewSourceBadges(&config.Config{SourceBadgeStyle: "none"})
+
+	plain := ansi.Strip(captureStdout(t, func() {
+		printRepositoryViewSessions(sessions, 120, badges, nil, nil, nil)
+	}))
+	assert.NotContains(t, plain, "EXPIRES", "the column is only shown with clean_after_days")
+
+	expiries := map[string]string{"github:1": "in 2d"}
+	output := ansi.Strip(captureStdout(t, func() {
+		printRepositoryViewSessions(sessions, 120, badges, nil, expiries, nil)
+	}))
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "EXPIRES")
+	assert.True(t, strings.HasSuffix(strings.TrimRight(lines[1], " "), "in 2d"))
+	assert.NotContains(t, lines[2], "in ", "sessions far from expiring are left blank")
+	assert.Equal(t, len(lines[0]), len(lines[1]), "the header and rows line up")
+}
+
+func TestPrintRepositoryViewSessions_Remote(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", IssueTitle: "Has a PR", Status: "active",
+			PullRequestURL: "https://github.com/o/r/pull/7", RemoteStatus: "checks pending"},
+		{NamespacedID: "github:2", IssueTitle: "No PR yet", Status: "active"},
+	}
+	badges := tui.NewSourceBadges(&config.Config{SourceBadgeStyle: "none"})
+
+	assert.Nil(t, listRemotes(sessions[1:]), "the column is only shown once a session has a pull request")
+	assert.Equal(t, map[string]string{"github:3": "ci passed"},
+		listRemotes([]config.SessionMetadata{{NamespacedID: "github:3", RemoteStatus: "ci passed"}}), "or a status pushed into its inbox")
+
+	remotes := listRemotes(sessions)
+	output := ansi.Strip(captureStdout(t, func() {
+		printRepositoryViewSessions(sessions, 120, badges, nil, nil, remotes)
+	}))
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "REMOTE")
+	assert.True(t, strings.HasSuffix(strings.TrimRight(lines[1], " "), "checks pending"))
+	assert.NotContains(t, lines[2], "checks")
+	assert.Equal(t, len(lines[0]), len(lines[1]), "the header and rows line up")
+}
+
+func TestLoadListSessions_Group(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	original := services
+	defer func() { services = original }()
+	services = app.NewContainer(&config.Config{RepositoryGroups: map[string][]string{"frontend": {"web"}}})
+
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{
+		{NamespacedID: "github:1", RepositoryName: "web", RepositoryRoot: "/src/web"},
+		{NamespacedID: "github:2", RepositoryName: "api", RepositoryRoot: "/src/api"},
+	}))
+
+	sessions, err := loadListSessions("frontend")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "github:1", sessions[0].NamespacedID)
+
+	sessions, err = loadListSessions("")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	_, err = loadListSessions("mobile")
+	assert.ErrorContains(t, err, `unknown repository group "mobile" (configured: frontend)`)
+
+	output := captureStdout(t, func() {
+		services = app.NewContainer(&config.Config{RepositoryGroups: map[string][]string{"mobile": {"ios"}}})
+		require.NoError(t, runPlainList(false, "mobile"))
+	})
+	assert.Equal(t, "No active sessions in repository group mobile.\n", output)
+}
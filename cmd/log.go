@@ -1,37 +1,66 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/logarchive"
+	"sbs/pkg/loghook"
 	"sbs/pkg/tui"
 )
 
 var logCmd = &cobra.Command{
-	Use:   "log <work-item-id>",
+	Use:   "log [work-item-id]",
 	Short: "Execute loghook script for a work session",
 	Long: `Display output for the specified work item session.
 
 Work item ID formats:
   sbs log 123         # Primary work type
   sbs log test:my-test  # Test work type
+  sbs log               # Session owning the current worktree
 
 If a .sbs/loghook script exists, it is executed from the session's worktree directory 
 with a 10-second timeout. If no loghook script is found, the command falls back to 
-capturing the current content of the tmux session's first pane.`,
-	Args: cobra.ExactArgs(1),
+capturing the current content of the tmux session's first pane.
+
+The script receives the mode ("snapshot", or "follow" with --follow) as its first
+argument followed by any configured loghook_args, and the environment variables
+SBS_LOGHOOK_MODE, SBS_WORK_ITEM, SBS_BRANCH, SBS_TMUX_SESSION, SBS_WORKTREE and
+SBS_SANDBOX. In follow mode the script streams output until interrupted.
+
+With loghook_archive set, each output is also archived: snapshots that
+differ from the last one archived, and whole follow-mode streams. --history
+lists a session's archived outputs, newest first, and --show N prints the
+Nth of them, so output from before a failure can still be read.
+
+Examples:
+  sbs log 123 --history   # List archived outputs
+  sbs log 123 --show 3    # Print the third newest`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runLog,
 }
 
 func init() {
 	rootCmd.AddCommand(logCmd)
+	logCmd.Flags().BoolP("follow", "f", false, "Run the loghook in follow mode and stream its output")
+	logCmd.Flags().Bool("history", false, "List the session's archived loghook outputs, newest first")
+	logCmd.Flags().Int("show", 0, "Print the Nth newest archived loghook output")
 }
 
 func runLog(cmd *cobra.Command, args []string) error {
-	workItemID := args[0]
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
 
 	// Load sessions
 	sessions, err := config.LoadSessions()
@@ -48,11 +77,35 @@ func runLog(cmd *cobra.Command, args []string) error {
 		}
 	}
 	if session == nil {
-		return fmt.Errorf("no session found for work item %s", workItemID)
+		return sessionNotFound(workItemID)
+	}
+
+	follow, _ := cmd.Flags().GetBool("follow")
+	history, _ := cmd.Flags().GetBool("history")
+	show, _ := cmd.Flags().GetInt("show")
+	archiver := appServices().LogArchive()
+	if history || cmd.Flags().Changed("show") {
+		if follow || (history && cmd.Flags().Changed("show")) {
+			return exitcode.Errorf(exitcode.Validation, "--follow, --history and --show can't be combined")
+		}
+		if archiver == nil {
+			return exitcode.Errorf(exitcode.Validation, "loghook output isn't archived; set loghook_archive in the config to keep it")
+		}
+		if history {
+			return listLogArchive(os.Stdout, archiver.Store(), session.NamespacedID)
+		}
+		return showLogArchive(os.Stdout, archiver.Store(), session.NamespacedID, show)
+	}
+
+	if follow {
+		return followLoghook(*session, archiver)
 	}
 
 	// Execute the loghook script
 	output, err := tui.ExecuteLoghookScript(*session)
+	if archiveErr := archiver.Snapshot(session.NamespacedID, loghook.DefaultSourceName, output, config.Now()); archiveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", archiveErr)
+	}
 	if err != nil {
 		// Print any output we got even if there was an error
 		if output != "" {
@@ -66,3 +119,140 @@ func runLog(cmd *cobra.Command, args []string) error {
 	fmt.Print(output)
 	return nil
 }
+
+// followLoghook streams loghook output in follow mode until interrupted,
+// archiving the stream when archiver is set
+func followLoghook(session config.SessionMetadata, archiver *logarchive.Archiver) error {
+	scriptPath := loghook.ScriptPath(session.WorktreePath)
+	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
+		return fmt.Errorf("follow mode requires a loghook script at %s", scriptPath)
+	}
+	if err := loghook.ValidateScript(scriptPath); err != nil {
+		return fmt.Errorf("security validation failed for %s: %w", scriptPath, err)
+	}
+
+	invocation, err := loghook.NewInvocation(session, scriptPath, loghook.ModeFollow, loghook.ResolveArgs(cfg, session.RepositoryRoot, session.WorktreePath))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	command := invocation.Command(ctx)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if archiver != nil {
+		archive, err := archiver.Follow(session.NamespacedID, loghook.DefaultSourceName, config.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			defer func() {
+				if err := archive.Close(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}()
+			command.Stdout = io.MultiWriter(os.Stdout, archive)
+		}
+	}
+
+	if err := command.Run(); err != nil {
+		// Interrupting the stream is the normal way to end follow mode
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+		return fmt.Errorf("loghook script %s failed: %w", scriptPath, err)
+	}
+	return nil
+}
+
+// archivedEntries lists a session's archived outputs, explaining stores
+// that can't be browsed
+func archivedEntries(store logarchive.Store, workItemID string) ([]logarchive.Entry, error) {
+	entries, err := store.List(workItemID)
+	if errors.Is(err, logarchive.ErrNotBrowsable) {
+		return nil, exitcode.Errorf(exitcode.Validation, "loghook_archive %q only sends outputs to loghook_archive_command; use \"local\" to browse them", "command")
+	}
+	return entries, err
+}
+
+// listLogArchive prints a session's archived outputs as numbered rows
+func listLogArchive(w io.Writer, store logarchive.Store, workItemID string) error {
+	entries, err := archivedEntries(store, workItemID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "No archived loghook output for %s.\n", workItemID)
+		return nil
+	}
+	fmt.Fprint(w, formatLogArchive(entries))
+	return nil
+}
+
+// formatLogArchive renders archived entries as aligned columns
+func formatLogArchive(entries []logarchive.Entry) string {
+	rows := [][]string{{"#", "ARCHIVED", "SOURCE", "MODE", "SIZE"}}
+	for i, entry := range entries {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Source,
+			entry.Mode,
+			formatArchiveSize(entry.Size),
+		})
+	}
+
+	widths := make([]int, len(rows[0])-1)
+	for _, row := range rows {
+		for i := range widths {
+			widths[i] = max(widths[i], len(row[i]))
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for i, width := range widths {
+			fmt.Fprintf(&b, "%-*s  ", width, row[i])
+		}
+		b.WriteString(row[len(row)-1])
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatArchiveSize renders a byte count using binary units
+func formatArchiveSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// showLogArchive prints the nth newest archived output of a session
+func showLogArchive(w io.Writer, store logarchive.Store, workItemID string, n int) error {
+	entries, err := archivedEntries(store, workItemID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return exitcode.Errorf(exitcode.Validation, "no archived loghook output for %s", workItemID)
+	}
+	if n < 1 || n > len(entries) {
+		return exitcode.Errorf(exitcode.Validation, "--show must be between 1 and %d, the number of archived outputs of %s", len(entries), workItemID)
+	}
+	r, err := store.Open(entries[n-1])
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("failed to read loghook archive entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/logarchive"
+)
+
+func TestLogArchiveHistory(t *testing.T) {
+	store := logarchive.NewLocalStore(t.TempDir(), logarchive.DefaultKeep, logarchive.DefaultMaxBytes)
+	archiver := logarchive.NewArchiver(store)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	require.NoError(t, archiver.Snapshot("github:1", "default", "building\n", at))
+	require.NoError(t, archiver.Snapshot("github:1", "tests", "FAIL TestLogin\n", at.Add(time.Minute)))
+
+	var out bytes.Buffer
+	require.NoError(t, listLogArchive(&out, store, "github:1"))
+	assert.Equal(t, "#  ARCHIVED             SOURCE   MODE      SIZE\n"+
+		"1  2026-03-01 12:01:00  tests    snapshot  15 B\n"+
+		"2  2026-03-01 12:00:00  default  snapshot  9 B\n", out.String())
+
+	out.Reset()
+	require.NoError(t, showLogArchive(&out, store, "github:1", 1))
+	assert.Equal(t, "FAIL TestLogin\n", out.String())
+	assert.ErrorContains(t, showLogArchive(&out, store, "github:1", 3), "--show must be between 1 and 2")
+	assert.ErrorContains(t, showLogArchive(&out, store, "github:2", 1), "no archived loghook output for github:2")
+
+	out.Reset()
+	require.NoError(t, listLogArchive(&out, store, "github:2"))
+	assert.Equal(t, "No archived loghook output for github:2.\n", out.String())
+
+	err := listLogArchive(&out, logarchive.NewCommandStore("cat"), "github:1")
+	assert.ErrorContains(t, err, "use \"local\" to browse them")
+	assert.Equal(t, "1.5 KiB", formatArchiveSize(1536))
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/naming"
+)
+
+var migrateNamesCmd = &cobra.Command{
+	Use:   "migrate-names",
+	Short: "Rename existing sessions to the configured name scope",
+	Long: `Rename the tmux sessions and sandboxes of existing sessions so they match
+the names sbs would give them today, for example after setting name_scope
+on a shared host:
+
+  {"name_scope": "user"}     # sbs-<username>-<repo>-<source>-<id>
+  {"name_scope": "team-a"}   # sbs-team-a-<repo>-<source>-<id>
+
+Until migrated, sessions keep working under the names they were created
+with. Running tmux sessions are renamed in place and stay attached. A
+sandbox is renamed only if the sandbox CLI supports it; otherwise the
+session keeps its old sandbox name. Resources that don't exist yet are
+simply recorded under the new name.
+
+Use --dry-run to see the renames without making them.`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateNames,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateNamesCmd)
+	migrateNamesCmd.Flags().Bool("dry-run", false, "Show the renames without making them")
+}
+
+// sessionRenamer renames tmux sessions
+type sessionRenamer interface {
+	SessionExists(name string) (bool, error)
+	RenameSession(oldName, newName string) error
+}
+
+// sandboxRenamer renames sandboxes
+type sandboxRenamer interface {
+	SandboxExists(name string) (bool, error)
+	RenameSandbox(oldName, newName string) error
+}
+
+func runMigrateNames(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	services := appServices()
+	var changed int
+	var err error
+	if dryRun {
+		sessions, loadErr := config.LoadSessions()
+		if loadErr != nil {
+			return fmt.Errorf("failed to load sessions: %w", loadErr)
+		}
+		changed, err = migrateSessionNames(sessions, services.TmuxManager(), services.SandboxManager(), true)
+	} else {
+		changed, err = migrateStoredSessionNames(services.TmuxManager(), services.SandboxManager())
+	}
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		fmt.Println("All sessions already use the current names")
+		return nil
+	}
+	if !dryRun {
+		fmt.Printf("Migrated %d session(s)\n", changed)
+	}
+	return nil
+}
+
+// migrateStoredSessionNames renames the resources of the stored sessions
+// under the sessions lock, so another sbs process saving meanwhile can't
+// put the old names back
+func migrateStoredSessionNames(tmuxManager sessionRenamer, sandboxManager sandboxRenamer) (int, error) {
+	var changed int
+	err := config.UpdateSessions(func(sessions []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		var err error
+		changed, err = migrateSessionNames(sessions, tmuxManager, sandboxManager, false)
+		return sessions, err
+	})
+	return changed, err
+}
+
+// scopedNames returns the tmux session and sandbox names sbs would give a
+// session now; ok is false for sessions without enough metadata to tell
+func scopedNames(session config.SessionMetadata) (tmuxName, sandboxName string, ok bool) {
+	source, id, found := strings.Cut(session.NamespacedID, ":")
+	if !found || session.RepositoryName == "" {
+		return "", "", false
+	}
+	return naming.TmuxSession(session.RepositoryName, source, id),
+		naming.Sandbox(session.RepositoryName, source, id), true
+}
+
+// migrateSessionNames renames each session's resources to the current names
+// and updates sessions in place, returning how many sessions changed. A
+// resource that fails to rename keeps its old name.
+func migrateSessionNames(sessions []config.SessionMetadata, tmuxManager sessionRenamer, sandboxManager sandboxRenamer, dryRun bool) (int, error) {
+	changed := 0
+	for i := range sessions {
+		session := &sessions[i]
+		tmuxName, sandboxName, ok := scopedNames(*session)
+		if !ok {
+			fmt.Printf("skip    %s: no namespaced ID or repository recorded\n", session.TmuxSession)
+			continue
+		}
+
+		sessionChanged := false
+		if session.TmuxSession != "" && session.TmuxSession != tmuxName {
+			renamed, err := migrateTmuxName(tmuxManager, session.TmuxSession, tmuxName, dryRun)
+			if err != nil {
+				return changed, err
+			}
+			if renamed {
+				session.TmuxSession = tmuxName
+				sessionChanged = true
+			}
+		}
+		if session.SandboxName != "" && session.SandboxName != sandboxName {
+			if migrateSandboxName(sandboxManager, session.SandboxName, sandboxName, dryRun) {
+				session.SandboxName = sandboxName
+				sessionChanged = true
+			}
+		}
+		if sessionChanged {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// migrateTmuxName renames a running tmux session and reports whether the
+// session should record the new name
+func migrateTmuxName(tmuxManager sessionRenamer, oldName, newName string, dryRun bool) (bool, error) {
+	running, err := tmuxManager.SessionExists(oldName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check tmux session %s: %w", oldName, err)
+	}
+	switch {
+	case dryRun:
+		fmt.Printf("would rename tmux session %s -> %s\n", oldName, newName)
+	case !running:
+		fmt.Printf("tmux    %s -> %s (not running)\n", oldName, newName)
+	default:
+		if err := tmuxManager.RenameSession(oldName, newName); err != nil {
+			fmt.Printf("keep    %s: %v\n", oldName, err)
+			return false, nil
+		}
+		fmt.Printf("tmux    %s -> %s\n", oldName, newName)
+	}
+	return true, nil
+}
+
+// migrateSandboxName renames an existing sandbox and reports whether the
+// session should record the new name
+func migrateSandboxName(sandboxManager sandboxRenamer, oldName, newName string, dryRun bool) bool {
+	exists, err := sandboxManager.SandboxExists(oldName)
+	if err != nil {
+		fmt.Printf("keep    %s: could not check sandbox: %v\n", oldName, err)
+		return false
+	}
+	switch {
+	case dryRun:
+		fmt.Printf("would rename sandbox %s -> %s\n", oldName, newName)
+	case !exists:
+		fmt.Printf("sandbox %s -> %s (not created yet)\n", oldName, newName)
+	default:
+		if err := sandboxManager.RenameSandbox(oldName, newName); err != nil {
+			fmt.Printf("keep    %s: sandbox could not be renamed (%v)\n", oldName, err)
+			return false
+		}
+		fmt.Printf("sandbox %s -> %s\n", oldName, newName)
+	}
+	return true
+}
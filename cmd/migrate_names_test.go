@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/naming"
+)
+
+type fakeRenamer struct {
+	existing  map[string]bool
+	renameErr error
+	renamed   map[string]string
+}
+
+func newFakeRenamer(existing ...string) *fakeRenamer {
+	f := &fakeRenamer{existing: map[string]bool{}, renamed: map[string]string{}}
+	for _, name := range existing {
+		f.existing[name] = true
+	}
+	return f
+}
+
+func (f *fakeRenamer) exists(name string) (bool, error) { return f.existing[name], nil }
+
+func (f *fakeRenamer) rename(oldName, newName string) error {
+	if f.renameErr != nil {
+		return f.renameErr
+	}
+	f.renamed[oldName] = newName
+	return nil
+}
+
+type fakeTmuxRenamer struct{ *fakeRenamer }
+
+func (f fakeTmuxRenamer) SessionExists(name string) (bool, error) { return f.exists(name) }
+func (f fakeTmuxRenamer) RenameSession(oldName, newName string) error {
+	return f.rename(oldName, newName)
+}
+
+type fakeSandboxRenamer struct{ *fakeRenamer }
+
+func (f fakeSandboxRenamer) SandboxExists(name string) (bool, error) { return f.exists(name) }
+func (f fakeSandboxRenamer) RenameSandbox(oldName, newName string) error {
+	return f.rename(oldName, newName)
+}
+
+func TestMigrateSessionNames(t *testing.T) {
+	naming.SetScope("alice")
+	defer naming.SetScope("")
+
+	newSessions := func() []config.SessionMetadata {
+		return []config.SessionMetadata{
+			{NamespacedID: "github:1", RepositoryName: "repo", TmuxSession: "sbs-repo-github-1", SandboxName: "sbs-repo-github-1"},
+			{NamespacedID: "github:2", RepositoryName: "repo", TmuxSession: "sbs-alice-repo-github-2", SandboxName: "sbs-alice-repo-github-2"},
+			{TmuxSession: "work-issue-repo-3"},
+		}
+	}
+
+	t.Run("renames running resources", func(t *testing.T) {
+		tmuxFake := newFakeRenamer("sbs-repo-github-1")
+		sandboxFake := newFakeRenamer("sbs-repo-github-1")
+		sessions := newSessions()
+
+		var changed int
+		captureStdout(t, func() {
+			var err error
+			changed, err = migrateSessionNames(sessions, fakeTmuxRenamer{tmuxFake}, fakeSandboxRenamer{sandboxFake}, false)
+			require.NoError(t, err)
+		})
+
+		assert.Equal(t, 1, changed)
+		assert.Equal(t, "sbs-alice-repo-github-1", sessions[0].TmuxSession)
+		assert.Equal(t, "sbs-alice-repo-github-1", sessions[0].SandboxName)
+		assert.Equal(t, map[string]string{"sbs-repo-github-1": "sbs-alice-repo-github-1"}, tmuxFake.renamed)
+		assert.Equal(t, map[string]string{"sbs-repo-github-1": "sbs-alice-repo-github-1"}, sandboxFake.renamed)
+	})
+
+	t.Run("keeps sandbox name when rename is unsupported", func(t *testing.T) {
+		sandboxFake := newFakeRenamer("sbs-repo-github-1")
+		sandboxFake.renameErr = errors.New("unknown command rename")
+		sessions := newSessions()
+
+		output := captureStdout(t, func() {
+			_, err := migrateSessionNames(sessions, fakeTmuxRenamer{newFakeRenamer()}, fakeSandboxRenamer{sandboxFake}, false)
+			require.NoError(t, err)
+		})
+
+		assert.Equal(t, "sbs-alice-repo-github-1", sessions[0].TmuxSession, "stopped tmux sessions just record the new name")
+		assert.Equal(t, "sbs-repo-github-1", sessions[0].SandboxName)
+		assert.Contains(t, output, "sandbox could not be renamed")
+	})
+
+	t.Run("dry run renames nothing", func(t *testing.T) {
+		tmuxFake := newFakeRenamer("sbs-repo-github-1")
+		sessions := newSessions()
+
+		output := captureStdout(t, func() {
+			changed, err := migrateSessionNames(sessions, fakeTmuxRenamer{tmuxFake}, fakeSandboxRenamer{newFakeRenamer()}, true)
+			require.NoError(t, err)
+			assert.Equal(t, 1, changed)
+		})
+
+		assert.Empty(t, tmuxFake.renamed)
+		assert.Contains(t, output, "would rename tmux session sbs-repo-github-1 -> sbs-alice-repo-github-1")
+	})
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/git"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/oplock"
+	"sbs/pkg/sessiondoc"
+	"sbs/pkg/tmux"
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <work-item-id> <new-path-or-volume>",
+	Short: "Move a session's worktree to another path or volume",
+	Long: `Move a session's worktree, e.g. off a disk that is filling up or onto faster
+storage. When the destination is an existing directory the worktree moves
+into it under its current name; otherwise the destination is the new
+worktree path.
+
+The worktree is moved with 'git worktree move' (or copied and repaired when
+the destination is on another filesystem) and the session is updated to the
+new path. A running tmux session is stopped first and restarted in the new
+directory unless --no-restart is given. The sandbox mounts the old path, so
+it is deleted (after confirmation unless -y) and recreated by the session
+command.
+
+Examples:
+  sbs move 123 /mnt/fast/worktrees
+  sbs move jira:PROJ-7 ~/scratch/proj-7 --no-restart`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMove,
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+	moveCmd.Flags().Bool("dry-run", false, "Show where the worktree would move without changing anything")
+	moveCmd.Flags().Bool("no-restart", false, "Leave the tmux session stopped after the move")
+	moveCmd.Flags().BoolP("yes", "y", false, "Delete the sandbox without asking for confirmation")
+	addWaitFlags(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noRestart, _ := cmd.Flags().GetBool("no-restart")
+	skipConfirmation, err := confirmationSkipped(cmd, "move")
+	if err != nil {
+		return err
+	}
+
+	workItemID, err := resolveWorkItemID(args[0])
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockWorkItem(cmd, workItemID, oplock.OpMove)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	var session *config.SessionMetadata
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return sessionNotFound(workItemID)
+	}
+	if session.WorktreePath == "" || !fileExists(session.WorktreePath) {
+		return fmt.Errorf("worktree of %s not found at %q; nothing to move", workItemID, session.WorktreePath)
+	}
+
+	oldPath := session.WorktreePath
+	newPath, err := moveDestination(oldPath, args[1])
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("Would move the worktree of %s: %s -> %s\n", workItemID, oldPath, newPath)
+		return nil
+	}
+
+	gitManager, err := git.NewManager(session.RepositoryRoot)
+	if err != nil {
+		return fmt.Errorf("failed to open repository %s: %w", session.RepositoryRoot, err)
+	}
+
+	// Processes running in the worktree would keep the old directory open
+	tmuxManager := appServices().TmuxManager()
+	wasRunning, err := tmuxManager.SessionExists(session.TmuxSession)
+	if err != nil {
+		return fmt.Errorf("failed to check tmux session: %w", err)
+	}
+	if wasRunning {
+		if err := stopTmuxSession(session); err != nil {
+			return err
+		}
+	}
+	if session.SandboxName != "" {
+		if err := stopSandbox(session, workItemID, skipConfirmation); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Moving worktree %s -> %s\n", oldPath, newPath)
+	if err := gitManager.WithContext(appServices().Context()).MoveWorktree(oldPath, newPath); err != nil {
+		if wasRunning {
+			fmt.Printf("The worktree was not moved; run 'sbs start %s' to resume the session.\n", workItemID)
+		}
+		return err
+	}
+
+	session.WorktreePath = newPath
+	status := session.Status
+	if wasRunning && noRestart {
+		status = "stopped"
+	}
+	if err := updateSession(workItemID, func(s *config.SessionMetadata) {
+		s.WorktreePath = newPath
+		s.Status = status
+	}); err != nil {
+		return fmt.Errorf("failed to save sessions: %w", err)
+	}
+	if err := sessiondoc.Update(*session); err != nil {
+		fmt.Printf("Warning: failed to update %s: %v\n", sessiondoc.RelativePath, err)
+	}
+	fmt.Printf("Moved the worktree of %s to %s\n", workItemID, newPath)
+
+	if !wasRunning || noRestart {
+		fmt.Printf("Run 'sbs start %s' to start the session in its new location.\n", workItemID)
+		return nil
+	}
+	return restartMovedSession(tmuxManager, session)
+}
+
+// moveDestination returns the new worktree path for target: inside target
+// under the worktree's name when target is an existing directory, else
+// target itself. The destination must not exist or lie inside the worktree.
+func moveDestination(worktreePath, target string) (string, error) {
+	if strings.HasPrefix(target, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			target = filepath.Join(home, target[2:])
+		}
+	}
+	dest, err := filepath.Abs(target)
+	if err != nil {
+		return "", exitcode.Errorf(exitcode.Validation, "invalid destination %q: %w", target, err)
+	}
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, filepath.Base(worktreePath))
+	}
+
+	rel, err := filepath.Rel(worktreePath, dest)
+	if err == nil && (rel == "." || !strings.HasPrefix(rel, "..")) {
+		return "", exitcode.Errorf(exitcode.Validation, "cannot move worktree %s into itself (%s)", worktreePath, dest)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return "", exitcode.Errorf(exitcode.Validation, "destination %s already exists", dest)
+	}
+	return dest, nil
+}
+
+// restartMovedSession recreates a moved session's tmux session in its new
+// worktree and runs the session command there again
+func restartMovedSession(tmuxManager *tmux.Manager, session *config.SessionMetadata) error {
+	workItem, err := inputsource.ParseWorkItemID(session.NamespacedID)
+	if err != nil {
+		return fmt.Errorf("failed to parse work item %s: %w", session.NamespacedID, err)
+	}
+	repoConfig, err := config.LoadConfigWithRepository(session.RepositoryRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load repository config: %w", err)
+	}
+
+	tmuxEnv := tmux.CreateTmuxEnvironment(session.FriendlyTitle)
+	if _, err := createWorkItemTmuxSession(tmuxManager, workItem, session.WorktreePath, session.TmuxSession, tmuxEnv); err != nil {
+		return fmt.Errorf("failed to restart tmux session %s: %w", session.TmuxSession, err)
+	}
+	fmt.Printf("Restarted tmux session %s in %s\n", session.TmuxSession, session.WorktreePath)
+
+	commandLine, _ := sessionCommandLine("", false, repoConfig, workItem, session.SandboxName, session.RepositoryRoot)
+	if commandLine == "" {
+		return nil
+	}
+	if err := tmuxManager.ExecuteCommand(session.TmuxSession, commandLine, nil, tmuxEnv); err != nil {
+		return fmt.Errorf("failed to run the session command: %w", err)
+	}
+	fmt.Printf("Restarted the session command: %s\n", commandLine)
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/exitcode"
+)
+
+func TestMoveDestination(t *testing.T) {
+	dir := t.TempDir()
+	worktree := filepath.Join(dir, "worktrees", "issue-12")
+	volume := filepath.Join(dir, "fast")
+	require.NoError(t, os.MkdirAll(worktree, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(volume, "taken"), 0755))
+
+	t.Run("existing directory receives the worktree", func(t *testing.T) {
+		dest, err := moveDestination(worktree, volume)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(volume, "issue-12"), dest)
+	})
+
+	t.Run("new path is used as is", func(t *testing.T) {
+		dest, err := moveDestination(worktree, filepath.Join(volume, "web-12"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(volume, "web-12"), dest)
+	})
+
+	t.Run("home directory is expanded", func(t *testing.T) {
+		t.Setenv("HOME", dir)
+		dest, err := moveDestination(worktree, "~/elsewhere")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "elsewhere"), dest)
+	})
+
+	t.Run("destination inside the worktree is rejected", func(t *testing.T) {
+		_, err := moveDestination(worktree, filepath.Join(worktree, "sub"))
+		assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+		_, err = moveDestination(worktree, filepath.Dir(worktree))
+		assert.Equal(t, exitcode.Validation, exitcode.Of(err), "moving into its own parent is a no-op")
+	})
+
+	t.Run("existing destination is rejected", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(volume, "issue-12"), 0755))
+		_, err := moveDestination(worktree, volume)
+		assert.ErrorContains(t, err, "already exists")
+		assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+	})
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/sandbox"
+)
+
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage the pool of prewarmed sandboxes",
+	Long: `Keep generic sandboxes ready so 'sbs start' doesn't wait for sandbox creation.
+
+With sandbox_pool_size set in the configuration, 'sbs start' claims a warm
+sandbox from the pool by renaming it to the session's sandbox name, then
+refills the pool in the background. When the pool is empty, or the sandbox CLI
+can't rename sandboxes, the session's sandbox is created on demand as usual.
+
+  sbs pool status        # Show the warm sandboxes
+  sbs pool fill          # Create sandboxes until the pool is full
+  sbs pool watch         # Keep the pool full until interrupted`,
+}
+
+var poolStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the prewarmed sandboxes",
+	Args:  cobra.NoArgs,
+	RunE:  runPoolStatus,
+}
+
+var poolFillCmd = &cobra.Command{
+	Use:   "fill",
+	Short: "Create sandboxes until the pool is full",
+	Args:  cobra.NoArgs,
+	RunE:  runPoolFill,
+}
+
+var poolWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep the pool full until interrupted",
+	Args:  cobra.NoArgs,
+	RunE:  runPoolWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(poolCmd)
+	poolCmd.AddCommand(poolStatusCmd, poolFillCmd, poolWatchCmd)
+	poolFillCmd.Flags().Int("size", 0, "Pool size (defaults to sandbox_pool_size)")
+	poolWatchCmd.Flags().Int("size", 0, "Pool size (defaults to sandbox_pool_size)")
+	poolWatchCmd.Flags().Duration("interval", 30*time.Second, "How often to check the pool")
+}
+
+// poolSize returns the --size flag, falling back to the configured pool size
+func poolSize(cmd *cobra.Command) (int, error) {
+	size, _ := cmd.Flags().GetInt("size")
+	if size < 0 {
+		return 0, exitcode.Errorf(exitcode.Validation, "--size cannot be negative")
+	}
+	if size == 0 {
+		cfg, err := loadPoolConfig()
+		if err != nil {
+			return 0, err
+		}
+		size = cfg.SandboxPoolSize
+	}
+	if size == 0 {
+		return 0, fmt.Errorf("sandbox pool is disabled: set sandbox_pool_size or pass --size")
+	}
+	return size, nil
+}
+
+// loadPoolConfig loads the repository configuration when run inside a
+// repository and the global configuration otherwise
+func loadPoolConfig() (*config.Config, error) {
+	if currentRepo, err := appServices().Repository(); err == nil {
+		return config.LoadConfigWithRepository(currentRepo.Root)
+	}
+	return config.LoadConfig()
+}
+
+func runPoolStatus(cmd *cobra.Command, args []string) error {
+	pool, err := appServices().SandboxManager().PoolSandboxes()
+	if err != nil {
+		return err
+	}
+	size := 0
+	if cfg, err := loadPoolConfig(); err == nil {
+		size = cfg.SandboxPoolSize
+	}
+
+	fmt.Printf("Warm sandboxes: %d/%d\n", len(pool), size)
+	for _, name := range pool {
+		fmt.Printf("  %s\n", name)
+	}
+	return nil
+}
+
+func runPoolFill(cmd *cobra.Command, args []string) error {
+	size, err := poolSize(cmd)
+	if err != nil {
+		return err
+	}
+	created, err := appServices().SandboxManager().FillPool(size)
+	for _, name := range created {
+		fmt.Printf("Created sandbox: %s\n", name)
+	}
+	return err
+}
+
+func runPoolWatch(cmd *cobra.Command, args []string) error {
+	size, err := poolSize(cmd)
+	if err != nil {
+		return err
+	}
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return exitcode.Errorf(exitcode.Validation, "--interval must be positive")
+	}
+
+	ctx := appServices().Context()
+	sandboxManager := appServices().SandboxManager()
+	fmt.Printf("Keeping %d sandbox(es) warm, checking every %s (Ctrl+C to stop)\n", size, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		created, err := sandboxManager.FillPool(size)
+		for _, name := range created {
+			fmt.Printf("%s Created sandbox: %s\n", time.Now().Format("15:04:05"), name)
+		}
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimPrewarmedSandbox hands a warm pool sandbox to the session by renaming
+// it to sandboxName and starts a background refill. Failures are reported and
+// leave the sandbox to be created on demand.
+func claimPrewarmedSandbox(sandboxManager *sandbox.Manager, sandboxName string, verbose bool) {
+	claimed, err := sandboxManager.ClaimPoolSandbox(sandboxName)
+	if errors.Is(err, sandbox.ErrPoolEmpty) {
+		fmt.Printf("Sandbox pool is empty, the sandbox will be created on demand.\n")
+		startPoolRefill(verbose)
+		return
+	}
+	if err != nil {
+		startWarningf("Could not use a prewarmed sandbox, creating it on demand: %v", err)
+		return
+	}
+	fmt.Printf("Using prewarmed sandbox %s as %s\n", claimed, sandboxName)
+	startPoolRefill(verbose)
+}
+
+// startPoolRefill runs 'sbs pool fill' detached so the pool is topped up
+// without delaying the session
+func startPoolRefill(verbose bool) {
+	executable, err := os.Executable()
+	if err != nil {
+		if verbose {
+			fmt.Printf("Debug: Not refilling sandbox pool: %v\n", err)
+		}
+		return
+	}
+	refill := exec.Command(executable, "pool", "fill")
+	if err := refill.Start(); err != nil {
+		if verbose {
+			fmt.Printf("Debug: Not refilling sandbox pool: %v\n", err)
+		}
+		return
+	}
+	refill.Process.Release()
+}
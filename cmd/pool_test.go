@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPoolFill(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// A sandbox binary that records the sandboxes it creates
+	binDir := t.TempDir()
+	state := filepath.Join(binDir, "sandboxes")
+	script := "#!/bin/sh\nstate=\"" + state + "\"\ncase \"$1\" in\n  list) cat \"$state\" 2>/dev/null ;;\n  --name) echo \"$2\" >> \"$state\" ;;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "sandbox"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	t.Run("disabled_without_size", func(t *testing.T) {
+		require.NoError(t, poolFillCmd.Flags().Set("size", "0"))
+		err := runPoolFill(poolFillCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sandbox_pool_size")
+	})
+
+	t.Run("fills_to_size", func(t *testing.T) {
+		require.NoError(t, poolFillCmd.Flags().Set("size", "2"))
+		defer poolFillCmd.Flags().Set("size", "0")
+
+		output := captureStdout(t, func() {
+			require.NoError(t, runPoolFill(poolFillCmd, nil))
+		})
+		assert.Contains(t, output, "Created sandbox: sbs-pool-1")
+		assert.Contains(t, output, "Created sandbox: sbs-pool-2")
+
+		data, err := os.ReadFile(state)
+		require.NoError(t, err)
+		assert.Equal(t, "sbs-pool-1\nsbs-pool-2\n", string(data))
+	})
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/git"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/pullrequest"
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr [work-item-id]",
+	Short: "Open a session's pull request and follow its checks",
+	Long: `Open a pull request for a session's branch, pushing the branch first, or
+find the one already open. The state of its checks is recorded on the
+session and shown in the REMOTE column of sbs list.
+
+When the base branch is protected and requires status checks, only those
+decide the outcome. --wait polls the checks every pr_check_interval_seconds
+(default 30) until they pass or one fails, then prints a summary; it exits
+non-zero when checks fail or are still pending after --timeout (default
+pr_check_timeout_minutes, 60). With pr_wait_required_checks set, sbs pr
+waits whenever the base branch requires checks.
+
+--transition moves the work item to a state in its input source, as sbs
+transition does: once the pull request is open, or when waiting, once its
+checks passed. A failed transition is reported as a warning.
+
+Run from inside a session worktree, the work item ID can be omitted.
+
+Examples:
+  sbs pr 123
+  sbs pr 123 --transition in-review
+  sbs pr --wait && sbs stop --done`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPR,
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.Flags().String("base", "", "Branch to open the pull request against (default: the repository's default branch)")
+	prCmd.Flags().Bool("draft", false, "Open the pull request as a draft")
+	prCmd.Flags().Bool("wait", false, "Wait until the pull request's checks pass or fail")
+	prCmd.Flags().Duration("timeout", 0, "How long --wait waits for checks (default: pr_check_timeout_minutes)")
+	prCmd.Flags().String("transition", "", "Move the work item to this state once the pull request is open (open, in-progress, in-review, done)")
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	base, _ := cmd.Flags().GetString("base")
+	draft, _ := cmd.Flags().GetBool("draft")
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	transition, _ := cmd.Flags().GetString("transition")
+	if transition != "" {
+		if _, err := inputsource.NormalizeState(transition); err != nil {
+			return exitcode.Wrap(exitcode.Validation, err)
+		}
+	}
+
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	var session *config.SessionMetadata
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return sessionNotFound(workItemID)
+	}
+
+	client := pullrequest.NewClient(session.WorktreePath)
+	pr, err := client.Find(session.Branch)
+	if err != nil {
+		return err
+	}
+	if pr == nil {
+		gitManager, err := git.NewManager(session.RepositoryRoot)
+		if err != nil {
+			return fmt.Errorf("failed to open repository %s: %w", session.RepositoryRoot, err)
+		}
+		if err := gitManager.WithContext(appServices().Context()).PushBranch(session.WorktreePath, session.Branch); err != nil {
+			return err
+		}
+		if pr, err = client.Create(session.Branch, base, draft); err != nil {
+			return err
+		}
+		fmt.Printf("Opened pull request #%d: %s\n", pr.Number, pr.URL)
+	} else {
+		fmt.Printf("Pull request #%d (%s): %s\n", pr.Number, strings.ToLower(pr.State), pr.URL)
+		if base != "" && base != pr.BaseRefName {
+			fmt.Printf("Warning: the pull request targets %s, not %s\n", pr.BaseRefName, base)
+		}
+	}
+
+	required, err := client.RequiredChecks(pr.BaseRefName)
+	if err != nil {
+		fmt.Printf("Warning: %v; all checks decide the outcome\n", err)
+	} else if len(required) > 0 {
+		fmt.Printf("%s requires checks: %s\n", pr.BaseRefName, strings.Join(required, ", "))
+	}
+
+	repoConfig := appServices().Config()
+	poll := func() (pullrequest.Summary, error) {
+		checks, err := client.Checks(pr.Number)
+		if err != nil {
+			return pullrequest.Summary{}, err
+		}
+		summary := pullrequest.Summarize(checks, required)
+		if err := recordPullRequest(workItemID, pr, summary.Status()); err != nil {
+			fmt.Printf("Warning: failed to record the pull request: %v\n", err)
+		}
+		return summary, nil
+	}
+
+	if !wait && !(repoConfig.PRWaitRequiredChecks && len(required) > 0) {
+		summary, err := poll()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Checks: %s\n", summary)
+		transitionAfterPR(workItemID, transition)
+		return nil
+	}
+
+	if timeout <= 0 {
+		timeout = prCheckTimeout(repoConfig)
+	}
+	interval := prCheckInterval(repoConfig)
+	fmt.Printf("Waiting up to %s for checks (polling every %s)...\n", timeout, interval)
+
+	ctx, cancel := context.WithTimeout(appServices().Context(), timeout)
+	defer cancel()
+	last := ""
+	summary, err := pullrequest.Wait(ctx, interval, poll, func(summary pullrequest.Summary) {
+		if line := summary.String(); line != last {
+			fmt.Printf("Checks: %s\n", line)
+			last = line
+		}
+	})
+	fmt.Print(formatCheckSummary(summary))
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("checks of #%d still pending after %s: %s", pr.Number, timeout, strings.Join(summary.Pending, ", "))
+	case err != nil:
+		return err
+	case len(summary.Failed) > 0:
+		return fmt.Errorf("checks of #%d failed: %s", pr.Number, strings.Join(summary.Failed, ", "))
+	}
+	fmt.Printf("Checks of #%d passed\n", pr.Number)
+	transitionAfterPR(workItemID, transition)
+	return nil
+}
+
+// transitionAfterPR moves a work item to state for --transition; a failure
+// doesn't fail sbs pr, whose pull request is open either way
+func transitionAfterPR(workItemID, state string) {
+	if state == "" {
+		return
+	}
+	if err := transitionWorkItem(workItemID, state); err != nil {
+		fmt.Printf("Warning: failed to move work item to %s: %v\n", state, err)
+	}
+}
+
+// recordPullRequest stores a session's pull request and its remote status
+func recordPullRequest(workItemID string, pr *pullrequest.PullRequest, status string) error {
+	return updateSession(workItemID, func(s *config.SessionMetadata) {
+		s.PullRequestNumber = pr.Number
+		s.PullRequestURL = pr.URL
+		s.RemoteStatus = status
+		s.RemoteStatusAt = config.Timestamp()
+		s.RemoteMessage, s.RemoteURL = "", ""
+	})
+}
+
+// prCheckInterval is how often sbs pr --wait polls checks
+func prCheckInterval(cfg *config.Config) time.Duration {
+	if cfg.PRCheckIntervalSecs > 0 {
+		return time.Duration(cfg.PRCheckIntervalSecs) * time.Second
+	}
+	return config.DefaultPRCheckIntervalSecs * time.Second
+}
+
+// prCheckTimeout is how long sbs pr --wait waits without --timeout
+func prCheckTimeout(cfg *config.Config) time.Duration {
+	if cfg.PRCheckTimeoutMins > 0 {
+		return time.Duration(cfg.PRCheckTimeoutMins) * time.Minute
+	}
+	return config.DefaultPRCheckTimeoutMins * time.Minute
+}
+
+// formatCheckSummary lists each reported check with its outcome, marking
+// the ones the base branch requires, followed by required checks not
+// reported yet
+func formatCheckSummary(summary pullrequest.Summary) string {
+	var b strings.Builder
+	reported := map[string]bool{}
+	for _, check := range summary.Checks {
+		reported[check.Name] = true
+		name := check.Name
+		for _, r := range summary.Required {
+			if r == check.Name {
+				name += " (required)"
+			}
+		}
+		fmt.Fprintf(&b, "  %-8s %s", check.Bucket, name)
+		if check.Link != "" && (check.Bucket == "fail" || check.Bucket == "cancel") {
+			fmt.Fprintf(&b, "  %s", check.Link)
+		}
+		b.WriteString("\n")
+	}
+	for _, name := range summary.Required {
+		if !reported[name] {
+			fmt.Fprintf(&b, "  %-8s %s (required)\n", "missing", name)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/pullrequest"
+)
+
+func TestPRCheckPolling(t *testing.T) {
+	cfg := &config.Config{}
+	assert.Equal(t, 30*time.Second, prCheckInterval(cfg))
+	assert.Equal(t, time.Hour, prCheckTimeout(cfg))
+
+	cfg.PRCheckIntervalSecs, cfg.PRCheckTimeoutMins = 10, 15
+	assert.Equal(t, 10*time.Second, prCheckInterval(cfg))
+	assert.Equal(t, 15*time.Minute, prCheckTimeout(cfg))
+}
+
+func TestFormatCheckSummary(t *testing.T) {
+	summary := pullrequest.Summarize([]pullrequest.Check{
+		{Name: "build", Bucket: "pass", Link: "https://ci/build"},
+		{Name: "lint", Bucket: "fail", Link: "https://ci/lint"},
+	}, []string{"build", "e2e"})
+
+	assert.Equal(t, ""+
+		"  pass     build (required)\n"+
+		"  fail     lint  https://ci/lint\n"+
+		"  missing  e2e (required)\n", formatCheckSummary(summary))
+}
+
+func TestPRTransition(t *testing.T) {
+	require.NoError(t, prCmd.Flags().Set("transition", "shipped"))
+	t.Cleanup(func() { prCmd.Flags().Set("transition", "") })
+	err := runPR(prCmd, []string{"test:pr"})
+	require.Error(t, err, "an unknown state fails before anything is pushed")
+	assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+
+	output := captureStdout(t, func() {
+		transitionAfterPR("test:pr", "in-review")
+	})
+	assert.Contains(t, output, "Work item test:pr transitioned to in-review")
+
+	output = captureStdout(t, func() {
+		transitionAfterPR("github:", "done")
+	})
+	assert.Contains(t, output, "Warning: failed to move work item to done")
+
+	assert.Empty(t, captureStdout(t, func() { transitionAfterPR("test:pr", "") }))
+}
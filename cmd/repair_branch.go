@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+	"sbs/pkg/sessiondoc"
+)
+
+var repairBranchCmd = &cobra.Command{
+	Use:   "repair-branch [work-item-id]",
+	Short: "Update a session after its branch was renamed outside sbs",
+	Long: `Update a session's recorded branch after the branch was renamed manually
+(e.g. with 'git branch -m'). The new name is read from the worktree's HEAD; the
+session is only updated when the recorded branch no longer exists, so switching
+the worktree to another branch is never mistaken for a rename.
+
+Until the session is repaired, sbs stop --delete-branch refuses to delete the
+renamed branch.
+
+Run from inside a session worktree, the work item ID can be omitted.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runRepairBranch,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(repairBranchCmd)
+	repairBranchCmd.Flags().Bool("dry-run", false, "Show the detected rename without updating the session")
+}
+
+// branchRenameDetector finds the new name of a session's renamed branch
+type branchRenameDetector interface {
+	DetectBranchRename(recordedBranch, worktreePath string) (string, error)
+}
+
+func runRepairBranch(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var session *config.SessionMetadata
+	for i := range sessions {
+		if sessionWorkItemID(sessions[i]) == workItemID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return fmt.Errorf("no session found for work item %s", workItemID)
+	}
+
+	gitManager, err := git.NewManager(session.RepositoryRoot)
+	if err != nil {
+		return fmt.Errorf("failed to open repository %s: %w", session.RepositoryRoot, err)
+	}
+
+	oldBranch := session.Branch
+	newBranch, err := repairSessionBranch(session, gitManager.WithContext(appServices().Context()))
+	if err != nil {
+		return err
+	}
+	if newBranch == "" {
+		fmt.Printf("Branch %s of %s is unchanged; nothing to repair.\n", oldBranch, workItemID)
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("Would update %s: branch %s -> %s\n", workItemID, oldBranch, newBranch)
+		return nil
+	}
+	if err := saveRepairedBranch(workItemID, oldBranch, newBranch); err != nil {
+		return fmt.Errorf("failed to save sessions: %w", err)
+	}
+	fmt.Printf("Updated %s: branch %s -> %s\n", workItemID, oldBranch, newBranch)
+	if err := sessiondoc.Update(*session); err != nil {
+		fmt.Printf("Warning: failed to update %s: %v\n", sessiondoc.RelativePath, err)
+	}
+	return nil
+}
+
+// repairSessionBranch records a renamed branch in the session and returns the
+// new name, or "" if the branch was not renamed
+func repairSessionBranch(session *config.SessionMetadata, detector branchRenameDetector) (string, error) {
+	if session.WorktreePath == "" {
+		return "", fmt.Errorf("session %s has no worktree to read the branch from", sessionWorkItemID(*session))
+	}
+	newBranch, err := detector.DetectBranchRename(session.Branch, session.WorktreePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect branch rename: %w", err)
+	}
+	if newBranch != "" {
+		session.Branch = newBranch
+	}
+	return newBranch, nil
+}
+
+// saveRepairedBranch records the new branch of a work item's session under
+// the sessions lock, unless another sbs process changed the branch meanwhile
+func saveRepairedBranch(workItemID, oldBranch, newBranch string) error {
+	return config.UpdateSessions(func(sessions []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		for i := range sessions {
+			if sessionWorkItemID(sessions[i]) == workItemID && sessions[i].Branch == oldBranch {
+				sessions[i].Branch = newBranch
+			}
+		}
+		return sessions, nil
+	})
+}
+
+// detectRenamedBranch returns the new name of a session's branch if it was
+// renamed outside sbs, or "" when it wasn't or the check isn't possible
+func detectRenamedBranch(session *config.SessionMetadata) string {
+	if session.WorktreePath == "" || session.RepositoryRoot == "" {
+		return ""
+	}
+	gitManager, err := git.NewManager(session.RepositoryRoot)
+	if err != nil {
+		return ""
+	}
+	renamed, err := gitManager.DetectBranchRename(session.Branch, session.WorktreePath)
+	if err != nil {
+		return ""
+	}
+	return renamed
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+type fakeRenameDetector struct {
+	renamed string
+	err     error
+}
+
+func (f fakeRenameDetector) DetectBranchRename(recordedBranch, worktreePath string) (string, error) {
+	return f.renamed, f.err
+}
+
+func TestRepairSessionBranch(t *testing.T) {
+	t.Run("records the new branch", func(t *testing.T) {
+		session := &config.SessionMetadata{NamespacedID: "test:1", Branch: "issue-test-1-old", WorktreePath: "/tmp/wt"}
+		newBranch, err := repairSessionBranch(session, fakeRenameDetector{renamed: "issue-test-1-new"})
+		require.NoError(t, err)
+		assert.Equal(t, "issue-test-1-new", newBranch)
+		assert.Equal(t, "issue-test-1-new", session.Branch)
+	})
+
+	t.Run("unchanged branch", func(t *testing.T) {
+		session := &config.SessionMetadata{NamespacedID: "test:1", Branch: "issue-test-1-old", WorktreePath: "/tmp/wt"}
+		newBranch, err := repairSessionBranch(session, fakeRenameDetector{})
+		require.NoError(t, err)
+		assert.Empty(t, newBranch)
+		assert.Equal(t, "issue-test-1-old", session.Branch)
+	})
+
+	t.Run("detection failure", func(t *testing.T) {
+		session := &config.SessionMetadata{NamespacedID: "test:1", Branch: "issue-test-1-old", WorktreePath: "/tmp/wt"}
+		_, err := repairSessionBranch(session, fakeRenameDetector{err: errors.New("not a git worktree")})
+		assert.ErrorContains(t, err, "not a git worktree")
+		assert.Equal(t, "issue-test-1-old", session.Branch)
+	})
+
+	t.Run("no worktree", func(t *testing.T) {
+		session := &config.SessionMetadata{NamespacedID: "test:1", Branch: "issue-test-1-old"}
+		_, err := repairSessionBranch(session, fakeRenameDetector{renamed: "other"})
+		assert.ErrorContains(t, err, "has no worktree")
+	})
+}
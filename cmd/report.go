@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+	"sbs/pkg/report"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate an activity report for a period",
+	Long: `Aggregate session activity over a period into a report for weekly updates:
+sessions touched, branches created and merged, active time per work item,
+and cleanups performed.
+
+Active time is the span between a session's creation and its last recorded
+activity that falls within the period. Merged branches are those reachable
+from the repository's main or master branch.
+
+Examples:
+  sbs report                          # Last 7 days as Markdown
+  sbs report --since 2w --format text
+  sbs report --since 2025-08-01 > weekly.md`,
+	Args:        cobra.NoArgs,
+	RunE:        runReport,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().String("since", "7d", "Start of the period: days or weeks (7d, 2w), a duration (36h) or a date (2025-08-01)")
+	reportCmd.Flags().String("format", report.FormatMarkdown, "Output format: markdown or text")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	sinceValue, _ := cmd.Flags().GetString("since")
+	format, _ := cmd.Flags().GetString("format")
+
+	now := config.Now()
+	since, err := report.ParseSince(sinceValue, now)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := config.LoadAllRepositorySessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var archived []config.ArchivedSession
+	if archivePath, err := config.GlobalSessionsArchivePath(); err == nil {
+		archived, err = config.LoadArchivedSessions(archivePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cleanups omitted: %v\n", err)
+		}
+	}
+
+	r := report.Build(sessions, archived, since, now, newMergedBranchChecker().merged)
+	output, err := report.Render(r, format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// mergedBranchChecker looks up merged branches once per repository
+type mergedBranchChecker struct {
+	branches map[string]map[string]bool // repository root -> merged branches
+}
+
+func newMergedBranchChecker() *mergedBranchChecker {
+	return &mergedBranchChecker{branches: make(map[string]map[string]bool)}
+}
+
+// merged reports whether the session's branch is merged into its repository's
+// base branch. Repositories that can't be inspected count as unmerged.
+func (c *mergedBranchChecker) merged(session config.SessionMetadata) bool {
+	if session.RepositoryRoot == "" {
+		return false
+	}
+	branches, ok := c.branches[session.RepositoryRoot]
+	if !ok {
+		branches = loadMergedBranches(session.RepositoryRoot)
+		c.branches[session.RepositoryRoot] = branches
+	}
+	return branches[session.Branch]
+}
+
+func loadMergedBranches(repoRoot string) map[string]bool {
+	branches := make(map[string]bool)
+	if _, err := os.Stat(repoRoot); err != nil {
+		return branches
+	}
+	gitManager, err := git.NewManager(repoRoot)
+	if err != nil {
+		return branches
+	}
+	gitManager = gitManager.WithContext(appServices().Context())
+	base, err := gitManager.DefaultBaseBranch()
+	if err != nil {
+		return branches
+	}
+	merged, err := gitManager.MergedBranches(base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not check merged branches in %s: %v\n", repoRoot, err)
+		return branches
+	}
+	for _, branch := range merged {
+		branches[branch] = true
+	}
+	return branches
+}
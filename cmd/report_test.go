@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestRunReport_IncludesCleanups(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Now()
+	session := config.SessionMetadata{
+		NamespacedID: "test:report",
+		IssueTitle:   "Report me",
+		SourceType:   "test",
+		CreatedAt:    now.Add(-2 * time.Hour).Format(time.RFC3339),
+		LastActivity: now.Add(-time.Hour).Format(time.RFC3339),
+	}
+	require.NoError(t, config.SaveSessions(nil))
+	require.NoError(t, archiveCleanedSessions([]config.SessionMetadata{session}))
+
+	require.NoError(t, reportCmd.Flags().Set("since", "1d"))
+	require.NoError(t, reportCmd.Flags().Set("format", "markdown"))
+
+	output := captureStdout(t, func() {
+		require.NoError(t, runReport(reportCmd, nil))
+	})
+	assert.Contains(t, output, "- Sessions touched: 1")
+	assert.Contains(t, output, "- Cleanups: 1")
+	assert.Contains(t, output, "| test:report | Report me |")
+
+	archivePath, err := config.GlobalSessionsArchivePath()
+	require.NoError(t, err)
+	archived, err := config.LoadArchivedSessions(archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, config.SessionStatusDeleted, archived[0].Session.Status, "cleaned sessions are marked deleted")
+
+	require.NoError(t, reportCmd.Flags().Set("since", "soon"))
+	assert.Error(t, runReport(reportCmd, nil))
+	require.NoError(t, reportCmd.Flags().Set("since", "7d"))
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	original := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = original }()
+
+	fn()
+	require.NoError(t, w.Close())
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -1,13 +1,32 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sbs/pkg/app"
+	"sbs/pkg/branchname"
 	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
 	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/git"
+	"sbs/pkg/issue"
+	"sbs/pkg/messages"
+	"sbs/pkg/naming"
+	"sbs/pkg/paths"
+	"sbs/pkg/timing"
+	"sbs/pkg/trace"
 	"sbs/pkg/tui"
 	"sbs/pkg/validation"
 )
@@ -23,21 +42,123 @@ It automatically handles:
 
 Each issue gets its own branch, worktree, and tmux session for organized development.
 
-When run without arguments, launches an interactive TUI to manage sessions.`,
-	RunE: runRoot,
+When run without arguments, launches an interactive TUI to manage sessions,
+where 1-3 attach to the most recently active sessions. Outside a terminal,
+those sessions are printed with the commands to attach to them.
+
+Exit codes: 0 success, 1 other errors, 2 invalid arguments, flags or
+configuration, 3 session or work item not found, 4 partial failure (some of
+the work failed or was skipped), 5 work item busy in another sbs process or
+an operation in progress, 130 interrupted.`,
+	RunE:              runRoot,
+	PersistentPreRunE: setupServices,
 }
 
 var cfg *config.Config
+
+// services holds the managers shared by all commands and the TUI
+var services *app.Container
 var verbose bool
+var profile bool
+var tracePath string
+
+// skipToolValidation marks commands that don't need tmux, git, gh or sandbox
+const skipToolValidation = "sbs/skip-tool-validation"
+
+// interruptGracePeriod is how long sbs waits for in-flight work to stop after
+// an interrupt before exiting anyway
+const interruptGracePeriod = 3 * time.Second
 
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	// Group the external commands of this invocation in the command log
+	ctx = cmdlog.WithCorrelationID(ctx, cmdlog.NewCorrelationID())
+
+	classifyOnce.Do(func() { classifyUsageErrors(rootCmd) })
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil && exitcode.Of(err) == exitcode.Failure && strings.HasPrefix(err.Error(), "unknown command ") {
+		err = exitcode.Wrap(exitcode.Validation, err)
+	}
+	if services != nil {
+		services.Close()
+	}
+
+	// Finish the --trace transcript with the invocation's result
+	if traceErr := trace.Stop(err); traceErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: trace incomplete: %v\n", traceErr)
+	}
+
+	// Typed git errors carry git's full output; show it under --verbose
+	if output := git.CommandOutput(err); verbose && output != "" {
+		fmt.Fprintf(os.Stderr, "Git output:\n%s\n", output)
+	}
+
+	// Dump startup phase timings collected with --profile
+	app.GetGlobalProfiler().Report(os.Stderr)
+
+	return err
+}
+
+var classifyOnce sync.Once
+
+// classifyUsageErrors gives flag parsing and argument count errors of cmd
+// and its subcommands the Validation exit code
+func classifyUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return exitcode.Wrap(exitcode.Validation, err)
+	})
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			return exitcode.Wrap(exitcode.Validation, validate(c, args))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		classifyUsageErrors(sub)
+	}
+}
+
+// signalContext returns a context cancelled by SIGINT or SIGTERM. Commands
+// get interruptGracePeriod to wind down; a second signal exits immediately.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			cancel()
+			time.AfterFunc(interruptGracePeriod, func() { os.Exit(exitcode.Interrupted) })
+		case <-ctx.Done():
+			signal.Stop(signals)
+		}
+	}()
+
+	return ctx, cancel
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
-	// Launch interactive TUI (same as current sbs list behavior)
-	model := tui.NewModel()
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	// Without a terminal for the TUI, point at the sessions to resume instead
+	if !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stdin.Fd())) {
+		sessions, err := config.LoadAllRepositorySessions()
+		if err != nil {
+			return fmt.Errorf("failed to load sessions: %w", err)
+		}
+		printRecentSessions(os.Stdout, sessions)
+		return nil
+	}
+
+	// Launch interactive TUI (same as current sbs list behavior). Quitting
+	// returns from Execute, which cancels commands still running in the background.
+	// Warnings about config files loaded later would scribble over the screen
+	config.SetWarningOutput(io.Discard)
+	defer config.SetWarningOutput(nil)
+	container := appServices()
+	model := tui.NewModelWithContainer(container)
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(container.Context()))
 
 	_, err := program.Run()
 	return err
@@ -47,16 +168,42 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is ~/.config/sbs/config.json)")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is config.json in the config directory)")
+	rootCmd.PersistentFlags().String("config-dir", "", "directory holding the config and state (default is $XDG_CONFIG_HOME/sbs or ~/.config/sbs)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose command logging")
+	rootCmd.PersistentFlags().BoolVar(&profile, "profile", false, "Print timing of each startup phase to stderr on exit")
+	rootCmd.PersistentFlags().StringVar(&tracePath, "trace", "", "Record the commands run, decisions made and state changed to a file for bug reports")
 }
 
 func initConfig() {
+	if profile {
+		app.EnableProfiling()
+	}
+	if tracePath != "" {
+		if err := trace.Start(tracePath, os.Args, sbsVersion); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; continuing without --trace\n", err)
+		}
+	}
+
+	// Locate the config and state before anything reads them
+	configDir, _ := rootCmd.PersistentFlags().GetString("config-dir")
+	paths.SetConfigDir(configDir)
+	configFile, _ := rootCmd.PersistentFlags().GetString("config")
+	paths.SetConfigFile(configFile)
+
+	endLoadConfig := app.Track("load config")
 	var err error
 	cfg, err = config.LoadConfig()
+	endLoadConfig()
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitcode.Validation)
+	}
+
+	// Keep state under $XDG_STATE_HOME/sbs when configured
+	paths.SetXDGState(cfg.XDGState)
+	if stateDir, err := paths.StateDir(); err == nil {
+		trace.Decision("state directory", stateDir, fmt.Sprintf("xdg_state %t", cfg.XDGState))
 	}
 
 	// Initialize command logging based on configuration and verbose flag
@@ -85,10 +232,106 @@ func initConfig() {
 		logger := cmdlog.NewCommandLogger(logConfig)
 		cmdlog.SetGlobalLogger(logger)
 	}
+	if trace.Enabled() {
+		cmdlog.SetGlobalLogger(trace.WrapLogger(cmdlog.GetGlobalLogger()))
+	}
+	// Time the slow operations sbs doctor and the TUI warn about
+	if timingsPath, err := timing.DefaultPath(); err == nil {
+		cmdlog.SetGlobalLogger(timing.WrapLogger(cmdlog.GetGlobalLogger(), timing.NewStore(timingsPath)))
+	}
+
+	// Apply external command timeouts
+	cmdtimeout.SetGlobalConfig(cmdtimeout.FromSeconds(cfg.CommandTimeoutSecs, cfg.CommandTimeouts))
+
+	// Run a custom git binary or wrapper if configured
+	git.SetExecutable(cfg.GitExecutable)
+
+	// Talk to GitHub through gh or the REST API
+	issue.SetClientConfig(issue.ClientConfig{Backend: cfg.GitHubClient, Token: cfg.GitHubToken, APIURL: cfg.GitHubAPIURL})
+
+	// Name new work item branches from the configured template
+	branchname.SetTemplate(cfg.BranchTemplate)
+
+	// Word user-facing messages as configured
+	catalog, err := messages.New(cfg.Messages, cfg.Terms)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using the default messages\n", err)
+	}
+	messages.SetCatalog(catalog)
+
+	// Fold the configured scope into tmux session and sandbox names
+	scope, err := naming.ResolveScope(cfg.NameScope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; names will not be scoped\n", err)
+	}
+	naming.SetScope(scope)
+	trace.Decision("name scope", scope, "name_scope "+cfg.NameScope)
+
+	traceConfig(cfg)
+}
+
+// setupServices builds the shared service container once per invocation
+// and validates required tools for the command being run
+func setupServices(cmd *cobra.Command, args []string) error {
+	services = app.NewContainerWithContext(cmd.Context(), cfg)
+	migrateLegacySessions(os.Stderr)
+	return validateTools(cmd, args)
+}
+
+// migrateLegacySessions merges sessions files older versions kept per
+// repository into the global sessions file, reporting what it did on w.
+// Failures only warn: the legacy files stay for the next run.
+func migrateLegacySessions(w io.Writer) {
+	migration, err := config.MigrateLegacySessions()
+	if err != nil {
+		fmt.Fprintf(w, "Warning: legacy sessions not migrated: %v\n", err)
+	}
+	for _, failure := range migration.Failed {
+		fmt.Fprintf(w, "Warning: %v\n", failure)
+	}
+	if len(migration.Files) > 0 {
+		fmt.Fprintf(w, "Migrated %d session(s) from %d legacy per-repository sessions file(s); the originals were kept with a %s suffix\n",
+			migration.Imported, len(migration.Files), config.LegacyBackupSuffix)
+	}
+}
 
-	// Validate required tools are available
+// appServices returns the shared service container, creating it if the
+// command was invoked without the root pre-run (e.g. directly from tests)
+func appServices() *app.Container {
+	if services == nil {
+		services = app.NewContainer(cfg)
+	}
+	return services
+}
+
+// validateTools checks that required external tools are available before
+// running commands that use them
+func validateTools(cmd *cobra.Command, args []string) error {
+	if cmd.Annotations[skipToolValidation] == "true" {
+		return nil
+	}
+
+	defer app.Track("validate tools")()
 	if err := validation.CheckRequiredTools(); err != nil {
 		fmt.Printf("Tool validation failed:\n%v", err)
-		os.Exit(1)
+		os.Exit(exitcode.Validation)
+	}
+	return nil
+}
+
+// printRecentSessions lists the most recently active sessions with the
+// command that attaches to each
+func printRecentSessions(w io.Writer, sessions []config.SessionMetadata) {
+	recent := tui.RecentSessions(sessions, tui.RecentSessionCount)
+	if len(recent) == 0 {
+		fmt.Fprintln(w, messages.Render(messages.NoSessions, nil))
+		fmt.Fprintln(w, "Use 'sbs start <issue-number>' to create a new session.")
+		return
+	}
+
+	fmt.Fprintln(w, "Recent sessions:")
+	for _, session := range recent {
+		fmt.Fprintf(w, "  %-24s %-40s %-10s sbs attach %s\n",
+			session.NamespacedID, truncateString(session.IssueTitle, 40), formatRelativeTime(session.LastActivity), session.NamespacedID)
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"sbs/pkg/cmdlog"
 	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
 )
 
 func TestInitConfig_CommandLogging(t *testing.T) {
@@ -261,3 +263,95 @@ func TestRootCommand_NowHasRunE(t *testing.T) {
 		assert.NotNil(t, rootCmd.RunE, "Root command should now have RunE function to launch TUI")
 	})
 }
+
+func TestRootCommand_ProfileFlag(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("profile")
+	require.NotNil(t, flag, "root command should have a persistent --profile flag")
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestRootCommand_ToolValidationSkipped(t *testing.T) {
+	// Commands that never run external tools opt out of tool validation
+	for _, cmd := range []*cobra.Command{listCmd, versionCmd} {
+		assert.Equal(t, "true", cmd.Annotations[skipToolValidation], "%s should skip tool validation", cmd.Name())
+		assert.NoError(t, validateTools(cmd, nil))
+	}
+
+	assert.Empty(t, startCmd.Annotations[skipToolValidation], "start needs tmux, git and sandbox")
+}
+
+func TestRootCommand_SharedServices(t *testing.T) {
+	original := services
+	defer func() { services = original }()
+
+	services = nil
+	first := appServices()
+	require.NotNil(t, first)
+	assert.Same(t, first, appServices(), "fallback container is created once")
+
+	require.NoError(t, setupServices(listCmd, nil))
+	assert.NotSame(t, first, services, "pre-run builds a fresh container per invocation")
+	assert.Same(t, services, appServices())
+}
+
+func TestPrintRecentSessions(t *testing.T) {
+	var out bytes.Buffer
+	printRecentSessions(&out, []config.SessionMetadata{
+		{NamespacedID: "github:1", IssueTitle: "Older", TmuxSession: "sbs-1", LastActivity: "2026-01-01T09:00:00Z"},
+		{NamespacedID: "test:demo", IssueTitle: "Newer", TmuxSession: "sbs-demo", LastActivity: "2026-01-02T09:00:00Z"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "Recent sessions:", lines[0])
+	assert.Contains(t, lines[1], "test:demo")
+	assert.True(t, strings.HasSuffix(lines[1], "sbs attach test:demo"))
+	assert.True(t, strings.HasSuffix(lines[2], "sbs attach github:1"))
+
+	out.Reset()
+	printRecentSessions(&out, nil)
+	assert.Contains(t, out.String(), "No active work sessions found.")
+}
+
+func TestClassifyUsageErrors(t *testing.T) {
+	root := &cobra.Command{Use: "sbs"}
+	child := &cobra.Command{Use: "show", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+	child.Flags().Int("limit", 0, "")
+	root.AddCommand(child)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	classifyUsageErrors(root)
+
+	root.SetArgs([]string{"show"})
+	assert.Equal(t, exitcode.Validation, exitcode.Of(root.Execute()), "wrong argument count")
+
+	root.SetArgs([]string{"show", "1", "--limit", "x"})
+	assert.Equal(t, exitcode.Validation, exitcode.Of(root.Execute()), "invalid flag value")
+
+	root.SetArgs([]string{"show", "1"})
+	assert.NoError(t, root.Execute())
+
+	assert.Equal(t, exitcode.NotFound, exitcode.Of(sessionNotFound("github:1")))
+}
+
+func TestMigrateLegacySessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	legacy := filepath.Join(home, ".config", "sbs", "repos", "web", "sessions.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(legacy), 0700))
+	require.NoError(t, os.WriteFile(legacy, []byte(`[{"issue_number": 7, "branch": "issue-7"}]`), 0600))
+
+	var out bytes.Buffer
+	migrateLegacySessions(&out)
+	assert.Equal(t, "Migrated 1 session(s) from 1 legacy per-repository sessions file(s); the originals were kept with a .migrated suffix\n", out.String())
+
+	sessions, err := config.LoadSessions()
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "github:7", sessions[0].NamespacedID)
+
+	out.Reset()
+	migrateLegacySessions(&out)
+	assert.Empty(t, out.String(), "nothing is left to migrate")
+}
@@ -0,0 +1,473 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/sandbox"
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:   "sandbox",
+	Short: "Manage the sandboxes of work sessions",
+}
+
+var sandboxLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List sbs sandboxes and the sessions that own them",
+	Long: `List every sbs sandbox the sandbox CLI knows about, with the session that
+owns it. Sandboxes no session owns are marked as orphans; 'sbs sandbox prune'
+removes them. Warm sandboxes of the prewarm pool are marked as such.`,
+	Args: cobra.NoArgs,
+	RunE: runSandboxLs,
+}
+
+var sandboxRmCmd = &cobra.Command{
+	Use:   "rm <sandbox-name>...",
+	Short: "Remove sbs sandboxes by name",
+	Long: `Remove sbs sandboxes by the names 'sbs sandbox ls' shows. A sandbox that an
+active session owns is only removed with --force; 'sbs stop' or 'sbs clean'
+is usually what you want for those.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSandboxRm,
+}
+
+var sandboxPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove sbs sandboxes no session owns",
+	Long: `Remove every orphaned sbs sandbox: sandboxes that no active session owns and
+that aren't part of the prewarm pool, such as those left behind by sessions
+removed outside of sbs clean.`,
+	Args: cobra.NoArgs,
+	RunE: runSandboxPrune,
+}
+
+var sandboxSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <work-item-id> [snapshot-name]",
+	Short: "Snapshot a session's sandbox",
+	Long: `Save the state of a session's sandbox, when the sandbox CLI supports
+snapshots. Without a name the snapshot is named after the current time. The
+snapshot is recorded on the session; --list shows the recorded snapshots.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSandboxSnapshot,
+}
+
+var sandboxRestoreCmd = &cobra.Command{
+	Use:   "restore <work-item-id> <snapshot-name>",
+	Short: "Restore a session's sandbox from a snapshot",
+	Long: `Return a session's sandbox to a snapshot, recreating the sandbox if it was
+deleted. Sessions removed by sbs clean are looked up in the session archive,
+so a sandbox snapshotted before clean can be brought back; sbs start then
+picks it up again.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSandboxRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(sandboxCmd)
+	sandboxCmd.AddCommand(sandboxLsCmd, sandboxRmCmd, sandboxPruneCmd, sandboxSnapshotCmd, sandboxRestoreCmd)
+	sandboxRmCmd.Flags().BoolP("yes", "y", false, "Remove without asking for confirmation")
+	sandboxRmCmd.Flags().Bool("force", false, "Also remove sandboxes that active sessions own")
+	sandboxPruneCmd.Flags().BoolP("yes", "y", false, "Remove without asking for confirmation")
+	sandboxPruneCmd.Flags().Bool("dry-run", false, "Show the orphaned sandboxes without removing them")
+	sandboxSnapshotCmd.Flags().Bool("list", false, "List the session's recorded snapshots instead of taking one")
+	sandboxRestoreCmd.Flags().BoolP("yes", "y", false, "Replace a running sandbox without asking for confirmation")
+}
+
+// sandboxInventory is a sandbox provider able to list and delete the sbs
+// sandboxes it can see
+type sandboxInventory interface {
+	ListSandboxes() ([]string, error)
+	DeleteSandbox(sandboxName string) error
+}
+
+// sandboxEntry is an sbs sandbox and the session that owns it
+type sandboxEntry struct {
+	Name  string
+	Owner string // Work item ID of the owning session
+	Pool  bool   // A warm sandbox of the prewarm pool
+}
+
+// Orphan reports whether neither a session nor the pool owns the sandbox
+func (e sandboxEntry) Orphan() bool {
+	return e.Owner == "" && !e.Pool
+}
+
+// listSandboxEntries returns the provider's sbs sandboxes sorted by name,
+// each with the session among sessions that owns it
+func listSandboxEntries(provider sandboxInventory, sessions []config.SessionMetadata) ([]sandboxEntry, error) {
+	names, err := provider.ListSandboxes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sandboxes: %w", err)
+	}
+	owners := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		name := session.SandboxName
+		if name == "" {
+			// Sessions from before sandbox names were recorded
+			name = "sbs-" + session.NamespacedID
+		}
+		owners[name] = session.NamespacedID
+	}
+
+	entries := make([]sandboxEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, sandboxEntry{Name: name, Owner: owners[name], Pool: sandbox.IsPoolSandbox(name)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// loadSandboxEntries lists the sbs sandboxes against the active sessions
+func loadSandboxEntries() ([]sandboxEntry, error) {
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	return listSandboxEntries(appServices().SandboxManager(), sessions)
+}
+
+func runSandboxLs(cmd *cobra.Command, args []string) error {
+	entries, err := loadSandboxEntries()
+	if err != nil {
+		return err
+	}
+	printSandboxEntries(entries)
+	return nil
+}
+
+// printSandboxEntries prints one line per sandbox with its owner
+func printSandboxEntries(entries []sandboxEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No sbs sandboxes found.")
+		return
+	}
+	width := len("SANDBOX")
+	for _, entry := range entries {
+		width = max(width, len(entry.Name))
+	}
+	fmt.Printf("%-*s  %s\n", width, "SANDBOX", "SESSION")
+	for _, entry := range entries {
+		owner := entry.Owner
+		switch {
+		case entry.Pool:
+			owner = "(pool)"
+		case entry.Orphan():
+			owner = "(orphan)"
+		}
+		fmt.Printf("%-*s  %s\n", width, entry.Name, owner)
+	}
+}
+
+func runSandboxRm(cmd *cobra.Command, args []string) error {
+	skipConfirmation, err := confirmationSkipped(cmd, "sandbox-rm")
+	if err != nil {
+		return err
+	}
+	force, _ := cmd.Flags().GetBool("force")
+
+	entries, err := loadSandboxEntries()
+	if err != nil {
+		return err
+	}
+	targets, err := sandboxRemovalTargets(entries, args, force)
+	if err != nil {
+		return err
+	}
+	if !skipConfirmation && !confirmSandboxRemoval(targets) {
+		fmt.Println("Removal cancelled.")
+		return nil
+	}
+	return removeSandboxes(appServices().SandboxManager(), targets)
+}
+
+// sandboxRemovalTargets resolves the names given to sbs sandbox rm. Only sbs
+// sandboxes can be removed, and owned ones only with force.
+func sandboxRemovalTargets(entries []sandboxEntry, names []string, force bool) ([]sandboxEntry, error) {
+	byName := make(map[string]sandboxEntry, len(entries))
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+	var targets []sandboxEntry
+	for _, name := range names {
+		entry, ok := byName[name]
+		if !ok {
+			return nil, exitcode.Errorf(exitcode.NotFound, "no sbs sandbox named %s (see 'sbs sandbox ls')", name)
+		}
+		if entry.Owner != "" && !force {
+			return nil, exitcode.Errorf(exitcode.Validation,
+				"sandbox %s belongs to session %s; use 'sbs stop %s' or 'sbs clean', or pass --force",
+				name, entry.Owner, entry.Owner)
+		}
+		targets = append(targets, entry)
+	}
+	return targets, nil
+}
+
+func runSandboxPrune(cmd *cobra.Command, args []string) error {
+	skipConfirmation, err := confirmationSkipped(cmd, "sandbox-prune")
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	entries, err := loadSandboxEntries()
+	if err != nil {
+		return err
+	}
+	var orphans []sandboxEntry
+	for _, entry := range entries {
+		if entry.Orphan() {
+			orphans = append(orphans, entry)
+		}
+	}
+	if len(orphans) == 0 {
+		fmt.Println("No orphaned sandboxes.")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Would remove %d orphaned sandbox(es):\n", len(orphans))
+		for _, orphan := range orphans {
+			fmt.Printf("  %s\n", orphan.Name)
+		}
+		return nil
+	}
+	if !skipConfirmation && !confirmSandboxRemoval(orphans) {
+		fmt.Println("Prune cancelled.")
+		return nil
+	}
+	return removeSandboxes(appServices().SandboxManager(), orphans)
+}
+
+// confirmSandboxRemoval lists the sandboxes about to be removed and asks
+func confirmSandboxRemoval(targets []sandboxEntry) bool {
+	fmt.Printf("About to remove %d sandbox(es):\n", len(targets))
+	for _, target := range targets {
+		if target.Owner != "" {
+			fmt.Printf("  %s (session %s)\n", target.Name, target.Owner)
+		} else {
+			fmt.Printf("  %s\n", target.Name)
+		}
+	}
+	fmt.Print("Continue? [y/N]: ")
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// removeSandboxes deletes each target, going on past failures, and fails
+// when any deletion did
+func removeSandboxes(provider sandboxInventory, targets []sandboxEntry) error {
+	failed := 0
+	for _, target := range targets {
+		if err := provider.DeleteSandbox(target.Name); err != nil {
+			fmt.Printf("Failed to remove %s: %v\n", target.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Removed sandbox %s\n", target.Name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d of %d sandbox(es)", failed, len(targets))
+	}
+	return nil
+}
+
+// sandboxSnapshotter is a sandbox provider able to snapshot the sandboxes
+// it can see
+type sandboxSnapshotter interface {
+	sandbox.Snapshotter
+	SandboxExists(sandboxName string) (bool, error)
+}
+
+func runSandboxSnapshot(cmd *cobra.Command, args []string) error {
+	list, _ := cmd.Flags().GetBool("list")
+
+	workItemID, err := resolveWorkItemID(args[0])
+	if err != nil {
+		return err
+	}
+	session, archived, err := findSessionOrArchived(workItemID)
+	if err != nil {
+		return err
+	}
+
+	if list {
+		if len(session.Snapshots) == 0 {
+			fmt.Printf("No snapshots recorded for %s\n", workItemID)
+			return nil
+		}
+		for _, snapshot := range session.Snapshots {
+			fmt.Printf("%-22s %s  %s\n", snapshot.Name, snapshot.CreatedAt, snapshot.Reason)
+		}
+		return nil
+	}
+	if archived {
+		return exitcode.Wrap(exitcode.NotFound, fmt.Errorf("session %s was cleaned; its sandbox can only be restored", workItemID))
+	}
+
+	name := ""
+	if len(args) > 1 {
+		name = args[1]
+	}
+	snapshot, err := takeSnapshot(sessionSandboxManager(*session), session, name, config.SnapshotReasonManual)
+	if err != nil {
+		return err
+	}
+	if err := updateSession(workItemID, func(s *config.SessionMetadata) {
+		s.Snapshots = append(s.Snapshots, snapshot)
+	}); err != nil {
+		return fmt.Errorf("failed to save sessions: %w", err)
+	}
+	fmt.Printf("Snapshot %s of sandbox %s taken; restore it with: sbs sandbox restore %s %s\n",
+		snapshot.Name, session.SandboxName, workItemID, snapshot.Name)
+	return nil
+}
+
+func runSandboxRestore(cmd *cobra.Command, args []string) error {
+	skipConfirmation, err := confirmationSkipped(cmd, "sandbox-restore")
+	if err != nil {
+		return err
+	}
+
+	workItemID, err := resolveWorkItemID(args[0])
+	if err != nil {
+		return err
+	}
+	snapshotName := args[1]
+	session, archived, err := findSessionOrArchived(workItemID)
+	if err != nil {
+		return err
+	}
+	if session.SandboxName == "" {
+		return fmt.Errorf("session %s has no sandbox name", workItemID)
+	}
+	if !hasSnapshot(session, snapshotName) {
+		fmt.Printf("Warning: snapshot %s is not recorded for %s; trying it anyway\n", snapshotName, workItemID)
+	}
+
+	manager := sessionSandboxManager(*session)
+	if exists, err := manager.SandboxExists(session.SandboxName); err == nil && exists && !skipConfirmation {
+		fmt.Printf("Sandbox %s exists; restoring replaces its current state. Continue? [y/N]: ", session.SandboxName)
+		response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Restore cancelled.")
+			return nil
+		}
+	}
+
+	if err := manager.RestoreSnapshot(session.SandboxName, snapshotName); err != nil {
+		return err
+	}
+	fmt.Printf("Restored sandbox %s from snapshot %s\n", session.SandboxName, snapshotName)
+	if archived {
+		fmt.Printf("Session %s was cleaned; run 'sbs start %s' to work in it again.\n", workItemID, workItemID)
+	}
+	return nil
+}
+
+// takeSnapshot snapshots a session's existing sandbox, naming the snapshot
+// after the current time when name is empty, and returns its record
+func takeSnapshot(snapshotter sandboxSnapshotter, session *config.SessionMetadata, name, reason string) (config.SandboxSnapshot, error) {
+	if session.SandboxName == "" {
+		return config.SandboxSnapshot{}, fmt.Errorf("session %s has no sandbox name", session.NamespacedID)
+	}
+	exists, err := snapshotter.SandboxExists(session.SandboxName)
+	if err != nil {
+		return config.SandboxSnapshot{}, fmt.Errorf("could not check sandbox %s: %w", session.SandboxName, err)
+	}
+	if !exists {
+		return config.SandboxSnapshot{}, fmt.Errorf("sandbox %s does not exist", session.SandboxName)
+	}
+
+	if name == "" {
+		name = sandbox.SnapshotName(config.Now())
+	}
+	if err := snapshotter.Snapshot(session.SandboxName, name); err != nil {
+		return config.SandboxSnapshot{}, err
+	}
+	return config.SandboxSnapshot{Name: name, CreatedAt: config.Timestamp(), Reason: reason}, nil
+}
+
+// snapshotBeforeClean snapshots the existing sandbox of each session about
+// to be cleaned and records the snapshot on it, so the archived session
+// keeps it. It returns the sessions to go on cleaning: a session whose
+// snapshot failed is kept rather than losing its sandbox unsaved.
+func snapshotBeforeClean(snapshotter sandboxSnapshotter, sessions []config.SessionMetadata) []config.SessionMetadata {
+	if !snapshotter.SupportsSnapshots() {
+		fmt.Printf("Warning: %v; cleaning without snapshots\n", sandbox.ErrSnapshotsUnsupported)
+		return sessions
+	}
+
+	var clean []config.SessionMetadata
+	for _, session := range sessions {
+		if session.SandboxName == "" {
+			clean = append(clean, session)
+			continue
+		}
+		exists, err := snapshotter.SandboxExists(session.SandboxName)
+		if err == nil && !exists {
+			clean = append(clean, session)
+			continue
+		}
+		snapshot, err := takeSnapshot(snapshotter, &session, "", config.SnapshotReasonClean)
+		if err != nil {
+			fmt.Printf("Skipping %s: %v\n", session.NamespacedID, err)
+			continue
+		}
+		session.Snapshots = append(session.Snapshots, snapshot)
+		if err := updateSession(session.NamespacedID, func(s *config.SessionMetadata) { s.Snapshots = session.Snapshots }); err != nil {
+			fmt.Printf("Warning: failed to record snapshot of %s: %v\n", session.NamespacedID, err)
+		}
+		fmt.Printf("Snapshot %s of sandbox %s taken; restore it with: sbs sandbox restore %s %s\n",
+			snapshot.Name, session.SandboxName, session.NamespacedID, snapshot.Name)
+		clean = append(clean, session)
+	}
+	return clean
+}
+
+// findSessionOrArchived returns the session of a work item, or its most
+// recently archived entry when it was removed, reporting which it found
+func findSessionOrArchived(workItemID string) (*config.SessionMetadata, bool, error) {
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			return &sessions[i], false, nil
+		}
+	}
+
+	archivePath, err := config.GlobalSessionsArchivePath()
+	if err != nil {
+		return nil, false, err
+	}
+	archived, err := config.LoadArchivedSessions(archivePath)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := len(archived) - 1; i >= 0; i-- {
+		if archived[i].Session.NamespacedID == workItemID {
+			return &archived[i].Session, true, nil
+		}
+	}
+	return nil, false, sessionNotFound(workItemID)
+}
+
+// hasSnapshot reports whether the session records the named snapshot
+func hasSnapshot(session *config.SessionMetadata, name string) bool {
+	for _, snapshot := range session.Snapshots {
+		if snapshot.Name == name {
+			return true
+		}
+	}
+	return false
+}
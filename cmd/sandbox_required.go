@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"sbs/pkg/config"
+	"sbs/pkg/sandbox"
+)
+
+// sandboxRequiredFor reports whether sandbox_required applies to sessions of
+// a repository: set in the global config or its .sbs/config.json
+func sandboxRequiredFor(repoRoot string) bool {
+	if appServices().SandboxManager().Required() {
+		return true
+	}
+	if repoRoot == "" {
+		return false
+	}
+	repoConfig, err := config.LoadConfigWithRepository(repoRoot)
+	return err == nil && repoConfig.SandboxRequired
+}
+
+// sessionSandboxManager returns the sandbox manager for a session, strict
+// when sandbox_required applies to its repository
+func sessionSandboxManager(session config.SessionMetadata) *sandbox.Manager {
+	return appServices().SandboxManager().WithRequired(sandboxRequiredFor(session.RepositoryRoot))
+}
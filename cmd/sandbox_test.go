@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/testsupport"
+)
+
+func TestTakeSnapshot(t *testing.T) {
+	config.SetClock(clock.NewFake(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { config.SetClock(nil) })
+	sandboxes := testsupport.NewFakeSandboxManager("sbs-web-12")
+
+	session := &config.SessionMetadata{NamespacedID: "github:12", SandboxName: "sbs-web-12"}
+	snapshot, err := takeSnapshot(sandboxes, session, "", config.SnapshotReasonManual)
+	require.NoError(t, err)
+	assert.Equal(t, config.SandboxSnapshot{Name: "sbs-20260304-120000", CreatedAt: "2026-03-04T12:00:00Z", Reason: "manual"}, snapshot)
+
+	snapshot, err = takeSnapshot(sandboxes, session, "before-upgrade", config.SnapshotReasonManual)
+	require.NoError(t, err)
+	assert.Equal(t, "before-upgrade", snapshot.Name)
+	assert.Equal(t, []string{"sbs-web-12@sbs-20260304-120000", "sbs-web-12@before-upgrade"}, sandboxes.Snapshots)
+
+	_, err = takeSnapshot(sandboxes, &config.SessionMetadata{NamespacedID: "github:40", SandboxName: "sbs-web-40"}, "", config.SnapshotReasonManual)
+	assert.ErrorContains(t, err, "sandbox sbs-web-40 does not exist")
+}
+
+func TestSnapshotBeforeClean(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:12", SandboxName: "sbs-web-12"},
+		{NamespacedID: "github:40", SandboxName: "sbs-web-40"}, // sandbox already gone
+		{NamespacedID: "github:41"},                            // no sandbox recorded
+	}
+	require.NoError(t, config.SaveSessions(sessions))
+
+	t.Run("snapshots existing sandboxes and records them", func(t *testing.T) {
+		sandboxes := testsupport.NewFakeSandboxManager("sbs-web-12")
+		var clean []config.SessionMetadata
+		output := captureStdout(t, func() { clean = snapshotBeforeClean(sandboxes, sessions) })
+
+		require.Len(t, clean, 3)
+		require.Len(t, clean[0].Snapshots, 1, "the cleaned session carries its snapshot into the archive")
+		assert.Equal(t, config.SnapshotReasonClean, clean[0].Snapshots[0].Reason)
+		assert.Empty(t, clean[1].Snapshots)
+		assert.Contains(t, output, "sbs sandbox restore github:12 "+clean[0].Snapshots[0].Name)
+
+		stored, err := config.LoadSessions()
+		require.NoError(t, err)
+		assert.Equal(t, clean[0].Snapshots, stored[0].Snapshots)
+	})
+
+	t.Run("a failed snapshot keeps the session", func(t *testing.T) {
+		sandboxes := testsupport.NewFakeSandboxManager("sbs-web-12")
+		sandboxes.SnapshotErr = errors.New("disk full")
+		var clean []config.SessionMetadata
+		output := captureStdout(t, func() { clean = snapshotBeforeClean(sandboxes, sessions) })
+
+		assert.Len(t, clean, 2)
+		assert.Equal(t, "github:40", clean[0].NamespacedID)
+		assert.Contains(t, output, "Skipping github:12: disk full")
+	})
+
+	t.Run("without snapshot support cleaning goes ahead", func(t *testing.T) {
+		sandboxes := testsupport.NewFakeSandboxManager("sbs-web-12")
+		sandboxes.NoSnapshots = true
+		var clean []config.SessionMetadata
+		output := captureStdout(t, func() { clean = snapshotBeforeClean(sandboxes, sessions) })
+
+		assert.Len(t, clean, 3)
+		assert.Contains(t, output, "does not support snapshots")
+	})
+}
+
+func TestListSandboxEntries(t *testing.T) {
+	pool := sandbox.PoolSandboxName(1)
+	sandboxes := testsupport.NewFakeSandboxManager("sbs-web-12", "sbs-web-40", "sbs-github:7", pool)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:12", SandboxName: "sbs-web-12"},
+		{NamespacedID: "github:7"}, // recorded before sandbox names were
+	}
+
+	entries, err := listSandboxEntries(sandboxes, sessions)
+	require.NoError(t, err)
+	assert.Equal(t, []sandboxEntry{
+		{Name: "sbs-github:7", Owner: "github:7"},
+		{Name: pool, Pool: true},
+		{Name: "sbs-web-12", Owner: "github:12"},
+		{Name: "sbs-web-40"},
+	}, entries)
+	assert.True(t, entries[3].Orphan())
+	assert.False(t, entries[1].Orphan(), "warm pool sandboxes are not orphans")
+
+	output := captureStdout(t, func() { printSandboxEntries(entries) })
+	assert.Contains(t, output, "sbs-web-12    github:12\n")
+	assert.Contains(t, output, "sbs-web-40    (orphan)\n")
+	assert.Contains(t, output, pool)
+}
+
+func TestSandboxRemovalTargets(t *testing.T) {
+	entries := []sandboxEntry{
+		{Name: "sbs-web-12", Owner: "github:12"},
+		{Name: "sbs-web-40"},
+	}
+
+	targets, err := sandboxRemovalTargets(entries, []string{"sbs-web-40"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []sandboxEntry{{Name: "sbs-web-40"}}, targets)
+
+	_, err = sandboxRemovalTargets(entries, []string{"sbs-web-12"}, false)
+	assert.ErrorContains(t, err, "belongs to session github:12")
+	assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+
+	targets, err = sandboxRemovalTargets(entries, []string{"sbs-web-12"}, true)
+	require.NoError(t, err)
+	assert.Len(t, targets, 1)
+
+	_, err = sandboxRemovalTargets(entries, []string{"my-own-sandbox"}, true)
+	assert.Equal(t, exitcode.NotFound, exitcode.Of(err), "only sbs sandboxes can be removed")
+}
+
+func TestRemoveSandboxes(t *testing.T) {
+	sandboxes := testsupport.NewFakeSandboxManager("sbs-web-40", "sbs-web-41")
+	output := captureStdout(t, func() {
+		require.NoError(t, removeSandboxes(sandboxes, []sandboxEntry{{Name: "sbs-web-40"}, {Name: "sbs-web-41"}}))
+	})
+	assert.Equal(t, []string{"sbs-web-40", "sbs-web-41"}, sandboxes.Deleted)
+	assert.Contains(t, output, "Removed sandbox sbs-web-41")
+
+	sandboxes.DeleteErr = errors.New("busy")
+	var err error
+	captureStdout(t, func() { err = removeSandboxes(sandboxes, []sandboxEntry{{Name: "sbs-web-42"}}) })
+	assert.ErrorContains(t, err, "failed to remove 1 of 1 sandbox(es)")
+}
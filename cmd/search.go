@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/validation"
+)
+
+const defaultSearchLimit = 20
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the repository's input source for work items",
+	Long: `Search the current repository's input source for work items matching a
+query and show which of them already have a session:
+
+  sbs search "flaky login"
+  sbs search login --limit 5
+
+With --start, the match is started as with 'sbs start'. A single match starts
+straight away; with several, sbs asks which one to start:
+
+  sbs search "flaky login" --start
+
+The query is passed to the input source as-is, so GitHub search qualifiers
+such as "label:bug" work too.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().IntP("limit", "n", defaultSearchLimit, "Maximum number of matches to show")
+	searchCmd.Flags().Bool("start", false, "Start a session for the selected match")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	startMatch, _ := cmd.Flags().GetBool("start")
+	if limit <= 0 {
+		return exitcode.Errorf(exitcode.Validation, "--limit must be positive, got %d", limit)
+	}
+	query := strings.Join(args, " ")
+
+	currentRepo, err := appServices().Repository()
+	if err != nil {
+		return fmt.Errorf("failed to find repository: %w", err)
+	}
+	source, err := inputsource.NewInputSourceFactory().CreateFromProject(currentRepo.Root)
+	if err != nil {
+		return fmt.Errorf("failed to create input source: %w", err)
+	}
+	if err := validation.CheckInputSourceAuth(source.GetType()); err != nil {
+		return err
+	}
+
+	matches, err := source.ListWorkItems(query, limit)
+	if err != nil {
+		return fmt.Errorf("failed to search %s work items: %w", source.GetType(), err)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No %s work items match %q\n", source.GetType(), query)
+		return nil
+	}
+
+	sessions, err := config.LoadAllRepositorySessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	printSearchResults(os.Stdout, matches, sessions)
+
+	if !startMatch {
+		return nil
+	}
+	selected, err := selectSearchMatch(matches)
+	if err != nil || selected == nil {
+		return err
+	}
+	fmt.Println()
+	return runStart(startCmd, []string{selected.FullID()})
+}
+
+// printSearchResults prints one numbered line per match with whether it has
+// a session
+func printSearchResults(w io.Writer, matches []*inputsource.WorkItem, sessions []config.SessionMetadata) {
+	idWidth := len("ID")
+	for _, match := range matches {
+		idWidth = max(idWidth, len(match.FullID()))
+	}
+
+	fmt.Fprintf(w, "%3s  %-*s  %-18s  %s\n", "#", idWidth, "ID", "SESSION", "TITLE")
+	for i, match := range matches {
+		fmt.Fprintf(w, "%3d  %-*s  %-18s  %s\n", i+1, idWidth, match.FullID(), searchSessionStatus(match, sessions), match.Title)
+	}
+}
+
+// searchSessionStatus describes the session a work item has, if any
+func searchSessionStatus(match *inputsource.WorkItem, sessions []config.SessionMetadata) string {
+	for _, session := range sessions {
+		if session.NamespacedID != match.FullID() {
+			continue
+		}
+		if session.Status == "" {
+			return "has session"
+		}
+		return fmt.Sprintf("has session (%s)", session.Status)
+	}
+	return "no session"
+}
+
+// selectSearchMatch picks the match to start: the only one, or the one the
+// user chooses. It returns nil when the user cancels.
+func selectSearchMatch(matches []*inputsource.WorkItem) (*inputsource.WorkItem, error) {
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("%d work items match; narrow the query or run 'sbs start <id>'", len(matches))
+	}
+	fmt.Printf("Start which match? (1-%d, Enter to cancel): ", len(matches))
+	return readSearchChoice(bufio.NewReader(os.Stdin), matches)
+}
+
+// readSearchChoice reads a 1-based match number; an empty answer cancels
+func readSearchChoice(reader *bufio.Reader, matches []*inputsource.WorkItem) (*inputsource.WorkItem, error) {
+	response, err := reader.ReadString('\n')
+	if err != nil && response == "" {
+		return nil, fmt.Errorf("failed to read selection: %w", err)
+	}
+	response = strings.TrimSpace(response)
+	if response == "" {
+		fmt.Println("Search selection cancelled.")
+		return nil, nil
+	}
+	choice, err := strconv.Atoi(response)
+	if err != nil || choice < 1 || choice > len(matches) {
+		return nil, fmt.Errorf("invalid selection %q; expected a number from 1 to %d", response, len(matches))
+	}
+	return matches[choice-1], nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+func searchMatches() []*inputsource.WorkItem {
+	return []*inputsource.WorkItem{
+		{Source: "github", ID: "12", Title: "Flaky login test"},
+		{Source: "github", ID: "345", Title: "Login redirects twice"},
+	}
+}
+
+func TestPrintSearchResults_ShowsSessionStatus(t *testing.T) {
+	sessions := []config.SessionMetadata{{NamespacedID: "github:345", Status: "active"}}
+
+	var out bytes.Buffer
+	printSearchResults(&out, searchMatches(), sessions)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "SESSION")
+	assert.Regexp(t, `^\s+1  github:12 \s+no session\s+Flaky login test$`, lines[1])
+	assert.Regexp(t, `^\s+2  github:345\s+has session \(active\)\s+Login redirects twice$`, lines[2])
+}
+
+func TestReadSearchChoice(t *testing.T) {
+	matches := searchMatches()
+
+	selected, err := readSearchChoice(bufio.NewReader(strings.NewReader("2\n")), matches)
+	require.NoError(t, err)
+	assert.Equal(t, "github:345", selected.FullID())
+
+	captureStdout(t, func() {
+		selected, err = readSearchChoice(bufio.NewReader(strings.NewReader("\n")), matches)
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, selected, "an empty answer cancels")
+
+	_, err = readSearchChoice(bufio.NewReader(strings.NewReader("3\n")), matches)
+	assert.ErrorContains(t, err, "expected a number from 1 to 2")
+}
+
+func TestSelectSearchMatch_SingleMatchNeedsNoPrompt(t *testing.T) {
+	selected, err := selectSearchMatch(searchMatches()[:1])
+	require.NoError(t, err)
+	assert.Equal(t, "github:12", selected.FullID())
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/messages"
+)
+
+// shortIDPrefix marks a short session index such as %3, as shown by sbs list
+const shortIDPrefix = "%"
+
+// workItemIDArg returns the work item ID from the first argument, or resolves
+// it from the session whose worktree contains the current directory when the
+// argument is omitted
+func workItemIDArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return resolveWorkItemID(args[0])
+	}
+	return currentSessionWorkItemID()
+}
+
+// resolveWorkItemID expands short session indexes (%3) and the current
+// repository's aliases into work item IDs; anything else is returned as is
+func resolveWorkItemID(arg string) (string, error) {
+	if strings.HasPrefix(arg, shortIDPrefix) {
+		sessions, err := config.LoadSessions()
+		if err != nil {
+			return "", fmt.Errorf("failed to load sessions: %w", err)
+		}
+		return shortIDWorkItemID(sessions, arg)
+	}
+
+	if strings.Contains(arg, ":") {
+		return arg, nil
+	}
+
+	// Aliases are per repository; outside one there is nothing to expand
+	currentRepo, err := appServices().Repository()
+	if err != nil {
+		return arg, nil
+	}
+	aliases, err := config.LoadAliases(currentRepo.Root)
+	if err != nil {
+		return "", err
+	}
+	if target, ok := aliases[arg]; ok {
+		return target, nil
+	}
+	return arg, nil
+}
+
+// shortIDWorkItemID maps a short session index to the session at that
+// position in the sessions file, the order sbs list shows them in
+func shortIDWorkItemID(sessions []config.SessionMetadata, arg string) (string, error) {
+	index, err := strconv.Atoi(strings.TrimPrefix(arg, shortIDPrefix))
+	if err != nil || index < 1 {
+		return "", exitcode.Errorf(exitcode.Validation, "invalid short session index %s (expected e.g. %s1)", arg, shortIDPrefix)
+	}
+	if index > len(sessions) {
+		return "", exitcode.Errorf(exitcode.NotFound, "no session %s: there are %d sessions (see 'sbs list')", arg, len(sessions))
+	}
+	return sessionWorkItemID(sessions[index-1]), nil
+}
+
+// shortID returns the short session index for the session at position i
+func shortID(i int) string {
+	return shortIDPrefix + strconv.Itoa(i+1)
+}
+
+// currentSessionWorkItemID resolves the work item ID of the session whose
+// worktree contains the current directory
+func currentSessionWorkItemID() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current directory: %w", err)
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return "", fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	return sessionWorkItemIDForPath(sessions, cwd)
+}
+
+// sessionWorkItemIDForPath maps a path inside a session worktree to the
+// session's work item ID, failing if no session or more than one matches
+func sessionWorkItemIDForPath(sessions []config.SessionMetadata, path string) (string, error) {
+	matches := config.FindSessionsByWorktreePath(sessions, path)
+
+	switch len(matches) {
+	case 0:
+		return "", exitcode.Errorf(exitcode.Validation, "no work item ID given and %s is not inside a session worktree", path)
+	case 1:
+		return sessionWorkItemID(*matches[0]), nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, match := range matches {
+			ids = append(ids, sessionWorkItemID(*match))
+		}
+		return "", exitcode.Errorf(exitcode.Validation, "%s belongs to more than one session (%s); specify the work item ID", path, strings.Join(ids, ", "))
+	}
+}
+
+// sessionWorkItemID returns the ID used to address a session on the command line
+func sessionWorkItemID(session config.SessionMetadata) string {
+	if session.NamespacedID != "" {
+		return session.NamespacedID
+	}
+	return fmt.Sprintf("%d", session.IssueNumber)
+}
+
+// sessionNotFound is the error for a work item without a session
+func sessionNotFound(workItemID string) error {
+	return exitcode.Wrap(exitcode.NotFound, messages.Error(messages.SessionNotFound, messages.Args{"ID": workItemID}))
+}
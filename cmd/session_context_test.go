@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestSessionWorkItemIDForPath(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:42", WorktreePath: "/worktrees/project/issue-42"},
+		{IssueNumber: 7, WorktreePath: "/worktrees/legacy/issue-7"},
+		{NamespacedID: "github:1", WorktreePath: "/worktrees/shared"},
+		{NamespacedID: "test:dup", WorktreePath: "/worktrees/shared"},
+	}
+
+	t.Run("resolves_session_from_nested_directory", func(t *testing.T) {
+		id, err := sessionWorkItemIDForPath(sessions, "/worktrees/project/issue-42/cmd")
+		require.NoError(t, err)
+		assert.Equal(t, "github:42", id)
+	})
+
+	t.Run("legacy_session_uses_issue_number", func(t *testing.T) {
+		id, err := sessionWorkItemIDForPath(sessions, "/worktrees/legacy/issue-7")
+		require.NoError(t, err)
+		assert.Equal(t, "7", id)
+	})
+
+	t.Run("outside_worktree", func(t *testing.T) {
+		_, err := sessionWorkItemIDForPath(sessions, "/src/project")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not inside a session worktree")
+	})
+
+	t.Run("ambiguous_mapping", func(t *testing.T) {
+		_, err := sessionWorkItemIDForPath(sessions, "/worktrees/shared")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "github:1, test:dup")
+		assert.Contains(t, err.Error(), "specify the work item ID")
+	})
+}
+
+func TestWorkItemIDArg_ExplicitArgument(t *testing.T) {
+	id, err := workItemIDArg([]string{"test:explicit"})
+	require.NoError(t, err)
+	assert.Equal(t, "test:explicit", id)
+}
+
+func TestWorkItemIDArg_ImplicitFromWorktree(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	worktree := t.TempDir()
+
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{
+		{NamespacedID: "test:implicit", WorktreePath: worktree},
+	}))
+	t.Chdir(worktree)
+
+	id, err := workItemIDArg(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "test:implicit", id)
+
+	// A single comment argument is the message when inside a worktree
+	workItemID, messageArgs, err := splitCommentArgs([]string{"Tests are green"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "test:implicit", workItemID)
+	assert.Equal(t, []string{"Tests are green"}, messageArgs)
+}
+
+func TestSplitCommentArgs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	workItemID, messageArgs, err := splitCommentArgs([]string{"github:1", "hello"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "github:1", workItemID)
+	assert.Equal(t, []string{"hello"}, messageArgs)
+
+	workItemID, messageArgs, err = splitCommentArgs([]string{"github:1"}, "notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "github:1", workItemID)
+	assert.Empty(t, messageArgs)
+
+	// Outside a worktree a single argument is still the work item ID
+	workItemID, messageArgs, err = splitCommentArgs([]string{"github:1"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "github:1", workItemID)
+	assert.Empty(t, messageArgs)
+
+	_, _, err = splitCommentArgs(nil, "notes.md")
+	assert.Error(t, err)
+}
+
+func TestShortIDWorkItemID(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1234"},
+		{NamespacedID: "test:quick"},
+	}
+
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr string
+	}{
+		{arg: "%1", want: "github:1234"},
+		{arg: "%2", want: "test:quick"},
+		{arg: "%3", wantErr: "there are 2 sessions"},
+		{arg: "%0", wantErr: "invalid short session index"},
+		{arg: "%x", wantErr: "invalid short session index"},
+	}
+
+	for _, tt := range tests {
+		got, err := shortIDWorkItemID(sessions, tt.arg)
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, tt.arg)
+			continue
+		}
+		require.NoError(t, err, tt.arg)
+		assert.Equal(t, tt.want, got)
+	}
+
+	assert.Equal(t, "%1", shortID(0))
+}
+
+func TestResolveWorkItemID(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{{NamespacedID: "test:first"}}))
+
+	id, err := resolveWorkItemID("%1")
+	require.NoError(t, err)
+	assert.Equal(t, "test:first", id)
+
+	// Namespaced IDs are never treated as aliases
+	id, err = resolveWorkItemID("github:99")
+	require.NoError(t, err)
+	assert.Equal(t, "github:99", id)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/buildcache"
+	"sbs/pkg/config"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show [work-item-id]",
+	Short: "Show the details of a work session",
+	Long: `Show everything sbs knows about a work session: branch, worktree, tmux
+session, sandbox and the shared build caches wired into it.
+
+Work item ID formats:
+  sbs show 123             # Primary work type
+  sbs show test:my-test    # Test work type
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runShow,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	collectInbox(sessions)
+
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			fmt.Print(formatSessionDetails(&sessions[i]))
+			return nil
+		}
+	}
+	return sessionNotFound(workItemID)
+}
+
+// formatSessionDetails renders a session as aligned "field: value" lines
+func formatSessionDetails(session *config.SessionMetadata) string {
+	var b strings.Builder
+	field := func(name, value string) {
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(&b, "%-12s %s\n", name+":", value)
+	}
+
+	field("Work item", session.NamespacedID)
+	field("Title", session.IssueTitle)
+	field("Status", session.Status)
+	field("Repository", session.RepositoryName)
+	field("Branch", session.Branch)
+	field("Worktree", session.WorktreePath)
+	field("Tmux", session.TmuxSession)
+	field("Sandbox", session.SandboxName)
+	field("Created", session.CreatedAt)
+	field("Activity", session.LastActivity)
+	if session.PullRequestURL != "" {
+		field("PR", session.PullRequestURL)
+	}
+	if session.PullRequestURL != "" || session.RemoteStatus != "" {
+		remote := session.RemoteStatus
+		if session.RemoteMessage != "" {
+			remote += ": " + session.RemoteMessage
+		}
+		if session.RemoteURL != "" {
+			remote += " " + session.RemoteURL
+		}
+		field("Remote", remote)
+	}
+
+	for _, snapshot := range session.Snapshots {
+		field("Snapshot", fmt.Sprintf("%s (%s, %s)", snapshot.Name, snapshot.CreatedAt, snapshot.Reason))
+	}
+
+	if len(session.BuildCaches) == 0 {
+		field("Caches", "none")
+	} else {
+		fmt.Fprintln(&b, "Caches:")
+		for _, cache := range session.BuildCaches {
+			fmt.Fprintf(&b, "  %s\n", buildcache.Describe(cache))
+		}
+	}
+	return b.String()
+}
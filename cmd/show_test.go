@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+func TestShowCommand_Structure(t *testing.T) {
+	assert.NoError(t, showCmd.Args(showCmd, []string{}))
+	assert.NoError(t, showCmd.Args(showCmd, []string{"github:1"}))
+	assert.Error(t, showCmd.Args(showCmd, []string{"github:1", "extra"}))
+}
+
+func TestFormatSessionDetails(t *testing.T) {
+	session := &config.SessionMetadata{
+		NamespacedID: "github:42",
+		IssueTitle:   "Speed up CI",
+		Status:       "active",
+		Branch:       "issue-github-42-speed-up-ci",
+		BuildCaches: []config.BuildCacheEntry{
+			{Name: "go", Env: "GOCACHE", Path: "/home/dev/.cache/sbs/go"},
+			{Name: "npm", Env: "npm_config_cache", Path: "/home/dev/.cache/sbs/npm", Mount: "/cache/npm"},
+		},
+	}
+
+	output := formatSessionDetails(session)
+	assert.Contains(t, output, "Work item:   github:42\n")
+	assert.Contains(t, output, "Sandbox:     -\n")
+	assert.Contains(t, output, "Caches:\n  go: GOCACHE=/home/dev/.cache/sbs/go\n  npm: npm_config_cache=/cache/npm (mounted from /home/dev/.cache/sbs/npm)\n")
+
+	assert.NotContains(t, output, "PR:")
+
+	session.BuildCaches = nil
+	assert.Contains(t, formatSessionDetails(session), "Caches:      none\n")
+
+	session.PullRequestURL = "https://github.com/o/r/pull/7"
+	session.RemoteStatus = "checks pending"
+	output = formatSessionDetails(session)
+	assert.Contains(t, output, "PR:          https://github.com/o/r/pull/7\nRemote:      checks pending\n")
+
+	session.PullRequestURL = ""
+	session.RemoteStatus, session.RemoteMessage, session.RemoteURL = "ci failed", "unit tests failed", "https://ci/42"
+	output = formatSessionDetails(session)
+	assert.NotContains(t, output, "PR:")
+	assert.Contains(t, output, "Remote:      ci failed: unit tests failed https://ci/42\n", "a status pushed into the inbox shows without a PR")
+
+	session.Snapshots = []config.SandboxSnapshot{{Name: "sbs-20260304-120000", CreatedAt: "2026-03-04T12:00:00Z", Reason: "before clean"}}
+	assert.Contains(t, formatSessionDetails(session), "Snapshot:    sbs-20260304-120000 (2026-03-04T12:00:00Z, before clean)\n")
+}
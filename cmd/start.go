@@ -1,21 +1,53 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sbs/pkg/bootstrap"
+	"sbs/pkg/branchname"
+	"sbs/pkg/buildcache"
 	"sbs/pkg/config"
+	"sbs/pkg/events"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/faultinject"
 	"sbs/pkg/git"
 	"sbs/pkg/inputsource"
 	"sbs/pkg/issue"
+	"sbs/pkg/messages"
+	"sbs/pkg/naming"
+	"sbs/pkg/oplock"
+	"sbs/pkg/provisioning"
 	"sbs/pkg/repo"
+	"sbs/pkg/sessiondoc"
 	"sbs/pkg/tmux"
+	"sbs/pkg/trace"
 	"sbs/pkg/tui"
+	"sbs/pkg/validation"
+)
+
+// Provisioning steps without an injectable fault of their own
+const (
+	stepWorktreeFiles = "files"
+	stepBuildCaches   = "caches"
+	stepSessionDoc    = "session-doc"
+)
+
+// Steps after provisioning, as named in --events-json output
+const (
+	stepCommand   = "command"
+	stepReadiness = "readiness"
 )
 
 var startCmd = &cobra.Command{
@@ -25,6 +57,7 @@ var startCmd = &cobra.Command{
 
 Work item ID formats:
   sbs start 123              # Primary work type (github, jira, etc.)
+  sbs start jira:ABC-123     # Jira issue (input source jira, sources.jira or sbs-source-jira)
   sbs start test:my-test     # Test work item with custom ID
   sbs start test:feature-x   # Test work item for feature development
   sbs start test:debugging   # Test work item for debugging
@@ -36,7 +69,33 @@ This command will:
 1. Create/switch to a work item branch (issue-{source}-{id}-{slug})
 2. Create/use a worktree in ~/.sbs-worktrees/
 3. Create/attach to a tmux session (sbs-{source}-{id})
-4. Execute .sbs/start script if it exists
+4. Write .sbs/SESSION.md into the worktree describing the work item and the
+   sbs commands for it (kept out of git status via info/exclude)
+5. Execute .sbs/start script if it exists
+
+A configured tmux_command (or --command) whose executable can't be found
+locally or in the sandbox is reported before it is sent to the session. Use
+--validate-only to check the configuration without starting anything:
+  sbs start --validate-only
+
+After launching the command, sbs start waits for the repository's
+readiness_checks (a command exiting 0, a port accepting connections, a file
+appearing) and warns with the session's last output when they fail or the
+tmux session exits. --skip-readiness skips the wait.
+
+With --events-json, progress is streamed to stdout as newline-delimited JSON
+events (step-started, step-completed, warning, then ready or failed) and the
+human-readable output goes to stderr. An already running session is reported
+as ready with "existing": true instead of being attached.
+
+Re-running sbs start on a running session attaches to it. If the session
+command has exited (its first pane is back at the shell, or dead), it is run
+there again first; --resume attaches without restarting it. --restart runs the
+command in a new "restart" window instead, leaving the first one alone:
+  sbs start 123 --restart
+
+If the work item already has a session in another repository, sbs start offers
+to attach to that session instead of creating a second environment here.
 
 Input sources are configured via .sbs/input-source.json in your project root.
 Test work types (test:*) are always available and accept any custom ID regardless of project configuration.`,
@@ -50,26 +109,62 @@ func init() {
 	startCmd.Flags().String("command", "", "Custom command to run in tmux session")
 	startCmd.Flags().Bool("no-command", false, "Start session without executing any command")
 	startCmd.Flags().BoolP("verbose", "v", false, "Enable verbose debug output")
+	startCmd.Flags().Bool("validate-only", false, "Check the configured tmux command and exit without starting a session")
+	startCmd.Flags().Bool("restart", false, "Run the session command again in a new window of the already running session")
+	startCmd.Flags().Bool("skip-readiness", false, "Don't wait for readiness_checks before declaring the session ready")
+	startCmd.Flags().Bool("events-json", false, "Stream progress as newline-delimited JSON events on stdout (human output goes to stderr)")
+	addWaitFlags(startCmd)
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
+	eventsJSON, _ := cmd.Flags().GetBool("events-json")
+	if !eventsJSON {
+		return startWorkItem(cmd, args)
+	}
+
+	restore := beginEventStream()
+	defer restore()
+	err := startWorkItem(cmd, args)
+	if err != nil {
+		startEvents.Emit(events.Event{Type: events.Failed, Error: err.Error()})
+	}
+	return err
+}
+
+// startWorkItem does the work of sbs start
+func startWorkItem(cmd *cobra.Command, args []string) error {
 	resume, _ := cmd.Flags().GetBool("resume")
 	customCommand, _ := cmd.Flags().GetString("command")
 	noCommand, _ := cmd.Flags().GetBool("no-command")
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	validateOnly, _ := cmd.Flags().GetBool("validate-only")
+	skipReadiness, _ := cmd.Flags().GetBool("skip-readiness")
+	restart, _ := cmd.Flags().GetBool("restart")
+	if restart && (resume || noCommand) {
+		return exitcode.Errorf(exitcode.Validation, "--restart can't be combined with --resume or --no-command")
+	}
 
 	// Initialize repository context first (required for both modes)
-	repoManager := repo.NewManager()
-	currentRepo, err := repoManager.DetectCurrentRepository()
+	currentRepo, err := appServices().Repository()
 	if err != nil {
 		return fmt.Errorf("must be run from within a git repository: %w", err)
 	}
+	if verbose && currentRepo.WorktreeRoot != "" {
+		fmt.Printf("Debug: Running from worktree %s, using parent repository %s\n", currentRepo.WorktreeRoot, currentRepo.Root)
+	}
 
 	// Load repository-aware configuration
 	repoConfig, err := config.LoadConfigWithRepository(currentRepo.Root)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	traceRepoConfig(currentRepo.Root, repoConfig)
+	if validateOnly && startEvents != nil {
+		return exitcode.Errorf(exitcode.Validation, "--events-json can't be combined with --validate-only")
+	}
+	if validateOnly {
+		return runValidateOnly(currentRepo.Root, repoConfig, customCommand, noCommand)
+	}
 
 	// Create input source factory and load project-specific input source
 	factory := inputsource.NewInputSourceFactory()
@@ -78,8 +173,8 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create input source: %w", err)
 	}
 
-	// Load input source configuration (not used in simplified architecture, but kept for future extensions)
-	_, err = config.LoadInputSourceConfig(currentRepo.Root)
+	// Load input source configuration for sources other than the primary one
+	inputSourceConfig, err := config.LoadInputSourceConfig(currentRepo.Root)
 	if err != nil {
 		return fmt.Errorf("failed to load input source config: %w", err)
 	}
@@ -91,32 +186,40 @@ func runStart(cmd *cobra.Command, args []string) error {
 	// Parse work item ID - either from args or interactive selection
 	var workItem *inputsource.WorkItem
 
+	if len(args) == 0 && startEvents != nil {
+		return exitcode.Errorf(exitcode.Validation, "--events-json needs a work item ID; interactive selection isn't available")
+	}
 	if len(args) == 0 {
 		// No arguments provided - launch interactive work item selection
+		if err := validation.CheckInputSourceAuth(inputSourceInstance.GetType()); err != nil {
+			return err
+		}
 		selectedWorkItem, err := runInteractiveWorkItemSelection(inputSourceInstance)
 		if err != nil {
 			return fmt.Errorf("failed to select work item: %w", err)
 		}
 		if selectedWorkItem == nil {
 			// User quit the selection
-			fmt.Println("Work item selection cancelled.")
+			fmt.Println(messages.Render(messages.StartSelectionCancelled, nil))
 			return nil
 		}
 		workItem = selectedWorkItem
 
-		fmt.Printf("Selected work item %s: %s\n", workItem.FullID(), workItem.Title)
+		fmt.Println(messages.Render(messages.StartSelected, messages.Args{"ID": workItem.FullID(), "Title": workItem.Title}))
 	} else {
-		// Work item ID provided as argument
-		workItemIDStr := args[0]
+		// Work item ID provided as argument, possibly as an alias or short index
+		workItemIDStr, err := resolveWorkItemID(args[0])
+		if err != nil {
+			return err
+		}
 
 		// Parse the work item ID - support both namespaced (test:*) and simple formats
-		if strings.HasPrefix(workItemIDStr, "test:") {
-			// Test work type - use namespaced parsing
-			parsedWorkItem, err := inputsource.ParseWorkItemID(workItemIDStr)
-			if err != nil {
-				return fmt.Errorf("invalid test work item ID: %s (%w)", workItemIDStr, err)
-			}
+		parsedWorkItem, err := parseStartWorkItemID(workItemIDStr, inputSourceInstance.GetType(), inputSourceConfig)
+		if err != nil {
+			return exitcode.Wrap(exitcode.Validation, err)
+		}
 
+		if parsedWorkItem.Source == "test" {
 			if verbose {
 				fmt.Printf("Debug: Using test work item for validation in %s project\n", inputSourceInstance.GetType())
 			}
@@ -128,23 +231,38 @@ func runStart(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("failed to get test work item %s: %w", parsedWorkItem.FullID(), err)
 			}
 		} else {
-			// Primary work type - use simple ID format (no namespace required)
-			workItem, err = inputSourceInstance.GetWorkItem(workItemIDStr)
+			workItem, err = fetchStartWorkItem(factory, inputSourceInstance, inputSourceConfig, parsedWorkItem)
 			if err != nil {
-				return fmt.Errorf("failed to get work item %s from %s source: %w", workItemIDStr, inputSourceInstance.GetType(), err)
+				return err
 			}
 		}
 	}
 
+	startEvents.SetWorkItem(workItem.FullID())
+
+	// Claim the work item so a concurrent stop or clean can't remove it mid-start
+	lock, err := lockWorkItem(cmd, workItem.FullID(), oplock.OpStart)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	// Initialize managers
-	gitManager, err := git.NewManager(currentRepo.Root)
+	gitManager, err := appServices().GitManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize git manager: %w", err)
+		return err
 	}
 
-	tmuxManager := tmux.NewManager()
+	tmuxManager := appServices().TmuxManager()
 	// issueTracker := issue.NewTracker(repoConfig) // Not needed for new implementation
 
+	// With sandbox_required, never start a session that would run unsandboxed
+	if repoConfig.SandboxRequired {
+		if err := appServices().SandboxManager().WithRequired(true).CheckAvailable(); err != nil {
+			return fmt.Errorf("refusing to start %s: %w (see 'sbs doctor')", workItem.FullID(), err)
+		}
+	}
+
 	// Load global sessions
 	sessionsPath, err := config.GetGlobalSessionsPath()
 	if err != nil {
@@ -158,8 +276,12 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 	// Check if session already exists by namespaced ID
 	existingSession := findSessionByWorkItem(sessions, workItem)
+	if existingSession != nil && isOtherRepository(existingSession, currentRepo) {
+		// Session IDs are global, so starting here would replace that session
+		return resolveWrongRepository(tmuxManager, existingSession, currentRepo, workItem)
+	}
 	if existingSession != nil {
-		fmt.Printf("Found existing session for work item %s\n", workItem.FullID())
+		fmt.Println(messages.Render(messages.StartFoundExisting, messages.Args{"ID": workItem.FullID()}))
 
 		// Check if tmux session exists
 		sessionExists, err := tmuxManager.SessionExists(existingSession.TmuxSession)
@@ -167,29 +289,76 @@ func runStart(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to check tmux session: %w", err)
 		}
 
+		if sessionExists && !resume {
+			sandboxName := existingSession.SandboxName
+			if sandboxName == "" {
+				sandboxName = generateWorkItemSandboxName(currentRepo, workItem)
+			}
+			commandLine, _ := sessionCommandLine(customCommand, noCommand, repoConfig, workItem, sandboxName, currentRepo.Root)
+			switch {
+			case restart && commandLine == "":
+				return messages.Error(messages.StartNothingToRestart, messages.Args{"ID": workItem.FullID()})
+			case restart:
+				if err := restartInNewWindow(tmuxManager, existingSession, commandLine); err != nil {
+					return err
+				}
+			case commandLine != "":
+				if _, err := restartIdlePrimaryPane(tmuxManager, existingSession, commandLine); errors.Is(err, tmux.ErrForeignSession) {
+					return err
+				} else if err != nil {
+					startWarningf("Couldn't check whether the session command is still running: %v", err)
+				}
+			}
+		}
+		if sessionExists {
+			// Starting a session counts as using it, postponing its expiry
+			if err := updateSession(workItem.FullID(), func(session *config.SessionMetadata) {
+				session.LastActivity = config.Timestamp()
+			}); err != nil {
+				startWarningf("Failed to record the session's last activity: %v", err)
+			}
+		}
+		if sessionExists && startEvents != nil {
+			// Wrappers attach themselves; report the running session instead
+			startEvents.Emit(events.Event{Type: events.Ready, Session: &events.Session{
+				TmuxSession:  existingSession.TmuxSession,
+				WorktreePath: existingSession.WorktreePath,
+				Branch:       existingSession.Branch,
+				SandboxName:  existingSession.SandboxName,
+				Existing:     true,
+				Ready:        true,
+			}})
+			return nil
+		}
 		if sessionExists {
-			fmt.Printf("Attaching to existing tmux session: %s\n", existingSession.TmuxSession)
+			fmt.Println(messages.Render(messages.StartAttaching, messages.Args{"TmuxSession": existingSession.TmuxSession}))
+			lock.Release() // Don't hold the work item for as long as the attach lasts
 			return tmuxManager.AttachToSession(existingSession.TmuxSession)
 		} else {
-			fmt.Printf("Tmux session not found, recreating...\n")
+			fmt.Println(messages.Render(messages.StartRecreating, nil))
 		}
 	}
 
-	fmt.Printf("Working on work item %s: %s\n", workItem.FullID(), workItem.Title)
+	fmt.Println(messages.Render(messages.StartWorkingOn, messages.Args{"ID": workItem.FullID(), "Title": workItem.Title}))
+
+	// A new session takes the settings in the work item's sbs block
+	var issueBlock bootstrap.Block
+	if existingSession == nil {
+		issueBlock = issueBootstrap(repoConfig, workItem)
+	}
+	commandSource := "--command"
+	if customCommand == "" && !noCommand && issueBlock.Command != "" {
+		customCommand = issueBlock.Command
+		commandSource = "sbs block"
+	}
 
 	// Use namespaced branch naming
 	branch := workItem.GetBranchName()
-	// Create the branch using the new name
-	err = createWorkItemBranch(gitManager, branch)
+	trace.Decision("branch", branch, "branch_template "+branchname.Template())
 	if verbose {
 		fmt.Printf("Debug: Using namespaced branch naming: %s\n", branch)
 	}
 
-	if err != nil {
-		return fmt.Errorf("failed to create work item branch: %w", err)
-	}
-	fmt.Printf("Using branch: %s\n", branch)
-
 	// Generate friendly title for sandbox environment
 	friendlyTitle := generateWorkItemFriendlyTitle(currentRepo.Name, workItem)
 	fmt.Printf("Friendly title: %s\n", friendlyTitle)
@@ -202,106 +371,316 @@ func runStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Debug: Repository root: %s\n", currentRepo.Root)
 	}
 
-	if err := gitManager.CreateWorktree(branch, worktreePath); err != nil {
-		return fmt.Errorf("failed to create worktree: %w", err)
-	}
-	fmt.Printf("Worktree created at: %s\n", worktreePath)
-
 	// Create environment variables for tmux session
 	tmuxEnv := tmux.CreateTmuxEnvironment(friendlyTitle)
-
-	// Create tmux session with work item-specific name
 	tmuxSessionName := generateWorkItemTmuxSessionName(currentRepo, workItem)
-	session, err := createWorkItemTmuxSession(tmuxManager, workItem, worktreePath, tmuxSessionName, tmuxEnv)
-	if err != nil {
-		return fmt.Errorf("failed to create tmux session: %w", err)
-	}
-	fmt.Printf("Tmux session created: %s (SBS_TITLE=%s)\n", session.Name, friendlyTitle)
 
 	// Get work item-specific sandbox name
 	sandboxName := generateWorkItemSandboxName(currentRepo, workItem)
 
-	// Create session metadata with input source information
-	sessionMetadata := createWorkItemSessionMetadata(workItem, branch, worktreePath, session.Name,
-		sandboxName, currentRepo.Name, currentRepo.Root, friendlyTitle)
-
-	// Update sessions list
+	// A recreated session keeps the names it was recorded with, so changing
+	// name_scope doesn't orphan its sandbox; 'sbs migrate-names' moves it
+	nameSource := "generated"
 	if existingSession != nil {
-		// Update existing session by namespaced ID
-		for i, s := range sessions {
-			if s.NamespacedID == workItem.FullID() {
-				sessions[i] = *sessionMetadata
-				break
+		if existingSession.TmuxSession != "" {
+			tmuxSessionName = existingSession.TmuxSession
+		}
+		if existingSession.SandboxName != "" {
+			sandboxName = existingSession.SandboxName
+		}
+		nameSource = "existing session"
+	}
+	// The worktree stays where it is, e.g. after 'sbs move'
+	worktreeSource := "worktree_base_path"
+	if existingSession != nil && existingSession.WorktreePath != "" {
+		worktreePath = existingSession.WorktreePath
+		worktreeSource = "existing session"
+	}
+	trace.Decision("worktree path", worktreePath, worktreeSource)
+	trace.Decision("tmux session", tmuxSessionName, nameSource)
+	trace.Decision("sandbox", sandboxName, nameSource)
+
+	// Provision the session. Steps start as soon as their dependencies are
+	// done, so tmux setup overlaps copying files into the new worktree and a
+	// prewarmed sandbox is claimed alongside everything else.
+	var buildCaches []config.BuildCacheEntry
+	var session *tmux.Session
+
+	// Let other sbs processes (the TUI) show that this session is starting
+	progressBoard := appServices().ProgressBoard()
+	if progressBoard != nil {
+		_ = progressBoard.Update(provisioning.Progress{WorkItem: workItem.FullID(), Step: "starting"})
+		defer progressBoard.Clear(workItem.FullID())
+	}
+
+	steps := []provisioning.Step{
+		{Name: faultinject.StepBranchCreate, Run: func(ctx context.Context) error {
+			if err := createWorkItemBranch(gitManager, branch, issueBlock.Base); err != nil {
+				return fmt.Errorf("failed to create work item branch: %w", err)
+			}
+			fmt.Printf("Using branch: %s\n", branch)
+			return nil
+		}},
+		{Name: faultinject.StepWorktreeAdd, DependsOn: []string{faultinject.StepBranchCreate}, Run: func(ctx context.Context) error {
+			checkout := newCheckoutProgress(progressBoard, workItem.FullID())
+			err := gitManager.CreateWorktreeWithProgress(branch, worktreePath, checkout.report)
+			checkout.finish()
+			if err != nil {
+				return fmt.Errorf("failed to create worktree: %w", err)
+			}
+			fmt.Printf("Worktree created at: %s\n", worktreePath)
+			return nil
+		}},
+		{Name: stepWorktreeFiles, DependsOn: []string{faultinject.StepWorktreeAdd}, Run: func(ctx context.Context) error {
+			// Bring over ignored files (e.g. .env) listed in copy_from_main
+			provisionWorktreeFiles(repoConfig, currentRepo.Root, worktreePath, verbose)
+			return nil
+		}},
+		{Name: stepSessionDoc, DependsOn: []string{faultinject.StepWorktreeAdd}, Run: func(ctx context.Context) error {
+			// Describe the session in .sbs/SESSION.md for whoever lands in the worktree
+			doc := createWorkItemSessionMetadata(workItem, branch, worktreePath, tmuxSessionName,
+				sandboxName, currentRepo.Name, currentRepo.Root, friendlyTitle)
+			if err := sessiondoc.Write(*doc); err != nil {
+				startWarningf("%s not written: %v", sessiondoc.RelativePath, err)
+			}
+			return nil
+		}},
+		{Name: stepBuildCaches, Run: func(ctx context.Context) error {
+			buildCaches = wireBuildCaches(repoConfig, tmuxEnv, verbose)
+			return nil
+		}},
+		{Name: faultinject.StepTmuxCreate, DependsOn: []string{faultinject.StepWorktreeAdd, stepBuildCaches}, Run: func(ctx context.Context) error {
+			if progressBoard != nil {
+				_ = progressBoard.Update(provisioning.Progress{WorkItem: workItem.FullID(), Step: faultinject.StepTmuxCreate})
+			}
+			// Create tmux session with work item-specific name
+			created, err := createWorkItemTmuxSession(tmuxManager, workItem, worktreePath, tmuxSessionName, tmuxEnv)
+			if err != nil {
+				return fmt.Errorf("failed to create tmux session: %w", err)
+			}
+			session = created
+			fmt.Printf("Tmux session created: %s (SBS_TITLE=%s)\n", session.Name, friendlyTitle)
+			return nil
+		}},
+	}
+	if !resume && !noCommand && repoConfig.SandboxPoolSize > 0 {
+		// Hand a prewarmed sandbox to the session before its first sandbox command runs
+		steps = append(steps, provisioning.Step{Name: faultinject.StepSandboxCreate, Run: func(ctx context.Context) error {
+			claimPrewarmedSandbox(appServices().SandboxManager(), sandboxName, verbose)
+			return nil
+		}})
+	}
+
+	results, err := provisioning.RunObserved(appServices().Context(), provisioningEvents(), steps...)
+	if verbose {
+		for _, result := range results {
+			if !result.Skipped {
+				fmt.Printf("Debug: Provisioning step %s took %s\n", result.Step, result.Duration.Round(time.Millisecond))
 			}
 		}
-	} else {
-		// Add new session
-		sessions = append(sessions, *sessionMetadata)
+	}
+	if err != nil {
+		recordFailedStart(createWorkItemSessionMetadata(workItem, branch, worktreePath, tmuxSessionName,
+			sandboxName, currentRepo.Name, currentRepo.Root, friendlyTitle), results, err, verbose)
+		return err
+	}
+
+	// The worktree may carry its own .sbs/config.json overrides for this session
+	if worktreeConfig, err := config.LoadWorktreeConfig(currentRepo.Root, worktreePath); err != nil {
+		startWarningf("%v; using the repository configuration", err)
+	} else if worktreeConfig != nil {
+		repoConfig = config.MergeConfig(repoConfig, worktreeConfig)
+		trace.Decision("worktree config", tracedConfig(worktreeConfig), config.RepositoryConfigPath(worktreePath))
 	}
 
-	// Save updated sessions to global location
-	if err := config.SaveSessions(sessions); err != nil {
+	// Create session metadata with input source information
+	sessionMetadata := createWorkItemSessionMetadata(workItem, branch, worktreePath, session.Name,
+		sandboxName, currentRepo.Name, currentRepo.Root, friendlyTitle)
+	sessionMetadata.BuildCaches = buildCaches
+	if existingSession != nil && existingSession.CreatedAt != "" {
+		sessionMetadata.CreatedAt = existingSession.CreatedAt
+	}
+
+	// Save the session, merging with changes other sbs processes made meanwhile
+	if err := config.UpdateSessions(func(current []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		return upsertSession(current, *sessionMetadata), nil
+	}); err != nil {
 		return fmt.Errorf("failed to save sessions: %w", err)
 	}
 
 	// Execute command in session unless resuming
+	launched := false
 	if !resume {
+		// The session may have been replaced under its name since it was created
+		if err := tmuxManager.VerifySession(session.Name, worktreePath); err != nil {
+			return err
+		}
 		// Determine what command to execute based on precedence:
-		// 1. Command-line flags (--command, --no-command)
+		// 1. Command-line flags (--command, --no-command), then the work
+		//    item's sbs block
 		// 2. Repository config
 		// 3. Global config
 		// 4. Default behavior (.sbs/start script if exists)
-
-		if noCommand {
-			// Explicitly requested no command execution
-			fmt.Printf("Session started without executing any command.\n")
-		} else if customCommand != "" {
-			// Custom command from command line
-			fmt.Printf("Executing custom command in session: %s\n", customCommand)
-			if err := tmuxManager.ExecuteCommand(session.Name, customCommand, nil, tmuxEnv); err != nil {
-				fmt.Printf("Warning: Failed to execute custom command: %v\n", err)
-			}
-		} else if repoConfig.NoCommand {
-			// Repository config specifies no command
-			fmt.Printf("Session started without executing any command (repository config).\n")
-		} else if repoConfig.TmuxCommand != "" {
-			// Repository config specifies custom command
-			fmt.Printf("Executing repository command in session: %s\n", repoConfig.TmuxCommand)
-
-			// Create substitution map for parameters
-			substitutions := map[string]string{
-				"$1": workItem.ID,
+		_ = runStartStep(stepCommand, func() error {
+			// Setup commands from the sbs block run first, in the same shell
+			for _, setup := range issueBlock.Setup {
+				trace.Decision("setup command", setup, "sbs block")
+				if err := tmuxManager.ExecuteCommand(session.Name, setup, nil, tmuxEnv); err != nil {
+					startWarningf("Failed to execute setup command: %v", err)
+					return err
+				}
 			}
+			if noCommand {
+				// Explicitly requested no command execution
+				trace.Decision("session command", "", "--no-command")
+				fmt.Printf("Session started without executing any command.\n")
+			} else if customCommand != "" {
+				// Custom command from command line
+				trace.Decision("session command", customCommand, commandSource)
+				fmt.Printf("Executing custom command in session: %s\n", customCommand)
+				warnMissingTmuxCommand(customCommand, worktreePath, tmuxCommandLookup(sandboxName))
+				if err := tmuxManager.ExecuteCommand(session.Name, customCommand, nil, tmuxEnv); err != nil {
+					startWarningf("Failed to execute custom command: %v", err)
+					return err
+				}
+				launched = true
+			} else if repoConfig.NoCommand {
+				// Repository config specifies no command
+				trace.Decision("session command", "", "no_command")
+				fmt.Printf("Session started without executing any command (repository config).\n")
+			} else if repoConfig.TmuxCommand != "" {
+				// Repository config specifies custom command
+				trace.Decision("session command", repoConfig.TmuxCommand, "tmux_command")
+				fmt.Printf("Executing repository command in session: %s\n", repoConfig.TmuxCommand)
+				warnMissingTmuxCommand(repoConfig.TmuxCommand, worktreePath, tmuxCommandLookup(sandboxName))
+
+				// Create substitution map for parameters
+				substitutions := map[string]string{
+					"$1": workItem.ID,
+				}
 
-			if err := tmuxManager.ExecuteCommandWithSubstitution(session.Name, repoConfig.TmuxCommand, repoConfig.TmuxCommandArgs, substitutions, tmuxEnv); err != nil {
-				fmt.Printf("Warning: Failed to execute repository command: %v\n", err)
-			}
-		} else if workItem.Source == "test" {
-			// Test work items use sandbox sleep infinity for long-running processes
-			fmt.Printf("Starting sandbox with sleep infinity for test work item...\n")
-			sandboxCommand := fmt.Sprintf("sandbox --name \"%s\" sleep infinity", sandboxName)
-			if err := tmuxManager.ExecuteCommand(session.Name, sandboxCommand, nil, tmuxEnv); err != nil {
-				fmt.Printf("Warning: Failed to start sandbox sleep: %v\n", err)
-			}
-		} else {
-			// Default behavior - check for .sbs/start script
-			startScript := resolveStartScript(currentRepo.Root)
-			if startScript != "" {
+				if err := tmuxManager.ExecuteCommandWithSubstitution(session.Name, repoConfig.TmuxCommand, repoConfig.TmuxCommandArgs, substitutions, tmuxEnv); err != nil {
+					startWarningf("Failed to execute repository command: %v", err)
+					return err
+				}
+				launched = true
+			} else if workItem.Source == "test" {
+				// Test work items use sandbox sleep infinity for long-running processes
+				fmt.Printf("Starting sandbox with sleep infinity for test work item...\n")
+				sandboxCommand := "sandbox --name " + tmux.ShellQuote(sandboxName) + " sleep infinity"
+				trace.Decision("session command", sandboxCommand, "test work item")
+				err := faultinject.Check(faultinject.StepSandboxCreate)
+				if err == nil {
+					err = tmuxManager.ExecuteCommand(session.Name, sandboxCommand, nil, tmuxEnv)
+				}
+				if err != nil {
+					startWarningf("Failed to start sandbox sleep: %v", err)
+					return err
+				}
+				launched = true
+			} else {
+				// Default behavior - check for .sbs/start script
+				startScript := resolveStartScript(currentRepo.Root)
+				trace.Decision("session command", startScript, ".sbs/start script")
+				if startScript == "" {
+					fmt.Printf("No .sbs/start script found, session started without executing any script.\n")
+					return nil
+				}
 				fmt.Printf("Executing start script in session: %s\n", startScript)
 				if err := tmuxManager.StartWorkIssue(session.Name, 0, startScript, tmuxEnv); err != nil {
-					fmt.Printf("Warning: Failed to execute start script: %v\n", err)
+					startWarningf("Failed to execute start script: %v", err)
+					return err
 				}
-			} else {
-				fmt.Printf("No .sbs/start script found, session started without executing any script.\n")
+				launched = true
 			}
-		}
+			return nil
+		})
+	}
+
+	// Probe the launched command before calling the environment ready
+	ready := true
+	if launched && !skipReadiness {
+		_ = runStartStep(stepReadiness, func() error {
+			ready = reportReadiness(appServices().Context(), tmuxManager, workItem.FullID(), session.Name, worktreePath, repoConfig.ReadinessChecks)
+			if !ready {
+				return errNotReady
+			}
+			return nil
+		})
+	}
+	startEvents.Emit(events.Event{Type: events.Ready, Session: &events.Session{
+		TmuxSession:  session.Name,
+		WorktreePath: worktreePath,
+		Branch:       branch,
+		SandboxName:  sandboxName,
+		Ready:        ready,
+	}})
+	if !ready {
+		fmt.Println("\n" + messages.Render(messages.StartNotReady, messages.Args{"ID": workItem.FullID()}))
+		return nil
 	}
 
 	// Show attach command
-	fmt.Printf("\nWork environment ready! Use 'sbs attach %s' to connect.\n", workItem.FullID())
+	fmt.Println("\n" + messages.Render(messages.StartReady, messages.Args{"ID": workItem.FullID()}))
 	return nil
 }
 
+// fetchStartWorkItem gets a work item named on the command line from the
+// project's primary source or, for a namespaced ID of another source, from
+// that source configured by the sources map of .sbs/input-source.json
+func fetchStartWorkItem(factory *inputsource.InputSourceFactory, primary inputsource.InputSource, sourceConfig *config.InputSourceConfig, parsed *inputsource.WorkItem) (*inputsource.WorkItem, error) {
+	source := primary
+	if parsed.Source != primary.GetType() {
+		var err error
+		source, err = factory.CreateCrossSource(sourceConfig.ForSource(parsed.Source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create input source: %w", err)
+		}
+	}
+
+	// Check credentials first so a bad login fails here rather than when
+	// closing or commenting on the issue later
+	if err := validation.CheckInputSourceAuth(source.GetType()); err != nil {
+		return nil, err
+	}
+	workItem, err := source.GetWorkItem(parsed.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to get work item %s from %s source: %w", parsed.ID, source.GetType(), err)
+		if errors.Is(err, issue.ErrNotFound) {
+			return nil, exitcode.Wrap(exitcode.NotFound, err)
+		}
+		return nil, err
+	}
+	return workItem, nil
+}
+
+// parseStartWorkItemID validates a work item ID given to sbs start. Plain IDs
+// belong to the project's primary source; namespaced IDs may name the primary
+// source, the always-available test source, a source listed under sources in
+// .sbs/input-source.json or a source plugin on PATH.
+func parseStartWorkItemID(input, primaryType string, sourceConfig *config.InputSourceConfig) (*inputsource.WorkItem, error) {
+	sources := []string{primaryType}
+	if primaryType != "test" {
+		sources = append(sources, "test")
+	}
+	if strings.Contains(input, ":") {
+		if workItem, err := inputsource.ParseWorkItemID(input); err == nil && !slices.Contains(sources, workItem.Source) {
+			if _, listed := sourceConfig.Sources[workItem.Source]; listed {
+				return workItem, nil
+			}
+			if _, found := inputsource.FindPlugin(workItem.Source); found {
+				return workItem, nil
+			}
+		}
+		return inputsource.ParseConfiguredWorkItemID(input, sources)
+	}
+	id, err := inputsource.NormalizeWorkItemID(primaryType, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w; valid formats:\n%s", err, inputsource.ValidFormats(sources))
+	}
+	return &inputsource.WorkItem{Source: primaryType, ID: id}, nil
+}
+
 // runInteractiveWorkItemSelection launches the TUI for work item selection
 func runInteractiveWorkItemSelection(inputSource inputsource.InputSource) (*inputsource.WorkItem, error) {
 	// For now, fall back to GitHub client for interactive selection
@@ -311,7 +690,7 @@ func runInteractiveWorkItemSelection(inputSource inputsource.InputSource) (*inpu
 	}
 
 	// Use existing GitHub interactive selection as fallback
-	githubClient := issue.NewGitHubClient()
+	githubClient := issue.NewClient()
 	model := tui.NewIssueSelectModel(githubClient)
 
 	program := tea.NewProgram(model, tea.WithAltScreen())
@@ -347,7 +726,7 @@ func runInteractiveWorkItemSelection(inputSource inputsource.InputSource) (*inpu
 // runInteractiveIssueSelection launches the TUI for issue selection (legacy compatibility)
 func runInteractiveIssueSelection() (*issue.Issue, error) {
 	// Initialize GitHub client
-	githubClient := issue.NewGitHubClient()
+	githubClient := issue.NewClient()
 
 	// Create and run the issue selection TUI
 	model := tui.NewIssueSelectModel(githubClient)
@@ -391,8 +770,53 @@ func findSessionByWorkItem(sessions []config.SessionMetadata, workItem *inputsou
 	return nil
 }
 
-// createWorkItemBranch creates a branch for a work item using direct git commands
-func createWorkItemBranch(gitManager *git.Manager, branchName string) error {
+// isOtherRepository reports whether session belongs to a repository other than
+// currentRepo. Sessions recorded without a repository match any repository.
+func isOtherRepository(session *config.SessionMetadata, currentRepo *repo.Repository) bool {
+	if session.RepositoryRoot == "" || currentRepo == nil {
+		return false
+	}
+	return filepath.Clean(session.RepositoryRoot) != filepath.Clean(currentRepo.Root)
+}
+
+// resolveWrongRepository handles sbs start for a work item whose session lives
+// in another repository: it offers to attach to that session rather than
+// creating a second environment here, and otherwise explains how to proceed
+func resolveWrongRepository(tmuxManager *tmux.Manager, session *config.SessionMetadata, currentRepo *repo.Repository, workItem *inputsource.WorkItem) error {
+	startWarningf("%s", messages.Render(messages.StartWrongRepository, messages.Args{
+		"ID": workItem.FullID(), "Repository": session.RepositoryName, "RepositoryRoot": session.RepositoryRoot, "CurrentRepository": currentRepo.Name,
+	}))
+
+	running, err := tmuxManager.SessionExists(session.TmuxSession)
+	if err != nil {
+		return fmt.Errorf("failed to check tmux session: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("refusing to start %s in %s; its session's tmux session is not running, so run 'sbs start %s' from %s to recreate it there",
+			workItem.FullID(), currentRepo.Name, workItem.FullID(), session.RepositoryRoot)
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Print(messages.Render(messages.StartConfirmAttachOther, messages.Args{"Repository": session.RepositoryName}))
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response == "" || response == "y" || response == "yes" {
+			fmt.Println(messages.Render(messages.StartAttaching, messages.Args{"TmuxSession": session.TmuxSession}))
+			return tmuxManager.AttachToSession(session.TmuxSession)
+		}
+	}
+
+	return fmt.Errorf("refusing to start a second environment for %s in %s; use 'sbs attach %s' to open the existing session",
+		workItem.FullID(), currentRepo.Name, workItem.FullID())
+}
+
+// createWorkItemBranch creates a branch for a work item from base, or from
+// HEAD when base is empty, using direct git commands
+func createWorkItemBranch(gitManager *git.Manager, branchName, base string) error {
 	// Check if branch already exists
 	exists, err := gitManager.BranchExists(branchName)
 	if err != nil {
@@ -404,7 +828,7 @@ func createWorkItemBranch(gitManager *git.Manager, branchName string) error {
 	}
 
 	// Create the branch using the direct method with the exact branch name
-	err = gitManager.CreateBranchDirect(branchName)
+	err = gitManager.CreateBranchFrom(branchName, base)
 	if err != nil {
 		return fmt.Errorf("failed to create branch %s: %w", branchName, err)
 	}
@@ -435,16 +859,12 @@ func generateWorkItemWorktreePath(currentRepo *repo.Repository, workItem *inputs
 
 // generateWorkItemTmuxSessionName creates a tmux session name for the work item
 func generateWorkItemTmuxSessionName(currentRepo *repo.Repository, workItem *inputsource.WorkItem) string {
-	// Create a consistent naming format for all work item sources
-	return fmt.Sprintf("sbs-%s-%s-%s",
-		currentRepo.Name, workItem.Source, workItem.ID)
+	return naming.TmuxSession(currentRepo.Name, workItem.Source, workItem.ID)
 }
 
 // generateWorkItemSandboxName creates a sandbox name for the work item
 func generateWorkItemSandboxName(currentRepo *repo.Repository, workItem *inputsource.WorkItem) string {
-	// Create a consistent naming format for all work item sources
-	return fmt.Sprintf("sbs-%s-%s-%s",
-		currentRepo.Name, workItem.Source, workItem.ID)
+	return naming.Sandbox(currentRepo.Name, workItem.Source, workItem.ID)
 }
 
 // createWorkItemTmuxSession creates a tmux session for the work item
@@ -459,6 +879,7 @@ func createWorkItemTmuxSession(tmuxManager *tmux.Manager, workItem *inputsource.
 func createWorkItemSessionMetadata(workItem *inputsource.WorkItem, branch, worktreePath,
 	tmuxSession, sandboxName, repoName, repoRoot, friendlyTitle string) *config.SessionMetadata {
 
+	now := config.Timestamp()
 	return &config.SessionMetadata{
 		IssueTitle:     workItem.Title,
 		FriendlyTitle:  friendlyTitle,
@@ -468,14 +889,49 @@ func createWorkItemSessionMetadata(workItem *inputsource.WorkItem, branch, workt
 		SandboxName:    sandboxName,
 		RepositoryName: repoName,
 		RepositoryRoot: repoRoot,
-		CreatedAt:      "", // TODO: Set timestamp
-		LastActivity:   "", // TODO: Set timestamp
+		CreatedAt:      now,
+		LastActivity:   now,
 		Status:         "active",
 		SourceType:     workItem.Source,
 		NamespacedID:   workItem.FullID(),
+		WorkItemURL:    workItem.URL,
 	}
 }
 
+// wireBuildCaches resolves the configured shared build caches, creates their
+// host directories and adds their variables to env. Problems are reported as
+// warnings so a broken cache never blocks starting a session.
+func wireBuildCaches(cfg *config.Config, env map[string]string, verbose bool) []config.BuildCacheEntry {
+	if len(cfg.BuildCaches) == 0 {
+		return nil
+	}
+
+	root, err := buildcache.DefaultRoot()
+	if err != nil {
+		startWarningf("Build caches not wired: %v", err)
+		return nil
+	}
+	caches, err := buildcache.Resolve(cfg.BuildCaches, root)
+	if err == nil {
+		err = buildcache.Prepare(caches)
+	}
+	if err != nil {
+		startWarningf("Build caches not wired: %v", err)
+		return nil
+	}
+
+	for key, value := range buildcache.Environment(caches) {
+		env[key] = value
+	}
+	if verbose {
+		for _, cache := range caches {
+			fmt.Printf("Debug: Build cache %s\n", buildcache.Describe(cache))
+		}
+	}
+	fmt.Printf("Wired %d build cache(s) into the session\n", len(caches))
+	return caches
+}
+
 // resolveStartScript determines if a local .sbs/start script exists
 // Returns the path to .sbs/start if it exists, empty string otherwise
 func resolveStartScript(repoRoot string) string {
@@ -488,3 +944,63 @@ func resolveStartScript(repoRoot string) string {
 	// Return empty string if no local start script exists
 	return ""
 }
+
+// progressPublishInterval limits how often checkout progress is written to the
+// progress board
+const progressPublishInterval = 250 * time.Millisecond
+
+// checkoutProgress shows git's worktree checkout progress, redrawing a single
+// line on a terminal and printing only finished phases otherwise, and
+// publishes it to the progress board
+type checkoutProgress struct {
+	board       *provisioning.ProgressBoard // nil disables publishing
+	workItem    string
+	terminal    bool
+	drawn       bool // a progress line is on screen without its newline
+	lastPublish time.Time
+}
+
+func newCheckoutProgress(board *provisioning.ProgressBoard, workItem string) *checkoutProgress {
+	if board != nil {
+		_ = board.Update(provisioning.Progress{WorkItem: workItem, Step: faultinject.StepWorktreeAdd})
+	}
+	return &checkoutProgress{
+		board:    board,
+		workItem: workItem,
+		terminal: term.IsTerminal(int(os.Stdout.Fd())),
+	}
+}
+
+func (c *checkoutProgress) report(p git.Progress) {
+	if c.terminal {
+		fmt.Printf("\r%s", p)
+		c.drawn = !p.Done
+		if p.Done {
+			fmt.Println()
+		}
+	} else if p.Done {
+		fmt.Println(p)
+	}
+
+	now := time.Now()
+	if c.board != nil && (p.Done || now.Sub(c.lastPublish) >= progressPublishInterval) {
+		c.lastPublish = now
+		_ = c.board.Update(provisioning.Progress{
+			WorkItem:  c.workItem,
+			Step:      faultinject.StepWorktreeAdd,
+			Phase:     p.Phase,
+			Current:   p.Current,
+			Total:     p.Total,
+			Percent:   p.Percent,
+			UpdatedAt: now,
+		})
+	}
+}
+
+// finish ends a progress line left open by an interrupted checkout
+func (c *checkoutProgress) finish() {
+	if c.drawn {
+		fmt.Println()
+		c.drawn = false
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+	"sbs/pkg/notify"
+	"sbs/pkg/provisioning"
+)
+
+// Resource creation statuses recorded in a session's resource creation log
+const (
+	creationStatusCreated = "created"
+	creationStatusFailed  = "failed"
+)
+
+// creationLog turns provisioning results into resource creation log entries.
+// A failed step records its error and, for git failures, git's full output.
+func creationLog(results []provisioning.Result, at time.Time) []config.ResourceCreationEntry {
+	var entries []config.ResourceCreationEntry
+	for _, result := range results {
+		if result.Skipped {
+			continue
+		}
+		entry := config.ResourceCreationEntry{
+			ResourceType: result.Step,
+			CreatedAt:    at,
+			Status:       creationStatusCreated,
+			Metadata:     map[string]interface{}{"duration_ms": result.Duration.Milliseconds()},
+		}
+		if result.Err != nil {
+			entry.Status = creationStatusFailed
+			entry.Metadata["error"] = result.Err.Error()
+			if output := git.CommandOutput(result.Err); output != "" {
+				entry.Metadata["git_output"] = output
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// recordFailedStart archives a session whose provisioning failed, with the
+// failed step and its output, so 'sessions-archive.json' keeps an audit trail
+// of what went wrong, and tells the notifiers. Under --verbose git's output is
+// also printed.
+func recordFailedStart(session *config.SessionMetadata, results []provisioning.Result, err error, verbose bool) {
+	now := config.Now()
+	session.ResourceStatus = creationStatusFailed
+	session.FailureReason = err.Error()
+	session.ResourceCreationLog = creationLog(results, now)
+	session.CreatedAt = now.Format(time.RFC3339)
+	for _, result := range results {
+		if result.Err != nil && !result.Skipped {
+			session.FailurePoint = result.Step
+			break
+		}
+	}
+
+	if verbose {
+		if output := git.CommandOutput(err); output != "" {
+			fmt.Fprintf(os.Stderr, "Debug: git output:\n%s\n", output)
+		}
+	}
+
+	archivePath, archiveErr := config.GlobalSessionsArchivePath()
+	if archiveErr == nil {
+		archiveErr = config.ArchiveSessions(archivePath, []config.SessionMetadata{*session}, config.ArchiveReasonFailed)
+	}
+	if archiveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the failed start: %v\n", archiveErr)
+	}
+	if err := appServices().Notifications().Send(notify.StartFailedEvent(*session, session.FailurePoint, err, now)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+	"sbs/pkg/provisioning"
+)
+
+func TestRecordFailedStart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(os.Getenv("HOME"), ".config", "sbs"), 0755))
+
+	gitErr := &git.CommandError{
+		Args:   []string{"worktree", "add", "/tmp/wt", "issue-test-1"},
+		Output: "fatal: '/tmp/wt' already exists",
+		Err:    errors.New("exit status 128"),
+	}
+	stepErr := fmt.Errorf("failed to create worktree: %w", gitErr)
+	results := []provisioning.Result{
+		{Step: "branch", Duration: 5 * time.Millisecond},
+		{Step: "worktree", Err: stepErr},
+		{Step: "tmux", Err: provisioning.ErrSkipped, Skipped: true},
+	}
+
+	output := captureStdout(t, func() {
+		recordFailedStart(&config.SessionMetadata{NamespacedID: "test:1"}, results, stepErr, false)
+	})
+	assert.Empty(t, output)
+
+	archivePath, err := config.GlobalSessionsArchivePath()
+	require.NoError(t, err)
+	archived, err := config.LoadArchivedSessions(archivePath)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, config.ArchiveReasonFailed, archived[0].Reason)
+
+	session := archived[0].Session
+	assert.Equal(t, "failed", session.ResourceStatus)
+	assert.Equal(t, "worktree", session.FailurePoint)
+	assert.Contains(t, session.FailureReason, "already exists")
+	require.Len(t, session.ResourceCreationLog, 2, "skipped steps are not logged")
+	assert.Equal(t, "created", session.ResourceCreationLog[0].Status)
+	assert.Equal(t, "failed", session.ResourceCreationLog[1].Status)
+	assert.Equal(t, "fatal: '/tmp/wt' already exists", session.ResourceCreationLog[1].Metadata["git_output"])
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"sbs/pkg/bootstrap"
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+// issueBootstrap returns the sbs block in a work item's body when
+// issue_bootstrap allows applying it. Its commands come from whoever wrote the
+// work item, so without issue_bootstrap a block is only mentioned, and a
+// malformed block is reported and ignored rather than partly applied.
+func issueBootstrap(cfg *config.Config, workItem *inputsource.WorkItem) bootstrap.Block {
+	block, found, err := bootstrap.Parse(workItem.Body)
+	if !found {
+		return bootstrap.Block{}
+	}
+	if !cfg.IssueBootstrap {
+		fmt.Printf("%s has an sbs block; set issue_bootstrap to apply it.\n", workItem.FullID())
+		return bootstrap.Block{}
+	}
+	if err != nil {
+		startWarningf("ignoring the sbs block in %s: %v", workItem.FullID(), err)
+		return bootstrap.Block{}
+	}
+	if block.Base != "" {
+		fmt.Printf("sbs block: branching from %s\n", block.Base)
+	}
+	for _, setup := range block.Setup {
+		fmt.Printf("sbs block: setup %s\n", setup)
+	}
+	if block.Command != "" {
+		fmt.Printf("sbs block: session command %s\n", block.Command)
+	}
+	return block
+}
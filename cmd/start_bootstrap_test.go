@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/bootstrap"
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+func TestIssueBootstrap(t *testing.T) {
+	workItem := &inputsource.WorkItem{
+		Source: "github",
+		ID:     "42",
+		Body:   "Flaky test.\n\n```sbs\nbase: develop\nsetup: make deps\ncommand: claude --model opus\n```\n",
+	}
+
+	var block bootstrap.Block
+	output := captureStdout(t, func() { block = issueBootstrap(&config.Config{}, workItem) })
+	assert.True(t, block.Empty(), "blocks are only applied with issue_bootstrap")
+	assert.Contains(t, output, "github:42 has an sbs block; set issue_bootstrap to apply it")
+
+	output = captureStdout(t, func() { block = issueBootstrap(&config.Config{IssueBootstrap: true}, workItem) })
+	assert.Equal(t, bootstrap.Block{Base: "develop", Setup: []string{"make deps"}, Command: "claude --model opus"}, block)
+	assert.Contains(t, output, "sbs block: branching from develop")
+
+	workItem.Body = "```sbs\nprofile: python\n```"
+	output = captureStdout(t, func() { block = issueBootstrap(&config.Config{IssueBootstrap: true}, workItem) })
+	assert.True(t, block.Empty(), "a malformed block is ignored")
+	assert.Contains(t, output, `Warning: ignoring the sbs block in github:42`)
+
+	workItem.Body = "No block here."
+	output = captureStdout(t, func() { block = issueBootstrap(&config.Config{}, workItem) })
+	assert.True(t, block.Empty())
+	assert.Empty(t, output)
+}
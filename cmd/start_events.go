@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sbs/pkg/events"
+	"sbs/pkg/provisioning"
+)
+
+// startEvents streams sbs start progress when --events-json is set; nil
+// (discarding events) otherwise
+var startEvents *events.Emitter
+
+// beginEventStream switches sbs start to --events-json: events are written to
+// stdout and the human-readable output moves to stderr. The returned function
+// restores stdout.
+func beginEventStream() func() {
+	stdout := os.Stdout
+	startEvents = events.NewEmitter(stdout)
+	os.Stdout = os.Stderr
+	return func() {
+		os.Stdout = stdout
+		startEvents = nil
+	}
+}
+
+// startWarningf prints a warning and reports it to the event stream
+func startWarningf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Printf("Warning: %s\n", message)
+	startEvents.Warning(message)
+}
+
+// provisioningEvents reports provisioning steps to the event stream
+func provisioningEvents() provisioning.Observer {
+	emitter := startEvents
+	return provisioning.Observer{
+		Started: emitter.StepStarted,
+		Finished: func(result provisioning.Result) {
+			emitter.StepCompleted(result.Step, result.Duration, result.Skipped, result.Err)
+		},
+	}
+}
+
+// runStartStep runs one of the steps after provisioning, reporting it to the
+// event stream
+func runStartStep(step string, run func() error) error {
+	startEvents.StepStarted(step)
+	start := time.Now()
+	err := run()
+	startEvents.StepCompleted(step, time.Since(start), false, err)
+	return err
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/events"
+	"sbs/pkg/provisioning"
+)
+
+func TestEventStream_SeparatesEventsFromHumanOutput(t *testing.T) {
+	stdoutR, stdoutW, err := os.Pipe()
+	require.NoError(t, err)
+	stderrR, stderrW, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutW, stderrW
+	defer func() { os.Stdout, os.Stderr = origStdout, origStderr }()
+
+	restore := beginEventStream()
+	startEvents.SetWorkItem("test:1")
+	fmt.Println("Working on work item test:1")
+	startWarningf("Build caches not wired: %v", errors.New("no home"))
+	observer := provisioningEvents()
+	observer.Started("branch")
+	observer.Finished(provisioning.Result{Step: "branch", Duration: 20 * time.Millisecond})
+	require.NoError(t, runStartStep(stepCommand, func() error { return nil }))
+	restore()
+
+	assert.Nil(t, startEvents, "restoring stops the stream")
+	assert.Equal(t, stdoutW, os.Stdout)
+
+	stdoutW.Close()
+	stderrW.Close()
+	stdout, _ := io.ReadAll(stdoutR)
+	stderr, _ := io.ReadAll(stderrR)
+
+	assert.Contains(t, string(stderr), "Working on work item test:1")
+	assert.Contains(t, string(stderr), "Warning: Build caches not wired: no home")
+
+	var types []events.Type
+	for _, line := range strings.Split(strings.TrimSpace(string(stdout)), "\n") {
+		var event events.Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "stdout holds only events: %q", line)
+		assert.Equal(t, "test:1", event.WorkItem)
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []events.Type{events.Warning, events.StepStarted, events.StepCompleted, events.StepStarted, events.StepCompleted}, types)
+}
+
+func TestStartWarningf_WithoutStream(t *testing.T) {
+	output := captureStdout(t, func() { startWarningf("disk %s", "full") })
+	assert.Equal(t, "Warning: disk full\n", output)
+}
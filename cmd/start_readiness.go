@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sbs/pkg/config"
+	"sbs/pkg/readiness"
+)
+
+// readinessPaneLines is how much of the session's pane a failed readiness
+// report shows
+const readinessPaneLines = 10
+
+// errNotReady marks the readiness step failed in --events-json output
+var errNotReady = errors.New("readiness checks failed")
+
+// sessionInspector is the tmux access readiness reporting needs
+type sessionInspector interface {
+	SessionExists(sessionName string) (bool, error)
+	CapturePane(sessionName string) (string, error)
+}
+
+// reportReadiness checks that a session whose command was just launched is
+// ready: its tmux session survived and every configured readiness check
+// passes. It prints the outcome, with pointers to the session's output when
+// something failed, and reports whether the session is ready.
+func reportReadiness(ctx context.Context, inspector sessionInspector, workItemID, sessionName, worktreePath string, checks []config.ReadinessCheck) bool {
+	target := readiness.Target{
+		WorkItem:     workItemID,
+		TmuxSession:  sessionName,
+		WorktreePath: worktreePath,
+		SessionAlive: func() (bool, error) { return inspector.SessionExists(sessionName) },
+	}
+
+	if len(checks) == 0 {
+		// Without checks, at least catch a command that took the session down
+		if exists, err := inspector.SessionExists(sessionName); err != nil || exists {
+			return true
+		}
+		fmt.Println()
+		startWarningf("tmux session %s exited right after its command started.", sessionName)
+		fmt.Printf("Check the command with 'sbs start %s --validate-only'.\n", workItemID)
+		return false
+	}
+
+	fmt.Printf("Waiting for %d readiness check(s)...\n", len(checks))
+	report := readiness.Run(ctx, target, checks)
+	for _, result := range report.Results {
+		mark := "✓"
+		if !result.Ready {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %s\n", mark, result)
+	}
+	if report.Ready() {
+		return true
+	}
+
+	fmt.Println()
+	startWarningf("%s is not ready (%d of %d readiness check(s) failed).",
+		workItemID, len(report.Failed()), len(report.Results))
+	if tail := paneTail(inspector, sessionName, readinessPaneLines); tail != "" {
+		fmt.Printf("Last output of tmux session %s:\n%s\n", sessionName, tail)
+	}
+	fmt.Printf("Inspect it with 'sbs log %s' or 'sbs attach %s'.\n", workItemID, workItemID)
+	return false
+}
+
+// paneTail returns the last non-empty lines of a session's pane, indented,
+// or "" when the pane can't be captured
+func paneTail(inspector sessionInspector, sessionName string, lines int) string {
+	content, err := inspector.CapturePane(sessionName)
+	if err != nil {
+		return ""
+	}
+	var kept []string
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			kept = append(kept, "  "+line)
+		}
+	}
+	if len(kept) > lines {
+		kept = kept[len(kept)-lines:]
+	}
+	return strings.Join(kept, "\n")
+}
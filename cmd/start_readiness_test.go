@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+// fakeInspector is a tmux session that exists until told otherwise
+type fakeInspector struct {
+	gone bool
+	pane string
+}
+
+func (f *fakeInspector) SessionExists(string) (bool, error) { return !f.gone, nil }
+
+func (f *fakeInspector) CapturePane(string) (string, error) { return f.pane, nil }
+
+func TestReportReadiness_NoChecks(t *testing.T) {
+	inspector := &fakeInspector{}
+	assert.True(t, reportReadiness(context.Background(), inspector, "test:1", "sbs-test-1", t.TempDir(), nil))
+
+	inspector.gone = true
+	var ready bool
+	output := captureStdout(t, func() {
+		ready = reportReadiness(context.Background(), inspector, "test:1", "sbs-test-1", t.TempDir(), nil)
+	})
+	assert.False(t, ready)
+	assert.Contains(t, output, "exited right after its command started")
+}
+
+func TestReportReadiness_FailedCheckPointsAtOutput(t *testing.T) {
+	inspector := &fakeInspector{pane: "$ ./server\npanic: listen tcp :3000: address already in use\n\n"}
+	checks := []config.ReadinessCheck{
+		{Name: "always", Command: "true"},
+		{Name: "server", Command: "false", TimeoutSecs: 1},
+	}
+
+	var ready bool
+	output := captureStdout(t, func() {
+		ready = reportReadiness(context.Background(), inspector, "test:1", "sbs-test-1", t.TempDir(), checks)
+	})
+
+	assert.False(t, ready)
+	assert.Contains(t, output, "✓ always: ready after")
+	assert.Contains(t, output, "✗ server: not ready after")
+	assert.Contains(t, output, "1 of 2 readiness check(s) failed")
+	assert.Contains(t, output, "  panic: listen tcp :3000: address already in use")
+	assert.Contains(t, output, "sbs log test:1")
+}
+
+func TestPaneTail(t *testing.T) {
+	inspector := &fakeInspector{pane: "one\n\ntwo\nthree\n"}
+	assert.Equal(t, "  two\n  three", paneTail(inspector, "s", 2))
+}
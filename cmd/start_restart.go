@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/tmux"
+)
+
+// restartWindowName is the window sbs start --restart runs the session command in
+const restartWindowName = "restart"
+
+// sessionCommandLine returns the command line sbs start types into a new
+// session and the setting it came from, following startWorkItem's
+// precedence: --no-command/--command, the repository's no_command or
+// tmux_command, sleeping in the sandbox for test work items, and finally the
+// .sbs/start script. The line is empty when no command is run.
+func sessionCommandLine(customCommand string, noCommand bool, repoConfig *config.Config, workItem *inputsource.WorkItem, sandboxName, repoRoot string) (string, string) {
+	switch {
+	case noCommand:
+		return "", "--no-command"
+	case customCommand != "":
+		return customCommand, "--command"
+	case repoConfig.NoCommand:
+		return "", "no_command"
+	case repoConfig.TmuxCommand != "":
+		substitutions := map[string]string{"$1": workItem.ID}
+		return tmux.CommandLine(repoConfig.TmuxCommand, repoConfig.TmuxCommandArgs, substitutions), "tmux_command"
+	case workItem.Source == "test":
+		return "sandbox --name " + tmux.ShellQuote(sandboxName) + " sleep infinity", "test work item"
+	}
+	startScript := resolveStartScript(repoRoot)
+	if startScript == "" {
+		return "", ".sbs/start script"
+	}
+	return tmux.ShellQuote(startScript) + " 0", ".sbs/start script"
+}
+
+// restartInNewWindow runs the session command again in a new window of a
+// running session, leaving whatever runs in its other windows alone
+func restartInNewWindow(tmuxManager *tmux.Manager, session *config.SessionMetadata, commandLine string) error {
+	if err := tmuxManager.VerifySession(session.TmuxSession, session.WorktreePath); err != nil {
+		return err
+	}
+	if err := tmuxManager.NewWindow(session.TmuxSession, restartWindowName, session.WorktreePath, ""); err != nil {
+		return err
+	}
+	// The new window is the session's current one, so the keys land in it
+	if err := tmuxManager.ExecuteCommand(session.TmuxSession, commandLine, nil); err != nil {
+		return fmt.Errorf("failed to run the session command in window %s: %w", restartWindowName, err)
+	}
+	fmt.Printf("Restarted the session command in window %q of %s: %s\n", restartWindowName, session.TmuxSession, commandLine)
+	return nil
+}
+
+// restartIdlePrimaryPane runs the session command again in a running
+// session's primary pane when the command has exited, respawning the pane
+// first if it died. It reports whether it restarted the command.
+func restartIdlePrimaryPane(tmuxManager *tmux.Manager, session *config.SessionMetadata, commandLine string) (bool, error) {
+	if err := tmuxManager.VerifySession(session.TmuxSession, session.WorktreePath); err != nil {
+		return false, err
+	}
+	pane, err := tmuxManager.PrimaryPane(session.TmuxSession)
+	if err != nil {
+		return false, err
+	}
+	if !pane.Idle() {
+		return false, nil
+	}
+	fmt.Printf("The session command in %s is no longer running; restarting it\n", session.TmuxSession)
+	if pane.Dead {
+		if err := tmuxManager.RespawnPane(pane.ID, session.WorktreePath); err != nil {
+			return false, err
+		}
+	}
+	if err := tmuxManager.ExecuteCommand(pane.ID, commandLine, nil); err != nil {
+		return false, fmt.Errorf("failed to restart the session command: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+func TestSessionCommandLine(t *testing.T) {
+	repoRoot := t.TempDir()
+	githubItem := &inputsource.WorkItem{Source: "github", ID: "42"}
+	testItem := &inputsource.WorkItem{Source: "test", ID: "demo"}
+	repoCommand := &config.Config{TmuxCommand: "claude", TmuxCommandArgs: []string{"--issue", "$1"}}
+
+	tests := []struct {
+		name          string
+		customCommand string
+		noCommand     bool
+		cfg           *config.Config
+		workItem      *inputsource.WorkItem
+		wantLine      string
+		wantSource    string
+	}{
+		{"no-command flag wins", "vim", true, repoCommand, githubItem, "", "--no-command"},
+		{"command flag beats config", "vim .", false, repoCommand, githubItem, "vim .", "--command"},
+		{"repository no_command", "", false, &config.Config{NoCommand: true, TmuxCommand: "claude"}, githubItem, "", "no_command"},
+		{"repository tmux_command with substitution", "", false, repoCommand, githubItem, "claude --issue 42", "tmux_command"},
+		{"test work item sleeps in the sandbox", "", false, &config.Config{}, testItem, "sandbox --name sbs-test-demo sleep infinity", "test work item"},
+		{"no start script", "", false, &config.Config{}, githubItem, "", ".sbs/start script"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, source := sessionCommandLine(tt.customCommand, tt.noCommand, tt.cfg, tt.workItem, "sbs-test-demo", repoRoot)
+			assert.Equal(t, tt.wantLine, line)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+
+	t.Run("start script", func(t *testing.T) {
+		script := filepath.Join(repoRoot, ".sbs", "start")
+		require.NoError(t, os.MkdirAll(filepath.Dir(script), 0755))
+		require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0755))
+
+		line, source := sessionCommandLine("", false, &config.Config{}, githubItem, "sbs-test-demo", repoRoot)
+		assert.Equal(t, script+" 0", line)
+		assert.Equal(t, ".sbs/start script", source)
+	})
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/repo"
+	"sbs/pkg/tmux"
 )
 
 func TestStartCommand_ArgumentParsing(t *testing.T) {
@@ -343,3 +349,140 @@ func TestResolveStartScript(t *testing.T) {
 		assert.Equal(t, "", result3)
 	})
 }
+
+func TestIsOtherRepository(t *testing.T) {
+	current := &repo.Repository{Name: "repo-a", Root: "/src/repo-a"}
+
+	assert.False(t, isOtherRepository(&config.SessionMetadata{RepositoryRoot: "/src/repo-a/"}, current))
+	assert.False(t, isOtherRepository(&config.SessionMetadata{}, current), "legacy sessions without a repository match")
+	assert.True(t, isOtherRepository(&config.SessionMetadata{RepositoryRoot: "/src/repo-b"}, current))
+}
+
+func TestResolveWrongRepository_SessionNotRunning(t *testing.T) {
+	// A tmux that reports every session as missing
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "tmux"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := &config.SessionMetadata{
+		NamespacedID:   "github:123",
+		TmuxSession:    "sbs-repo-b-github-123",
+		RepositoryName: "repo-b",
+		RepositoryRoot: "/src/repo-b",
+	}
+	current := &repo.Repository{Name: "repo-a", Root: "/src/repo-a"}
+	workItem := &inputsource.WorkItem{Source: "github", ID: "123"}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = resolveWrongRepository(tmux.NewManager(), session, current, workItem)
+	})
+	assert.Contains(t, output, "github:123 already has a session in repository repo-b (/src/repo-b), not repo-a")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run 'sbs start github:123' from /src/repo-b")
+}
+
+func TestParseStartWorkItemID(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	noSources := &config.InputSourceConfig{}
+	item, err := parseStartWorkItemID("#12", "github", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "github:12", item.FullID())
+
+	item, err = parseStartWorkItemID("Test:quick", "github", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "test:quick", item.FullID())
+
+	_, err = parseStartWorkItemID("fix-login", "github", noSources)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "valid formats:")
+	assert.Contains(t, err.Error(), "test:my-test")
+
+	_, err = parseStartWorkItemID("jira:PROJ-1", "github", noSources)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown work item source")
+	item, err = parseStartWorkItemID("jira:proj-1", "github", &config.InputSourceConfig{Sources: map[string]map[string]interface{}{"jira": {}}})
+	require.NoError(t, err)
+	assert.Equal(t, "jira:PROJ-1", item.FullID())
+
+	item, err = parseStartWorkItemID("quick", "test", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "test:quick", item.FullID())
+
+	item, err = parseStartWorkItemID("jira:abc-123", "jira", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "jira:ABC-123", item.FullID())
+	item, err = parseStartWorkItemID("abc-123", "jira", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "jira:ABC-123", item.FullID())
+}
+
+func TestParseStartWorkItemID_SourcePlugin(t *testing.T) {
+	noSources := &config.InputSourceConfig{}
+	dir := t.TempDir()
+	for _, sourceType := range []string{"jira", "youtrack"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, inputsource.PluginPrefix+sourceType), []byte("#!/bin/sh\n"), 0755))
+	}
+	t.Setenv("PATH", dir)
+
+	item, err := parseStartWorkItemID("jira:proj-42", "github", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "jira:PROJ-42", item.FullID())
+
+	item, err = parseStartWorkItemID("youtrack:PROJ-42", "github", noSources)
+	require.NoError(t, err)
+	assert.Equal(t, "youtrack:PROJ-42", item.FullID())
+
+	_, err = parseStartWorkItemID("linear:abc-1", "github", noSources)
+	assert.ErrorContains(t, err, "unknown work item source")
+}
+
+func TestFetchStartWorkItem_CrossSourcePlugin(t *testing.T) {
+	// A plugin that records its request and knows only PROJ-42
+	dir := t.TempDir()
+	requestPath := filepath.Join(dir, "request.json")
+	script := `#!/bin/sh
+cat > "` + requestPath + `"
+if grep -q PROJ-42 "` + requestPath + `"; then
+	echo '{"work_item": {"id": "PROJ-42", "title": "Fix export"}}'
+else
+	echo '{"error": "no such issue", "not_found": true}'
+fi
+`
+	for _, sourceType := range []string{"jira", "youtrack"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, inputsource.PluginPrefix+sourceType), []byte(script), 0755))
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	sourceConfig := &config.InputSourceConfig{
+		Type:     "test",
+		Settings: map[string]interface{}{},
+		Sources:  map[string]map[string]interface{}{"youtrack": {"url": "https://yt.example.com"}},
+	}
+	factory := inputsource.NewInputSourceFactory()
+	primary := inputsource.NewTestInputSource()
+
+	workItem, err := fetchStartWorkItem(factory, primary, sourceConfig, &inputsource.WorkItem{Source: "youtrack", ID: "PROJ-42"})
+	require.NoError(t, err)
+	assert.Equal(t, "youtrack:PROJ-42", workItem.FullID())
+	assert.Equal(t, "Fix export", workItem.Title)
+
+	request, err := os.ReadFile(requestPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(request), `"settings":{"url":"https://yt.example.com"}`, "the plugin gets the settings listed under sources")
+
+	_, err = fetchStartWorkItem(factory, primary, sourceConfig, &inputsource.WorkItem{Source: "youtrack", ID: "PROJ-7"})
+	require.Error(t, err)
+	assert.Equal(t, exitcode.NotFound, exitcode.Of(err))
+	assert.Contains(t, err.Error(), "failed to get work item PROJ-7 from youtrack source")
+
+	// An installed Jira plugin serves jira: IDs without Jira settings here
+	workItem, err = fetchStartWorkItem(factory, primary, sourceConfig, &inputsource.WorkItem{Source: "jira", ID: "PROJ-42"})
+	require.NoError(t, err)
+	assert.Equal(t, "jira:PROJ-42", workItem.FullID())
+
+	// The primary source serves its own IDs
+	workItem, err = fetchStartWorkItem(factory, primary, sourceConfig, &inputsource.WorkItem{Source: "test", ID: "quick"})
+	require.NoError(t, err)
+	assert.Equal(t, "test:quick", workItem.FullID())
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sbs/pkg/config"
+	"sbs/pkg/validation"
+)
+
+// tmuxCommandLookup searches the local PATH and, when it is running, the session's sandbox
+func tmuxCommandLookup(sandboxName string) validation.CommandLookup {
+	var lookup validation.CommandLookup
+	if sandboxName == "" {
+		return lookup
+	}
+	sandboxManager := appServices().SandboxManager()
+	if exists, err := sandboxManager.SandboxExists(sandboxName); err != nil || !exists {
+		return lookup
+	}
+	lookup.InSandbox = func(executable string) (bool, error) {
+		return sandboxManager.CommandExists(sandboxName, executable)
+	}
+	return lookup
+}
+
+// warnMissingTmuxCommand warns when the executable of a command about to be
+// typed into the session does not exist, since tmux can't report it
+func warnMissingTmuxCommand(command, dir string, lookup validation.CommandLookup) {
+	check := validation.CheckTmuxCommand(command, dir, lookup)
+	if problem := check.Problem(); problem != "" {
+		startWarningf("%s; the session will start but the command will fail", problem)
+	}
+}
+
+// runValidateOnly checks the command configuration sbs start would use and
+// reports problems without creating anything
+func runValidateOnly(repoRoot string, repoConfig *config.Config, customCommand string, noCommand bool) error {
+	var problems []string
+
+	// LoadConfigWithRepository ignores a broken repository config, so load it again to report why
+	if _, err := config.LoadRepositoryConfig(repoRoot); err != nil && !os.IsNotExist(err) {
+		problems = append(problems, fmt.Sprintf("%s: %v", config.RepositoryConfigPath(repoRoot), err))
+	}
+
+	command, origin := customCommand, "--command"
+	switch {
+	case noCommand || (customCommand == "" && repoConfig.NoCommand):
+		fmt.Println("No command is run in new sessions.")
+	case customCommand == "" && repoConfig.TmuxCommand != "":
+		command, origin = repoConfig.TmuxCommand, "tmux_command"
+	case customCommand == "":
+		fmt.Println("No tmux_command configured; the .sbs/start script is used if present.")
+	}
+	if customCommand == "" && repoConfig.TmuxCommand == "" && len(repoConfig.TmuxCommandArgs) > 0 {
+		problems = append(problems, "tmux_command_args is set without tmux_command and is ignored")
+	}
+
+	if command != "" && !noCommand {
+		if origin == "tmux_command" && len(repoConfig.TmuxCommandArgs) > 0 {
+			fmt.Printf("Command (%s): %s %s\n", origin, command, strings.Join(repoConfig.TmuxCommandArgs, " "))
+		} else {
+			fmt.Printf("Command (%s): %s\n", origin, command)
+		}
+		check := validation.CheckTmuxCommand(command, repoRoot, validation.CommandLookup{})
+		if problem := check.Problem(); problem != "" {
+			problems = append(problems, problem+" (it may still exist inside the sandbox)")
+		} else if check.Path != "" {
+			fmt.Printf("Executable: %s\n", check.Path)
+		} else {
+			fmt.Printf("Executable: %s (%s)\n", check.Executable, check.Location)
+		}
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("Problem: %s\n", problem)
+		}
+		return fmt.Errorf("start configuration has %d problem(s)", len(problems))
+	}
+	fmt.Println("Start configuration is valid.")
+	return nil
+}
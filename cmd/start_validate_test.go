@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestRunValidateOnly(t *testing.T) {
+	repoRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoRoot, "scripts", "dev.sh"), []byte("#!/bin/sh\n"), 0755))
+
+	t.Run("valid repository command", func(t *testing.T) {
+		cfg := &config.Config{TmuxCommand: "./scripts/dev.sh", TmuxCommandArgs: []string{"$1"}}
+		var err error
+		output := captureStdout(t, func() {
+			err = runValidateOnly(repoRoot, cfg, "", false)
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Command (tmux_command): ./scripts/dev.sh $1")
+		assert.Contains(t, output, "Start configuration is valid.")
+	})
+
+	t.Run("misspelled command", func(t *testing.T) {
+		cfg := &config.Config{TmuxCommand: "sbs-no-such-tool-xyz"}
+		var err error
+		output := captureStdout(t, func() {
+			err = runValidateOnly(repoRoot, cfg, "", false)
+		})
+		require.Error(t, err)
+		assert.Contains(t, output, `Problem: "sbs-no-such-tool-xyz" was not found on PATH`)
+	})
+
+	t.Run("command flag overrides config", func(t *testing.T) {
+		cfg := &config.Config{TmuxCommand: "sbs-no-such-tool-xyz"}
+		var err error
+		output := captureStdout(t, func() {
+			err = runValidateOnly(repoRoot, cfg, "./scripts/dev.sh", false)
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Command (--command): ./scripts/dev.sh")
+	})
+
+	t.Run("args without command", func(t *testing.T) {
+		cfg := &config.Config{TmuxCommandArgs: []string{"--continue"}}
+		var err error
+		output := captureStdout(t, func() {
+			err = runValidateOnly(repoRoot, cfg, "", false)
+		})
+		require.Error(t, err)
+		assert.Contains(t, output, "tmux_command_args is set without tmux_command")
+	})
+
+	t.Run("broken repository config", func(t *testing.T) {
+		brokenRoot := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(brokenRoot, ".sbs"), 0755))
+		require.NoError(t, os.WriteFile(config.RepositoryConfigPath(brokenRoot), []byte("{not json"), 0644))
+		var err error
+		output := captureStdout(t, func() {
+			err = runValidateOnly(brokenRoot, &config.Config{}, "", false)
+		})
+		require.Error(t, err)
+		assert.Contains(t, output, "config.json")
+	})
+}
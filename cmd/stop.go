@@ -7,23 +7,32 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"sbs/pkg/cleanup"
 	"sbs/pkg/config"
-	"sbs/pkg/git"
-	"sbs/pkg/repo"
-	"sbs/pkg/sandbox"
-	"sbs/pkg/tmux"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/messages"
+	"sbs/pkg/oplock"
+	"sbs/pkg/sessiondoc"
 )
 
 var stopCmd = &cobra.Command{
-	Use:   "stop <work-item-id>",
+	Use:   "stop [work-item-id]",
 	Short: "Stop a work session",
 	Long: `Stop the tmux session for the specified work item.
 The worktree and session metadata are preserved.
 
 Work item ID formats:
   sbs stop 123           # Primary work type
-  sbs stop test:my-test    # Test work type`,
-	Args: cobra.ExactArgs(1),
+  sbs stop test:my-test    # Test work type
+  sbs stop 123 --only tmux # Only kill the tmux session, keep the sandbox
+
+Use --only to act on specific resources (tmux, sandbox, worktree, branch);
+it can be repeated or given a comma-separated list.
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runStop,
 }
 
@@ -32,15 +41,38 @@ func init() {
 	stopCmd.Flags().BoolP("delete-branch", "d", false, "Delete the associated branch when stopping the session")
 	stopCmd.Flags().BoolP("remove-worktree", "w", false, "Remove the associated worktree when stopping the session")
 	stopCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompts")
+	stopCmd.Flags().Bool("done", false, "Mark the work item as done in its input source after stopping")
+	stopCmd.Flags().StringSlice("only", nil, "Stop only these resources: tmux, sandbox, worktree, branch (repeatable)")
+	addWaitFlags(stopCmd)
 }
 
 func runStop(cmd *cobra.Command, args []string) error {
-	workItemID := args[0]
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
 
 	// Get flags
 	deleteBranch, _ := cmd.Flags().GetBool("delete-branch")
 	removeWorktree, _ := cmd.Flags().GetBool("remove-worktree")
-	skipConfirmation, _ := cmd.Flags().GetBool("yes")
+	skipConfirmation, err := confirmationSkipped(cmd, "stop")
+	if err != nil {
+		return err
+	}
+	markDone, _ := cmd.Flags().GetBool("done")
+	onlyValues, _ := cmd.Flags().GetStringSlice("only")
+
+	resources, err := stopResources(onlyValues, removeWorktree, deleteBranch)
+	if err != nil {
+		return err
+	}
+
+	// Claim the work item so a concurrent start or clean doesn't interleave with the stop
+	lock, err := lockWorkItem(cmd, workItemID, oplock.OpStop)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
 
 	// Load sessions
 	sessions, err := config.LoadSessions()
@@ -57,11 +89,119 @@ func runStop(cmd *cobra.Command, args []string) error {
 		}
 	}
 	if session == nil {
-		return fmt.Errorf("no session found for work item %s", workItemID)
+		return sessionNotFound(workItemID)
 	}
 
 	// Stop tmux session
-	tmuxManager := tmux.NewManager()
+	if resources&cleanup.ResourceTmux != 0 {
+		if err := stopTmuxSession(session); err != nil {
+			return err
+		}
+	}
+
+	// Stop sandbox if it exists
+	if resources&cleanup.ResourceSandbox != 0 {
+		if err := stopSandbox(session, workItemID, skipConfirmation); err != nil {
+			return err
+		}
+	}
+
+	// Update session status; the session only counts as stopped once its tmux session is gone
+	if resources&cleanup.ResourceTmux != 0 {
+		if err := updateSession(workItemID, func(s *config.SessionMetadata) { s.Status = "stopped" }); err != nil {
+			return fmt.Errorf("failed to save sessions: %w", err)
+		}
+	}
+
+	// A branch renamed outside sbs is never deleted under its new name without
+	// a second look; detect it while the worktree still exists
+	if resources&cleanup.ResourceBranch != 0 {
+		if renamed := detectRenamedBranch(session); renamed != "" {
+			fmt.Printf("Warning: branch %s was renamed to %s outside sbs; recorded the new name but not deleting it. "+
+				"Run 'sbs stop %s --only branch' to delete it.\n", session.Branch, renamed, workItemID)
+			resources &^= cleanup.ResourceBranch
+			session.Branch = renamed
+			if err := updateSession(workItemID, func(s *config.SessionMetadata) { s.Branch = renamed }); err != nil {
+				return fmt.Errorf("failed to save sessions: %w", err)
+			}
+			if resources&cleanup.ResourceWorktree == 0 {
+				if err := sessiondoc.Update(*session); err != nil {
+					fmt.Printf("Warning: failed to update %s: %v\n", sessiondoc.RelativePath, err)
+				}
+			}
+		}
+	}
+
+	// Handle worktree removal if requested
+	if resources&cleanup.ResourceWorktree != 0 {
+		if err := removeWorktreeForSession(session); err != nil {
+			fmt.Printf("Warning: failed to remove worktree: %v\n", err)
+		} else {
+			fmt.Printf("Removed worktree: %s\n", session.WorktreePath)
+		}
+	}
+
+	// Handle branch deletion if requested
+	if resources&cleanup.ResourceBranch != 0 {
+		if err := deleteBranchForSession(session); err != nil {
+			fmt.Printf("Warning: failed to delete branch: %v\n", err)
+		} else {
+			fmt.Printf("Deleted branch: %s\n", session.Branch)
+		}
+	}
+
+	// Update the work item in its input source if requested
+	if markDone {
+		if err := transitionWorkItem(workItemID, inputsource.StateDone); err != nil {
+			fmt.Printf("Warning: failed to mark work item as done: %v\n", err)
+		}
+	}
+
+	switch {
+	case len(onlyValues) > 0:
+		fmt.Printf("Stopped %s for work item %s.\n", resources, workItemID)
+	case resources&cleanup.ResourceWorktree == 0:
+		fmt.Printf("Session for work item %s stopped. Worktree preserved at: %s\n",
+			workItemID, session.WorktreePath)
+	default:
+		fmt.Printf("Session for work item %s stopped and worktree removed.\n", workItemID)
+	}
+
+	return nil
+}
+
+// stopResources determines which resources stop acts on. Without --only the
+// tmux session and sandbox are stopped, plus the worktree and branch when
+// --remove-worktree and --delete-branch are given.
+func stopResources(onlyValues []string, removeWorktree, deleteBranch bool) (cleanup.ResourceMask, error) {
+	only, err := cleanup.ParseResourceMask(onlyValues)
+	if err != nil {
+		return 0, exitcode.Errorf(exitcode.Validation, "invalid --only value: %w", err)
+	}
+
+	if only == 0 {
+		resources := cleanup.ResourceTmux | cleanup.ResourceSandbox
+		if removeWorktree {
+			resources |= cleanup.ResourceWorktree
+		}
+		if deleteBranch {
+			resources |= cleanup.ResourceBranch
+		}
+		return resources, nil
+	}
+
+	if removeWorktree && only&cleanup.ResourceWorktree == 0 {
+		return 0, exitcode.Errorf(exitcode.Validation, "--remove-worktree cannot be combined with --only %s", only)
+	}
+	if deleteBranch && only&cleanup.ResourceBranch == 0 {
+		return 0, exitcode.Errorf(exitcode.Validation, "--delete-branch cannot be combined with --only %s", only)
+	}
+	return only, nil
+}
+
+// stopTmuxSession kills the session's tmux session if it is running
+func stopTmuxSession(session *config.SessionMetadata) error {
+	tmuxManager := appServices().TmuxManager()
 	exists, err := tmuxManager.SessionExists(session.TmuxSession)
 	if err != nil {
 		return fmt.Errorf("failed to check tmux session: %w", err)
@@ -75,22 +215,27 @@ func runStop(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("Tmux session %s was not running\n", session.TmuxSession)
 	}
+	return nil
+}
 
-	// Stop sandbox if it exists
-	sandboxManager := sandbox.NewManager()
+// stopSandbox deletes the session's sandbox, asking for confirmation unless skipped
+func stopSandbox(session *config.SessionMetadata, workItemID string, skipConfirmation bool) error {
+	sandboxManager := sessionSandboxManager(*session)
 	sandboxName := session.SandboxName
 	if sandboxName == "" {
 		return fmt.Errorf("session missing sandbox name - cannot stop sandbox for %s", workItemID)
 	}
 
 	sandboxExists, err := sandboxManager.SandboxExists(sandboxName)
-	if err != nil {
+	if err != nil && sandboxManager.Required() {
+		return fmt.Errorf("could not check sandbox %s: %w", sandboxName, err)
+	} else if err != nil {
 		fmt.Printf("Warning: could not check sandbox %s: %v\n", sandboxName, err)
 	} else if sandboxExists {
 		// Ask for confirmation before deleting sandbox unless -y flag is used
 		shouldDelete := skipConfirmation
 		if !skipConfirmation {
-			fmt.Printf("Delete sandbox %s? (y/N): ", sandboxName)
+			fmt.Print(messages.Render(messages.StopConfirmSandbox, messages.Args{"Sandbox": sandboxName}))
 			reader := bufio.NewReader(os.Stdin)
 			response, err := reader.ReadString('\n')
 			if err != nil {
@@ -100,12 +245,14 @@ func runStop(cmd *cobra.Command, args []string) error {
 			if response == "y" || response == "yes" {
 				shouldDelete = true
 			} else {
-				fmt.Printf("Sandbox deletion cancelled. Tmux session stopped but sandbox preserved.\n")
+				fmt.Printf("Sandbox deletion cancelled. Sandbox %s preserved.\n", sandboxName)
 			}
 		}
 
 		if shouldDelete {
-			if err := sandboxManager.DeleteSandbox(sandboxName); err != nil {
+			if err := sandboxManager.DeleteSandbox(sandboxName); err != nil && sandboxManager.Required() {
+				return fmt.Errorf("failed to delete sandbox %s (sandbox_required is set): %w", sandboxName, err)
+			} else if err != nil {
 				fmt.Printf("Warning: failed to delete sandbox %s: %v\n", sandboxName, err)
 			} else {
 				fmt.Printf("Deleted sandbox: %s\n", sandboxName)
@@ -115,44 +262,6 @@ func runStop(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Sandbox %s was not running\n", sandboxName)
 	}
 
-	// Update session status
-	for i, s := range sessions {
-		if s.NamespacedID == workItemID {
-			sessions[i].Status = "stopped"
-			break
-		}
-	}
-
-	// Save updated sessions
-	if err := config.SaveSessions(sessions); err != nil {
-		return fmt.Errorf("failed to save sessions: %w", err)
-	}
-
-	// Handle worktree removal if requested
-	if removeWorktree {
-		if err := removeWorktreeForSession(session); err != nil {
-			fmt.Printf("Warning: failed to remove worktree: %v\n", err)
-		} else {
-			fmt.Printf("Removed worktree: %s\n", session.WorktreePath)
-		}
-	}
-
-	// Handle branch deletion if requested
-	if deleteBranch {
-		if err := deleteBranchForSession(session); err != nil {
-			fmt.Printf("Warning: failed to delete branch: %v\n", err)
-		} else {
-			fmt.Printf("Deleted branch: %s\n", session.Branch)
-		}
-	}
-
-	if !removeWorktree {
-		fmt.Printf("Session for work item %s stopped. Worktree preserved at: %s\n",
-			workItemID, session.WorktreePath)
-	} else {
-		fmt.Printf("Session for work item %s stopped and worktree removed.\n", workItemID)
-	}
-
 	return nil
 }
 
@@ -162,17 +271,10 @@ func removeWorktreeForSession(session *config.SessionMetadata) error {
 		return fmt.Errorf("no worktree path associated with session")
 	}
 
-	// Initialize repository manager to get current repo
-	repoManager := repo.NewManager()
-	currentRepo, err := repoManager.DetectCurrentRepository()
+	// Git manager for the current repository
+	gitManager, err := appServices().GitManager()
 	if err != nil {
-		return fmt.Errorf("must be run from within a git repository: %w", err)
-	}
-
-	// Initialize git manager
-	gitManager, err := git.NewManager(currentRepo.Root)
-	if err != nil {
-		return fmt.Errorf("failed to initialize git manager: %w", err)
+		return err
 	}
 
 	// Use the enhanced worktree removal method
@@ -190,17 +292,10 @@ func deleteBranchForSession(session *config.SessionMetadata) error {
 		return fmt.Errorf("no branch associated with session")
 	}
 
-	// Initialize repository manager to get current repo
-	repoManager := repo.NewManager()
-	currentRepo, err := repoManager.DetectCurrentRepository()
-	if err != nil {
-		return fmt.Errorf("must be run from within a git repository: %w", err)
-	}
-
-	// Initialize git manager
-	gitManager, err := git.NewManager(currentRepo.Root)
+	// Git manager for the current repository
+	gitManager, err := appServices().GitManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize git manager: %w", err)
+		return err
 	}
 
 	// Validate branch deletion is safe
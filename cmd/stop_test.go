@@ -6,6 +6,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/cleanup"
 )
 
 func TestStopCommand_BranchCleanup(t *testing.T) {
@@ -261,3 +263,36 @@ func TestStopCommand_VariousScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestStopResources(t *testing.T) {
+	tests := []struct {
+		name           string
+		only           []string
+		removeWorktree bool
+		deleteBranch   bool
+		expected       cleanup.ResourceMask
+		expectError    string
+	}{
+		{name: "default", expected: cleanup.ResourceTmux | cleanup.ResourceSandbox},
+		{name: "default_with_worktree_and_branch", removeWorktree: true, deleteBranch: true,
+			expected: cleanup.ResourceTmux | cleanup.ResourceSandbox | cleanup.ResourceWorktree | cleanup.ResourceBranch},
+		{name: "only_tmux", only: []string{"tmux"}, expected: cleanup.ResourceTmux},
+		{name: "only_worktree_with_flag", only: []string{"worktree"}, removeWorktree: true, expected: cleanup.ResourceWorktree},
+		{name: "conflicting_worktree_flag", only: []string{"tmux"}, removeWorktree: true, expectError: "--remove-worktree"},
+		{name: "conflicting_branch_flag", only: []string{"sandbox"}, deleteBranch: true, expectError: "--delete-branch"},
+		{name: "unknown_resource", only: []string{"memory"}, expectError: "invalid --only value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := stopResources(tt.only, tt.removeWorktree, tt.deleteBranch)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resources)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary [work-item-id]",
+	Short: "Summarize the work done in a session",
+	Long: `Summarize the work on a session's branch: commits ahead of the base
+branch, files changed with a diffstat, and TODO/FIXME markers added.
+
+The output is suitable for a standup note, or with --markdown for a pull
+request body.
+
+Work item ID formats:
+  sbs summary 123                 # Primary work type
+  sbs summary test:my-test        # Test work type
+  sbs summary --markdown | gh pr create --body-file -
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runSummary,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+	summaryCmd.Flags().Bool("markdown", false, "Render the summary as Markdown for a pull request body")
+	summaryCmd.Flags().String("base", "", "Base branch to compare against (default: main or master)")
+}
+
+func runSummary(cmd *cobra.Command, args []string) error {
+	markdown, _ := cmd.Flags().GetBool("markdown")
+	base, _ := cmd.Flags().GetString("base")
+
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var session *config.SessionMetadata
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return sessionNotFound(workItemID)
+	}
+
+	summary, err := summarizeSession(session, base)
+	if err != nil {
+		return err
+	}
+
+	if markdown {
+		fmt.Print(summary.Markdown())
+	} else {
+		fmt.Print(summary.Text())
+	}
+	return nil
+}
+
+// summarizeSession summarizes the session's branch in its own repository
+func summarizeSession(session *config.SessionMetadata, base string) (*git.BranchSummary, error) {
+	if session.Branch == "" {
+		return nil, fmt.Errorf("session for work item %s has no branch", session.NamespacedID)
+	}
+
+	gitManager, err := sessionGitManager(session)
+	if err != nil {
+		return nil, err
+	}
+
+	if base == "" {
+		base, err = gitManager.DefaultBaseBranch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine base branch (use --base): %w", err)
+		}
+	}
+
+	return gitManager.SummarizeBranch(session.Branch, base)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestSummaryCommand_Structure(t *testing.T) {
+	require.NotNil(t, summaryCmd.Flags().Lookup("markdown"))
+	require.NotNil(t, summaryCmd.Flags().Lookup("base"))
+
+	assert.NoError(t, summaryCmd.Args(summaryCmd, []string{}))
+	assert.NoError(t, summaryCmd.Args(summaryCmd, []string{"github:1"}))
+	assert.Error(t, summaryCmd.Args(summaryCmd, []string{"github:1", "extra"}))
+}
+
+func TestSummarizeSession_RequiresBranch(t *testing.T) {
+	_, err := summarizeSession(&config.SessionMetadata{NamespacedID: "test:nobranch"}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no branch")
+}
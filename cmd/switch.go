@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/messages"
+	"sbs/pkg/tmux"
+	"sbs/pkg/tui"
+)
+
+var switchCmd = &cobra.Command{
+	Use:   "switch [query]",
+	Short: "Fuzzy-find a session and switch to it",
+	Long: `Open a minimal fuzzy finder over the active sessions and attach to the
+chosen one on Enter. Inside tmux the current client is switched instead of
+nesting a new attach.
+
+Session status is not checked up front so the finder opens instantly,
+which makes it suitable for a tmux key binding:
+
+  bind-key S display-popup -E "sbs switch"
+
+A query pre-fills the filter; when it matches exactly one session, sbs
+switches to it without opening the finder.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runSwitch,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(switchCmd)
+	switchCmd.Flags().BoolP("all", "a", false, "Include stopped and stale sessions")
+}
+
+func runSwitch(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	candidates := switchCandidates(sessions, all)
+	if len(candidates) == 0 {
+		fmt.Println(messages.Render(messages.NoSessions, nil))
+		return nil
+	}
+
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	model := tui.NewSwitchModel(candidates, query)
+	var selected *config.SessionMetadata
+	if matches := model.Matches(); query != "" && len(matches) == 1 {
+		selected = &matches[0]
+	} else {
+		finalModel, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		if err != nil {
+			return fmt.Errorf("failed to run session switcher: %w", err)
+		}
+		selected = finalModel.(*tui.SwitchModel).Selected()
+	}
+	if selected == nil {
+		return nil
+	}
+
+	return switchToSession(selected)
+}
+
+// switchCandidates returns the sessions offered by the switcher
+func switchCandidates(sessions []config.SessionMetadata, all bool) []config.SessionMetadata {
+	if all {
+		return sessions
+	}
+	candidates := make([]config.SessionMetadata, 0, len(sessions))
+	for _, session := range sessions {
+		if session.Status == "stopped" || session.Status == "stale" {
+			continue
+		}
+		candidates = append(candidates, session)
+	}
+	return candidates
+}
+
+// switchToSession records activity on the session and moves the terminal to it
+func switchToSession(selected *config.SessionMetadata) error {
+	if err := recordSessionActivity(selected.NamespacedID); err != nil {
+		fmt.Printf("Warning: failed to update session activity: %v\n", err)
+	}
+
+	tmuxManager := appServices().TmuxManager()
+	if tmux.InsideTmux() {
+		return tmuxManager.SwitchClient(selected.TmuxSession)
+	}
+
+	var tmuxEnv map[string]string
+	if selected.FriendlyTitle != "" {
+		tmuxEnv = tmux.CreateTmuxEnvironment(selected.FriendlyTitle)
+	}
+	return tmuxManager.AttachToSession(selected.TmuxSession, tmuxEnv)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+func TestSwitchCandidates(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:a", Status: "active"},
+		{NamespacedID: "test:b", Status: "stopped"},
+		{NamespacedID: "test:c", Status: "stale"},
+		{NamespacedID: "test:d"},
+	}
+
+	ids := func(sessions []config.SessionMetadata) []string {
+		var ids []string
+		for _, s := range sessions {
+			ids = append(ids, s.NamespacedID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"test:a", "test:d"}, ids(switchCandidates(sessions, false)))
+	assert.Len(t, switchCandidates(sessions, true), 4)
+}
+
+func TestSwitchCommand_Structure(t *testing.T) {
+	assert.NotNil(t, switchCmd.Flags().Lookup("all"))
+	assert.NoError(t, switchCmd.Args(switchCmd, []string{"query"}))
+	assert.Error(t, switchCmd.Args(switchCmd, []string{"a", "b"}))
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/git"
+	"sbs/pkg/tmux"
+)
+
+// syncWindowName is the tmux window sbs sync opens for resolving conflicts
+const syncWindowName = "sync"
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [work-item-id]",
+	Short: "Rebase a session's branch onto its base branch",
+	Long: `Rebase the branch of a session's worktree onto the base branch (main or
+master unless --base is given), bringing in work merged since it started.
+
+When the rebase stops on conflicts, sbs lists the conflicted files, opens a
+"sync" window in the session's tmux session at the worktree and attaches to
+it, so the conflicts can be resolved where they are. Once the files are
+fixed and staged with git add:
+
+  sbs sync --continue     # Carry on with the rebase
+  sbs sync --abort        # Give up and restore the branch as it was
+
+Use --no-attach to only print the instructions.
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runSync,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().String("base", "", "Branch to rebase onto (default: main or master)")
+	syncCmd.Flags().Bool("continue", false, "Continue a sync stopped on conflicts once they are resolved and staged")
+	syncCmd.Flags().Bool("abort", false, "Abandon a sync stopped on conflicts, restoring the branch")
+	syncCmd.Flags().Bool("no-attach", false, "Don't open the tmux session when the sync stops on conflicts")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	base, _ := cmd.Flags().GetString("base")
+	continueSync, _ := cmd.Flags().GetBool("continue")
+	abortSync, _ := cmd.Flags().GetBool("abort")
+	noAttach, _ := cmd.Flags().GetBool("no-attach")
+	if continueSync && abortSync {
+		return exitcode.Errorf(exitcode.Validation, "--continue and --abort cannot be used together")
+	}
+	if base != "" && (continueSync || abortSync) {
+		return exitcode.Errorf(exitcode.Validation, "--base only applies when starting a sync")
+	}
+
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := config.LoadSessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	var session *config.SessionMetadata
+	for i := range sessions {
+		if sessions[i].NamespacedID == workItemID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return sessionNotFound(workItemID)
+	}
+	if _, err := os.Stat(session.WorktreePath); err != nil {
+		return fmt.Errorf("worktree of work item %s is missing: %w", workItemID, err)
+	}
+
+	gitManager, err := sessionGitManager(session)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case abortSync:
+		if err := gitManager.AbortRebase(session.WorktreePath); err != nil {
+			if errors.Is(err, git.ErrNoRebaseInProgress) {
+				return exitcode.Errorf(exitcode.Validation, "no sync in progress for work item %s", workItemID)
+			}
+			return fmt.Errorf("failed to abort sync: %w", err)
+		}
+		fmt.Printf("Aborted the sync of %s; %s is back as it was\n", workItemID, session.Branch)
+		return nil
+
+	case continueSync:
+		err = gitManager.ContinueRebase(session.WorktreePath)
+		if errors.Is(err, git.ErrNoRebaseInProgress) {
+			return exitcode.Errorf(exitcode.Validation, "no sync in progress for work item %s", workItemID)
+		}
+		if err == nil {
+			fmt.Printf("Finished syncing %s\n", workItemID)
+			return nil
+		}
+
+	default:
+		if base == "" {
+			base, err = gitManager.DefaultBaseBranch()
+			if err != nil {
+				return fmt.Errorf("failed to determine base branch (use --base): %w", err)
+			}
+		}
+		if inProgress, _ := git.RebaseInProgress(session.WorktreePath); inProgress {
+			return exitcode.Errorf(exitcode.Busy, "a sync of %s is already in progress; resolve it and run 'sbs sync %s --continue', or 'sbs sync %s --abort'", workItemID, workItemID, workItemID)
+		}
+		err = gitManager.RebaseWorktree(session.WorktreePath, base)
+		if err == nil {
+			fmt.Printf("Rebased %s onto %s\n", session.Branch, base)
+			return nil
+		}
+	}
+
+	var conflict *git.RebaseConflictError
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("failed to sync %s: %w", workItemID, err)
+	}
+
+	guidance := syncConflictGuidance(workItemID, session.WorktreePath, conflict.Files)
+	fmt.Print(guidance)
+	if !noAttach && term.IsTerminal(int(os.Stdin.Fd())) {
+		if err := attachForConflicts(session, guidance); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return exitcode.Errorf(exitcode.Busy, "sync of %s stopped on conflicts", workItemID)
+}
+
+// sessionGitManager opens the repository a session belongs to
+func sessionGitManager(session *config.SessionMetadata) (*git.Manager, error) {
+	repoRoot := session.RepositoryRoot
+	if repoRoot == "" {
+		currentRepo, err := appServices().Repository()
+		if err != nil {
+			return nil, fmt.Errorf("session has no repository root and not in a git repository: %w", err)
+		}
+		repoRoot = currentRepo.Root
+	}
+	return git.NewManager(repoRoot)
+}
+
+// syncConflictGuidance explains how to finish or abandon a sync stopped on
+// conflicts in files
+func syncConflictGuidance(workItemID, worktreePath string, files []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sync of %s stopped on conflicts in %s\n", workItemID, worktreePath)
+	if len(files) > 0 {
+		b.WriteString("\nConflicted files:\n")
+		for _, file := range files {
+			fmt.Fprintf(&b, "  %s\n", file)
+		}
+	}
+	b.WriteString("\nTo finish the sync:\n")
+	b.WriteString("  1. Edit the files to resolve the conflict markers\n")
+	b.WriteString("  2. Stage them with git add\n")
+	fmt.Fprintf(&b, "  3. Run: sbs sync %s --continue\n", workItemID)
+	fmt.Fprintf(&b, "\nTo give up and restore the branch: sbs sync %s --abort\n", workItemID)
+	return b.String()
+}
+
+// attachForConflicts opens a window at the session's worktree showing git
+// status and the guidance, and attaches to it (or switches to it inside tmux)
+func attachForConflicts(session *config.SessionMetadata, guidance string) error {
+	tmuxManager := appServices().TmuxManager()
+	exists, err := tmuxManager.SessionExists(session.TmuxSession)
+	if err != nil {
+		return fmt.Errorf("failed to check tmux session: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("tmux session %s is not running; resolve the conflicts in %s", session.TmuxSession, session.WorktreePath)
+	}
+
+	command := "git status; printf '\\n%s' " + tmux.ShellQuote(guidance) + `; exec "${SHELL:-/bin/sh}"`
+	if err := tmuxManager.NewWindow(session.TmuxSession, syncWindowName, session.WorktreePath, command); err != nil {
+		return err
+	}
+	if tmux.InsideTmux() {
+		return tmuxManager.SwitchClient(session.TmuxSession)
+	}
+	return tmuxManager.AttachToSession(session.TmuxSession)
+}
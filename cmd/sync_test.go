@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestSyncCommand_Structure(t *testing.T) {
+	for _, flag := range []string{"base", "continue", "abort", "no-attach"} {
+		require.NotNil(t, syncCmd.Flags().Lookup(flag), flag)
+	}
+	assert.NoError(t, syncCmd.Args(syncCmd, []string{}))
+	assert.Error(t, syncCmd.Args(syncCmd, []string{"github:1", "extra"}))
+}
+
+func TestSyncConflictGuidance(t *testing.T) {
+	guidance := syncConflictGuidance("github:7", "/work/repo/issue-7", []string{"app.go", "pkg/db.go"})
+	assert.Contains(t, guidance, "Sync of github:7 stopped on conflicts in /work/repo/issue-7")
+	assert.Contains(t, guidance, "Conflicted files:\n  app.go\n  pkg/db.go\n")
+	assert.Contains(t, guidance, "sbs sync github:7 --continue")
+	assert.Contains(t, guidance, "sbs sync github:7 --abort")
+}
+
+func TestRunSync_Conflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "test")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "test@example.com")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+
+	dir := t.TempDir()
+	repoDir := filepath.Join(dir, "repo")
+	worktreeDir := filepath.Join(dir, "worktree")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	run := func(cwd string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cwd
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	commit := func(cwd, content, message string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(cwd, "app.go"), []byte(content), 0644))
+		run(cwd, "commit", "-q", "-am", message)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "app.go"), []byte("package app\n"), 0644))
+	run(repoDir, "init", "-q", "-b", "main")
+	run(repoDir, "add", ".")
+	run(repoDir, "commit", "-q", "-m", "initial")
+	run(repoDir, "worktree", "add", "-q", "-b", "issue-test-7", worktreeDir)
+	commit(worktreeDir, "package app // branch\n", "branch change")
+	commit(repoDir, "package app // main\n", "main change")
+
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{{
+		NamespacedID:   "test:7",
+		Branch:         "issue-test-7",
+		WorktreePath:   worktreeDir,
+		RepositoryRoot: repoDir,
+		TmuxSession:    "sbs-test-7",
+	}}))
+	setFlags := func(values map[string]string) {
+		t.Helper()
+		for name, value := range values {
+			require.NoError(t, syncCmd.Flags().Set(name, value))
+		}
+	}
+	t.Cleanup(func() { setFlags(map[string]string{"continue": "false", "abort": "false", "no-attach": "false"}) })
+	setFlags(map[string]string{"no-attach": "true"})
+
+	var err error
+	output := captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	assert.EqualError(t, err, "sync of test:7 stopped on conflicts")
+	assert.Contains(t, output, "Conflicted files:\n  app.go\n")
+
+	captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	assert.ErrorContains(t, err, "already in progress")
+
+	setFlags(map[string]string{"abort": "true"})
+	output = captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	require.NoError(t, err)
+	assert.Contains(t, output, "Aborted the sync of test:7")
+
+	captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	assert.EqualError(t, err, "no sync in progress for work item test:7")
+
+	setFlags(map[string]string{"abort": "false"})
+	captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeDir, "app.go"), []byte("package app // both\n"), 0644))
+	run(worktreeDir, "add", "app.go")
+	setFlags(map[string]string{"continue": "true"})
+	output = captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	require.NoError(t, err)
+	assert.Contains(t, output, "Finished syncing test:7")
+
+	setFlags(map[string]string{"continue": "false", "abort": "true"})
+	captureStdout(t, func() { err = runSync(syncCmd, []string{"test:7"}) })
+	assert.EqualError(t, err, "no sync in progress for work item test:7")
+	setFlags(map[string]string{"continue": "true"})
+	assert.EqualError(t, runSync(syncCmd, []string{"test:7"}), "--continue and --abort cannot be used together")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/resmon"
+	"sbs/pkg/tui"
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show live CPU, memory, IO and disk usage per session",
+	Long: `Launch an htop-like monitor showing the resources each work session uses:
+the processes running in its tmux panes and its sandbox (CPU, resident
+memory, disk reads and writes per second) and the size of its worktree.
+
+Processes are sampled every few seconds; worktree sizes are recalculated once
+a minute. Sort by CPU (c), memory (m), IO (i), disk (d) or name (n), and
+press r to reverse the order. Requires Linux, as processes are read from /proc.`,
+	Args:        cobra.NoArgs,
+	RunE:        runTop,
+	Annotations: map[string]string{skipToolValidation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.Flags().Duration("interval", tui.DefaultTopInterval, "How often to sample processes")
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return exitcode.Errorf(exitcode.Validation, "--interval must be positive")
+	}
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("sbs top requires Linux (processes are read from /proc)")
+	}
+
+	container := appServices()
+	model := tui.NewTopModel(tui.TopSources{
+		Sessions: config.LoadSessions,
+		PanePIDs: container.TmuxManager().PanePIDs,
+		Monitor:  resmon.NewMonitor(),
+		Interval: interval,
+	})
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(container.Context()))
+
+	_, err := program.Run()
+	return err
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"sbs/pkg/branchname"
+	"sbs/pkg/config"
+	"sbs/pkg/trace"
+)
+
+// redactedValue replaces secrets in traced config
+const redactedValue = "[redacted]"
+
+// traceConfig records the global config an invocation runs with
+func traceConfig(cfg *config.Config) {
+	if !trace.Enabled() {
+		return
+	}
+	source := "defaults"
+	if path, err := config.GlobalConfigPath(); err == nil {
+		source = path
+	}
+	trace.Decision("config", tracedConfig(cfg), source)
+	trace.Decision("branch_template", branchname.Template(), "branch_template")
+}
+
+// traceRepoConfig records the config a command uses in a repository: the
+// global config merged with the repository's .sbs/config.json, if any
+func traceRepoConfig(repoRoot string, merged *config.Config) {
+	if !trace.Enabled() {
+		return
+	}
+	source := "global config (no " + config.RepositoryConfigPath(repoRoot) + ")"
+	if _, err := os.Stat(config.RepositoryConfigPath(repoRoot)); err == nil {
+		source = "global config merged with " + config.RepositoryConfigPath(repoRoot)
+	}
+	trace.Decision("repository config", tracedConfig(merged), source)
+}
+
+// tracedConfig renders a config as JSON with its secrets redacted
+func tracedConfig(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	redacted := *cfg
+	if redacted.GitHubToken != "" {
+		redacted.GitHubToken = redactedValue
+	}
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
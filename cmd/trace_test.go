@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sbs/pkg/config"
+	"sbs/pkg/trace"
+)
+
+func TestTracedConfig_RedactsToken(t *testing.T) {
+	cfg := &config.Config{GitHubToken: "ghp_secret", WorktreeBasePath: "/work"}
+
+	traced := tracedConfig(cfg)
+	assert.NotContains(t, traced, "ghp_secret")
+	assert.Contains(t, traced, redactedValue)
+	assert.Contains(t, traced, "/work")
+	assert.Equal(t, "ghp_secret", cfg.GitHubToken, "the config itself is left alone")
+}
+
+func TestTraceRepoConfig_RecordsMergedConfig(t *testing.T) {
+	var buf bytes.Buffer
+	trace.SetGlobalRecorder(trace.NewRecorder(&buf))
+	t.Cleanup(func() { trace.SetGlobalRecorder(nil) })
+
+	repoRoot := t.TempDir()
+	traceRepoConfig(repoRoot, &config.Config{TmuxCommand: "claude"})
+
+	assert.Contains(t, buf.String(), `"name":"repository config"`)
+	assert.Contains(t, buf.String(), `tmux_command`)
+	assert.Contains(t, buf.String(), "no "+config.RepositoryConfigPath(repoRoot))
+}
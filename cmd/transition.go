@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/inputsource"
+)
+
+var transitionCmd = &cobra.Command{
+	Use:   "transition [work-item-id]",
+	Short: "Update the state of a work item in its input source",
+	Long: `Update the state of the work item in the tracker it came from.
+
+Work item ID formats:
+  sbs transition 123 --state in-review         # Primary work type
+  sbs transition github:123 --state done       # Namespaced work item
+  sbs transition test:my-test --state done     # Test work type
+
+Supported states: open, in-progress, in-review, done.
+GitHub issues are closed for "done", reopened for "open", and labelled
+with the state name for intermediate states; the label of the previous
+state is removed.
+
+A namespaced ID acts on the repository of its session, a plain ID on the
+current repository. Outside a repository, a plain ID must match exactly one
+session.
+
+Run from inside a session worktree, the work item ID can be omitted and the
+session owning the worktree is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTransition,
+}
+
+func init() {
+	rootCmd.AddCommand(transitionCmd)
+	transitionCmd.Flags().StringP("state", "s", "", "Target state (open, in-progress, in-review, done)")
+	transitionCmd.MarkFlagRequired("state")
+}
+
+func runTransition(cmd *cobra.Command, args []string) error {
+	state, _ := cmd.Flags().GetString("state")
+	workItemID, err := workItemIDArg(args)
+	if err != nil {
+		return err
+	}
+	return transitionWorkItem(workItemID, state)
+}
+
+// transitionWorkItem resolves the input source for a work item and updates its state
+func transitionWorkItem(workItemID, state string) error {
+	canonical, err := inputsource.NormalizeState(state)
+	if err != nil {
+		return err
+	}
+
+	source, id, err := resolveWorkItemSource(workItemID)
+	if err != nil {
+		return err
+	}
+
+	if err := inputsource.TransitionWorkItem(source, id, canonical); err != nil {
+		return fmt.Errorf("failed to transition work item %s: %w", workItemID, err)
+	}
+
+	fmt.Printf("Work item %s:%s transitioned to %s\n", source.GetType(), id, canonical)
+	return nil
+}
+
+// resolveWorkItemSource finds the input source responsible for a work item ID.
+// Namespaced IDs (source:id) select the source explicitly; plain IDs use the
+// project's primary input source. When a session exists for the work item,
+// its repository is used to load the input source configuration.
+func resolveWorkItemSource(workItemID string) (inputsource.InputSource, string, error) {
+	sourceType := ""
+	id := strings.TrimSpace(workItemID)
+
+	if strings.Contains(id, ":") {
+		parsed, err := inputsource.ParseWorkItemID(id)
+		if err != nil {
+			return nil, "", exitcode.Errorf(exitcode.Validation, "invalid work item ID: %w", err)
+		}
+		sourceType = parsed.Source
+		id = parsed.ID
+	}
+
+	if id == "" {
+		return nil, "", exitcode.Errorf(exitcode.Validation, "work item ID cannot be empty")
+	}
+
+	// Test work types are always available regardless of project configuration
+	if sourceType == "test" {
+		return inputsource.NewTestInputSource(), id, nil
+	}
+
+	projectRoot, err := resolveWorkItemProjectRoot(sourceType, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	factory := inputsource.NewInputSourceFactory()
+	source, err := factory.CreateFromProject(projectRoot)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create input source: %w", err)
+	}
+
+	if sourceType != "" && source.GetType() != sourceType {
+		return nil, "", exitcode.Errorf(exitcode.Validation, "work item source %s does not match project input source %s", sourceType, source.GetType())
+	}
+
+	return source, id, nil
+}
+
+// resolveWorkItemProjectRoot returns the repository root owning the work
+// item. A namespaced ID uses its session's repository. A plain ID belongs to
+// the current repository; outside one it must match exactly one session.
+func resolveWorkItemProjectRoot(sourceType, id string) (string, error) {
+	sessions, _ := config.LoadSessions()
+	if sourceType != "" {
+		for _, session := range sessions {
+			if session.RepositoryRoot != "" && session.NamespacedID == fmt.Sprintf("%s:%s", sourceType, id) {
+				return session.RepositoryRoot, nil
+			}
+		}
+	}
+
+	currentRepo, repoErr := appServices().Repository()
+	if repoErr == nil {
+		return currentRepo.Root, nil
+	}
+	if sourceType != "" {
+		return "", fmt.Errorf("no session found for work item %s:%s and not in a git repository: %w", sourceType, id, repoErr)
+	}
+
+	var matches []config.SessionMetadata
+	for _, session := range sessions {
+		if session.RepositoryRoot != "" && session.SourceType != "test" && strings.HasSuffix(session.NamespacedID, ":"+id) {
+			matches = append(matches, session)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no session found for work item %s and not in a git repository: %w", id, repoErr)
+	case 1:
+		return matches[0].RepositoryRoot, nil
+	}
+	candidates := make([]string, len(matches))
+	for i, session := range matches {
+		candidates[i] = fmt.Sprintf("%s (%s)", session.NamespacedID, session.RepositoryRoot)
+	}
+	return "", exitcode.Errorf(exitcode.Validation, "work item %s matches sessions in several repositories: %s; run from the repository or use a namespaced ID", id, strings.Join(candidates, ", "))
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/app"
+	"sbs/pkg/config"
+)
+
+func TestTransitionCommand_Structure(t *testing.T) {
+	t.Run("transition_command_registered", func(t *testing.T) {
+		found := false
+		for _, c := range rootCmd.Commands() {
+			if c.Name() == "transition" {
+				found = true
+			}
+		}
+		assert.True(t, found, "transition command should be registered")
+	})
+
+	t.Run("state_flag_exists", func(t *testing.T) {
+		flag := transitionCmd.Flags().Lookup("state")
+		require.NotNil(t, flag)
+		assert.Equal(t, "s", flag.Shorthand)
+	})
+
+	t.Run("done_command_registered", func(t *testing.T) {
+		found := false
+		for _, c := range rootCmd.Commands() {
+			if c.Name() == "done" {
+				found = true
+			}
+		}
+		assert.True(t, found, "done command should be registered")
+	})
+
+	t.Run("stop_has_done_flag", func(t *testing.T) {
+		flag := stopCmd.Flags().Lookup("done")
+		require.NotNil(t, flag)
+		assert.Equal(t, "false", flag.DefValue)
+	})
+}
+
+func TestResolveWorkItemSource(t *testing.T) {
+	t.Run("test_work_item", func(t *testing.T) {
+		source, id, err := resolveWorkItemSource("test:my-test")
+		require.NoError(t, err)
+		assert.Equal(t, "test", source.GetType())
+		assert.Equal(t, "my-test", id)
+	})
+
+	t.Run("invalid_namespaced_id", func(t *testing.T) {
+		_, _, err := resolveWorkItemSource("github:")
+		assert.Error(t, err)
+	})
+}
+
+func TestTransitionWorkItem_InvalidState(t *testing.T) {
+	err := transitionWorkItem("test:my-test", "not-a-state")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown state")
+}
+
+func TestResolveWorkItemProjectRoot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	original := services
+	defer func() { services = original }()
+
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{
+		{NamespacedID: "github:123", SourceType: "github", RepositoryRoot: "/src/web"},
+		{NamespacedID: "linear:123", SourceType: "linear", RepositoryRoot: "/src/api"},
+		{NamespacedID: "github:7", SourceType: "github", RepositoryRoot: "/src/web"},
+	}))
+
+	t.Run("namespaced_id_uses_its_session", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		services = app.NewContainer(&config.Config{})
+		root, err := resolveWorkItemProjectRoot("github", "123")
+		require.NoError(t, err)
+		assert.Equal(t, "/src/web", root)
+	})
+
+	t.Run("plain_id_outside_a_repository", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		services = app.NewContainer(&config.Config{})
+		root, err := resolveWorkItemProjectRoot("", "7")
+		require.NoError(t, err)
+		assert.Equal(t, "/src/web", root)
+
+		_, err = resolveWorkItemProjectRoot("", "123")
+		assert.ErrorContains(t, err, "matches sessions in several repositories")
+	})
+
+	t.Run("plain_id_uses_the_current_repository", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, exec.Command("git", "-C", dir, "init", "-q").Run())
+		t.Chdir(dir)
+		services = app.NewContainer(&config.Config{})
+		root, err := resolveWorkItemProjectRoot("", "123")
+		require.NoError(t, err)
+		resolved, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		assert.Equal(t, resolved, root, "a session of the same number elsewhere doesn't redirect a plain ID")
+	})
+}
@@ -6,11 +6,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// sbsVersion is the version reported by 'sbs version' and recorded in traces
+const sbsVersion = "v1.0.0"
+
 var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Show version information",
+	Use:         "version",
+	Short:       "Show version information",
+	Annotations: map[string]string{skipToolValidation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("SBS (Sandbox Sessions) v1.0.0")
+		fmt.Printf("SBS (Sandbox Sessions) %s\n", sbsVersion)
 		fmt.Println("A GitHub issue work environment manager")
 	},
 }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+	"sbs/pkg/notify"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Notify about agents left waiting for input",
+	Long: `Check the sessions periodically and tell the notifiers in the global config
+about agents that have waited for input longer than notify_waiting_minutes
+(default 30). Each wait is announced once, even across restarts of sbs watch.
+
+'sbs clean' and 'sbs start' tell the notifiers about cleanups and failed starts
+themselves, so those are sent whether or not sbs watch runs.
+
+  sbs watch              # Check every minute until interrupted
+  sbs watch --once       # Check once, e.g. from cron`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().Duration("interval", time.Minute, "How often to check the sessions")
+	watchCmd.Flags().Bool("once", false, "Check once and exit")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	once, _ := cmd.Flags().GetBool("once")
+	if interval <= 0 {
+		return exitcode.Errorf(exitcode.Validation, "--interval must be positive")
+	}
+
+	dispatcher := appServices().Notifications()
+	if dispatcher == nil {
+		return exitcode.Errorf(exitcode.Validation, "no notifiers are configured; add notifiers to the global config")
+	}
+	path, err := notify.DefaultWaitingNoticesPath()
+	if err != nil {
+		return err
+	}
+	minutes := appServices().Config().NotifyWaitingMins
+	if minutes == 0 {
+		minutes = config.DefaultNotifyWaitingMins
+	}
+	notifier := notify.NewWaitingNotifier(path, time.Duration(minutes)*time.Minute, dispatcher.Send)
+
+	ctx := appServices().Context()
+	if !once {
+		fmt.Printf("Watching for agents waiting more than %dm, checking every %s (Ctrl+C to stop)\n", minutes, interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := checkWaitingSessions(notifier)
+		if once {
+			return err
+		}
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkWaitingSessions announces the agents waiting too long and prints
+// which were announced
+func checkWaitingSessions(notifier *notify.WaitingNotifier) error {
+	sessions, err := config.LoadAllRepositorySessions()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+	notified, err := notifier.Notify(waitingSessions(sessions, appServices().StatusDetector()), config.Now())
+	for _, id := range notified {
+		fmt.Printf("%s Notified: %s is waiting for input\n", config.Now().Format("15:04:05"), id)
+	}
+	return err
+}
+
+// waitingSessions returns the sessions whose agent is waiting for input,
+// with when it started waiting
+func waitingSessions(sessions []config.SessionMetadata, detector sessionStatusDetector) []notify.Waiter {
+	var waiters []notify.Waiter
+	for _, session := range sessions {
+		sessionStatus := detector.DetectSessionStatus(session)
+		if sessionStatus.Status != "waiting" || sessionStatus.LastChange == nil {
+			continue
+		}
+		waiters = append(waiters, notify.Waiter{Session: session, Since: *sessionStatus.LastChange, Prompt: sessionStatus.Message})
+	}
+	return waiters
+}
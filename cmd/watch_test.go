@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/app"
+	"sbs/pkg/config"
+	"sbs/pkg/exitcode"
+)
+
+func TestWaitingSessions(t *testing.T) {
+	since := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1"},
+		{NamespacedID: "github:2"},
+		{NamespacedID: "github:3"},
+	}
+	detector := fakeStatusDetector{
+		"github:1": {Status: "active"},
+		"github:2": {Status: "waiting", LastChange: &since, Message: "Claude needs your permission to use Bash"},
+		"github:3": {Status: "waiting"},
+	}
+
+	waiters := waitingSessions(sessions, detector)
+
+	require.Len(t, waiters, 1, "waits without a start time can't be measured")
+	assert.Equal(t, "github:2", waiters[0].Session.NamespacedID)
+	assert.Equal(t, since, waiters[0].Since)
+	assert.Equal(t, "Claude needs your permission to use Bash", waiters[0].Prompt)
+}
+
+func TestWatchCommand_RequiresNotifiers(t *testing.T) {
+	original := services
+	defer func() { services = original }()
+	services = app.NewContainer(config.DefaultConfig())
+
+	err := runWatch(watchCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no notifiers are configured")
+	assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+}
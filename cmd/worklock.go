@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sbs/pkg/config"
+	"sbs/pkg/oplock"
+)
+
+// defaultWaitTimeout bounds --wait when --wait-timeout isn't given
+const defaultWaitTimeout = 10 * time.Minute
+
+// addWaitFlags registers the flags choosing what happens when another sbs
+// process is working on the same work item
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait for another sbs process working on the same work item instead of failing")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "How long --wait waits before giving up")
+}
+
+// lockWorkItem claims a work item for op. When another sbs process holds it,
+// this fails at once, or with --wait retries until --wait-timeout.
+func lockWorkItem(cmd *cobra.Command, workItemID string, op oplock.Operation) (*oplock.Lock, error) {
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+
+	if !wait {
+		lock, err := oplock.Acquire(workItemID, op)
+		var conflict *oplock.ConflictError
+		if errors.As(err, &conflict) {
+			return nil, fmt.Errorf("%w; retry with --wait to wait for it", err)
+		}
+		return lock, err
+	}
+
+	ctx, cancel := context.WithTimeout(appServices().Context(), timeout)
+	defer cancel()
+	return oplock.Wait(ctx, workItemID, op, func(conflict *oplock.ConflictError) {
+		fmt.Printf("%v; waiting up to %s...\n", conflict, timeout)
+	})
+}
+
+// upsertSession replaces the session with the same work item ID, or appends it
+func upsertSession(sessions []config.SessionMetadata, session config.SessionMetadata) []config.SessionMetadata {
+	for i := range sessions {
+		if sessions[i].NamespacedID == session.NamespacedID {
+			sessions[i] = session
+			return sessions
+		}
+	}
+	return append(sessions, session)
+}
+
+// updateSession applies change to the stored session of a work item under
+// the sessions lock; a session removed meanwhile is left alone
+func updateSession(workItemID string, change func(*config.SessionMetadata)) error {
+	return config.UpdateSessions(func(sessions []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		for i := range sessions {
+			if sessions[i].NamespacedID == workItemID {
+				change(&sessions[i])
+			}
+		}
+		return sessions, nil
+	})
+}
+
+// claimSessionsForClean takes the clean lock of each session. It returns the
+// sessions claimed, the conflicts for those busy in another sbs process, and
+// the function releasing the claims.
+func claimSessionsForClean(sessions []config.SessionMetadata) ([]config.SessionMetadata, []*oplock.ConflictError, func()) {
+	var claimed []config.SessionMetadata
+	var busy []*oplock.ConflictError
+	var locks []*oplock.Lock
+	for _, session := range sessions {
+		lock, err := oplock.Acquire(session.NamespacedID, oplock.OpClean)
+		var conflict *oplock.ConflictError
+		if errors.As(err, &conflict) {
+			busy = append(busy, conflict)
+			continue
+		}
+		// Locking is best effort; other failures clean the session unlocked
+		locks = append(locks, lock)
+		claimed = append(claimed, session)
+	}
+	release := func() {
+		for _, lock := range locks {
+			lock.Release()
+		}
+	}
+	return claimed, busy, release
+}
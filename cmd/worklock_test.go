@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/naming"
+	"sbs/pkg/oplock"
+)
+
+func TestUpsertSession(t *testing.T) {
+	sessions := []config.SessionMetadata{{NamespacedID: "test:1", Status: "active"}}
+
+	sessions = upsertSession(sessions, config.SessionMetadata{NamespacedID: "test:1", Status: "stopped"})
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "stopped", sessions[0].Status)
+
+	sessions = upsertSession(sessions, config.SessionMetadata{NamespacedID: "test:2"})
+	assert.Len(t, sessions, 2)
+}
+
+func TestLockWorkItem_ConflictSuggestsWait(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	held, err := oplock.Acquire("test:1", oplock.OpClean)
+	require.NoError(t, err)
+	defer held.Release()
+
+	cmd := &cobra.Command{}
+	addWaitFlags(cmd)
+	_, err = lockWorkItem(cmd, "test:1", oplock.OpStart)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "clean in progress")
+	assert.Contains(t, err.Error(), "--wait")
+
+	claimed, busy, release := claimSessionsForClean([]config.SessionMetadata{
+		{NamespacedID: "test:1"}, {NamespacedID: "test:2"},
+	})
+	defer release()
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "test:2", claimed[0].NamespacedID)
+	require.Len(t, busy, 1)
+	assert.Equal(t, "test:1", busy[0].WorkItem)
+}
+
+func TestSessionUpdates_ConcurrentWithCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	naming.SetScope("alice")
+	defer naming.SetScope("")
+
+	require.NoError(t, config.SaveSessions([]config.SessionMetadata{
+		{NamespacedID: "test:attach", Branch: "issue-test-attach"},
+		{NamespacedID: "test:repair", Branch: "issue-test-repair"},
+		{NamespacedID: "test:migrate", RepositoryName: "repo", TmuxSession: "sbs-repo-test-migrate"},
+	}))
+
+	// Starts record new sessions while attach, repair-branch and
+	// migrate-names update the stored ones
+	const starts = 20
+	captureStdout(t, func() {
+		var wg sync.WaitGroup
+		for i := 0; i < starts; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, config.UpdateSessions(func(sessions []config.SessionMetadata) ([]config.SessionMetadata, error) {
+					return upsertSession(sessions, config.SessionMetadata{NamespacedID: fmt.Sprintf("test:start-%d", i)}), nil
+				}))
+			}(i)
+		}
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, recordSessionActivity("test:attach"))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, saveRepairedBranch("test:repair", "issue-test-repair", "issue-test-repair-renamed"))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := migrateStoredSessionNames(fakeTmuxRenamer{newFakeRenamer()}, fakeSandboxRenamer{newFakeRenamer()})
+			assert.NoError(t, err)
+		}()
+		wg.Wait()
+	})
+
+	sessions, err := config.LoadSessions()
+	require.NoError(t, err)
+	byID := make(map[string]config.SessionMetadata, len(sessions))
+	for _, session := range sessions {
+		byID[session.NamespacedID] = session
+	}
+	assert.Len(t, byID, starts+3, "no update was lost")
+	assert.NotEmpty(t, byID["test:attach"].LastActivity)
+	assert.Equal(t, "issue-test-repair-renamed", byID["test:repair"].Branch)
+	assert.Equal(t, "sbs-alice-repo-test-migrate", byID["test:migrate"].TmuxSession)
+}
@@ -385,6 +385,25 @@ func TestE2E_ErrorHandling(t *testing.T) {
 	})
 }
 
+// TestE2E_FaultInjection verifies partial-failure handling using SBS_FAULT_INJECT
+func TestE2E_FaultInjection(t *testing.T) {
+	suite := NewE2ETestSuite(t)
+	defer suite.cleanup()
+
+	for _, step := range []string{"branch", "worktree", "tmux"} {
+		t.Run(step, func(t *testing.T) {
+			t.Setenv("SBS_FAULT_INJECT", step)
+
+			output, err := suite.runSBSCommand("start", "test:fault-"+step, "--no-command")
+			require.Error(t, err, "start should fail when the %s step fails", step)
+			assert.Contains(t, output, "injected fault: "+step)
+
+			listOutput, _ := suite.runSBSCommand("list")
+			assert.NotContains(t, listOutput, "test:fault-"+step, "failed starts should not leave a session behind")
+		})
+	}
+}
+
 // TestE2E_ConfigurationHandling tests configuration scenarios
 func TestE2E_ConfigurationHandling(t *testing.T) {
 	suite := NewE2ETestSuite(t)
@@ -5,11 +5,12 @@ import (
 	"os"
 
 	"sbs/cmd"
+	"sbs/pkg/exitcode"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
 		log.Printf("Error: %v", err)
-		os.Exit(1)
+		os.Exit(exitcode.Of(err))
 	}
 }
@@ -0,0 +1,306 @@
+// Package app provides lazily-constructed application services shared by
+// the CLI commands and the TUI. The root command builds one Container per
+// invocation so commands and the TUI use identically wired managers.
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"sbs/pkg/behind"
+	"sbs/pkg/cleanup"
+	"sbs/pkg/config"
+	"sbs/pkg/diffstat"
+	"sbs/pkg/expiry"
+	"sbs/pkg/git"
+	"sbs/pkg/logarchive"
+	"sbs/pkg/notify"
+	"sbs/pkg/protection"
+	"sbs/pkg/provisioning"
+	"sbs/pkg/repo"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/status"
+	"sbs/pkg/timing"
+	"sbs/pkg/tmux"
+)
+
+// Container builds each service on first use so commands only pay for
+// what they touch. It is safe for concurrent use.
+type Container struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	configOnce sync.Once
+	config     *config.Config
+	configErr  error
+
+	repoOnce    sync.Once
+	repoManager *repo.Manager
+	currentRepo *repo.Repository
+	repoErr     error
+
+	gitOnce    sync.Once
+	gitManager *git.Manager
+	gitErr     error
+
+	tmuxOnce    sync.Once
+	tmuxManager *tmux.Manager
+
+	sandboxOnce    sync.Once
+	sandboxManager *sandbox.Manager
+
+	cleanupOnce    sync.Once
+	cleanupManager *cleanup.CleanupManager
+
+	statusOnce     sync.Once
+	statusDetector *status.Detector
+
+	historyOnce   sync.Once
+	statusHistory *status.HistoryStore
+
+	progressOnce  sync.Once
+	progressBoard *provisioning.ProgressBoard
+
+	diffstatOnce  sync.Once
+	diffstatCache *diffstat.Cache
+
+	behindOnce  sync.Once
+	behindCache *behind.Cache
+
+	archiveOnce sync.Once
+	logArchive  *logarchive.Archiver
+
+	notifierOnce   sync.Once
+	expiryNotifier *expiry.Notifier
+
+	dispatcherOnce sync.Once
+	dispatcher     *notify.Dispatcher
+
+	timingsOnce sync.Once
+	timings     *timing.Store
+}
+
+// NewContainer creates a container. A nil cfg is loaded from disk on first use.
+func NewContainer(cfg *config.Config) *Container {
+	return NewContainerWithContext(context.Background(), cfg)
+}
+
+// NewContainerWithContext creates a container whose managers run external
+// commands under ctx, so cancelling ctx (or calling Close) stops them
+func NewContainerWithContext(ctx context.Context, cfg *config.Config) *Container {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c := &Container{}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	if cfg != nil {
+		c.configOnce.Do(func() { c.config = cfg })
+	}
+	return c
+}
+
+// Context returns the context the container's managers run under
+func (c *Container) Context() context.Context {
+	return c.ctx
+}
+
+// Close cancels the container's context, stopping any external commands
+// still running on its behalf
+func (c *Container) Close() {
+	c.cancel()
+}
+
+// Config returns the loaded configuration, falling back to defaults if it cannot be loaded
+func (c *Container) Config() *config.Config {
+	c.configOnce.Do(func() {
+		defer Track("load config")()
+		c.config, c.configErr = config.LoadConfig()
+		if c.config == nil {
+			c.config = config.DefaultConfig()
+		}
+	})
+	return c.config
+}
+
+// ConfigError returns the error from loading the configuration, if any
+func (c *Container) ConfigError() error {
+	c.Config()
+	return c.configErr
+}
+
+// RepoManager returns the repository manager
+func (c *Container) RepoManager() *repo.Manager {
+	c.initRepo()
+	return c.repoManager
+}
+
+// CurrentRepository returns the repository containing the working directory, or nil outside a repository
+func (c *Container) CurrentRepository() *repo.Repository {
+	c.initRepo()
+	return c.currentRepo
+}
+
+// Repository returns the repository containing the working directory, or the detection error
+func (c *Container) Repository() (*repo.Repository, error) {
+	c.initRepo()
+	return c.currentRepo, c.repoErr
+}
+
+func (c *Container) initRepo() {
+	c.repoOnce.Do(func() {
+		defer Track("detect repository")()
+		c.repoManager = repo.NewManager()
+		c.currentRepo, c.repoErr = c.repoManager.DetectCurrentRepository()
+	})
+}
+
+// GitManager returns the git manager for the current repository
+func (c *Container) GitManager() (*git.Manager, error) {
+	c.gitOnce.Do(func() {
+		currentRepo, err := c.Repository()
+		if err != nil {
+			c.gitErr = fmt.Errorf("must be run from within a git repository: %w", err)
+			return
+		}
+
+		defer Track("init git manager")()
+		gitManager, err := git.NewManager(currentRepo.Root)
+		if err != nil {
+			c.gitErr = fmt.Errorf("failed to initialize git manager: %w", err)
+			return
+		}
+		c.gitManager = gitManager.WithContext(c.ctx).WithProtection(protection.ForRepository(currentRepo.Root))
+	})
+	return c.gitManager, c.gitErr
+}
+
+// TmuxManager returns the tmux manager
+func (c *Container) TmuxManager() *tmux.Manager {
+	c.tmuxOnce.Do(func() {
+		defer Track("init tmux manager")()
+		c.tmuxManager = tmux.NewManager().WithContext(c.ctx).
+			WithAttachEnvironment(tmux.ResolveAttachEnvironment(c.Config().AttachEnvironment))
+	})
+	return c.tmuxManager
+}
+
+// SandboxManager returns the sandbox manager
+func (c *Container) SandboxManager() *sandbox.Manager {
+	c.sandboxOnce.Do(func() {
+		defer Track("init sandbox manager")()
+		c.sandboxManager = sandbox.NewManager().WithContext(c.ctx).WithRequired(c.Config().SandboxRequired)
+	})
+	return c.sandboxManager
+}
+
+// CleanupManager returns the cleanup manager wired to the tmux and sandbox managers
+func (c *Container) CleanupManager() *cleanup.CleanupManager {
+	c.cleanupOnce.Do(func() {
+		tmuxManager := c.TmuxManager()
+		sandboxManager := c.SandboxManager()
+		defer Track("init cleanup manager")()
+		c.cleanupManager = cleanup.NewCleanupManager(tmuxManager, sandboxManager, nil, nil).
+			WithSessionLocker(cleanup.OperationLocker).
+			WithProtection(protection.ForRepository).
+			WithExpiryPolicy(expiry.FromConfig(c.Config()))
+	})
+	return c.cleanupManager
+}
+
+// StatusDetector returns the session status detector wired to the tmux and sandbox managers
+func (c *Container) StatusDetector() *status.Detector {
+	c.statusOnce.Do(func() {
+		c.statusDetector = status.NewDetector(c.TmuxManager(), c.SandboxManager())
+	})
+	return c.statusDetector
+}
+
+// StatusHistory returns the store of recorded session status samples, or nil
+// when the home directory cannot be determined
+func (c *Container) StatusHistory() *status.HistoryStore {
+	c.historyOnce.Do(func() {
+		if path, err := status.DefaultHistoryPath(); err == nil {
+			c.statusHistory = status.NewHistoryStore(path)
+		}
+	})
+	return c.statusHistory
+}
+
+// DiffstatCache returns the cache of session branch diffstats, or nil when
+// the home directory cannot be determined
+func (c *Container) DiffstatCache() *diffstat.Cache {
+	c.diffstatOnce.Do(func() {
+		if path, err := diffstat.DefaultPath(); err == nil {
+			c.diffstatCache = diffstat.NewCache(path)
+		}
+	})
+	return c.diffstatCache
+}
+
+// BehindCache returns the cache of how far session branches are behind their
+// base, or nil when the home directory cannot be determined
+func (c *Container) BehindCache() *behind.Cache {
+	c.behindOnce.Do(func() {
+		if path, err := behind.DefaultPath(); err == nil {
+			c.behindCache = behind.NewCache(path)
+		}
+	})
+	return c.behindCache
+}
+
+// LogArchive returns the archiver loghook output is kept with, or nil when
+// loghook_archive is off or its store can't be set up
+func (c *Container) LogArchive() *logarchive.Archiver {
+	c.archiveOnce.Do(func() {
+		if store, err := logarchive.FromConfig(c.Config()); err == nil {
+			c.logArchive = logarchive.NewArchiver(store)
+		}
+	})
+	return c.logArchive
+}
+
+// Timings returns the store of recorded slow command durations, or nil when
+// the home directory cannot be determined
+func (c *Container) Timings() *timing.Store {
+	c.timingsOnce.Do(func() {
+		if path, err := timing.DefaultPath(); err == nil {
+			c.timings = timing.NewStore(path)
+		}
+	})
+	return c.timings
+}
+
+// ExpiryNotifier returns the desktop notifier for sessions about to expire,
+// or nil when expiry_notify is off or the home directory cannot be determined
+func (c *Container) ExpiryNotifier() *expiry.Notifier {
+	c.notifierOnce.Do(func() {
+		if !c.Config().ExpiryNotify {
+			return
+		}
+		if path, err := expiry.DefaultNoticesPath(); err == nil {
+			c.expiryNotifier = expiry.NewNotifier(path, expiry.DesktopNotification)
+		}
+	})
+	return c.expiryNotifier
+}
+
+// Notifications returns the dispatcher for the notifiers in the global
+// config, or nil when none are configured
+func (c *Container) Notifications() *notify.Dispatcher {
+	c.dispatcherOnce.Do(func() {
+		c.dispatcher = notify.NewDispatcher(c.Config().Notifiers)
+	})
+	return c.dispatcher
+}
+
+// ProgressBoard returns the board where sbs start publishes the progress of
+// sessions being started, or nil when the home directory cannot be determined
+func (c *Container) ProgressBoard() *provisioning.ProgressBoard {
+	c.progressOnce.Do(func() {
+		if dir, err := provisioning.DefaultProgressDir(); err == nil {
+			c.progressBoard = provisioning.NewProgressBoard(dir)
+		}
+	})
+	return c.progressBoard
+}
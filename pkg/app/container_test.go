@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestContainer_UsesProvidedConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.WorktreeBasePath = "/custom/worktrees"
+
+	c := NewContainer(cfg)
+	assert.Same(t, cfg, c.Config())
+	assert.NoError(t, c.ConfigError())
+}
+
+func TestContainer_LazyServices(t *testing.T) {
+	original := GetGlobalProfiler()
+	defer SetGlobalProfiler(original)
+	profiler := NewProfiler(time.Now())
+	SetGlobalProfiler(profiler)
+
+	c := NewContainer(config.DefaultConfig())
+	assert.Empty(t, profiler.Phases(), "nothing should be built until first use")
+
+	tmuxManager := c.TmuxManager()
+	require.NotNil(t, tmuxManager)
+	assert.Same(t, tmuxManager, c.TmuxManager(), "services are built once")
+
+	cleanupManager := c.CleanupManager()
+	require.NotNil(t, cleanupManager)
+	assert.Same(t, cleanupManager, c.CleanupManager())
+	assert.NotNil(t, c.SandboxManager(), "cleanup manager pulls in the sandbox manager")
+
+	var names []string
+	for _, phase := range profiler.Phases() {
+		names = append(names, phase.Name)
+	}
+	assert.Equal(t, []string{"init tmux manager", "init sandbox manager", "init cleanup manager"}, names)
+}
+
+func TestContainer_CurrentRepository(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	c := NewContainer(config.DefaultConfig())
+	assert.Nil(t, c.CurrentRepository(), "no repository outside a git checkout")
+	assert.NotNil(t, c.RepoManager())
+}
+
+func TestContainer_OutsideRepository(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	c := NewContainer(config.DefaultConfig())
+
+	currentRepo, err := c.Repository()
+	assert.Nil(t, currentRepo)
+	assert.Error(t, err)
+
+	gitManager, err := c.GitManager()
+	assert.Nil(t, gitManager)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be run from within a git repository")
+}
+
+func TestContainer_SharedWiring(t *testing.T) {
+	c := NewContainer(config.DefaultConfig())
+
+	detector := c.StatusDetector()
+	require.NotNil(t, detector)
+	assert.Same(t, detector, c.StatusDetector())
+}
+
+func TestContainer_CloseCancelsContext(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+
+	c := NewContainerWithContext(parent, config.DefaultConfig())
+	require.NoError(t, c.Context().Err())
+
+	c.Close()
+	assert.ErrorIs(t, c.Context().Err(), context.Canceled)
+	assert.NoError(t, parent.Err(), "closing the container leaves the parent alone")
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// processStart approximates process start for cold-start measurements
+var processStart = time.Now()
+
+// Phase is the recorded duration of one startup phase
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Profiler records how long each startup phase takes. A nil Profiler is
+// valid and records nothing, so call sites don't need to check whether
+// profiling is enabled.
+type Profiler struct {
+	mu     sync.Mutex
+	start  time.Time
+	phases []Phase
+}
+
+// NewProfiler creates a profiler measuring total time from start
+func NewProfiler(start time.Time) *Profiler {
+	return &Profiler{start: start}
+}
+
+// Track starts timing a phase and returns a function that ends it
+func (p *Profiler) Track(name string) func() {
+	if p == nil {
+		return func() {}
+	}
+
+	begin := time.Now()
+	return func() {
+		p.Record(name, time.Since(begin))
+	}
+}
+
+// Record adds a completed phase
+func (p *Profiler) Record(name string, duration time.Duration) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.phases = append(p.phases, Phase{Name: name, Duration: duration})
+}
+
+// Phases returns the recorded phases in completion order
+func (p *Profiler) Phases() []Phase {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	phases := make([]Phase, len(p.phases))
+	copy(phases, p.phases)
+	return phases
+}
+
+// Report writes a timing table of all phases and the total elapsed time
+func (p *Profiler) Report(w io.Writer) {
+	if p == nil {
+		return
+	}
+
+	phases := p.Phases()
+	width := len("total")
+	for _, phase := range phases {
+		width = max(width, len(phase.Name))
+	}
+
+	fmt.Fprintln(w, "Startup profile:")
+	for _, phase := range phases {
+		fmt.Fprintf(w, "  %-*s %10s\n", width, phase.Name, formatPhaseDuration(phase.Duration))
+	}
+	fmt.Fprintf(w, "  %-*s %10s\n", width, "total", formatPhaseDuration(time.Since(p.start)))
+}
+
+func formatPhaseDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+}
+
+// Global profiler, nil unless --profile is used
+var (
+	globalProfiler *Profiler
+	profilerMutex  sync.RWMutex
+)
+
+// EnableProfiling installs a global profiler measuring from process start
+func EnableProfiling() *Profiler {
+	profilerMutex.Lock()
+	defer profilerMutex.Unlock()
+	globalProfiler = NewProfiler(processStart)
+	return globalProfiler
+}
+
+// SetGlobalProfiler replaces the global profiler (nil disables profiling)
+func SetGlobalProfiler(p *Profiler) {
+	profilerMutex.Lock()
+	defer profilerMutex.Unlock()
+	globalProfiler = p
+}
+
+// GetGlobalProfiler returns the global profiler, or nil when profiling is disabled
+func GetGlobalProfiler() *Profiler {
+	profilerMutex.RLock()
+	defer profilerMutex.RUnlock()
+	return globalProfiler
+}
+
+// Track times a phase on the global profiler
+func Track(name string) func() {
+	return GetGlobalProfiler().Track(name)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler_Track(t *testing.T) {
+	p := NewProfiler(time.Now())
+
+	end := p.Track("load config")
+	time.Sleep(2 * time.Millisecond)
+	end()
+	p.Record("load sessions", 5*time.Millisecond)
+
+	phases := p.Phases()
+	require.Len(t, phases, 2)
+	assert.Equal(t, "load config", phases[0].Name)
+	assert.GreaterOrEqual(t, phases[0].Duration, 2*time.Millisecond)
+	assert.Equal(t, Phase{Name: "load sessions", Duration: 5 * time.Millisecond}, phases[1])
+}
+
+func TestProfiler_Report(t *testing.T) {
+	p := NewProfiler(time.Now())
+	p.Record("load config", 1500*time.Microsecond)
+	p.Record("detect repository", 250*time.Microsecond)
+
+	var buf bytes.Buffer
+	p.Report(&buf)
+
+	output := buf.String()
+	assert.Contains(t, output, "Startup profile:")
+	assert.Contains(t, output, "load config")
+	assert.Contains(t, output, "1.50ms")
+	assert.Contains(t, output, "detect repository")
+	assert.Contains(t, output, "0.25ms")
+	assert.Contains(t, output, "total")
+}
+
+func TestProfiler_NilIsNoOp(t *testing.T) {
+	var p *Profiler
+
+	assert.NotPanics(t, func() {
+		p.Track("phase")()
+		p.Record("phase", time.Second)
+		p.Report(&bytes.Buffer{})
+	})
+	assert.Nil(t, p.Phases())
+
+	var buf bytes.Buffer
+	p.Report(&buf)
+	assert.Empty(t, buf.String())
+}
+
+func TestGlobalProfiler(t *testing.T) {
+	original := GetGlobalProfiler()
+	defer SetGlobalProfiler(original)
+
+	SetGlobalProfiler(nil)
+	Track("ignored")()
+	assert.Nil(t, GetGlobalProfiler())
+
+	p := EnableProfiling()
+	require.NotNil(t, p)
+	Track("recorded")()
+
+	phases := GetGlobalProfiler().Phases()
+	require.Len(t, phases, 1)
+	assert.Equal(t, "recorded", phases[0].Name)
+}
@@ -0,0 +1,148 @@
+// Package behind tracks how far each session branch has fallen behind the
+// base branch, so the TUI can suggest 'sbs sync' before a late rebase gets
+// painful. Counts are cached with branchcache, so they are recomputed only
+// when the branch or base moved.
+package behind
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sbs/pkg/branchcache"
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+	"sbs/pkg/paths"
+)
+
+const (
+	// RefreshInterval is how long a count is shown before the branch tips
+	// are checked again
+	RefreshInterval = 5 * time.Minute
+	// Retention drops counts of sessions that have not been refreshed for this long
+	Retention = 24 * time.Hour
+)
+
+// Defaults for branch_behind_commits and branch_behind_days
+const (
+	DefaultCommits = 50
+	DefaultDays    = 14
+)
+
+const day = 24 * time.Hour
+
+// Stat is how far a session branch is behind its base
+type Stat struct {
+	branchcache.Tips
+	Commits int       `json:"commits"`          // Commits on the base the branch lacks
+	Oldest  time.Time `json:"oldest,omitempty"` // Commit time of the oldest of them
+}
+
+// Describe renders the stat as "63 commits behind main, the oldest from 12 days ago"
+func (s Stat) Describe(now time.Time) string {
+	text := fmt.Sprintf("%d commit%s behind %s", s.Commits, plural(s.Commits), s.Base)
+	if days := int(now.Sub(s.Oldest) / day); !s.Oldest.IsZero() && days > 0 {
+		text += fmt.Sprintf(", the oldest from %d day%s ago", days, plural(days))
+	}
+	return text
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// Threshold is when a branch counts as significantly behind. A zero field
+// disables that criterion.
+type Threshold struct {
+	Commits int
+	Age     time.Duration
+}
+
+// FromConfig returns the threshold set by branch_behind_commits and
+// branch_behind_days
+func FromConfig(cfg *config.Config) Threshold {
+	threshold := Threshold{Commits: DefaultCommits, Age: DefaultDays * day}
+	if cfg == nil {
+		return threshold
+	}
+	switch {
+	case cfg.BranchBehindCommits < 0:
+		threshold.Commits = 0
+	case cfg.BranchBehindCommits > 0:
+		threshold.Commits = cfg.BranchBehindCommits
+	}
+	switch {
+	case cfg.BranchBehindDays < 0:
+		threshold.Age = 0
+	case cfg.BranchBehindDays > 0:
+		threshold.Age = time.Duration(cfg.BranchBehindDays) * day
+	}
+	return threshold
+}
+
+// Enabled reports whether any branch can be flagged
+func (t Threshold) Enabled() bool {
+	return t.Commits > 0 || t.Age > 0
+}
+
+// Exceeded reports whether stat is past the threshold
+func (t Threshold) Exceeded(stat Stat, now time.Time) bool {
+	if stat.Commits == 0 {
+		return false
+	}
+	if t.Commits > 0 && stat.Commits >= t.Commits {
+		return true
+	}
+	return t.Age > 0 && !stat.Oldest.IsZero() && now.Sub(stat.Oldest) >= t.Age
+}
+
+// Repository is the git functionality counts are computed with
+type Repository interface {
+	branchcache.Repository
+	CommitsBehind(branch, base string) (int, time.Time, error)
+}
+
+// Cache persists the counts in a single JSON file. It is safe for concurrent use.
+type Cache = branchcache.Cache[Stat, *Stat, Repository]
+
+// NewCache creates a cache backed by the file at path, opening repositories
+// with the git manager
+func NewCache(path string) *Cache {
+	return branchcache.New[Stat, *Stat](path, branchcache.Options[Stat, Repository]{
+		Name:            "branch-behind",
+		RefreshInterval: RefreshInterval,
+		Retention:       Retention,
+		Open:            openRepository,
+		Compute:         computeStat,
+	})
+}
+
+func openRepository(repoRoot string) (Repository, error) {
+	manager, err := git.NewManager(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+// errOnBase leaves sessions working on the base branch itself without a count
+var errOnBase = errors.New("branch is the base branch")
+
+func computeStat(repository Repository, branch, base string) (Stat, error) {
+	if branch == base {
+		return Stat{}, errOnBase
+	}
+	commits, oldest, err := repository.CommitsBehind(branch, base)
+	if err != nil {
+		return Stat{}, err
+	}
+	return Stat{Commits: commits, Oldest: oldest}, nil
+}
+
+// DefaultPath returns behind-cache.json in the cache directory
+func DefaultPath() (string, error) {
+	return paths.CachePath("behind-cache.json")
+}
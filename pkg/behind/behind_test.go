@@ -0,0 +1,114 @@
+package behind
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/branchcache"
+	"sbs/pkg/config"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// fakeRepository serves branch tips and behind counts from maps and counts comparisons
+type fakeRepository struct {
+	tips        map[string]string
+	behind      map[string]int
+	comparisons int
+}
+
+func (r *fakeRepository) DefaultBaseBranch() (string, error) {
+	return "main", nil
+}
+
+func (r *fakeRepository) BranchTips(branch, base string) (string, string, error) {
+	tip, ok := r.tips[branch]
+	if !ok {
+		return "", "", errors.New("unknown branch")
+	}
+	return tip, r.tips[base], nil
+}
+
+func (r *fakeRepository) CommitsBehind(branch, base string) (int, time.Time, error) {
+	r.comparisons++
+	return r.behind[branch], now.Add(-3 * day), nil
+}
+
+func newTestCache(t *testing.T, repository *fakeRepository) *Cache {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "behind-cache.json")
+	return NewCache(path).WithOpener(func(repoRoot string) (Repository, error) {
+		if repoRoot != "/repo" {
+			return nil, errors.New("not a repository")
+		}
+		return repository, nil
+	})
+}
+
+func TestStat_Describe(t *testing.T) {
+	assert.Equal(t, "63 commits behind main, the oldest from 12 days ago", Stat{Tips: branchcache.Tips{Base: "main"}, Commits: 63, Oldest: now.Add(-12*day - time.Hour)}.Describe(now))
+	assert.Equal(t, "1 commit behind master", Stat{Tips: branchcache.Tips{Base: "master"}, Commits: 1, Oldest: now.Add(-time.Hour)}.Describe(now))
+}
+
+func TestThreshold(t *testing.T) {
+	threshold := FromConfig(&config.Config{})
+	assert.Equal(t, Threshold{Commits: DefaultCommits, Age: DefaultDays * day}, threshold)
+
+	assert.True(t, threshold.Exceeded(Stat{Commits: 50}, now))
+	assert.True(t, threshold.Exceeded(Stat{Commits: 1, Oldest: now.Add(-15 * day)}, now))
+	assert.False(t, threshold.Exceeded(Stat{Commits: 49, Oldest: now.Add(-13 * day)}, now))
+	assert.False(t, threshold.Exceeded(Stat{}, now))
+
+	commitsOnly := FromConfig(&config.Config{BranchBehindCommits: 10, BranchBehindDays: -1})
+	assert.True(t, commitsOnly.Exceeded(Stat{Commits: 10}, now))
+	assert.False(t, commitsOnly.Exceeded(Stat{Commits: 9, Oldest: now.Add(-90 * day)}, now))
+
+	assert.False(t, FromConfig(&config.Config{BranchBehindCommits: -1, BranchBehindDays: -1}).Enabled())
+}
+
+func TestCache_Refresh(t *testing.T) {
+	repository := &fakeRepository{
+		tips:   map[string]string{"main": "b1", "issue-1": "c1"},
+		behind: map[string]int{"issue-1": 7},
+	}
+	cache := newTestCache(t, repository)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", Branch: "issue-1", RepositoryRoot: "/repo"},
+		{NamespacedID: "github:2", Branch: "deleted-branch", RepositoryRoot: "/repo"},
+		{NamespacedID: "github:3", Branch: "issue-3", RepositoryRoot: "/gone"},
+		{NamespacedID: "github:4", Branch: "main", RepositoryRoot: "/repo"},
+	}
+
+	stats, err := cache.Refresh(sessions, now)
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "sessions without a readable branch, or on the base itself, get no count")
+	assert.Equal(t, 7, stats["github:1"].Commits)
+	assert.Equal(t, 1, repository.comparisons)
+
+	// Fresh counts aren't rechecked, and unmoved tips aren't recompared
+	repository.behind["issue-1"] = 9
+	stats, err = cache.Refresh(sessions, now.Add(RefreshInterval/2))
+	require.NoError(t, err)
+	assert.Equal(t, 7, stats["github:1"].Commits)
+	stats, err = cache.Refresh(sessions, now.Add(2*RefreshInterval))
+	require.NoError(t, err)
+	assert.Equal(t, 7, stats["github:1"].Commits)
+	assert.Equal(t, 1, repository.comparisons)
+
+	// A moved base is recompared
+	repository.tips["main"] = "b2"
+	stats, err = cache.Refresh(sessions, now.Add(4*RefreshInterval))
+	require.NoError(t, err)
+	assert.Equal(t, 9, stats["github:1"].Commits)
+	assert.Equal(t, 2, repository.comparisons)
+
+	data, err := os.ReadFile(cache.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"commits":9`)
+}
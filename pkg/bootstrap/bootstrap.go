@@ -0,0 +1,137 @@
+// Package bootstrap reads the session settings an issue author can put in a
+// work item's body, so everyone who starts that work item gets the same
+// environment.
+//
+// The settings live in the first fenced block whose info string is sbs:
+//
+//	```sbs
+//	# comments and blank lines are ignored
+//	base: develop
+//	setup: make deps
+//	command: claude --model opus
+//	```
+//
+//	base     the branch, tag or commit the new branch is created from
+//	setup    a command typed into the session before the session command;
+//	         repeat the key to run several in order
+//	command  the session command, used unless --command or --no-command is given
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidBlock is wrapped by every error about a malformed sbs block
+var ErrInvalidBlock = errors.New("invalid sbs block")
+
+// Block holds the settings of an sbs block
+type Block struct {
+	Base    string
+	Setup   []string
+	Command string
+}
+
+// Empty reports whether the block sets nothing
+func (b Block) Empty() bool {
+	return b.Base == "" && len(b.Setup) == 0 && b.Command == ""
+}
+
+// Parse returns the settings of the first sbs block in body and whether there
+// was one. A block with a line it doesn't understand is an error rather than
+// partly applied.
+func Parse(body string) (Block, bool, error) {
+	lines, found, err := findBlock(body)
+	if err != nil || !found {
+		return Block{}, found, err
+	}
+
+	var block Block
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Block{}, true, fmt.Errorf("%w: line %d: expected key: value, got %q", ErrInvalidBlock, i+1, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return Block{}, true, fmt.Errorf("%w: line %d: %s has no value", ErrInvalidBlock, i+1, key)
+		}
+		switch key {
+		case "base":
+			if block.Base != "" {
+				return Block{}, true, fmt.Errorf("%w: line %d: base is set twice", ErrInvalidBlock, i+1)
+			}
+			block.Base = value
+		case "setup":
+			block.Setup = append(block.Setup, value)
+		case "command":
+			if block.Command != "" {
+				return Block{}, true, fmt.Errorf("%w: line %d: command is set twice", ErrInvalidBlock, i+1)
+			}
+			block.Command = value
+		default:
+			return Block{}, true, fmt.Errorf("%w: line %d: unknown key %q (supported: base, setup, command)", ErrInvalidBlock, i+1, key)
+		}
+	}
+	return block, true, nil
+}
+
+// findBlock returns the lines inside the first fence opened with the info
+// string sbs, by ``` or ~~~ of any length, as CommonMark allows
+func findBlock(body string) ([]string, bool, error) {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		fence, info, ok := openingFence(line)
+		if !ok || !isSBSInfo(info) {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if closesFence(lines[j], fence) {
+				return lines[i+1 : j], true, nil
+			}
+		}
+		return nil, true, fmt.Errorf("%w: the fence is never closed", ErrInvalidBlock)
+	}
+	return nil, false, nil
+}
+
+// openingFence splits a fence opening line into its fence and info string
+func openingFence(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return "", "", false
+	}
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return "", "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == char {
+		n++
+	}
+	if n < 3 {
+		return "", "", false
+	}
+	return trimmed[:n], strings.TrimSpace(trimmed[n:]), true
+}
+
+// isSBSInfo reports whether a fence's info string marks an sbs block
+func isSBSInfo(info string) bool {
+	fields := strings.Fields(info)
+	return len(fields) > 0 && strings.TrimSuffix(strings.ToLower(fields[0]), ":") == "sbs"
+}
+
+// closesFence reports whether line closes a block opened with fence
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, fence) {
+		return false
+	}
+	return strings.Trim(trimmed, fence[:1]) == ""
+}
@@ -0,0 +1,62 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	body := "Fix the flaky test.\r\n\r\n```go\nbase: ignored\n```\r\n\r\n```sbs\r\n# pre-configured by the maintainers\r\nbase: origin/develop\r\nsetup: make deps\r\nsetup: make db\r\n\r\ncommand: claude --model opus\r\n```\r\n"
+	block, found, err := Parse(body)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Block{
+		Base:    "origin/develop",
+		Setup:   []string{"make deps", "make db"},
+		Command: "claude --model opus",
+	}, block)
+}
+
+func TestParse_Fences(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		found bool
+		want  Block
+	}{
+		{"no block", "Just a description.", false, Block{}},
+		{"other language", "```yaml\nbase: main\n```", false, Block{}},
+		{"tildes", "~~~sbs\nbase: main\n~~~", true, Block{Base: "main"}},
+		{"colon in info string", "```sbs:\nbase: main\n```", true, Block{Base: "main"}},
+		{"longer fence", "````sbs\nbase: main\n````", true, Block{Base: "main"}},
+		{"indented code is not a fence", "    ```sbs\n    base: main\n    ```", false, Block{}},
+		{"empty block", "```sbs\n```", true, Block{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, found, err := Parse(tt.body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, block)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":   "```sbs\nprofile: python\n```",
+		"not key:value": "```sbs\nmake deps\n```",
+		"empty value":   "```sbs\nbase:\n```",
+		"repeated key":  "```sbs\nbase: a\nbase: b\n```",
+		"unclosed":      "```sbs\nbase: main\n",
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, found, err := Parse(body)
+			assert.True(t, found)
+			assert.ErrorIs(t, err, ErrInvalidBlock)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Package branchcache caches stats computed by comparing each session branch
+// with its base branch, such as diffstats and behind counts. Stats are kept
+// in a JSON file keyed by namespaced ID and recomputed only when the branch
+// or base moved, so rendering never waits on git.
+package branchcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+// Tips records the commits a stat was computed from and when they were last
+// checked. Stat types embed it, which keeps its fields at the top level of
+// the cache file.
+type Tips struct {
+	Base      string    `json:"base"`
+	BranchTip string    `json:"branch_tip"`
+	BaseTip   string    `json:"base_tip"`
+	Checked   time.Time `json:"checked"`
+}
+
+func (t *Tips) tips() *Tips {
+	return t
+}
+
+// Stat is satisfied by pointers to stat types embedding Tips
+type Stat[S any] interface {
+	*S
+	tips() *Tips
+}
+
+// Repository is the git functionality every cache needs to find the tips
+type Repository interface {
+	DefaultBaseBranch() (string, error)
+	BranchTips(branch, base string) (string, string, error)
+}
+
+// Options describe what a cache holds and how it is computed
+type Options[S any, R Repository] struct {
+	// Name appears in error messages, e.g. "diffstat"
+	Name string
+	// RefreshInterval is how long a stat is shown before the branch tips are
+	// checked again
+	RefreshInterval time.Duration
+	// Retention drops stats of sessions that have not been refreshed for this long
+	Retention time.Duration
+	// Open opens the repository at a root
+	Open func(repoRoot string) (R, error)
+	// Compute computes the stat of branch against base. An error leaves the
+	// session without a stat.
+	Compute func(repository R, branch, base string) (S, error)
+}
+
+// Cache persists stats in a single JSON file. It is safe for concurrent use.
+type Cache[S any, P Stat[S], R Repository] struct {
+	path    string
+	options Options[S, R]
+	mu      sync.Mutex
+}
+
+// New creates a cache backed by the file at path
+func New[S any, P Stat[S], R Repository](path string, options Options[S, R]) *Cache[S, P, R] {
+	return &Cache[S, P, R]{path: path, options: options}
+}
+
+// WithOpener returns a copy of the cache that opens repositories with open
+func (c *Cache[S, P, R]) WithOpener(open func(repoRoot string) (R, error)) *Cache[S, P, R] {
+	options := c.options
+	options.Open = open
+	return New[S, P](c.path, options)
+}
+
+// Path returns the cache file
+func (c *Cache[S, P, R]) Path() string {
+	return c.path
+}
+
+// Load returns the cached stats by namespaced ID; a missing file yields none
+func (c *Cache[S, P, R]) Load() (map[string]S, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load()
+}
+
+func (c *Cache[S, P, R]) load() (map[string]S, error) {
+	stats := make(map[string]S)
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s cache: %w", c.options.Name, err)
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse %s cache %s: %w", c.options.Name, c.path, err)
+	}
+	return stats, nil
+}
+
+// Refresh returns the stats of sessions by namespaced ID. Stats checked less
+// than RefreshInterval ago are returned as cached; older ones are recomputed
+// only if the branch or base moved since. Sessions whose repository or
+// branch can't be read get no stat. A corrupt cache file is replaced rather
+// than reported, and a failed write still returns the stats.
+func (c *Cache[S, P, R]) Refresh(sessions []config.SessionMetadata, now time.Time) (map[string]S, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, err := c.load()
+	if err != nil {
+		cached = make(map[string]S)
+	}
+
+	repositories := make(map[string]R)
+	opened := make(map[string]bool)
+	bases := make(map[string]string)
+	stats := make(map[string]S, len(sessions))
+	changed := false
+	for _, session := range sessions {
+		if session.NamespacedID == "" || session.Branch == "" || session.RepositoryRoot == "" {
+			continue
+		}
+		stat, ok := cached[session.NamespacedID]
+		if ok && now.Sub(P(&stat).tips().Checked) < c.options.RefreshInterval {
+			stats[session.NamespacedID] = stat
+			continue
+		}
+
+		root := session.RepositoryRoot
+		if _, tried := repositories[root]; !tried {
+			repository, err := c.options.Open(root)
+			repositories[root] = repository
+			if err == nil {
+				opened[root] = true
+				bases[root], _ = repository.DefaultBaseBranch()
+			}
+		}
+		if !opened[root] || bases[root] == "" {
+			continue
+		}
+
+		updated, err := c.refreshStat(repositories[root], session.Branch, bases[root], stat)
+		if err != nil {
+			continue
+		}
+		P(&updated).tips().Checked = now
+		cached[session.NamespacedID] = updated
+		stats[session.NamespacedID] = updated
+		changed = true
+	}
+
+	for id, stat := range cached {
+		if now.Sub(P(&stat).tips().Checked) > c.options.Retention {
+			delete(cached, id)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return stats, nil
+	}
+	return stats, c.save(cached)
+}
+
+// refreshStat returns previous if neither branch nor base moved since it was
+// computed, and a freshly computed stat otherwise
+func (c *Cache[S, P, R]) refreshStat(repository R, branch, base string, previous S) (S, error) {
+	branchTip, baseTip, err := repository.BranchTips(branch, base)
+	if err != nil {
+		return previous, err
+	}
+	tips := P(&previous).tips()
+	if tips.Base == base && tips.BranchTip == branchTip && tips.BaseTip == baseTip {
+		return previous, nil
+	}
+	stat, err := c.options.Compute(repository, branch, base)
+	if err != nil {
+		return stat, err
+	}
+	*P(&stat).tips() = Tips{Base: base, BranchTip: branchTip, BaseTip: baseTip}
+	return stat, nil
+}
+
+func (c *Cache[S, P, R]) save(stats map[string]S) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s cache: %w", c.options.Name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create %s cache directory: %w", c.options.Name, err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write %s cache: %w", c.options.Name, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s cache: %w", c.options.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package branchcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+type testStat struct {
+	Tips
+	Value int `json:"value"`
+}
+
+// fakeRepository serves branch tips and values from maps and counts computations
+type fakeRepository struct {
+	tips         map[string]string
+	values       map[string]int
+	computations int
+}
+
+func (r *fakeRepository) DefaultBaseBranch() (string, error) {
+	return "main", nil
+}
+
+func (r *fakeRepository) BranchTips(branch, base string) (string, string, error) {
+	tip, ok := r.tips[branch]
+	if !ok {
+		return "", "", errors.New("unknown branch")
+	}
+	return tip, r.tips[base], nil
+}
+
+func newTestCache(t *testing.T, repository *fakeRepository) *Cache[testStat, *testStat, *fakeRepository] {
+	t.Helper()
+	return New[testStat, *testStat](filepath.Join(t.TempDir(), "test-cache.json"), Options[testStat, *fakeRepository]{
+		Name:            "test",
+		RefreshInterval: time.Minute,
+		Retention:       24 * time.Hour,
+		Open: func(repoRoot string) (*fakeRepository, error) {
+			if repoRoot != "/repo" {
+				return nil, errors.New("not a repository")
+			}
+			return repository, nil
+		},
+		Compute: func(repository *fakeRepository, branch, base string) (testStat, error) {
+			repository.computations++
+			value, ok := repository.values[branch]
+			if !ok {
+				return testStat{}, errors.New("no value")
+			}
+			return testStat{Value: value}, nil
+		},
+	})
+}
+
+func TestCache_Refresh(t *testing.T) {
+	repository := &fakeRepository{
+		tips:   map[string]string{"main": "b1", "issue-1": "c1", "issue-4": "c4"},
+		values: map[string]int{"issue-1": 12},
+	}
+	cache := newTestCache(t, repository)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", Branch: "issue-1", RepositoryRoot: "/repo"},
+		{NamespacedID: "github:2", Branch: "deleted-branch", RepositoryRoot: "/repo"},
+		{NamespacedID: "github:3", Branch: "issue-3", RepositoryRoot: "/gone"},
+		{NamespacedID: "github:4", Branch: "issue-4", RepositoryRoot: "/repo"},
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	stats, err := cache.Refresh(sessions, now)
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "sessions without a readable branch or a computable stat get none")
+	assert.Equal(t, 12, stats["github:1"].Value)
+	assert.Equal(t, Tips{Base: "main", BranchTip: "c1", BaseTip: "b1", Checked: now}, stats["github:1"].Tips)
+	assert.Equal(t, 2, repository.computations)
+
+	info, err := os.Stat(cache.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	t.Run("fresh_stats_are_not_rechecked", func(t *testing.T) {
+		repository.tips["issue-1"] = "c2"
+		stats, err := cache.Refresh(sessions[:1], now.Add(30*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 12, stats["github:1"].Value)
+		assert.Equal(t, 2, repository.computations)
+		repository.tips["issue-1"] = "c1"
+	})
+
+	t.Run("unmoved_branches_are_not_recomputed", func(t *testing.T) {
+		repository.values["issue-1"] = 40
+		stats, err := cache.Refresh(sessions[:1], now.Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 12, stats["github:1"].Value)
+		assert.Equal(t, 2, repository.computations)
+		assert.Equal(t, now.Add(2*time.Minute), stats["github:1"].Checked)
+	})
+
+	t.Run("moved_bases_are_recomputed", func(t *testing.T) {
+		repository.tips["main"] = "b2"
+		stats, err := cache.Refresh(sessions[:1], now.Add(4*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 40, stats["github:1"].Value)
+		assert.Equal(t, 3, repository.computations)
+
+		loaded, err := cache.Load()
+		require.NoError(t, err)
+		assert.Equal(t, stats["github:1"], loaded["github:1"])
+	})
+
+	t.Run("old_stats_are_dropped", func(t *testing.T) {
+		_, err := cache.Refresh(nil, now.Add(25*time.Hour))
+		require.NoError(t, err)
+		loaded, err := cache.Load()
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+	})
+}
+
+func TestCache_FileFormat(t *testing.T) {
+	repository := &fakeRepository{
+		tips:   map[string]string{"main": "b1", "issue-1": "c1"},
+		values: map[string]int{"issue-1": 3},
+	}
+	cache := newTestCache(t, repository)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	_, err := cache.Refresh([]config.SessionMetadata{{NamespacedID: "github:1", Branch: "issue-1", RepositoryRoot: "/repo"}}, now)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(cache.Path())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"github:1": {"base": "main", "branch_tip": "c1", "base_tip": "b1", "checked": "2026-10-18T12:00:00Z", "value": 3}}`, string(data))
+}
+
+func TestCache_CorruptFileIsReplaced(t *testing.T) {
+	repository := &fakeRepository{
+		tips:   map[string]string{"main": "b1", "issue-1": "c1"},
+		values: map[string]int{"issue-1": 1},
+	}
+	cache := newTestCache(t, repository)
+	require.NoError(t, os.WriteFile(cache.Path(), []byte("{not json"), 0644))
+
+	_, err := cache.Load()
+	assert.ErrorContains(t, err, "failed to parse test cache")
+
+	stats, err := cache.Refresh([]config.SessionMetadata{
+		{NamespacedID: "github:1", Branch: "issue-1", RepositoryRoot: "/repo"},
+	}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats["github:1"].Value)
+
+	loaded, err := cache.Load()
+	require.NoError(t, err)
+	assert.Len(t, loaded, 1)
+}
+
+func TestCache_WithOpener(t *testing.T) {
+	repository := &fakeRepository{
+		tips:   map[string]string{"main": "b1", "issue-1": "c1"},
+		values: map[string]int{"issue-1": 5},
+	}
+	cache := newTestCache(t, &fakeRepository{}).WithOpener(func(repoRoot string) (*fakeRepository, error) {
+		return repository, nil
+	})
+
+	stats, err := cache.Refresh([]config.SessionMetadata{
+		{NamespacedID: "github:1", Branch: "issue-1", RepositoryRoot: "/elsewhere"},
+	}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 5, stats["github:1"].Value)
+}
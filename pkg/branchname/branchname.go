@@ -0,0 +1,207 @@
+// Package branchname renders work item branch names from a template and parses
+// work item IDs back out of them.
+//
+// Templates use the placeholders {source}, {id} and {title} (the title slug),
+// e.g. the default "issue-{source}-{id}-{title}". A missing title drops the
+// {title} placeholder along with the separator before it.
+package branchname
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultTemplate is the branch template used when none is configured
+	DefaultTemplate = "issue-{source}-{id}-{title}"
+
+	// LegacyTemplate is the format used before namespaced work items, for
+	// GitHub issue numbers only
+	LegacyTemplate = "issue-{id}-{title}"
+
+	// LegacySource is the work item source of branches in the legacy format
+	LegacySource = "github"
+)
+
+const (
+	placeholderSource = "{source}"
+	placeholderID     = "{id}"
+	placeholderTitle  = "{title}"
+)
+
+var (
+	template      = DefaultTemplate
+	templateMutex sync.RWMutex
+
+	placeholderPattern = regexp.MustCompile(`\{[^}]*\}`)
+	invalidRefChars    = regexp.MustCompile(`[\s~^:?*\[\\]`)
+)
+
+// SetTemplate sets the template used for new branches; "" restores the
+// default. The template must already be validated with Validate.
+func SetTemplate(t string) {
+	templateMutex.Lock()
+	defer templateMutex.Unlock()
+	if t == "" {
+		t = DefaultTemplate
+	}
+	template = t
+}
+
+// Template returns the template used for new branches
+func Template() string {
+	templateMutex.RLock()
+	defer templateMutex.RUnlock()
+	return template
+}
+
+// Validate checks a branch template. It must contain {source} and {id}, may
+// contain {title}, and must start with literal text so unrelated branches are
+// never taken for work item branches.
+func Validate(t string) error {
+	if t == "" {
+		return nil
+	}
+	for _, placeholder := range placeholderPattern.FindAllString(t, -1) {
+		switch placeholder {
+		case placeholderSource, placeholderID, placeholderTitle:
+		default:
+			return fmt.Errorf("branch_template has unknown placeholder %s (expected {source}, {id} or {title})", placeholder)
+		}
+	}
+	if !strings.Contains(t, placeholderSource) || !strings.Contains(t, placeholderID) {
+		return fmt.Errorf("branch_template must contain {source} and {id} (got %q)", t)
+	}
+	if strings.HasPrefix(t, "{") {
+		return fmt.Errorf("branch_template must start with a fixed prefix such as \"issue-\" (got %q)", t)
+	}
+	literal := placeholderPattern.ReplaceAllString(t, "")
+	if invalidRefChars.MatchString(literal) || strings.Contains(literal, "..") || strings.Contains(literal, "@{") {
+		return fmt.Errorf("branch_template contains characters not allowed in git branch names (got %q)", t)
+	}
+	return nil
+}
+
+// Render fills a template with a work item's source, ID and title slug
+func Render(t, source, id, titleSlug string) string {
+	if titleSlug == "" {
+		t = trimTitle(t)
+	}
+	return strings.NewReplacer(
+		placeholderSource, source,
+		placeholderID, id,
+		placeholderTitle, titleSlug,
+	).Replace(t)
+}
+
+// trimTitle removes the {title} placeholder and the separator before it
+func trimTitle(t string) string {
+	before, after, found := strings.Cut(t, placeholderTitle)
+	if !found {
+		return t
+	}
+	return strings.TrimRight(before, "-_./") + after
+}
+
+// matcher parses work item IDs out of branches rendered from one template
+type matcher struct {
+	pattern *regexp.Regexp
+	source  string // fixed source for templates without {source}
+}
+
+var (
+	matchers      = map[string]*matcher{}
+	matchersMutex sync.Mutex
+)
+
+// compile turns a template into a matcher. Sources are lowercase words,
+// legacy IDs are numbers and other IDs run up to the title.
+func compile(t string) *matcher {
+	matchersMutex.Lock()
+	defer matchersMutex.Unlock()
+	if m, exists := matchers[t]; exists {
+		return m
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	rest := t
+	for {
+		loc := placeholderPattern.FindStringIndex(rest)
+		if loc == nil {
+			b.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		literal, placeholder := rest[:loc[0]], rest[loc[0]:loc[1]]
+		rest = rest[loc[1]:]
+
+		switch placeholder {
+		case placeholderSource:
+			b.WriteString(regexp.QuoteMeta(literal) + `(?P<source>[a-z][a-z0-9]*)`)
+		case placeholderID:
+			if t == LegacyTemplate {
+				b.WriteString(regexp.QuoteMeta(literal) + `(?P<id>[0-9]+)`)
+			} else {
+				b.WriteString(regexp.QuoteMeta(literal) + `(?P<id>[^/]+?)`)
+			}
+		case placeholderTitle:
+			// The title (and its separator) is missing for untitled work items
+			trimmed := strings.TrimRight(literal, "-_./")
+			separator := literal[len(trimmed):]
+			b.WriteString(regexp.QuoteMeta(trimmed) + `(?:` + regexp.QuoteMeta(separator) + `.+)?`)
+		}
+	}
+	b.WriteString("$")
+
+	m := &matcher{pattern: regexp.MustCompile(b.String())}
+	if !strings.Contains(t, placeholderSource) {
+		m.source = LegacySource
+	}
+	matchers[t] = m
+	return m
+}
+
+// match returns the namespaced work item ID of a branch rendered from the matcher's template
+func (m *matcher) match(branch string) (string, bool) {
+	groups := m.pattern.FindStringSubmatch(branch)
+	if groups == nil {
+		return "", false
+	}
+	source, id := m.source, ""
+	for i, name := range m.pattern.SubexpNames() {
+		switch name {
+		case "source":
+			source = groups[i]
+		case "id":
+			id = groups[i]
+		}
+	}
+	if source == "" || id == "" {
+		return "", false
+	}
+	return source + ":" + id, true
+}
+
+// Templates returns the templates branches may have been created with: the
+// configured one, the default and the legacy format
+func Templates() []string {
+	templates := []string{Template()}
+	if templates[0] != DefaultTemplate {
+		templates = append(templates, DefaultTemplate)
+	}
+	return append(templates, LegacyTemplate)
+}
+
+// ParseWorkItemID returns the namespaced work item ID ("source:id") of a
+// branch created from any template in use, or false if the branch is not a
+// work item branch
+func ParseWorkItemID(branch string) (string, bool) {
+	for _, t := range Templates() {
+		if workItemID, ok := compile(t).match(branch); ok {
+			return workItemID, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,70 @@
+package branchname
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{"", DefaultTemplate, "feature/{source}-{id}", "sbs/{source}/{id}/{title}"}
+	for _, tmpl := range valid {
+		assert.NoError(t, Validate(tmpl), tmpl)
+	}
+
+	invalid := []string{
+		"{source}-{id}",           // no fixed prefix
+		"issue-{id}-{title}",      // no source
+		"issue-{source}-{title}",  // no id
+		"issue-{source}-{id}-{x}", // unknown placeholder
+		"issue {source}-{id}",     // space
+		"issue..{source}-{id}",    // double dot
+	}
+	for _, tmpl := range invalid {
+		assert.Error(t, Validate(tmpl), tmpl)
+	}
+}
+
+func TestRender(t *testing.T) {
+	assert.Equal(t, "issue-github-123-fix-bug", Render(DefaultTemplate, "github", "123", "fix-bug"))
+	assert.Equal(t, "issue-github-123", Render(DefaultTemplate, "github", "123", ""))
+	assert.Equal(t, "feature/jira/PROJ-7", Render("feature/{source}/{id}/{title}", "jira", "PROJ-7", ""))
+}
+
+func TestParseWorkItemID(t *testing.T) {
+	t.Cleanup(func() { SetTemplate("") })
+
+	tests := []struct {
+		branch   string
+		template string
+		expected string
+	}{
+		{"issue-github-123-fix-bug", "", "github:123"},
+		{"issue-test-quick", "", "test:quick"},
+		{"issue-42-old-style", "", "github:42"},
+		{"issue-42", "", "github:42"},
+		{"feature/github-99-add-search", "feature/{source}-{id}-{title}", "github:99"},
+		{"feature/jira/PROJ-7/login", "feature/{source}/{id}/{title}", "jira:PROJ-7"},
+		{"issue-github-5-x", "feature/{source}-{id}-{title}", "github:5"}, // default still recognized
+		{"main", "", ""},
+		{"issue-", "", ""},
+		{"feature/search", "feature/{source}-{id}-{title}", ""},
+	}
+	for _, tt := range tests {
+		SetTemplate(tt.template)
+		workItemID, ok := ParseWorkItemID(tt.branch)
+		assert.Equal(t, tt.expected, workItemID, tt.branch)
+		assert.Equal(t, tt.expected != "", ok, tt.branch)
+	}
+}
+
+func TestRenderParseRoundTrip(t *testing.T) {
+	t.Cleanup(func() { SetTemplate("") })
+	SetTemplate("sbs/{source}/{id}-{title}")
+
+	branch := Render(Template(), "github", "77", "refactor-parser")
+	assert.Equal(t, "sbs/github/77-refactor-parser", branch)
+	workItemID, ok := ParseWorkItemID(branch)
+	assert.True(t, ok)
+	assert.Equal(t, "github:77", workItemID)
+}
@@ -0,0 +1,103 @@
+// Package buildcache wires shared build cache directories (GOCACHE, npm,
+// ccache, ...) into sessions so builds in fresh worktrees don't start cold.
+package buildcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+// MountsEnvVar lists host:sandbox bind mounts for caches with a mount target,
+// comma separated, for .sbs/start scripts to pass on to the sandbox
+const MountsEnvVar = "SBS_SANDBOX_MOUNTS"
+
+// DefaultRoot returns the directory holding caches without an explicit path
+func DefaultRoot() (string, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return dir, nil
+}
+
+// Resolve fills in preset variables and default host paths, returning the
+// caches as they are wired into a session
+func Resolve(entries []config.BuildCacheEntry, root string) ([]config.BuildCacheEntry, error) {
+	resolved := make([]config.BuildCacheEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Env == "" {
+			env, ok := config.BuildCachePresets[entry.Name]
+			if !ok {
+				return nil, fmt.Errorf("build cache %s has no env variable and is not a preset", entry.Name)
+			}
+			entry.Env = env
+		}
+
+		path, err := expandHome(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			path = filepath.Join(root, entry.Name)
+		}
+		entry.Path = path
+
+		resolved = append(resolved, entry)
+	}
+	return resolved, nil
+}
+
+// Prepare creates the host cache directories
+func Prepare(caches []config.BuildCacheEntry) error {
+	for _, cache := range caches {
+		if err := os.MkdirAll(cache.Path, 0755); err != nil {
+			return fmt.Errorf("failed to create build cache %s: %w", cache.Name, err)
+		}
+	}
+	return nil
+}
+
+// Environment returns the variables exported to the session. Mounted caches
+// point at the path inside the sandbox and are listed in SBS_SANDBOX_MOUNTS.
+func Environment(caches []config.BuildCacheEntry) map[string]string {
+	env := make(map[string]string)
+	var mounts []string
+	for _, cache := range caches {
+		if cache.Mount != "" {
+			env[cache.Env] = cache.Mount
+			mounts = append(mounts, cache.Path+":"+cache.Mount)
+		} else {
+			env[cache.Env] = cache.Path
+		}
+	}
+	if len(mounts) > 0 {
+		sort.Strings(mounts)
+		env[MountsEnvVar] = strings.Join(mounts, ",")
+	}
+	return env
+}
+
+// Describe renders one wired cache for display
+func Describe(cache config.BuildCacheEntry) string {
+	if cache.Mount != "" {
+		return fmt.Sprintf("%s: %s=%s (mounted from %s)", cache.Name, cache.Env, cache.Mount, cache.Path)
+	}
+	return fmt.Sprintf("%s: %s=%s", cache.Name, cache.Env, cache.Path)
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to expand %s: %w", path, err)
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~")), nil
+}
@@ -0,0 +1,73 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestResolve(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	caches, err := Resolve([]config.BuildCacheEntry{
+		{Name: "go"},
+		{Name: "npm", Path: "~/caches/npm"},
+		{Name: "gradle", Env: "GRADLE_USER_HOME", Mount: "/cache/gradle"},
+	}, "/var/cache/sbs")
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.BuildCacheEntry{
+		{Name: "go", Env: "GOCACHE", Path: "/var/cache/sbs/go"},
+		{Name: "npm", Env: "npm_config_cache", Path: filepath.Join(home, "caches/npm")},
+		{Name: "gradle", Env: "GRADLE_USER_HOME", Path: "/var/cache/sbs/gradle", Mount: "/cache/gradle"},
+	}, caches)
+}
+
+func TestResolve_UnknownWithoutEnv(t *testing.T) {
+	_, err := Resolve([]config.BuildCacheEntry{{Name: "gradle"}}, "/tmp")
+	assert.ErrorContains(t, err, "not a preset")
+}
+
+func TestDefaultRoot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	root, err := DefaultRoot()
+	require.NoError(t, err)
+	assert.Equal(t, "/xdg/cache/sbs", root)
+}
+
+func TestPrepare(t *testing.T) {
+	dir := t.TempDir()
+	caches := []config.BuildCacheEntry{{Name: "go", Env: "GOCACHE", Path: filepath.Join(dir, "nested", "go")}}
+
+	require.NoError(t, Prepare(caches))
+	assert.DirExists(t, filepath.Join(dir, "nested", "go"))
+}
+
+func TestEnvironment(t *testing.T) {
+	env := Environment([]config.BuildCacheEntry{
+		{Name: "go", Env: "GOCACHE", Path: "/host/go"},
+		{Name: "ccache", Env: "CCACHE_DIR", Path: "/host/ccache", Mount: "/cache/ccache"},
+		{Name: "npm", Env: "npm_config_cache", Path: "/host/npm", Mount: "/cache/npm"},
+	})
+
+	assert.Equal(t, map[string]string{
+		"GOCACHE":          "/host/go",
+		"CCACHE_DIR":       "/cache/ccache",
+		"npm_config_cache": "/cache/npm",
+		MountsEnvVar:       "/host/ccache:/cache/ccache,/host/npm:/cache/npm",
+	}, env)
+
+	assert.Empty(t, Environment(nil))
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "go: GOCACHE=/host/go", Describe(config.BuildCacheEntry{Name: "go", Env: "GOCACHE", Path: "/host/go"}))
+	assert.Equal(t, "npm: npm_config_cache=/cache/npm (mounted from /host/npm)",
+		Describe(config.BuildCacheEntry{Name: "npm", Env: "npm_config_cache", Path: "/host/npm", Mount: "/cache/npm"}))
+}
@@ -0,0 +1,85 @@
+package cleanup
+
+import (
+	"context"
+
+	"sbs/pkg/config"
+)
+
+// SessionResult is the outcome of cleaning one session of a batch
+type SessionResult struct {
+	Session config.SessionMetadata
+	Actions []Action // What happened to each of the session's resources
+	Cleaned bool     // At least one resource was removed
+}
+
+// Progress is sent each time a session of a batch finishes
+type Progress struct {
+	Done   int // Sessions finished so far, including this one
+	Total  int
+	Result SessionResult
+}
+
+// BatchFunc cleans sessions under ctx, calling finished with each session's
+// result as soon as it is done, and returns the aggregate results
+type BatchFunc func(ctx context.Context, finished func(SessionResult)) (CleanupResults, error)
+
+// Batch is a cleanup running in the background. Progress streams the
+// sessions as they finish, Cancel stops it before the next session, and Wait
+// returns the aggregate results.
+type Batch struct {
+	progress chan Progress
+	cancel   context.CancelFunc
+	done     chan struct{}
+	results  CleanupResults
+	err      error
+}
+
+// NewBatch starts run in the background as a batch of total sessions
+func NewBatch(ctx context.Context, total int, run BatchFunc) *Batch {
+	ctx, cancel := context.WithCancel(ctx)
+	batch := &Batch{
+		progress: make(chan Progress, total),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(batch.done)
+		defer close(batch.progress)
+		defer cancel()
+		finished := 0
+		batch.results, batch.err = run(ctx, func(result SessionResult) {
+			finished++
+			batch.progress <- Progress{Done: finished, Total: total, Result: result}
+		})
+	}()
+	return batch
+}
+
+// StartBatch cleans sessions in the background the way CleanupSessions does
+func (c *CleanupManager) StartBatch(ctx context.Context, sessions []config.SessionMetadata, options CleanupOptions) *Batch {
+	sessions = filterSessions(sessions, options)
+	return NewBatch(ctx, len(sessions), func(ctx context.Context, finished func(SessionResult)) (CleanupResults, error) {
+		return c.runBatch(ctx, sessions, options, finished)
+	})
+}
+
+// Progress returns the channel each finished session is sent on. It is
+// closed when the batch ends, and buffered for every session, so the batch
+// never waits for a slow reader or one that doesn't read at all.
+func (b *Batch) Progress() <-chan Progress {
+	return b.progress
+}
+
+// Cancel stops the batch before its next session; Wait then returns the
+// results so far with context.Canceled
+func (b *Batch) Cancel() {
+	b.cancel()
+}
+
+// Wait blocks until the batch ends and returns its aggregate results. The
+// error is only set when the batch was cancelled.
+func (b *Batch) Wait() (CleanupResults, error) {
+	<-b.done
+	return b.results, b.err
+}
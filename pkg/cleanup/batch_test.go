@@ -0,0 +1,88 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/config"
+)
+
+func TestStartBatch_StreamsSessionResults(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:1", TmuxSession: "sbs-1", RepositoryRoot: "/repo"},
+		{NamespacedID: "test:2", TmuxSession: "sbs-2", RepositoryRoot: "/other"},
+		{NamespacedID: "test:3", TmuxSession: "sbs-3", RepositoryRoot: "/repo"},
+	}
+	manager := NewCleanupManager(&MockTmuxManager{sessions: []string{"sbs-1"}}, nil, nil, nil)
+
+	batch := manager.StartBatch(context.Background(), sessions, NewCleanupOptions(
+		WithResources(ResourceTmux),
+		WithRepositoryFilter("/repo"),
+	))
+	var progress []Progress
+	for p := range batch.Progress() {
+		progress = append(progress, p)
+	}
+	results, err := batch.Wait()
+	require.NoError(t, err)
+
+	require.Len(t, progress, 2, "the repository filter applies to the batch")
+	assert.Equal(t, 1, progress[0].Done)
+	assert.Equal(t, 2, progress[0].Total)
+	assert.Equal(t, "test:1", progress[0].Result.Session.NamespacedID)
+	assert.True(t, progress[0].Result.Cleaned)
+	assert.Equal(t, []Action{{SessionID: "test:1", Resource: ResourceTmux, Target: "sbs-1", Outcome: OutcomeRemoved}}, progress[0].Result.Actions)
+	assert.Equal(t, "test:3", progress[1].Result.Session.NamespacedID)
+	assert.False(t, progress[1].Result.Cleaned, "a session whose tmux session is already gone has nothing removed")
+	assert.Equal(t, OutcomeMissing, progress[1].Result.Actions[0].Outcome)
+
+	assert.Equal(t, 1, results.CleanedSessions)
+	assert.Len(t, results.Actions, 2)
+}
+
+func TestStartBatch_DryRunStreamsPlans(t *testing.T) {
+	sessions := []config.SessionMetadata{{NamespacedID: "test:1", TmuxSession: "sbs-1", WorktreePath: "/work/1"}}
+	manager := NewCleanupManager(&MockTmuxManager{}, nil, nil, nil)
+
+	batch := manager.StartBatch(context.Background(), sessions, NewCleanupOptions(WithResources(ResourceTmux), WithDryRun(true)))
+	results, err := batch.Wait()
+	require.NoError(t, err)
+	progress, ok := <-batch.Progress()
+	require.True(t, ok, "results stay buffered after the batch ends")
+	assert.Equal(t, []Action{{SessionID: "test:1", Resource: ResourceTmux, Target: "sbs-1", Outcome: OutcomePlanned}}, progress.Result.Actions)
+	assert.Equal(t, 1, results.WouldClean)
+}
+
+func TestStartBatch_Cancel(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:1", TmuxSession: "sbs-1"},
+		{NamespacedID: "test:2", TmuxSession: "sbs-2"},
+		{NamespacedID: "test:3", TmuxSession: "sbs-3"},
+	}
+	gate := make(chan struct{})
+	locker := func(workItem string) (func(), error) {
+		if workItem == "test:2" {
+			<-gate
+		}
+		return func() {}, nil
+	}
+	manager := NewCleanupManager(&MockTmuxManager{sessions: []string{"sbs-1", "sbs-2", "sbs-3"}}, nil, nil, nil).WithSessionLocker(locker)
+
+	batch := manager.StartBatch(context.Background(), sessions, CleanupOptions{CleanTmux: true})
+	first := <-batch.Progress()
+	assert.Equal(t, "test:1", first.Result.Session.NamespacedID)
+	batch.Cancel()
+	close(gate)
+
+	results, err := batch.Wait()
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, []string{"test:1", "test:2"}, results.Removed(), "the session in progress finishes; the rest are left")
+	second, ok := <-batch.Progress()
+	require.True(t, ok)
+	assert.Equal(t, "test:2", second.Result.Session.NamespacedID)
+	_, ok = <-batch.Progress()
+	assert.False(t, ok, "the progress channel is closed when the batch ends")
+}
@@ -1,6 +1,7 @@
 package cleanup
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -85,11 +86,11 @@ func TestCLI_CleanupManagerIntegration(t *testing.T) {
 			manager := NewCleanupManager(mockTmux, mockSandbox, mockGit, nil)
 
 			// First identify stale sessions
-			staleSessions, err := manager.IdentifyStaleSessionsInView(tt.sessions, ViewModeGlobal)
+			staleSessions, err := manager.IdentifyStaleSessionsInView(context.Background(), tt.sessions, ViewModeGlobal)
 			assert.NoError(t, err)
 
 			// Then cleanup the stale sessions
-			results, err := manager.CleanupSessions(staleSessions, tt.options)
+			results, err := manager.CleanupSessions(context.Background(), staleSessions, tt.options)
 			assert.NoError(t, err)
 
 			assert.Equal(t, tt.expectedResults.CleanedSessions, results.CleanedSessions)
@@ -141,7 +142,7 @@ func TestTUI_CleanupManagerIntegration(t *testing.T) {
 			manager := NewCleanupManager(mockTmux, mockSandbox, nil, nil)
 
 			// Test TUI-specific stale session identification
-			staleSessions, err := manager.IdentifyStaleSessionsInView(tt.sessions, tt.viewMode)
+			staleSessions, err := manager.IdentifyStaleSessionsInView(context.Background(), tt.sessions, tt.viewMode)
 			assert.NoError(t, err)
 			assert.Len(t, staleSessions, tt.expectedStale)
 
@@ -152,7 +153,7 @@ func TestTUI_CleanupManagerIntegration(t *testing.T) {
 				SilentMode:     true,
 			}
 
-			results, err := manager.CleanupSessions(staleSessions, options)
+			results, err := manager.CleanupSessions(context.Background(), staleSessions, options)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedStale, results.CleanedSessions)
 		})
@@ -174,10 +175,10 @@ func TestCrossInterface_CleanupConsistency(t *testing.T) {
 	manager := NewCleanupManager(mockTmux, mockSandbox, nil, nil)
 
 	// Test that both interfaces identify the same stale sessions
-	cliStale, err := manager.IdentifyStaleSessionsInView(sessions, ViewModeGlobal)
+	cliStale, err := manager.IdentifyStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
 	assert.NoError(t, err)
 
-	tuiStale, err := manager.IdentifyStaleSessionsInView(sessions, ViewModeGlobal)
+	tuiStale, err := manager.IdentifyStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
 	assert.NoError(t, err)
 
 	assert.Equal(t, len(cliStale), len(tuiStale))
@@ -1,11 +1,44 @@
+// Package cleanup removes the resources of stale sbs sessions. It is the
+// supported programmatic entry point for cleanup; the CLI and the TUI both go
+// through it. The package never prints: outcomes are returned as typed Actions.
+//
+//	manager := cleanup.NewCleanupManager(tmuxManager, sandboxManager, nil, nil)
+//	stale, err := manager.IdentifyStaleSessionsInView(ctx, sessions, cleanup.ViewModeGlobal)
+//	...
+//	results, err := manager.CleanupSessions(ctx, stale, cleanup.NewCleanupOptions(
+//		cleanup.WithResources(cleanup.ResourceSandbox|cleanup.ResourceWorktree),
+//		cleanup.WithDryRun(true),
+//	))
+//	for _, action := range results.Actions {
+//		fmt.Println(action)
+//	}
+//
+// StartBatch runs the same cleanup in the background, streaming each session's
+// result as it finishes:
+//
+//	batch := manager.StartBatch(ctx, stale, options)
+//	for progress := range batch.Progress() {
+//		fmt.Printf("%d/%d %s\n", progress.Done, progress.Total, progress.Result.Session.NamespacedID)
+//	}
+//	results, err := batch.Wait()
 package cleanup
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"sbs/pkg/clock"
 	"sbs/pkg/config"
+	"sbs/pkg/expiry"
+	"sbs/pkg/oplock"
+	"sbs/pkg/protection"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/stalehook"
+	"sbs/pkg/tmux"
 )
 
 // ViewMode represents the different view modes for session filtering
@@ -26,17 +59,23 @@ const (
 	CleanupModeBranches
 	CleanupModeAll
 	CleanupModeStaleAndBranches
+	CleanupModeRepoMissing
 )
 
 // CleanupOptions contains configuration for cleanup operations
 type CleanupOptions struct {
 	// Behavior options
+	CleanTmux      bool
 	CleanSandboxes bool
 	CleanWorktrees bool
 	CleanBranches  bool
 	DryRun         bool
 	Force          bool
 
+	// RemoveWorktreeDirs deletes worktree directories outright, for
+	// worktrees git can no longer remove, e.g. of deleted repositories
+	RemoveWorktreeDirs bool
+
 	// Interface options
 	RequireConfirmation bool
 	VerboseLogging      bool
@@ -46,17 +85,28 @@ type CleanupOptions struct {
 	// Context options
 	ViewMode         ViewMode
 	RepositoryFilter string
+
+	// Only is the resource mask requested by the user (e.g. --only tmux);
+	// zero when every resource enabled above should be cleaned
+	Only ResourceMask
 }
 
 // CleanupResults contains the results of cleanup operations
 type CleanupResults struct {
 	CleanedSessions  int
+	CleanedTmux      int
 	CleanedSandboxes int
 	CleanedWorktrees int
 	CleanedBranches  int
 	WouldClean       int // For dry run
-	Errors           []error
-	Details          []string // For verbose output
+
+	// Actions records what happened to each resource, in order
+	Actions []Action
+	// Errors holds a *ResourceError for every failed action
+	Errors []error
+	// Details holds human-readable lines: the dry-run plan, or the rendered
+	// actions when VerboseLogging is set
+	Details []string
 }
 
 // TmuxManager interface for tmux operations
@@ -83,12 +133,57 @@ type ConfigManager interface {
 	SaveSessions(sessions []config.SessionMetadata) error
 }
 
+// SessionCleaner identifies and cleans stale sessions. CleanupManager is the
+// production implementation; front ends depend on this interface so they can be
+// tested with fakes.
+type SessionCleaner interface {
+	IdentifyStaleSessionsInView(ctx context.Context, sessions []config.SessionMetadata, viewMode ViewMode) ([]config.SessionMetadata, error)
+	ExplainStaleSessionsInView(ctx context.Context, sessions []config.SessionMetadata, viewMode ViewMode) ([]StaleSession, error)
+	CleanupSessions(ctx context.Context, sessions []config.SessionMetadata, options CleanupOptions) (CleanupResults, error)
+	StartBatch(ctx context.Context, sessions []config.SessionMetadata, options CleanupOptions) *Batch
+	BuildTUICleanupOptions(viewMode ViewMode, silent bool) CleanupOptions
+	BuildCLICleanupOptions(dryRun, force bool, mode CleanupMode) CleanupOptions
+	ResolveSandboxName(session config.SessionMetadata) string
+}
+
+var _ SessionCleaner = (*CleanupManager)(nil)
+
+// StaleDetector gives a custom staleness verdict for a session. The default
+// runs the repository's .sbs/stalehook.
+type StaleDetector func(ctx context.Context, session config.SessionMetadata) (stalehook.Verdict, error)
+
+// SessionLocker claims a session's work item for cleanup and returns the
+// function releasing it. An error means another process is working on the
+// session, which is then skipped.
+type SessionLocker func(workItem string) (release func(), err error)
+
+// OperationLocker is the SessionLocker coordinating with other sbs processes
+// through operation locks. Locking is best effort: when the lock file can't
+// be created the session is cleaned unlocked.
+func OperationLocker(workItem string) (func(), error) {
+	lock, err := oplock.Acquire(workItem, oplock.OpClean)
+	var conflict *oplock.ConflictError
+	if errors.As(err, &conflict) {
+		return nil, err
+	}
+	return func() { lock.Release() }, nil
+}
+
+// ProtectionRules returns the protected branches and worktrees of a
+// repository, such as protection.ForRepository
+type ProtectionRules func(repoRoot string) protection.Rules
+
 // CleanupManager provides unified cleanup functionality
 type CleanupManager struct {
 	tmuxManager    TmuxManager
 	sandboxManager SandboxManager
 	gitManager     GitManager
 	configManager  ConfigManager
+	staleDetector  StaleDetector
+	sessionLocker  SessionLocker
+	protection     ProtectionRules
+	expiry         expiry.Policy
+	clock          clock.Clock
 }
 
 // NewCleanupManager creates a new cleanup manager
@@ -98,64 +193,344 @@ func NewCleanupManager(tmux TmuxManager, sandbox SandboxManager, git GitManager,
 		sandboxManager: sandbox,
 		gitManager:     git,
 		configManager:  config,
+		staleDetector:  stalehook.Run,
 	}
 }
 
-// IdentifyStaleSessionsInView identifies stale sessions for a given view mode
-func (c *CleanupManager) IdentifyStaleSessionsInView(sessions []config.SessionMetadata, viewMode ViewMode) ([]config.SessionMetadata, error) {
-	var staleSessions []config.SessionMetadata
+// WithStaleDetector returns a copy of the manager that asks detect for custom
+// staleness verdicts; nil leaves staleness to the tmux session check alone
+func (c *CleanupManager) WithStaleDetector(detect StaleDetector) *CleanupManager {
+	bound := *c
+	bound.staleDetector = detect
+	return &bound
+}
+
+// WithSessionLocker returns a copy of the manager that claims each session
+// with lock before cleaning it; nil cleans without locking
+func (c *CleanupManager) WithSessionLocker(lock SessionLocker) *CleanupManager {
+	bound := *c
+	bound.sessionLocker = lock
+	return &bound
+}
+
+// WithSandboxManager returns a copy of the manager that checks and deletes
+// sandboxes through sandbox, e.g. one in sandbox_required mode
+func (c *CleanupManager) WithSandboxManager(sandbox SandboxManager) *CleanupManager {
+	bound := *c
+	bound.sandboxManager = sandbox
+	return &bound
+}
+
+// WithProtection returns a copy of the manager that keeps the worktrees rules
+// protect; nil protects none
+func (c *CleanupManager) WithProtection(rules ProtectionRules) *CleanupManager {
+	bound := *c
+	bound.protection = rules
+	return &bound
+}
+
+// WithExpiryPolicy returns a copy of the manager that also treats sessions
+// expired under policy as stale, killing their tmux sessions when cleaning
+// them since they may still be running
+func (c *CleanupManager) WithExpiryPolicy(policy expiry.Policy) *CleanupManager {
+	bound := *c
+	bound.expiry = policy
+	return &bound
+}
+
+// WithClock returns a copy of the manager that checks expiry against c
+// instead of the clock set with config.SetClock
+func (c *CleanupManager) WithClock(clk clock.Clock) *CleanupManager {
+	bound := *c
+	bound.clock = clk
+	return &bound
+}
+
+func (c *CleanupManager) now() time.Time {
+	if c.clock == nil {
+		return config.Now()
+	}
+	return c.clock.Now()
+}
+
+// cleansTmux reports whether cleaning a session kills its tmux session
+func (c *CleanupManager) cleansTmux(session config.SessionMetadata, options CleanupOptions) bool {
+	if options.CleanTmux {
+		return true
+	}
+	return options.Only == 0 && c.expiry.Check(session, c.now()).Expired()
+}
+
+// worktreeProtection returns why a session's worktree must be kept, or nil
+func (c *CleanupManager) worktreeProtection(session config.SessionMetadata) error {
+	if c.protection == nil {
+		return nil
+	}
+	return c.protection(session.RepositoryRoot).CheckWorktree(session.WorktreePath)
+}
+
+// bindContext returns a copy of the manager whose tmux and sandbox commands
+// run under ctx, so they stop with it and are logged with its correlation ID
+func (c *CleanupManager) bindContext(ctx context.Context) *CleanupManager {
+	bound := *c
+	if manager, ok := c.tmuxManager.(*tmux.Manager); ok {
+		bound.tmuxManager = manager.WithContext(ctx)
+	}
+	if manager, ok := c.sandboxManager.(*sandbox.Manager); ok {
+		bound.sandboxManager = manager.WithContext(ctx)
+	}
+	return &bound
+}
+
+// Reasons a session is considered stale
+const (
+	StaleReasonTmuxGone  = "tmux session no longer exists"
+	StaleReasonStaleHook = "marked stale by .sbs/stalehook"
+	StaleReasonExpired   = "not used within clean_after_days"
+)
+
+// StaleSession is a session identified as stale, with the reason why
+type StaleSession struct {
+	Session config.SessionMetadata
+	Reason  string
+}
+
+// IdentifyStaleSessionsInView identifies stale sessions for a given view mode.
+// A session is stale when its tmux session is gone or it expired under the
+// expiry policy, unless the stale detector (the repository's .sbs/stalehook)
+// decides otherwise. It stops early and returns ctx.Err() if ctx is cancelled.
+func (c *CleanupManager) IdentifyStaleSessionsInView(ctx context.Context, sessions []config.SessionMetadata, viewMode ViewMode) ([]config.SessionMetadata, error) {
+	explained, err := c.ExplainStaleSessionsInView(ctx, sessions, viewMode)
+	staleSessions := make([]config.SessionMetadata, 0, len(explained))
+	for _, stale := range explained {
+		staleSessions = append(staleSessions, stale.Session)
+	}
+	if len(staleSessions) == 0 {
+		staleSessions = nil
+	}
+	return staleSessions, err
+}
+
+// ExplainStaleSessionsInView is IdentifyStaleSessionsInView with the reason
+// each session is considered stale
+func (c *CleanupManager) ExplainStaleSessionsInView(ctx context.Context, sessions []config.SessionMetadata, viewMode ViewMode) ([]StaleSession, error) {
+	c = c.bindContext(ctx)
+	var staleSessions []StaleSession
+	now := c.now()
 
 	for _, session := range sessions {
+		if err := ctx.Err(); err != nil {
+			return staleSessions, err
+		}
+		if c.staleDetector != nil {
+			// Hook failures are logged by the detector and leave the decision to tmux
+			verdict, _ := c.staleDetector(ctx, session)
+			if verdict == stalehook.Stale {
+				staleSessions = append(staleSessions, StaleSession{Session: session, Reason: StaleReasonStaleHook})
+				continue
+			}
+			if verdict == stalehook.Active {
+				continue
+			}
+		}
 		exists, err := c.tmuxManager.SessionExists(session.TmuxSession)
 		if err != nil {
 			// If we can't check the session, treat it as active to be safe
 			continue
 		}
 		if !exists {
-			staleSessions = append(staleSessions, session)
+			staleSessions = append(staleSessions, StaleSession{Session: session, Reason: StaleReasonTmuxGone})
+		} else if c.expiry.Check(session, now).Expired() {
+			staleSessions = append(staleSessions, StaleSession{Session: session, Reason: StaleReasonExpired})
 		}
 	}
 
 	return staleSessions, nil
 }
 
-// CleanupSessions performs cleanup of sessions according to the given options
-func (c *CleanupManager) CleanupSessions(sessions []config.SessionMetadata, options CleanupOptions) (CleanupResults, error) {
+// IdentifyRepoMissingSessions returns the sessions whose repository
+// directory no longer exists. Each repository is checked once.
+func IdentifyRepoMissingSessions(sessions []config.SessionMetadata) []config.SessionMetadata {
+	var repoMissing []config.SessionMetadata
+	missing := make(map[string]bool)
+	for _, session := range sessions {
+		if _, checked := missing[session.RepositoryRoot]; !checked {
+			missing[session.RepositoryRoot] = session.RepositoryMissing()
+		}
+		if missing[session.RepositoryRoot] {
+			repoMissing = append(repoMissing, session)
+		}
+	}
+	return repoMissing
+}
+
+// CleanupSessions performs cleanup of sessions according to the given options.
+// Per-resource failures are reported in the results rather than as an error;
+// the error is only set when ctx is cancelled, in which case the results cover
+// the sessions processed so far. StartBatch runs the same cleanup in the
+// background, reporting each session as it finishes.
+func (c *CleanupManager) CleanupSessions(ctx context.Context, sessions []config.SessionMetadata, options CleanupOptions) (CleanupResults, error) {
+	return c.runBatch(ctx, filterSessions(sessions, options), options, nil)
+}
+
+// filterSessions applies the options' repository filter
+func filterSessions(sessions []config.SessionMetadata, options CleanupOptions) []config.SessionMetadata {
+	if options.RepositoryFilter == "" {
+		return sessions
+	}
+	var filtered []config.SessionMetadata
+	for _, session := range sessions {
+		if session.RepositoryRoot == options.RepositoryFilter {
+			filtered = append(filtered, session)
+		}
+	}
+	return filtered
+}
+
+// runBatch cleans sessions in order, calling finished (when set) with each
+// session's result as soon as it is done
+func (c *CleanupManager) runBatch(ctx context.Context, sessions []config.SessionMetadata, options CleanupOptions, finished func(SessionResult)) (CleanupResults, error) {
+	c = c.bindContext(ctx)
 	results := CleanupResults{
 		Errors:  []error{},
 		Details: []string{},
 	}
+	report := func(session config.SessionMetadata, firstAction int, cleaned bool) {
+		if finished == nil {
+			return
+		}
+		finished(SessionResult{
+			Session: session,
+			Actions: append([]Action(nil), results.Actions[firstAction:]...),
+			Cleaned: cleaned,
+		})
+	}
 
 	if options.DryRun {
 		// Count what would be cleaned
 		results.WouldClean = len(sessions)
 
-		// Add details for verbose output
 		for _, session := range sessions {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			firstAction := len(results.Actions)
+			planned := func(resource ResourceMask, target string) {
+				results.Actions = append(results.Actions, Action{
+					SessionID: session.NamespacedID,
+					Title:     session.IssueTitle,
+					Resource:  resource,
+					Target:    target,
+					Outcome:   OutcomePlanned,
+				})
+			}
 			details := fmt.Sprintf("Would clean Work Item %s: %s", session.NamespacedID, session.IssueTitle)
-			if session.WorktreePath != "" {
-				details += fmt.Sprintf("\n    Worktree: %s", session.WorktreePath)
+			if c.cleansTmux(session, options) && session.TmuxSession != "" {
+				details += fmt.Sprintf("\n    Tmux Session: %s", session.TmuxSession)
+				planned(ResourceTmux, session.TmuxSession)
+			}
+			if session.WorktreePath != "" && (options.Only == 0 || options.CleanWorktrees) {
+				if err := c.worktreeProtection(session); err != nil {
+					details += fmt.Sprintf("\n    Worktree: %s (protected, kept)", session.WorktreePath)
+					results.Actions = append(results.Actions, Action{
+						SessionID: session.NamespacedID,
+						Title:     session.IssueTitle,
+						Resource:  ResourceWorktree,
+						Target:    session.WorktreePath,
+						Outcome:   OutcomeProtected,
+						Err:       err,
+					})
+				} else {
+					details += fmt.Sprintf("\n    Worktree: %s", session.WorktreePath)
+					planned(ResourceWorktree, session.WorktreePath)
+				}
 			}
 			sandboxName := c.ResolveSandboxName(session)
-			if sandboxName != "" {
+			if sandboxName != "" && (options.Only == 0 || options.CleanSandboxes) {
 				details += fmt.Sprintf("\n    Sandbox: %s", sandboxName)
+				planned(ResourceSandbox, sandboxName)
 			}
 			results.Details = append(results.Details, details)
+			report(session, firstAction, false)
 		}
 
 		return results, nil
 	}
 
 	for _, session := range sessions {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		firstAction := len(results.Actions)
+
+		release := func() {}
+		if c.sessionLocker != nil {
+			unlock, err := c.sessionLocker(session.NamespacedID)
+			if err != nil {
+				results.record(Action{
+					SessionID: session.NamespacedID,
+					Title:     session.IssueTitle,
+					Target:    session.NamespacedID,
+					Outcome:   OutcomeBusy,
+					Err:       err,
+				}, options.VerboseLogging)
+				report(session, firstAction, false)
+				continue
+			}
+			release = unlock
+		}
+
 		sessionCleaned := false
-		var sessionErrors []error
+		record := func(resource ResourceMask, target string, outcome Outcome, op string, err error) {
+			action := Action{
+				SessionID: session.NamespacedID,
+				Title:     session.IssueTitle,
+				Resource:  resource,
+				Target:    target,
+				Outcome:   outcome,
+			}
+			if err != nil {
+				action.Err = &ResourceError{SessionID: session.NamespacedID, Resource: resource, Target: target, Op: op, Err: err}
+			}
+			if outcome == OutcomeRemoved {
+				sessionCleaned = true
+			}
+			results.record(action, options.VerboseLogging)
+		}
+
+		// Kill tmux sessions if requested (e.g. to free memory while keeping worktrees)
+		if c.cleansTmux(session, options) && session.TmuxSession != "" && c.tmuxManager != nil {
+			exists, err := c.tmuxManager.SessionExists(session.TmuxSession)
+			if err != nil {
+				record(ResourceTmux, session.TmuxSession, OutcomeFailed, "check", err)
+			} else if exists {
+				if err := c.tmuxManager.KillSession(session.TmuxSession); err != nil {
+					record(ResourceTmux, session.TmuxSession, OutcomeFailed, "remove", err)
+				} else {
+					results.CleanedTmux++
+					record(ResourceTmux, session.TmuxSession, OutcomeRemoved, "", nil)
+				}
+			} else {
+				record(ResourceTmux, session.TmuxSession, OutcomeMissing, "", nil)
+			}
+		}
 
 		// Clean worktrees if requested (CLI-style comprehensive cleanup)
-		if options.CleanWorktrees && session.WorktreePath != "" {
+		protectedErr := c.worktreeProtection(session)
+		if options.CleanWorktrees && session.WorktreePath != "" && protectedErr != nil {
+			results.record(Action{
+				SessionID: session.NamespacedID,
+				Title:     session.IssueTitle,
+				Resource:  ResourceWorktree,
+				Target:    session.WorktreePath,
+				Outcome:   OutcomeProtected,
+				Err:       protectedErr,
+			}, options.VerboseLogging)
+		} else if options.CleanWorktrees && session.WorktreePath != "" {
 			worktreeExists := false
 
 			// Check if worktree exists using GitManager if available, otherwise fall back to filesystem
-			if c.gitManager != nil {
+			if c.gitManager != nil && !options.RemoveWorktreeDirs {
 				worktreeExists = c.gitManager.WorktreeExists(session.WorktreePath)
 			} else {
 				// Fallback to filesystem check for backward compatibility
@@ -164,63 +539,47 @@ func (c *CleanupManager) CleanupSessions(sessions []config.SessionMetadata, opti
 				}
 			}
 
-			if worktreeExists {
+			if worktreeExists && options.RemoveWorktreeDirs {
+				if err := c.removeWorktreeDirectory(session.WorktreePath); err != nil {
+					record(ResourceWorktree, session.WorktreePath, OutcomeFailed, "remove", err)
+				} else {
+					results.CleanedWorktrees++
+					record(ResourceWorktree, session.WorktreePath, OutcomeRemoved, "", nil)
+				}
+			} else if worktreeExists {
 				// In production, we would call c.removeWorktreeDirectory(session.WorktreePath)
 				// For testing with mocks, we just count it as cleaned if it exists
 				results.CleanedWorktrees++
-				sessionCleaned = true
-				if options.VerboseLogging {
-					results.Details = append(results.Details, fmt.Sprintf("Removed worktree: %s", session.WorktreePath))
-				}
+				record(ResourceWorktree, session.WorktreePath, OutcomeRemoved, "", nil)
 			} else {
-				if options.VerboseLogging {
-					results.Details = append(results.Details, fmt.Sprintf("Worktree already gone: %s", session.WorktreePath))
-				}
+				record(ResourceWorktree, session.WorktreePath, OutcomeMissing, "", nil)
 			}
 		}
 
 		// Clean sandboxes if requested
-		if options.CleanSandboxes {
-			sandboxName := c.ResolveSandboxName(session)
-			if sandboxName != "" {
-				if c.sandboxManager != nil {
-					exists, err := c.sandboxManager.SandboxExists(sandboxName)
-					if err != nil {
-						sessionErrors = append(sessionErrors, fmt.Errorf("could not check sandbox %s: %w", sandboxName, err))
-						if options.VerboseLogging {
-							results.Details = append(results.Details, fmt.Sprintf("Warning: could not check sandbox %s: %v", sandboxName, err))
-						}
-					} else if exists {
-						if options.VerboseLogging {
-							results.Details = append(results.Details, fmt.Sprintf("Attempting to delete sandbox: %s", sandboxName))
-						}
-						if err := c.sandboxManager.DeleteSandbox(sandboxName); err != nil {
-							sessionErrors = append(sessionErrors, fmt.Errorf("failed to delete sandbox %s: %w", sandboxName, err))
-							if options.VerboseLogging {
-								results.Details = append(results.Details, fmt.Sprintf("Warning: failed to delete sandbox %s: %v", sandboxName, err))
-							}
-						} else {
-							results.CleanedSandboxes++
-							sessionCleaned = true
-							if options.VerboseLogging {
-								results.Details = append(results.Details, fmt.Sprintf("Removed sandbox: %s", sandboxName))
-							}
-						}
+		if options.CleanSandboxes && c.sandboxManager != nil {
+			if sandboxName := c.ResolveSandboxName(session); sandboxName != "" {
+				exists, err := c.sandboxManager.SandboxExists(sandboxName)
+				if err != nil {
+					record(ResourceSandbox, sandboxName, OutcomeFailed, "check", err)
+				} else if exists {
+					if err := c.sandboxManager.DeleteSandbox(sandboxName); err != nil {
+						record(ResourceSandbox, sandboxName, OutcomeFailed, "remove", err)
 					} else {
-						if options.VerboseLogging {
-							results.Details = append(results.Details, fmt.Sprintf("Sandbox already gone: %s", sandboxName))
-						}
+						results.CleanedSandboxes++
+						record(ResourceSandbox, sandboxName, OutcomeRemoved, "", nil)
 					}
+				} else {
+					record(ResourceSandbox, sandboxName, OutcomeMissing, "", nil)
 				}
 			}
 		}
 
+		release()
 		if sessionCleaned {
 			results.CleanedSessions++
 		}
-
-		// Collect any errors from this session
-		results.Errors = append(results.Errors, sessionErrors...)
+		report(session, firstAction, sessionCleaned)
 	}
 
 	return results, nil
@@ -284,6 +643,12 @@ func (c *CleanupManager) BuildCLICleanupOptions(dryRun, force bool, mode Cleanup
 		options.CleanSandboxes = true
 		options.CleanWorktrees = true
 		options.CleanBranches = true
+	case CleanupModeRepoMissing:
+		// Nothing of these sessions can be resumed; their branches went with the repository
+		options.CleanTmux = true
+		options.CleanSandboxes = true
+		options.CleanWorktrees = true
+		options.RemoveWorktreeDirs = true
 	}
 
 	return options
@@ -306,14 +671,14 @@ func (c *CleanupManager) BuildTUICleanupOptions(viewMode ViewMode, silent bool)
 
 // IdentifyAndCleanupStaleSessionsForTUI is a convenience method for TUI that combines
 // identification and cleanup in one call, similar to the original TUI implementation
-func (c *CleanupManager) IdentifyAndCleanupStaleSessionsForTUI(sessions []config.SessionMetadata, viewMode ViewMode) (CleanupResults, error) {
+func (c *CleanupManager) IdentifyAndCleanupStaleSessionsForTUI(ctx context.Context, sessions []config.SessionMetadata, viewMode ViewMode) (CleanupResults, error) {
 	// First identify stale sessions
-	staleSessions, err := c.IdentifyStaleSessionsInView(sessions, viewMode)
+	staleSessions, err := c.IdentifyStaleSessionsInView(ctx, sessions, viewMode)
 	if err != nil {
 		return CleanupResults{}, err
 	}
 
 	// Then clean them up using TUI-style options
 	options := c.BuildTUICleanupOptions(viewMode, true)
-	return c.CleanupSessions(staleSessions, options)
+	return c.CleanupSessions(ctx, staleSessions, options)
 }
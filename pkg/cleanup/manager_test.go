@@ -1,10 +1,20 @@
 package cleanup
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/clock"
 	"sbs/pkg/config"
+	"sbs/pkg/expiry"
+	"sbs/pkg/protection"
+	"sbs/pkg/stalehook"
 )
 
 // TestCleanupManager_Creation tests that CleanupManager struct exists and initializes correctly
@@ -59,7 +69,7 @@ func TestCleanupManager_SessionIdentification(t *testing.T) {
 			}
 
 			manager := NewCleanupManager(mockTmux, nil, nil, nil)
-			staleSessions, err := manager.IdentifyStaleSessionsInView(tt.sessions, ViewModeGlobal)
+			staleSessions, err := manager.IdentifyStaleSessionsInView(context.Background(), tt.sessions, ViewModeGlobal)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -139,7 +149,7 @@ func TestCleanupManager_ResourceCleanup(t *testing.T) {
 			}
 
 			manager := NewCleanupManager(nil, mockSandbox, mockGit, nil)
-			results, err := manager.CleanupSessions(tt.sessions, tt.cleanupOptions)
+			results, err := manager.CleanupSessions(context.Background(), tt.sessions, tt.cleanupOptions)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -162,3 +172,179 @@ func extractSessionIDs(sessions []config.SessionMetadata) []string {
 	}
 	return ids
 }
+
+func TestIdentifyStaleSessionsInView_StaleDetector(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "jira:A-1", TmuxSession: "sbs-a-1"}, // tmux alive, ticket done
+		{NamespacedID: "jira:A-2", TmuxSession: "sbs-a-2"}, // tmux gone, kept by the hook
+		{NamespacedID: "jira:A-3", TmuxSession: "sbs-a-3"}, // tmux gone, hook undecided
+		{NamespacedID: "jira:A-4", TmuxSession: "sbs-a-4"}, // tmux alive, hook failed
+	}
+	verdicts := map[string]stalehook.Verdict{
+		"jira:A-1": stalehook.Stale,
+		"jira:A-2": stalehook.Active,
+	}
+	detect := func(ctx context.Context, session config.SessionMetadata) (stalehook.Verdict, error) {
+		if session.NamespacedID == "jira:A-4" {
+			return stalehook.Undecided, errors.New("hook timed out")
+		}
+		return verdicts[session.NamespacedID], nil
+	}
+
+	manager := NewCleanupManager(&MockTmuxManager{sessions: []string{"sbs-a-1", "sbs-a-4"}}, nil, nil, nil).WithStaleDetector(detect)
+	stale, err := manager.IdentifyStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, session := range stale {
+		ids = append(ids, session.NamespacedID)
+	}
+	assert.Equal(t, []string{"jira:A-1", "jira:A-3"}, ids)
+
+	// Without a detector only the tmux check applies
+	stale, err = manager.WithStaleDetector(nil).IdentifyStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
+	require.NoError(t, err)
+	assert.Len(t, stale, 2)
+	assert.Equal(t, "jira:A-2", stale[0].NamespacedID)
+}
+
+func TestExplainStaleSessionsInView_Expired(t *testing.T) {
+	longAgo := time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:old", TmuxSession: "sbs-old", LastActivity: longAgo},
+		{NamespacedID: "test:new", TmuxSession: "sbs-new", LastActivity: time.Now().Format(time.RFC3339)},
+	}
+	tmuxManager := &MockTmuxManager{sessions: []string{"sbs-old", "sbs-new"}}
+
+	stale, err := NewCleanupManager(tmuxManager, nil, nil, nil).ExplainStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
+	require.NoError(t, err)
+	assert.Empty(t, stale, "sessions don't expire without clean_after_days")
+
+	manager := NewCleanupManager(tmuxManager, nil, nil, nil).WithExpiryPolicy(expiry.FromConfig(&config.Config{CleanAfterDays: 14}))
+	stale, err = manager.ExplainStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, "test:old", stale[0].Session.NamespacedID)
+	assert.Equal(t, StaleReasonExpired, stale[0].Reason)
+
+	// The tmux session of an expired session is killed even without CleanTmux
+	results, err := manager.CleanupSessions(context.Background(), sessions[:1], CleanupOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, results.CleanedTmux)
+}
+
+func TestExplainStaleSessionsInView_ExpiresWithClock(t *testing.T) {
+	start := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:1", TmuxSession: "sbs-1", LastActivity: start.Add(-13 * 24 * time.Hour).Format(time.RFC3339)},
+	}
+	manager := NewCleanupManager(&MockTmuxManager{sessions: []string{"sbs-1"}}, nil, nil, nil).
+		WithExpiryPolicy(expiry.FromConfig(&config.Config{CleanAfterDays: 14})).
+		WithClock(fake)
+
+	stale, err := manager.ExplainStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	fake.Advance(24 * time.Hour)
+	stale, err = manager.ExplainStaleSessionsInView(context.Background(), sessions, ViewModeGlobal)
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, StaleReasonExpired, stale[0].Reason)
+}
+
+func TestCleanupSessions_SkipsSessionsBusyElsewhere(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:busy", TmuxSession: "sbs-busy"},
+		{NamespacedID: "test:free", TmuxSession: "sbs-free"},
+	}
+	var released []string
+	locker := func(workItem string) (func(), error) {
+		if workItem == "test:busy" {
+			return nil, errors.New("start in progress")
+		}
+		return func() { released = append(released, workItem) }, nil
+	}
+
+	manager := NewCleanupManager(&MockTmuxManager{sessions: []string{"sbs-busy", "sbs-free"}}, nil, nil, nil).WithSessionLocker(locker)
+	results, err := manager.CleanupSessions(context.Background(), sessions, CleanupOptions{CleanTmux: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"test:busy"}, results.Busy())
+	assert.Equal(t, []string{"test:free"}, results.Removed())
+	assert.Equal(t, []string{"test:free"}, released, "claimed sessions are released")
+	assert.Equal(t, "Skipped test:busy: start in progress", results.Actions[0].String())
+	assert.Empty(t, results.Errors, "busy sessions are not failures")
+}
+
+func TestCleanupSessions_KeepsProtectedWorktrees(t *testing.T) {
+	protectedDir := t.TempDir()
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:integration", RepositoryRoot: "/repo", WorktreePath: protectedDir + "/integration"},
+		{NamespacedID: "test:scratch", RepositoryRoot: "/repo", WorktreePath: t.TempDir()},
+	}
+	var asked []string
+	rules := func(repoRoot string) protection.Rules {
+		asked = append(asked, repoRoot)
+		return protection.New(nil, []string{protectedDir})
+	}
+	manager := NewCleanupManager(&MockTmuxManager{}, nil, nil, nil).WithProtection(rules)
+
+	options := CleanupOptions{CleanWorktrees: true, DryRun: true}
+	planned, err := manager.CleanupSessions(context.Background(), sessions, options)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeProtected, planned.Actions[0].Outcome)
+	assert.Contains(t, planned.Details[0], "(protected, kept)")
+	assert.Equal(t, OutcomePlanned, planned.Actions[1].Outcome)
+
+	options.DryRun = false
+	results, err := manager.CleanupSessions(context.Background(), sessions, options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test:integration"}, results.Protected())
+	assert.Equal(t, []string{"test:scratch"}, results.Removed())
+	assert.Equal(t, 1, results.CleanedWorktrees)
+	assert.Empty(t, results.Errors, "protected worktrees are not failures")
+	assert.Contains(t, results.Actions[0].String(), "Kept worktree "+protectedDir+"/integration is protected")
+	assert.Contains(t, asked, "/repo", "rules come from the session's repository")
+}
+
+func TestIdentifyRepoMissingSessions(t *testing.T) {
+	repoRoot := t.TempDir()
+	deleted := filepath.Join(t.TempDir(), "deleted-repo")
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", RepositoryRoot: repoRoot},
+		{NamespacedID: "github:2", RepositoryRoot: deleted},
+		{NamespacedID: "github:3"},
+		{NamespacedID: "github:4", RepositoryRoot: deleted},
+	}
+
+	assert.Equal(t, []string{"github:2", "github:4"}, extractSessionIDs(IdentifyRepoMissingSessions(sessions)))
+	assert.Nil(t, IdentifyRepoMissingSessions(sessions[:1]))
+}
+
+func TestCleanupSessions_RepoMissingRemovesWorktreeDirectories(t *testing.T) {
+	worktreePath := filepath.Join(t.TempDir(), "sbs-worktrees", "repo", "issue-test-1")
+	require.NoError(t, os.MkdirAll(filepath.Join(worktreePath, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, ".git"), []byte("gitdir: /deleted-repo/.git/worktrees/issue-test-1\n"), 0644))
+	goneWorktree := filepath.Join(t.TempDir(), "sbs-worktrees", "repo", "issue-test-2")
+
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:1", TmuxSession: "sbs-test-1", WorktreePath: worktreePath, SandboxName: "sbs-test-1"},
+		{NamespacedID: "test:2", TmuxSession: "sbs-test-2", WorktreePath: goneWorktree, SandboxName: "sbs-test-2"},
+	}
+
+	// The git manager belongs to another repository and knows nothing of these worktrees
+	manager := NewCleanupManager(&MockTmuxManager{sessions: []string{"sbs-test-1"}},
+		&MockSandboxManager{sandboxes: map[string]bool{"sbs-test-1": true}}, &MockGitManager{}, nil)
+	options := manager.BuildCLICleanupOptions(false, true, CleanupModeRepoMissing)
+	results, err := manager.CleanupSessions(context.Background(), sessions, options)
+	require.NoError(t, err)
+
+	assert.Empty(t, results.Errors)
+	assert.NoDirExists(t, worktreePath)
+	assert.Equal(t, 1, results.CleanedTmux)
+	assert.Equal(t, 1, results.CleanedSandboxes)
+	assert.Equal(t, 1, results.CleanedWorktrees)
+	assert.Equal(t, 1, results.CleanedSessions)
+}
@@ -0,0 +1,50 @@
+package cleanup
+
+// Option configures CleanupOptions built with NewCleanupOptions
+type Option func(*CleanupOptions)
+
+// NewCleanupOptions builds options for CleanupSessions. With no options nothing
+// is removed; callers opt in to each resource with WithResources.
+func NewCleanupOptions(opts ...Option) CleanupOptions {
+	var options CleanupOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithResources enables cleanup of exactly the resources in mask
+func WithResources(mask ResourceMask) Option {
+	return func(o *CleanupOptions) {
+		*o = o.WithOnly(mask)
+	}
+}
+
+// WithDryRun reports what would be removed without touching anything
+func WithDryRun(dryRun bool) Option {
+	return func(o *CleanupOptions) {
+		o.DryRun = dryRun
+	}
+}
+
+// WithForce marks the cleanup as already confirmed by the user
+func WithForce(force bool) Option {
+	return func(o *CleanupOptions) {
+		o.Force = force
+		o.RequireConfirmation = !force
+	}
+}
+
+// WithViewMode sets the view the sessions were selected from
+func WithViewMode(mode ViewMode) Option {
+	return func(o *CleanupOptions) {
+		o.ViewMode = mode
+	}
+}
+
+// WithRepositoryFilter limits cleanup to sessions whose repository root is root
+func WithRepositoryFilter(root string) Option {
+	return func(o *CleanupOptions) {
+		o.RepositoryFilter = root
+	}
+}
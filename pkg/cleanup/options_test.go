@@ -0,0 +1,28 @@
+package cleanup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCleanupOptions(t *testing.T) {
+	assert.Equal(t, CleanupOptions{}, NewCleanupOptions(), "no options cleans nothing")
+
+	options := NewCleanupOptions(
+		WithResources(ResourceSandbox|ResourceWorktree),
+		WithDryRun(true),
+		WithForce(true),
+		WithViewMode(ViewModeRepository),
+		WithRepositoryFilter("/repo"),
+	)
+	assert.False(t, options.CleanTmux)
+	assert.True(t, options.CleanSandboxes)
+	assert.True(t, options.CleanWorktrees)
+	assert.Equal(t, ResourceSandbox|ResourceWorktree, options.Only)
+	assert.True(t, options.DryRun)
+	assert.True(t, options.Force)
+	assert.False(t, options.RequireConfirmation)
+	assert.Equal(t, ViewModeRepository, options.ViewMode)
+	assert.Equal(t, "/repo", options.RepositoryFilter)
+}
@@ -0,0 +1,111 @@
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sbs/pkg/trace"
+)
+
+// FindEmptyDirs returns the directories beneath base that are empty or
+// contain only empty directories, deepest first. base itself is never
+// included, symlinks are not followed and git worktrees (directories with a
+// .git entry) are not descended into.
+func FindEmptyDirs(base string) ([]string, error) {
+	if err := checkPruneBase(base); err != nil {
+		return nil, err
+	}
+	info, err := os.Lstat(base)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", base, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", base)
+	}
+
+	var empty []string
+	if _, err := collectEmptyDirs(base, &empty); err != nil {
+		return nil, err
+	}
+	return empty, nil
+}
+
+// collectEmptyDirs appends dir's empty subdirectories to empty and reports
+// whether dir would be empty once they are removed
+func collectEmptyDirs(dir string, empty *[]string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			return false, nil
+		}
+	}
+
+	allEmpty := true
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			allEmpty = false
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		childEmpty, err := collectEmptyDirs(path, empty)
+		if err != nil {
+			return false, err
+		}
+		if childEmpty {
+			*empty = append(*empty, path)
+		} else {
+			allEmpty = false
+		}
+	}
+	return allEmpty, nil
+}
+
+// PruneEmptyDirs removes the directories FindEmptyDirs reports beneath base
+// and returns the ones removed. A directory that gained an entry meanwhile is
+// left alone.
+func PruneEmptyDirs(base string) ([]string, error) {
+	empty, err := FindEmptyDirs(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for _, dir := range empty {
+		// os.Remove refuses non-empty directories, so a worktree created
+		// after the scan is never touched
+		if err := os.Remove(dir); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			if entries, readErr := os.ReadDir(dir); readErr == nil && len(entries) > 0 {
+				continue
+			}
+			return pruned, fmt.Errorf("failed to remove empty directory %s: %w", dir, err)
+		}
+		pruned = append(pruned, dir)
+		trace.StateChange("remove empty directory", dir, "")
+	}
+	return pruned, nil
+}
+
+// checkPruneBase refuses bases whose empty subdirectories are likely there
+// on purpose: the filesystem root and the home directory
+func checkPruneBase(base string) error {
+	if base == "" {
+		return fmt.Errorf("no directory to prune beneath")
+	}
+	cleaned := filepath.Clean(base)
+	homeDir, _ := os.UserHomeDir()
+	if cleaned == string(filepath.Separator) || (homeDir != "" && cleaned == filepath.Clean(homeDir)) {
+		return fmt.Errorf("refusing to prune empty directories beneath %s", base)
+	}
+	return nil
+}
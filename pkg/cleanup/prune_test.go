@@ -0,0 +1,64 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneEmptyDirs(t *testing.T) {
+	base := t.TempDir()
+	mkdir := func(parts ...string) string {
+		path := filepath.Join(append([]string{base}, parts...)...)
+		require.NoError(t, os.MkdirAll(path, 0755))
+		return path
+	}
+
+	emptyRepo := mkdir("old-repo")
+	nestedEmpty := mkdir("gone-repo", "leftover")
+	worktree := mkdir("live-repo", "issue-github-1")
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: /x\n"), 0644))
+	emptyInsideWorktree := mkdir("live-repo", "issue-github-1", "build")
+	withFile := mkdir("notes")
+	require.NoError(t, os.WriteFile(filepath.Join(withFile, "todo.txt"), nil, 0644))
+
+	empty, err := FindEmptyDirs(base)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{emptyRepo, nestedEmpty, filepath.Dir(nestedEmpty)}, empty)
+	assert.Equal(t, nestedEmpty, empty[indexOf(empty, filepath.Dir(nestedEmpty))-1], "deepest directories come first")
+
+	pruned, err := PruneEmptyDirs(base)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, empty, pruned)
+	assert.NoDirExists(t, emptyRepo)
+	assert.NoDirExists(t, filepath.Dir(nestedEmpty))
+	assert.DirExists(t, base, "the base itself is kept")
+	assert.DirExists(t, emptyInsideWorktree, "worktrees aren't descended into")
+	assert.DirExists(t, withFile)
+}
+
+func TestFindEmptyDirs_Bases(t *testing.T) {
+	empty, err := FindEmptyDirs(filepath.Join(t.TempDir(), "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = FindEmptyDirs("/")
+	assert.ErrorContains(t, err, "refusing to prune")
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	_, err = FindEmptyDirs(home)
+	assert.ErrorContains(t, err, "refusing to prune")
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,100 @@
+package cleanup
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResourceMask selects which kinds of session resources a cleanup touches.
+// The zero mask places no restriction.
+type ResourceMask uint8
+
+const (
+	ResourceTmux ResourceMask = 1 << iota
+	ResourceSandbox
+	ResourceWorktree
+	ResourceBranch
+)
+
+// resourceNames maps --only values to resources, in display order
+var resourceNames = []struct {
+	name     string
+	resource ResourceMask
+}{
+	{"tmux", ResourceTmux},
+	{"sandbox", ResourceSandbox},
+	{"worktree", ResourceWorktree},
+	{"branch", ResourceBranch},
+}
+
+// ResourceNames returns the accepted resource names
+func ResourceNames() []string {
+	names := make([]string, 0, len(resourceNames))
+	for _, entry := range resourceNames {
+		names = append(names, entry.name)
+	}
+	return names
+}
+
+// ParseResourceMask builds a mask from resource names such as those given to
+// --only. Each value may itself be a comma-separated list.
+func ParseResourceMask(values []string) (ResourceMask, error) {
+	var mask ResourceMask
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			resource, ok := lookupResource(name)
+			if !ok {
+				return 0, fmt.Errorf("unknown resource %q (valid: %s)", name, strings.Join(ResourceNames(), ", "))
+			}
+			mask |= resource
+		}
+	}
+	return mask, nil
+}
+
+func lookupResource(name string) (ResourceMask, bool) {
+	for _, entry := range resourceNames {
+		if entry.name == name {
+			return entry.resource, true
+		}
+	}
+	return 0, false
+}
+
+// Includes reports whether the mask allows the given resource. The zero mask
+// allows everything.
+func (m ResourceMask) Includes(resource ResourceMask) bool {
+	return m == 0 || m&resource != 0
+}
+
+// String renders the mask as a comma-separated list of resource names
+func (m ResourceMask) String() string {
+	if m == 0 {
+		return "all"
+	}
+	var names []string
+	for _, entry := range resourceNames {
+		if m&entry.resource != 0 {
+			names = append(names, entry.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// WithOnly restricts the options to the resources in mask, enabling cleanup of
+// exactly those resources. A zero mask leaves the options unchanged.
+func (o CleanupOptions) WithOnly(mask ResourceMask) CleanupOptions {
+	if mask == 0 {
+		return o
+	}
+	o.Only = mask
+	o.CleanTmux = mask&ResourceTmux != 0
+	o.CleanSandboxes = mask&ResourceSandbox != 0
+	o.CleanWorktrees = mask&ResourceWorktree != 0
+	o.CleanBranches = mask&ResourceBranch != 0
+	return o
+}
@@ -0,0 +1,116 @@
+package cleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestParseResourceMask(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []string
+		expected    ResourceMask
+		expectError bool
+	}{
+		{name: "empty", values: nil, expected: 0},
+		{name: "single", values: []string{"tmux"}, expected: ResourceTmux},
+		{name: "repeated", values: []string{"tmux", "sandbox"}, expected: ResourceTmux | ResourceSandbox},
+		{name: "comma_separated", values: []string{"worktree, Branch"}, expected: ResourceWorktree | ResourceBranch},
+		{name: "duplicates", values: []string{"tmux", "tmux"}, expected: ResourceTmux},
+		{name: "unknown", values: []string{"tmux", "volume"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := ParseResourceMask(tt.values)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "tmux, sandbox, worktree, branch")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mask)
+		})
+	}
+}
+
+func TestResourceMask_IncludesAndString(t *testing.T) {
+	var all ResourceMask
+	assert.True(t, all.Includes(ResourceBranch))
+	assert.Equal(t, "all", all.String())
+
+	mask := ResourceSandbox | ResourceTmux
+	assert.True(t, mask.Includes(ResourceTmux))
+	assert.False(t, mask.Includes(ResourceWorktree))
+	assert.Equal(t, "tmux,sandbox", mask.String())
+}
+
+func TestCleanupOptions_WithOnly(t *testing.T) {
+	manager := NewCleanupManager(nil, nil, nil, nil)
+	defaults := manager.BuildCLICleanupOptions(false, true, CleanupModeDefault)
+
+	assert.Equal(t, defaults, defaults.WithOnly(0), "zero mask leaves options unchanged")
+
+	options := defaults.WithOnly(ResourceTmux)
+	assert.True(t, options.CleanTmux)
+	assert.False(t, options.CleanSandboxes)
+	assert.False(t, options.CleanWorktrees)
+	assert.False(t, options.CleanBranches)
+	assert.Equal(t, ResourceTmux, options.Only)
+	assert.True(t, options.Force, "other options are preserved")
+}
+
+func TestCleanupManager_CleanupSessionsWithOnly(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", TmuxSession: "sbs-1", SandboxName: "sbs-repo-1", WorktreePath: "/worktrees/issue-1"},
+	}
+
+	t.Run("tmux_only_keeps_sandbox_and_worktree", func(t *testing.T) {
+		tmuxManager := &MockTmuxManager{sessions: []string{"sbs-1"}}
+		sandboxManager := &MockSandboxManager{sandboxes: map[string]bool{"sbs-repo-1": true}}
+		gitManager := &MockGitManager{worktrees: map[string]bool{"/worktrees/issue-1": true}}
+		manager := NewCleanupManager(tmuxManager, sandboxManager, gitManager, nil)
+
+		options := manager.BuildCLICleanupOptions(false, true, CleanupModeDefault).WithOnly(ResourceTmux)
+		results, err := manager.CleanupSessions(context.Background(), sessions, options)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, results.CleanedTmux)
+		assert.Equal(t, 0, results.CleanedSandboxes)
+		assert.Equal(t, 0, results.CleanedWorktrees)
+		assert.Equal(t, 1, results.CleanedSessions)
+	})
+
+	t.Run("sandbox_only", func(t *testing.T) {
+		tmuxManager := &MockTmuxManager{sessions: []string{"sbs-1"}}
+		sandboxManager := &MockSandboxManager{sandboxes: map[string]bool{"sbs-repo-1": true}}
+		gitManager := &MockGitManager{worktrees: map[string]bool{"/worktrees/issue-1": true}}
+		manager := NewCleanupManager(tmuxManager, sandboxManager, gitManager, nil)
+
+		options := manager.BuildCLICleanupOptions(false, true, CleanupModeDefault).WithOnly(ResourceSandbox)
+		results, err := manager.CleanupSessions(context.Background(), sessions, options)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, results.CleanedTmux)
+		assert.Equal(t, 1, results.CleanedSandboxes)
+		assert.Equal(t, 0, results.CleanedWorktrees)
+	})
+
+	t.Run("dry_run_lists_only_selected_resources", func(t *testing.T) {
+		manager := NewCleanupManager(&MockTmuxManager{}, &MockSandboxManager{}, nil, nil)
+
+		options := manager.BuildCLICleanupOptions(true, true, CleanupModeDefault).WithOnly(ResourceTmux)
+		results, err := manager.CleanupSessions(context.Background(), sessions, options)
+		require.NoError(t, err)
+
+		require.Len(t, results.Details, 1)
+		assert.Contains(t, results.Details[0], "Tmux Session: sbs-1")
+		assert.NotContains(t, results.Details[0], "Worktree:")
+		assert.NotContains(t, results.Details[0], "Sandbox:")
+	})
+}
@@ -0,0 +1,151 @@
+package cleanup
+
+import (
+	"fmt"
+	"strings"
+
+	"sbs/pkg/trace"
+)
+
+// Outcome describes what happened to a single resource during cleanup
+type Outcome string
+
+const (
+	OutcomeRemoved   Outcome = "removed"
+	OutcomeMissing   Outcome = "missing" // already gone before cleanup ran
+	OutcomeFailed    Outcome = "failed"
+	OutcomePlanned   Outcome = "planned"   // dry run: would be removed
+	OutcomeBusy      Outcome = "busy"      // skipped: another sbs process is working on the session
+	OutcomeProtected Outcome = "protected" // kept: matches protected_worktrees
+)
+
+// Action records the outcome of cleaning one resource of one session
+type Action struct {
+	SessionID string
+	Title     string
+	Resource  ResourceMask
+	Target    string // tmux session, sandbox name or worktree path
+	Outcome   Outcome
+	Err       error // set when Outcome is OutcomeFailed, OutcomeBusy or OutcomeProtected
+}
+
+// String renders the action the way the CLI reports it
+func (a Action) String() string {
+	switch a.Outcome {
+	case OutcomeRemoved:
+		if a.Resource == ResourceTmux {
+			return fmt.Sprintf("Killed tmux session: %s", a.Target)
+		}
+		return fmt.Sprintf("Removed %s: %s", resourceLabel(a.Resource), a.Target)
+	case OutcomeMissing:
+		return fmt.Sprintf("%s already gone: %s", capitalize(resourceLabel(a.Resource)), a.Target)
+	case OutcomeFailed:
+		return fmt.Sprintf("Warning: %v", a.Err)
+	case OutcomeBusy:
+		return fmt.Sprintf("Skipped %s: %v", a.SessionID, a.Err)
+	case OutcomeProtected:
+		return fmt.Sprintf("Kept %v", a.Err)
+	default:
+		return fmt.Sprintf("Would remove %s: %s", resourceLabel(a.Resource), a.Target)
+	}
+}
+
+// ResourceError is returned for resources that could not be checked or removed
+type ResourceError struct {
+	SessionID string
+	Resource  ResourceMask
+	Target    string
+	Op        string // "check" or "remove"
+	Err       error
+}
+
+func (e *ResourceError) Error() string {
+	if e.Op == "check" {
+		return fmt.Sprintf("could not check %s %s: %v", resourceLabel(e.Resource), e.Target, e.Err)
+	}
+	if e.Resource == ResourceTmux {
+		return fmt.Sprintf("failed to kill tmux session %s: %v", e.Target, e.Err)
+	}
+	return fmt.Sprintf("failed to delete %s %s: %v", resourceLabel(e.Resource), e.Target, e.Err)
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// Removed returns the IDs of sessions that had at least one resource removed,
+// in the order they were cleaned
+func (r CleanupResults) Removed() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, action := range r.Actions {
+		if action.Outcome == OutcomeRemoved && !seen[action.SessionID] {
+			seen[action.SessionID] = true
+			ids = append(ids, action.SessionID)
+		}
+	}
+	return ids
+}
+
+// Busy returns the IDs of sessions skipped because another sbs process was
+// working on them
+func (r CleanupResults) Busy() []string {
+	var ids []string
+	for _, action := range r.Actions {
+		if action.Outcome == OutcomeBusy {
+			ids = append(ids, action.SessionID)
+		}
+	}
+	return ids
+}
+
+// Protected returns the IDs of sessions whose worktree was kept because it is
+// protected
+func (r CleanupResults) Protected() []string {
+	var ids []string
+	for _, action := range r.Actions {
+		if action.Outcome == OutcomeProtected {
+			ids = append(ids, action.SessionID)
+		}
+	}
+	return ids
+}
+
+// Failed returns the failed actions on resource, such as sandboxes that could
+// not be checked or deleted
+func (r CleanupResults) Failed(resource ResourceMask) []Action {
+	var failed []Action
+	for _, action := range r.Actions {
+		if action.Outcome == OutcomeFailed && action.Resource == resource {
+			failed = append(failed, action)
+		}
+	}
+	return failed
+}
+
+// record appends an action and, when verbose, its rendering to Details.
+// Failures are also collected in Errors.
+func (r *CleanupResults) record(action Action, verbose bool) {
+	r.Actions = append(r.Actions, action)
+	trace.StateChange("cleanup "+action.Resource.String(), action.Target, action.String())
+	if action.Outcome == OutcomeFailed {
+		r.Errors = append(r.Errors, action.Err)
+	}
+	if verbose {
+		r.Details = append(r.Details, action.String())
+	}
+}
+
+func resourceLabel(resource ResourceMask) string {
+	if resource == ResourceTmux {
+		return "tmux session"
+	}
+	return resource.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
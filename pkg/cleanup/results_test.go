@@ -0,0 +1,98 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestCleanupSessions_Actions(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "1", TmuxSession: "sbs-1", SandboxName: "sbs-repo-1", WorktreePath: "/worktrees/issue-1"},
+		{NamespacedID: "2", TmuxSession: "sbs-2", SandboxName: "sbs-repo-2"},
+	}
+	tmuxManager := &MockTmuxManager{sessions: []string{"sbs-1"}}
+	sandboxManager := &MockSandboxManager{sandboxes: map[string]bool{"sbs-repo-1": true}}
+	gitManager := &MockGitManager{worktrees: map[string]bool{}}
+	manager := NewCleanupManager(tmuxManager, sandboxManager, gitManager, nil)
+
+	results, err := manager.CleanupSessions(context.Background(), sessions, NewCleanupOptions(
+		WithResources(ResourceTmux|ResourceSandbox|ResourceWorktree),
+	))
+	require.NoError(t, err)
+
+	require.Len(t, results.Actions, 5)
+	assert.Equal(t, Action{SessionID: "1", Resource: ResourceTmux, Target: "sbs-1", Outcome: OutcomeRemoved}, results.Actions[0])
+	assert.Equal(t, OutcomeMissing, results.Actions[1].Outcome)
+	assert.Equal(t, "Worktree already gone: /worktrees/issue-1", results.Actions[1].String())
+	assert.Equal(t, "Removed sandbox: sbs-repo-1", results.Actions[2].String())
+	assert.Equal(t, "Tmux session already gone: sbs-2", results.Actions[3].String())
+	assert.Equal(t, OutcomeMissing, results.Actions[4].Outcome)
+	assert.Equal(t, []string{"1"}, results.Removed())
+	assert.Empty(t, results.Details, "details are only rendered when verbose")
+}
+
+func TestCleanupSessions_FailuresAreTyped(t *testing.T) {
+	sessions := []config.SessionMetadata{{NamespacedID: "1", SandboxName: "sbs-repo-1"}}
+	manager := NewCleanupManager(nil, &MockSandboxManager{error: errors.New("daemon down")}, nil, nil)
+
+	results, err := manager.CleanupSessions(context.Background(), sessions, NewCleanupOptions(WithResources(ResourceSandbox)))
+	require.NoError(t, err)
+
+	require.Len(t, results.Errors, 1)
+	var resourceErr *ResourceError
+	require.ErrorAs(t, results.Errors[0], &resourceErr)
+	assert.Equal(t, ResourceSandbox, resourceErr.Resource)
+	assert.Equal(t, "check", resourceErr.Op)
+	assert.EqualError(t, resourceErr, "could not check sandbox sbs-repo-1: daemon down")
+	assert.Equal(t, OutcomeFailed, results.Actions[0].Outcome)
+	assert.Equal(t, "Warning: could not check sandbox sbs-repo-1: daemon down", results.Actions[0].String())
+}
+
+func TestCleanupSessions_RepositoryFilter(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "1", RepositoryRoot: "/a"},
+		{NamespacedID: "2", RepositoryRoot: "/b"},
+	}
+	manager := NewCleanupManager(nil, nil, nil, nil)
+
+	results, err := manager.CleanupSessions(context.Background(), sessions, NewCleanupOptions(WithDryRun(true), WithRepositoryFilter("/b")))
+	require.NoError(t, err)
+	assert.Equal(t, 1, results.WouldClean)
+	require.Len(t, results.Details, 1)
+	assert.Contains(t, results.Details[0], "Work Item 2")
+}
+
+func TestCleanupSessions_Cancelled(t *testing.T) {
+	sessions := []config.SessionMetadata{{NamespacedID: "1", SandboxName: "sbs-repo-1"}}
+	sandboxManager := &MockSandboxManager{sandboxes: map[string]bool{"sbs-repo-1": true}}
+	manager := NewCleanupManager(&MockTmuxManager{}, sandboxManager, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := manager.CleanupSessions(ctx, sessions, NewCleanupOptions(WithResources(ResourceSandbox)))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results.Actions)
+
+	_, err = manager.IdentifyStaleSessionsInView(ctx, sessions, ViewModeGlobal)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCleanupResults_Failed(t *testing.T) {
+	results := CleanupResults{Actions: []Action{
+		{SessionID: "github:1", Resource: ResourceSandbox, Outcome: OutcomeFailed, Err: errors.New("list failed")},
+		{SessionID: "github:2", Resource: ResourceSandbox, Outcome: OutcomeRemoved},
+		{SessionID: "github:3", Resource: ResourceTmux, Outcome: OutcomeFailed},
+	}}
+
+	failed := results.Failed(ResourceSandbox)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "github:1", failed[0].SessionID)
+	assert.Empty(t, CleanupResults{}.Failed(ResourceSandbox))
+}
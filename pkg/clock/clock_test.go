@@ -0,0 +1,27 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := System.Now()
+	assert.False(t, now.Before(before))
+}
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	assert.Equal(t, start, fake.Now())
+	assert.Equal(t, start, fake.Now(), "a fake doesn't move by itself")
+
+	fake.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), fake.Now())
+
+	fake.Set(start)
+	assert.Equal(t, start, fake.Now())
+}
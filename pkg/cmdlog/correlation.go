@@ -0,0 +1,39 @@
+package cmdlog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+// correlationKey is the context key for the correlation ID
+type correlationKey struct{}
+
+// fallbackCounter numbers correlation IDs if the random source fails
+var fallbackCounter atomic.Uint64
+
+// NewCorrelationID returns a short random ID grouping the commands of one operation
+func NewCorrelationID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("op%06d", fallbackCounter.Add(1))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// WithCorrelationID returns a copy of ctx whose logged commands carry id,
+// replacing any ID ctx already had
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or "" if none
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
@@ -1,12 +1,14 @@
 package cmdlog
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sbs/pkg/paths"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +21,12 @@ type Logger interface {
 	GetLevel() string
 }
 
+// ContextLogger is implemented by loggers that record the correlation ID
+// carried by a context
+type ContextLogger interface {
+	LogCommandContext(ctx context.Context, command string, args []string, caller string) CommandContext
+}
+
 // CommandContext represents an active command logging context
 type CommandContext interface {
 	LogCompletion(success bool, exitCode int, errorMsg string, duration time.Duration)
@@ -41,11 +49,12 @@ type commandLogger struct {
 
 // commandContext implements the CommandContext interface
 type commandContext struct {
-	logger    *commandLogger
-	command   string
-	args      []string
-	caller    string
-	startTime time.Time
+	logger        *commandLogger
+	command       string
+	args          []string
+	caller        string
+	correlationID string
+	startTime     time.Time
 }
 
 // LogLevel represents different logging levels
@@ -99,11 +108,11 @@ func NewCommandLogger(config Config) Logger {
 		output = config.Output
 	} else if config.FilePath != "" {
 		// Create log file and directories if needed
-		if err := os.MkdirAll(filepath.Dir(config.FilePath), 0755); err != nil {
+		if err := os.MkdirAll(filepath.Dir(config.FilePath), paths.PrivateDirMode); err != nil {
 			// Fall back to stderr if file creation fails
 			output = os.Stderr
 		} else {
-			file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, paths.PrivateFileMode)
 			if err != nil {
 				// Fall back to stderr if file opening fails
 				output = os.Stderr
@@ -120,8 +129,12 @@ func NewCommandLogger(config Config) Logger {
 	return logger
 }
 
-// SetGlobalLogger sets the global logger instance
+// SetGlobalLogger sets the global logger instance; nil disables logging.
+// It is safe to call while other goroutines are logging.
 func SetGlobalLogger(logger Logger) {
+	if logger == nil {
+		logger = &noOpLogger{}
+	}
 	globalMutex.Lock()
 	defer globalMutex.Unlock()
 	globalLogger = logger
@@ -144,17 +157,35 @@ func (cl *commandLogger) LogCommand(command string, args []string, caller string
 	if caller == "" {
 		caller = cl.getCaller()
 	}
+	return cl.newCommandContext(context.Background(), command, args, caller)
+}
 
-	ctx := &commandContext{
-		logger:    cl,
-		command:   command,
-		args:      make([]string, len(args)),
-		caller:    caller,
-		startTime: time.Now(),
+// LogCommandContext logs the start of a command execution, tagging it with
+// ctx's correlation ID
+func (cl *commandLogger) LogCommandContext(ctx context.Context, command string, args []string, caller string) CommandContext {
+	if !cl.config.Enabled {
+		return &noOpContext{}
 	}
-	copy(ctx.args, args)
 
-	return ctx
+	// If no caller provided, try to get it from runtime
+	if caller == "" {
+		caller = cl.getCaller()
+	}
+	return cl.newCommandContext(ctx, command, args, caller)
+}
+
+func (cl *commandLogger) newCommandContext(ctx context.Context, command string, args []string, caller string) CommandContext {
+	cc := &commandContext{
+		logger:        cl,
+		command:       command,
+		args:          make([]string, len(args)),
+		caller:        caller,
+		correlationID: CorrelationID(ctx),
+		startTime:     time.Now(),
+	}
+	copy(cc.args, args)
+
+	return cc
 }
 
 // LogCompletion logs the completion of a command execution
@@ -192,6 +223,10 @@ func (cc *commandContext) LogCompletion(success bool, exitCode int, errorMsg str
 		msgBuilder.WriteString(fmt.Sprintf(" error=%q", errorMsg))
 	}
 
+	if cc.correlationID != "" {
+		msgBuilder.WriteString(" op=" + cc.correlationID)
+	}
+
 	// Log the message
 	cc.logger.logger.Println(msgBuilder.String())
 }
@@ -257,6 +292,16 @@ func LogCommandGlobal(command string, args []string, caller string) CommandConte
 	return GetGlobalLogger().LogCommand(command, args, caller)
 }
 
+// LogCommandContext logs a command using the global logger, tagging it with
+// ctx's correlation ID when the logger supports it
+func LogCommandContext(ctx context.Context, command string, args []string, caller string) CommandContext {
+	logger := GetGlobalLogger()
+	if contextLogger, ok := logger.(ContextLogger); ok {
+		return contextLogger.LogCommandContext(ctx, command, args, caller)
+	}
+	return logger.LogCommand(command, args, caller)
+}
+
 // IsGlobalLoggingEnabled returns whether global logging is enabled
 func IsGlobalLoggingEnabled() bool {
 	return GetGlobalLogger().IsEnabled()
@@ -2,10 +2,13 @@ package cmdlog
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -339,3 +342,61 @@ func TestCreateTestCommandScenario(t *testing.T) {
 	assert.Equal(t, 0, scenario.ExitCode)
 	assert.Equal(t, "clean working tree", scenario.Output)
 }
+
+func TestCommandLogger_CorrelationID(t *testing.T) {
+	t.Run("commands_of_one_operation_share_an_id", func(t *testing.T) {
+		buf := NewTestBuffer()
+		original := GetGlobalLogger()
+		t.Cleanup(func() { SetGlobalLogger(original) })
+		SetGlobalLogger(NewCommandLogger(Config{Enabled: true, Level: "info", Output: buf}))
+
+		ctx := WithCorrelationID(context.Background(), "abc123")
+		LogCommandContext(ctx, "tmux", []string{"kill-session"}, "test:1").LogCompletion(true, 0, "", time.Millisecond)
+		LogCommandContext(ctx, "sandbox", []string{"delete"}, "test:2").LogCompletion(true, 0, "", time.Millisecond)
+		LogCommandContext(context.Background(), "git", []string{"status"}, "test:3").LogCompletion(true, 0, "", time.Millisecond)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[0], "op=abc123")
+		assert.Contains(t, lines[1], "op=abc123")
+		assert.NotContains(t, lines[2], "op=")
+	})
+
+	t.Run("new_ids_differ", func(t *testing.T) {
+		first, second := NewCorrelationID(), NewCorrelationID()
+		assert.Len(t, first, 8)
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, "", CorrelationID(context.Background()))
+	})
+
+	t.Run("mock_logger_records_id", func(t *testing.T) {
+		mock := NewMockLogger(Config{Enabled: true})
+		mock.LogCommandContext(WithCorrelationID(context.Background(), "op1"), "git", nil, "").LogCompletion(true, 0, "", 0)
+		require.Len(t, mock.Entries(), 1)
+		assert.Equal(t, "op1", mock.Entries()[0].CorrelationID)
+	})
+}
+
+func TestGlobalLogger_ConcurrentAccess(t *testing.T) {
+	original := GetGlobalLogger()
+	t.Cleanup(func() { SetGlobalLogger(original) })
+
+	buf := NewTestBuffer()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetGlobalLogger(NewCommandLogger(Config{Enabled: true, Level: "info", Output: buf}))
+		}()
+		go func(i int) {
+			defer wg.Done()
+			ctx := WithCorrelationID(context.Background(), fmt.Sprintf("op%d", i))
+			LogCommandContext(ctx, "git", []string{"status"}, "test").LogCompletion(true, 0, "", time.Millisecond)
+		}(i)
+	}
+	wg.Wait()
+
+	SetGlobalLogger(nil)
+	assert.False(t, IsGlobalLoggingEnabled(), "a nil logger disables logging")
+}
@@ -2,7 +2,9 @@ package cmdlog
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"sync"
 	"time"
 )
 
@@ -17,24 +19,27 @@ type TestScenario struct {
 	Duration time.Duration
 }
 
-// MockLogger implements the Logger interface for testing
+// MockLogger implements the Logger interface for testing. It is safe for
+// concurrent use; read LogEntries with Entries while commands may still run.
 type MockLogger struct {
 	LogEntries []LogEntry
 	Config     Config
+	mutex      sync.Mutex
 }
 
 // LogEntry represents a single log entry for testing
 type LogEntry struct {
-	Level     string
-	Command   string
-	Arguments []string
-	Caller    string
-	Success   bool
-	ExitCode  int
-	Output    string
-	Error     string
-	Duration  time.Duration
-	Timestamp time.Time
+	Level         string
+	Command       string
+	Arguments     []string
+	Caller        string
+	CorrelationID string
+	Success       bool
+	ExitCode      int
+	Output        string
+	Error         string
+	Duration      time.Duration
+	Timestamp     time.Time
 }
 
 // MockCommandContext implements the CommandContext interface for testing
@@ -51,6 +56,8 @@ func (m *MockCommandContext) LogCompletion(success bool, exitCode int, errorMsg
 	m.entry.Timestamp = time.Now()
 
 	// Add to logger's entries
+	m.logger.mutex.Lock()
+	defer m.logger.mutex.Unlock()
 	m.logger.LogEntries = append(m.logger.LogEntries, m.entry)
 }
 
@@ -63,11 +70,16 @@ func NewMockLogger(config Config) *MockLogger {
 }
 
 func (m *MockLogger) LogCommand(command string, args []string, caller string) CommandContext {
+	return m.LogCommandContext(context.Background(), command, args, caller)
+}
+
+func (m *MockLogger) LogCommandContext(ctx context.Context, command string, args []string, caller string) CommandContext {
 	entry := LogEntry{
-		Level:     m.Config.Level,
-		Command:   command,
-		Arguments: make([]string, len(args)),
-		Caller:    caller,
+		Level:         m.Config.Level,
+		Command:       command,
+		Arguments:     make([]string, len(args)),
+		Caller:        caller,
+		CorrelationID: CorrelationID(ctx),
 	}
 	copy(entry.Arguments, args)
 
@@ -77,6 +89,13 @@ func (m *MockLogger) LogCommand(command string, args []string, caller string) Co
 	}
 }
 
+// Entries returns a copy of the completed log entries
+func (m *MockLogger) Entries() []LogEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]LogEntry(nil), m.LogEntries...)
+}
+
 func (m *MockLogger) IsEnabled() bool {
 	return m.Config.Enabled
 }
@@ -112,7 +131,8 @@ func CreateTestCommandScenarioWithError(name, command string, args []string, exi
 
 // TestBuffer provides a thread-safe buffer for testing logging output
 type TestBuffer struct {
-	buf *bytes.Buffer
+	buf   *bytes.Buffer
+	mutex sync.Mutex
 }
 
 func NewTestBuffer() *TestBuffer {
@@ -122,14 +142,20 @@ func NewTestBuffer() *TestBuffer {
 }
 
 func (tb *TestBuffer) Write(p []byte) (n int, err error) {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
 	return tb.buf.Write(p)
 }
 
 func (tb *TestBuffer) String() string {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
 	return tb.buf.String()
 }
 
 func (tb *TestBuffer) Reset() {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
 	tb.buf.Reset()
 }
 
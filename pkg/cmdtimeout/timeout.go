@@ -0,0 +1,146 @@
+package cmdtimeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is applied to external tools when no timeout is configured:
+// none, since git worktree add, fetch or a sandbox create can take minutes on
+// a large repository and killing them midway leaves a half-made worktree
+const DefaultTimeout time.Duration = 0
+
+// DefaultRequestTimeout bounds the network requests in DefaultPerTool, so an
+// unreachable tracker or webhook can't hang a command
+const DefaultRequestTimeout = 60 * time.Second
+
+// DefaultPerTool are the tools bounded when nothing is configured
+var DefaultPerTool = map[string]time.Duration{
+	"github": DefaultRequestTimeout,
+	"jira":   DefaultRequestTimeout,
+	"notify": DefaultRequestTimeout,
+
+	// Snapshots are archived while the archive is locked
+	"loghook_archive": DefaultRequestTimeout,
+}
+
+// WaitDelay bounds how long a killed command may hold its output pipes open,
+// e.g. when a wrapper script leaves child processes behind
+const WaitDelay = time.Second
+
+// Config holds the timeouts applied to external tool invocations
+type Config struct {
+	Default time.Duration            // Timeout for tools without a specific entry (0 disables)
+	PerTool map[string]time.Duration // Tool-specific timeouts keyed by binary name (0 disables)
+}
+
+// TimeoutError is returned when an external command exceeds its timeout
+type TimeoutError struct {
+	Tool    string
+	Args    []string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	command := e.Tool
+	if len(e.Args) > 0 {
+		command = e.Tool + " " + strings.Join(e.Args, " ")
+	}
+	return fmt.Sprintf("%s timed out after %s (raise command_timeout_seconds or command_timeouts, or set -1 for no timeout)", command, e.Timeout)
+}
+
+// Global timeout configuration
+var globalConfig = FromSeconds(0, nil)
+var globalMutex sync.RWMutex
+
+// SetGlobalConfig sets the timeout configuration used by all managers
+func SetGlobalConfig(config Config) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	globalConfig = config
+}
+
+// GetGlobalConfig returns the current timeout configuration
+func GetGlobalConfig() Config {
+	globalMutex.RLock()
+	defer globalMutex.RUnlock()
+	return globalConfig
+}
+
+// For returns the timeout configured for the given tool
+func For(tool string) time.Duration {
+	config := GetGlobalConfig()
+	if timeout, exists := config.PerTool[tool]; exists {
+		return timeout
+	}
+	return config.Default
+}
+
+// Context returns a context bounded by the timeout configured for the given tool.
+// A zero timeout yields a context without a deadline.
+func Context(tool string) (context.Context, context.CancelFunc, time.Duration) {
+	return ContextFrom(context.Background(), tool)
+}
+
+// ContextFrom is like Context but derives from parent, so cancelling parent
+// also stops the command. A nil parent is treated as context.Background().
+func ContextFrom(parent context.Context, tool string) (context.Context, context.CancelFunc, time.Duration) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	timeout := For(tool)
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(parent)
+		return ctx, cancel, 0
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return ctx, cancel, timeout
+}
+
+// Check maps an error from a command run under ctx to a TimeoutError when the deadline was hit
+func Check(ctx context.Context, tool string, args []string, timeout time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &TimeoutError{Tool: tool, Args: args, Timeout: timeout}
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%s cancelled: %w", tool, context.Canceled)
+	}
+	return err
+}
+
+// IsTimeout reports whether err is, or wraps, a TimeoutError
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// FromSeconds builds a Config from second-based settings as stored in the
+// config file. Without a default (0), DefaultPerTool applies; a default
+// bounds every tool without its own entry, and -1 bounds none.
+func FromSeconds(defaultSecs int, perToolSecs map[string]int) Config {
+	config := Config{Default: DefaultTimeout, PerTool: make(map[string]time.Duration)}
+	switch {
+	case defaultSecs > 0:
+		config.Default = time.Duration(defaultSecs) * time.Second
+	case defaultSecs == 0:
+		for tool, timeout := range DefaultPerTool {
+			config.PerTool[tool] = timeout
+		}
+	}
+
+	for tool, secs := range perToolSecs {
+		if secs < 0 {
+			secs = 0
+		}
+		config.PerTool[tool] = time.Duration(secs) * time.Second
+	}
+
+	return config
+}
@@ -0,0 +1,134 @@
+package cmdtimeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withConfig(t *testing.T, config Config) {
+	t.Helper()
+	original := GetGlobalConfig()
+	SetGlobalConfig(config)
+	t.Cleanup(func() { SetGlobalConfig(original) })
+}
+
+func TestFor(t *testing.T) {
+	withConfig(t, Config{
+		Default: 30 * time.Second,
+		PerTool: map[string]time.Duration{"sandbox": 2 * time.Minute, "tmux": 0},
+	})
+
+	tests := []struct {
+		tool     string
+		expected time.Duration
+	}{
+		{"sandbox", 2 * time.Minute},
+		{"tmux", 0},
+		{"git", 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			assert.Equal(t, tt.expected, For(tt.tool))
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	t.Run("with_timeout", func(t *testing.T) {
+		withConfig(t, Config{Default: time.Second})
+
+		ctx, cancel, timeout := Context("git")
+		defer cancel()
+
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		assert.Equal(t, time.Second, timeout)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		withConfig(t, Config{})
+
+		ctx, cancel, timeout := Context("git")
+		defer cancel()
+
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		assert.Equal(t, time.Duration(0), timeout)
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("nil_error", func(t *testing.T) {
+		assert.NoError(t, Check(context.Background(), "git", nil, time.Second, nil))
+	})
+
+	t.Run("non_timeout_error_passes_through", func(t *testing.T) {
+		original := errors.New("exit status 1")
+		err := Check(context.Background(), "git", []string{"status"}, time.Second, original)
+		assert.Equal(t, original, err)
+		assert.False(t, IsTimeout(err))
+	})
+
+	t.Run("deadline_exceeded", func(t *testing.T) {
+		if _, err := exec.LookPath("sleep"); err != nil {
+			t.Skip("sleep not available")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		runErr := exec.CommandContext(ctx, "sleep", "5").Run()
+		err := Check(ctx, "sleep", []string{"5"}, 50*time.Millisecond, runErr)
+
+		require.Error(t, err)
+		assert.True(t, IsTimeout(err))
+		assert.Equal(t, "sleep 5 timed out after 50ms (raise command_timeout_seconds or command_timeouts, or set -1 for no timeout)", err.Error())
+	})
+
+	t.Run("parent_cancelled", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel, timeout := ContextFrom(parent, "git")
+		defer cancel()
+		cancelParent()
+
+		err := Check(ctx, "git", []string{"fetch"}, timeout, errors.New("signal: killed"))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, IsTimeout(err))
+	})
+
+	t.Run("wrapped_timeout_detected", func(t *testing.T) {
+		err := fmt.Errorf("failed to list sandboxes: %w", &TimeoutError{Tool: "sandbox", Timeout: time.Second})
+		assert.True(t, IsTimeout(err))
+	})
+}
+
+func TestFromSeconds(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		config := FromSeconds(0, nil)
+		assert.Equal(t, time.Duration(0), config.Default, "git, tmux and sandbox have no deadline unless configured")
+		assert.Equal(t, DefaultPerTool, config.PerTool)
+	})
+
+	t.Run("custom_values", func(t *testing.T) {
+		config := FromSeconds(10, map[string]int{"git": 120, "tmux": -1})
+		assert.Equal(t, 10*time.Second, config.Default)
+		assert.Equal(t, 120*time.Second, config.PerTool["git"])
+		assert.Equal(t, time.Duration(0), config.PerTool["tmux"])
+		_, bounded := config.PerTool["github"]
+		assert.False(t, bounded, "a configured default applies to requests too")
+	})
+
+	t.Run("negative_default_disables", func(t *testing.T) {
+		config := FromSeconds(-1, map[string]int{"git": 300})
+		assert.Equal(t, time.Duration(0), config.Default)
+		assert.Equal(t, map[string]time.Duration{"git": 300 * time.Second}, config.PerTool, "only explicit entries remain")
+	})
+}
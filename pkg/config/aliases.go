@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sbs/pkg/paths"
+)
+
+// AliasesFile holds work item aliases in the state directory, keyed by
+// repository root. Aliases are personal, so they stay out of the repository.
+const AliasesFile = "aliases.json"
+
+// AliasesPath returns the path of the aliases file
+func AliasesPath() (string, error) {
+	return paths.StatePath(AliasesFile)
+}
+
+// LegacyAliasesPath returns the per-repository aliases file earlier versions
+// wrote. It is still read until the repository's aliases are saved again.
+func LegacyAliasesPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".sbs", "aliases.json")
+}
+
+// LoadAliases reads a repository's aliases, mapping alias names to namespaced
+// work item IDs. A missing file means no aliases.
+func LoadAliases(repoRoot string) (map[string]string, error) {
+	all, err := loadAllAliases()
+	if err != nil {
+		return nil, err
+	}
+	if aliases, ok := all[repoRoot]; ok {
+		return aliases, nil
+	}
+	return loadAliasesFile(LegacyAliasesPath(repoRoot))
+}
+
+// SaveAliases writes a repository's aliases
+func SaveAliases(repoRoot string, aliases map[string]string) error {
+	path, err := AliasesPath()
+	if err != nil {
+		return err
+	}
+	all, err := loadAllAliases()
+	if err != nil {
+		return err
+	}
+	all[repoRoot] = aliases
+
+	if err := os.MkdirAll(filepath.Dir(path), paths.PrivateDirMode); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'), paths.PrivateFileMode)
+}
+
+// loadAllAliases reads the aliases of every repository
+func loadAllAliases() (map[string]map[string]string, error) {
+	path, err := AliasesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	all := map[string]map[string]string{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return all, nil
+}
+
+// loadAliasesFile reads a single repository's aliases file
+func loadAliasesFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := map[string]string{}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return aliases, nil
+}
+
+// ValidateAliasName checks that an alias can't be mistaken for a work item ID
+// or a short session index
+func ValidateAliasName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("alias name cannot be empty")
+	case strings.ContainsAny(name, ": \t"):
+		return fmt.Errorf("alias %q cannot contain colons or spaces", name)
+	case strings.HasPrefix(name, "%"):
+		return fmt.Errorf("alias %q cannot start with %% (reserved for short session indexes)", name)
+	}
+	return nil
+}
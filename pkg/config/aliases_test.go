@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases_SaveAndLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	repoRoot := t.TempDir()
+	otherRoot := t.TempDir()
+
+	aliases, err := LoadAliases(repoRoot)
+	require.NoError(t, err)
+	assert.Empty(t, aliases, "a missing file means no aliases")
+
+	require.NoError(t, SaveAliases(repoRoot, map[string]string{"fix1": "github:1234"}))
+	require.NoError(t, SaveAliases(otherRoot, map[string]string{"docs": "test:docs"}))
+	aliases, err = LoadAliases(repoRoot)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fix1": "github:1234"}, aliases)
+	aliases, err = LoadAliases(otherRoot)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"docs": "test:docs"}, aliases)
+
+	// Aliases are private state, not files in the repository
+	path, err := AliasesPath()
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.NoDirExists(t, filepath.Join(repoRoot, ".sbs"))
+
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0600))
+	_, err = LoadAliases(repoRoot)
+	assert.Error(t, err)
+}
+
+func TestAliases_LegacyRepositoryFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	repoRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, ".sbs"), 0755))
+	require.NoError(t, os.WriteFile(LegacyAliasesPath(repoRoot), []byte(`{"fix1": "github:1234"}`), 0644))
+
+	aliases, err := LoadAliases(repoRoot)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fix1": "github:1234"}, aliases, "aliases from .sbs/aliases.json are still read")
+
+	// Once saved, the state file wins, so removed aliases stay removed
+	require.NoError(t, SaveAliases(repoRoot, map[string]string{}))
+	aliases, err = LoadAliases(repoRoot)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+}
+
+func TestValidateAliasName(t *testing.T) {
+	assert.NoError(t, ValidateAliasName("fix1"))
+	assert.Error(t, ValidateAliasName(""))
+	assert.Error(t, ValidateAliasName("github:1"))
+	assert.Error(t, ValidateAliasName("my alias"))
+	assert.Error(t, ValidateAliasName("%3"))
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"sbs/pkg/clock"
+)
+
+var (
+	currentClock clock.Clock = clock.System
+	clockMutex   sync.RWMutex
+)
+
+// SetClock sets the clock session timestamps, ages and expiry are measured
+// with; nil restores the system clock
+func SetClock(c clock.Clock) {
+	clockMutex.Lock()
+	defer clockMutex.Unlock()
+	if c == nil {
+		c = clock.System
+	}
+	currentClock = c
+}
+
+// Clock returns the clock set with SetClock
+func Clock() clock.Clock {
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+	return currentClock
+}
+
+// Now returns the current time from the clock set with SetClock
+func Now() time.Time {
+	return Clock().Now()
+}
+
+// Timestamp returns the current time formatted for session metadata
+func Timestamp() string {
+	return Now().Format(time.RFC3339)
+}
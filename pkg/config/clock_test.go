@@ -0,0 +1,31 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+)
+
+func TestSetClock(t *testing.T) {
+	fixed := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	SetClock(clock.NewFake(fixed))
+	defer SetClock(nil)
+
+	assert.Equal(t, fixed, Now())
+	assert.Equal(t, "2026-03-04T12:00:00Z", Timestamp())
+
+	archivePath := filepath.Join(t.TempDir(), "sessions-archive.json")
+	require.NoError(t, ArchiveSessions(archivePath, []SessionMetadata{{NamespacedID: "test:1"}}, ArchiveReasonCleaned))
+	archived, err := LoadArchivedSessions(archivePath)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "2026-03-04T12:00:00Z", archived[0].ArchivedAt)
+
+	SetClock(nil)
+	assert.Equal(t, clock.System, Clock())
+}
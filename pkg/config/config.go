@@ -1,12 +1,28 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
+
+	"sbs/pkg/branchname"
+	"sbs/pkg/messages"
+	"sbs/pkg/naming"
+	"sbs/pkg/oplock"
+	"sbs/pkg/paths"
+	"sbs/pkg/trace"
 )
 
 type Config struct {
@@ -30,7 +46,272 @@ type Config struct {
 	StatusTimeoutSeconds      int  `json:"status_timeout_seconds,omitempty"`          // Timeout for status operations (default: 5)
 
 	// Log display configuration
-	LogRefreshIntervalSecs int `json:"log_refresh_interval_seconds,omitempty"` // Log refresh interval in seconds (default: 5)
+	LogRefreshIntervalSecs int                `json:"log_refresh_interval_seconds,omitempty"` // Log refresh interval in seconds (default: 5)
+	LogHighlightRules      []LogHighlightRule `json:"log_highlight_rules,omitempty"`          // Extra regex-to-style rules applied in the log view
+	LoghookArgs            []string           `json:"loghook_args,omitempty"`                 // Extra arguments passed to .sbs/loghook after the mode
+	LoghookIntervals       map[string]int     `json:"loghook_intervals_seconds,omitempty"`    // Refresh interval per named loghook in .sbs/loghooks/
+	LoghookMinIntervalSecs int                `json:"loghook_min_interval_seconds,omitempty"` // Shortest refresh interval a worktree's .sbs/loghook.json may request (default: 2)
+	LoghookMaxIntervalSecs int                `json:"loghook_max_interval_seconds,omitempty"` // Longest refresh interval a worktree's .sbs/loghook.json may request (default: 120)
+	LoghookMaxOutputBytes  int                `json:"loghook_max_output_bytes,omitempty"`     // Largest loghook output a worktree's .sbs/loghook.json may request (default: 1MB)
+	LoghookArchive         string             `json:"loghook_archive,omitempty"`              // Keep loghook output for 'sbs log --history': "local" or "command" (default: off)
+	LoghookArchiveCommand  string             `json:"loghook_archive_command,omitempty"`      // Shell command each archived output is piped to with loghook_archive "command"
+	LoghookArchiveKeep     int                `json:"loghook_archive_keep,omitempty"`         // Archived outputs kept per session by the local store (default: 100)
+	LoghookArchiveMaxBytes int64              `json:"loghook_archive_max_bytes,omitempty"`    // Archive size per session the local store rotates down to (default: 50MB)
+
+	// Config file checking (global config only)
+	UnknownConfigKeys string `json:"unknown_config_keys,omitempty"` // Unknown keys in config files: "warn" (default), "error" or "ignore"
+
+	// Tmux integration
+	TmuxControlMode   bool     `json:"tmux_control_mode,omitempty"`  // Stream session events from a tmux control-mode client instead of relying on polling
+	AttachEnvironment []string `json:"attach_environment,omitempty"` // Variables copied from the terminal into a session when attaching (default: DISPLAY, SSH_AUTH_SOCK, SSH_CONNECTION, XAUTHORITY; "none" copies none)
+
+	// Waiting-for-input alerts
+	WaitingBell bool `json:"waiting_bell,omitempty"` // Ring the terminal bell in the TUI when a session starts waiting for input
+
+	// Session table columns
+	ShowDiffstat bool `json:"show_diffstat,omitempty"` // Show each session branch's +adds/-dels against the base branch in the TUI repository view
+
+	// Branch-behind warnings
+	BranchBehindCommits int `json:"branch_behind_commits,omitempty"` // The TUI flags a session branch missing this many commits of the base branch (default: 50, -1 disables)
+	BranchBehindDays    int `json:"branch_behind_days,omitempty"`    // ... or missing a base commit this many days old (default: 14, -1 disables)
+
+	// External command timeout configuration
+	CommandTimeoutSecs int            `json:"command_timeout_seconds,omitempty"` // Timeout for external commands without their own (default: none, except 60 for github, jira, notify and loghook_archive; -1 disables all)
+	CommandTimeouts    map[string]int `json:"command_timeouts,omitempty"`        // Per-tool timeouts in seconds, keyed by git, tmux, sandbox, github, jira, notify, stalehook or loghook_archive
+
+	// Slow command warnings
+	TimingBudgets map[string]int `json:"timing_budgets_seconds,omitempty"` // Seconds "git worktree add" or "sandbox create" may usually take before sbs doctor and the TUI suggest speeding it up (0 disables)
+
+	// Git executable
+	GitExecutable string `json:"git_executable,omitempty"` // Git binary or wrapper to run instead of "git" from PATH
+
+	// State location
+	XDGState bool `json:"xdg_state,omitempty"` // Keep sessions, history, progress and locks under $XDG_STATE_HOME/sbs (default ~/.local/state/sbs) instead of the config directory
+
+	// GitHub client
+	GitHubClient string `json:"github_client,omitempty"`  // "gh" (default) shells out to the GitHub CLI, "api" calls the REST API with github_token
+	GitHubAPIURL string `json:"github_api_url,omitempty"` // REST API root for github_client "api", e.g. "https://github.example.com/api/v3" (default: https://api.github.com)
+
+	// Branch naming
+	BranchTemplate string `json:"branch_template,omitempty"` // Template for work item branches, e.g. "issue-{source}-{id}-{title}"
+
+	// Name scope for tmux sessions and sandboxes on shared hosts
+	NameScope string `json:"name_scope,omitempty"` // "user" for the current username, or a custom prefix
+
+	// Repository groups scoping the global view
+	RepositoryGroups map[string][]string `json:"repository_groups,omitempty"` // Repositories per group, by name or path, e.g. {"frontend": ["web", "~/src/design-system"]}
+
+	// TUI appearance and key bindings (applied live when the config file changes)
+	Theme       map[string]string   `json:"theme,omitempty"`        // Colors keyed by primary, secondary, accent, warning, error, muted
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"` // Keys per TUI action, e.g. {"refresh": ["r", "f5"]}
+
+	// Work item source badges in the ID column of sbs list and the TUI
+	SourceBadges     map[string]SourceBadge `json:"source_badges,omitempty"`      // Icon and color per source, e.g. {"jira": {"icon": "J", "color": "#2684FF"}}
+	SourceBadgeStyle string                 `json:"source_badge_style,omitempty"` // "color" (default), "icon" for uncolored icons, or "none"
+
+	// User-facing wording
+	Messages map[string]string `json:"messages,omitempty"` // Message templates by ID, e.g. {"start.ready": "Ready: sbs attach {{.ID}}"}
+	Terms    map[string]string `json:"terms,omitempty"`    // Nouns used in messages, e.g. {"work_item": "ticket"}
+
+	// Worktree provisioning
+	CopyFromMain         []CopyFromMainEntry `json:"copy_from_main,omitempty"`           // Ignored files copied or symlinked from the main checkout into new worktrees
+	CopyFromMainMaxBytes int64               `json:"copy_from_main_max_bytes,omitempty"` // Largest entry that is copied rather than skipped (default: 100MB)
+
+	// Shared build caches
+	BuildCaches []BuildCacheEntry `json:"build_caches,omitempty"` // Cache directories shared by every session via environment variables or sandbox bind mounts
+
+	// Sandbox prewarming
+	SandboxPoolSize int `json:"sandbox_pool_size,omitempty"` // Generic sandboxes kept ready by 'sbs pool watch' for sbs start to claim (0 disables)
+
+	// Session readiness
+	ReadinessChecks []ReadinessCheck `json:"readiness_checks,omitempty"` // Probes sbs start waits for before declaring a session ready
+
+	// Issue bootstrap
+	IssueBootstrap bool `json:"issue_bootstrap,omitempty"` // sbs start applies the sbs fenced block in a work item's body (base branch, setup and session command)
+
+	// Cleanup protection (main and master are always protected)
+	ProtectedBranches      []string `json:"protected_branches,omitempty"`        // Branch globs, e.g. "release/*", that are never deleted
+	ProtectedWorktrees     []string `json:"protected_worktrees,omitempty"`       // Worktree paths or globs that are never removed
+	PruneEmptyWorktreeDirs bool     `json:"prune_empty_worktree_dirs,omitempty"` // sbs clean removes empty directories beneath worktree_base_path
+	SandboxRequired        bool     `json:"sandbox_required,omitempty"`          // Sandbox failures fail start, stop and clean instead of being ignored
+	SnapshotBeforeClean    bool     `json:"snapshot_before_clean,omitempty"`     // sbs clean snapshots each sandbox before deleting it, when the sandbox CLI supports snapshots
+	AutomationAllow        []string `json:"automation_allow,omitempty"`          // Destructive operations --yes may run without confirmation (unset allows all; "none" allows none)
+
+	// Session expiry
+	CleanAfterDays    int  `json:"clean_after_days,omitempty"`    // Sessions not used for this many days are stale, so sbs clean removes them (0 disables)
+	ExpiryWarningDays int  `json:"expiry_warning_days,omitempty"` // How long before expiring sbs list and the TUI flag a session (default: 2)
+	ExpiryNotify      bool `json:"expiry_notify,omitempty"`       // Post a desktop notification when a session starts to be flagged
+
+	// Pull request checks
+	PRCheckIntervalSecs  int  `json:"pr_check_interval_seconds,omitempty"` // How often sbs pr --wait polls a pull request's checks (default: 30)
+	PRCheckTimeoutMins   int  `json:"pr_check_timeout_minutes,omitempty"`  // How long sbs pr --wait waits for checks to finish (default: 60)
+	PRWaitRequiredChecks bool `json:"pr_wait_required_checks,omitempty"`   // sbs pr waits as with --wait when the base branch requires checks
+
+	// Notifications (read from the global config only)
+	Notifiers         []NotifierConfig `json:"notifiers,omitempty"`              // Webhooks, Slack webhooks and shell commands told about waiting agents, cleanups and failed starts
+	NotifyWaitingMins int              `json:"notify_waiting_minutes,omitempty"` // How long an agent waits for input before 'sbs watch' sends session-waiting (default: 30)
+
+	// Organization defaults (read from the global config only)
+	OrgConfigPath string `json:"org_config_path,omitempty"` // Shared config merged between the global config and each repository's .sbs/config.json; SBS_ORG_CONFIG overrides it
+}
+
+// DefaultPRCheckIntervalSecs is how often sbs pr --wait polls checks when
+// pr_check_interval_seconds isn't set
+const DefaultPRCheckIntervalSecs = 30
+
+// MinPRCheckIntervalSecs keeps polling within GitHub's rate limits
+const MinPRCheckIntervalSecs = 5
+
+// DefaultPRCheckTimeoutMins bounds sbs pr --wait when neither
+// pr_check_timeout_minutes nor --timeout is given
+const DefaultPRCheckTimeoutMins = 60
+
+// DefaultReadinessTimeoutSecs is how long a readiness check may take when it
+// sets no timeout
+const DefaultReadinessTimeoutSecs = 30
+
+// MaxReadinessTimeoutSecs is the longest allowed readiness check timeout
+const MaxReadinessTimeoutSecs = 600
+
+// ReadinessCheck is one probe of a freshly started session. Exactly one of
+// Command, Port or File is set.
+type ReadinessCheck struct {
+	Name        string `json:"name,omitempty"`            // Label in sbs start output (default: describes the probe)
+	Command     string `json:"command,omitempty"`         // Shell command run in the worktree until it exits 0
+	Port        int    `json:"port,omitempty"`            // TCP port accepting connections
+	Host        string `json:"host,omitempty"`            // Host of Port (default: 127.0.0.1)
+	File        string `json:"file,omitempty"`            // File that exists, relative to the worktree
+	TimeoutSecs int    `json:"timeout_seconds,omitempty"` // How long to keep probing (default: 30)
+}
+
+// DefaultCopyFromMainMaxBytes is the copy_from_main size limit when none is configured
+const DefaultCopyFromMainMaxBytes = 100 * 1024 * 1024
+
+// CopyFromMainEntry is a path, relative to the repository root, provisioned
+// into new worktrees from the main checkout. In JSON it is either a plain path
+// string or an object such as {"path": "node_modules", "symlink": true}.
+type CopyFromMainEntry struct {
+	Path    string `json:"path"`
+	Symlink bool   `json:"symlink,omitempty"` // Link to the main checkout instead of copying
+}
+
+// UnmarshalJSON accepts either a path string or an entry object
+func (e *CopyFromMainEntry) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*e = CopyFromMainEntry{Path: path}
+		return nil
+	}
+
+	type entry CopyFromMainEntry
+	var decoded entry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("copy_from_main entries must be a path or an object with a path: %w", err)
+	}
+	*e = CopyFromMainEntry(decoded)
+	return nil
+}
+
+// BuildCachePresets maps well-known cache names to the environment variable
+// that points the tool at its cache directory
+var BuildCachePresets = map[string]string{
+	"go":     "GOCACHE",
+	"gomod":  "GOMODCACHE",
+	"npm":    "npm_config_cache",
+	"ccache": "CCACHE_DIR",
+	"pip":    "PIP_CACHE_DIR",
+}
+
+// BuildCacheEntry is a cache directory on the host shared by all sessions.
+// In JSON it is either a preset name such as "go" or an object such as
+// {"name": "gradle", "env": "GRADLE_USER_HOME", "mount": "/cache/gradle"}.
+type BuildCacheEntry struct {
+	Name  string `json:"name"`
+	Env   string `json:"env,omitempty"`   // Variable exported to the session (default: from the preset)
+	Path  string `json:"path,omitempty"`  // Host directory (default: ~/.cache/sbs/<name>)
+	Mount string `json:"mount,omitempty"` // Bind mount target inside the sandbox; the variable then points here
+}
+
+// UnmarshalJSON accepts either a preset name or an entry object
+func (e *BuildCacheEntry) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*e = BuildCacheEntry{Name: name}
+		return nil
+	}
+
+	type entry BuildCacheEntry
+	var decoded entry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("build_caches entries must be a preset name or an object with a name: %w", err)
+	}
+	*e = BuildCacheEntry(decoded)
+	return nil
+}
+
+// ThemeColorNames are the configurable TUI color slots
+var ThemeColorNames = []string{"primary", "secondary", "accent", "warning", "error", "muted"}
+
+// KeyBindingActions are the TUI actions whose keys can be configured
+var KeyBindingActions = []string{"up", "down", "enter", "quit", "help", "refresh", "toggle_view", "stop", "clean", "logs", "dashboard", "page_up", "page_down", "recent", "palette", "group"}
+
+// AutomationOperations are the destructive operations automation_allow names
+var AutomationOperations = []string{"clean", "stop", "move", "sandbox-restore", "sandbox-rm", "sandbox-prune"}
+
+// AutomationNone in automation_allow lets no operation skip confirmation
+const AutomationNone = "none"
+
+// NotifierConfig is one destination for sbs events. Webhook and Slack
+// notifiers post to URL; command notifiers run Command with sh -c.
+type NotifierConfig struct {
+	Type     string   `json:"type"`               // webhook, slack or command
+	URL      string   `json:"url,omitempty"`      // Where webhook and slack notifiers post
+	Command  string   `json:"command,omitempty"`  // Shell command run with the event as JSON on stdin
+	Events   []string `json:"events,omitempty"`   // Events sent to this notifier (default: all)
+	Template string   `json:"template,omitempty"` // Go text/template for the message, over the event's fields (default: the event's message)
+}
+
+// LoghookArchiveStores are the accepted loghook_archive values
+var LoghookArchiveStores = []string{"local", "command"}
+
+// NotifierTypes are the accepted notifiers[].type values
+var NotifierTypes = []string{"webhook", "slack", "command"}
+
+// NotifierEvents are the events notifiers can subscribe to
+var NotifierEvents = []string{"session-waiting", "cleanup", "start-failed"}
+
+// DefaultNotifyWaitingMins is how long an agent waits for input before
+// session-waiting is sent when notify_waiting_minutes isn't set
+const DefaultNotifyWaitingMins = 30
+
+// SourceBadge is the icon and color marking one work item source
+type SourceBadge struct {
+	Icon  string `json:"icon,omitempty"`  // Short ASCII label, e.g. "GH"
+	Color string `json:"color,omitempty"` // Hex color like "#8957E5" or ANSI color number
+}
+
+// SourceBadgeStyles are the accepted source_badge_style values
+var SourceBadgeStyles = []string{"color", "icon", "none"}
+
+// GitHubClients are the accepted github_client values
+var GitHubClients = []string{"gh", "api"}
+
+// MaxSourceBadgeIconLength is the longest allowed source badge icon
+const MaxSourceBadgeIconLength = 4
+
+var sourceBadgeIconPattern = regexp.MustCompile(`^[!-~]+$`)
+
+var themeColorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|#[0-9A-Fa-f]{3}|[0-9]{1,3})$`)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LogHighlightRule maps a regular expression to a style in the log view
+type LogHighlightRule struct {
+	Pattern    string `json:"pattern"`              // Regular expression matched against each log line
+	Color      string `json:"color,omitempty"`      // Foreground color (hex like "#FF6B6B" or ANSI number like "196")
+	Background string `json:"background,omitempty"` // Background color
+	Bold       bool   `json:"bold,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
 }
 
 // ResourceCreationEntry tracks the creation of individual resources during session setup
@@ -54,11 +335,12 @@ type SessionMetadata struct {
 	RepositoryRoot string `json:"repository_root"`
 	CreatedAt      string `json:"created_at"`
 	LastActivity   string `json:"last_activity"`
-	Status         string `json:"status"` // active, stopped, stale
+	Status         string `json:"status"` // active, stopped, stale, repo missing; deleted in the archive
 
 	// Input source fields for pluggable backends
 	SourceType   string `json:"source_type,omitempty"`   // github, test, jira, etc.
 	NamespacedID string `json:"namespaced_id,omitempty"` // Full namespaced ID (e.g., "github:123", "test:quick")
+	WorkItemURL  string `json:"work_item_url,omitempty"` // Link to the work item in its tracker, when it has one
 
 	// Resource tracking fields for enhanced cleanup and failure recovery
 	ResourceStatus      string                  `json:"resource_status,omitempty"`       // creating, active, cleanup, failed
@@ -66,6 +348,34 @@ type SessionMetadata struct {
 	FailurePoint        string                  `json:"failure_point,omitempty"`         // step where creation failed
 	FailureReason       string                  `json:"failure_reason,omitempty"`        // reason for failure
 	ResourceCreationLog []ResourceCreationEntry `json:"resource_creation_log,omitempty"` // log of all created resources
+
+	// Build caches wired into the session when it was started (resolved paths)
+	BuildCaches []BuildCacheEntry `json:"build_caches,omitempty"`
+
+	// Pull request opened with sbs pr, and its checks when last polled or the
+	// last status pushed into the worktree's .sbs/inbox/
+	PullRequestNumber int    `json:"pull_request_number,omitempty"`
+	PullRequestURL    string `json:"pull_request_url,omitempty"`
+	RemoteStatus      string `json:"remote_status,omitempty"`    // e.g. "checks pending", "checks passed", "ci failed"
+	RemoteStatusAt    string `json:"remote_status_at,omitempty"` // When RemoteStatus was polled or pushed (RFC3339)
+	RemoteMessage     string `json:"remote_message,omitempty"`   // Details pushed with RemoteStatus
+	RemoteURL         string `json:"remote_url,omitempty"`       // Link pushed with RemoteStatus, e.g. a build
+
+	// Snapshots of the session's sandbox, oldest first
+	Snapshots []SandboxSnapshot `json:"snapshots,omitempty"`
+}
+
+// Reasons recorded for sandbox snapshots
+const (
+	SnapshotReasonManual = "manual"
+	SnapshotReasonClean  = "before clean"
+)
+
+// SandboxSnapshot is a snapshot taken of a session's sandbox
+type SandboxSnapshot struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	Reason    string `json:"reason,omitempty"`
 }
 
 func DefaultConfig() *Config {
@@ -83,16 +393,27 @@ func DefaultConfig() *Config {
 	}
 }
 
+// GlobalConfigPath returns the path of the user's global config file
+func GlobalConfigPath() (string, error) {
+	return paths.ConfigFile()
+}
+
+// RepositoryConfigPath returns the path of a repository's .sbs/config.json
+func RepositoryConfigPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".sbs", "config.json")
+}
+
 func LoadConfig() (*Config, error) {
-	homeDir, err := os.UserHomeDir()
+	configPath, err := GlobalConfigPath()
 	if err != nil {
 		return nil, err
 	}
 
-	configPath := filepath.Join(homeDir, ".config", "sbs", "config.json")
-
-	// Create default config if doesn't exist
+	// Create default config if doesn't exist, unless --config named it
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if paths.ConfigFileOverride() != "" {
+			return nil, fmt.Errorf("config file %s does not exist", configPath)
+		}
 		config := DefaultConfig()
 		if err := SaveConfig(config); err != nil {
 			return nil, err
@@ -109,6 +430,10 @@ func LoadConfig() (*Config, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
+	setUnknownKeysMode(config.UnknownConfigKeys)
+	if err := checkUnknownKeys(configPath, data); err != nil {
+		return nil, err
+	}
 
 	// Validate required fields for resource tracking features
 	if err := validateConfig(&config); err != nil {
@@ -118,29 +443,59 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
-// LoadConfigWithRepository loads configuration with repository-specific overrides
+// LoadConfigWithRepository loads configuration with repository-specific
+// overrides: the global config, the org defaults over it, and the
+// repository's .sbs/config.json over those
 func LoadConfigWithRepository(repoRoot string) (*Config, error) {
-	// Load global config first
-	config, err := LoadConfig()
+	layers, err := LoadConfigLayers(repoRoot)
 	if err != nil {
 		return nil, err
 	}
+	return MergeLayers(layers), nil
+}
 
-	// Try to load repository-specific config
-	repoConfig, err := LoadRepositoryConfig(repoRoot)
+// LoadConfigWithWorktree loads configuration with repository-specific
+// overrides and, above those, the overrides in a session worktree's own
+// .sbs/config.json (e.g. a different tmux command or loghook interval for
+// that session). An unreadable worktree config is reported with the
+// repository configuration so callers can fall back to it.
+func LoadConfigWithWorktree(repoRoot, worktreePath string) (*Config, error) {
+	config, err := LoadConfigWithRepository(repoRoot)
 	if err != nil {
-		// If repository config doesn't exist or can't be loaded, just use global config
+		return nil, err
+	}
+
+	worktreeConfig, err := LoadWorktreeConfig(repoRoot, worktreePath)
+	if err != nil {
+		return config, err
+	}
+	if worktreeConfig == nil {
 		return config, nil
 	}
+	return MergeConfig(config, worktreeConfig), nil
+}
 
-	// Merge repository config over global config
-	mergedConfig := MergeConfig(config, repoConfig)
-	return mergedConfig, nil
+// LoadWorktreeConfig loads the overrides in a session worktree's
+// .sbs/config.json. It returns nil when there are none, including when the
+// worktree is the repository's main checkout, whose config is the
+// repository config.
+func LoadWorktreeConfig(repoRoot, worktreePath string) (*Config, error) {
+	if worktreePath == "" || filepath.Clean(worktreePath) == filepath.Clean(repoRoot) {
+		return nil, nil
+	}
+	worktreeConfig, err := LoadRepositoryConfig(worktreePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", RepositoryConfigPath(worktreePath), err)
+	}
+	return worktreeConfig, nil
 }
 
 // LoadRepositoryConfig loads configuration from .sbs/config.json in repository root
 func LoadRepositoryConfig(repoRoot string) (*Config, error) {
-	configPath := filepath.Join(repoRoot, ".sbs", "config.json")
+	configPath := RepositoryConfigPath(repoRoot)
 
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, err
@@ -152,13 +507,29 @@ func LoadRepositoryConfig(repoRoot string) (*Config, error) {
 	}
 
 	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := decodeConfigFile(configPath, data, &config); err != nil {
 		return nil, err
 	}
 
 	return &config, nil
 }
 
+// ValidateRepositoryConfig checks that a repository's .sbs/config.json
+// parses and, merged over base, passes validation. A missing file is valid.
+func ValidateRepositoryConfig(base *Config, repoRoot string) error {
+	repoConfig, err := LoadRepositoryConfig(repoRoot)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", RepositoryConfigPath(repoRoot), err)
+	}
+	if base == nil {
+		base = DefaultConfig()
+	}
+	return validateConfig(MergeConfig(base, repoConfig))
+}
+
 // MergeConfig merges repository config over base config, only overriding non-zero values
 func MergeConfig(base, override *Config) *Config {
 	merged := *base // Copy base config
@@ -219,42 +590,327 @@ func MergeConfig(base, override *Config) *Config {
 	if override.LogRefreshIntervalSecs > 0 {
 		merged.LogRefreshIntervalSecs = override.LogRefreshIntervalSecs
 	}
+	if len(override.LoghookArgs) > 0 {
+		merged.LoghookArgs = make([]string, len(override.LoghookArgs))
+		copy(merged.LoghookArgs, override.LoghookArgs)
+	}
+	if len(override.LoghookIntervals) > 0 {
+		intervals := make(map[string]int, len(base.LoghookIntervals)+len(override.LoghookIntervals))
+		for name, secs := range base.LoghookIntervals {
+			intervals[name] = secs
+		}
+		for name, secs := range override.LoghookIntervals {
+			intervals[name] = secs
+		}
+		merged.LoghookIntervals = intervals
+	}
+	if override.LoghookMinIntervalSecs > 0 {
+		merged.LoghookMinIntervalSecs = override.LoghookMinIntervalSecs
+	}
+	if override.LoghookMaxIntervalSecs > 0 {
+		merged.LoghookMaxIntervalSecs = override.LoghookMaxIntervalSecs
+	}
+	if override.LoghookMaxOutputBytes > 0 {
+		merged.LoghookMaxOutputBytes = override.LoghookMaxOutputBytes
+	}
+	if override.LoghookArchive != "" {
+		merged.LoghookArchive = override.LoghookArchive
+	}
+	if override.LoghookArchiveCommand != "" {
+		merged.LoghookArchiveCommand = override.LoghookArchiveCommand
+	}
+	if override.LoghookArchiveKeep > 0 {
+		merged.LoghookArchiveKeep = override.LoghookArchiveKeep
+	}
+	if override.LoghookArchiveMaxBytes > 0 {
+		merged.LoghookArchiveMaxBytes = override.LoghookArchiveMaxBytes
+	}
+	if len(override.LogHighlightRules) > 0 {
+		merged.LogHighlightRules = make([]LogHighlightRule, len(override.LogHighlightRules))
+		copy(merged.LogHighlightRules, override.LogHighlightRules)
+	}
+
+	// Tmux integration
+	if override.TmuxControlMode {
+		merged.TmuxControlMode = override.TmuxControlMode
+	}
+	if len(override.AttachEnvironment) > 0 {
+		merged.AttachEnvironment = make([]string, len(override.AttachEnvironment))
+		copy(merged.AttachEnvironment, override.AttachEnvironment)
+	}
+
+	// Waiting-for-input alerts
+	if override.WaitingBell {
+		merged.WaitingBell = override.WaitingBell
+	}
+	if override.ShowDiffstat {
+		merged.ShowDiffstat = override.ShowDiffstat
+	}
+
+	// Branch-behind warnings
+	if override.BranchBehindCommits != 0 {
+		merged.BranchBehindCommits = override.BranchBehindCommits
+	}
+	if override.BranchBehindDays != 0 {
+		merged.BranchBehindDays = override.BranchBehindDays
+	}
+
+	// External command timeout configuration
+	if override.CommandTimeoutSecs != 0 {
+		merged.CommandTimeoutSecs = override.CommandTimeoutSecs
+	}
+	if len(override.CommandTimeouts) > 0 {
+		timeouts := make(map[string]int, len(base.CommandTimeouts)+len(override.CommandTimeouts))
+		for tool, secs := range base.CommandTimeouts {
+			timeouts[tool] = secs
+		}
+		for tool, secs := range override.CommandTimeouts {
+			timeouts[tool] = secs
+		}
+		merged.CommandTimeouts = timeouts
+	}
+	if len(override.TimingBudgets) > 0 {
+		budgets := make(map[string]int, len(base.TimingBudgets)+len(override.TimingBudgets))
+		for op, secs := range base.TimingBudgets {
+			budgets[op] = secs
+		}
+		for op, secs := range override.TimingBudgets {
+			budgets[op] = secs
+		}
+		merged.TimingBudgets = budgets
+	}
+
+	// TUI appearance and key bindings
+	if len(override.Theme) > 0 {
+		theme := make(map[string]string, len(base.Theme)+len(override.Theme))
+		for name, color := range base.Theme {
+			theme[name] = color
+		}
+		for name, color := range override.Theme {
+			theme[name] = color
+		}
+		merged.Theme = theme
+	}
+	if len(override.RepositoryGroups) > 0 {
+		groups := make(map[string][]string, len(base.RepositoryGroups)+len(override.RepositoryGroups))
+		for name, members := range base.RepositoryGroups {
+			groups[name] = members
+		}
+		for name, members := range override.RepositoryGroups {
+			groups[name] = append([]string(nil), members...)
+		}
+		merged.RepositoryGroups = groups
+	}
+	if len(override.KeyBindings) > 0 {
+		bindings := make(map[string][]string, len(base.KeyBindings)+len(override.KeyBindings))
+		for action, keys := range base.KeyBindings {
+			bindings[action] = keys
+		}
+		for action, keys := range override.KeyBindings {
+			bindings[action] = append([]string(nil), keys...)
+		}
+		merged.KeyBindings = bindings
+	}
+	if len(override.SourceBadges) > 0 {
+		badges := make(map[string]SourceBadge, len(base.SourceBadges)+len(override.SourceBadges))
+		for source, badge := range base.SourceBadges {
+			badges[source] = badge
+		}
+		for source, badge := range override.SourceBadges {
+			badges[source] = badge
+		}
+		merged.SourceBadges = badges
+	}
+	if override.SourceBadgeStyle != "" {
+		merged.SourceBadgeStyle = override.SourceBadgeStyle
+	}
+
+	// User-facing wording
+	if len(override.Messages) > 0 {
+		templates := make(map[string]string, len(base.Messages)+len(override.Messages))
+		for id, text := range base.Messages {
+			templates[id] = text
+		}
+		for id, text := range override.Messages {
+			templates[id] = text
+		}
+		merged.Messages = templates
+	}
+	if len(override.Terms) > 0 {
+		terms := make(map[string]string, len(base.Terms)+len(override.Terms))
+		for term, text := range base.Terms {
+			terms[term] = text
+		}
+		for term, text := range override.Terms {
+			terms[term] = text
+		}
+		merged.Terms = terms
+	}
+
+	// Worktree provisioning
+	if len(override.CopyFromMain) > 0 {
+		merged.CopyFromMain = make([]CopyFromMainEntry, len(override.CopyFromMain))
+		copy(merged.CopyFromMain, override.CopyFromMain)
+	}
+	if override.CopyFromMainMaxBytes > 0 {
+		merged.CopyFromMainMaxBytes = override.CopyFromMainMaxBytes
+	}
+	if len(override.BuildCaches) > 0 {
+		merged.BuildCaches = make([]BuildCacheEntry, len(override.BuildCaches))
+		copy(merged.BuildCaches, override.BuildCaches)
+	}
+
+	// State location
+	if override.XDGState {
+		merged.XDGState = override.XDGState
+	}
+
+	// GitHub client
+	if override.GitHubClient != "" {
+		merged.GitHubClient = override.GitHubClient
+	}
+	if override.GitHubAPIURL != "" {
+		merged.GitHubAPIURL = override.GitHubAPIURL
+	}
+
+	// Git executable
+	if override.GitExecutable != "" {
+		merged.GitExecutable = override.GitExecutable
+	}
+
+	// Branch naming
+	if override.BranchTemplate != "" {
+		merged.BranchTemplate = override.BranchTemplate
+	}
+
+	// Name scope
+	if override.NameScope != "" {
+		merged.NameScope = override.NameScope
+	}
+
+	// Sandbox prewarming
+	if override.SandboxPoolSize > 0 {
+		merged.SandboxPoolSize = override.SandboxPoolSize
+	}
+
+	// Session readiness
+	if len(override.ReadinessChecks) > 0 {
+		merged.ReadinessChecks = make([]ReadinessCheck, len(override.ReadinessChecks))
+		copy(merged.ReadinessChecks, override.ReadinessChecks)
+	}
+
+	// Issue bootstrap
+	if override.IssueBootstrap {
+		merged.IssueBootstrap = override.IssueBootstrap
+	}
+
+	// Cleanup protection adds to the global patterns rather than replacing them
+	if len(override.ProtectedBranches) > 0 {
+		merged.ProtectedBranches = appendUnique(base.ProtectedBranches, override.ProtectedBranches)
+	}
+	if len(override.ProtectedWorktrees) > 0 {
+		merged.ProtectedWorktrees = appendUnique(base.ProtectedWorktrees, override.ProtectedWorktrees)
+	}
+	if override.PruneEmptyWorktreeDirs {
+		merged.PruneEmptyWorktreeDirs = override.PruneEmptyWorktreeDirs
+	}
+	if override.SandboxRequired {
+		merged.SandboxRequired = override.SandboxRequired
+	}
+	if override.SnapshotBeforeClean {
+		merged.SnapshotBeforeClean = override.SnapshotBeforeClean
+	}
+	if len(override.AutomationAllow) > 0 {
+		merged.AutomationAllow = append([]string(nil), override.AutomationAllow...)
+	}
+
+	// Session expiry
+	if override.CleanAfterDays > 0 {
+		merged.CleanAfterDays = override.CleanAfterDays
+	}
+	if override.ExpiryWarningDays > 0 {
+		merged.ExpiryWarningDays = override.ExpiryWarningDays
+	}
+	if override.ExpiryNotify {
+		merged.ExpiryNotify = override.ExpiryNotify
+	}
+
+	// Pull request checks
+	if override.PRCheckIntervalSecs > 0 {
+		merged.PRCheckIntervalSecs = override.PRCheckIntervalSecs
+	}
+	if override.PRCheckTimeoutMins > 0 {
+		merged.PRCheckTimeoutMins = override.PRCheckTimeoutMins
+	}
+	if override.PRWaitRequiredChecks {
+		merged.PRWaitRequiredChecks = override.PRWaitRequiredChecks
+	}
 
 	return &merged
 }
 
+// SaveConfig writes the global config file, readable only by the user since
+// it can hold github_token
 func SaveConfig(config *Config) error {
-	homeDir, err := os.UserHomeDir()
+	configPath, err := GlobalConfigPath()
 	if err != nil {
 		return err
 	}
-
-	configDir := filepath.Join(homeDir, ".config", "sbs")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), paths.PrivateDirMode); err != nil {
 		return err
 	}
 
-	configPath := filepath.Join(configDir, "config.json")
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	return writeFileAtomic(configPath, data, paths.PrivateFileMode)
+}
+
+// ErrSessionsChecksum is returned when a sessions file doesn't match the
+// checksum recorded when it was saved, e.g. after a truncated write
+var ErrSessionsChecksum = errors.New("sessions file does not match its checksum (run 'sbs fsck')")
+
+// sessionsFile is the layout of a sessions file: the checksum of the
+// sessions heads the file, so both are replaced by a single atomic write.
+// Files saved before checksums were introduced hold a bare array.
+type sessionsFile struct {
+	Checksum string          `json:"checksum"`
+	Sessions json.RawMessage `json:"sessions"`
 }
 
-// LoadSessions loads sessions from a specific path
+// legacySessionsChecksumPath is the checksum file earlier versions wrote next
+// to a sessions file; saving removes it
+func legacySessionsChecksumPath(sessionsPath string) string {
+	return sessionsPath + ".sha256"
+}
+
+// LoadSessions loads sessions from a specific path, verifying its checksum
+// when one was recorded
 func LoadSessionsFromPath(sessionsPath string) ([]SessionMetadata, error) {
-	if _, err := os.Stat(sessionsPath); os.IsNotExist(err) {
-		return []SessionMetadata{}, nil
+	if err := VerifySessionsChecksum(sessionsPath); err != nil {
+		return nil, err
 	}
+	return LoadSessionsFromPathUnverified(sessionsPath)
+}
 
+// LoadSessionsFromPathUnverified loads sessions without checking the checksum
+func LoadSessionsFromPathUnverified(sessionsPath string) ([]SessionMetadata, error) {
 	data, err := os.ReadFile(sessionsPath)
+	if os.IsNotExist(err) {
+		return []SessionMetadata{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	var sessions []SessionMetadata
+	if file, sealed, err := parseSessionsFile(data); err != nil {
+		return nil, err
+	} else if sealed {
+		data = file.Sessions
+	}
 	if err := json.Unmarshal(data, &sessions); err != nil {
 		return nil, err
 	}
@@ -262,44 +918,386 @@ func LoadSessionsFromPath(sessionsPath string) ([]SessionMetadata, error) {
 	return sessions, nil
 }
 
-// SaveSessionsToPath saves sessions to a specific path
+// parseSessionsFile reads the checksum header of a sessions file; sealed is
+// false for files holding a bare array
+func parseSessionsFile(data []byte) (file sessionsFile, sealed bool, err error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return file, false, nil
+	}
+	if err := json.Unmarshal(trimmed, &file); err != nil {
+		return file, true, err
+	}
+	return file, true, nil
+}
+
+// VerifySessionsChecksum compares the sessions in a file with the checksum in
+// its header. Files saved before checksums were introduced have none and
+// always pass.
+func VerifySessionsChecksum(sessionsPath string) error {
+	data, err := os.ReadFile(sessionsPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	file, sealed, err := parseSessionsFile(data)
+	if !sealed {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s is truncated or corrupt", ErrSessionsChecksum, sessionsPath)
+	}
+	sum, err := sessionsChecksum(file.Sessions)
+	if err != nil || file.Checksum != sum {
+		return fmt.Errorf("%w: %s", ErrSessionsChecksum, sessionsPath)
+	}
+	return nil
+}
+
+// SaveSessionsToPath saves sessions to a specific path. Entries sharing a
+// namespaced ID are merged first, keeping the newest and archiving the rest.
+// The file is replaced atomically, headed by the checksum of its sessions so
+// truncated writes and hand edits can be detected.
 func SaveSessionsToPath(sessions []SessionMetadata, sessionsPath string) error {
 	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(sessionsPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(sessionsPath), paths.PrivateDirMode); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(sessions, "", "  ")
+	sessions, duplicates := DedupeSessions(sessions)
+	if len(duplicates) > 0 {
+		archivePath := SessionsArchivePath(sessionsPath)
+		if err := ArchiveSessions(archivePath, duplicates, ArchiveReasonDuplicate); err != nil {
+			return fmt.Errorf("failed to archive duplicate sessions: %w", err)
+		}
+		for _, duplicate := range duplicates {
+			log.Printf("Warning: dropped duplicate session entry for %s (archived to %s)", duplicate.NamespacedID, archivePath)
+		}
+	}
+
+	body, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	checksum, err := sessionsChecksum(body)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sessionsFile{Checksum: checksum, Sessions: body}, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(sessionsPath, data, 0644)
+	if trace.Enabled() {
+		previous, _ := LoadSessionsFromPathUnverified(sessionsPath)
+		trace.StateChange("save sessions", sessionsPath, DescribeSessionChanges(previous, sessions))
+	}
+	if err := writeFileAtomic(sessionsPath, data, paths.PrivateFileMode); err != nil {
+		return err
+	}
+	os.Remove(legacySessionsChecksumPath(sessionsPath))
+	return nil
+}
+
+// DescribeSessionChanges summarizes how saving after would change before,
+// e.g. "added github:1; changed github:2 (status)"
+func DescribeSessionChanges(before, after []SessionMetadata) string {
+	previous := make(map[string]SessionMetadata, len(before))
+	for _, session := range before {
+		previous[session.NamespacedID] = session
+	}
+
+	var added, changed, removed []string
+	seen := make(map[string]bool, len(after))
+	for _, session := range after {
+		seen[session.NamespacedID] = true
+		old, exists := previous[session.NamespacedID]
+		if !exists {
+			added = append(added, session.NamespacedID)
+			continue
+		}
+		if fields := changedSessionFields(old, session); len(fields) > 0 {
+			changed = append(changed, fmt.Sprintf("%s (%s)", session.NamespacedID, strings.Join(fields, ", ")))
+		}
+	}
+	for _, session := range before {
+		if !seen[session.NamespacedID] {
+			removed = append(removed, session.NamespacedID)
+		}
+	}
+
+	var parts []string
+	for _, group := range []struct {
+		label string
+		ids   []string
+	}{{"added", added}, {"changed", changed}, {"removed", removed}} {
+		if len(group.ids) > 0 {
+			parts = append(parts, group.label+" "+strings.Join(group.ids, ", "))
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// changedSessionFields lists the JSON names of the fields that differ
+// between two entries for the same session
+func changedSessionFields(before, after SessionMetadata) []string {
+	var oldFields, newFields map[string]json.RawMessage
+	oldData, _ := json.Marshal(before)
+	newData, _ := json.Marshal(after)
+	if json.Unmarshal(oldData, &oldFields) != nil || json.Unmarshal(newData, &newFields) != nil {
+		return nil
+	}
+
+	var fields []string
+	for name, value := range newFields {
+		if string(oldFields[name]) != string(value) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range oldFields {
+		if _, exists := newFields[name]; !exists {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// DedupeSessions keeps one entry per namespaced ID and returns the dropped
+// duplicates. The most recently active entry wins (later entries on ties) and
+// takes the position of the first occurrence. Entries without an ID are kept.
+func DedupeSessions(sessions []SessionMetadata) (kept, duplicates []SessionMetadata) {
+	index := make(map[string]int)
+	kept = make([]SessionMetadata, 0, len(sessions))
+	for _, session := range sessions {
+		if session.NamespacedID == "" {
+			kept = append(kept, session)
+			continue
+		}
+		i, ok := index[session.NamespacedID]
+		if !ok {
+			index[session.NamespacedID] = len(kept)
+			kept = append(kept, session)
+			continue
+		}
+		if session.LastUsed().Before(kept[i].LastUsed()) {
+			duplicates = append(duplicates, session)
+		} else {
+			duplicates = append(duplicates, kept[i])
+			kept[i] = session
+		}
+	}
+	return kept, duplicates
+}
+
+// LastUsed is when the session was last attached to, switched to or started:
+// last_activity, else created_at. It is zero when neither is recorded.
+func (s SessionMetadata) LastUsed() time.Time {
+	for _, value := range []string{s.LastActivity, s.CreatedAt} {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// SessionStatusDeleted is the status of sessions sbs clean moved to the archive
+const SessionStatusDeleted = "deleted"
+
+// Reasons recorded for archived sessions
+const (
+	ArchiveReasonDuplicate = "duplicate namespaced_id"
+	ArchiveReasonCleaned   = "cleaned"
+	ArchiveReasonFailed    = "start failed"
+)
+
+// ArchivedSession is a session entry removed from sessions.json but kept for
+// reference
+type ArchivedSession struct {
+	ArchivedAt string          `json:"archived_at"`
+	Reason     string          `json:"reason"`
+	Session    SessionMetadata `json:"session"`
+}
+
+// SessionsArchivePath returns the archive written next to a sessions file
+func SessionsArchivePath(sessionsPath string) string {
+	return filepath.Join(filepath.Dir(sessionsPath), "sessions-archive.json")
+}
+
+// ArchiveSessions appends sessions to the archive at archivePath
+func ArchiveSessions(archivePath string, sessions []SessionMetadata, reason string) error {
+	archived, err := LoadArchivedSessions(archivePath)
+	if err != nil {
+		return err
+	}
+
+	now := Timestamp()
+	for _, session := range sessions {
+		archived = append(archived, ArchivedSession{ArchivedAt: now, Reason: reason, Session: session})
+		trace.StateChange("archive session", archivePath, session.NamespacedID+" ("+reason+")")
+	}
+
+	data, err := json.MarshalIndent(archived, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(archivePath, data, paths.PrivateFileMode)
+}
+
+// GlobalSessionsArchivePath returns the archive next to the global sessions file
+func GlobalSessionsArchivePath() (string, error) {
+	sessionsPath, err := GetGlobalSessionsPath()
+	if err != nil {
+		return "", err
+	}
+	return SessionsArchivePath(sessionsPath), nil
+}
+
+// LoadArchivedSessions reads the session archive, which may not exist yet
+func LoadArchivedSessions(archivePath string) ([]ArchivedSession, error) {
+	data, err := os.ReadFile(archivePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []ArchivedSession
+	if err := json.Unmarshal(data, &archived); err != nil {
+		return nil, fmt.Errorf("failed to parse session archive %s: %w", archivePath, err)
+	}
+	return archived, nil
+}
+
+// PurgeArchivedSessions removes the archive entries purge selects and
+// returns how many it removed
+func PurgeArchivedSessions(archivePath string, purge func(ArchivedSession) bool) (int, error) {
+	archived, err := LoadArchivedSessions(archivePath)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]ArchivedSession, 0, len(archived))
+	for _, entry := range archived {
+		if !purge(entry) {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(archived) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := writeFileAtomic(archivePath, data, paths.PrivateFileMode); err != nil {
+		return 0, err
+	}
+	trace.StateChange("purge session history", archivePath, fmt.Sprintf("%d entries", removed))
+	return removed, nil
+}
+
+// sessionsChecksum hashes the compacted sessions JSON, so reindenting the
+// file doesn't change it
+func sessionsChecksum(sessions json.RawMessage) (string, error) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, sessions); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(compacted.Bytes())
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // LoadSessions loads sessions from the global location (for backward compatibility)
 func LoadSessions() ([]SessionMetadata, error) {
-	homeDir, err := os.UserHomeDir()
+	sessionsPath, err := GetGlobalSessionsPath()
 	if err != nil {
 		return nil, err
 	}
-
-	sessionsPath := filepath.Join(homeDir, ".config", "sbs", "sessions.json")
 	return LoadSessionsFromPath(sessionsPath)
 }
 
 // SaveSessions saves sessions to the global location (for backward compatibility)
 func SaveSessions(sessions []SessionMetadata) error {
-	homeDir, err := os.UserHomeDir()
+	sessionsPath, err := GetGlobalSessionsPath()
 	if err != nil {
 		return err
 	}
-
-	configDir := filepath.Join(homeDir, ".config", "sbs")
-	sessionsPath := filepath.Join(configDir, "sessions.json")
 	return SaveSessionsToPath(sessions, sessionsPath)
 }
 
+// UpdateSessions applies update to the global sessions while holding the
+// sessions lock, so concurrent sbs processes never overwrite each other's
+// changes the way a separate load and save can
+func UpdateSessions(update func([]SessionMetadata) ([]SessionMetadata, error)) error {
+	sessionsPath, err := GetGlobalSessionsPath()
+	if err != nil {
+		return err
+	}
+	return UpdateSessionsAtPath(sessionsPath, update)
+}
+
+// SessionsLockPath returns the lock file guarding updates of a sessions file
+func SessionsLockPath(sessionsPath string) string {
+	return sessionsPath + ".lock"
+}
+
+// UpdateSessionsAtPath is UpdateSessions for a specific sessions file
+func UpdateSessionsAtPath(sessionsPath string, update func([]SessionMetadata) ([]SessionMetadata, error)) error {
+	if err := os.MkdirAll(filepath.Dir(sessionsPath), paths.PrivateDirMode); err != nil {
+		return err
+	}
+	unlock, err := oplock.File(SessionsLockPath(sessionsPath))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	sessions, err := LoadSessionsFromPath(sessionsPath)
+	if err != nil {
+		return err
+	}
+	updated, err := update(sessions)
+	if err != nil {
+		return err
+	}
+	return SaveSessionsToPath(updated, sessionsPath)
+}
+
 // LoadAllRepositorySessions loads sessions from the global sessions file
 func LoadAllRepositorySessions() ([]SessionMetadata, error) {
 	// Use only the global sessions file as the single source of truth
@@ -307,13 +1305,37 @@ func LoadAllRepositorySessions() ([]SessionMetadata, error) {
 	return LoadSessions()
 }
 
-// GetGlobalSessionsPath returns the path to the global sessions file
+// GetGlobalSessionsPath returns the path to the global sessions file in the
+// state directory
 func GetGlobalSessionsPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
+	return paths.StatePath("sessions.json")
+}
+
+// FindSessionsByWorktreePath returns the sessions whose worktree contains path.
+// More than one match means the mapping is ambiguous (e.g. a stale entry
+// pointing at a reused worktree directory).
+func FindSessionsByWorktreePath(sessions []SessionMetadata, path string) []*SessionMetadata {
+	path = canonicalPath(path)
+
+	var matches []*SessionMetadata
+	for i := range sessions {
+		if sessions[i].WorktreePath == "" {
+			continue
+		}
+		worktree := canonicalPath(sessions[i].WorktreePath)
+		if path == worktree || strings.HasPrefix(path, worktree+string(filepath.Separator)) {
+			matches = append(matches, &sessions[i])
+		}
+	}
+	return matches
+}
+
+// canonicalPath cleans a path and resolves symlinks where possible
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
 	}
-	return filepath.Join(homeDir, ".config", "sbs", "sessions.json"), nil
+	return filepath.Clean(path)
 }
 
 // validateConfig validates that required fields are present for resource tracking features
@@ -358,6 +1380,270 @@ func validateConfig(config *Config) error {
 	if config.LogRefreshIntervalSecs != 0 && (config.LogRefreshIntervalSecs < 1 || config.LogRefreshIntervalSecs > 300) {
 		errors = append(errors, "log_refresh_interval_seconds must be between 1 and 300")
 	}
+	for name, secs := range config.LoghookIntervals {
+		if secs < 1 || secs > 300 {
+			errors = append(errors, fmt.Sprintf("loghook_intervals_seconds.%s must be between 1 and 300", name))
+		}
+	}
+	if config.LoghookMinIntervalSecs != 0 && (config.LoghookMinIntervalSecs < 1 || config.LoghookMinIntervalSecs > 300) {
+		errors = append(errors, "loghook_min_interval_seconds must be between 1 and 300")
+	}
+	if config.LoghookMaxIntervalSecs != 0 && (config.LoghookMaxIntervalSecs < 1 || config.LoghookMaxIntervalSecs > 300) {
+		errors = append(errors, "loghook_max_interval_seconds must be between 1 and 300")
+	}
+	if config.LoghookMinIntervalSecs > 0 && config.LoghookMaxIntervalSecs > 0 && config.LoghookMinIntervalSecs > config.LoghookMaxIntervalSecs {
+		errors = append(errors, "loghook_min_interval_seconds must not exceed loghook_max_interval_seconds")
+	}
+	if config.LoghookMaxOutputBytes != 0 && (config.LoghookMaxOutputBytes < 1024 || config.LoghookMaxOutputBytes > 10485760) {
+		errors = append(errors, "loghook_max_output_bytes must be between 1KB and 10MB")
+	}
+	if config.LoghookArchive != "" && !containsString(LoghookArchiveStores, config.LoghookArchive) {
+		errors = append(errors, fmt.Sprintf("loghook_archive must be one of: %s", strings.Join(LoghookArchiveStores, ", ")))
+	}
+	if config.LoghookArchive == "command" && strings.TrimSpace(config.LoghookArchiveCommand) == "" {
+		errors = append(errors, "loghook_archive_command is required with loghook_archive \"command\"")
+	}
+	if config.LoghookArchiveKeep < 0 {
+		errors = append(errors, "loghook_archive_keep must be positive")
+	}
+	if config.LoghookArchiveMaxBytes < 0 {
+		errors = append(errors, "loghook_archive_max_bytes must be positive")
+	}
+	for i, rule := range config.LogHighlightRules {
+		if rule.Pattern == "" {
+			errors = append(errors, fmt.Sprintf("log_highlight_rules[%d].pattern is required", i))
+		} else if _, err := regexp.Compile(rule.Pattern); err != nil {
+			errors = append(errors, fmt.Sprintf("log_highlight_rules[%d].pattern is not a valid regular expression: %v", i, err))
+		}
+	}
+
+	if config.UnknownConfigKeys != "" && !containsString(UnknownKeyModes, config.UnknownConfigKeys) {
+		errors = append(errors, fmt.Sprintf("unknown_config_keys must be one of: %s", strings.Join(UnknownKeyModes, ", ")))
+	}
+
+	// Validate attach environment ("none" alone turns it off)
+	for _, name := range config.AttachEnvironment {
+		if name == "none" && len(config.AttachEnvironment) > 1 {
+			errors = append(errors, "attach_environment can't combine \"none\" with variable names")
+		} else if name != "none" && !envNamePattern.MatchString(name) {
+			errors = append(errors, fmt.Sprintf("attach_environment has an invalid variable name: %q", name))
+		}
+	}
+
+	// Validate external command timeouts (only if explicitly set)
+	if config.CommandTimeoutSecs < -1 {
+		errors = append(errors, "command_timeout_seconds must be -1 (disabled) or greater")
+	}
+	for tool, secs := range config.CommandTimeouts {
+		switch tool {
+		case "git", "tmux", "sandbox", "github", "jira", "notify", "stalehook", "loghook_archive":
+		default:
+			errors = append(errors, fmt.Sprintf("command_timeouts has unknown tool %q (expected git, tmux, sandbox, github, jira, notify, stalehook or loghook_archive)", tool))
+		}
+		if secs < -1 {
+			errors = append(errors, fmt.Sprintf("command_timeouts.%s must be -1 (disabled) or greater", tool))
+		}
+	}
+	for op, secs := range config.TimingBudgets {
+		switch op {
+		case "git worktree add", "sandbox create":
+		default:
+			errors = append(errors, fmt.Sprintf("timing_budgets_seconds has unknown operation %q (expected \"git worktree add\" or \"sandbox create\")", op))
+		}
+		if secs < 0 {
+			errors = append(errors, fmt.Sprintf("timing_budgets_seconds.%s must be 0 (disabled) or greater", op))
+		}
+	}
+
+	// Validate TUI theme and key bindings
+	for name, color := range config.Theme {
+		if !containsString(ThemeColorNames, name) {
+			errors = append(errors, fmt.Sprintf("theme has unknown color %q (expected one of: %s)", name, strings.Join(ThemeColorNames, ", ")))
+		} else if !themeColorPattern.MatchString(color) {
+			errors = append(errors, fmt.Sprintf("theme.%s must be a hex color like \"#7D56F4\" or an ANSI color number", name))
+		}
+	}
+	for action, keys := range config.KeyBindings {
+		if !containsString(KeyBindingActions, action) {
+			errors = append(errors, fmt.Sprintf("key_bindings has unknown action %q (expected one of: %s)", action, strings.Join(KeyBindingActions, ", ")))
+			continue
+		}
+		if len(keys) == 0 {
+			errors = append(errors, fmt.Sprintf("key_bindings.%s must list at least one key", action))
+		}
+		for _, key := range keys {
+			if strings.TrimSpace(key) == "" {
+				errors = append(errors, fmt.Sprintf("key_bindings.%s contains an empty key", action))
+			}
+		}
+	}
+
+	for _, op := range config.AutomationAllow {
+		if op != AutomationNone && !containsString(AutomationOperations, op) {
+			errors = append(errors, fmt.Sprintf("automation_allow has unknown operation %q (expected %q or one of: %s)", op, AutomationNone, strings.Join(AutomationOperations, ", ")))
+		}
+	}
+	for i, notifier := range config.Notifiers {
+		errors = append(errors, validateNotifier(fmt.Sprintf("notifiers[%d]", i), notifier)...)
+	}
+	if config.BranchBehindCommits < -1 {
+		errors = append(errors, "branch_behind_commits must be positive, or -1 to disable")
+	}
+	if config.BranchBehindDays < -1 {
+		errors = append(errors, "branch_behind_days must be positive, or -1 to disable")
+	}
+	if config.NotifyWaitingMins < 0 {
+		errors = append(errors, "notify_waiting_minutes must be positive")
+	}
+	for name, members := range config.RepositoryGroups {
+		if strings.TrimSpace(name) == "" {
+			errors = append(errors, "repository_groups has a group without a name")
+			continue
+		}
+		if len(members) == 0 {
+			errors = append(errors, fmt.Sprintf("repository_groups.%s must list at least one repository", name))
+		}
+		for _, member := range members {
+			if strings.TrimSpace(member) == "" {
+				errors = append(errors, fmt.Sprintf("repository_groups.%s contains an empty repository", name))
+			}
+		}
+	}
+
+	// Validate source badges
+	if config.GitHubClient != "" && !containsString(GitHubClients, config.GitHubClient) {
+		errors = append(errors, fmt.Sprintf("github_client must be one of: %s", strings.Join(GitHubClients, ", ")))
+	}
+	if config.GitHubAPIURL != "" && !strings.HasPrefix(config.GitHubAPIURL, "https://") && !strings.HasPrefix(config.GitHubAPIURL, "http://") {
+		errors = append(errors, "github_api_url must be an http:// or https:// URL")
+	}
+	if config.SourceBadgeStyle != "" && !containsString(SourceBadgeStyles, config.SourceBadgeStyle) {
+		errors = append(errors, fmt.Sprintf("source_badge_style must be one of: %s", strings.Join(SourceBadgeStyles, ", ")))
+	}
+	for source, badge := range config.SourceBadges {
+		if badge.Icon != "" && (len(badge.Icon) > MaxSourceBadgeIconLength || !sourceBadgeIconPattern.MatchString(badge.Icon)) {
+			errors = append(errors, fmt.Sprintf("source_badges.%s.icon must be 1-%d printable ASCII characters without spaces", source, MaxSourceBadgeIconLength))
+		}
+		if badge.Color != "" && !themeColorPattern.MatchString(badge.Color) {
+			errors = append(errors, fmt.Sprintf("source_badges.%s.color must be a hex color like \"#7D56F4\" or an ANSI color number", source))
+		}
+	}
+
+	// Validate worktree provisioning paths; they must stay inside the repository
+	for i, entry := range config.CopyFromMain {
+		cleaned := filepath.Clean(entry.Path)
+		switch {
+		case strings.TrimSpace(entry.Path) == "":
+			errors = append(errors, fmt.Sprintf("copy_from_main[%d].path is required", i))
+		case filepath.IsAbs(entry.Path) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)):
+			errors = append(errors, fmt.Sprintf("copy_from_main[%d].path must be relative to the repository root: %s", i, entry.Path))
+		case cleaned == "." || cleaned == ".git" || strings.HasPrefix(cleaned, ".git"+string(filepath.Separator)):
+			errors = append(errors, fmt.Sprintf("copy_from_main[%d].path cannot be the repository root or .git: %s", i, entry.Path))
+		}
+	}
+	if config.CopyFromMainMaxBytes < 0 {
+		errors = append(errors, "copy_from_main_max_bytes cannot be negative")
+	}
+
+	// Validate shared build caches
+	seenCaches := make(map[string]bool)
+	for i, entry := range config.BuildCaches {
+		if strings.TrimSpace(entry.Name) == "" {
+			errors = append(errors, fmt.Sprintf("build_caches[%d].name is required", i))
+			continue
+		}
+		if seenCaches[entry.Name] {
+			errors = append(errors, fmt.Sprintf("build_caches has duplicate name %q", entry.Name))
+		}
+		seenCaches[entry.Name] = true
+		if _, ok := BuildCachePresets[entry.Name]; !ok && entry.Env == "" {
+			errors = append(errors, fmt.Sprintf("build_caches.%s needs an env variable (presets: %s)", entry.Name, strings.Join(buildCachePresetNames(), ", ")))
+		}
+		if entry.Mount != "" && !filepath.IsAbs(entry.Mount) {
+			errors = append(errors, fmt.Sprintf("build_caches.%s.mount must be an absolute path: %s", entry.Name, entry.Mount))
+		}
+	}
+
+	// Validate session expiry
+	if config.CleanAfterDays < 0 {
+		errors = append(errors, "clean_after_days cannot be negative")
+	}
+	if config.ExpiryWarningDays < 0 {
+		errors = append(errors, "expiry_warning_days cannot be negative")
+	} else if config.CleanAfterDays > 0 && config.ExpiryWarningDays >= config.CleanAfterDays {
+		errors = append(errors, "expiry_warning_days must be less than clean_after_days")
+	}
+
+	// Validate pull request checks
+	if config.PRCheckIntervalSecs < 0 {
+		errors = append(errors, "pr_check_interval_seconds cannot be negative")
+	} else if config.PRCheckIntervalSecs > 0 && config.PRCheckIntervalSecs < MinPRCheckIntervalSecs {
+		errors = append(errors, fmt.Sprintf("pr_check_interval_seconds must be at least %d", MinPRCheckIntervalSecs))
+	}
+	if config.PRCheckTimeoutMins < 0 {
+		errors = append(errors, "pr_check_timeout_minutes cannot be negative")
+	}
+
+	// Validate git executable (only if explicitly set)
+	if config.GitExecutable != "" && strings.TrimSpace(config.GitExecutable) != config.GitExecutable {
+		errors = append(errors, "git_executable cannot have leading or trailing whitespace")
+	}
+
+	// Validate branch template
+	if err := branchname.Validate(config.BranchTemplate); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate message templates and terms
+	if err := messages.Validate(config.Messages, config.Terms); err != nil {
+		errors = append(errors, strings.Split(err.Error(), "\n")...)
+	}
+
+	// Validate name scope
+	if err := naming.ValidateScopeSetting(config.NameScope); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate sandbox prewarming
+	if config.SandboxPoolSize < 0 {
+		errors = append(errors, "sandbox_pool_size cannot be negative")
+	}
+
+	// Validate readiness checks
+	for i, check := range config.ReadinessChecks {
+		probes := 0
+		for _, set := range []bool{strings.TrimSpace(check.Command) != "", check.Port != 0, check.File != ""} {
+			if set {
+				probes++
+			}
+		}
+		if probes != 1 {
+			errors = append(errors, fmt.Sprintf("readiness_checks[%d] must set exactly one of command, port or file", i))
+		}
+		if check.Port < 0 || check.Port > 65535 {
+			errors = append(errors, fmt.Sprintf("readiness_checks[%d].port must be between 1 and 65535", i))
+		}
+		if check.Host != "" && check.Port == 0 {
+			errors = append(errors, fmt.Sprintf("readiness_checks[%d].host needs a port", i))
+		}
+		if check.TimeoutSecs < 0 || check.TimeoutSecs > MaxReadinessTimeoutSecs {
+			errors = append(errors, fmt.Sprintf("readiness_checks[%d].timeout_seconds must be between 1 and %d", i, MaxReadinessTimeoutSecs))
+		}
+	}
+
+	// Validate cleanup protection patterns
+	for i, pattern := range config.ProtectedBranches {
+		if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+			errors = append(errors, fmt.Sprintf("protected_branches[%d] is not a valid glob: %q", i, pattern))
+		}
+	}
+	for i, pattern := range config.ProtectedWorktrees {
+		if _, err := filepath.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+			errors = append(errors, fmt.Sprintf("protected_worktrees[%d] is not a valid glob: %q", i, pattern))
+		} else if !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, "~/") {
+			errors = append(errors, fmt.Sprintf("protected_worktrees[%d] must be an absolute path or start with ~/: %q", i, pattern))
+		}
+	}
 
 	// If there are validation errors, return them as a single error
 	if len(errors) > 0 {
@@ -366,3 +1652,69 @@ func validateConfig(config *Config) error {
 
 	return nil
 }
+
+func buildCachePresetNames() []string {
+	names := make([]string, 0, len(BuildCachePresets))
+	for name := range BuildCachePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// appendUnique returns base followed by the values of extra it lacks
+func appendUnique(base, extra []string) []string {
+	merged := append([]string(nil), base...)
+	for _, value := range extra {
+		if !containsString(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNotifier checks one notifiers entry, naming it field in messages
+func validateNotifier(field string, notifier NotifierConfig) []string {
+	var errors []string
+	switch notifier.Type {
+	case "webhook", "slack":
+		if !strings.HasPrefix(notifier.URL, "https://") && !strings.HasPrefix(notifier.URL, "http://") {
+			errors = append(errors, fmt.Sprintf("%s.url must be an http:// or https:// URL", field))
+		}
+	case "command":
+		if strings.TrimSpace(notifier.Command) == "" {
+			errors = append(errors, fmt.Sprintf("%s.command is required", field))
+		}
+	default:
+		errors = append(errors, fmt.Sprintf("%s.type must be one of: %s", field, strings.Join(NotifierTypes, ", ")))
+	}
+	for _, event := range notifier.Events {
+		if !containsString(NotifierEvents, event) {
+			errors = append(errors, fmt.Sprintf("%s.events has unknown event %q (expected one of: %s)", field, event, strings.Join(NotifierEvents, ", ")))
+		}
+	}
+	if notifier.Template != "" {
+		if _, err := template.New(field).Parse(notifier.Template); err != nil {
+			errors = append(errors, fmt.Sprintf("%s.template is invalid: %v", field, err))
+		}
+	}
+	return errors
+}
+
+// AutomationAllowed reports whether --yes may run op without confirmation.
+// Every operation may when automation_allow is unset.
+func (c *Config) AutomationAllowed(op string) bool {
+	if len(c.AutomationAllow) == 0 {
+		return true
+	}
+	return containsString(c.AutomationAllow, op)
+}
@@ -2,10 +2,17 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/paths"
 )
 
 func TestSessionMetadata_FriendlyTitleField(t *testing.T) {
@@ -207,3 +214,785 @@ func TestSessionMetadata_EmptyFriendlyTitle(t *testing.T) {
 	assert.Equal(t, 789, deserializedMetadata.IssueNumber)
 	assert.Equal(t, "Fix critical security vulnerability", deserializedMetadata.IssueTitle)
 }
+
+func TestFindSessionsByWorktreePath(t *testing.T) {
+	sessions := []SessionMetadata{
+		{NamespacedID: "github:1", WorktreePath: "/worktrees/project/issue-1"},
+		{NamespacedID: "github:12", WorktreePath: "/worktrees/project/issue-12"},
+		{NamespacedID: "test:legacy"},
+		{NamespacedID: "github:5", WorktreePath: "/worktrees/shared"},
+		{NamespacedID: "github:6", WorktreePath: "/worktrees/shared/"},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected []string
+	}{
+		{name: "worktree_root", path: "/worktrees/project/issue-1", expected: []string{"github:1"}},
+		{name: "nested_directory", path: "/worktrees/project/issue-12/pkg/app", expected: []string{"github:12"}},
+		{name: "trailing_slash", path: "/worktrees/project/issue-1/", expected: []string{"github:1"}},
+		{name: "shared_prefix_is_not_a_match", path: "/worktrees/project/issue-123"},
+		{name: "outside_worktrees", path: "/src/project"},
+		{name: "ambiguous", path: "/worktrees/shared/src", expected: []string{"github:5", "github:6"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, session := range FindSessionsByWorktreePath(sessions, tt.path) {
+				ids = append(ids, session.NamespacedID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestConfig_CopyFromMain(t *testing.T) {
+	t.Run("accepts_strings_and_objects", func(t *testing.T) {
+		var cfg Config
+		data := `{"copy_from_main": [".env", {"path": "node_modules", "symlink": true}], "copy_from_main_max_bytes": 2048}`
+		require.NoError(t, json.Unmarshal([]byte(data), &cfg))
+
+		assert.Equal(t, []CopyFromMainEntry{
+			{Path: ".env"},
+			{Path: "node_modules", Symlink: true},
+		}, cfg.CopyFromMain)
+		assert.Equal(t, int64(2048), cfg.CopyFromMainMaxBytes)
+	})
+
+	t.Run("rejects_invalid_entries", func(t *testing.T) {
+		var cfg Config
+		assert.Error(t, json.Unmarshal([]byte(`{"copy_from_main": [42]}`), &cfg))
+	})
+
+	t.Run("merge_replaces_list", func(t *testing.T) {
+		base := DefaultConfig()
+		base.CopyFromMain = []CopyFromMainEntry{{Path: ".env"}}
+		merged := MergeConfig(base, &Config{CopyFromMain: []CopyFromMainEntry{{Path: "certs"}}, CopyFromMainMaxBytes: 10})
+
+		assert.Equal(t, []CopyFromMainEntry{{Path: "certs"}}, merged.CopyFromMain)
+		assert.Equal(t, int64(10), merged.CopyFromMainMaxBytes)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		tests := []struct {
+			path  string
+			valid bool
+		}{
+			{path: ".env", valid: true},
+			{path: "config/local.yaml", valid: true},
+			{path: ""},
+			{path: "/etc/passwd"},
+			{path: "../other"},
+			{path: "."},
+			{path: ".git/config"},
+		}
+
+		for _, tt := range tests {
+			cfg := DefaultConfig()
+			cfg.CopyFromMain = []CopyFromMainEntry{{Path: tt.path}}
+			err := validateConfig(cfg)
+			if tt.valid {
+				assert.NoError(t, err, tt.path)
+			} else {
+				assert.Error(t, err, tt.path)
+			}
+		}
+	})
+}
+
+func TestConfig_BuildCaches(t *testing.T) {
+	t.Run("accepts_presets_and_objects", func(t *testing.T) {
+		var cfg Config
+		data := `{"build_caches": ["go", {"name": "gradle", "env": "GRADLE_USER_HOME", "mount": "/cache/gradle"}]}`
+		require.NoError(t, json.Unmarshal([]byte(data), &cfg))
+
+		assert.Equal(t, []BuildCacheEntry{
+			{Name: "go"},
+			{Name: "gradle", Env: "GRADLE_USER_HOME", Mount: "/cache/gradle"},
+		}, cfg.BuildCaches)
+	})
+
+	t.Run("merge_replaces_list", func(t *testing.T) {
+		base := DefaultConfig()
+		base.BuildCaches = []BuildCacheEntry{{Name: "go"}}
+		merged := MergeConfig(base, &Config{BuildCaches: []BuildCacheEntry{{Name: "npm"}}})
+		assert.Equal(t, []BuildCacheEntry{{Name: "npm"}}, merged.BuildCaches)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			caches  []BuildCacheEntry
+			wantErr string
+		}{
+			{name: "presets", caches: []BuildCacheEntry{{Name: "go"}, {Name: "ccache", Mount: "/cache/ccache"}}},
+			{name: "custom", caches: []BuildCacheEntry{{Name: "gradle", Env: "GRADLE_USER_HOME"}}},
+			{name: "missing_name", caches: []BuildCacheEntry{{Env: "X"}}, wantErr: "build_caches[0].name is required"},
+			{name: "unknown_without_env", caches: []BuildCacheEntry{{Name: "gradle"}}, wantErr: "needs an env variable"},
+			{name: "duplicate", caches: []BuildCacheEntry{{Name: "go"}, {Name: "go"}}, wantErr: "duplicate name"},
+			{name: "relative_mount", caches: []BuildCacheEntry{{Name: "go", Mount: "cache"}}, wantErr: "must be an absolute path"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				cfg := DefaultConfig()
+				cfg.BuildCaches = tt.caches
+				err := validateConfig(cfg)
+				if tt.wantErr == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorContains(t, err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
+
+func TestSessionsChecksum(t *testing.T) {
+	sessionsPath := filepath.Join(t.TempDir(), "sessions.json")
+	sessions := []SessionMetadata{{NamespacedID: "test:checksum", Branch: "issue-test-checksum"}}
+
+	// A checksum file left by an earlier version is dropped on save
+	legacyChecksumPath := sessionsPath + ".sha256"
+	require.NoError(t, os.WriteFile(legacyChecksumPath, []byte("sha256:stale\n"), 0600))
+
+	require.NoError(t, SaveSessionsToPath(sessions, sessionsPath))
+	assert.NoFileExists(t, legacyChecksumPath)
+	data, err := os.ReadFile(sessionsPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "{\n  \"checksum\": \"sha256:"), "the checksum heads the sessions file")
+
+	loaded, err := LoadSessionsFromPath(sessionsPath)
+	require.NoError(t, err)
+	assert.Equal(t, sessions, loaded)
+
+	t.Run("truncated_write_is_detected", func(t *testing.T) {
+		data, err := os.ReadFile(sessionsPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(sessionsPath, data[:len(data)/2], 0644))
+
+		_, err = LoadSessionsFromPath(sessionsPath)
+		assert.ErrorIs(t, err, ErrSessionsChecksum)
+		assert.ErrorIs(t, VerifySessionsChecksum(sessionsPath), ErrSessionsChecksum)
+	})
+
+	t.Run("hand_edit_is_detected", func(t *testing.T) {
+		require.NoError(t, SaveSessionsToPath(sessions, sessionsPath))
+		data, err := os.ReadFile(sessionsPath)
+		require.NoError(t, err)
+		edited := strings.Replace(string(data), "issue-test-checksum", "issue-test-edited", 1)
+		require.NoError(t, os.WriteFile(sessionsPath, []byte(edited), 0644))
+
+		assert.ErrorIs(t, VerifySessionsChecksum(sessionsPath), ErrSessionsChecksum)
+		loaded, err := LoadSessionsFromPathUnverified(sessionsPath)
+		require.NoError(t, err)
+		assert.Equal(t, "issue-test-edited", loaded[0].Branch)
+	})
+
+	t.Run("saving_reseals", func(t *testing.T) {
+		require.NoError(t, SaveSessionsToPath(sessions, sessionsPath))
+		assert.NoError(t, VerifySessionsChecksum(sessionsPath))
+	})
+
+	t.Run("files_without_checksum_load", func(t *testing.T) {
+		legacyPath := filepath.Join(t.TempDir(), "sessions.json")
+		require.NoError(t, os.WriteFile(legacyPath, []byte(`[{"namespaced_id": "test:legacy"}]`), 0644))
+
+		loaded, err := LoadSessionsFromPath(legacyPath)
+		require.NoError(t, err)
+		assert.Equal(t, "test:legacy", loaded[0].NamespacedID)
+	})
+}
+
+func TestDedupeSessions(t *testing.T) {
+	sessions := []SessionMetadata{
+		{NamespacedID: "test:a", Branch: "old", LastActivity: "2025-01-01T10:00:00Z"},
+		{NamespacedID: "test:b", Branch: "only"},
+		{Branch: "no-id"},
+		{NamespacedID: "test:a", Branch: "new", LastActivity: "2025-01-02T10:00:00Z"},
+		{NamespacedID: "test:a", Branch: "older", CreatedAt: "2024-12-01T10:00:00Z"},
+		{Branch: "no-id"},
+	}
+
+	kept, duplicates := DedupeSessions(sessions)
+
+	assert.Equal(t, []SessionMetadata{
+		{NamespacedID: "test:a", Branch: "new", LastActivity: "2025-01-02T10:00:00Z"},
+		{NamespacedID: "test:b", Branch: "only"},
+		{Branch: "no-id"},
+		{Branch: "no-id"},
+	}, kept)
+	assert.Equal(t, []string{"old", "older"}, []string{duplicates[0].Branch, duplicates[1].Branch})
+
+	t.Run("later_entry_wins_ties", func(t *testing.T) {
+		kept, duplicates := DedupeSessions([]SessionMetadata{
+			{NamespacedID: "test:a", Branch: "first"},
+			{NamespacedID: "test:a", Branch: "second"},
+		})
+		assert.Equal(t, "second", kept[0].Branch)
+		assert.Equal(t, "first", duplicates[0].Branch)
+	})
+}
+
+func TestLoadConfig_ConfigFileOverride(t *testing.T) {
+	t.Cleanup(func() { paths.SetConfigFile("") })
+	configPath := filepath.Join(t.TempDir(), "team.json")
+
+	paths.SetConfigFile(configPath)
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "config file "+configPath+" does not exist")
+	assert.NoFileExists(t, configPath, "a missing --config file is not created")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"worktree_base_path": "/srv/worktrees"}`), 0600))
+	loaded, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "/srv/worktrees", loaded.WorktreeBasePath)
+}
+
+func TestSaveSessionsToPath_PrivateModes(t *testing.T) {
+	sessionsPath := filepath.Join(t.TempDir(), "state", "sessions.json")
+	require.NoError(t, SaveSessionsToPath([]SessionMetadata{
+		{NamespacedID: "test:dup", IssueTitle: "Rotate the deploy token", LastActivity: "2025-01-01T10:00:00Z"},
+		{NamespacedID: "test:dup", LastActivity: "2025-01-03T10:00:00Z"},
+	}, sessionsPath))
+
+	for _, path := range []string{sessionsPath, SessionsArchivePath(sessionsPath)} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), path)
+	}
+	info, err := os.Stat(filepath.Dir(sessionsPath))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+}
+
+func TestGetGlobalSessionsPath_XDGState(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Cleanup(func() { paths.SetXDGState(false) })
+
+	sessionsPath, err := GetGlobalSessionsPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "sbs", "sessions.json"), sessionsPath)
+
+	paths.SetXDGState(true)
+	sessionsPath, err = GetGlobalSessionsPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "state", "sbs", "sessions.json"), sessionsPath)
+
+	configPath, err := GlobalConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "sbs", "config.json"), configPath, "config stays in ~/.config/sbs")
+}
+
+func TestSaveSessionsToPath_ArchivesDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	sessionsPath := filepath.Join(dir, "sessions.json")
+
+	require.NoError(t, SaveSessionsToPath([]SessionMetadata{
+		{NamespacedID: "test:dup", Branch: "stale", LastActivity: "2025-01-01T10:00:00Z"},
+		{NamespacedID: "test:dup", Branch: "current", LastActivity: "2025-01-03T10:00:00Z"},
+	}, sessionsPath))
+
+	loaded, err := LoadSessionsFromPath(sessionsPath)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "current", loaded[0].Branch)
+
+	archived, err := LoadArchivedSessions(SessionsArchivePath(sessionsPath))
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "stale", archived[0].Session.Branch)
+	assert.Equal(t, "duplicate namespaced_id", archived[0].Reason)
+	assert.NotEmpty(t, archived[0].ArchivedAt)
+
+	// Saving clean sessions leaves the archive alone
+	require.NoError(t, SaveSessionsToPath(loaded, sessionsPath))
+	archived, err = LoadArchivedSessions(SessionsArchivePath(sessionsPath))
+	require.NoError(t, err)
+	assert.Len(t, archived, 1)
+}
+
+func TestConfig_SandboxPoolSize(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{SandboxPoolSize: 2})
+	assert.Equal(t, 2, merged.SandboxPoolSize)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.SandboxPoolSize = -1
+	assert.Error(t, validateConfig(merged))
+}
+
+func TestConfig_ReadinessChecks(t *testing.T) {
+	checks := []ReadinessCheck{
+		{Command: "curl -sf localhost:3000/health", TimeoutSecs: 60},
+		{Port: 5432},
+		{File: "tmp/ready"},
+	}
+	merged := MergeConfig(DefaultConfig(), &Config{ReadinessChecks: checks})
+	assert.Equal(t, checks, merged.ReadinessChecks)
+	assert.NoError(t, validateConfig(merged))
+
+	invalid := []ReadinessCheck{
+		{},
+		{Command: "true", Port: 80},
+		{Port: 70000},
+		{File: "ready", Host: "db"},
+		{File: "ready", TimeoutSecs: MaxReadinessTimeoutSecs + 1},
+	}
+	for _, check := range invalid {
+		merged.ReadinessChecks = []ReadinessCheck{check}
+		assert.Error(t, validateConfig(merged), "%+v", check)
+	}
+}
+
+func TestConfig_ProtectedBranchesAndWorktrees(t *testing.T) {
+	base := DefaultConfig()
+	base.ProtectedBranches = []string{"develop"}
+	merged := MergeConfig(base, &Config{
+		ProtectedBranches:  []string{"release/*", "develop"},
+		ProtectedWorktrees: []string{"~/.sbs-worktrees/*integration*"},
+	})
+	assert.Equal(t, []string{"develop", "release/*"}, merged.ProtectedBranches, "repository patterns add to the global ones")
+	assert.Equal(t, []string{"~/.sbs-worktrees/*integration*"}, merged.ProtectedWorktrees)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.ProtectedBranches = []string{"release/["}
+	assert.Error(t, validateConfig(merged))
+
+	merged.ProtectedBranches = nil
+	merged.ProtectedWorktrees = []string{"relative/path"}
+	assert.Error(t, validateConfig(merged))
+}
+
+func TestConfig_PruneEmptyWorktreeDirs(t *testing.T) {
+	assert.False(t, DefaultConfig().PruneEmptyWorktreeDirs)
+	merged := MergeConfig(DefaultConfig(), &Config{PruneEmptyWorktreeDirs: true})
+	assert.True(t, merged.PruneEmptyWorktreeDirs)
+}
+
+func TestConfig_SandboxRequired(t *testing.T) {
+	assert.False(t, DefaultConfig().SandboxRequired)
+	global := DefaultConfig()
+	global.SandboxRequired = true
+	assert.True(t, MergeConfig(global, &Config{}).SandboxRequired, "a repository can't turn it off")
+	assert.True(t, MergeConfig(DefaultConfig(), &Config{SandboxRequired: true}).SandboxRequired)
+}
+
+func TestConfig_GitExecutable(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{GitExecutable: "/opt/git/bin/git"})
+	assert.Equal(t, "/opt/git/bin/git", merged.GitExecutable)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.GitExecutable = " git"
+	assert.Error(t, validateConfig(merged))
+}
+
+func TestConfig_NameScope(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{NameScope: "user"})
+	assert.Equal(t, "user", merged.NameScope)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.NameScope = "team a"
+	assert.Error(t, validateConfig(merged))
+}
+
+func TestConfig_GitHubClient(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{GitHubClient: "api", GitHubAPIURL: "https://github.example.com/api/v3"})
+	assert.Equal(t, "api", merged.GitHubClient)
+	assert.Equal(t, "https://github.example.com/api/v3", merged.GitHubAPIURL)
+	assert.Equal(t, "api", MergeConfig(merged, &Config{}).GitHubClient, "an unset override keeps it")
+
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{name: "gh", modify: func(c *Config) { c.GitHubClient = "gh" }},
+		{name: "api", modify: func(c *Config) { c.GitHubClient = "api" }},
+		{name: "unknown_client", modify: func(c *Config) { c.GitHubClient = "octokit" }, errContains: "github_client"},
+		{name: "relative_api_url", modify: func(c *Config) { c.GitHubAPIURL = "api.github.com" }, errContains: "github_api_url"},
+		{name: "github_timeout", modify: func(c *Config) { c.CommandTimeouts = map[string]int{"github": 10} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+			err := validateConfig(config)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestConfig_ShowDiffstat(t *testing.T) {
+	assert.False(t, DefaultConfig().ShowDiffstat)
+	merged := MergeConfig(DefaultConfig(), &Config{ShowDiffstat: true})
+	assert.True(t, merged.ShowDiffstat)
+	assert.True(t, MergeConfig(merged, &Config{}).ShowDiffstat, "an unset override keeps it")
+}
+
+func TestConfig_SessionExpiry(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{CleanAfterDays: 14, ExpiryWarningDays: 3, ExpiryNotify: true})
+	assert.Equal(t, 14, merged.CleanAfterDays)
+	assert.Equal(t, 3, merged.ExpiryWarningDays)
+	assert.True(t, merged.ExpiryNotify)
+	assert.Equal(t, 14, MergeConfig(merged, &Config{}).CleanAfterDays, "an unset override keeps it")
+
+	for _, tt := range []struct {
+		cfg     Config
+		problem string
+	}{
+		{Config{CleanAfterDays: -1}, "clean_after_days cannot be negative"},
+		{Config{ExpiryWarningDays: -1}, "expiry_warning_days cannot be negative"},
+		{Config{CleanAfterDays: 2, ExpiryWarningDays: 2}, "expiry_warning_days must be less than clean_after_days"},
+	} {
+		cfg := DefaultConfig()
+		cfg.CleanAfterDays, cfg.ExpiryWarningDays = tt.cfg.CleanAfterDays, tt.cfg.ExpiryWarningDays
+		err := validateConfig(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tt.problem)
+	}
+	cfg := DefaultConfig()
+	cfg.CleanAfterDays, cfg.ExpiryWarningDays = 7, 2
+	assert.NoError(t, validateConfig(cfg))
+}
+
+func TestConfig_SnapshotBeforeClean(t *testing.T) {
+	assert.False(t, DefaultConfig().SnapshotBeforeClean)
+	merged := MergeConfig(DefaultConfig(), &Config{SnapshotBeforeClean: true})
+	assert.True(t, merged.SnapshotBeforeClean)
+	assert.True(t, MergeConfig(merged, &Config{}).SnapshotBeforeClean, "an unset override keeps it")
+}
+
+func TestConfig_PRChecks(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{PRCheckIntervalSecs: 15, PRCheckTimeoutMins: 90, PRWaitRequiredChecks: true})
+	assert.Equal(t, 15, merged.PRCheckIntervalSecs)
+	assert.Equal(t, 90, merged.PRCheckTimeoutMins)
+	assert.True(t, merged.PRWaitRequiredChecks)
+	assert.Equal(t, 15, MergeConfig(merged, &Config{}).PRCheckIntervalSecs, "an unset override keeps it")
+
+	for _, tt := range []struct {
+		interval, timeout int
+		problem           string
+	}{
+		{-1, 0, "pr_check_interval_seconds cannot be negative"},
+		{2, 0, "pr_check_interval_seconds must be at least 5"},
+		{0, -1, "pr_check_timeout_minutes cannot be negative"},
+	} {
+		cfg := DefaultConfig()
+		cfg.PRCheckIntervalSecs, cfg.PRCheckTimeoutMins = tt.interval, tt.timeout
+		err := validateConfig(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tt.problem)
+	}
+	cfg := DefaultConfig()
+	cfg.PRCheckIntervalSecs, cfg.PRCheckTimeoutMins = 5, 30
+	assert.NoError(t, validateConfig(cfg))
+}
+
+func TestConfig_MessagesAndTerms(t *testing.T) {
+	base := &Config{Terms: map[string]string{"work_item": "ticket", "work_items": "tickets"}}
+	merged := MergeConfig(base, &Config{
+		Messages: map[string]string{"start.ready": "Ready: {{.ID}}"},
+		Terms:    map[string]string{"work_item": "story"},
+	})
+	assert.Equal(t, map[string]string{"work_item": "story", "work_items": "tickets"}, merged.Terms)
+	assert.Equal(t, "Ready: {{.ID}}", merged.Messages["start.ready"])
+	assert.Equal(t, "ticket", base.Terms["work_item"], "merging leaves the base untouched")
+
+	cfg := DefaultConfig()
+	cfg.Messages = map[string]string{"start.bogus": "x", "start.ready": "{{.ID"}
+	cfg.Terms = map[string]string{"ticket": "x"}
+	err := validateConfig(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `messages has unknown message "start.bogus"`)
+	assert.Contains(t, err.Error(), "messages.start.ready is not a valid template")
+	assert.Contains(t, err.Error(), `terms has unknown term "ticket"`)
+	assert.NotContains(t, err.Error(), "\n")
+}
+
+func TestSessionMetadata_LastUsed(t *testing.T) {
+	assert.True(t, SessionMetadata{}.LastUsed().IsZero())
+	created := SessionMetadata{CreatedAt: "2026-01-02T03:04:05Z"}
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), created.LastUsed().UTC())
+	created.LastActivity = "2026-02-01T00:00:00Z"
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), created.LastUsed().UTC(), "last_activity wins over created_at")
+}
+
+func TestConfig_XDGState(t *testing.T) {
+	assert.False(t, DefaultConfig().XDGState)
+	merged := MergeConfig(DefaultConfig(), &Config{XDGState: true})
+	assert.True(t, merged.XDGState)
+	assert.True(t, MergeConfig(merged, &Config{}).XDGState, "an unset override keeps it")
+}
+
+func TestConfig_WaitingBell(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{WaitingBell: true})
+	assert.True(t, merged.WaitingBell)
+	assert.True(t, MergeConfig(merged, &Config{}).WaitingBell, "an unset override keeps it")
+}
+
+func TestConfig_LoghookLimits(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{LoghookMinIntervalSecs: 5, LoghookMaxOutputBytes: 4096})
+	assert.Equal(t, 5, merged.LoghookMinIntervalSecs)
+	assert.Equal(t, 4096, merged.LoghookMaxOutputBytes)
+	assert.Equal(t, 0, merged.LoghookMaxIntervalSecs)
+
+	cfg := DefaultConfig()
+	cfg.LoghookMinIntervalSecs, cfg.LoghookMaxIntervalSecs = 30, 10
+	assert.ErrorContains(t, validateConfig(cfg), "loghook_min_interval_seconds must not exceed")
+
+	cfg = DefaultConfig()
+	cfg.LoghookMaxOutputBytes = 100
+	assert.ErrorContains(t, validateConfig(cfg), "loghook_max_output_bytes")
+}
+
+func TestConfig_LoghookArchive(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{LoghookArchive: "local", LoghookArchiveKeep: 20})
+	assert.Equal(t, "local", merged.LoghookArchive)
+	assert.Equal(t, 20, merged.LoghookArchiveKeep)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.LoghookArchive = "s3"
+	assert.ErrorContains(t, validateConfig(merged), "loghook_archive must be one of: local, command")
+
+	merged.LoghookArchive = "command"
+	assert.ErrorContains(t, validateConfig(merged), "loghook_archive_command is required")
+	merged.LoghookArchiveCommand = "cat > /dev/null"
+	assert.NoError(t, validateConfig(merged))
+
+	merged.LoghookArchiveMaxBytes = -1
+	assert.ErrorContains(t, validateConfig(merged), "loghook_archive_max_bytes")
+}
+
+func TestConfig_AttachEnvironment(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{AttachEnvironment: []string{"SSH_AUTH_SOCK", "KRB5CCNAME"}})
+	assert.Equal(t, []string{"SSH_AUTH_SOCK", "KRB5CCNAME"}, merged.AttachEnvironment)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.AttachEnvironment = []string{"none"}
+	assert.NoError(t, validateConfig(merged))
+	merged.AttachEnvironment = []string{"none", "DISPLAY"}
+	assert.ErrorContains(t, validateConfig(merged), "can't combine \"none\"")
+	merged.AttachEnvironment = []string{"SSH AUTH"}
+	assert.ErrorContains(t, validateConfig(merged), "invalid variable name")
+}
+
+func TestConfig_BranchTemplate(t *testing.T) {
+	merged := MergeConfig(DefaultConfig(), &Config{BranchTemplate: "feature/{source}-{id}"})
+	assert.Equal(t, "feature/{source}-{id}", merged.BranchTemplate)
+	assert.NoError(t, validateConfig(merged))
+
+	merged.BranchTemplate = "{id}-{title}"
+	assert.ErrorContains(t, validateConfig(merged), "branch_template")
+}
+
+func TestUpdateSessionsAtPath_ConcurrentUpdatesAreKept(t *testing.T) {
+	sessionsPath := filepath.Join(t.TempDir(), "sessions.json")
+
+	// Each writer adds its own session, as concurrent sbs start runs would
+	const writers = 20
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		id := fmt.Sprintf("test:w%d", i)
+		go func() {
+			errs <- UpdateSessionsAtPath(sessionsPath, func(sessions []SessionMetadata) ([]SessionMetadata, error) {
+				return append(sessions, SessionMetadata{NamespacedID: id}), nil
+			})
+		}()
+	}
+	for i := 0; i < writers; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	sessions, err := LoadSessionsFromPath(sessionsPath)
+	require.NoError(t, err)
+	assert.Len(t, sessions, writers, "no update may be lost")
+}
+
+func TestUpdateSessionsAtPath_ErrorLeavesFileUnchanged(t *testing.T) {
+	sessionsPath := filepath.Join(t.TempDir(), "sessions.json")
+	require.NoError(t, SaveSessionsToPath([]SessionMetadata{{NamespacedID: "test:a"}}, sessionsPath))
+
+	err := UpdateSessionsAtPath(sessionsPath, func(sessions []SessionMetadata) ([]SessionMetadata, error) {
+		return nil, assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+
+	sessions, err := LoadSessionsFromPath(sessionsPath)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestDescribeSessionChanges(t *testing.T) {
+	before := []SessionMetadata{
+		{NamespacedID: "github:1", Status: "active"},
+		{NamespacedID: "github:2", Status: "active", Branch: "issue-github-2"},
+		{NamespacedID: "github:3"},
+	}
+	after := []SessionMetadata{
+		{NamespacedID: "github:1", Status: "active"},
+		{NamespacedID: "github:2", Status: "stopped", Branch: "issue-github-2-renamed"},
+		{NamespacedID: "github:4"},
+	}
+
+	assert.Equal(t, "added github:4; changed github:2 (branch, status); removed github:3",
+		DescribeSessionChanges(before, after))
+	assert.Equal(t, "no changes", DescribeSessionChanges(before, before))
+}
+
+func TestLoadConfigWithWorktree(t *testing.T) {
+	writeJSON := func(t *testing.T, path string, value interface{}) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+	}
+
+	setup := func(t *testing.T) (string, string) {
+		t.Setenv("HOME", t.TempDir())
+		repoRoot, worktreePath := t.TempDir(), t.TempDir()
+		writeJSON(t, RepositoryConfigPath(repoRoot), map[string]interface{}{
+			"tmux_command":                 "repo-agent",
+			"log_refresh_interval_seconds": 9,
+		})
+		return repoRoot, worktreePath
+	}
+
+	t.Run("worktree_overrides_repository", func(t *testing.T) {
+		repoRoot, worktreePath := setup(t)
+		writeJSON(t, RepositoryConfigPath(worktreePath), map[string]interface{}{"tmux_command": "session-agent"})
+
+		cfg, err := LoadConfigWithWorktree(repoRoot, worktreePath)
+		require.NoError(t, err)
+		assert.Equal(t, "session-agent", cfg.TmuxCommand)
+		assert.Equal(t, 9, cfg.LogRefreshIntervalSecs, "settings the worktree leaves out come from the repository")
+	})
+
+	t.Run("no_worktree_config", func(t *testing.T) {
+		repoRoot, worktreePath := setup(t)
+
+		cfg, err := LoadConfigWithWorktree(repoRoot, worktreePath)
+		require.NoError(t, err)
+		assert.Equal(t, "repo-agent", cfg.TmuxCommand)
+
+		worktreeConfig, err := LoadWorktreeConfig(repoRoot, worktreePath)
+		require.NoError(t, err)
+		assert.Nil(t, worktreeConfig)
+	})
+
+	t.Run("main_checkout_is_not_a_worktree", func(t *testing.T) {
+		repoRoot, _ := setup(t)
+
+		worktreeConfig, err := LoadWorktreeConfig(repoRoot, repoRoot+string(filepath.Separator))
+		require.NoError(t, err)
+		assert.Nil(t, worktreeConfig)
+	})
+
+	t.Run("invalid_worktree_config", func(t *testing.T) {
+		repoRoot, worktreePath := setup(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(worktreePath, ".sbs"), 0755))
+		require.NoError(t, os.WriteFile(RepositoryConfigPath(worktreePath), []byte("{"), 0644))
+
+		cfg, err := LoadConfigWithWorktree(repoRoot, worktreePath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), RepositoryConfigPath(worktreePath))
+		require.NotNil(t, cfg, "the repository configuration is returned to fall back to")
+		assert.Equal(t, "repo-agent", cfg.TmuxCommand)
+	})
+}
+
+func TestValidateRepositoryConfig(t *testing.T) {
+	repoRoot := t.TempDir()
+	assert.NoError(t, ValidateRepositoryConfig(nil, repoRoot), "no repository config")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, ".sbs"), 0755))
+	configPath := RepositoryConfigPath(repoRoot)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"sandbox_pool_size": 2}`), 0644))
+	assert.NoError(t, ValidateRepositoryConfig(DefaultConfig(), repoRoot))
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"messages": {"start.bogus": "x"}}`), 0644))
+	assert.Error(t, ValidateRepositoryConfig(DefaultConfig(), repoRoot))
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{`), 0644))
+	assert.ErrorContains(t, ValidateRepositoryConfig(DefaultConfig(), repoRoot), "failed to parse")
+}
+
+func TestAutomationAllow(t *testing.T) {
+	assert.True(t, (&Config{}).AutomationAllowed("clean"), "unset allows every operation")
+
+	cfg := &Config{AutomationAllow: []string{"stop", "move"}}
+	assert.True(t, cfg.AutomationAllowed("stop"))
+	assert.False(t, cfg.AutomationAllowed("clean"))
+	assert.False(t, (&Config{AutomationAllow: []string{AutomationNone}}).AutomationAllowed("stop"))
+
+	merged := MergeConfig(&Config{AutomationAllow: []string{"stop"}}, &Config{AutomationAllow: []string{"none"}})
+	assert.Equal(t, []string{"none"}, merged.AutomationAllow)
+
+	invalid := DefaultConfig()
+	invalid.AutomationAllow = []string{"stop", "rm"}
+	assert.ErrorContains(t, validateConfig(invalid), `automation_allow has unknown operation "rm"`)
+}
+
+func TestBranchBehindConfig(t *testing.T) {
+	merged := MergeConfig(&Config{BranchBehindCommits: 30, BranchBehindDays: 7}, &Config{BranchBehindDays: -1})
+	assert.Equal(t, 30, merged.BranchBehindCommits)
+	assert.Equal(t, -1, merged.BranchBehindDays, "a repository can turn a threshold off")
+
+	invalid := DefaultConfig()
+	invalid.BranchBehindCommits = -5
+	assert.ErrorContains(t, validateConfig(invalid), "branch_behind_commits must be positive, or -1 to disable")
+}
+
+func TestNotifiers(t *testing.T) {
+	valid := DefaultConfig()
+	valid.Notifiers = []NotifierConfig{
+		{Type: "slack", URL: "https://hooks.slack.com/services/T/B/x", Events: []string{"session-waiting"}, Template: "{{.WorkItem}} needs you"},
+		{Type: "command", Command: "notify-send sbs \"$SBS_MESSAGE\""},
+	}
+	assert.NoError(t, validateConfig(valid))
+
+	merged := MergeConfig(valid, &Config{Notifiers: []NotifierConfig{{Type: "webhook", URL: "https://example.com"}}})
+	assert.Equal(t, valid.Notifiers, merged.Notifiers, "repository configs can't add notifiers")
+
+	invalid := DefaultConfig()
+	invalid.Notifiers = []NotifierConfig{
+		{Type: "webhook", URL: "example.com"},
+		{Type: "command"},
+		{Type: "email"},
+		{Type: "slack", URL: "https://hooks.slack.com", Events: []string{"done"}, Template: "{{.WorkItem"},
+	}
+	err := validateConfig(invalid)
+	assert.ErrorContains(t, err, "notifiers[0].url must be an http:// or https:// URL")
+	assert.ErrorContains(t, err, "notifiers[1].command is required")
+	assert.ErrorContains(t, err, "notifiers[2].type must be one of: webhook, slack, command")
+	assert.ErrorContains(t, err, `notifiers[3].events has unknown event "done"`)
+	assert.ErrorContains(t, err, "notifiers[3].template is invalid")
+}
+
+func TestPurgeArchivedSessions(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "sessions-archive.json")
+	removed, err := PurgeArchivedSessions(archivePath, func(ArchivedSession) bool { return true })
+	require.NoError(t, err, "a missing archive has nothing to purge")
+	assert.Zero(t, removed)
+
+	require.NoError(t, ArchiveSessions(archivePath, []SessionMetadata{{NamespacedID: "github:1"}, {NamespacedID: "github:2"}}, ArchiveReasonCleaned))
+	removed, err = PurgeArchivedSessions(archivePath, func(entry ArchivedSession) bool {
+		return entry.Session.NamespacedID == "github:1"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	archived, err := LoadArchivedSessions(archivePath)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "github:2", archived[0].Session.NamespacedID)
+}
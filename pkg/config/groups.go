@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RepositoryGroupNames returns the names of the configured repository groups, sorted
+func (c *Config) RepositoryGroupNames() []string {
+	names := make([]string, 0, len(c.RepositoryGroups))
+	for name := range c.RepositoryGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RepositoryGroup returns the members of the named group, or an error
+// listing the configured groups when there is no such group
+func (c *Config) RepositoryGroup(name string) ([]string, error) {
+	members, ok := c.RepositoryGroups[name]
+	if !ok {
+		if len(c.RepositoryGroups) == 0 {
+			return nil, fmt.Errorf("unknown repository group %q: no repository_groups are configured", name)
+		}
+		return nil, fmt.Errorf("unknown repository group %q (configured: %s)", name, strings.Join(c.RepositoryGroupNames(), ", "))
+	}
+	return members, nil
+}
+
+// InRepositoryGroup reports whether a session's repository is one of
+// members. Members containing a path separator or starting with ~ are
+// repository roots; others are repository names.
+func InRepositoryGroup(session SessionMetadata, members []string) bool {
+	for _, member := range members {
+		if !strings.ContainsRune(member, '/') && !strings.HasPrefix(member, "~") {
+			if member == session.RepositoryName {
+				return true
+			}
+			continue
+		}
+		if session.RepositoryRoot != "" && expandGroupPath(member) == filepath.Clean(session.RepositoryRoot) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterRepositoryGroup returns the sessions whose repository is one of members
+func FilterRepositoryGroup(sessions []SessionMetadata, members []string) []SessionMetadata {
+	var filtered []SessionMetadata
+	for _, session := range sessions {
+		if InRepositoryGroup(session, members) {
+			filtered = append(filtered, session)
+		}
+	}
+	return filtered
+}
+
+// expandGroupPath expands a leading ~ and cleans a group member path
+func expandGroupPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryGroups(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	cfg := &Config{RepositoryGroups: map[string][]string{
+		"frontend": {"web", "~/src/design-system"},
+		"backend":  {"/srv/api/"},
+	}}
+	assert.Equal(t, []string{"backend", "frontend"}, cfg.RepositoryGroupNames())
+
+	members, err := cfg.RepositoryGroup("frontend")
+	require.NoError(t, err)
+	sessions := []SessionMetadata{
+		{NamespacedID: "github:1", RepositoryName: "web", RepositoryRoot: "/home/dev/code/web"},
+		{NamespacedID: "github:2", RepositoryName: "design", RepositoryRoot: "/home/dev/src/design-system"},
+		{NamespacedID: "github:3", RepositoryName: "api", RepositoryRoot: "/srv/api"},
+		{NamespacedID: "github:4", RepositoryName: "web-legacy", RepositoryRoot: "/home/dev/web"},
+	}
+	var ids []string
+	for _, session := range FilterRepositoryGroup(sessions, members) {
+		ids = append(ids, session.NamespacedID)
+	}
+	assert.Equal(t, []string{"github:1", "github:2"}, ids, "members match repository names or roots")
+	assert.True(t, InRepositoryGroup(sessions[2], cfg.RepositoryGroups["backend"]), "paths are cleaned")
+
+	_, err = cfg.RepositoryGroup("mobile")
+	assert.EqualError(t, err, `unknown repository group "mobile" (configured: backend, frontend)`)
+	_, err = (&Config{}).RepositoryGroup("mobile")
+	assert.ErrorContains(t, err, "no repository_groups are configured")
+}
+
+func TestRepositoryGroups_MergeAndValidate(t *testing.T) {
+	base := &Config{RepositoryGroups: map[string][]string{"frontend": {"web"}, "backend": {"api"}}}
+	merged := MergeConfig(base, &Config{RepositoryGroups: map[string][]string{"frontend": {"web", "docs"}}})
+	assert.Equal(t, map[string][]string{"frontend": {"web", "docs"}, "backend": {"api"}}, merged.RepositoryGroups)
+
+	invalid := DefaultConfig()
+	invalid.RepositoryGroups = map[string][]string{"empty": {}, "blank": {" "}}
+	err := validateConfig(invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repository_groups.empty must list at least one repository")
+	assert.Contains(t, err.Error(), "repository_groups.blank contains an empty repository")
+}
@@ -12,6 +12,23 @@ import (
 type InputSourceConfig struct {
 	Type     string                 `json:"type"`     // github, test, jira, etc.
 	Settings map[string]interface{} `json:"settings"` // source-specific settings
+
+	// Settings of other sources by type, used when a namespaced ID such as
+	// youtrack:PROJ-42 starts a session from a source that isn't the primary one
+	Sources map[string]map[string]interface{} `json:"sources,omitempty"`
+}
+
+// ForSource returns the configuration of sourceType: this one for the primary
+// type, otherwise one with the settings listed for it under sources
+func (c *InputSourceConfig) ForSource(sourceType string) *InputSourceConfig {
+	if sourceType == c.Type {
+		return c
+	}
+	settings := c.Sources[sourceType]
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	return &InputSourceConfig{Type: sourceType, Settings: settings}
 }
 
 // DefaultInputSourceConfig returns the default configuration (GitHub)
@@ -104,5 +121,15 @@ func validateInputSourceConfig(config *InputSourceConfig) error {
 		config.Settings = make(map[string]interface{})
 	}
 
+	// The primary source's settings live in settings
+	for sourceType := range config.Sources {
+		if strings.TrimSpace(sourceType) == "" {
+			return fmt.Errorf("sources cannot have an empty type")
+		}
+		if sourceType == config.Type {
+			return fmt.Errorf("sources lists the primary type %s; put its settings in settings", sourceType)
+		}
+	}
+
 	return nil
 }
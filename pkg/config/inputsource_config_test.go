@@ -232,3 +232,28 @@ func TestInputSourceConfig_SaveAndLoad(t *testing.T) {
 	assert.Equal(t, float64(42), loadedConfig.Settings["number"]) // JSON unmarshals numbers as float64
 	assert.Equal(t, true, loadedConfig.Settings["boolean"])
 }
+
+func TestInputSourceConfig_Sources(t *testing.T) {
+	tempDir := t.TempDir()
+	sbsDir := filepath.Join(tempDir, ".sbs")
+	require.NoError(t, os.MkdirAll(sbsDir, 0755))
+	configPath := filepath.Join(sbsDir, "input-source.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+        "type": "github",
+        "settings": {"repository": "auto-detect"},
+        "sources": {"youtrack": {"url": "https://yt.example.com"}}
+    }`), 0644))
+
+	config, err := LoadInputSourceConfig(tempDir)
+	require.NoError(t, err)
+
+	assert.Same(t, config, config.ForSource("github"))
+	youtrack := config.ForSource("youtrack")
+	assert.Equal(t, "youtrack", youtrack.Type)
+	assert.Equal(t, map[string]interface{}{"url": "https://yt.example.com"}, youtrack.Settings)
+	assert.Equal(t, map[string]interface{}{}, config.ForSource("linear").Settings, "unlisted sources get no settings")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"type": "github", "sources": {"github": {}}}`), 0644))
+	_, err = LoadInputSourceConfig(tempDir)
+	assert.ErrorContains(t, err, "sources lists the primary type github")
+}
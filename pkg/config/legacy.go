@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sbs/pkg/paths"
+	"sbs/pkg/trace"
+)
+
+// legacySessionsPattern matches the per-repository sessions files older sbs
+// versions kept in the config directory
+var legacySessionsPattern = filepath.Join("repos", "*", "sessions.json")
+
+// LegacyBackupSuffix is appended to a legacy sessions file once its sessions
+// are merged into the global sessions file
+const LegacyBackupSuffix = ".migrated"
+
+// LegacyMigration describes a merge of legacy sessions files
+type LegacyMigration struct {
+	Files    []string // Legacy files merged and renamed to their backups
+	Imported int      // Sessions the global file didn't have yet
+	Failed   []error  // Legacy files left in place because they couldn't be read
+}
+
+// LegacySessionFiles returns the legacy per-repository sessions files in the
+// config and state directories, sorted
+func LegacySessionFiles() ([]string, error) {
+	dirs := make(map[string]bool)
+	for _, dir := range []func() (string, error){paths.ConfigDir, paths.StateDir} {
+		path, err := dir()
+		if err != nil {
+			return nil, err
+		}
+		dirs[path] = true
+	}
+
+	var files []string
+	for dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, legacySessionsPattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// MigrateLegacySessions merges the sessions of every legacy per-repository
+// sessions file into the global sessions file, which keeps the most recently
+// active entry per work item and archives the others. Each merged file is
+// renamed with LegacyBackupSuffix, so the migration runs once.
+func MigrateLegacySessions() (LegacyMigration, error) {
+	var migration LegacyMigration
+	files, err := LegacySessionFiles()
+	if err != nil || len(files) == 0 {
+		return migration, err
+	}
+
+	var legacy []SessionMetadata
+	var merged []string
+	for _, file := range files {
+		sessions, err := LoadSessionsFromPathUnverified(file)
+		if err != nil {
+			migration.Failed = append(migration.Failed, fmt.Errorf("failed to read legacy sessions %s: %w", file, err))
+			continue
+		}
+		repoName := filepath.Base(filepath.Dir(file))
+		for _, session := range sessions {
+			if normalizeLegacySession(&session, repoName) {
+				legacy = append(legacy, session)
+			}
+		}
+		merged = append(merged, file)
+	}
+	if len(merged) == 0 {
+		return migration, nil
+	}
+
+	err = UpdateSessions(func(current []SessionMetadata) ([]SessionMetadata, error) {
+		known := make(map[string]bool, len(current))
+		for _, session := range current {
+			known[session.NamespacedID] = true
+		}
+		for _, session := range legacy {
+			if !known[session.NamespacedID] {
+				known[session.NamespacedID] = true
+				migration.Imported++
+			}
+		}
+		return append(current, legacy...), nil
+	})
+	if err != nil {
+		return migration, fmt.Errorf("failed to merge legacy sessions: %w", err)
+	}
+
+	for _, file := range merged {
+		if err := os.Rename(file, file+LegacyBackupSuffix); err != nil {
+			migration.Failed = append(migration.Failed, fmt.Errorf("failed to back up legacy sessions %s: %w", file, err))
+			continue
+		}
+		trace.StateChange("migrate legacy sessions", file, "renamed to "+filepath.Base(file)+LegacyBackupSuffix)
+		migration.Files = append(migration.Files, file)
+	}
+	return migration, nil
+}
+
+// normalizeLegacySession fills in the fields older versions didn't record:
+// they only knew GitHub issues, by number. It reports whether the session
+// can be identified at all.
+func normalizeLegacySession(session *SessionMetadata, repoName string) bool {
+	if session.NamespacedID == "" {
+		if session.IssueNumber <= 0 {
+			return false
+		}
+		session.NamespacedID = fmt.Sprintf("github:%d", session.IssueNumber)
+	}
+	if session.SourceType == "" {
+		session.SourceType, _, _ = strings.Cut(session.NamespacedID, ":")
+	}
+	if session.RepositoryName == "" {
+		session.RepositoryName = repoName
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateLegacySessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	configDir := filepath.Join(home, ".config", "sbs")
+
+	require.NoError(t, SaveSessions([]SessionMetadata{
+		{NamespacedID: "github:1", RepositoryName: "web", LastActivity: "2026-03-04T12:00:00Z"},
+	}))
+	webLegacy := filepath.Join(configDir, "repos", "web", "sessions.json")
+	writeJSONFile(t, webLegacy, `[
+		{"issue_number": 1, "branch": "issue-1-old", "last_activity": "2026-01-01T12:00:00Z"},
+		{"issue_number": 2, "branch": "issue-2", "last_activity": "2026-01-02T12:00:00Z"},
+		{"issue_title": "no way to tell which work item"}
+	]`)
+	writeJSONFile(t, filepath.Join(configDir, "repos", "api", "sessions.json"),
+		`[{"namespaced_id": "test:spike", "repository_name": "api-server"}]`)
+	brokenLegacy := filepath.Join(configDir, "repos", "broken", "sessions.json")
+	writeJSONFile(t, brokenLegacy, `[{`)
+
+	migration, err := MigrateLegacySessions()
+	require.NoError(t, err)
+	assert.Equal(t, 2, migration.Imported)
+	assert.Len(t, migration.Files, 2)
+	require.Len(t, migration.Failed, 1)
+	assert.ErrorContains(t, migration.Failed[0], brokenLegacy)
+
+	sessions, err := LoadSessions()
+	require.NoError(t, err)
+	byID := make(map[string]SessionMetadata)
+	for _, session := range sessions {
+		byID[session.NamespacedID] = session
+	}
+	assert.Len(t, byID, 3)
+	assert.Equal(t, "2026-03-04T12:00:00Z", byID["github:1"].LastActivity, "the more recently active entry wins")
+	assert.Equal(t, SessionMetadata{
+		IssueNumber: 2, NamespacedID: "github:2", SourceType: "github", RepositoryName: "web",
+		Branch: "issue-2", LastActivity: "2026-01-02T12:00:00Z",
+	}, byID["github:2"])
+	assert.Equal(t, "api-server", byID["test:spike"].RepositoryName)
+	assert.Equal(t, "test", byID["test:spike"].SourceType)
+
+	archived, err := LoadArchivedSessions(SessionsArchivePath(filepath.Join(configDir, "sessions.json")))
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "issue-1-old", archived[0].Session.Branch, "the older duplicate is archived")
+
+	assert.NoFileExists(t, webLegacy)
+	assert.FileExists(t, webLegacy+LegacyBackupSuffix)
+	assert.FileExists(t, brokenLegacy, "unreadable files are left for the user")
+
+	require.NoError(t, os.Remove(brokenLegacy))
+	migration, err = MigrateLegacySessions()
+	require.NoError(t, err)
+	assert.Equal(t, LegacyMigration{}, migration, "the migration runs once")
+}
@@ -152,3 +152,69 @@ func LoadConfigFromPath(configPath string) (*Config, error) {
 
 	return &config, nil
 }
+
+func TestConfig_LogHighlightRules(t *testing.T) {
+	t.Run("load_rules_from_json", func(t *testing.T) {
+		jsonData := `{
+			"worktree_base_path": "~/.sbs-worktrees",
+			"log_highlight_rules": [
+				{"pattern": "\\bPASS\\b", "color": "#04B575", "bold": true},
+				{"pattern": "deprecated", "background": "238", "underline": true}
+			]
+		}`
+
+		var config Config
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &config))
+
+		require.Len(t, config.LogHighlightRules, 2)
+		assert.Equal(t, LogHighlightRule{Pattern: `\bPASS\b`, Color: "#04B575", Bold: true}, config.LogHighlightRules[0])
+		assert.Equal(t, LogHighlightRule{Pattern: "deprecated", Background: "238", Underline: true}, config.LogHighlightRules[1])
+	})
+
+	t.Run("merge_replaces_rules", func(t *testing.T) {
+		base := DefaultConfig()
+		base.LogHighlightRules = []LogHighlightRule{{Pattern: "global"}}
+		override := &Config{LogHighlightRules: []LogHighlightRule{{Pattern: "repo"}}}
+
+		merged := MergeConfig(base, override)
+		assert.Equal(t, []LogHighlightRule{{Pattern: "repo"}}, merged.LogHighlightRules)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		config := DefaultConfig()
+		config.LogHighlightRules = []LogHighlightRule{{Pattern: "ok"}}
+		assert.NoError(t, validateConfig(config))
+
+		config.LogHighlightRules = []LogHighlightRule{{Pattern: ""}}
+		err := validateConfig(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "log_highlight_rules[0].pattern is required")
+
+		config.LogHighlightRules = []LogHighlightRule{{Pattern: "ok"}, {Pattern: "(unclosed"}}
+		err = validateConfig(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "log_highlight_rules[1].pattern is not a valid regular expression")
+	})
+}
+
+func TestConfig_LoghookIntervals(t *testing.T) {
+	t.Run("merge_overlays_intervals", func(t *testing.T) {
+		base := DefaultConfig()
+		base.LoghookIntervals = map[string]int{"build": 10, "tests": 30}
+		override := &Config{LoghookIntervals: map[string]int{"tests": 60}}
+
+		merged := MergeConfig(base, override)
+		assert.Equal(t, map[string]int{"build": 10, "tests": 60}, merged.LoghookIntervals)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		config := DefaultConfig()
+		config.LoghookIntervals = map[string]int{"build": 10}
+		assert.NoError(t, validateConfig(config))
+
+		config.LoghookIntervals = map[string]int{"agent": 0}
+		err := validateConfig(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "loghook_intervals_seconds.agent")
+	})
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OrgConfigEnv names the org defaults file, overriding org_config_path
+const OrgConfigEnv = "SBS_ORG_CONFIG"
+
+// Names of the configuration layers, lowest first
+const (
+	LayerDefault    = "default"
+	LayerGlobal     = "global"
+	LayerOrg        = "org"
+	LayerRepository = "repository"
+)
+
+// Layer is one configuration file merged into the effective config
+type Layer struct {
+	Name   string // LayerGlobal, LayerOrg or LayerRepository
+	Path   string
+	Config *Config
+}
+
+// Setting is one key of the effective config and the layer it comes from
+type Setting struct {
+	Key    string
+	Value  json.RawMessage
+	Source string // Layer name, or LayerDefault when no layer sets the key
+	Path   string // File of the layer, empty for defaults
+}
+
+// OrgConfigPath returns the org defaults file named by SBS_ORG_CONFIG or the
+// global config's org_config_path, or "" when there is none
+func OrgConfigPath(global *Config) string {
+	path := os.Getenv(OrgConfigEnv)
+	if path == "" && global != nil {
+		path = global.OrgConfigPath
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// LoadOrgConfig loads the org defaults file. A missing file is reported with
+// an error satisfying os.IsNotExist.
+func LoadOrgConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := decodeConfigFile(path, data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse org config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// LoadConfigLayers loads the configuration files LoadConfigWithRepository
+// merges, lowest first. Org defaults and repository config are left out
+// when their files don't exist; an unreadable repository config is too.
+func LoadConfigLayers(repoRoot string) ([]Layer, error) {
+	global, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	globalPath, _ := GlobalConfigPath()
+	layers := []Layer{{Name: LayerGlobal, Path: globalPath, Config: global}}
+
+	if orgPath := OrgConfigPath(global); orgPath != "" {
+		org, err := LoadOrgConfig(orgPath)
+		switch {
+		case err == nil:
+			layers = append(layers, Layer{Name: LayerOrg, Path: orgPath, Config: org})
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+
+	if repoRoot != "" {
+		if repoConfig, err := LoadRepositoryConfig(repoRoot); err == nil {
+			layers = append(layers, Layer{Name: LayerRepository, Path: RepositoryConfigPath(repoRoot), Config: repoConfig})
+		}
+	}
+	return layers, nil
+}
+
+// MergeLayers merges layers in order, each over the ones before it
+func MergeLayers(layers []Layer) *Config {
+	if len(layers) == 0 {
+		return DefaultConfig()
+	}
+	merged := layers[0].Config
+	for _, layer := range layers[1:] {
+		merged = MergeConfig(merged, layer.Config)
+	}
+	return merged
+}
+
+// EffectiveSettings returns each key of the merged layers, sorted by key,
+// with the highest layer setting it to its effective value
+func EffectiveSettings(layers []Layer) ([]Setting, error) {
+	effective, err := configKeys(MergeLayers(layers))
+	if err != nil {
+		return nil, err
+	}
+	layerKeys := make([]map[string]json.RawMessage, len(layers))
+	for i, layer := range layers {
+		if layerKeys[i], err = configKeys(layer.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	settings := make([]Setting, 0, len(effective))
+	for key, value := range effective {
+		setting := Setting{Key: key, Value: value, Source: LayerDefault}
+		for i := len(layers) - 1; i >= 0; i-- {
+			layerValue, ok := layerKeys[i][key]
+			if i > 0 && zeroValue(layerValue) {
+				continue // MergeConfig only takes set values from overriding layers
+			}
+			if ok && bytes.Equal(layerValue, value) {
+				setting.Source, setting.Path = layers[i].Name, layers[i].Path
+				break
+			}
+		}
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+// configKeys returns the keys a config sets, as they are written to JSON
+func configKeys(config *Config) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// zeroValue reports whether a JSON value is empty, such as "" or false
+func zeroValue(value json.RawMessage) bool {
+	switch string(value) {
+	case "", `""`, "0", "false", "null", "[]", "{}":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeJSONFile writes content to path, creating its directory
+func writeJSONFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestOrgConfigPath(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	t.Setenv(OrgConfigEnv, "")
+	assert.Equal(t, "", OrgConfigPath(&Config{}))
+	assert.Equal(t, "/home/dev/org/sbs.json", OrgConfigPath(&Config{OrgConfigPath: "~/org/sbs.json"}))
+
+	t.Setenv(OrgConfigEnv, "/etc/sbs/org.json")
+	assert.Equal(t, "/etc/sbs/org.json", OrgConfigPath(&Config{OrgConfigPath: "~/org/sbs.json"}), "the environment wins")
+}
+
+func TestLoadConfigWithRepository_OrgDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	orgPath := filepath.Join(home, "org.json")
+	t.Setenv(OrgConfigEnv, orgPath)
+
+	writeJSONFile(t, filepath.Join(home, ".config", "sbs", "config.json"),
+		`{"worktree_base_path": "/w", "tmux_command": "claude", "clean_after_days": 30}`)
+	writeJSONFile(t, orgPath, `{"tmux_command": "org-agent", "clean_after_days": 14, "protected_branches": ["release/*"]}`)
+	repoRoot := t.TempDir()
+	writeJSONFile(t, RepositoryConfigPath(repoRoot), `{"clean_after_days": 7}`)
+
+	merged, err := LoadConfigWithRepository(repoRoot)
+	require.NoError(t, err)
+	assert.Equal(t, "/w", merged.WorktreeBasePath)
+	assert.Equal(t, "org-agent", merged.TmuxCommand, "org defaults override the global config")
+	assert.Equal(t, 7, merged.CleanAfterDays, "the repository overrides org defaults")
+	assert.Equal(t, []string{"release/*"}, merged.ProtectedBranches)
+
+	layers, err := LoadConfigLayers(repoRoot)
+	require.NoError(t, err)
+	settings, err := EffectiveSettings(layers)
+	require.NoError(t, err)
+	sources := make(map[string]string)
+	for _, setting := range settings {
+		sources[setting.Key] = setting.Source
+	}
+	assert.Equal(t, LayerGlobal, sources["worktree_base_path"])
+	assert.Equal(t, LayerOrg, sources["tmux_command"])
+	assert.Equal(t, LayerOrg, sources["protected_branches"])
+	assert.Equal(t, LayerRepository, sources["clean_after_days"])
+	assert.Equal(t, LayerGlobal, sources["github_token"], "empty values in higher layers don't claim a key")
+
+	t.Run("a missing org file is skipped", func(t *testing.T) {
+		t.Setenv(OrgConfigEnv, filepath.Join(home, "missing.json"))
+		merged, err := LoadConfigWithRepository(repoRoot)
+		require.NoError(t, err)
+		assert.Equal(t, "claude", merged.TmuxCommand)
+	})
+
+	t.Run("a broken org file is an error", func(t *testing.T) {
+		writeJSONFile(t, orgPath, `{"tmux_command": `)
+		_, err := LoadConfigWithRepository(repoRoot)
+		assert.ErrorContains(t, err, "failed to parse org config")
+	})
+}
@@ -0,0 +1,42 @@
+package config
+
+import "os"
+
+// StatusRepoMissing is the status of sessions whose repository directory no
+// longer exists. Such sessions can't be restarted; 'sbs clean --repo-missing'
+// removes them with their worktrees and sandboxes.
+const StatusRepoMissing = "repo missing"
+
+// RepositoryMissing reports whether the session's repository directory has
+// been deleted. Sessions without a recorded repository are never missing one.
+func (s SessionMetadata) RepositoryMissing() bool {
+	if s.RepositoryRoot == "" {
+		return false
+	}
+	_, err := os.Stat(s.RepositoryRoot)
+	return os.IsNotExist(err)
+}
+
+// MarkMissingRepositories sets the status of sessions whose repository was
+// deleted to StatusRepoMissing, and marks sessions whose repository is back
+// as stale so the next start or clean looks at them afresh. It returns
+// whether any session changed. Each repository is checked once.
+func MarkMissingRepositories(sessions []SessionMetadata) bool {
+	missing := make(map[string]bool)
+	changed := false
+	for i := range sessions {
+		root := sessions[i].RepositoryRoot
+		if _, checked := missing[root]; !checked {
+			missing[root] = sessions[i].RepositoryMissing()
+		}
+		switch {
+		case missing[root] && sessions[i].Status != StatusRepoMissing:
+			sessions[i].Status = StatusRepoMissing
+			changed = true
+		case !missing[root] && sessions[i].Status == StatusRepoMissing:
+			sessions[i].Status = "stale"
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryMissing(t *testing.T) {
+	repoRoot := t.TempDir()
+
+	assert.False(t, SessionMetadata{RepositoryRoot: repoRoot}.RepositoryMissing())
+	assert.True(t, SessionMetadata{RepositoryRoot: filepath.Join(repoRoot, "deleted")}.RepositoryMissing())
+	assert.False(t, SessionMetadata{}.RepositoryMissing(), "sessions without a repository are not missing one")
+}
+
+func TestMarkMissingRepositories(t *testing.T) {
+	repoRoot := t.TempDir()
+	deleted := filepath.Join(repoRoot, "deleted")
+
+	sessions := []SessionMetadata{
+		{NamespacedID: "github:1", RepositoryRoot: repoRoot, Status: "active"},
+		{NamespacedID: "github:2", RepositoryRoot: deleted, Status: "active"},
+		{NamespacedID: "github:3", RepositoryRoot: repoRoot, Status: StatusRepoMissing},
+		{NamespacedID: "github:4", Status: "stopped"},
+	}
+
+	assert.True(t, MarkMissingRepositories(sessions))
+	assert.Equal(t, "active", sessions[0].Status)
+	assert.Equal(t, StatusRepoMissing, sessions[1].Status)
+	assert.Equal(t, "stale", sessions[2].Status, "a restored repository's sessions are no longer marked")
+	assert.Equal(t, "stopped", sessions[3].Status)
+
+	assert.False(t, MarkMissingRepositories(sessions), "marking again changes nothing")
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// How unknown keys in config files are handled, set by unknown_config_keys
+const (
+	UnknownKeysWarn   = "warn"
+	UnknownKeysError  = "error"
+	UnknownKeysIgnore = "ignore"
+)
+
+// UnknownKeyModes are the accepted unknown_config_keys values
+var UnknownKeyModes = []string{UnknownKeysWarn, UnknownKeysError, UnknownKeysIgnore}
+
+// UnknownKeyError reports a config key sbs doesn't know, usually a typo
+type UnknownKeyError struct {
+	Path       string
+	Key        string
+	Suggestion string // Closest known key, if any is close
+}
+
+func (e *UnknownKeyError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown key %q in %s (did you mean %q?)", e.Key, e.Path, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown key %q in %s", e.Key, e.Path)
+}
+
+var (
+	unknownKeysMu   sync.Mutex
+	unknownKeysMode           = UnknownKeysWarn
+	warningOutput   io.Writer = os.Stderr
+	warnedKeys                = make(map[string]bool)
+)
+
+// setUnknownKeysMode applies the global config's unknown_config_keys to the
+// files loaded after it
+func setUnknownKeysMode(mode string) {
+	if mode == "" {
+		mode = UnknownKeysWarn
+	}
+	unknownKeysMu.Lock()
+	defer unknownKeysMu.Unlock()
+	unknownKeysMode = mode
+}
+
+// SetWarningOutput redirects unknown-key warnings, e.g. to io.Discard while
+// a full-screen TUI runs; nil restores stderr
+func SetWarningOutput(w io.Writer) {
+	if w == nil {
+		w = os.Stderr
+	}
+	unknownKeysMu.Lock()
+	defer unknownKeysMu.Unlock()
+	warningOutput = w
+}
+
+// decodeConfigFile decodes a config file other than the global one, whose
+// unknown_config_keys applies to it
+func decodeConfigFile(path string, data []byte, config *Config) error {
+	if err := json.Unmarshal(data, config); err != nil {
+		return err
+	}
+	return checkUnknownKeys(path, data)
+}
+
+// checkUnknownKeys handles a config file's unknown keys under the
+// unknown_config_keys mode: each is warned about once per process, or fails
+// the load
+func checkUnknownKeys(path string, data []byte) error {
+	unknownKeysMu.Lock()
+	mode := unknownKeysMode
+	unknownKeysMu.Unlock()
+	if mode == UnknownKeysIgnore {
+		return nil
+	}
+
+	unknown := FindUnknownKeys(path, data)
+	if len(unknown) == 0 {
+		return nil
+	}
+	if mode == UnknownKeysError {
+		errs := make([]error, len(unknown))
+		for i, err := range unknown {
+			errs[i] = err
+		}
+		return errors.Join(errs...)
+	}
+
+	unknownKeysMu.Lock()
+	defer unknownKeysMu.Unlock()
+	for _, err := range unknown {
+		if id := err.Path + "\x00" + err.Key; !warnedKeys[id] {
+			warnedKeys[id] = true
+			fmt.Fprintf(warningOutput, "Warning: %v; it is ignored\n", err)
+		}
+	}
+	return nil
+}
+
+// FindUnknownKeys returns the keys of a config file that no setting reads.
+// Every unknown top-level key is reported; below the top level, the first
+// unknown key found by a strict decode is.
+func FindUnknownKeys(path string, data []byte) []*UnknownKeyError {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	known := jsonKeys(reflect.TypeOf(Config{}))
+	var unknown []*UnknownKeyError
+	for key := range fields {
+		if !known[key] {
+			unknown = append(unknown, &UnknownKeyError{Path: path, Key: key, Suggestion: closestKey(key, known)})
+			delete(fields, key)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Key < unknown[j].Key })
+
+	// Nested objects, such as a notifier or a source badge
+	remaining, err := json.Marshal(fields)
+	if err != nil {
+		return unknown
+	}
+	decoder := json.NewDecoder(bytes.NewReader(remaining))
+	decoder.DisallowUnknownFields()
+	var strict Config
+	if err := decoder.Decode(&strict); err != nil {
+		if key, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+			key = strings.Trim(key, `"`)
+			unknown = append(unknown, &UnknownKeyError{Path: path, Key: key, Suggestion: closestKey(key, nestedKeys())})
+		}
+	}
+	return unknown
+}
+
+// jsonKeys returns the JSON keys of a struct type's fields
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// nestedKeys returns the JSON keys of every struct type a Config contains
+func nestedKeys() map[string]bool {
+	keys := make(map[string]bool)
+	seen := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || seen[t] {
+			return
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			walk(t.Field(i).Type)
+		}
+		if t != reflect.TypeOf(Config{}) {
+			for key := range jsonKeys(t) {
+				keys[key] = true
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}))
+	return keys
+}
+
+// closestKey returns the known key nearest to key by edit distance, or ""
+// when none is close enough to be a likely typo
+func closestKey(key string, known map[string]bool) string {
+	candidates := make([]string, 0, len(known))
+	for candidate := range known {
+		candidates = append(candidates, candidate)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", min(3, len(key)/3+1)+1
+	for _, candidate := range candidates {
+		if distance := editDistance(key, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/paths"
+)
+
+func TestFindUnknownKeys(t *testing.T) {
+	data := []byte(`{
+		"tmux_comand": "claude",
+		"worktree_base_pth": "/srv",
+		"zzz": 1,
+		"notifiers": [{"typ": "webhook", "url": "https://hooks.example.com"}]
+	}`)
+	unknown := FindUnknownKeys("/home/dev/.config/sbs/config.json", data)
+	require.Len(t, unknown, 4)
+	assert.EqualError(t, unknown[0], `unknown key "tmux_comand" in /home/dev/.config/sbs/config.json (did you mean "tmux_command"?)`)
+	assert.Equal(t, "worktree_base_path", unknown[1].Suggestion)
+	assert.EqualError(t, unknown[2], `unknown key "zzz" in /home/dev/.config/sbs/config.json`)
+	assert.Equal(t, "typ", unknown[3].Key, "nested keys are checked too")
+	assert.Equal(t, "type", unknown[3].Suggestion)
+
+	assert.Empty(t, FindUnknownKeys("config.json", []byte(`{"tmux_command": "claude", "source_badges": {"jira": {"icon": "J"}}}`)))
+}
+
+func TestLoadConfig_UnknownKeys(t *testing.T) {
+	var warnings bytes.Buffer
+	SetWarningOutput(&warnings)
+	t.Cleanup(func() {
+		SetWarningOutput(nil)
+		setUnknownKeysMode("")
+		paths.SetConfigFile("")
+	})
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	paths.SetConfigFile(configPath)
+
+	// Warned about once per process
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"worktree_base_path": "/srv", "tmux_comand": "claude"}`), 0600))
+	_, err := LoadConfig()
+	require.NoError(t, err)
+	_, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, `Warning: unknown key "tmux_comand" in `+configPath+` (did you mean "tmux_command"?); it is ignored`+"\n", warnings.String())
+
+	// The repository config follows the global mode
+	repoRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, ".sbs"), 0755))
+	require.NoError(t, os.WriteFile(RepositoryConfigPath(repoRoot), []byte(`{"loghook_arg": ["-v"]}`), 0644))
+	_, err = LoadRepositoryConfig(repoRoot)
+	require.NoError(t, err)
+	assert.Contains(t, warnings.String(), `unknown key "loghook_arg" in `+RepositoryConfigPath(repoRoot)+` (did you mean "loghook_args"?)`)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"worktree_base_path": "/srv", "unknown_config_keys": "error", "tmux_comand": "claude"}`), 0600))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, `unknown key "tmux_comand"`)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"worktree_base_path": "/srv", "unknown_config_keys": "error"}`), 0600))
+	_, err = LoadConfig()
+	require.NoError(t, err)
+	_, err = LoadRepositoryConfig(repoRoot)
+	assert.ErrorContains(t, err, `unknown key "loghook_arg"`)
+
+	warnings.Reset()
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"worktree_base_path": "/srv", "unknown_config_keys": "ignore", "tmux_comand": "claude"}`), 0600))
+	_, err = LoadConfig()
+	require.NoError(t, err)
+	_, err = LoadRepositoryConfig(repoRoot)
+	require.NoError(t, err)
+	assert.Empty(t, warnings.String())
+
+	cfg := DefaultConfig()
+	cfg.UnknownConfigKeys = "strict"
+	assert.ErrorContains(t, validateConfig(cfg), "unknown_config_keys must be one of: warn, error, ignore")
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_CommandTimeoutConfiguration(t *testing.T) {
+	t.Run("default_timeout", func(t *testing.T) {
+		config := DefaultConfig()
+		assert.Equal(t, 0, config.CommandTimeoutSecs, "no deadline unless configured")
+		assert.Empty(t, config.CommandTimeouts)
+	})
+
+	t.Run("load_timeouts_from_json", func(t *testing.T) {
+		jsonData := `{
+			"worktree_base_path": "~/.sbs-worktrees",
+			"command_timeout_seconds": 30,
+			"command_timeouts": {"sandbox": 120, "tmux": 5}
+		}`
+
+		var config Config
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &config))
+
+		assert.Equal(t, 30, config.CommandTimeoutSecs)
+		assert.Equal(t, map[string]int{"sandbox": 120, "tmux": 5}, config.CommandTimeouts)
+	})
+
+	t.Run("merge_per_tool_timeouts", func(t *testing.T) {
+		base := DefaultConfig()
+		base.CommandTimeouts = map[string]int{"git": 90, "tmux": 5}
+		override := &Config{CommandTimeoutSecs: 20, CommandTimeouts: map[string]int{"tmux": 10}}
+
+		merged := MergeConfig(base, override)
+
+		assert.Equal(t, 20, merged.CommandTimeoutSecs)
+		assert.Equal(t, map[string]int{"git": 90, "tmux": 10}, merged.CommandTimeouts)
+		assert.Equal(t, map[string]int{"git": 90, "tmux": 5}, base.CommandTimeouts, "base config should not be modified")
+	})
+
+	t.Run("merge_timing_budgets", func(t *testing.T) {
+		base := &Config{TimingBudgets: map[string]int{"git worktree add": 45}}
+		override := &Config{TimingBudgets: map[string]int{"sandbox create": 0}}
+
+		merged := MergeConfig(base, override)
+
+		assert.Equal(t, map[string]int{"git worktree add": 45, "sandbox create": 0}, merged.TimingBudgets)
+	})
+}
+
+func TestConfig_CommandTimeoutValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{
+			name:   "disabled_default",
+			modify: func(c *Config) { c.CommandTimeoutSecs = -1 },
+		},
+		{
+			name:        "invalid_default",
+			modify:      func(c *Config) { c.CommandTimeoutSecs = -5 },
+			errContains: "command_timeout_seconds",
+		},
+		{
+			name:   "valid_per_tool",
+			modify: func(c *Config) { c.CommandTimeouts = map[string]int{"git": 120, "sandbox": -1} },
+		},
+		{
+			name:        "unknown_tool",
+			modify:      func(c *Config) { c.CommandTimeouts = map[string]int{"docker": 10} },
+			errContains: "unknown tool \"docker\"",
+		},
+		{
+			name:        "invalid_per_tool_value",
+			modify:      func(c *Config) { c.CommandTimeouts = map[string]int{"git": -3} },
+			errContains: "command_timeouts.git",
+		},
+		{
+			name:   "valid_timing_budgets",
+			modify: func(c *Config) { c.TimingBudgets = map[string]int{"git worktree add": 45, "sandbox create": 0} },
+		},
+		{
+			name:        "unknown_timing_operation",
+			modify:      func(c *Config) { c.TimingBudgets = map[string]int{"git clone": 10} },
+			errContains: "unknown operation \"git clone\"",
+		},
+		{
+			name:        "negative_timing_budget",
+			modify:      func(c *Config) { c.TimingBudgets = map[string]int{"sandbox create": -1} },
+			errContains: "timing_budgets_seconds.sandbox create",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+
+			err := validateConfig(config)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			}
+		})
+	}
+}
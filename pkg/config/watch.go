@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces the burst of events editors produce when saving
+const watchDebounce = 200 * time.Millisecond
+
+// Watcher reports changes to configuration files. It watches the parent
+// directories so files that are replaced atomically or created later are
+// still picked up.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	files   map[string]bool
+	changes chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewWatcher watches the given config files. Files whose directory doesn't
+// exist are skipped; an error is returned only if nothing can be watched.
+func NewWatcher(paths ...string) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	w := &Watcher{
+		watcher: fsWatcher,
+		files:   make(map[string]bool),
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	watchedDirs := make(map[string]bool)
+	var lastErr error
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		path = filepath.Clean(path)
+		w.files[path] = true
+
+		dir := filepath.Dir(path)
+		if watchedDirs[dir] {
+			continue
+		}
+		if err := fsWatcher.Add(dir); err != nil {
+			lastErr = err
+			continue
+		}
+		watchedDirs[dir] = true
+	}
+
+	if len(watchedDirs) == 0 {
+		fsWatcher.Close()
+		if lastErr == nil {
+			lastErr = errors.New("no config files to watch")
+		}
+		return nil, fmt.Errorf("failed to watch config files: %w", lastErr)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers one notification per burst of changes to watched files.
+// The channel is closed when the watcher is closed.
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
+
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !w.files[filepath.Clean(event.Name)] || event.Op == fsnotify.Chmod {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+			} else {
+				timer.Reset(watchDebounce)
+			}
+			fire = timer.C
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-fire:
+			fire = nil
+			select {
+			case w.changes <- struct{}{}:
+			default: // A notification is already pending
+			}
+		}
+	}
+}
+
+// ReloadConfig loads the global config merged with the repository config (when
+// repoRoot is set) and validates the result. Unlike LoadConfigWithRepository, a
+// repository config that exists but can't be parsed is reported as an error.
+func ReloadConfig(repoRoot string) (*Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if repoRoot != "" {
+		repoConfig, err := LoadRepositoryConfig(repoRoot)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load %s: %w", RepositoryConfigPath(repoRoot), err)
+		}
+		if repoConfig != nil {
+			cfg = MergeConfig(cfg, repoConfig)
+		}
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return cfg, nil
+}
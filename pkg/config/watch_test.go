@@ -0,0 +1,253 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForChange returns true if the watcher reports a change within the timeout
+func waitForChange(t *testing.T, w *Watcher, timeout time.Duration) bool {
+	t.Helper()
+	select {
+	case _, ok := <-w.Changes():
+		return ok
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Run("reports_writes_to_watched_file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+
+		w, err := NewWatcher(path)
+		require.NoError(t, err)
+		defer w.Close()
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"status_refresh_interval_seconds": 30}`), 0644))
+		assert.True(t, waitForChange(t, w, 2*time.Second))
+	})
+
+	t.Run("reports_atomic_replace_and_creation", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+
+		w, err := NewWatcher(path)
+		require.NoError(t, err)
+		defer w.Close()
+
+		tmp := filepath.Join(dir, "config.json.tmp")
+		require.NoError(t, os.WriteFile(tmp, []byte("{}"), 0644))
+		require.NoError(t, os.Rename(tmp, path))
+		assert.True(t, waitForChange(t, w, 2*time.Second))
+	})
+
+	t.Run("ignores_other_files", func(t *testing.T) {
+		dir := t.TempDir()
+		w, err := NewWatcher(filepath.Join(dir, "config.json"))
+		require.NoError(t, err)
+		defer w.Close()
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "sessions.json"), []byte("[]"), 0644))
+		assert.False(t, waitForChange(t, w, 500*time.Millisecond))
+	})
+
+	t.Run("coalesces_bursts", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		w, err := NewWatcher(path)
+		require.NoError(t, err)
+		defer w.Close()
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+		}
+		assert.True(t, waitForChange(t, w, 2*time.Second))
+		assert.False(t, waitForChange(t, w, 500*time.Millisecond), "a burst should produce one notification")
+	})
+
+	t.Run("close_closes_changes", func(t *testing.T) {
+		w, err := NewWatcher(filepath.Join(t.TempDir(), "config.json"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, w.Close(), "closing twice is safe")
+
+		select {
+		case _, ok := <-w.Changes():
+			assert.False(t, ok)
+		case <-time.After(2 * time.Second):
+			t.Fatal("changes channel was not closed")
+		}
+	})
+
+	t.Run("missing_directories", func(t *testing.T) {
+		_, err := NewWatcher(filepath.Join(t.TempDir(), "missing", "config.json"))
+		assert.Error(t, err)
+
+		_, err = NewWatcher()
+		assert.Error(t, err)
+	})
+}
+
+func TestReloadConfig(t *testing.T) {
+	writeJSON := func(t *testing.T, path string, value interface{}) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+	}
+
+	setup := func(t *testing.T) (string, string) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		global := DefaultConfig()
+		global.StatusRefreshIntervalSecs = 30
+		globalPath, err := GlobalConfigPath()
+		require.NoError(t, err)
+		writeJSON(t, globalPath, global)
+		return home, t.TempDir()
+	}
+
+	t.Run("merges_repository_config", func(t *testing.T) {
+		_, repoRoot := setup(t)
+		writeJSON(t, RepositoryConfigPath(repoRoot), map[string]interface{}{
+			"log_refresh_interval_seconds": 9,
+			"theme":                        map[string]string{"primary": "#112233"},
+		})
+
+		cfg, err := ReloadConfig(repoRoot)
+		require.NoError(t, err)
+		assert.Equal(t, 30, cfg.StatusRefreshIntervalSecs)
+		assert.Equal(t, 9, cfg.LogRefreshIntervalSecs)
+		assert.Equal(t, "#112233", cfg.Theme["primary"])
+	})
+
+	t.Run("no_repository_config", func(t *testing.T) {
+		_, repoRoot := setup(t)
+
+		cfg, err := ReloadConfig(repoRoot)
+		require.NoError(t, err)
+		assert.Equal(t, 30, cfg.StatusRefreshIntervalSecs)
+	})
+
+	t.Run("malformed_repository_config", func(t *testing.T) {
+		_, repoRoot := setup(t)
+		path := RepositoryConfigPath(repoRoot)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(`{"theme": `), 0644))
+
+		_, err := ReloadConfig(repoRoot)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), path)
+	})
+
+	t.Run("invalid_merged_config", func(t *testing.T) {
+		_, repoRoot := setup(t)
+		writeJSON(t, RepositoryConfigPath(repoRoot), map[string]interface{}{
+			"key_bindings": map[string][]string{"launch": {"x"}},
+		})
+
+		_, err := ReloadConfig(repoRoot)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown action")
+	})
+}
+
+func TestConfig_ThemeAndKeyBindings(t *testing.T) {
+	t.Run("merge_overlays_maps", func(t *testing.T) {
+		base := DefaultConfig()
+		base.Theme = map[string]string{"primary": "#111111", "muted": "240"}
+		base.KeyBindings = map[string][]string{"quit": {"q"}}
+		override := &Config{
+			Theme:       map[string]string{"primary": "#222222"},
+			KeyBindings: map[string][]string{"refresh": {"f5"}},
+		}
+
+		merged := MergeConfig(base, override)
+		assert.Equal(t, map[string]string{"primary": "#222222", "muted": "240"}, merged.Theme)
+		assert.Equal(t, map[string][]string{"quit": {"q"}, "refresh": {"f5"}}, merged.KeyBindings)
+		assert.Equal(t, "#111111", base.Theme["primary"], "base config should not be modified")
+	})
+
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{name: "hex_color", modify: func(c *Config) { c.Theme = map[string]string{"accent": "#00FF00"} }},
+		{name: "short_hex_color", modify: func(c *Config) { c.Theme = map[string]string{"accent": "#0F0"} }},
+		{name: "ansi_color", modify: func(c *Config) { c.Theme = map[string]string{"error": "196"} }},
+		{name: "unknown_color_slot", modify: func(c *Config) { c.Theme = map[string]string{"border": "#000000"} }, errContains: "unknown color"},
+		{name: "invalid_color", modify: func(c *Config) { c.Theme = map[string]string{"primary": "purple"} }, errContains: "theme.primary"},
+		{name: "valid_binding", modify: func(c *Config) { c.KeyBindings = map[string][]string{"refresh": {"r", "f5"}} }},
+		{name: "unknown_action", modify: func(c *Config) { c.KeyBindings = map[string][]string{"launch": {"x"}} }, errContains: "unknown action"},
+		{name: "empty_key_list", modify: func(c *Config) { c.KeyBindings = map[string][]string{"quit": {}} }, errContains: "at least one key"},
+		{name: "blank_key", modify: func(c *Config) { c.KeyBindings = map[string][]string{"quit": {" "}} }, errContains: "empty key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+			err := validateConfig(config)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			}
+		})
+	}
+}
+
+func TestConfig_SourceBadges(t *testing.T) {
+	t.Run("merge_overlays_badges", func(t *testing.T) {
+		base := DefaultConfig()
+		base.SourceBadges = map[string]SourceBadge{"github": {Icon: "GH"}, "jira": {Icon: "J"}}
+		override := &Config{
+			SourceBadges:     map[string]SourceBadge{"jira": {Icon: "JR", Color: "33"}},
+			SourceBadgeStyle: "icon",
+		}
+
+		merged := MergeConfig(base, override)
+		assert.Equal(t, map[string]SourceBadge{"github": {Icon: "GH"}, "jira": {Icon: "JR", Color: "33"}}, merged.SourceBadges)
+		assert.Equal(t, "icon", merged.SourceBadgeStyle)
+		assert.Equal(t, "J", base.SourceBadges["jira"].Icon, "base config should not be modified")
+	})
+
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{name: "valid_badge", modify: func(c *Config) { c.SourceBadges = map[string]SourceBadge{"jira": {Icon: "JR", Color: "#2684FF"}} }},
+		{name: "valid_style", modify: func(c *Config) { c.SourceBadgeStyle = "none" }},
+		{name: "unknown_style", modify: func(c *Config) { c.SourceBadgeStyle = "emoji" }, errContains: "source_badge_style"},
+		{name: "long_icon", modify: func(c *Config) { c.SourceBadges = map[string]SourceBadge{"jira": {Icon: "JIRA!"}} }, errContains: "source_badges.jira.icon"},
+		{name: "non_ascii_icon", modify: func(c *Config) { c.SourceBadges = map[string]SourceBadge{"jira": {Icon: "★"}} }, errContains: "source_badges.jira.icon"},
+		{name: "invalid_color", modify: func(c *Config) { c.SourceBadges = map[string]SourceBadge{"jira": {Color: "blue"}} }, errContains: "source_badges.jira.color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+			err := validateConfig(config)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			}
+		})
+	}
+}
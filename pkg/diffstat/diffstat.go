@@ -0,0 +1,76 @@
+// Package diffstat keeps the size of each session's work, the lines added and
+// deleted on its branch against the base branch, for the diffstat column of
+// the TUI repository view and 'sbs list --wide'. Stats are cached with
+// branchcache, so rendering never waits on a full diff.
+package diffstat
+
+import (
+	"fmt"
+	"time"
+
+	"sbs/pkg/branchcache"
+	"sbs/pkg/git"
+	"sbs/pkg/paths"
+)
+
+const (
+	// RefreshInterval is how long a stat is shown before the branch tips are
+	// checked again
+	RefreshInterval = time.Minute
+	// Retention drops stats of sessions that have not been refreshed for this long
+	Retention = 24 * time.Hour
+)
+
+// Stat is the change on a session branch since it diverged from its base
+type Stat struct {
+	branchcache.Tips
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// String renders the stat as "+adds/-dels"
+func (s Stat) String() string {
+	return fmt.Sprintf("+%d/-%d", s.Additions, s.Deletions)
+}
+
+// Repository is the git functionality stats are computed with
+type Repository interface {
+	branchcache.Repository
+	DiffStat(branch, base string) (int, int, error)
+}
+
+// Cache persists the stats in a single JSON file. It is safe for concurrent use.
+type Cache = branchcache.Cache[Stat, *Stat, Repository]
+
+// NewCache creates a cache backed by the file at path, opening repositories
+// with the git manager
+func NewCache(path string) *Cache {
+	return branchcache.New[Stat, *Stat](path, branchcache.Options[Stat, Repository]{
+		Name:            "diffstat",
+		RefreshInterval: RefreshInterval,
+		Retention:       Retention,
+		Open:            openRepository,
+		Compute:         computeStat,
+	})
+}
+
+func openRepository(repoRoot string) (Repository, error) {
+	manager, err := git.NewManager(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+func computeStat(repository Repository, branch, base string) (Stat, error) {
+	additions, deletions, err := repository.DiffStat(branch, base)
+	if err != nil {
+		return Stat{}, err
+	}
+	return Stat{Additions: additions, Deletions: deletions}, nil
+}
+
+// DefaultPath returns diffstat-cache.json in the cache directory
+func DefaultPath() (string, error) {
+	return paths.CachePath("diffstat-cache.json")
+}
@@ -0,0 +1,88 @@
+package diffstat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/config"
+)
+
+// fakeRepository serves branch tips and diffstats from maps and counts diffs
+type fakeRepository struct {
+	tips  map[string]string
+	stats map[string][2]int
+	diffs int
+}
+
+func (r *fakeRepository) DefaultBaseBranch() (string, error) {
+	return "main", nil
+}
+
+func (r *fakeRepository) BranchTips(branch, base string) (string, string, error) {
+	tip, ok := r.tips[branch]
+	if !ok {
+		return "", "", errors.New("unknown branch")
+	}
+	return tip, r.tips[base], nil
+}
+
+func (r *fakeRepository) DiffStat(branch, base string) (int, int, error) {
+	r.diffs++
+	stat := r.stats[branch]
+	return stat[0], stat[1], nil
+}
+
+func newTestCache(t *testing.T, repository *fakeRepository) *Cache {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "diffstat-cache.json")
+	return NewCache(path).WithOpener(func(repoRoot string) (Repository, error) {
+		if repoRoot != "/repo" {
+			return nil, errors.New("not a repository")
+		}
+		return repository, nil
+	})
+}
+
+func TestStat_String(t *testing.T) {
+	assert.Equal(t, "+120/-30", Stat{Additions: 120, Deletions: 30}.String())
+}
+
+func TestCache_Refresh(t *testing.T) {
+	repository := &fakeRepository{
+		tips:  map[string]string{"main": "b1", "issue-1": "c1"},
+		stats: map[string][2]int{"issue-1": {12, 3}},
+	}
+	cache := newTestCache(t, repository)
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", Branch: "issue-1", RepositoryRoot: "/repo"},
+		{NamespacedID: "github:2", Branch: "deleted-branch", RepositoryRoot: "/repo"},
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	stats, err := cache.Refresh(sessions, now)
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "sessions without a readable branch get no stat")
+	assert.Equal(t, "+12/-3", stats["github:1"].String())
+	assert.Equal(t, "main", stats["github:1"].Base)
+	assert.Equal(t, 1, repository.diffs)
+
+	// Only a moved branch is diffed again
+	stats, err = cache.Refresh(sessions, now.Add(2*RefreshInterval))
+	require.NoError(t, err)
+	assert.Equal(t, 1, repository.diffs)
+	repository.tips["issue-1"] = "c2"
+	repository.stats["issue-1"] = [2]int{40, 5}
+	stats, err = cache.Refresh(sessions, now.Add(4*RefreshInterval))
+	require.NoError(t, err)
+	assert.Equal(t, "+40/-5", stats["github:1"].String())
+	assert.Equal(t, 2, repository.diffs)
+
+	data, err := os.ReadFile(cache.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"additions":40`)
+}
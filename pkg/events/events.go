@@ -0,0 +1,114 @@
+// Package events streams machine-readable progress of sbs commands as
+// newline-delimited JSON, one Event per line, so wrappers such as editor
+// plugins can follow a command without parsing its human output.
+//
+// A stream from sbs start --events-json looks like:
+//
+//	{"type":"step-started","time":"...","work_item":"github:123","step":"branch"}
+//	{"type":"step-completed","time":"...","work_item":"github:123","step":"branch","duration_ms":41}
+//	{"type":"warning","time":"...","work_item":"github:123","message":"Build caches not wired: ..."}
+//	{"type":"ready","time":"...","work_item":"github:123","session":{...}}
+//
+// and always ends with exactly one ready or failed event.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type is the kind of an event
+type Type string
+
+const (
+	StepStarted   Type = "step-started"   // A step began
+	StepCompleted Type = "step-completed" // A step finished; Error is set when it failed, Skipped when it never ran
+	Warning       Type = "warning"        // Something went wrong without stopping the command
+	Ready         Type = "ready"          // The command finished; Session describes the result
+	Failed        Type = "failed"         // The command failed with Error
+)
+
+// Event is one line of the stream
+type Event struct {
+	Type       Type      `json:"type"`
+	Time       time.Time `json:"time"`
+	WorkItem   string    `json:"work_item,omitempty"`
+	Step       string    `json:"step,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Skipped    bool      `json:"skipped,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Session    *Session  `json:"session,omitempty"`
+}
+
+// Session describes the session a ready event reports
+type Session struct {
+	TmuxSession  string `json:"tmux_session"`
+	WorktreePath string `json:"worktree_path"`
+	Branch       string `json:"branch,omitempty"`
+	SandboxName  string `json:"sandbox_name,omitempty"`
+	Existing     bool   `json:"existing,omitempty"` // An already running session was found; nothing was started
+	Ready        bool   `json:"ready"`              // Readiness checks passed (or none were configured)
+}
+
+// Emitter writes events to a stream. It is safe for concurrent use, and a nil
+// Emitter discards events so callers need not check whether streaming is on.
+type Emitter struct {
+	mu       sync.Mutex
+	encoder  *json.Encoder
+	workItem string
+	now      func() time.Time
+}
+
+// NewEmitter creates an emitter writing to w
+func NewEmitter(w io.Writer) *Emitter {
+	return &Emitter{encoder: json.NewEncoder(w), now: time.Now}
+}
+
+// SetWorkItem sets the work item stamped on later events
+func (e *Emitter) SetWorkItem(workItem string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.workItem = workItem
+}
+
+// Emit writes an event, filling in its time and work item. Write failures
+// are dropped: a wrapper that stopped reading must not fail the command.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if event.Time.IsZero() {
+		event.Time = e.now()
+	}
+	if event.WorkItem == "" {
+		event.WorkItem = e.workItem
+	}
+	_ = e.encoder.Encode(event)
+}
+
+// StepStarted reports that a step began
+func (e *Emitter) StepStarted(step string) {
+	e.Emit(Event{Type: StepStarted, Step: step})
+}
+
+// StepCompleted reports that a step finished, failed (err set) or was skipped
+func (e *Emitter) StepCompleted(step string, duration time.Duration, skipped bool, err error) {
+	event := Event{Type: StepCompleted, Step: step, DurationMs: duration.Milliseconds(), Skipped: skipped}
+	if err != nil && !skipped {
+		event.Error = err.Error()
+	}
+	e.Emit(event)
+}
+
+// Warning reports a problem that didn't stop the command
+func (e *Emitter) Warning(message string) {
+	e.Emit(Event{Type: Warning, Message: message})
+}
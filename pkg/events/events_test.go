@@ -0,0 +1,65 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode parses a newline-delimited event stream
+func decode(t *testing.T, stream string) []Event {
+	t.Helper()
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(stream), "\n") {
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event), line)
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestEmitter_WritesOneEventPerLine(t *testing.T) {
+	var out bytes.Buffer
+	emitter := NewEmitter(&out)
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	emitter.now = func() time.Time { return fixed }
+
+	emitter.StepStarted("branch")
+	emitter.SetWorkItem("github:123")
+	emitter.StepCompleted("branch", 1500*time.Millisecond, false, nil)
+	emitter.StepCompleted("worktree", time.Second, false, errors.New("disk full"))
+	emitter.StepCompleted("tmux", 0, true, errors.New("skipped"))
+	emitter.Warning("Build caches not wired")
+	emitter.Emit(Event{Type: Ready, Session: &Session{TmuxSession: "sbs-123", WorktreePath: "/w", Ready: true}})
+
+	events := decode(t, out.String())
+	require.Len(t, events, 6)
+	assert.Equal(t, Event{Type: StepStarted, Time: fixed, Step: "branch"}, events[0])
+	assert.Equal(t, Event{Type: StepCompleted, Time: fixed, WorkItem: "github:123", Step: "branch", DurationMs: 1500}, events[1])
+	assert.Equal(t, "disk full", events[2].Error)
+	assert.True(t, events[3].Skipped)
+	assert.Empty(t, events[3].Error, "skipped steps carry no error of their own")
+	assert.Equal(t, "Build caches not wired", events[4].Message)
+	assert.Equal(t, Ready, events[5].Type)
+	assert.Equal(t, "sbs-123", events[5].Session.TmuxSession)
+
+	assert.Contains(t, out.String(), `"type":"step-started"`)
+	assert.Contains(t, out.String(), `"ready":true`)
+}
+
+func TestEmitter_NilDiscards(t *testing.T) {
+	var emitter *Emitter
+	assert.NotPanics(t, func() {
+		emitter.SetWorkItem("test:1")
+		emitter.StepStarted("branch")
+		emitter.StepCompleted("branch", time.Second, false, nil)
+		emitter.Warning("ignored")
+		emitter.Emit(Event{Type: Failed})
+	})
+}
@@ -0,0 +1,72 @@
+// Package exitcode defines the process exit codes sbs commands end with, so
+// scripts and CI can tell a typo from a missing session or a busy one.
+// Commands attach a code to the errors they return with Errorf or Wrap;
+// Of maps any error to the code sbs exits with.
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sbs/pkg/oplock"
+)
+
+const (
+	OK          = 0   // Success
+	Failure     = 1   // Any error without a more specific code
+	Validation  = 2   // Invalid arguments, flags or configuration
+	NotFound    = 3   // The session or work item named doesn't exist
+	Partial     = 4   // Some of the work was done and some failed or was skipped
+	Busy        = 5   // Another sbs process holds the work item, or an operation on it is in progress
+	Interrupted = 130 // Stopped by SIGINT or SIGTERM
+)
+
+// Error is an error with the exit code it should end sbs with
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches an exit code to err; nil stays nil
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf formats an error with an exit code, wrapping %w arguments like
+// fmt.Errorf
+func Errorf(code int, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Of returns the exit code for the error a command returned: the outermost
+// code attached with Wrap or Errorf, Busy for operation lock conflicts,
+// Interrupted for cancellation, and Failure otherwise
+func Of(err error) int {
+	if err == nil {
+		return OK
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	var conflict *oplock.ConflictError
+	if errors.As(err, &conflict) {
+		return Busy
+	}
+	if errors.Is(err, context.Canceled) {
+		return Interrupted
+	}
+	return Failure
+}
@@ -0,0 +1,43 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/oplock"
+)
+
+func TestOf(t *testing.T) {
+	base := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, OK},
+		{"plain", base, Failure},
+		{"coded", Wrap(NotFound, base), NotFound},
+		{"wrapped coded", fmt.Errorf("context: %w", Errorf(Validation, "bad flag")), Validation},
+		{"outermost code wins", Wrap(Partial, Wrap(NotFound, base)), Partial},
+		{"lock conflict", fmt.Errorf("%w; retry with --wait", &oplock.ConflictError{WorkItem: "github:1"}), Busy},
+		{"cancelled", fmt.Errorf("cleanup interrupted: %w", context.Canceled), Interrupted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Of(tt.err))
+		})
+	}
+}
+
+func TestWrapAndErrorf(t *testing.T) {
+	assert.Nil(t, Wrap(Failure, nil))
+
+	base := errors.New("boom")
+	err := Errorf(Partial, "kept 1 session: %w", base)
+	assert.Equal(t, "kept 1 session: boom", err.Error())
+	assert.ErrorIs(t, err, base, "Errorf wraps %w arguments")
+}
@@ -0,0 +1,103 @@
+// Package expiry applies the clean_after_days policy: a session that hasn't
+// been started, attached or switched to for that many days has expired, and
+// sbs clean and the TUI clean dialog treat it as stale. Sessions within
+// expiry_warning_days of expiring are flagged in sbs list and the TUI, and
+// with expiry_notify announced by a desktop notification, so nothing is
+// cleaned by surprise.
+package expiry
+
+import (
+	"fmt"
+	"time"
+
+	"sbs/pkg/config"
+)
+
+// DefaultWarningDays is how long before expiring a session is flagged when
+// expiry_warning_days isn't set
+const DefaultWarningDays = 2
+
+const day = 24 * time.Hour
+
+// Policy is when sessions expire and how early they are flagged
+type Policy struct {
+	CleanAfter time.Duration // Time unused after which a session expires; 0 disables expiry
+	WarnBefore time.Duration // How long before expiring a session is flagged
+}
+
+// FromConfig returns the policy set by clean_after_days and
+// expiry_warning_days
+func FromConfig(cfg *config.Config) Policy {
+	if cfg == nil || cfg.CleanAfterDays <= 0 {
+		return Policy{}
+	}
+	warningDays := cfg.ExpiryWarningDays
+	if warningDays <= 0 {
+		warningDays = DefaultWarningDays
+	}
+	return Policy{
+		CleanAfter: time.Duration(cfg.CleanAfterDays) * day,
+		WarnBefore: time.Duration(warningDays) * day,
+	}
+}
+
+// Enabled reports whether sessions expire at all
+func (p Policy) Enabled() bool {
+	return p.CleanAfter > 0
+}
+
+// Status is where a session stands under the policy
+type Status struct {
+	ExpiresAt time.Time     // Zero when the session never expires
+	Remaining time.Duration // Time left until ExpiresAt; zero or less once expired
+	Warn      bool          // The session is within the warning window or expired
+}
+
+// Expired reports whether the session is past its expiry
+func (s Status) Expired() bool {
+	return !s.ExpiresAt.IsZero() && s.Remaining <= 0
+}
+
+// Label is the EXPIRES column text: "in 2d", "in 5h", "in 20m", "expired",
+// or empty for sessions that aren't flagged
+func (s Status) Label() string {
+	switch {
+	case !s.Warn:
+		return ""
+	case s.Expired():
+		return "expired"
+	case s.Remaining >= day:
+		return fmt.Sprintf("in %dd", int(s.Remaining/day))
+	case s.Remaining >= time.Hour:
+		return fmt.Sprintf("in %dh", int(s.Remaining/time.Hour))
+	default:
+		return fmt.Sprintf("in %dm", max(1, int(s.Remaining/time.Minute)))
+	}
+}
+
+// Check returns a session's status at now. Sessions without a recorded
+// last use never expire, since their age is unknown.
+func (p Policy) Check(session config.SessionMetadata, now time.Time) Status {
+	lastUsed := session.LastUsed()
+	if !p.Enabled() || lastUsed.IsZero() {
+		return Status{}
+	}
+	expiresAt := lastUsed.Add(p.CleanAfter)
+	remaining := expiresAt.Sub(now)
+	return Status{ExpiresAt: expiresAt, Remaining: remaining, Warn: remaining <= p.WarnBefore}
+}
+
+// Labels returns the EXPIRES column text of the flagged sessions by
+// namespaced ID, or nil when the policy is disabled and the column is hidden
+func (p Policy) Labels(sessions []config.SessionMetadata, now time.Time) map[string]string {
+	if !p.Enabled() {
+		return nil
+	}
+	labels := make(map[string]string)
+	for _, session := range sessions {
+		if label := p.Check(session, now).Label(); label != "" {
+			labels[session.NamespacedID] = label
+		}
+	}
+	return labels
+}
@@ -0,0 +1,62 @@
+package expiry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+var now = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// usedAgo returns a session last used d before now
+func usedAgo(id string, d time.Duration) config.SessionMetadata {
+	return config.SessionMetadata{NamespacedID: id, LastActivity: now.Add(-d).Format(time.RFC3339)}
+}
+
+func TestFromConfig(t *testing.T) {
+	assert.False(t, FromConfig(nil).Enabled())
+	assert.False(t, FromConfig(&config.Config{ExpiryWarningDays: 3}).Enabled(), "expiry needs clean_after_days")
+
+	policy := FromConfig(&config.Config{CleanAfterDays: 14})
+	assert.Equal(t, Policy{CleanAfter: 14 * day, WarnBefore: DefaultWarningDays * day}, policy)
+	assert.Equal(t, 5*day, FromConfig(&config.Config{CleanAfterDays: 14, ExpiryWarningDays: 5}).WarnBefore)
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy := Policy{CleanAfter: 14 * day, WarnBefore: 2 * day}
+
+	tests := []struct {
+		name    string
+		session config.SessionMetadata
+		label   string
+		expired bool
+	}{
+		{"recently used", usedAgo("a", day), "", false},
+		{"inside the warning window", usedAgo("b", 12*day), "in 2d", false},
+		{"hours left", usedAgo("c", 14*day-5*time.Hour), "in 5h", false},
+		{"minutes left", usedAgo("d", 14*day-20*time.Minute), "in 20m", false},
+		{"expired", usedAgo("e", 15*day), "expired", true},
+		{"created but never attached", config.SessionMetadata{CreatedAt: now.Add(-13 * day).Format(time.RFC3339)}, "in 1d", false},
+		{"no recorded use never expires", config.SessionMetadata{}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := policy.Check(tt.session, now)
+			assert.Equal(t, tt.label, status.Label())
+			assert.Equal(t, tt.expired, status.Expired())
+		})
+	}
+
+	assert.Equal(t, Status{}, Policy{}.Check(usedAgo("a", 100*day), now), "a disabled policy never expires sessions")
+}
+
+func TestPolicy_Labels(t *testing.T) {
+	sessions := []config.SessionMetadata{usedAgo("github:1", day), usedAgo("github:2", 13*day)}
+
+	assert.Nil(t, Policy{}.Labels(sessions, now), "no column without a policy")
+	assert.Equal(t, map[string]string{"github:2": "in 1d"},
+		Policy{CleanAfter: 14 * day, WarnBefore: 2 * day}.Labels(sessions, now))
+}
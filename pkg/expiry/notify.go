@@ -0,0 +1,156 @@
+package expiry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+// NoticesFile records the expiries already announced, in the state directory
+const NoticesFile = "expiry-notices.json"
+
+// DefaultNoticesPath returns NoticesFile in the state directory
+func DefaultNoticesPath() (string, error) {
+	return paths.StatePath(NoticesFile)
+}
+
+// PostFunc shows a desktop notification
+type PostFunc func(title, message string) error
+
+// Notifier announces each flagged session once per expiry. What was announced
+// is kept in a JSON file, so sbs list and the TUI don't repeat each other. It
+// is safe for concurrent use.
+type Notifier struct {
+	path string
+	post PostFunc
+	mu   sync.Mutex
+}
+
+// NewNotifier creates a notifier remembering announcements in the file at
+// path and posting them with post
+func NewNotifier(path string, post PostFunc) *Notifier {
+	return &Notifier{path: path, post: post}
+}
+
+// Notify posts a notification for each flagged session not yet announced for
+// its current expiry and returns their IDs. Sessions that are no longer
+// flagged are forgotten, so one used again and then left is announced anew.
+// Sessions whose notification fails are retried next time.
+func (n *Notifier) Notify(sessions []config.SessionMetadata, policy Policy, now time.Time) ([]string, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	announced, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string)
+	var notified []string
+	var failures []error
+	for _, session := range sessions {
+		status := policy.Check(session, now)
+		if !status.Warn {
+			continue
+		}
+		expiresAt := status.ExpiresAt.UTC().Format(time.RFC3339)
+		if announced[session.NamespacedID] == expiresAt {
+			current[session.NamespacedID] = expiresAt
+			continue
+		}
+		if err := n.post("sbs", noticeMessage(session, status)); err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		current[session.NamespacedID] = expiresAt
+		notified = append(notified, session.NamespacedID)
+	}
+
+	if len(notified) > 0 || len(current) != len(announced) {
+		if err := n.save(current); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return notified, errors.Join(failures...)
+}
+
+// noticeMessage is the notification text for a flagged session
+func noticeMessage(session config.SessionMetadata, status Status) string {
+	name := session.NamespacedID
+	if session.IssueTitle != "" {
+		name += " (" + session.IssueTitle + ")"
+	}
+	if status.Expired() {
+		return fmt.Sprintf("Session %s has expired; the next sbs clean removes it", name)
+	}
+	return fmt.Sprintf("Session %s expires %s; attach to it to keep it", name, status.Label())
+}
+
+func (n *Notifier) load() (map[string]string, error) {
+	announced := make(map[string]string)
+	data, err := os.ReadFile(n.path)
+	if os.IsNotExist(err) {
+		return announced, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expiry notices: %w", err)
+	}
+	if err := json.Unmarshal(data, &announced); err != nil {
+		// Forgetting announcements only repeats them
+		return make(map[string]string), nil
+	}
+	return announced, nil
+}
+
+func (n *Notifier) save(announced map[string]string) error {
+	data, err := json.Marshal(announced)
+	if err != nil {
+		return fmt.Errorf("failed to encode expiry notices: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(n.path), paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create expiry notices directory: %w", err)
+	}
+	tmp := n.path + ".tmp"
+	if err := os.WriteFile(tmp, data, paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write expiry notices: %w", err)
+	}
+	if err := os.Rename(tmp, n.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write expiry notices: %w", err)
+	}
+	return nil
+}
+
+// DesktopNotification posts a notification with osascript on macOS and
+// notify-send elsewhere
+func DesktopNotification(title, message string) error {
+	name, args := "notify-send", []string{"--app-name=sbs", title, message}
+	if runtime.GOOS == "darwin" {
+		name, args = "osascript", []string{"-e", fmt.Sprintf("display notification %q with title %q", message, title)}
+	}
+
+	logCtx := cmdlog.LogCommandContext(context.Background(), name, args, cmdlog.GetCaller())
+	start := time.Now()
+	output, err := exec.Command(name, args...).CombinedOutput()
+	duration := time.Since(start)
+	if err != nil {
+		logCtx.LogCompletion(false, -1, strings.TrimSpace(string(output)), duration)
+		return fmt.Errorf("failed to post desktop notification with %s: %w", name, err)
+	}
+	logCtx.LogCompletion(true, 0, "", duration)
+	return nil
+}
@@ -0,0 +1,93 @@
+package expiry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+// recorder collects posted notifications
+type recorder struct {
+	messages []string
+	err      error
+}
+
+func (r *recorder) post(title, message string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func TestNotifier_AnnouncesEachExpiryOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), NoticesFile)
+	posted := &recorder{}
+	notifier := NewNotifier(path, posted.post)
+	policy := Policy{CleanAfter: 14 * day, WarnBefore: 2 * day}
+
+	expiring := usedAgo("github:2", 13*day)
+	expiring.IssueTitle = "Fix login"
+	sessions := []config.SessionMetadata{usedAgo("github:1", day), expiring, usedAgo("github:3", 20*day)}
+
+	notified, err := notifier.Notify(sessions, policy, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github:2", "github:3"}, notified)
+	assert.Equal(t, []string{
+		"Session github:2 (Fix login) expires in 1d; attach to it to keep it",
+		"Session github:3 has expired; the next sbs clean removes it",
+	}, posted.messages)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A later check, e.g. from another sbs process, doesn't repeat them
+	notified, err = NewNotifier(path, posted.post).Notify(sessions, policy, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+	assert.Len(t, posted.messages, 2)
+
+	// Using the session moves its expiry; when that comes near it is announced again
+	sessions[1] = usedAgo("github:2", 0)
+	_, err = notifier.Notify(sessions, policy, now)
+	require.NoError(t, err)
+	later := now.Add(13 * day)
+	notified, err = notifier.Notify(sessions, policy, later)
+	require.NoError(t, err)
+	assert.Contains(t, notified, "github:2")
+}
+
+func TestNotifier_RetriesFailedPosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), NoticesFile)
+	posted := &recorder{err: errors.New("no notification daemon")}
+	notifier := NewNotifier(path, posted.post)
+	policy := Policy{CleanAfter: 14 * day, WarnBefore: 2 * day}
+	sessions := []config.SessionMetadata{usedAgo("github:2", 13*day)}
+
+	notified, err := notifier.Notify(sessions, policy, now)
+	assert.Error(t, err)
+	assert.Empty(t, notified)
+
+	posted.err = nil
+	notified, err = notifier.Notify(sessions, policy, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github:2"}, notified)
+}
+
+func TestNotifier_DisabledPolicy(t *testing.T) {
+	posted := &recorder{}
+	notifier := NewNotifier(filepath.Join(t.TempDir(), NoticesFile), posted.post)
+
+	notified, err := notifier.Notify([]config.SessionMetadata{usedAgo("github:1", 100*day)}, Policy{}, now)
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+	assert.Empty(t, posted.messages)
+}
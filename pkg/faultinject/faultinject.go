@@ -0,0 +1,52 @@
+// Package faultinject makes session provisioning steps fail on demand so
+// partial-failure handling can be tested without shadowing tools on PATH.
+//
+// It is driven by the hidden SBS_FAULT_INJECT environment variable, a comma
+// separated list of steps, e.g. SBS_FAULT_INJECT=worktree,tmux.
+package faultinject
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvVar lists the steps that should fail
+const EnvVar = "SBS_FAULT_INJECT"
+
+// Provisioning steps that can be made to fail
+const (
+	StepBranchCreate  = "branch"
+	StepWorktreeAdd   = "worktree"
+	StepTmuxCreate    = "tmux"
+	StepSandboxCreate = "sandbox"
+)
+
+// Steps are all the steps that accept injected faults
+var Steps = []string{StepBranchCreate, StepWorktreeAdd, StepTmuxCreate, StepSandboxCreate}
+
+// ErrInjected is wrapped by every injected failure
+var ErrInjected = errors.New("injected fault")
+
+// Enabled reports whether step is listed in SBS_FAULT_INJECT
+func Enabled(step string) bool {
+	value := os.Getenv(EnvVar)
+	if value == "" {
+		return false
+	}
+	for _, listed := range strings.Split(value, ",") {
+		if strings.TrimSpace(listed) == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error wrapping ErrInjected when step should fail
+func Check(step string) error {
+	if !Enabled(step) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s step (%s)", ErrInjected, step, EnvVar)
+}
@@ -0,0 +1,45 @@
+package faultinject
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		failing []string
+	}{
+		{name: "unset", value: ""},
+		{name: "single", value: "worktree", failing: []string{StepWorktreeAdd}},
+		{name: "list_with_spaces", value: "branch, tmux", failing: []string{StepBranchCreate, StepTmuxCreate}},
+		{name: "unknown_step_ignored", value: "network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvVar, tt.value)
+			for _, step := range Steps {
+				err := Check(step)
+				if containsStep(tt.failing, step) {
+					assert.True(t, errors.Is(err, ErrInjected), step)
+					assert.Contains(t, err.Error(), step)
+				} else {
+					assert.NoError(t, err, step)
+				}
+			}
+		})
+	}
+}
+
+func containsStep(steps []string, step string) bool {
+	for _, s := range steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+// Package fsck checks session metadata against reality and repairs the
+// problems that can be fixed without guessing.
+package fsck
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+// Problem is one inconsistency found in a session entry
+type Problem struct {
+	Session    string // Namespaced ID, or the entry index when it has none
+	Message    string
+	Repairable bool // Check can fix it when repairing
+	Repaired   bool
+}
+
+func (p Problem) String() string {
+	state := "needs attention"
+	switch {
+	case p.Repaired:
+		state = "repaired"
+	case p.Repairable:
+		state = "repairable"
+	}
+	return fmt.Sprintf("%s: %s (%s)", p.Session, p.Message, state)
+}
+
+// Result holds the problems found and the sessions after any repairs
+type Result struct {
+	Sessions []config.SessionMetadata
+	Problems []Problem
+}
+
+// Unrepaired returns the number of problems left for the user to resolve
+func (r *Result) Unrepaired() int {
+	count := 0
+	for _, problem := range r.Problems {
+		if !problem.Repaired {
+			count++
+		}
+	}
+	return count
+}
+
+// Changed reports whether repairs modified the sessions
+func (r *Result) Changed() bool {
+	for _, problem := range r.Problems {
+		if problem.Repaired {
+			return true
+		}
+	}
+	return false
+}
+
+var knownStatuses = map[string]bool{"": true, "active": true, "stopped": true, "stale": true, config.StatusRepoMissing: true}
+
+// timestampLayouts are accepted and rewritten as RFC 3339 when repairing
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 -0700 MST",
+	time.RFC1123,
+	time.RFC1123Z,
+}
+
+// Check validates sessions and, when repair is set, fixes what it safely can:
+// source types derived from the namespaced ID, missing worktrees marked
+// stale, timestamps normalized and exact duplicate entries dropped. Other
+// duplicates are merged by config.SaveSessionsToPath when the result is saved.
+func Check(sessions []config.SessionMetadata, repair bool) *Result {
+	result := &Result{}
+	seen := make(map[string]int) // namespaced ID -> index of its first entry
+
+	for i := range sessions {
+		session := sessions[i]
+		label := session.NamespacedID
+		if label == "" {
+			label = fmt.Sprintf("entry %d", i)
+		}
+		report := func(repairable bool, format string, args ...interface{}) {
+			result.Problems = append(result.Problems, Problem{
+				Session:    label,
+				Message:    fmt.Sprintf(format, args...),
+				Repairable: repairable,
+				Repaired:   repairable && repair,
+			})
+		}
+
+		if session.NamespacedID == "" {
+			report(false, "missing namespaced_id")
+		} else if workItem, err := inputsource.ParseWorkItemID(session.NamespacedID); err != nil {
+			report(false, "namespaced_id does not parse: %v", err)
+		} else if session.SourceType != workItem.Source {
+			if repair {
+				session.SourceType = workItem.Source
+			}
+			report(true, "source_type %q does not match namespaced_id", sessions[i].SourceType)
+		}
+
+		if session.NamespacedID != "" {
+			if first, ok := seen[session.NamespacedID]; ok {
+				if reflect.DeepEqual(sessions[first], sessions[i]) {
+					report(true, "exact duplicate of entry %d", first)
+					if repair {
+						continue
+					}
+				} else {
+					// Saving merges these, keeping the newest and archiving the rest
+					report(true, "duplicate namespaced_id (also entry %d); the newest entry is kept", first)
+				}
+			} else {
+				seen[session.NamespacedID] = i
+			}
+		}
+
+		if session.Branch == "" {
+			report(false, "missing branch")
+		}
+		if session.TmuxSession == "" {
+			report(false, "missing tmux_session")
+		}
+
+		if session.WorktreePath == "" {
+			report(false, "missing worktree_path")
+		} else if !dirExists(session.WorktreePath) && session.Status != "stale" && !session.RepositoryMissing() {
+			if repair {
+				session.Status = "stale"
+			}
+			report(true, "worktree %s does not exist; status should be stale", session.WorktreePath)
+		}
+		if session.RepositoryMissing() && session.Status != config.StatusRepoMissing {
+			if repair {
+				session.Status = config.StatusRepoMissing
+			}
+			report(true, "repository %s does not exist; status should be %s", session.RepositoryRoot, config.StatusRepoMissing)
+		}
+
+		if !knownStatuses[session.Status] {
+			report(false, "unknown status %q", session.Status)
+		}
+
+		for _, field := range []struct {
+			name  string
+			value *string
+		}{
+			{"created_at", &session.CreatedAt},
+			{"last_activity", &session.LastActivity},
+		} {
+			original := *field.value
+			normalized, ok := normalizeTimestamp(original)
+			switch {
+			case !ok:
+				report(false, "%s %q is not a valid timestamp", field.name, original)
+			case normalized != original:
+				if repair {
+					*field.value = normalized
+				}
+				report(true, "%s %q is not RFC 3339", field.name, original)
+			}
+		}
+
+		result.Sessions = append(result.Sessions, session)
+	}
+
+	return result
+}
+
+// normalizeTimestamp returns the RFC 3339 form of value. Empty timestamps
+// are allowed (older sessions never recorded them).
+func normalizeTimestamp(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", true
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return value, true
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format(time.RFC3339), true
+		}
+	}
+	return "", false
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,117 @@
+package fsck
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func healthySession(t *testing.T, id string) config.SessionMetadata {
+	return config.SessionMetadata{
+		NamespacedID:   id,
+		SourceType:     "test",
+		Branch:         "issue-test-" + id,
+		TmuxSession:    "sbs-repo-test-" + id,
+		WorktreePath:   t.TempDir(),
+		RepositoryRoot: t.TempDir(),
+		Status:         "active",
+		CreatedAt:      "2025-01-02T15:04:05Z",
+	}
+}
+
+func TestCheck_Healthy(t *testing.T) {
+	sessions := []config.SessionMetadata{healthySession(t, "test:a"), healthySession(t, "test:b")}
+
+	result := Check(sessions, true)
+	assert.Empty(t, result.Problems)
+	assert.False(t, result.Changed())
+	assert.Equal(t, sessions, result.Sessions)
+}
+
+func TestCheck_Problems(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "gone")
+
+	badSource := healthySession(t, "test:source")
+	badSource.SourceType = "github"
+
+	goneWorktree := healthySession(t, "test:gone")
+	goneWorktree.WorktreePath = missing
+
+	oldTimestamp := healthySession(t, "test:time")
+	oldTimestamp.LastActivity = "2025-01-02 15:04:05"
+
+	badTimestamp := healthySession(t, "test:badtime")
+	badTimestamp.CreatedAt = "yesterday"
+
+	unparsable := healthySession(t, "no-colon")
+	unparsable.SourceType = ""
+
+	conflicting := healthySession(t, "test:source")
+
+	sessions := []config.SessionMetadata{badSource, goneWorktree, oldTimestamp, badTimestamp, unparsable, goneWorktree, conflicting}
+
+	t.Run("report_only", func(t *testing.T) {
+		result := Check(sessions, false)
+		assert.Equal(t, sessions, result.Sessions, "nothing changes without repair")
+		assert.False(t, result.Changed())
+
+		messages := make([]string, 0, len(result.Problems))
+		for _, problem := range result.Problems {
+			messages = append(messages, problem.String())
+		}
+		assert.Equal(t, []string{
+			`test:source: source_type "github" does not match namespaced_id (repairable)`,
+			"test:gone: worktree " + missing + " does not exist; status should be stale (repairable)",
+			`test:time: last_activity "2025-01-02 15:04:05" is not RFC 3339 (repairable)`,
+			`test:badtime: created_at "yesterday" is not a valid timestamp (needs attention)`,
+			"no-colon: namespaced_id does not parse: invalid work item ID format: no-colon (expected 'source:id' format, e.g., 'github:123', 'jira:PROJ-456' or 'test:my-test') (needs attention)",
+			"test:gone: exact duplicate of entry 1 (repairable)",
+			"test:gone: worktree " + missing + " does not exist; status should be stale (repairable)",
+			"test:source: duplicate namespaced_id (also entry 0); the newest entry is kept (repairable)",
+		}, messages)
+		assert.Equal(t, 8, result.Unrepaired())
+	})
+
+	t.Run("repair", func(t *testing.T) {
+		result := Check(sessions, true)
+		assert.True(t, result.Changed())
+		assert.Equal(t, 2, result.Unrepaired())
+
+		require.Len(t, result.Sessions, 6, "the exact duplicate is dropped")
+		assert.Equal(t, "test", result.Sessions[0].SourceType)
+		assert.Equal(t, "stale", result.Sessions[1].Status)
+		assert.Equal(t, "2025-01-02T15:04:05Z", result.Sessions[2].LastActivity)
+		assert.Equal(t, "yesterday", result.Sessions[3].CreatedAt, "unparseable timestamps are left alone")
+	})
+}
+
+func TestCheck_MissingFields(t *testing.T) {
+	result := Check([]config.SessionMetadata{{}}, true)
+
+	var messages []string
+	for _, problem := range result.Problems {
+		assert.False(t, problem.Repaired)
+		messages = append(messages, problem.Message)
+	}
+	assert.Equal(t, []string{"missing namespaced_id", "missing branch", "missing tmux_session", "missing worktree_path"}, messages)
+	assert.Equal(t, "entry 0", result.Problems[0].Session)
+}
+
+func TestCheck_RepositoryMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "deleted-repo")
+	session := healthySession(t, "test:orphan")
+	session.RepositoryRoot = missing
+	session.WorktreePath = filepath.Join(t.TempDir(), "gone")
+
+	result := Check([]config.SessionMetadata{session}, true)
+	require.Len(t, result.Problems, 1, "a missing worktree is part of the missing repository")
+	assert.Equal(t, "test:orphan: repository "+missing+" does not exist; status should be repo missing (repaired)", result.Problems[0].String())
+	assert.Equal(t, config.StatusRepoMissing, result.Sessions[0].Status)
+
+	again := Check(result.Sessions, true)
+	assert.Empty(t, again.Problems)
+}
@@ -1,9 +1,16 @@
 package git
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/branchname"
+	"sbs/pkg/protection"
 )
 
 func TestGitManager_BranchCleanup(t *testing.T) {
@@ -108,4 +115,105 @@ func TestGitManager_BranchCleanupIntegration(t *testing.T) {
 	})
 }
 
+func TestGitManager_FindOrphanedIssueBranches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Cleanup(func() { branchname.SetTemplate("") })
+	branchname.SetTemplate("feature/{source}/{id}-{title}")
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "initial")
+	for _, branch := range []string{
+		"feature/github/12-custom", // configured template
+		"feature/github/13-active", // configured template, active session
+		"issue-test-quick-default", // default template
+		"issue-42-legacy",          // legacy format
+		"feature/search",           // unrelated
+		"release-1.0",              // unrelated
+	} {
+		run("branch", branch)
+	}
+
+	manager, err := NewManager(dir)
+	require.NoError(t, err)
+
+	branches, err := manager.ListIssueBranches()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"feature/github/12-custom", "feature/github/13-active", "issue-test-quick-default", "issue-42-legacy"}, branches)
+
+	orphaned, err := manager.FindOrphanedIssueBranches([]string{"github:13"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"feature/github/12-custom", "issue-test-quick-default", "issue-42-legacy"}, orphaned)
+}
+
+func TestGitManager_Protection(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "initial")
+	run("branch", "issue-test-integration")
+	run("branch", "issue-test-scratch")
+	run("branch", "master")
+
+	protectedDir := t.TempDir()
+	manager, err := NewManager(dir)
+	require.NoError(t, err)
+	manager = manager.WithProtection(protection.New([]string{"issue-test-integ*"}, []string{protectedDir}))
+
+	orphaned, err := manager.FindOrphanedIssueBranches(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"issue-test-scratch"}, orphaned, "protected branches are never orphaned")
+
+	safe, warnings, err := manager.ValidateBranchDeletion("issue-test-integration")
+	require.NoError(t, err)
+	assert.False(t, safe)
+	assert.Equal(t, []string{`branch issue-test-integration is protected (matches protected_branches pattern "issue-test-integ*")`}, warnings)
+
+	safe, _, err = manager.ValidateBranchDeletion("master")
+	require.NoError(t, err)
+	assert.False(t, safe, "master is always protected")
+
+	var protectedErr *protection.Error
+	assert.ErrorAs(t, manager.DeleteIssueBranchForce("issue-test-integration"), &protectedErr)
+	exists, _ := manager.BranchExists("issue-test-integration")
+	assert.True(t, exists)
+
+	worktree := filepath.Join(protectedDir, "issue-test-integration")
+	require.NoError(t, os.MkdirAll(worktree, 0755))
+	assert.ErrorAs(t, manager.RemoveWorktreeForSession(worktree), &protectedErr)
+	assert.DirExists(t, worktree)
+
+	require.NoError(t, manager.DeleteIssueBranch("issue-test-scratch"))
+}
+
 // BranchDeletionResult is defined in manager.go
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// DefaultExecutable is the git binary used unless git_executable is configured
+const DefaultExecutable = "git"
+
+// locationEnv are the variables git uses to find a repository. They are
+// honored for commands run against the main repository, so exotic layouts
+// (a bare GIT_DIR with a separate GIT_WORK_TREE) work, but dropped for
+// commands run inside a session worktree, where they would otherwise point
+// git at the wrong checkout.
+var locationEnv = []string{"GIT_DIR", "GIT_WORK_TREE", "GIT_INDEX_FILE", "GIT_COMMON_DIR", "GIT_OBJECT_DIRECTORY"}
+
+var (
+	executable      = DefaultExecutable
+	executableMutex sync.RWMutex
+)
+
+// SetExecutable sets the git binary used by every command; an empty path
+// restores the default
+func SetExecutable(path string) {
+	executableMutex.Lock()
+	defer executableMutex.Unlock()
+	if path == "" {
+		path = DefaultExecutable
+	}
+	executable = path
+}
+
+// Executable returns the git binary used by every command
+func Executable() string {
+	executableMutex.RLock()
+	defer executableMutex.RUnlock()
+	return executable
+}
+
+// Command builds a git command run from dir with the configured executable,
+// inheriting GIT_DIR and related overrides from the environment. All git
+// invocations in sbs go through Command or WorktreeCommand.
+func Command(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, Executable(), args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// WorktreeCommand builds a git command that operates on the worktree at dir
+// itself, ignoring repository location overrides from the environment
+func WorktreeCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := Command(ctx, dir, args...)
+	cmd.Env = withoutLocationEnv(cmd.Environ())
+	return cmd
+}
+
+func withoutLocationEnv(env []string) []string {
+	filtered := make([]string, 0, len(env))
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		drop := false
+		for _, location := range locationEnv {
+			if name == location {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// openRepository opens the repository at repoPath, using GIT_DIR as its git
+// directory when set so repositories with a separated git directory work
+func openRepository(repoPath string) (*git.Repository, error) {
+	gitDir := os.Getenv("GIT_DIR")
+	if gitDir == "" {
+		return git.PlainOpen(repoPath)
+	}
+	if !filepath.IsAbs(gitDir) {
+		absolute, err := filepath.Abs(gitDir)
+		if err != nil {
+			return nil, err
+		}
+		gitDir = absolute
+	}
+	storage := filesystem.NewStorage(osfs.New(gitDir), cache.NewObjectLRUDefault())
+	return git.Open(storage, osfs.New(repoPath))
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetExecutable(t *testing.T) {
+	defer SetExecutable("")
+
+	SetExecutable("/opt/git/bin/git-wrapper")
+	assert.Equal(t, "/opt/git/bin/git-wrapper", Executable())
+	assert.Equal(t, "/opt/git/bin/git-wrapper", Command(context.Background(), "/tmp", "status").Args[0])
+
+	SetExecutable("")
+	assert.Equal(t, DefaultExecutable, Executable())
+}
+
+func TestWorktreeCommand_DropsLocationEnv(t *testing.T) {
+	t.Setenv("GIT_DIR", "/srv/repo.git")
+	t.Setenv("GIT_WORK_TREE", "/srv/checkout")
+
+	repoCmd := Command(context.Background(), "/srv/checkout", "status")
+	assert.Contains(t, repoCmd.Environ(), "GIT_DIR=/srv/repo.git", "repository commands honor GIT_DIR")
+
+	worktreeCmd := WorktreeCommand(context.Background(), "/worktrees/issue-1", "status")
+	assert.Equal(t, "/worktrees/issue-1", worktreeCmd.Dir)
+	for _, entry := range worktreeCmd.Env {
+		assert.False(t, strings.HasPrefix(entry, "GIT_DIR=") || strings.HasPrefix(entry, "GIT_WORK_TREE="), entry)
+	}
+	assert.Contains(t, worktreeCmd.Env, "PATH="+os.Getenv("PATH"))
+}
+
+func TestManager_CustomExecutableAndSeparateGitDir(t *testing.T) {
+	realGit, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not available")
+	}
+	defer SetExecutable("")
+
+	base := t.TempDir()
+	workTree := filepath.Join(base, "checkout")
+	gitDir := filepath.Join(base, "repo.git")
+	require.NoError(t, os.MkdirAll(workTree, 0755))
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workTree
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	run("init", "-q", "-b", "main", "--separate-git-dir", gitDir)
+	require.NoError(t, os.WriteFile(filepath.Join(workTree, "README.md"), []byte("hi\n"), 0644))
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	require.NoError(t, os.Remove(filepath.Join(workTree, ".git")), "only GIT_DIR can locate the repository now")
+
+	// A wrapper that records each invocation before running the real git
+	calls := filepath.Join(base, "calls.log")
+	wrapper := filepath.Join(base, "git-wrapper")
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\nexec " + realGit + " \"$@\"\n"
+	require.NoError(t, os.WriteFile(wrapper, []byte(script), 0755))
+	SetExecutable(wrapper)
+
+	t.Setenv("GIT_DIR", gitDir)
+	t.Setenv("GIT_WORK_TREE", workTree)
+
+	manager, err := NewManager(workTree)
+	require.NoError(t, err)
+	exists, err := manager.BranchExists("main")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	merged, err := manager.MergedBranches("main")
+	require.NoError(t, err)
+	assert.Empty(t, merged)
+
+	logged, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.NotEmpty(t, strings.TrimSpace(string(logged)), "git commands go through the configured executable")
+}
@@ -0,0 +1,55 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CommandError is a failed git command together with what it printed. Its
+// message includes the last line of output (usually git's "fatal: ..."); the
+// full output is available through CommandOutput.
+type CommandError struct {
+	Args     []string
+	ExitCode int
+	Output   string // combined output, or stderr when stdout is not captured
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("git %s: %v", strings.Join(e.Args, " "), e.Err)
+	if line := lastLine(e.Output); line != "" {
+		msg += ": " + line
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// CommandOutput returns the full output of the failed git command behind
+// err, or "" if err doesn't wrap a CommandError
+func CommandOutput(err error) string {
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		return strings.TrimSpace(commandErr.Output)
+	}
+	return ""
+}
+
+// newCommandError wraps err with the command's arguments and output
+func newCommandError(args []string, exitCode int, output []byte, err error) *CommandError {
+	return &CommandError{
+		Args:     append([]string(nil), args...),
+		ExitCode: exitCode,
+		Output:   string(output),
+		Err:      err,
+	}
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandError(t *testing.T) {
+	err := &CommandError{
+		Args:   []string{"worktree", "add", "/tmp/wt", "issue-1"},
+		Output: "Preparing worktree\nfatal: 'issue-1' is already checked out at '/tmp/other'\n",
+		Err:    errors.New("exit status 128"),
+	}
+	wrapped := fmt.Errorf("failed to create worktree: %w", err)
+
+	assert.Equal(t, "git worktree add /tmp/wt issue-1: exit status 128: fatal: 'issue-1' is already checked out at '/tmp/other'", err.Error())
+	assert.Equal(t, "Preparing worktree\nfatal: 'issue-1' is already checked out at '/tmp/other'", CommandOutput(wrapped))
+	assert.Equal(t, "", CommandOutput(errors.New("plain")))
+	assert.Equal(t, "", CommandOutput(nil))
+}
+
+func TestManager_FailedCommandKeepsOutput(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	init := exec.Command("git", "init", "-q", dir)
+	require.NoError(t, init.Run())
+
+	manager, err := NewManager(dir)
+	require.NoError(t, err)
+
+	_, err = manager.runGitCommand([]string{"checkout", "no-such-branch"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no-such-branch")
+	assert.Contains(t, CommandOutput(err), "did not match")
+
+	err = manager.runGitCommandRun([]string{"checkout", "no-such-branch"})
+	require.Error(t, err)
+	assert.Contains(t, CommandOutput(err), "did not match", "stderr is captured for commands whose output is not returned")
+}
@@ -0,0 +1,55 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/faultinject"
+)
+
+func TestManager_FaultInjection(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command("sh", "-c", "git init -q -b main && git commit -q --allow-empty -m initial")
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+	)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", output)
+
+	manager, err := NewManager(dir)
+	require.NoError(t, err)
+
+	t.Run("branch", func(t *testing.T) {
+		t.Setenv(faultinject.EnvVar, faultinject.StepBranchCreate)
+
+		err := manager.CreateBranchDirect("issue-test-fault")
+		assert.True(t, errors.Is(err, faultinject.ErrInjected))
+
+		exists, err := manager.BranchExists("issue-test-fault")
+		require.NoError(t, err)
+		assert.False(t, exists, "no branch is created when the step fails")
+	})
+
+	t.Run("worktree", func(t *testing.T) {
+		require.NoError(t, manager.CreateBranchDirect("issue-test-worktree"))
+		t.Setenv(faultinject.EnvVar, faultinject.StepWorktreeAdd)
+
+		worktreePath := filepath.Join(t.TempDir(), "worktrees", "issue-test-worktree")
+		err := manager.CreateWorktree("issue-test-worktree", worktreePath)
+		assert.True(t, errors.Is(err, faultinject.ErrInjected))
+		assert.NoDirExists(t, worktreePath)
+		assert.DirExists(t, filepath.Dir(worktreePath), "the parent directory is left behind like a real partial failure")
+	})
+}
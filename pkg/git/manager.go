@@ -1,6 +1,8 @@
 package git
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,16 +14,22 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"sbs/pkg/branchname"
 	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/faultinject"
+	"sbs/pkg/protection"
 )
 
 type Manager struct {
-	repoPath string
-	repo     *git.Repository
+	ctx        context.Context // bound with WithContext; nil means context.Background()
+	repoPath   string
+	repo       *git.Repository
+	protection protection.Rules // branches and worktrees never deleted
 }
 
 func NewManager(repoPath string) (*Manager, error) {
-	repo, err := git.PlainOpen(repoPath)
+	repo, err := openRepository(repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open git repository at %s: %w", repoPath, err)
 	}
@@ -32,6 +40,30 @@ func NewManager(repoPath string) (*Manager, error) {
 	}, nil
 }
 
+// WithContext returns a copy of the manager whose commands are cancelled
+// when ctx is done
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	c := *m
+	c.ctx = ctx
+	return &c
+}
+
+// WithProtection returns a copy of the manager that refuses to delete the
+// branches and worktrees rules protect (main and master always are)
+func (m *Manager) WithProtection(rules protection.Rules) *Manager {
+	c := *m
+	c.protection = rules
+	return &c
+}
+
+// baseContext returns the context commands run under
+func (m *Manager) baseContext() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
 func (m *Manager) CreateIssueBranch(issueNumber int, issueTitle string) (string, error) {
 	branchName := m.formatBranchName(issueNumber, issueTitle)
 
@@ -56,6 +88,10 @@ func (m *Manager) CreateIssueBranch(issueNumber int, issueTitle string) (string,
 		return branchName, nil
 	}
 
+	if err := faultinject.Check(faultinject.StepBranchCreate); err != nil {
+		return "", fmt.Errorf("failed to create branch %s: %w", branchName, err)
+	}
+
 	// Get HEAD reference
 	head, err := m.repo.Head()
 	if err != nil {
@@ -76,6 +112,13 @@ func (m *Manager) CreateIssueBranch(issueNumber int, issueTitle string) (string,
 
 // CreateBranchDirect creates a branch with the exact name provided
 func (m *Manager) CreateBranchDirect(branchName string) error {
+	return m.CreateBranchFrom(branchName, "")
+}
+
+// CreateBranchFrom creates a branch with the exact name provided at base, a
+// branch, tag or commit such as "develop" or "origin/develop", or at HEAD
+// when base is empty. An existing branch is left where it is.
+func (m *Manager) CreateBranchFrom(branchName, base string) error {
 	// Check if branch already exists
 	exists, err := m.BranchExists(branchName)
 	if err != nil {
@@ -86,15 +129,28 @@ func (m *Manager) CreateBranchDirect(branchName string) error {
 		return nil // Branch already exists
 	}
 
-	// Get HEAD reference
-	head, err := m.repo.Head()
-	if err != nil {
-		return fmt.Errorf("failed to get HEAD: %w", err)
+	if err := faultinject.Check(faultinject.StepBranchCreate); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branchName, err)
+	}
+
+	var start plumbing.Hash
+	if base == "" {
+		head, err := m.repo.Head()
+		if err != nil {
+			return fmt.Errorf("failed to get HEAD: %w", err)
+		}
+		start = head.Hash()
+	} else {
+		hash, err := m.repo.ResolveRevision(plumbing.Revision(base))
+		if err != nil {
+			return fmt.Errorf("failed to resolve base %s for branch %s: %w", base, branchName, err)
+		}
+		start = *hash
 	}
 
 	// Create new branch
 	branchRef := plumbing.NewBranchReferenceName(branchName)
-	ref := plumbing.NewHashReference(branchRef, head.Hash())
+	ref := plumbing.NewHashReference(branchRef, start)
 
 	err = m.repo.Storer.SetReference(ref)
 	if err != nil {
@@ -105,6 +161,13 @@ func (m *Manager) CreateBranchDirect(branchName string) error {
 }
 
 func (m *Manager) CreateWorktree(branchName string, worktreePath string) error {
+	return m.CreateWorktreeWithProgress(branchName, worktreePath, nil)
+}
+
+// CreateWorktreeWithProgress creates a worktree like CreateWorktree, passing
+// git's checkout progress to report as files are written. A nil report
+// creates the worktree in one step without progress.
+func (m *Manager) CreateWorktreeWithProgress(branchName string, worktreePath string, report func(Progress)) error {
 	// Ensure worktree directory exists
 	parentDir := filepath.Dir(worktreePath)
 	if err := os.MkdirAll(parentDir, 0755); err != nil {
@@ -129,15 +192,23 @@ func (m *Manager) CreateWorktree(branchName string, worktreePath string) error {
 		return fmt.Errorf("branch %s does not exist", branchName)
 	}
 
+	if err := faultinject.Check(faultinject.StepWorktreeAdd); err != nil {
+		return fmt.Errorf("failed to create worktree at %s for branch %s: %w", worktreePath, branchName, err)
+	}
+
 	// Use git command to create worktree with enhanced error handling
 	args := []string{"worktree", "add", worktreePath, branchName}
+	if report != nil {
+		// Check out separately so git's progress can be streamed
+		args = []string{"worktree", "add", "--no-checkout", worktreePath, branchName}
+	}
 	output, err := m.runGitCommand(args)
 	if err != nil {
 		// If it fails due to worktree conflict, try cleanup and retry once
 		if strings.Contains(string(output), "already registered worktree") {
 			if cleanupErr := m.CleanupWorktreeConflict(worktreePath); cleanupErr != nil {
-				return fmt.Errorf("failed to create worktree at %s for branch %s: %w\nCleanup also failed: %w\nGit output: %s",
-					worktreePath, branchName, err, cleanupErr, string(output))
+				return fmt.Errorf("failed to create worktree at %s for branch %s: %w\nCleanup also failed: %w",
+					worktreePath, branchName, err, cleanupErr)
 			}
 			// Retry after cleanup
 			_, retryErr := m.runGitCommand(args)
@@ -145,8 +216,15 @@ func (m *Manager) CreateWorktree(branchName string, worktreePath string) error {
 				return fmt.Errorf("failed to create worktree at %s for branch %s after cleanup: %w", worktreePath, branchName, retryErr)
 			}
 		} else {
-			return fmt.Errorf("failed to create worktree at %s for branch %s: %w\nGit output: %s",
-				worktreePath, branchName, err, string(output))
+			return fmt.Errorf("failed to create worktree at %s for branch %s: %w", worktreePath, branchName, err)
+		}
+	}
+
+	if report != nil {
+		if err := m.checkoutWithProgress(worktreePath, report); err != nil {
+			// Don't leave an empty worktree behind that would pass validation next time
+			_ = m.RemoveWorktree(worktreePath)
+			return fmt.Errorf("failed to check out worktree at %s for branch %s: %w", worktreePath, branchName, err)
 		}
 	}
 
@@ -159,11 +237,16 @@ func (m *Manager) CreateWorktree(branchName string, worktreePath string) error {
 }
 
 func (m *Manager) RemoveWorktree(worktreePath string) error {
+	if err := m.protection.CheckWorktree(worktreePath); err != nil {
+		return err
+	}
+
 	// Remove worktree using git command with logging
 	args := []string{"worktree", "remove", worktreePath, "--force"}
 	_, err := m.runGitCommand(args)
 	if err != nil {
-		// If git command fails, try manual removal and then prune
+		// If git command fails, try manual removal and then prune. git's output
+		// stays in the command log, and in the error if manual removal fails too.
 		if rmErr := os.RemoveAll(worktreePath); rmErr != nil {
 			return fmt.Errorf("failed to remove worktree via git (%w) and manual removal (%w)", err, rmErr)
 		}
@@ -174,8 +257,7 @@ func (m *Manager) RemoveWorktree(worktreePath string) error {
 }
 
 func (m *Manager) ListWorktrees() ([]string, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = m.repoPath
+	cmd := Command(m.baseContext(), m.repoPath, "worktree", "list", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
@@ -225,6 +307,10 @@ func (m *Manager) CleanupWorktreeConflict(worktreePath string) error {
 
 // RemoveWorktreeForSession removes a worktree and handles all cleanup
 func (m *Manager) RemoveWorktreeForSession(worktreePath string) error {
+	if err := m.protection.CheckWorktree(worktreePath); err != nil {
+		return err
+	}
+
 	// First check if worktree exists and is valid
 	if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
 		// Worktree doesn't exist, just prune stale references
@@ -350,8 +436,7 @@ func (m *Manager) isWorktreeRegistered(worktreePath string) bool {
 // cleanupInvalidWorktree removes an invalid worktree
 func (m *Manager) cleanupInvalidWorktree(worktreePath string) error {
 	// First try to remove via git worktree command
-	cmd := exec.Command("git", "worktree", "remove", worktreePath, "--force")
-	cmd.Dir = m.repoPath
+	cmd := Command(m.baseContext(), m.repoPath, "worktree", "remove", worktreePath, "--force")
 
 	// Capture output for debugging
 	output, err := cmd.CombinedOutput()
@@ -367,8 +452,7 @@ func (m *Manager) cleanupInvalidWorktree(worktreePath string) error {
 	}
 
 	// Then try to prune stale worktree references
-	pruneCmd := exec.Command("git", "worktree", "prune")
-	pruneCmd.Dir = m.repoPath
+	pruneCmd := Command(m.baseContext(), m.repoPath, "worktree", "prune")
 	if err := pruneCmd.Run(); err != nil {
 		// Prune failure is not critical, just log it
 		return fmt.Errorf("worktree directory removed but failed to prune references: %w", err)
@@ -379,17 +463,22 @@ func (m *Manager) cleanupInvalidWorktree(worktreePath string) error {
 
 // runGitCommand executes a git command with logging in the repository directory
 func (m *Manager) runGitCommand(args []string) ([]byte, error) {
-	ctx := cmdlog.LogCommandGlobal("git", args, cmdlog.GetCaller())
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "git", args, cmdlog.GetCaller())
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = m.repoPath
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "git")
+	defer cancel()
+
+	cmd := Command(timeoutCtx, m.repoPath, args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
 	start := time.Now()
 	output, err := cmd.CombinedOutput()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "git", args, timeout, err)
 
 	if err != nil {
-		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
-		return output, err
+		commandErr := newCommandError(args, getExitCode(cmd), output, err)
+		ctx.LogCompletion(false, commandErr.ExitCode, logMessage(commandErr), duration)
+		return output, commandErr
 	}
 
 	ctx.LogCompletion(true, 0, "", duration)
@@ -398,23 +487,39 @@ func (m *Manager) runGitCommand(args []string) ([]byte, error) {
 
 // runGitCommandRun executes a git command without capturing output, with logging
 func (m *Manager) runGitCommandRun(args []string) error {
-	ctx := cmdlog.LogCommandGlobal("git", args, cmdlog.GetCaller())
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "git", args, cmdlog.GetCaller())
+
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "git")
+	defer cancel()
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = m.repoPath
+	var stderr bytes.Buffer
+	cmd := Command(timeoutCtx, m.repoPath, args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+	cmd.Stderr = &stderr
 	start := time.Now()
 	err := cmd.Run()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "git", args, timeout, err)
 
 	if err != nil {
-		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
-		return err
+		commandErr := newCommandError(args, getExitCode(cmd), stderr.Bytes(), err)
+		ctx.LogCompletion(false, commandErr.ExitCode, logMessage(commandErr), duration)
+		return commandErr
 	}
 
 	ctx.LogCompletion(true, 0, "", duration)
 	return nil
 }
 
+// logMessage is the command log entry for a failed git command: the error
+// followed by git's full output
+func logMessage(err *CommandError) string {
+	if output := CommandOutput(err); output != "" {
+		return err.Err.Error() + "\n" + output
+	}
+	return err.Err.Error()
+}
+
 // getExitCode extracts exit code from exec.Cmd
 func getExitCode(cmd *exec.Cmd) int {
 	if cmd.ProcessState != nil {
@@ -435,6 +540,10 @@ type BranchDeletionResult struct {
 
 // DeleteIssueBranch deletes a single issue branch safely
 func (m *Manager) DeleteIssueBranch(branchName string) error {
+	if err := m.protection.CheckBranch(branchName); err != nil {
+		return err
+	}
+
 	// Validate branch exists
 	if !m.branchExists(branchName) {
 		// Not an error - branch doesn't exist, which is the desired state
@@ -458,6 +567,10 @@ func (m *Manager) DeleteIssueBranch(branchName string) error {
 
 // DeleteIssueBranchForce forcefully deletes a branch (even if unmerged)
 func (m *Manager) DeleteIssueBranchForce(branchName string) error {
+	if err := m.protection.CheckBranch(branchName); err != nil {
+		return err
+	}
+
 	// Validate branch exists
 	if !m.branchExists(branchName) {
 		return nil
@@ -483,9 +596,10 @@ func (m *Manager) DeleteCurrentBranch() error {
 	return fmt.Errorf("cannot delete current branch - switch to another branch first")
 }
 
-// ListIssueBranches returns all branches that match the issue-* pattern
+// ListIssueBranches returns all work item branches: those matching the
+// configured branch template, the default template or the legacy format
 func (m *Manager) ListIssueBranches() ([]string, error) {
-	args := []string{"branch", "--list", "issue-*"}
+	args := []string{"branch", "--list", "--format=%(refname:short)"}
 	output, err := m.runGitCommand(args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list issue branches: %w", err)
@@ -498,10 +612,7 @@ func (m *Manager) ListIssueBranches() ([]string, error) {
 		if line == "" {
 			continue
 		}
-		// Remove leading asterisk and spaces (current branch marker)
-		line = strings.TrimPrefix(line, "*")
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "issue-") {
+		if _, ok := branchname.ParseWorkItemID(line); ok {
 			branches = append(branches, line)
 		}
 	}
@@ -528,7 +639,7 @@ func (m *Manager) FindOrphanedIssueBranches(activeSessionWorkItems []string) ([]
 	for _, branch := range allIssueBranches {
 		// Extract work item ID from branch name
 		workItemID := m.extractWorkItemFromBranch(branch)
-		if workItemID != "" && !activeWorkItems[workItemID] {
+		if workItemID != "" && !activeWorkItems[workItemID] && m.protection.CheckBranch(branch) == nil {
 			orphanedBranches = append(orphanedBranches, branch)
 		}
 	}
@@ -564,10 +675,16 @@ func (m *Manager) GetBranchAge(branchName string) (time.Duration, error) {
 // ValidateBranchDeletion checks if a branch is safe to delete.
 // Returns true if the branch can be safely deleted, false if there are concerns.
 // The warnings slice contains human-readable messages about potential issues.
-// This method checks for: branch existence, current branch status, and unmerged changes.
+// This method checks for: protection, branch existence, current branch status,
+// and unmerged changes.
 func (m *Manager) ValidateBranchDeletion(branchName string) (bool, []string, error) {
 	var warnings []string
 
+	// Protected branches are never deleted, whatever their state
+	if err := m.protection.CheckBranch(branchName); err != nil {
+		return false, append(warnings, err.Error()), nil
+	}
+
 	// Handle case where repository is not initialized (for testing)
 	if m.repo == nil {
 		return true, warnings, nil // Treat as safe in test scenarios
@@ -669,22 +786,11 @@ func (m *Manager) DeleteMultipleBranches(branchNames []string, dryRun bool) ([]B
 	return results, nil
 }
 
-// extractWorkItemFromBranch extracts the work item ID from a branch name
-// Expects new format "issue-source-id-some-title" only
+// extractWorkItemFromBranch extracts the namespaced work item ID from a branch
+// name created from any branch template in use ("" if it is not a work item branch)
 func (m *Manager) extractWorkItemFromBranch(branchName string) string {
-	if !strings.HasPrefix(branchName, "issue-") {
-		return ""
-	}
-
-	parts := strings.Split(branchName, "-")
-	if len(parts) < 3 {
-		return ""
-	}
-
-	// New format: issue-source-id-title
-	source := parts[1]
-	id := parts[2]
-	return source + ":" + id
+	workItemID, _ := branchname.ParseWorkItemID(branchName)
+	return workItemID
 }
 
 // extractIssueNumberFromBranch extracts the issue number from a branch name like "issue-123-some-title"
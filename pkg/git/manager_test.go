@@ -0,0 +1,43 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revParse returns the commit rev names in the repository at dir
+func revParse(t *testing.T, dir, rev string) string {
+	t.Helper()
+	cmd := exec.Command("git", "rev-parse", rev)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(output))
+}
+
+func TestManager_CreateBranchFrom(t *testing.T) {
+	repoDir, worktreeDir := newMoveTestRepo(t)
+	commit := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "work")
+	commit.Dir = worktreeDir
+	output, err := commit.CombinedOutput()
+	require.NoError(t, err, "%s", output)
+
+	manager, err := NewManager(repoDir)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.CreateBranchFrom("issue-2", "issue-1"))
+	assert.Equal(t, revParse(t, repoDir, "issue-1"), revParse(t, repoDir, "issue-2"))
+
+	require.NoError(t, manager.CreateBranchFrom("issue-3", ""))
+	assert.Equal(t, revParse(t, repoDir, "main"), revParse(t, repoDir, "issue-3"), "an empty base is HEAD")
+
+	require.NoError(t, manager.CreateBranchFrom("issue-2", "main"))
+	assert.Equal(t, revParse(t, repoDir, "issue-1"), revParse(t, repoDir, "issue-2"), "an existing branch is left alone")
+
+	err = manager.CreateBranchFrom("issue-4", "no-such-branch")
+	assert.ErrorContains(t, err, "failed to resolve base no-such-branch")
+}
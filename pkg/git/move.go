@@ -0,0 +1,49 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// MoveWorktree relocates a linked worktree to newPath, which must not exist
+// yet; missing parent directories are created. git worktree move can't
+// cross filesystems, so a move to another volume copies the worktree,
+// repairs git's links to the copy and then removes the original.
+func (m *Manager) MoveWorktree(oldPath, newPath string) error {
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("destination %s already exists", newPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(newPath), err)
+	}
+
+	err := m.runGitCommandRun([]string{"worktree", "move", oldPath, newPath})
+	if err == nil {
+		return nil
+	}
+	if !strings.Contains(CommandOutput(err), "cross-device") {
+		return fmt.Errorf("failed to move worktree: %w", err)
+	}
+	return m.copyWorktree(oldPath, newPath)
+}
+
+// copyWorktree moves a worktree across filesystems: copy, git worktree
+// repair, remove the original. A failed repair removes the copy instead.
+func (m *Manager) copyWorktree(oldPath, newPath string) error {
+	output, err := exec.CommandContext(m.baseContext(), "cp", "-a", oldPath, newPath).CombinedOutput()
+	if err != nil {
+		_ = os.RemoveAll(newPath)
+		return fmt.Errorf("failed to copy worktree to %s: %w: %s", newPath, err, strings.TrimSpace(string(output)))
+	}
+	if err := m.runGitCommandRun([]string{"worktree", "repair", newPath}); err != nil {
+		_ = os.RemoveAll(newPath)
+		return fmt.Errorf("failed to repair worktree at %s: %w", newPath, err)
+	}
+	if err := os.RemoveAll(oldPath); err != nil {
+		return fmt.Errorf("worktree copied to %s but the original could not be removed: %w", newPath, err)
+	}
+	return nil
+}
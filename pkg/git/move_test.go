@@ -0,0 +1,96 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMoveTestRepo creates a repository with a linked worktree and returns
+// their paths
+func newMoveTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "test")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "test@example.com")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+
+	dir := t.TempDir()
+	repoDir := filepath.Join(dir, "repo")
+	worktreeDir := filepath.Join(dir, "worktrees", "issue-1")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+		{"worktree", "add", "-q", "-b", "issue-1", worktreeDir},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeDir, "notes.txt"), []byte("wip\n"), 0644))
+	return repoDir, worktreeDir
+}
+
+// worktreeBranch returns the branch checked out at path, as git sees it
+func worktreeBranch(t *testing.T, path string) string {
+	t.Helper()
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(output))
+}
+
+func TestManager_MoveWorktree(t *testing.T) {
+	repoDir, worktreeDir := newMoveTestRepo(t)
+	manager, err := NewManager(repoDir)
+	require.NoError(t, err)
+
+	newPath := filepath.Join(filepath.Dir(repoDir), "fast", "nested", "issue-1")
+	require.NoError(t, manager.MoveWorktree(worktreeDir, newPath))
+
+	assert.NoDirExists(t, worktreeDir)
+	assert.FileExists(t, filepath.Join(newPath, "notes.txt"), "uncommitted files move along")
+	assert.Equal(t, "issue-1", worktreeBranch(t, newPath))
+
+	worktrees, err := manager.ListWorktrees()
+	require.NoError(t, err)
+	assert.Contains(t, worktrees, newPath)
+	assert.NotContains(t, worktrees, worktreeDir)
+
+	err = manager.MoveWorktree(newPath, repoDir)
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestManager_CopyWorktree(t *testing.T) {
+	repoDir, worktreeDir := newMoveTestRepo(t)
+	manager, err := NewManager(repoDir)
+	require.NoError(t, err)
+
+	// The path git worktree move takes when the destination is on another volume
+	newPath := filepath.Join(filepath.Dir(repoDir), "volume", "issue-1")
+	require.NoError(t, os.MkdirAll(filepath.Dir(newPath), 0755))
+	require.NoError(t, manager.copyWorktree(worktreeDir, newPath))
+
+	assert.NoDirExists(t, worktreeDir)
+	assert.FileExists(t, filepath.Join(newPath, "notes.txt"))
+	assert.Equal(t, "issue-1", worktreeBranch(t, newPath))
+
+	worktrees, err := manager.ListWorktrees()
+	require.NoError(t, err)
+	assert.Contains(t, worktrees, newPath)
+}
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+)
+
+// Progress is one progress report from git, such as
+// "Updating files:  45% (1234/2742)"
+type Progress struct {
+	Phase   string // e.g. "Updating files"
+	Percent int
+	Current int
+	Total   int
+	Done    bool // git printed the phase's final ", done." line
+}
+
+// String renders the progress the way git does
+func (p Progress) String() string {
+	s := fmt.Sprintf("%s: %3d%% (%d/%d)", p.Phase, p.Percent, p.Current, p.Total)
+	if p.Done {
+		s += ", done"
+	}
+	return s
+}
+
+var progressPattern = regexp.MustCompile(`^(.+?):\s+(\d+)% \((\d+)/(\d+)\)(, done)?`)
+
+// ParseProgress parses a git progress line; ok is false for other output
+func ParseProgress(line string) (Progress, bool) {
+	match := progressPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return Progress{}, false
+	}
+	percent, _ := strconv.Atoi(match[2])
+	current, _ := strconv.Atoi(match[3])
+	total, _ := strconv.Atoi(match[4])
+	return Progress{
+		Phase:   match[1],
+		Percent: percent,
+		Current: current,
+		Total:   total,
+		Done:    match[5] != "",
+	}, true
+}
+
+// scanProgressLines splits git's stderr into lines ending in either \n or the
+// \r git uses to redraw progress in place
+func scanProgressLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// checkoutWithProgress populates a worktree created with --no-checkout,
+// reporting git's progress as it goes. Git only starts printing progress once
+// a checkout has run for a couple of seconds, so small repositories report
+// nothing.
+func (m *Manager) checkoutWithProgress(worktreePath string, report func(Progress)) error {
+	args := []string{"checkout", "--progress", "--force"}
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "git", args, cmdlog.GetCaller())
+
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "git")
+	defer cancel()
+
+	cmd := WorktreeCommand(timeoutCtx, worktreePath, args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		ctx.LogCompletion(false, -1, err.Error(), time.Since(start))
+		return err
+	}
+
+	var output []string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if progress, ok := ParseProgress(line); ok {
+			report(progress)
+		} else if strings.TrimSpace(line) != "" {
+			output = append(output, line)
+		}
+	}
+
+	err = cmd.Wait()
+	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "git", args, timeout, err)
+	if err != nil {
+		commandErr := newCommandError(args, getExitCode(cmd), []byte(strings.Join(output, "\n")), err)
+		ctx.LogCompletion(false, commandErr.ExitCode, logMessage(commandErr), duration)
+		return commandErr
+	}
+
+	ctx.LogCompletion(true, 0, "", duration)
+	return nil
+}
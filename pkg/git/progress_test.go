@@ -0,0 +1,83 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProgress(t *testing.T) {
+	progress, ok := ParseProgress("Updating files:  45% (1234/2742)")
+	require.True(t, ok)
+	assert.Equal(t, Progress{Phase: "Updating files", Percent: 45, Current: 1234, Total: 2742}, progress)
+
+	progress, ok = ParseProgress("Updating files: 100% (2742/2742), done.")
+	require.True(t, ok)
+	assert.True(t, progress.Done)
+	assert.Equal(t, "Updating files: 100% (2742/2742), done", progress.String())
+
+	_, ok = ParseProgress("Preparing worktree (checking out 'issue-1')")
+	assert.False(t, ok)
+}
+
+func TestScanProgressLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("Updating files:  50% (1/2)\rUpdating files: 100% (2/2), done.\nfatal: oops"))
+	scanner.Split(scanProgressLines)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Equal(t, []string{"Updating files:  50% (1/2)", "Updating files: 100% (2/2), done.", "fatal: oops"}, lines)
+}
+
+func TestManager_CreateWorktreeWithProgress(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	// Report progress immediately instead of after git's usual delay
+	t.Setenv("GIT_PROGRESS_DELAY", "0")
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+
+	run("init", "-q", "-b", "main")
+	for i := 0; i < 20; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.txt", i)), []byte("content\n"), 0644))
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	run("branch", "issue-1")
+
+	manager, err := NewManager(dir)
+	require.NoError(t, err)
+
+	var reports []Progress
+	worktreePath := filepath.Join(t.TempDir(), "worktrees", "issue-1")
+	require.NoError(t, manager.CreateWorktreeWithProgress("issue-1", worktreePath, func(p Progress) {
+		reports = append(reports, p)
+	}))
+
+	require.NotEmpty(t, reports)
+	last := reports[len(reports)-1]
+	assert.Equal(t, 20, last.Total)
+	assert.Equal(t, 20, last.Current)
+	assert.FileExists(t, filepath.Join(worktreePath, "file19.txt"))
+}
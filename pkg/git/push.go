@@ -0,0 +1,12 @@
+package git
+
+import "fmt"
+
+// PushBranch pushes a session's branch from its worktree to origin and sets
+// it as the branch's upstream
+func (m *Manager) PushBranch(worktreePath, branch string) error {
+	if _, err := m.runWorktreeCommand(worktreePath, []string{"push", "--set-upstream", "origin", branch}); err != nil {
+		return fmt.Errorf("failed to push %s: %w", branch, err)
+	}
+	return nil
+}
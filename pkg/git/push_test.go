@@ -0,0 +1,38 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_PushBranch(t *testing.T) {
+	repoDir, worktreeDir := newMoveTestRepo(t)
+	remoteDir := filepath.Join(filepath.Dir(repoDir), "remote.git")
+	for _, args := range [][]string{
+		{"init", "-q", "--bare", remoteDir},
+		{"-C", repoDir, "remote", "add", "origin", remoteDir},
+	} {
+		output, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+
+	manager, err := NewManager(repoDir)
+	require.NoError(t, err)
+	require.NoError(t, manager.PushBranch(worktreeDir, "issue-1"))
+
+	output, err := exec.Command("git", "-C", remoteDir, "branch", "--list", "issue-1").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "issue-1", strings.TrimSpace(string(output)))
+
+	output, err = exec.Command("git", "-C", worktreeDir, "rev-parse", "--abbrev-ref", "@{upstream}").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "origin/issue-1", strings.TrimSpace(string(output)))
+
+	err = manager.PushBranch(worktreeDir, "no-such-branch")
+	assert.ErrorContains(t, err, "failed to push no-such-branch")
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+)
+
+// ErrNoRebaseInProgress is returned when continuing or aborting a rebase in a
+// worktree that isn't rebasing
+var ErrNoRebaseInProgress = errors.New("no rebase in progress")
+
+// RebaseConflictError is returned when a rebase in a worktree stops on
+// conflicts. The worktree is left mid-rebase for the user to resolve.
+type RebaseConflictError struct {
+	WorktreePath string
+	Files        []string // Paths with unresolved conflicts, relative to the worktree
+	Err          error
+}
+
+func (e *RebaseConflictError) Error() string {
+	if len(e.Files) == 0 {
+		return fmt.Sprintf("rebase in %s stopped on conflicts", e.WorktreePath)
+	}
+	return fmt.Sprintf("rebase in %s stopped on conflicts in %s", e.WorktreePath, strings.Join(e.Files, ", "))
+}
+
+func (e *RebaseConflictError) Unwrap() error {
+	return e.Err
+}
+
+// RebaseInProgress reports whether the worktree is in the middle of a rebase,
+// by looking for the state directories git keeps while one is stopped
+func RebaseInProgress(worktreePath string) (bool, error) {
+	gitDir, err := worktreeGitDir(worktreePath)
+	if err != nil {
+		return false, err
+	}
+	for _, state := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(gitDir, state)); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RebaseWorktree rebases the branch checked out in a worktree onto base. A
+// rebase that stops on conflicts returns a *RebaseConflictError.
+func (m *Manager) RebaseWorktree(worktreePath, base string) error {
+	if inProgress, err := RebaseInProgress(worktreePath); err != nil {
+		return err
+	} else if inProgress {
+		return fmt.Errorf("a rebase is already in progress in %s", worktreePath)
+	}
+	_, err := m.runWorktreeCommand(worktreePath, []string{"rebase", base})
+	return m.rebaseError(worktreePath, err)
+}
+
+// ContinueRebase continues a stopped rebase once its conflicts are resolved
+// and staged, keeping the original commit messages. Conflicts in a later
+// commit return a *RebaseConflictError.
+func (m *Manager) ContinueRebase(worktreePath string) error {
+	if inProgress, err := RebaseInProgress(worktreePath); err != nil {
+		return err
+	} else if !inProgress {
+		return ErrNoRebaseInProgress
+	}
+	_, err := m.runWorktreeCommand(worktreePath, []string{"rebase", "--continue"}, "GIT_EDITOR=true")
+	return m.rebaseError(worktreePath, err)
+}
+
+// AbortRebase abandons a stopped rebase, restoring the branch as it was
+func (m *Manager) AbortRebase(worktreePath string) error {
+	if inProgress, err := RebaseInProgress(worktreePath); err != nil {
+		return err
+	} else if !inProgress {
+		return ErrNoRebaseInProgress
+	}
+	_, err := m.runWorktreeCommand(worktreePath, []string{"rebase", "--abort"})
+	return err
+}
+
+// ConflictedFiles returns the paths in a worktree with unresolved conflicts
+func (m *Manager) ConflictedFiles(worktreePath string) ([]string, error) {
+	output, err := m.runWorktreeCommand(worktreePath, []string{"diff", "--name-only", "--diff-filter=U"})
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// rebaseError turns a failed rebase that left the worktree mid-rebase into a
+// *RebaseConflictError; other errors are returned as they are
+func (m *Manager) rebaseError(worktreePath string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if inProgress, stateErr := RebaseInProgress(worktreePath); stateErr != nil || !inProgress {
+		return err
+	}
+	files, _ := m.ConflictedFiles(worktreePath)
+	return &RebaseConflictError{WorktreePath: worktreePath, Files: files, Err: err}
+}
+
+// runWorktreeCommand executes a git command with logging inside a session
+// worktree, returning its combined output. env adds KEY=value entries to the
+// command's environment.
+func (m *Manager) runWorktreeCommand(worktreePath string, args []string, env ...string) ([]byte, error) {
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "git", args, cmdlog.GetCaller())
+
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "git")
+	defer cancel()
+
+	cmd := WorktreeCommand(timeoutCtx, worktreePath, args...)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "git", args, timeout, err)
+
+	if err != nil {
+		commandErr := newCommandError(args, getExitCode(cmd), output, err)
+		ctx.LogCompletion(false, commandErr.ExitCode, logMessage(commandErr), duration)
+		return output, commandErr
+	}
+
+	ctx.LogCompletion(true, 0, "", duration)
+	return output, nil
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_RebaseWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "test")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "test@example.com")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+
+	dir := t.TempDir()
+	repoDir := filepath.Join(dir, "repo")
+	worktreeDir := filepath.Join(dir, "worktree")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	run := func(cwd string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cwd
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	commit := func(cwd, file, content, message string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(cwd, file), []byte(content), 0644))
+		run(cwd, "add", file)
+		run(cwd, "commit", "-q", "-m", message)
+	}
+
+	run(repoDir, "init", "-q", "-b", "main")
+	commit(repoDir, "app.go", "package app\n", "initial")
+	run(repoDir, "worktree", "add", "-q", "-b", "issue-test-1", worktreeDir)
+	commit(worktreeDir, "app.go", "package app // branch\n", "branch change")
+	commit(repoDir, "app.go", "package app // main\n", "main change")
+
+	manager, err := NewManager(repoDir)
+	require.NoError(t, err)
+
+	err = manager.ContinueRebase(worktreeDir)
+	assert.True(t, errors.Is(err, ErrNoRebaseInProgress))
+
+	err = manager.RebaseWorktree(worktreeDir, "main")
+	var conflict *RebaseConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"app.go"}, conflict.Files)
+	assert.ErrorContains(t, err, "stopped on conflicts in app.go")
+	inProgress, err := RebaseInProgress(worktreeDir)
+	require.NoError(t, err)
+	assert.True(t, inProgress)
+	assert.ErrorContains(t, manager.RebaseWorktree(worktreeDir, "main"), "already in progress")
+
+	err = manager.ContinueRebase(worktreeDir)
+	require.ErrorAs(t, err, &conflict, "continuing with conflicts unresolved stops again")
+
+	require.NoError(t, manager.AbortRebase(worktreeDir))
+	inProgress, err = RebaseInProgress(worktreeDir)
+	require.NoError(t, err)
+	assert.False(t, inProgress)
+	data, err := os.ReadFile(filepath.Join(worktreeDir, "app.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package app // branch\n", string(data), "abort restores the branch")
+
+	// Resolve the conflict and continue to the end
+	require.ErrorAs(t, manager.RebaseWorktree(worktreeDir, "main"), &conflict)
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeDir, "app.go"), []byte("package app // both\n"), 0644))
+	run(worktreeDir, "add", "app.go")
+	require.NoError(t, manager.ContinueRebase(worktreeDir))
+	inProgress, err = RebaseInProgress(worktreeDir)
+	require.NoError(t, err)
+	assert.False(t, inProgress)
+
+	// A clean rebase just succeeds
+	commit(repoDir, "other.go", "package app\n", "unrelated change")
+	require.NoError(t, manager.RebaseWorktree(worktreeDir, "main"))
+	assert.FileExists(t, filepath.Join(worktreeDir, "other.go"))
+}
@@ -0,0 +1,284 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoPattern matches markers worth calling out in a summary
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME|XXX|HACK)\b`)
+
+// hunkHeaderPattern extracts the starting line of the new side of a diff hunk
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// CommitSummary is one commit on a branch
+type CommitSummary struct {
+	Hash    string
+	Subject string
+}
+
+// FileChange is the diffstat for one file
+type FileChange struct {
+	Path      string
+	Additions int
+	Deletions int
+	Binary    bool
+}
+
+// TodoMarker is a TODO-style marker added on a branch
+type TodoMarker struct {
+	Path string
+	Line int
+	Text string
+}
+
+// BranchSummary describes the work on a branch relative to its base
+type BranchSummary struct {
+	Branch     string
+	Base       string
+	Commits    []CommitSummary // Oldest first
+	Files      []FileChange
+	Insertions int
+	Deletions  int
+	Todos      []TodoMarker
+}
+
+// DefaultBaseBranch returns the branch work branches are compared against
+func (m *Manager) DefaultBaseBranch() (string, error) {
+	for _, candidate := range []string{"main", "master"} {
+		if m.branchExists(candidate) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no main or master branch found")
+}
+
+// MergedBranches returns the local branches whose tips are reachable from base,
+// excluding base itself
+func (m *Manager) MergedBranches(base string) ([]string, error) {
+	output, err := m.runGitCommand([]string{"branch", "--merged", base, "--format=%(refname:short)"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches merged into %s: %w", base, err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if branch := strings.TrimSpace(line); branch != "" && branch != base {
+			branches = append(branches, branch)
+		}
+	}
+	return branches, nil
+}
+
+// SummarizeBranch collects the commits, diffstat and added TODO markers on
+// branch since it diverged from base
+func (m *Manager) SummarizeBranch(branch, base string) (*BranchSummary, error) {
+	summary := &BranchSummary{Branch: branch, Base: base}
+
+	output, err := m.runGitCommand([]string{"log", "--reverse", "--format=%h%x09%s", base + ".." + branch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits on %s: %w", branch, err)
+	}
+	summary.Commits = parseCommitLog(string(output))
+
+	output, err = m.runGitCommand([]string{"diff", "--numstat", base + "..." + branch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute diffstat for %s: %w", branch, err)
+	}
+	summary.Files = parseNumstat(string(output))
+	for _, file := range summary.Files {
+		summary.Insertions += file.Additions
+		summary.Deletions += file.Deletions
+	}
+
+	output, err = m.runGitCommand([]string{"diff", "--unified=0", "--no-color", base + "..." + branch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", branch, err)
+	}
+	summary.Todos = parseAddedTodos(string(output))
+
+	return summary, nil
+}
+
+// BranchTips resolves branch and base to the commits they point at
+func (m *Manager) BranchTips(branch, base string) (string, string, error) {
+	output, err := m.runGitCommand([]string{"rev-parse", branch + "^{commit}", base + "^{commit}"})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s and %s: %w", branch, base, err)
+	}
+	tips := strings.Fields(string(output))
+	if len(tips) != 2 {
+		return "", "", fmt.Errorf("failed to resolve %s and %s: unexpected output %q", branch, base, output)
+	}
+	return tips[0], tips[1], nil
+}
+
+// CommitsBehind counts the commits on base that branch lacks and returns the
+// commit time of the oldest, or the zero time when branch is up to date
+func (m *Manager) CommitsBehind(branch, base string) (int, time.Time, error) {
+	output, err := m.runGitCommand([]string{"log", "--format=%ct", branch + ".." + base})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to compare %s with %s: %w", branch, base, err)
+	}
+	lines := strings.Fields(string(output))
+	if len(lines) == 0 {
+		return 0, time.Time{}, nil
+	}
+	// git log lists newest first
+	seconds, err := strconv.ParseInt(lines[len(lines)-1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to compare %s with %s: unexpected output %q", branch, base, lines[len(lines)-1])
+	}
+	return len(lines), time.Unix(seconds, 0), nil
+}
+
+// DiffStat counts the lines added and deleted on branch since it diverged
+// from base; binary files count as neither
+func (m *Manager) DiffStat(branch, base string) (int, int, error) {
+	output, err := m.runGitCommand([]string{"diff", "--numstat", base + "..." + branch})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compute diffstat for %s: %w", branch, err)
+	}
+	additions, deletions := 0, 0
+	for _, file := range parseNumstat(string(output)) {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	return additions, deletions, nil
+}
+
+// parseCommitLog parses "hash<TAB>subject" lines
+func parseCommitLog(output string) []CommitSummary {
+	var commits []CommitSummary
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		hash, subject, _ := strings.Cut(line, "\t")
+		commits = append(commits, CommitSummary{Hash: hash, Subject: subject})
+	}
+	return commits
+}
+
+// parseNumstat parses `git diff --numstat` output; binary files report "-" counts
+func parseNumstat(output string) []FileChange {
+	var files []FileChange
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		change := FileChange{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			change.Binary = true
+		} else {
+			change.Additions, _ = strconv.Atoi(fields[0])
+			change.Deletions, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, change)
+	}
+	return files
+}
+
+// parseAddedTodos finds TODO-style markers on added lines of a zero-context diff
+func parseAddedTodos(diff string) []TodoMarker {
+	var todos []TodoMarker
+	path := ""
+	line := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+		case strings.HasPrefix(text, "@@"):
+			if match := hunkHeaderPattern.FindStringSubmatch(text); match != nil {
+				line, _ = strconv.Atoi(match[1])
+			}
+		case strings.HasPrefix(text, "+"):
+			added := strings.TrimPrefix(text, "+")
+			if todoPattern.MatchString(added) {
+				todos = append(todos, TodoMarker{Path: path, Line: line, Text: strings.TrimSpace(added)})
+			}
+			line++
+		}
+	}
+	return todos
+}
+
+// Text renders the summary for a terminal or standup note
+func (s *BranchSummary) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Branch %s: %d commit(s) ahead of %s, %d file(s) changed, +%d -%d\n",
+		s.Branch, len(s.Commits), s.Base, len(s.Files), s.Insertions, s.Deletions)
+
+	if len(s.Commits) > 0 {
+		b.WriteString("\nCommits:\n")
+		for _, commit := range s.Commits {
+			fmt.Fprintf(&b, "  %s %s\n", commit.Hash, commit.Subject)
+		}
+	}
+
+	if len(s.Files) > 0 {
+		b.WriteString("\nFiles:\n")
+		for _, file := range s.Files {
+			fmt.Fprintf(&b, "  %s %s\n", file.Path, formatFileStat(file))
+		}
+	}
+
+	if len(s.Todos) > 0 {
+		b.WriteString("\nTODO markers added:\n")
+		for _, todo := range s.Todos {
+			fmt.Fprintf(&b, "  %s:%d %s\n", todo.Path, todo.Line, todo.Text)
+		}
+	}
+
+	return b.String()
+}
+
+// Markdown renders the summary for a pull request body
+func (s *BranchSummary) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "%d commit(s) ahead of `%s`, %d file(s) changed (+%d -%d).\n",
+		len(s.Commits), s.Base, len(s.Files), s.Insertions, s.Deletions)
+
+	if len(s.Commits) > 0 {
+		b.WriteString("\n## Commits\n\n")
+		for _, commit := range s.Commits {
+			fmt.Fprintf(&b, "- %s (%s)\n", commit.Subject, commit.Hash)
+		}
+	}
+
+	if len(s.Files) > 0 {
+		b.WriteString("\n## Files changed\n\n")
+		for _, file := range s.Files {
+			fmt.Fprintf(&b, "- `%s` %s\n", file.Path, formatFileStat(file))
+		}
+	}
+
+	if len(s.Todos) > 0 {
+		b.WriteString("\n## TODO markers added\n\n")
+		for _, todo := range s.Todos {
+			fmt.Fprintf(&b, "- `%s:%d` %s\n", todo.Path, todo.Line, todo.Text)
+		}
+	}
+
+	return b.String()
+}
+
+func formatFileStat(file FileChange) string {
+	if file.Binary {
+		return "(binary)"
+	}
+	return fmt.Sprintf("+%d -%d", file.Additions, file.Deletions)
+}
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNumstat(t *testing.T) {
+	output := "10\t2\tpkg/app/container.go\n-\t-\tdocs/diagram.png\n3\t0\tREADME.md\n"
+
+	files := parseNumstat(output)
+	require.Len(t, files, 3)
+	assert.Equal(t, FileChange{Path: "pkg/app/container.go", Additions: 10, Deletions: 2}, files[0])
+	assert.Equal(t, FileChange{Path: "docs/diagram.png", Binary: true}, files[1])
+	assert.Equal(t, FileChange{Path: "README.md", Additions: 3}, files[2])
+
+	assert.Empty(t, parseNumstat(""))
+}
+
+func TestParseAddedTodos(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -10,0 +11,2 @@ func main() {
++	// TODO: handle errors
++	run()
+@@ -20 +22 @@ func run() {
+-	// TODO: old marker removed
++	// FIXME temporary workaround
+diff --git a/new.txt b/new.txt
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,3 @@
++first
++second
++XXX third
+`
+
+	todos := parseAddedTodos(diff)
+	require.Len(t, todos, 3)
+	assert.Equal(t, TodoMarker{Path: "main.go", Line: 11, Text: "// TODO: handle errors"}, todos[0])
+	assert.Equal(t, TodoMarker{Path: "main.go", Line: 22, Text: "// FIXME temporary workaround"}, todos[1])
+	assert.Equal(t, TodoMarker{Path: "new.txt", Line: 3, Text: "XXX third"}, todos[2])
+}
+
+func TestParseCommitLog(t *testing.T) {
+	commits := parseCommitLog("abc1234\tAdd feature\ndef5678\tFix: tabs\tin subject\n")
+	assert.Equal(t, []CommitSummary{
+		{Hash: "abc1234", Subject: "Add feature"},
+		{Hash: "def5678", Subject: "Fix: tabs\tin subject"},
+	}, commits)
+}
+
+func TestManager_SummarizeBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	run("init", "-q", "-b", "main")
+	write("app.go", "package app\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+
+	run("checkout", "-q", "-b", "issue-42-feature")
+	write("app.go", "package app\n\n// TODO: wire up config\nfunc Run() {}\n")
+	run("commit", "-q", "-am", "Add Run")
+	write("notes.md", "notes\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "Add notes")
+
+	manager, err := NewManager(dir)
+	require.NoError(t, err)
+
+	base, err := manager.DefaultBaseBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "main", base)
+
+	summary, err := manager.SummarizeBranch("issue-42-feature", base)
+	require.NoError(t, err)
+
+	require.Len(t, summary.Commits, 2)
+	assert.Equal(t, "Add Run", summary.Commits[0].Subject)
+	assert.Equal(t, "Add notes", summary.Commits[1].Subject)
+	assert.Len(t, summary.Files, 2)
+	assert.Equal(t, 4, summary.Insertions)
+	assert.Equal(t, 0, summary.Deletions)
+	require.Len(t, summary.Todos, 1)
+	assert.Equal(t, TodoMarker{Path: "app.go", Line: 3, Text: "// TODO: wire up config"}, summary.Todos[0])
+
+	text := summary.Text()
+	assert.Contains(t, text, "Branch issue-42-feature: 2 commit(s) ahead of main, 2 file(s) changed, +4 -0")
+	assert.Contains(t, text, "app.go:3 // TODO: wire up config")
+
+	additions, deletions, err := manager.DiffStat("issue-42-feature", base)
+	require.NoError(t, err)
+	assert.Equal(t, summary.Insertions, additions)
+	assert.Equal(t, summary.Deletions, deletions)
+
+	branchTip, baseTip, err := manager.BranchTips("issue-42-feature", base)
+	require.NoError(t, err)
+	assert.Len(t, branchTip, 40)
+	assert.Len(t, baseTip, 40)
+	assert.NotEqual(t, branchTip, baseTip)
+	_, _, err = manager.BranchTips("no-such-branch", base)
+	assert.Error(t, err)
+
+	markdown := summary.Markdown()
+	assert.Contains(t, markdown, "## Summary")
+	assert.Contains(t, markdown, "- Add Run (")
+	assert.Contains(t, markdown, "- `notes.md` +1 -0")
+
+	merged, err := manager.MergedBranches(base)
+	require.NoError(t, err)
+	assert.Empty(t, merged)
+
+	run("checkout", "-q", "main")
+	run("merge", "-q", "--ff-only", "issue-42-feature")
+	merged, err = manager.MergedBranches(base)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"issue-42-feature"}, merged)
+
+	behind, oldest, err := manager.CommitsBehind("issue-42-feature", base)
+	require.NoError(t, err)
+	assert.Zero(t, behind)
+	assert.True(t, oldest.IsZero())
+
+	write("main.go", "package app\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "Add main")
+	run("commit", "-q", "--allow-empty", "-m", "Empty")
+	behind, oldest, err = manager.CommitsBehind("issue-42-feature", base)
+	require.NoError(t, err)
+	assert.Equal(t, 2, behind)
+	assert.WithinDuration(t, time.Now(), oldest, time.Minute)
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// WorktreeHead returns the branch checked out in a worktree by reading its HEAD
+// symref directly, without running git, or "" when HEAD is detached
+func WorktreeHead(worktreePath string) (string, error) {
+	gitDir, err := worktreeGitDir(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD of %s: %w", worktreePath, err)
+	}
+	ref, isSymref := strings.CutPrefix(strings.TrimSpace(string(data)), "ref: ")
+	if !isSymref {
+		return "", nil
+	}
+	return strings.TrimPrefix(ref, "refs/heads/"), nil
+}
+
+// worktreeGitDir locates a worktree's git directory: .git itself in a main
+// checkout, or the directory named by the .git file in a linked worktree
+func worktreeGitDir(worktreePath string) (string, error) {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a git worktree: %w", worktreePath, err)
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dotGit, err)
+	}
+	gitDir, found := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !found {
+		return "", fmt.Errorf("%s does not name a git directory", dotGit)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	return gitDir, nil
+}
+
+// DetectBranchRename reports the branch a session's worktree now has checked
+// out if its recorded branch was renamed: HEAD names another branch and the
+// recorded one no longer exists. A worktree switched to another branch while
+// the recorded one still exists is not a rename, and returns "".
+func (m *Manager) DetectBranchRename(recordedBranch, worktreePath string) (string, error) {
+	head, err := WorktreeHead(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	if head == "" || recordedBranch == "" || head == recordedBranch {
+		return "", nil
+	}
+	exists, err := m.localBranchExists(recordedBranch)
+	if err != nil || exists {
+		return "", err
+	}
+	return head, nil
+}
+
+// localBranchExists checks for an exact local branch name
+func (m *Manager) localBranchExists(branchName string) (bool, error) {
+	if m.repo == nil {
+		return false, fmt.Errorf("repository not available")
+	}
+	_, err := m.repo.Reference(plumbing.NewBranchReferenceName(branchName), false)
+	if err == plumbing.ErrReferenceNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up branch %s: %w", branchName, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_DetectBranchRename(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	repoDir := filepath.Join(dir, "repo")
+	worktreeDir := filepath.Join(dir, "worktree")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	run := func(cwd string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cwd
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+
+	run(repoDir, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "app.go"), []byte("package app\n"), 0644))
+	run(repoDir, "add", ".")
+	run(repoDir, "commit", "-q", "-m", "initial")
+	run(repoDir, "worktree", "add", "-q", "-b", "issue-test-1-old", worktreeDir)
+
+	manager, err := NewManager(repoDir)
+	require.NoError(t, err)
+
+	head, err := WorktreeHead(worktreeDir)
+	require.NoError(t, err)
+	assert.Equal(t, "issue-test-1-old", head)
+
+	head, err = WorktreeHead(repoDir)
+	require.NoError(t, err)
+	assert.Equal(t, "main", head)
+
+	renamed, err := manager.DetectBranchRename("issue-test-1-old", worktreeDir)
+	require.NoError(t, err)
+	assert.Empty(t, renamed, "unchanged branch is not a rename")
+
+	run(worktreeDir, "branch", "-m", "issue-test-1-new")
+	renamed, err = manager.DetectBranchRename("issue-test-1-old", worktreeDir)
+	require.NoError(t, err)
+	assert.Equal(t, "issue-test-1-new", renamed)
+
+	// Switching branches while the recorded one still exists is not a rename
+	run(worktreeDir, "checkout", "-q", "-b", "scratch")
+	renamed, err = manager.DetectBranchRename("issue-test-1-new", worktreeDir)
+	require.NoError(t, err)
+	assert.Empty(t, renamed)
+
+	run(worktreeDir, "checkout", "-q", "--detach")
+	head, err = WorktreeHead(worktreeDir)
+	require.NoError(t, err)
+	assert.Empty(t, head, "detached HEAD has no branch")
+
+	_, err = WorktreeHead(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+// Package inbox folds status updates pushed by external systems, such as
+// build servers, into sessions. A system drops a JSON file into
+// .sbs/inbox/ of the session's worktree:
+//
+//	{
+//	  "source": "ci",                       // optional, who reports
+//	  "state": "failed",                    // pending, running, passed or failed
+//	  "message": "unit tests failed",       // optional
+//	  "url": "https://ci.example/build/42", // optional
+//	  "timestamp": "<RFC3339>"              // optional, defaults to when sbs reads it
+//	}
+//
+// Only *.json files are read, in name order, so writers should write under
+// another name and rename into place. Read files are removed; invalid ones
+// are moved aside with a .bad suffix.
+package inbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sbs/pkg/config"
+)
+
+// RelativeDir is where updates are dropped inside a worktree
+const RelativeDir = ".sbs/inbox"
+
+// QuarantineSuffix is appended to invalid update files when they are moved aside
+const QuarantineSuffix = ".bad"
+
+// States an update can report
+const (
+	StatePending = "pending"
+	StateRunning = "running"
+	StatePassed  = "passed"
+	StateFailed  = "failed"
+)
+
+// ErrInvalidUpdate indicates an update file that is not valid JSON or misses
+// required fields
+var ErrInvalidUpdate = errors.New("invalid inbox update")
+
+// Update is one status update pushed into a worktree's inbox
+type Update struct {
+	Source    string    `json:"source,omitempty"`
+	State     string    `json:"state"`
+	Message   string    `json:"message,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Status returns the remote status the update gives a session, e.g. "ci failed"
+func (u Update) Status() string {
+	if u.Source == "" {
+		return u.State
+	}
+	return u.Source + " " + u.State
+}
+
+// Dir returns the inbox directory of a worktree
+func Dir(worktreePath string) string {
+	return filepath.Join(worktreePath, filepath.FromSlash(RelativeDir))
+}
+
+// Parse validates the content of an update file. Errors wrap ErrInvalidUpdate.
+func Parse(data []byte) (Update, error) {
+	var update Update
+	if err := json.Unmarshal(data, &update); err != nil {
+		return Update{}, fmt.Errorf("%w: %v", ErrInvalidUpdate, err)
+	}
+	switch update.State {
+	case StatePending, StateRunning, StatePassed, StateFailed:
+	case "":
+		return Update{}, fmt.Errorf("%w: no state", ErrInvalidUpdate)
+	default:
+		return Update{}, fmt.Errorf("%w: unknown state %q (expected pending, running, passed or failed)", ErrInvalidUpdate, update.State)
+	}
+	return update, nil
+}
+
+// Drain reads and removes the updates in a worktree's inbox, oldest file
+// name first. Updates without a timestamp get now. Invalid files are
+// quarantined and reported in the error, alongside the valid updates.
+func Drain(worktreePath string, now time.Time) ([]Update, error) {
+	dir := Dir(worktreePath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var updates []Update
+	var errs []error
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s: %w", path, err))
+			continue
+		}
+		update, err := Parse(data)
+		if err != nil {
+			if renameErr := os.Rename(path, path+QuarantineSuffix); renameErr != nil {
+				err = fmt.Errorf("%w (and it could not be quarantined: %v)", err, renameErr)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if update.Timestamp.IsZero() {
+			update.Timestamp = now
+		}
+		updates = append(updates, update)
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", path, err))
+		}
+	}
+	return updates, errors.Join(errs...)
+}
+
+// Apply records the newest of updates as the session's remote status and
+// reports whether the session changed. Updates older than the recorded
+// status are ignored.
+func Apply(session *config.SessionMetadata, updates []Update) bool {
+	var newest *Update
+	for i := range updates {
+		if newest == nil || !updates[i].Timestamp.Before(newest.Timestamp) {
+			newest = &updates[i]
+		}
+	}
+	if newest == nil {
+		return false
+	}
+	if recorded, err := time.Parse(time.RFC3339, session.RemoteStatusAt); err == nil && newest.Timestamp.Before(recorded) {
+		return false
+	}
+
+	session.RemoteStatus = newest.Status()
+	session.RemoteStatusAt = newest.Timestamp.UTC().Format(time.RFC3339)
+	session.RemoteMessage = newest.Message
+	session.RemoteURL = newest.URL
+	return true
+}
+
+// Collect drains the inboxes of the sessions' worktrees, applies the updates
+// to sessions and saves them to the sessions file so other commands see
+// them. It reports whether any session changed; the error lists inbox files
+// that could not be read.
+func Collect(sessions []config.SessionMetadata) (bool, error) {
+	now := config.Now()
+	pending := make(map[string][]Update)
+	var errs []error
+	for i := range sessions {
+		if sessions[i].WorktreePath == "" || sessions[i].NamespacedID == "" {
+			continue
+		}
+		updates, err := Drain(sessions[i].WorktreePath, now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(updates) > 0 {
+			pending[sessions[i].NamespacedID] = updates
+		}
+	}
+	if len(pending) == 0 {
+		return false, errors.Join(errs...)
+	}
+
+	changed := false
+	for i := range sessions {
+		if Apply(&sessions[i], pending[sessions[i].NamespacedID]) {
+			changed = true
+		}
+	}
+	if err := config.UpdateSessions(func(current []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		for i := range current {
+			Apply(&current[i], pending[current[i].NamespacedID])
+		}
+		return current, nil
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("failed to save inbox updates: %w", err))
+	}
+	return changed, errors.Join(errs...)
+}
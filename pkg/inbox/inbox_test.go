@@ -0,0 +1,125 @@
+package inbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+)
+
+// drop writes an update file into a worktree's inbox
+func drop(t *testing.T, worktreePath, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(Dir(worktreePath), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(worktreePath), name), []byte(content), 0644))
+}
+
+func TestParse(t *testing.T) {
+	update, err := Parse([]byte(`{"source":"ci","state":"failed","message":"unit tests failed","url":"https://ci/42","timestamp":"2026-03-04T12:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, Update{
+		Source:    "ci",
+		State:     StateFailed,
+		Message:   "unit tests failed",
+		URL:       "https://ci/42",
+		Timestamp: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+	}, update)
+	assert.Equal(t, "ci failed", update.Status())
+	assert.Equal(t, "passed", Update{State: StatePassed}.Status())
+
+	for name, content := range map[string]string{
+		"not json":      `{"state":`,
+		"no state":      `{"source":"ci"}`,
+		"unknown state": `{"state":"green"}`,
+		"bad timestamp": `{"state":"passed","timestamp":"yesterday"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(content))
+			assert.ErrorIs(t, err, ErrInvalidUpdate)
+		})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	worktree := t.TempDir()
+
+	updates, err := Drain(worktree, now)
+	require.NoError(t, err, "a worktree without an inbox has no updates")
+	assert.Empty(t, updates)
+
+	drop(t, worktree, "2-ci.json", `{"source":"ci","state":"passed"}`)
+	drop(t, worktree, "1-ci.json", `{"source":"ci","state":"running","timestamp":"2026-03-04T11:00:00Z"}`)
+	drop(t, worktree, "3-bad.json", `{"state":"green"}`)
+	drop(t, worktree, "4-ci.json.tmp", `{"state":"failed"}`)
+
+	updates, err = Drain(worktree, now)
+	assert.ErrorIs(t, err, ErrInvalidUpdate)
+	assert.ErrorContains(t, err, "3-bad.json")
+	require.Len(t, updates, 2)
+	assert.Equal(t, StateRunning, updates[0].State, "files are read in name order")
+	assert.Equal(t, now, updates[1].Timestamp, "updates without a timestamp are stamped when read")
+
+	entries, err := os.ReadDir(Dir(worktree))
+	require.NoError(t, err)
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	assert.Equal(t, []string{"3-bad.json.bad", "4-ci.json.tmp"}, left, "read files are removed, invalid ones quarantined")
+}
+
+func TestApply(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	session := &config.SessionMetadata{NamespacedID: "github:12"}
+
+	assert.False(t, Apply(session, nil))
+
+	changed := Apply(session, []Update{
+		{Source: "ci", State: StateFailed, Message: "unit tests failed", URL: "https://ci/42", Timestamp: at.Add(time.Minute)},
+		{Source: "ci", State: StateRunning, Timestamp: at},
+	})
+	assert.True(t, changed)
+	assert.Equal(t, "ci failed", session.RemoteStatus, "the newest update wins")
+	assert.Equal(t, "2026-03-04T12:01:00Z", session.RemoteStatusAt)
+	assert.Equal(t, "unit tests failed", session.RemoteMessage)
+	assert.Equal(t, "https://ci/42", session.RemoteURL)
+
+	assert.False(t, Apply(session, []Update{{State: StatePassed, Timestamp: at}}), "an update older than the recorded status is ignored")
+	assert.Equal(t, "ci failed", session.RemoteStatus)
+}
+
+func TestCollect(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	config.SetClock(clock.NewFake(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { config.SetClock(nil) })
+
+	worktree := t.TempDir()
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:12", WorktreePath: worktree},
+		{NamespacedID: "github:40", WorktreePath: filepath.Join(t.TempDir(), "gone")},
+	}
+	require.NoError(t, config.SaveSessions(sessions))
+
+	changed, err := Collect(sessions)
+	require.NoError(t, err)
+	assert.False(t, changed, "empty inboxes change nothing")
+
+	drop(t, worktree, "build.json", `{"source":"ci","state":"passed"}`)
+	changed, err = Collect(sessions)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "ci passed", sessions[0].RemoteStatus)
+	assert.Equal(t, "2026-03-04T12:00:00Z", sessions[0].RemoteStatusAt)
+
+	stored, err := config.LoadSessions()
+	require.NoError(t, err)
+	assert.Equal(t, "ci passed", stored[0].RemoteStatus, "updates are saved for other commands")
+	assert.Empty(t, stored[1].RemoteStatus)
+}
@@ -0,0 +1,69 @@
+package inputsource
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"sbs/pkg/config"
+)
+
+// sourceBadges are the built-in badges of the known sources
+var sourceBadges = map[string]config.SourceBadge{
+	"github": {Icon: "GH", Color: "#8957E5"},
+	"jira":   {Icon: "JR", Color: "#2684FF"},
+	"test":   {Icon: "T", Color: "#E5C07B"},
+}
+
+// badgePalette colors sources without a built-in badge; each source always
+// gets the same color
+var badgePalette = []string{"#E06C75", "#56B6C2", "#98C379", "#D19A66", "#C678DD", "#61AFEF"}
+
+// SourceBadge returns the badge marking a source's work items: the built-in
+// one overlaid with any configured icon or color. Sources without either get
+// their first two letters and a color derived from the name, so new sources
+// are styled without configuration.
+func SourceBadge(source string, overrides map[string]config.SourceBadge) config.SourceBadge {
+	source = NormalizeSource(source)
+	badge, known := sourceBadges[source]
+	if !known {
+		badge = derivedBadge(source)
+	}
+	if override, exists := overrides[source]; exists {
+		if override.Icon != "" {
+			badge.Icon = override.Icon
+		}
+		if override.Color != "" {
+			badge.Color = override.Color
+		}
+	}
+	return badge
+}
+
+// derivedBadge builds a badge for a source from its name
+func derivedBadge(source string) config.SourceBadge {
+	if source == "" {
+		return config.SourceBadge{Icon: "?", Color: badgePalette[0]}
+	}
+	icon := source
+	if len(icon) > 2 {
+		icon = icon[:2]
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(source))
+	return config.SourceBadge{
+		Icon:  strings.ToUpper(icon),
+		Color: badgePalette[hash.Sum32()%uint32(len(badgePalette))],
+	}
+}
+
+// SessionSource returns the source of a session's work item. Sessions from
+// before pluggable sources have neither field set and are GitHub issues.
+func SessionSource(session config.SessionMetadata) string {
+	if session.SourceType != "" {
+		return session.SourceType
+	}
+	if source, _, found := strings.Cut(session.NamespacedID, ":"); found {
+		return source
+	}
+	return "github"
+}
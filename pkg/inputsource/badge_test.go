@@ -0,0 +1,35 @@
+package inputsource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+func TestSourceBadge(t *testing.T) {
+	t.Run("built_in", func(t *testing.T) {
+		assert.Equal(t, config.SourceBadge{Icon: "GH", Color: "#8957E5"}, SourceBadge("github", nil))
+		assert.Equal(t, "JR", SourceBadge("JIRA", nil).Icon, "source names are normalized")
+	})
+
+	t.Run("override_replaces_set_fields", func(t *testing.T) {
+		overrides := map[string]config.SourceBadge{"github": {Color: "33"}}
+		assert.Equal(t, config.SourceBadge{Icon: "GH", Color: "33"}, SourceBadge("github", overrides))
+	})
+
+	t.Run("unknown_source_is_derived", func(t *testing.T) {
+		badge := SourceBadge("linear", nil)
+		assert.Equal(t, "LI", badge.Icon)
+		assert.Contains(t, badgePalette, badge.Color)
+		assert.Equal(t, badge, SourceBadge("linear", nil), "derived badges are stable")
+		assert.Equal(t, "X", SourceBadge("x", nil).Icon)
+	})
+}
+
+func TestSessionSource(t *testing.T) {
+	assert.Equal(t, "jira", SessionSource(config.SessionMetadata{SourceType: "jira", NamespacedID: "jira:A-1"}))
+	assert.Equal(t, "test", SessionSource(config.SessionMetadata{NamespacedID: "test:quick"}))
+	assert.Equal(t, "github", SessionSource(config.SessionMetadata{IssueNumber: 12}))
+}
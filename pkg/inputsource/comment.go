@@ -0,0 +1,26 @@
+package inputsource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commenter is implemented by input sources that can post comments to work items
+type Commenter interface {
+	// AddComment posts a comment to the work item with the given source-specific ID
+	AddComment(id string, body string) error
+}
+
+// CommentOnWorkItem posts a comment if the source supports comments
+func CommentOnWorkItem(source InputSource, id string, body string) error {
+	commenter, ok := source.(Commenter)
+	if !ok {
+		return fmt.Errorf("input source %s does not support comments", source.GetType())
+	}
+
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment cannot be empty")
+	}
+
+	return commenter.AddComment(id, body)
+}
@@ -0,0 +1,54 @@
+package inputsource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentOnWorkItem(t *testing.T) {
+	t.Run("github_comment", func(t *testing.T) {
+		mockClient := &mockGitHubClient{}
+		source := &GitHubInputSource{client: mockClient}
+
+		err := CommentOnWorkItem(source, "42", "Build passed")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Build passed"}, mockClient.comments[42])
+	})
+
+	t.Run("github_invalid_id", func(t *testing.T) {
+		source := &GitHubInputSource{client: &mockGitHubClient{}}
+
+		err := CommentOnWorkItem(source, "abc", "hello")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid GitHub issue number")
+	})
+
+	t.Run("github_client_error", func(t *testing.T) {
+		source := &GitHubInputSource{client: &mockGitHubClient{stateError: errors.New("boom")}}
+
+		err := CommentOnWorkItem(source, "1", "hello")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to comment on GitHub issue #1")
+	})
+
+	t.Run("empty_comment", func(t *testing.T) {
+		source := &GitHubInputSource{client: &mockGitHubClient{}}
+
+		err := CommentOnWorkItem(source, "1", "   \n")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "comment cannot be empty")
+	})
+
+	t.Run("test_source_accepts_comments", func(t *testing.T) {
+		assert.NoError(t, CommentOnWorkItem(NewTestInputSource(), "my-test", "hello"))
+	})
+
+	t.Run("unsupported_source", func(t *testing.T) {
+		err := CommentOnWorkItem(&noTransitionSource{}, "1", "hello")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support comments")
+	})
+}
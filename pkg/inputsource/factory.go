@@ -7,71 +7,117 @@ import (
 	"sbs/pkg/config"
 )
 
-// InputSourceFactory creates InputSource instances based on configuration
+// InputSourceFactory creates InputSource instances based on configuration.
+// Types it doesn't support itself are served by sbs-source-<type> plugins
+// found on PATH.
 type InputSourceFactory struct {
-	supportedTypes map[string]func() InputSource
+	supportedTypes map[string]sourceCreator
 }
 
+// sourceCreator builds a source from its settings. projectRoot is the
+// repository the source acts for, or "" for the current directory's.
+type sourceCreator func(settings map[string]interface{}, projectRoot string) (InputSource, error)
+
 // NewInputSourceFactory creates a new InputSourceFactory with all supported types
 func NewInputSourceFactory() *InputSourceFactory {
 	return &InputSourceFactory{
-		supportedTypes: map[string]func() InputSource{
-			"github": func() InputSource { return NewGitHubInputSource() },
-			"test":   func() InputSource { return NewTestInputSource() },
+		supportedTypes: map[string]sourceCreator{
+			"github": func(_ map[string]interface{}, projectRoot string) (InputSource, error) {
+				return NewGitHubInputSourceIn(projectRoot), nil
+			},
+			"jira": func(settings map[string]interface{}, _ string) (InputSource, error) {
+				source, err := NewJiraInputSource(settings)
+				if err != nil {
+					return nil, err
+				}
+				return source, nil
+			},
+			"test": func(map[string]interface{}, string) (InputSource, error) { return NewTestInputSource(), nil },
 		},
 	}
 }
 
 // Create creates an InputSource based on the provided configuration
 func (f *InputSourceFactory) Create(cfg *config.InputSourceConfig) (InputSource, error) {
+	return f.create(cfg, "")
+}
+
+func (f *InputSourceFactory) create(cfg *config.InputSourceConfig, projectRoot string) (InputSource, error) {
 	// Handle nil config - default to GitHub
 	if cfg == nil {
-		return f.createGitHubSource(), nil
+		return NewGitHubInputSourceIn(projectRoot), nil
 	}
 
 	// Handle empty type - default to GitHub
 	sourceType := strings.TrimSpace(cfg.Type)
 	if sourceType == "" {
-		return f.createGitHubSource(), nil
+		return NewGitHubInputSourceIn(projectRoot), nil
 	}
 
 	// Look up the creator function
 	creator, exists := f.supportedTypes[sourceType]
 	if !exists {
+		if path, found := FindPlugin(sourceType); found {
+			return NewPluginInputSource(sourceType, path, cfg.Settings), nil
+		}
 		supportedTypes := f.GetSupportedTypes()
 		return nil, fmt.Errorf("unsupported input source type: %s (supported types: %s)",
 			sourceType, strings.Join(supportedTypes, ", "))
 	}
 
 	// Create the input source
-	return creator(), nil
+	source, err := creator(cfg.Settings, projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input source settings: %w", sourceType, err)
+	}
+	return source, nil
 }
 
-// CreateFromProject creates an InputSource by loading configuration from project root
+// CreateCrossSource creates a source for work items outside the project's
+// primary source, configured by cfg.ForSource. A plugin on PATH wins over a
+// built-in type here, so an installed sbs-source-jira keeps serving jira: IDs
+// in projects that don't keep their own Jira settings.
+func (f *InputSourceFactory) CreateCrossSource(cfg *config.InputSourceConfig) (InputSource, error) {
+	if path, found := FindPlugin(cfg.Type); found {
+		return NewPluginInputSource(cfg.Type, path, cfg.Settings), nil
+	}
+	source, err := f.create(cfg, "")
+	if err != nil {
+		if _, builtin := f.supportedTypes[cfg.Type]; builtin {
+			return nil, fmt.Errorf("%w; set %s settings under sources.%s in .sbs/input-source.json or install %s%s", err, cfg.Type, cfg.Type, PluginPrefix, cfg.Type)
+		}
+		return nil, err
+	}
+	return source, nil
+}
+
+// CreateFromProject creates an InputSource by loading configuration from
+// project root. The source acts on that project's repository, wherever sbs
+// runs from.
 func (f *InputSourceFactory) CreateFromProject(projectRoot string) (InputSource, error) {
 	// Load configuration from project
 	cfg, err := config.LoadInputSourceConfig(projectRoot)
 	if err != nil {
 		// If we can't load config, fall back to GitHub default
 		// This maintains backward compatibility for projects without input source config
-		return f.createGitHubSource(), nil
+		return NewGitHubInputSourceIn(projectRoot), nil
 	}
 
 	// Create source from configuration
-	return f.Create(cfg)
+	return f.create(cfg, projectRoot)
 }
 
-// GetSupportedTypes returns a list of supported input source types
+// GetSupportedTypes returns a list of supported input source types,
+// including those of plugins on PATH
 func (f *InputSourceFactory) GetSupportedTypes() []string {
 	types := make([]string, 0, len(f.supportedTypes))
 	for sourceType := range f.supportedTypes {
 		types = append(types, sourceType)
 	}
+	for _, sourceType := range DiscoverPlugins() {
+		if _, builtin := f.supportedTypes[sourceType]; !builtin {
+			types = append(types, sourceType)
+		}
+	}
 	return types
 }
-
-// createGitHubSource is a helper to create GitHub sources consistently
-func (f *InputSourceFactory) createGitHubSource() InputSource {
-	creator := f.supportedTypes["github"]
-	return creator()
-}
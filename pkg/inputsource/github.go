@@ -2,6 +2,7 @@ package inputsource
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
 	"sbs/pkg/issue"
@@ -11,6 +12,11 @@ import (
 type GitHubClientInterface interface {
 	GetIssue(issueNumber int) (*issue.Issue, error)
 	ListIssues(searchQuery string, limit int) ([]issue.Issue, error)
+	CloseIssue(issueNumber int) error
+	ReopenIssue(issueNumber int) error
+	AddIssueLabel(issueNumber int, label string) error
+	RemoveIssueLabel(issueNumber int, label string) error
+	AddIssueComment(issueNumber int, body string) error
 }
 
 // GitHubInputSource wraps the existing GitHub issue functionality
@@ -21,13 +27,29 @@ type GitHubInputSource struct {
 // NewGitHubInputSource creates a new GitHubInputSource
 func NewGitHubInputSource() *GitHubInputSource {
 	return &GitHubInputSource{
-		client: issue.NewGitHubClient(),
+		client: issue.NewClient(),
 	}
 }
 
+// NewGitHubInputSourceIn creates a GitHubInputSource acting on the repository
+// checked out at dir, whatever the current directory is
+func NewGitHubInputSourceIn(dir string) *GitHubInputSource {
+	return &GitHubInputSource{
+		client: issue.NewClientIn(dir),
+	}
+}
+
+// stateLabels are the labels intermediate states are applied as
+var stateLabels = []string{StateInProgress, StateInReview}
+
 // GetWorkItem retrieves a GitHub issue by its number
 func (g *GitHubInputSource) GetWorkItem(id string) (*WorkItem, error) {
-	// Parse the ID as an issue number
+	// Parse the ID as an issue number, accepting forms such as "#123"
+	normalized, err := NormalizeWorkItemID("github", id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub issue number: %s", id)
+	}
+	id = normalized
 	issueNumber, err := strconv.Atoi(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid GitHub issue number: %s", id)
@@ -46,6 +68,7 @@ func (g *GitHubInputSource) GetWorkItem(id string) (*WorkItem, error) {
 		Title:  githubIssue.Title,
 		State:  githubIssue.State,
 		URL:    githubIssue.URL,
+		Body:   githubIssue.Body,
 	}, nil
 }
 
@@ -76,3 +99,59 @@ func (g *GitHubInputSource) ListWorkItems(searchQuery string, limit int) ([]*Wor
 func (g *GitHubInputSource) GetType() string {
 	return "github"
 }
+
+// TransitionWorkItem updates the state of a GitHub issue.
+// GitHub issues only have open/closed states, so "done" closes the issue, "open" reopens it,
+// and intermediate states such as "in-review" are applied as labels. The
+// label of the previous intermediate state is removed, so at most one applies.
+func (g *GitHubInputSource) TransitionWorkItem(id string, state string) error {
+	issueNumber, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid GitHub issue number: %s", id)
+	}
+
+	if err := g.transition(issueNumber, state); err != nil {
+		return fmt.Errorf("failed to transition GitHub issue #%d to %s: %w", issueNumber, state, err)
+	}
+
+	return nil
+}
+
+func (g *GitHubInputSource) transition(issueNumber int, state string) error {
+	current, err := g.client.GetIssue(issueNumber)
+	if err != nil {
+		return err
+	}
+	for _, label := range current.Labels {
+		if label != state && slices.Contains(stateLabels, label) {
+			if err := g.client.RemoveIssueLabel(issueNumber, label); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch state {
+	case StateDone:
+		return g.client.CloseIssue(issueNumber)
+	case StateOpen:
+		return g.client.ReopenIssue(issueNumber)
+	}
+	if slices.Contains(current.Labels, state) {
+		return nil
+	}
+	return g.client.AddIssueLabel(issueNumber, state)
+}
+
+// AddComment posts a comment on a GitHub issue
+func (g *GitHubInputSource) AddComment(id string, body string) error {
+	issueNumber, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid GitHub issue number: %s", id)
+	}
+
+	if err := g.client.AddIssueComment(issueNumber, body); err != nil {
+		return fmt.Errorf("failed to comment on GitHub issue #%d: %w", issueNumber, err)
+	}
+
+	return nil
+}
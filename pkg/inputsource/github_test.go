@@ -2,6 +2,7 @@ package inputsource
 
 import (
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,6 +21,12 @@ type mockGitHubClient struct {
 	lastLimit       int
 	getIssueError   error
 	listIssuesError error
+	closed          []int
+	reopened        []int
+	labels          map[int][]string
+	removedLabels   map[int][]string
+	comments        map[int][]string
+	stateError      error
 }
 
 func (m *mockGitHubClient) GetIssue(issueNumber int) (*issue.Issue, error) {
@@ -31,7 +38,11 @@ func (m *mockGitHubClient) GetIssue(issueNumber int) (*issue.Issue, error) {
 	}
 
 	if issue, exists := m.issues[issueNumber]; exists {
-		return issue, nil
+		current := *issue
+		if labels, labelled := m.labels[issueNumber]; labelled {
+			current.Labels = labels
+		}
+		return &current, nil
 	}
 
 	return nil, errors.New("issue not found")
@@ -49,6 +60,56 @@ func (m *mockGitHubClient) ListIssues(searchQuery string, limit int) ([]issue.Is
 	return m.listResult, nil
 }
 
+func (m *mockGitHubClient) CloseIssue(issueNumber int) error {
+	if m.stateError != nil {
+		return m.stateError
+	}
+	m.closed = append(m.closed, issueNumber)
+	return nil
+}
+
+func (m *mockGitHubClient) ReopenIssue(issueNumber int) error {
+	if m.stateError != nil {
+		return m.stateError
+	}
+	m.reopened = append(m.reopened, issueNumber)
+	return nil
+}
+
+func (m *mockGitHubClient) AddIssueLabel(issueNumber int, label string) error {
+	if m.stateError != nil {
+		return m.stateError
+	}
+	if m.labels == nil {
+		m.labels = make(map[int][]string)
+	}
+	m.labels[issueNumber] = append(m.labels[issueNumber], label)
+	return nil
+}
+
+func (m *mockGitHubClient) RemoveIssueLabel(issueNumber int, label string) error {
+	if m.stateError != nil {
+		return m.stateError
+	}
+	if m.removedLabels == nil {
+		m.removedLabels = make(map[int][]string)
+	}
+	m.removedLabels[issueNumber] = append(m.removedLabels[issueNumber], label)
+	m.labels[issueNumber] = slices.DeleteFunc(m.labels[issueNumber], func(l string) bool { return l == label })
+	return nil
+}
+
+func (m *mockGitHubClient) AddIssueComment(issueNumber int, body string) error {
+	if m.stateError != nil {
+		return m.stateError
+	}
+	if m.comments == nil {
+		m.comments = make(map[int][]string)
+	}
+	m.comments[issueNumber] = append(m.comments[issueNumber], body)
+	return nil
+}
+
 func TestGitHubInputSource_GetWorkItem(t *testing.T) {
 	mockClient := &mockGitHubClient{
 		issues: map[int]*issue.Issue{
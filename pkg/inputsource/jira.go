@@ -0,0 +1,285 @@
+package inputsource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/issue"
+)
+
+const (
+	// DefaultJiraTokenEnv names the environment variable holding the Jira
+	// API token when token_env isn't set
+	DefaultJiraTokenEnv = "JIRA_API_TOKEN"
+
+	// jiraTimeoutTool is the command_timeouts key bounding Jira requests
+	jiraTimeoutTool = "jira"
+
+	// defaultJiraListLimit matches the GitHub source's default
+	defaultJiraListLimit = 30
+)
+
+// JiraAPIError is a failed Jira REST API request
+type JiraAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // Jira's explanation, if any
+	TokenEnv   string
+}
+
+func (e *JiraAPIError) Error() string {
+	message := fmt.Sprintf("Jira API %s %s returned %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	if e.StatusCode == http.StatusUnauthorized {
+		message += fmt.Sprintf(" (check %s and the email setting)", e.TokenEnv)
+	}
+	return message
+}
+
+// JiraInputSource reads issues from a Jira instance through its REST API.
+// It is configured by the settings of .sbs/input-source.json:
+//
+//	url        base URL of the instance, e.g. https://example.atlassian.net (required)
+//	project    project key whose open issues are listed, e.g. ABC
+//	token_env  environment variable holding the API token (default JIRA_API_TOKEN)
+//	email      account email; Jira Cloud needs it for basic auth, without it
+//	           the token is sent as a bearer token (Data Center personal tokens)
+type JiraInputSource struct {
+	baseURL    string
+	project    string
+	email      string
+	tokenEnv   string
+	httpClient *http.Client
+}
+
+// NewJiraInputSource creates a Jira source from input source settings
+func NewJiraInputSource(settings map[string]interface{}) (*JiraInputSource, error) {
+	values := make(map[string]string)
+	for _, key := range []string{"url", "project", "token_env", "email"} {
+		value, exists := settings[key]
+		if !exists || value == nil {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("jira setting %s must be a string", key)
+		}
+		values[key] = strings.TrimSpace(text)
+	}
+
+	baseURL := strings.TrimRight(values["url"], "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("jira input source needs a url setting, e.g. https://example.atlassian.net")
+	}
+	if parsed, err := url.Parse(baseURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("jira url %q must be an http or https URL", values["url"])
+	}
+
+	project := strings.ToUpper(values["project"])
+	if project != "" {
+		if _, err := NormalizeWorkItemID("jira", project+"-1"); err != nil {
+			return nil, fmt.Errorf("invalid jira project key %q", values["project"])
+		}
+	}
+
+	tokenEnv := values["token_env"]
+	if tokenEnv == "" {
+		tokenEnv = DefaultJiraTokenEnv
+	}
+
+	return &JiraInputSource{
+		baseURL:    baseURL,
+		project:    project,
+		email:      values["email"],
+		tokenEnv:   tokenEnv,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// WithHTTPClient returns a copy of the source sending requests with client
+func (j *JiraInputSource) WithHTTPClient(client *http.Client) *JiraInputSource {
+	clone := *j
+	clone.httpClient = client
+	return &clone
+}
+
+// jiraIssue is an issue as the REST API returns it
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// toWorkItem converts an issue, linking to its page in the instance
+func (j *JiraInputSource) toWorkItem(result jiraIssue) *WorkItem {
+	return &WorkItem{
+		Source: "jira",
+		ID:     result.Key,
+		Title:  result.Fields.Summary,
+		State:  result.Fields.Status.Name,
+		URL:    j.baseURL + "/browse/" + result.Key,
+		Body:   result.Fields.Description,
+	}
+}
+
+// GetWorkItem fetches a Jira issue by its key, e.g. "ABC-123"
+func (j *JiraInputSource) GetWorkItem(id string) (*WorkItem, error) {
+	key, err := NormalizeWorkItemID("jira", id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("fields", "summary,status,description")
+	var result jiraIssue
+	if err := j.request(http.MethodGet, "/rest/api/2/issue/"+key+"?"+query.Encode(), &result); err != nil {
+		var apiErr *JiraAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("issue %s %w", key, issue.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch Jira issue %s: %w", key, err)
+	}
+
+	// Jira answers with the current key of an issue moved to another project
+	workItem := j.toWorkItem(result)
+	if workItem.ID, err = NormalizeWorkItemID("jira", result.Key); err != nil {
+		workItem.ID = key
+	}
+	return workItem, nil
+}
+
+// ListWorkItems fetches up to limit unresolved issues of the configured
+// project, most recently updated first. A search query matches their text.
+func (j *JiraInputSource) ListWorkItems(searchQuery string, limit int) ([]*WorkItem, error) {
+	if limit <= 0 {
+		limit = defaultJiraListLimit
+	}
+
+	query := url.Values{}
+	query.Set("jql", j.listJQL(searchQuery))
+	query.Set("maxResults", strconv.Itoa(limit))
+	query.Set("fields", "summary,status")
+
+	var result struct {
+		Issues []jiraIssue `json:"issues"`
+	}
+	err := j.request(http.MethodGet, "/rest/api/2/search?"+query.Encode(), &result)
+	var apiErr *JiraAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusGone {
+		// Jira Cloud retired /search in favour of /search/jql
+		err = j.request(http.MethodGet, "/rest/api/2/search/jql?"+query.Encode(), &result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jira issues: %w", err)
+	}
+
+	workItems := make([]*WorkItem, 0, len(result.Issues))
+	for _, item := range result.Issues {
+		// Keys end up in branch and session names
+		if _, err := NormalizeWorkItemID("jira", item.Key); err != nil {
+			continue
+		}
+		workItems = append(workItems, j.toWorkItem(item))
+		if len(workItems) == limit {
+			break
+		}
+	}
+	return workItems, nil
+}
+
+// listJQL builds the JQL query ListWorkItems runs
+func (j *JiraInputSource) listJQL(searchQuery string) string {
+	clauses := []string{"statusCategory != Done"}
+	if j.project != "" {
+		clauses = append([]string{"project = " + strconv.Quote(j.project)}, clauses...)
+	}
+	if searchQuery = strings.TrimSpace(searchQuery); searchQuery != "" {
+		clauses = append(clauses, "text ~ "+strconv.Quote(searchQuery))
+	}
+	return strings.Join(clauses, " AND ") + " ORDER BY updated DESC"
+}
+
+// GetType returns the input source type identifier
+func (j *JiraInputSource) GetType() string {
+	return "jira"
+}
+
+// request sends a request to path (relative to the instance's base URL) and
+// decodes the JSON response into out. Requests are logged like external
+// commands and bounded by the "jira" command timeout.
+func (j *JiraInputSource) request(method, path string, out interface{}) error {
+	logPath, _, _ := strings.Cut(path, "?")
+	args := []string{method, logPath}
+	logCtx := cmdlog.LogCommandGlobal(jiraTimeoutTool, args, cmdlog.GetCaller())
+	ctx, cancel, timeout := cmdtimeout.ContextFrom(context.Background(), jiraTimeoutTool)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, j.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := os.Getenv(j.tokenEnv); token != "" {
+		if j.email != "" {
+			req.SetBasicAuth(j.email, token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	start := time.Now()
+	resp, err := j.httpClient.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		err = cmdtimeout.Check(ctx, jiraTimeoutTool, args, timeout, err)
+		logCtx.LogCompletion(false, -1, err.Error(), duration)
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		err = cmdtimeout.Check(ctx, jiraTimeoutTool, args, timeout, err)
+		logCtx.LogCompletion(false, resp.StatusCode, err.Error(), duration)
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &JiraAPIError{Method: method, Path: logPath, StatusCode: resp.StatusCode, TokenEnv: j.tokenEnv}
+		var message struct {
+			ErrorMessages []string `json:"errorMessages"`
+		}
+		if json.Unmarshal(data, &message) == nil {
+			apiErr.Message = strings.Join(message.ErrorMessages, "; ")
+		}
+		logCtx.LogCompletion(false, resp.StatusCode, apiErr.Error(), duration)
+		return apiErr
+	}
+	logCtx.LogCompletion(true, 0, "", duration)
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse Jira API response: %w", err)
+		}
+	}
+	return nil
+}
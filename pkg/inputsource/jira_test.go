@@ -0,0 +1,140 @@
+package inputsource
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/issue"
+)
+
+// fakeJira serves ABC-123 and a search returning two issues, one with a key
+// sbs can't use, recording the last request
+func fakeJira(t *testing.T, last **http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = r
+		switch r.URL.Path {
+		case "/rest/api/2/issue/ABC-123":
+			w.Write([]byte(`{"key": "ABC-123", "fields": {"summary": "Fix login", "description": "Users can't log in", "status": {"name": "In Progress"}}}`))
+		case "/rest/api/2/issue/ABC-500":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errorMessages": ["You are not authenticated"]}`))
+		case "/rest/api/2/search":
+			w.WriteHeader(http.StatusGone)
+		case "/rest/api/2/search/jql":
+			w.Write([]byte(`{"issues": [{"key": "ABC-1", "fields": {"summary": "First", "status": {"name": "To Do"}}}, {"key": "odd.key", "fields": {}}, {"key": "ABC-2", "fields": {"summary": "Second", "status": {"name": "Open"}}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorMessages": ["Issue does not exist or you do not have permission to see it."]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestJiraInputSource_GetWorkItem(t *testing.T) {
+	var last *http.Request
+	server := fakeJira(t, &last)
+	t.Setenv(DefaultJiraTokenEnv, "secret")
+	source, err := NewJiraInputSource(map[string]interface{}{"url": server.URL + "/", "project": "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "jira", source.GetType())
+
+	workItem, err := source.GetWorkItem("abc-123")
+	require.NoError(t, err)
+	assert.Equal(t, &WorkItem{Source: "jira", ID: "ABC-123", Title: "Fix login", State: "In Progress", URL: server.URL + "/browse/ABC-123", Body: "Users can't log in"}, workItem)
+	assert.Equal(t, "Bearer secret", last.Header.Get("Authorization"))
+	assert.Equal(t, "jira:ABC-123", workItem.FullID())
+
+	_, err = source.GetWorkItem("ABC-7")
+	assert.True(t, errors.Is(err, issue.ErrNotFound))
+
+	_, err = source.GetWorkItem("ABC-500")
+	assert.EqualError(t, err, "failed to fetch Jira issue ABC-500: Jira API GET /rest/api/2/issue/ABC-500 returned 401: You are not authenticated (check JIRA_API_TOKEN and the email setting)")
+
+	_, err = source.GetWorkItem("123")
+	assert.ErrorContains(t, err, "invalid jira work item ID")
+}
+
+func TestJiraInputSource_BasicAuth(t *testing.T) {
+	var last *http.Request
+	server := fakeJira(t, &last)
+	t.Setenv("MY_JIRA_TOKEN", "secret")
+	source, err := NewJiraInputSource(map[string]interface{}{"url": server.URL, "token_env": "MY_JIRA_TOKEN", "email": "me@example.com"})
+	require.NoError(t, err)
+
+	_, err = source.GetWorkItem("ABC-123")
+	require.NoError(t, err)
+	email, token, ok := last.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "me@example.com", email)
+	assert.Equal(t, "secret", token)
+}
+
+func TestJiraInputSource_ListWorkItems(t *testing.T) {
+	var last *http.Request
+	server := fakeJira(t, &last)
+	t.Setenv(DefaultJiraTokenEnv, "")
+	source, err := NewJiraInputSource(map[string]interface{}{"url": server.URL, "project": "ABC"})
+	require.NoError(t, err)
+
+	workItems, err := source.ListWorkItems(`login "page"`, 10)
+	require.NoError(t, err)
+	require.Len(t, workItems, 2)
+	assert.Equal(t, "jira:ABC-1", workItems[0].FullID())
+	assert.Equal(t, "To Do", workItems[0].State)
+	assert.Equal(t, "jira:ABC-2", workItems[1].FullID())
+	assert.Equal(t, "/rest/api/2/search/jql", last.URL.Path)
+	assert.Equal(t, `project = "ABC" AND statusCategory != Done AND text ~ "login \"page\"" ORDER BY updated DESC`, last.URL.Query().Get("jql"))
+	assert.Equal(t, "10", last.URL.Query().Get("maxResults"))
+	assert.Empty(t, last.Header.Get("Authorization"), "no token is sent when the variable is unset")
+
+	workItems, err = source.ListWorkItems("", 1)
+	require.NoError(t, err)
+	assert.Len(t, workItems, 1)
+}
+
+func TestNewJiraInputSource_InvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		wantErr  string
+	}{
+		{"missing url", map[string]interface{}{"project": "ABC"}, "needs a url setting"},
+		{"relative url", map[string]interface{}{"url": "jira.example.com"}, "must be an http or https URL"},
+		{"bad project", map[string]interface{}{"url": "https://jira.example.com", "project": "A B"}, "invalid jira project key"},
+		{"non-string", map[string]interface{}{"url": "https://jira.example.com", "project": 12}, "jira setting project must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJiraInputSource(tt.settings)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestInputSourceFactory_Jira(t *testing.T) {
+	factory := NewInputSourceFactory()
+	source, err := factory.Create(&config.InputSourceConfig{Type: "jira", Settings: map[string]interface{}{"url": "https://jira.example.com", "project": "ABC"}})
+	require.NoError(t, err)
+	assert.IsType(t, &JiraInputSource{}, source)
+	assert.Contains(t, factory.GetSupportedTypes(), "jira")
+
+	_, err = factory.Create(&config.InputSourceConfig{Type: "jira"})
+	assert.ErrorContains(t, err, "invalid jira input source settings")
+
+	// Without a plugin on PATH, other projects reach Jira through sources
+	t.Setenv("PATH", t.TempDir())
+	source, err = factory.CreateCrossSource(&config.InputSourceConfig{Type: "jira", Settings: map[string]interface{}{"url": "https://jira.example.com"}})
+	require.NoError(t, err)
+	assert.IsType(t, &JiraInputSource{}, source)
+	_, err = factory.CreateCrossSource(&config.InputSourceConfig{Type: "jira", Settings: map[string]interface{}{}})
+	assert.ErrorContains(t, err, "needs a url setting")
+	assert.ErrorContains(t, err, "set jira settings under sources.jira in .sbs/input-source.json or install sbs-source-jira")
+}
@@ -0,0 +1,219 @@
+package inputsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/issue"
+)
+
+// PluginPrefix starts the name of input source plugin executables: a
+// "sbs-source-linear" on PATH provides the "linear" source
+const PluginPrefix = "sbs-source-"
+
+// PluginProtocolVersion is sent with every plugin request
+const PluginProtocolVersion = 1
+
+// Plugin request methods
+const (
+	PluginGetWorkItem   = "get_work_item"
+	PluginListWorkItems = "list_work_items"
+)
+
+// PluginRequest is written as JSON to a plugin's stdin. Settings are the
+// input source settings from .sbs/input-source.json, taken from its sources
+// map when the plugin isn't the primary source.
+type PluginRequest struct {
+	Version  int                    `json:"version"`
+	Method   string                 `json:"method"`
+	ID       string                 `json:"id,omitempty"`
+	Query    string                 `json:"query,omitempty"`
+	Limit    int                    `json:"limit,omitempty"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// PluginResponse is read as JSON from a plugin's stdout. A plugin reports a
+// failure with error, and sets not_found when the work item doesn't exist.
+type PluginResponse struct {
+	WorkItem  *WorkItem   `json:"work_item,omitempty"`
+	WorkItems []*WorkItem `json:"work_items,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	NotFound  bool        `json:"not_found,omitempty"`
+}
+
+// PluginInputSource is an input source served by an external executable
+type PluginInputSource struct {
+	sourceType string
+	path       string
+	settings   map[string]interface{}
+}
+
+// NewPluginInputSource creates a source of sourceType served by the plugin at path
+func NewPluginInputSource(sourceType, path string, settings map[string]interface{}) *PluginInputSource {
+	return &PluginInputSource{sourceType: sourceType, path: path, settings: settings}
+}
+
+// FindPlugin returns the path of the plugin providing sourceType on PATH
+func FindPlugin(sourceType string) (string, bool) {
+	if ValidateSource(sourceType) != nil {
+		return "", false
+	}
+	path, err := exec.LookPath(PluginPrefix + sourceType)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// DiscoverPlugins returns the source types provided by plugins on PATH, in
+// name order
+func DiscoverPlugins() []string {
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			sourceType, found := strings.CutPrefix(entry.Name(), PluginPrefix)
+			if !found || seen[sourceType] || ValidateSource(sourceType) != nil {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(dir, entry.Name())); err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+				seen[sourceType] = true
+			}
+		}
+	}
+	types := make([]string, 0, len(seen))
+	for sourceType := range seen {
+		types = append(types, sourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// GetWorkItem asks the plugin for one work item
+func (p *PluginInputSource) GetWorkItem(id string) (*WorkItem, error) {
+	id, err := NormalizeWorkItemID(p.sourceType, id)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.call(PluginRequest{Method: PluginGetWorkItem, ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if response.WorkItem == nil {
+		return nil, fmt.Errorf("%s returned no work item for %s", filepath.Base(p.path), id)
+	}
+	return p.adopt(response.WorkItem, id)
+}
+
+// ListWorkItems asks the plugin for work items matching searchQuery
+func (p *PluginInputSource) ListWorkItems(searchQuery string, limit int) ([]*WorkItem, error) {
+	response, err := p.call(PluginRequest{Method: PluginListWorkItems, Query: searchQuery, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	workItems := make([]*WorkItem, 0, len(response.WorkItems))
+	for _, workItem := range response.WorkItems {
+		if workItem == nil {
+			continue
+		}
+		adopted, err := p.adopt(workItem, workItem.ID)
+		if err != nil {
+			return nil, err
+		}
+		workItems = append(workItems, adopted)
+		if limit > 0 && len(workItems) == limit {
+			break
+		}
+	}
+	return workItems, nil
+}
+
+// GetType returns the source type the plugin provides
+func (p *PluginInputSource) GetType() string {
+	return p.sourceType
+}
+
+// adopt stamps a work item returned by the plugin with this source and
+// validates its ID, since it ends up in branch and session names
+func (p *PluginInputSource) adopt(workItem *WorkItem, id string) (*WorkItem, error) {
+	if workItem.ID == "" {
+		workItem.ID = id
+	}
+	normalized, err := NormalizeWorkItemID(p.sourceType, workItem.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s returned an unusable work item: %w", filepath.Base(p.path), err)
+	}
+	adopted := *workItem
+	adopted.Source = p.sourceType
+	adopted.ID = normalized
+	return &adopted, nil
+}
+
+// call runs the plugin with one request and decodes its response
+func (p *PluginInputSource) call(request PluginRequest) (*PluginResponse, error) {
+	request.Version = PluginProtocolVersion
+	request.Settings = p.settings
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", request.Method, err)
+	}
+
+	tool := filepath.Base(p.path)
+	args := []string{request.Method}
+	logCtx := cmdlog.LogCommandGlobal(tool, args, cmdlog.GetCaller())
+	ctx, cancel, timeout := cmdtimeout.ContextFrom(context.Background(), tool)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+
+	start := time.Now()
+	err = cmd.Run()
+	duration := time.Since(start)
+
+	var response PluginResponse
+	decodeErr := json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil && (decodeErr != nil || response.Error == "") {
+		err = cmdtimeout.Check(ctx, tool, args, timeout, err)
+		message := strings.TrimSpace(stderr.String())
+		logCtx.LogCompletion(false, cmd.ProcessState.ExitCode(), message, duration)
+		if message != "" {
+			return nil, fmt.Errorf("%s %s failed: %w: %s", tool, request.Method, err, message)
+		}
+		return nil, fmt.Errorf("%s %s failed: %w", tool, request.Method, err)
+	}
+	if decodeErr != nil {
+		logCtx.LogCompletion(false, 0, decodeErr.Error(), duration)
+		return nil, fmt.Errorf("%s %s returned invalid JSON: %w", tool, request.Method, decodeErr)
+	}
+	if response.Error != "" {
+		logCtx.LogCompletion(false, cmd.ProcessState.ExitCode(), response.Error, duration)
+		if response.NotFound {
+			return nil, fmt.Errorf("%s %w: %s", request.ID, issue.ErrNotFound, response.Error)
+		}
+		return nil, errors.New(response.Error)
+	}
+	logCtx.LogCompletion(true, 0, "", duration)
+	return &response, nil
+}
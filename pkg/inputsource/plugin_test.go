@@ -0,0 +1,121 @@
+package inputsource
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/issue"
+)
+
+// fakeYouTrackPlugin answers like a YouTrack plugin: PROJ-42 exists, anything else
+// is not found, and listing returns two items
+const fakeYouTrackPlugin = `#!/bin/sh
+request=$(cat)
+echo "$request" > "$(dirname "$0")/last-request.json"
+case "$1" in
+list_work_items)
+	echo '{"work_items": [{"id": "proj-1", "title": "First", "state": "open"}, {"id": "PROJ-2", "title": "Second", "state": "open"}]}' ;;
+get_work_item)
+	case "$request" in
+	*'"id":"PROJ-42"'*) echo '{"work_item": {"source": "other", "title": "Fix login", "state": "open", "url": "https://youtrack.example.com/browse/PROJ-42"}}' ;;
+	*'"id":"PROJ-500"'*) echo "youtrack is down" >&2; exit 2 ;;
+	*) echo '{"error": "issue does not exist", "not_found": true}' ;;
+	esac ;;
+esac
+`
+
+// installPlugin puts an executable sbs-source-<sourceType> first on PATH
+func installPlugin(t *testing.T, sourceType, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginPrefix+sourceType), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestPluginInputSource(t *testing.T) {
+	dir := installPlugin(t, "youtrack", fakeYouTrackPlugin)
+	source, err := NewInputSourceFactory().Create(&config.InputSourceConfig{Type: "youtrack", Settings: map[string]interface{}{"url": "https://youtrack.example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, "youtrack", source.GetType())
+
+	workItem, err := source.GetWorkItem(" PROJ-42 ")
+	require.NoError(t, err)
+	assert.Equal(t, &WorkItem{Source: "youtrack", ID: "PROJ-42", Title: "Fix login", State: "open", URL: "https://youtrack.example.com/browse/PROJ-42"}, workItem)
+	request, err := os.ReadFile(filepath.Join(dir, "last-request.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": 1, "method": "get_work_item", "id": "PROJ-42", "settings": {"url": "https://youtrack.example.com"}}`, string(request))
+
+	_, err = source.GetWorkItem("PROJ-7")
+	assert.True(t, errors.Is(err, issue.ErrNotFound))
+	assert.EqualError(t, err, "PROJ-7 not found: issue does not exist")
+
+	_, err = source.GetWorkItem("PROJ-500")
+	assert.ErrorContains(t, err, "sbs-source-youtrack get_work_item failed: exit status 2: youtrack is down")
+
+	workItems, err := source.ListWorkItems("login", 1)
+	require.NoError(t, err)
+	require.Len(t, workItems, 1)
+	assert.Equal(t, "youtrack:proj-1", workItems[0].FullID())
+}
+
+func TestInputSourceFactory_CrossSourcePlugin(t *testing.T) {
+	// A plugin beats the built-in source of the same type outside the
+	// project's primary source, and its items follow that type's ID rules
+	dir := installPlugin(t, "jira", fakeYouTrackPlugin)
+	source, err := NewInputSourceFactory().CreateCrossSource(&config.InputSourceConfig{Type: "jira", Settings: map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.IsType(t, &PluginInputSource{}, source)
+
+	workItem, err := source.GetWorkItem("proj-42")
+	require.NoError(t, err)
+	assert.Equal(t, "jira:PROJ-42", workItem.FullID())
+	request, err := os.ReadFile(filepath.Join(dir, "last-request.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": 1, "method": "get_work_item", "id": "PROJ-42"}`, string(request))
+
+	workItems, err := source.ListWorkItems("login", 1)
+	require.NoError(t, err)
+	require.Len(t, workItems, 1)
+	assert.Equal(t, "jira:PROJ-1", workItems[0].FullID())
+
+	// The primary source stays built in
+	source, err = NewInputSourceFactory().Create(&config.InputSourceConfig{Type: "jira", Settings: map[string]interface{}{"url": "https://jira.example.com"}})
+	require.NoError(t, err)
+	assert.IsType(t, &JiraInputSource{}, source)
+}
+
+func TestPluginInputSource_InvalidResponses(t *testing.T) {
+	installPlugin(t, "linear", "#!/bin/sh\ncat > /dev/null\necho not json\n")
+	source, err := NewInputSourceFactory().Create(&config.InputSourceConfig{Type: "linear"})
+	require.NoError(t, err)
+	_, err = source.GetWorkItem("abc-1")
+	assert.ErrorContains(t, err, "sbs-source-linear get_work_item returned invalid JSON")
+
+	installPlugin(t, "linear", "#!/bin/sh\ncat > /dev/null\necho '{\"work_items\": [{\"id\": \"a:b\"}]}'\n")
+	source, err = NewInputSourceFactory().Create(&config.InputSourceConfig{Type: "linear"})
+	require.NoError(t, err)
+	_, err = source.ListWorkItems("", 10)
+	assert.ErrorContains(t, err, "returned an unusable work item")
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	dir := installPlugin(t, "youtrack", fakeYouTrackPlugin)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginPrefix+"notes"), []byte("not executable"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginPrefix+"Bad_Name"), []byte("#!/bin/sh\n"), 0755))
+	installPlugin(t, "linear", "#!/bin/sh\n")
+
+	assert.Equal(t, []string{"linear", "youtrack"}, DiscoverPlugins())
+	assert.Contains(t, NewInputSourceFactory().GetSupportedTypes(), "linear")
+
+	_, found := FindPlugin("youtrack")
+	assert.True(t, found)
+	_, found = FindPlugin("notes")
+	assert.False(t, found)
+}
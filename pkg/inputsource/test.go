@@ -40,16 +40,8 @@ func (t *TestInputSource) GetWorkItem(id string) (*WorkItem, error) {
 
 // isValidTestID validates that a test ID contains only allowed characters
 func isValidTestID(id string) bool {
-	if len(id) == 0 {
-		return false
-	}
-
-	for _, r := range id {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_') {
-			return false
-		}
-	}
-	return true
+	_, err := NormalizeWorkItemID("test", id)
+	return id != "" && err == nil
 }
 
 // ListWorkItems retrieves a list of test work items, optionally filtered by search query.
@@ -65,3 +57,20 @@ func (t *TestInputSource) ListWorkItems(searchQuery string, limit int) ([]*WorkI
 func (t *TestInputSource) GetType() string {
 	return "test"
 }
+
+// TransitionWorkItem accepts any state for test work items.
+// Test items have no backing tracker, so only the ID is validated.
+func (t *TestInputSource) TransitionWorkItem(id string, state string) error {
+	if !isValidTestID(strings.TrimSpace(id)) {
+		return fmt.Errorf("invalid test work item ID: %s", id)
+	}
+	return nil
+}
+
+// AddComment accepts comments for test work items without storing them
+func (t *TestInputSource) AddComment(id string, body string) error {
+	if !isValidTestID(strings.TrimSpace(id)) {
+		return fmt.Errorf("invalid test work item ID: %s", id)
+	}
+	return nil
+}
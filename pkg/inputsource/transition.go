@@ -0,0 +1,65 @@
+package inputsource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Work item states understood by sbs transitions.
+// Sources map these onto their own workflow (e.g. GitHub close/reopen, JIRA transitions).
+const (
+	StateOpen       = "open"
+	StateInProgress = "in-progress"
+	StateInReview   = "in-review"
+	StateDone       = "done"
+)
+
+// StateTransitioner is implemented by input sources that can update the state
+// of a work item in the underlying tracker
+type StateTransitioner interface {
+	// TransitionWorkItem moves the work item with the given source-specific ID to the target state
+	TransitionWorkItem(id string, state string) error
+}
+
+// NormalizeState converts user-supplied state names into a canonical state.
+// Common aliases such as "closed" or "review" are accepted.
+func NormalizeState(state string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(state))
+	normalized = strings.ReplaceAll(normalized, "_", "-")
+	normalized = strings.ReplaceAll(normalized, " ", "-")
+
+	switch normalized {
+	case StateOpen, "reopen", "todo":
+		return StateOpen, nil
+	case StateInProgress, "progress", "wip":
+		return StateInProgress, nil
+	case StateInReview, "review":
+		return StateInReview, nil
+	case StateDone, "closed", "close", "complete", "completed":
+		return StateDone, nil
+	case "":
+		return "", fmt.Errorf("state cannot be empty")
+	default:
+		return "", fmt.Errorf("unknown state: %s (valid states: %s)", state, strings.Join(SupportedStates(), ", "))
+	}
+}
+
+// SupportedStates returns the canonical states accepted by NormalizeState
+func SupportedStates() []string {
+	return []string{StateOpen, StateInProgress, StateInReview, StateDone}
+}
+
+// TransitionWorkItem transitions a work item if the source supports state changes
+func TransitionWorkItem(source InputSource, id string, state string) error {
+	transitioner, ok := source.(StateTransitioner)
+	if !ok {
+		return fmt.Errorf("input source %s does not support state transitions", source.GetType())
+	}
+
+	canonical, err := NormalizeState(state)
+	if err != nil {
+		return err
+	}
+
+	return transitioner.TransitionWorkItem(id, canonical)
+}
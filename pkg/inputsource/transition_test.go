@@ -0,0 +1,141 @@
+package inputsource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/issue"
+)
+
+// noTransitionSource is an input source that does not implement StateTransitioner
+type noTransitionSource struct{}
+
+func (n *noTransitionSource) GetWorkItem(id string) (*WorkItem, error) { return nil, nil }
+func (n *noTransitionSource) ListWorkItems(searchQuery string, limit int) ([]*WorkItem, error) {
+	return nil, nil
+}
+func (n *noTransitionSource) GetType() string { return "plain" }
+
+func TestNormalizeState(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{"done", StateDone, false},
+		{"Closed", StateDone, false},
+		{"in-review", StateInReview, false},
+		{"in_review", StateInReview, false},
+		{"review", StateInReview, false},
+		{"In Progress", StateInProgress, false},
+		{"open", StateOpen, false},
+		{"reopen", StateOpen, false},
+		{"", "", true},
+		{"shipped", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			state, err := NormalizeState(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, state)
+		})
+	}
+}
+
+func TestTransitionWorkItem_GitHub(t *testing.T) {
+	// newMock returns a client knowing issue number with the given labels
+	newMock := func(number int, labels ...string) *mockGitHubClient {
+		return &mockGitHubClient{
+			issues: map[int]*issue.Issue{number: {Number: number, Title: "Issue", State: "OPEN"}},
+			labels: map[int][]string{number: labels},
+		}
+	}
+
+	t.Run("done_closes_issue", func(t *testing.T) {
+		mockClient := newMock(123)
+		source := &GitHubInputSource{client: mockClient}
+
+		err := TransitionWorkItem(source, "123", "done")
+		require.NoError(t, err)
+		assert.Equal(t, []int{123}, mockClient.closed)
+	})
+
+	t.Run("open_reopens_issue", func(t *testing.T) {
+		mockClient := newMock(5)
+		source := &GitHubInputSource{client: mockClient}
+
+		err := TransitionWorkItem(source, "5", "reopen")
+		require.NoError(t, err)
+		assert.Equal(t, []int{5}, mockClient.reopened)
+	})
+
+	t.Run("intermediate_state_adds_label", func(t *testing.T) {
+		mockClient := newMock(9)
+		source := &GitHubInputSource{client: mockClient}
+
+		err := TransitionWorkItem(source, "9", "review")
+		require.NoError(t, err)
+		assert.Equal(t, []string{StateInReview}, mockClient.labels[9])
+	})
+
+	t.Run("previous_state_label_is_removed", func(t *testing.T) {
+		mockClient := newMock(9, "bug", StateInProgress)
+		source := &GitHubInputSource{client: mockClient}
+
+		require.NoError(t, TransitionWorkItem(source, "9", "review"))
+		assert.Equal(t, []string{"bug", StateInReview}, mockClient.labels[9])
+		assert.Equal(t, []string{StateInProgress}, mockClient.removedLabels[9])
+
+		require.NoError(t, TransitionWorkItem(source, "9", "done"))
+		assert.Equal(t, []string{"bug"}, mockClient.labels[9], "closing drops the intermediate state label")
+		assert.Equal(t, []int{9}, mockClient.closed)
+	})
+
+	t.Run("current_state_label_is_kept", func(t *testing.T) {
+		mockClient := newMock(9, StateInReview)
+		source := &GitHubInputSource{client: mockClient}
+
+		require.NoError(t, TransitionWorkItem(source, "9", "review"))
+		assert.Equal(t, []string{StateInReview}, mockClient.labels[9])
+		assert.Empty(t, mockClient.removedLabels[9])
+	})
+
+	t.Run("invalid_issue_number", func(t *testing.T) {
+		source := &GitHubInputSource{client: &mockGitHubClient{}}
+
+		err := TransitionWorkItem(source, "abc", "done")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid GitHub issue number")
+	})
+
+	t.Run("client_error", func(t *testing.T) {
+		mockClient := newMock(1)
+		mockClient.stateError = errors.New("boom")
+		source := &GitHubInputSource{client: mockClient}
+
+		err := TransitionWorkItem(source, "1", "done")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to transition GitHub issue #1")
+	})
+}
+
+func TestTransitionWorkItem_TestSource(t *testing.T) {
+	source := NewTestInputSource()
+
+	assert.NoError(t, TransitionWorkItem(source, "my-test", "done"))
+	assert.Error(t, TransitionWorkItem(source, "bad id", "done"))
+	assert.Error(t, TransitionWorkItem(source, "my-test", "unknown-state"))
+}
+
+func TestTransitionWorkItem_UnsupportedSource(t *testing.T) {
+	err := TransitionWorkItem(&noTransitionSource{}, "1", "done")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support state transitions")
+}
@@ -0,0 +1,148 @@
+package inputsource
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxSourceLength is the longest allowed source name
+	MaxSourceLength = 16
+	// MaxWorkItemIDLength is the longest allowed work item ID; IDs end up in
+	// branch, tmux session and sandbox names
+	MaxWorkItemIDLength = 64
+)
+
+// sourcePattern matches source names, the same lowercase words branch templates parse
+var sourcePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// idFormat describes the IDs one source accepts
+type idFormat struct {
+	pattern   *regexp.Regexp
+	example   string
+	describe  string
+	normalize func(id string) string
+}
+
+// genericIDFormat applies to sources without a format of their own. Dots and
+// colons are excluded since tmux reads them as window and pane separators.
+var genericIDFormat = idFormat{
+	pattern:  regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`),
+	example:  "abc-123",
+	describe: "letters, digits, hyphens and underscores",
+}
+
+var idFormats = map[string]idFormat{
+	"github": {
+		pattern:  regexp.MustCompile(`^[1-9][0-9]*$`),
+		example:  "123",
+		describe: "an issue number",
+		normalize: func(id string) string {
+			// Accept "#123" and "0123" for issue 123
+			if n, err := strconv.Atoi(strings.TrimPrefix(id, "#")); err == nil && n > 0 {
+				return strconv.Itoa(n)
+			}
+			return id
+		},
+	},
+	"jira": {
+		pattern:   regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[1-9][0-9]*$`),
+		example:   "PROJ-456",
+		describe:  "a project key and number",
+		normalize: strings.ToUpper,
+	},
+	"test": {
+		pattern:  genericIDFormat.pattern,
+		example:  "my-test",
+		describe: "letters, digits, hyphens and underscores",
+	},
+}
+
+// formatFor returns the ID format of a source
+func formatFor(source string) idFormat {
+	if format, exists := idFormats[source]; exists {
+		return format
+	}
+	return genericIDFormat
+}
+
+// NormalizeSource folds a source name to its canonical lowercase form
+func NormalizeSource(source string) string {
+	return strings.ToLower(strings.TrimSpace(source))
+}
+
+// ValidateSource checks that a (normalized) source name can be used in branch and session names
+func ValidateSource(source string) error {
+	if source == "" {
+		return fmt.Errorf("source cannot be empty")
+	}
+	if len(source) > MaxSourceLength || !sourcePattern.MatchString(source) {
+		return fmt.Errorf("invalid source %q (expected a lowercase name of up to %d letters and digits, e.g. github)", source, MaxSourceLength)
+	}
+	return nil
+}
+
+// NormalizeWorkItemID validates a work item ID for a source and returns it in
+// canonical form, e.g. "#0123" becomes "123" for GitHub and "proj-4" becomes
+// "PROJ-4" for JIRA
+func NormalizeWorkItemID(source, id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", fmt.Errorf("%s work item ID cannot be empty", source)
+	}
+	format := formatFor(source)
+	if format.normalize != nil {
+		id = format.normalize(id)
+	}
+	if len(id) > MaxWorkItemIDLength {
+		return "", fmt.Errorf("%s work item ID is %d characters long (maximum %d)", source, len(id), MaxWorkItemIDLength)
+	}
+	if !format.pattern.MatchString(id) {
+		return "", fmt.Errorf("invalid %s work item ID %q (expected %s, e.g. %s:%s)", source, id, format.describe, source, format.example)
+	}
+	return id, nil
+}
+
+// ValidFormats describes the work item ID format of each source, for error messages
+func ValidFormats(sources []string) string {
+	sorted := append([]string(nil), sources...)
+	sort.Strings(sorted)
+	lines := make([]string, 0, len(sorted))
+	for _, source := range sorted {
+		format := formatFor(source)
+		lines = append(lines, fmt.Sprintf("  %s:%s (%s)", source, format.example, format.describe))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatExamples lists an example ID for each source, for one-line error messages
+func formatExamples(sources []string) string {
+	sorted := append([]string(nil), sources...)
+	sort.Strings(sorted)
+	examples := make([]string, 0, len(sorted))
+	for _, source := range sorted {
+		examples = append(examples, "'"+source+":"+formatFor(source).example+"'")
+	}
+	if len(examples) < 2 {
+		return strings.Join(examples, "")
+	}
+	return strings.Join(examples[:len(examples)-1], ", ") + " or " + examples[len(examples)-1]
+}
+
+// ParseConfiguredWorkItemID parses a namespaced work item ID like
+// ParseWorkItemID, and additionally requires its source to be one of sources
+func ParseConfiguredWorkItemID(input string, sources []string) (*WorkItem, error) {
+	workItem, err := ParseWorkItemID(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		if workItem.Source == source {
+			return workItem, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown work item source %q in %s; valid formats:\n%s", workItem.Source, input, ValidFormats(sources))
+}
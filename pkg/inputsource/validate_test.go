@@ -0,0 +1,86 @@
+package inputsource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeWorkItemID(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		id       string
+		expected string
+		errPart  string
+	}{
+		{"github number", "github", "123", "123", ""},
+		{"github hash prefix", "github", "#123", "123", ""},
+		{"github leading zeros", "github", "00123", "123", ""},
+		{"github zero", "github", "0", "", "an issue number"},
+		{"github text", "github", "abc", "", "e.g. github:123"},
+		{"jira lowercase", "jira", "proj-456", "PROJ-456", ""},
+		{"jira missing number", "jira", "PROJ", "", "a project key and number"},
+		{"test with underscores", "test", "quick_test-2", "quick_test-2", ""},
+		{"test with dot", "test", "a.b", "", "letters, digits, hyphens and underscores"},
+		{"test leading hyphen", "test", "-x", "", "invalid test work item ID"},
+		{"unknown source generic", "linear", "ENG-12", "ENG-12", ""},
+		{"unknown source with slash", "linear", "ENG/12", "", "invalid linear work item ID"},
+		{"trimmed", "test", "  quick  ", "quick", ""},
+		{"empty", "test", "  ", "", "cannot be empty"},
+		{"too long", "test", strings.Repeat("a", MaxWorkItemIDLength+1), "", "maximum 64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := NormalizeWorkItemID(tt.source, tt.id)
+			if tt.errPart != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errPart)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	assert.NoError(t, ValidateSource("github"))
+	assert.NoError(t, ValidateSource("jira2"))
+	assert.Error(t, ValidateSource(""))
+	assert.Error(t, ValidateSource("git-hub"))
+	assert.Error(t, ValidateSource("2jira"))
+	assert.Error(t, ValidateSource(strings.Repeat("a", MaxSourceLength+1)))
+}
+
+func TestParseWorkItemID_Normalizes(t *testing.T) {
+	item, err := ParseWorkItemID("GitHub:#42")
+	require.NoError(t, err)
+	assert.Equal(t, "github:42", item.FullID())
+
+	item, err = ParseWorkItemID(" JIRA : proj-7 ")
+	require.NoError(t, err)
+	assert.Equal(t, "jira:PROJ-7", item.FullID())
+
+	_, err = ParseWorkItemID("git hub:1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid source")
+
+	_, err = ParseWorkItemID("no-colon")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'github:123', 'jira:PROJ-456' or 'test:my-test'")
+}
+
+func TestParseConfiguredWorkItemID(t *testing.T) {
+	item, err := ParseConfiguredWorkItemID("TEST:quick", []string{"github", "test"})
+	require.NoError(t, err)
+	assert.Equal(t, "test:quick", item.FullID())
+
+	_, err = ParseConfiguredWorkItemID("jira:PROJ-1", []string{"github", "test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown work item source "jira"`)
+	assert.Contains(t, err.Error(), "github:123 (an issue number)")
+	assert.Contains(t, err.Error(), "test:my-test")
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"sbs/pkg/branchname"
 )
 
 const (
@@ -26,8 +28,9 @@ type WorkItem struct {
 	Source string `json:"source"` // github, test, jira, etc.
 	ID     string `json:"id"`     // The source-specific identifier
 	Title  string `json:"title"`
-	State  string `json:"state"` // open, closed, etc.
-	URL    string `json:"url"`   // Optional URL to the work item
+	State  string `json:"state"`          // open, closed, etc.
+	URL    string `json:"url"`            // Optional URL to the work item
+	Body   string `json:"body,omitempty"` // Description, when the source fetched it
 }
 
 // FullID returns the full namespaced ID in the format "source:id"
@@ -35,42 +38,33 @@ func (w *WorkItem) FullID() string {
 	return fmt.Sprintf("%s:%s", w.Source, w.ID)
 }
 
-// GetBranchName returns the git branch name using namespaced format
-// Format: issue-{source}-{id}-{title-slug}
+// GetBranchName returns the git branch name rendered from the configured
+// branch template (default: issue-{source}-{id}-{title-slug})
 func (w *WorkItem) GetBranchName() string {
-	titleSlug := createTitleSlug(w.Title)
-	if titleSlug == "" {
-		return fmt.Sprintf("issue-%s-%s", w.Source, w.ID)
-	}
-	return fmt.Sprintf("issue-%s-%s-%s", w.Source, w.ID, titleSlug)
+	return branchname.Render(branchname.Template(), w.Source, w.ID, createTitleSlug(w.Title))
 }
 
 // ParseWorkItemID parses a work item ID and returns a WorkItem
-// Requires namespaced format "source:id" (e.g., "github:123", "test:quick")
+// Requires namespaced format "source:id" (e.g., "github:123", "test:quick").
+// The source is case-folded and the ID validated and normalized for its source.
 func ParseWorkItemID(input string) (*WorkItem, error) {
-	if input == "" {
+	if strings.TrimSpace(input) == "" {
 		return nil, fmt.Errorf("work item ID cannot be empty")
 	}
 
 	// Parse namespaced format "source:id"
 	parts := strings.Split(input, ":")
 	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid work item ID format: %s (expected 'source:id' format, e.g., 'github:123' or 'test:quick')", input)
+		return nil, fmt.Errorf("invalid work item ID format: %s (expected 'source:id' format, e.g., %s)", input, formatExamples(knownSources()))
 	}
 
-	source := strings.TrimSpace(parts[0])
-	id := strings.TrimSpace(parts[1])
-
-	if source == "" {
-		return nil, fmt.Errorf("source cannot be empty in work item ID: %s", input)
-	}
-	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty in work item ID: %s", input)
+	source := NormalizeSource(parts[0])
+	if err := ValidateSource(source); err != nil {
+		return nil, fmt.Errorf("%w in work item ID: %s", err, input)
 	}
-
-	// Validate ID doesn't contain spaces or special characters that would break parsing
-	if strings.Contains(id, " ") {
-		return nil, fmt.Errorf("work item ID cannot contain spaces: %s", input)
+	id, err := NormalizeWorkItemID(source, parts[1])
+	if err != nil {
+		return nil, err
 	}
 
 	return &WorkItem{
@@ -79,6 +73,15 @@ func ParseWorkItemID(input string) (*WorkItem, error) {
 	}, nil
 }
 
+// knownSources returns the sources with a dedicated ID format
+func knownSources() []string {
+	sources := make([]string, 0, len(idFormats))
+	for source := range idFormats {
+		sources = append(sources, source)
+	}
+	return sources
+}
+
 // createTitleSlug creates a URL-safe slug from a title
 func createTitleSlug(title string) string {
 	// Trim whitespace
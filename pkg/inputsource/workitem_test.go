@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/branchname"
 )
 
 func TestWorkItem_ParseID(t *testing.T) {
@@ -133,6 +135,17 @@ func TestWorkItem_GetBranchName(t *testing.T) {
 	}
 }
 
+func TestWorkItem_GetBranchName_Template(t *testing.T) {
+	t.Cleanup(func() { branchname.SetTemplate("") })
+	branchname.SetTemplate("feature/{source}/{id}-{title}")
+
+	item := &WorkItem{Source: "github", ID: "123", Title: "Fix authentication bug"}
+	assert.Equal(t, "feature/github/123-fix-authentication-bug", item.GetBranchName())
+
+	item.Title = ""
+	assert.Equal(t, "feature/github/123", item.GetBranchName())
+}
+
 func TestWorkItem_EdgeCases(t *testing.T) {
 	t.Run("special_characters_in_id", func(t *testing.T) {
 		tests := []struct {
@@ -0,0 +1,457 @@
+package issue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/git"
+)
+
+const (
+	// DefaultAPIURL is the REST API root of github.com
+	DefaultAPIURL = "https://api.github.com"
+
+	// apiTimeoutTool is the command_timeouts key bounding API requests
+	apiTimeoutTool = "github"
+
+	// maxPerPage is the largest page the REST API returns
+	maxPerPage = 100
+
+	// defaultListLimit matches gh issue list's default
+	defaultListLimit = 30
+)
+
+// APIError is a failed GitHub REST API request
+type APIError struct {
+	Method      string
+	Path        string
+	StatusCode  int
+	Message     string // GitHub's explanation, if any
+	RateLimited bool
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("GitHub API %s %s returned %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	switch {
+	case e.RateLimited:
+		message += " (rate limit exceeded; a github_token raises the limit)"
+	case e.StatusCode == http.StatusUnauthorized:
+		message += " (check github_token or GITHUB_TOKEN)"
+	}
+	return message
+}
+
+// APIClient talks to the GitHub REST API directly, so sbs works without the
+// gh CLI, e.g. in CI. It acts on the repository named by GH_REPO or, like gh,
+// the current directory's origin remote.
+type APIClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	dir        string // Checkout whose origin remote names the repository
+	repo       *repositoryName
+}
+
+// repositoryName is the "owner/name" the client acts on, resolved on first use
+type repositoryName struct {
+	once sync.Once
+	name string
+	err  error
+}
+
+// NewAPIClient creates a REST API client from cfg. Without a configured
+// token it uses GH_TOKEN or GITHUB_TOKEN, as gh does.
+func NewAPIClient(cfg ClientConfig) *APIClient {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.APIURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	return &APIClient{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: http.DefaultClient,
+		repo:       &repositoryName{},
+	}
+}
+
+// WithRepository returns a copy of the client acting on the "owner/name"
+// repository instead of the current directory's
+func (c *APIClient) WithRepository(name string) *APIClient {
+	clone := *c
+	clone.repo = &repositoryName{name: name}
+	clone.repo.once.Do(func() {}) // already resolved
+	return &clone
+}
+
+// WithDirectory returns a copy of the client acting on the repository
+// checked out at dir instead of the current directory's. GH_REPO still takes
+// precedence, as it does for gh.
+func (c *APIClient) WithDirectory(dir string) *APIClient {
+	clone := *c
+	clone.dir = dir
+	clone.repo = &repositoryName{}
+	return &clone
+}
+
+// WithHTTPClient returns a copy of the client sending requests with client
+func (c *APIClient) WithHTTPClient(client *http.Client) *APIClient {
+	clone := *c
+	clone.httpClient = client
+	return &clone
+}
+
+// Repository returns the "owner/name" the client acts on
+func (c *APIClient) Repository() (string, error) {
+	c.repo.once.Do(func() {
+		c.repo.name, c.repo.err = currentRepository(c.dir)
+	})
+	return c.repo.name, c.repo.err
+}
+
+// currentRepository resolves the repository gh would act on in dir: GH_REPO,
+// else the origin remote of dir (the current directory when empty)
+func currentRepository(dir string) (string, error) {
+	if repo := strings.TrimSpace(os.Getenv("GH_REPO")); repo != "" {
+		parts := strings.Split(repo, "/")
+		if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+			return "", fmt.Errorf("GH_REPO %q is not in [HOST/]OWNER/REPO form", repo)
+		}
+		return parts[len(parts)-2] + "/" + parts[len(parts)-1], nil
+	}
+
+	args := []string{"remote", "get-url", "origin"}
+	ctx := cmdlog.LogCommandGlobal(git.Executable(), args, cmdlog.GetCaller())
+	cmd := git.Command(context.Background(), dir, args...)
+	start := time.Now()
+	output, err := cmd.Output()
+	duration := time.Since(start)
+	if err != nil {
+		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
+		return "", fmt.Errorf("failed to find the GitHub repository from the origin remote (set GH_REPO to OWNER/REPO): %w", err)
+	}
+	ctx.LogCompletion(true, 0, "", duration)
+	return ParseRepository(strings.TrimSpace(string(output)))
+}
+
+// scpRemotePattern matches scp-style remotes such as git@github.com:owner/repo.git
+var scpRemotePattern = regexp.MustCompile(`^[^/@:]+@[^/:]+:(.+)$`)
+
+// ParseRepository extracts "owner/name" from a git remote URL in https,
+// ssh:// or scp-style (git@host:owner/name.git) form
+func ParseRepository(remote string) (string, error) {
+	path := ""
+	if match := scpRemotePattern.FindStringSubmatch(remote); match != nil {
+		path = match[1]
+	} else if parsed, err := url.Parse(remote); err == nil && parsed.Host != "" {
+		path = parsed.Path
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(strings.TrimRight(path, "/"), ".git"), "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", fmt.Errorf("remote %q does not name a GitHub repository", remote)
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1], nil
+}
+
+// apiIssue is an issue (or pull request) as the REST API returns it
+type apiIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	State       string          `json:"state"`
+	HTMLURL     string          `json:"html_url"`
+	Body        string          `json:"body"`
+	Labels      []apiLabel      `json:"labels"`
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+}
+
+type apiLabel struct {
+	Name string `json:"name"`
+}
+
+// toIssue converts to the gh client's representation, whose states are upper case
+func (i apiIssue) toIssue() Issue {
+	return Issue{Number: i.Number, Title: i.Title, State: strings.ToUpper(i.State), URL: i.HTMLURL}
+}
+
+// GetIssue fetches an issue from the repository
+func (c *APIClient) GetIssue(issueNumber int) (*Issue, error) {
+	repo, err := c.Repository()
+	if err != nil {
+		return nil, err
+	}
+
+	var result apiIssue
+	if _, err := c.request(http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", repo, issueNumber), nil, &result); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("issue #%d %w", issueNumber, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch issue #%d with the GitHub API: %w", issueNumber, err)
+	}
+	if len(result.PullRequest) > 0 {
+		return nil, fmt.Errorf("issue #%d %w (#%d is a pull request)", issueNumber, ErrNotFound, issueNumber)
+	}
+
+	issue := result.toIssue()
+	issue.Body = result.Body
+	for _, label := range result.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return &issue, nil
+}
+
+// ListIssues fetches up to limit open issues from the repository, following
+// pages as needed. A search query is run through the search API restricted
+// to the repository's open issues, like gh issue list --search.
+func (c *APIClient) ListIssues(searchQuery string, limit int) ([]Issue, error) {
+	repo, err := c.Repository()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(min(limit, maxPerPage)))
+	next := ""
+	if searchQuery == "" {
+		query.Set("state", "open")
+		next = fmt.Sprintf("/repos/%s/issues?%s", repo, query.Encode())
+	} else {
+		query.Set("q", fmt.Sprintf("%s repo:%s is:issue is:open", searchQuery, repo))
+		next = "/search/issues?" + query.Encode()
+	}
+
+	issues := []Issue{}
+	for next != "" && len(issues) < limit {
+		var page []apiIssue
+		var header http.Header
+		if searchQuery == "" {
+			header, err = c.request(http.MethodGet, next, nil, &page)
+		} else {
+			var result struct {
+				Items []apiIssue `json:"items"`
+			}
+			header, err = c.request(http.MethodGet, next, nil, &result)
+			page = result.Items
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues with the GitHub API: %w", err)
+		}
+
+		for _, item := range page {
+			// The issues endpoint also lists pull requests
+			if len(item.PullRequest) > 0 {
+				continue
+			}
+			issues = append(issues, item.toIssue())
+			if len(issues) == limit {
+				break
+			}
+		}
+		next = nextPageURL(header)
+	}
+	return issues, nil
+}
+
+// linkNextPattern finds the next page in a Link response header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPageURL returns the next page's URL from a Link header, or ""
+func nextPageURL(header http.Header) string {
+	if match := linkNextPattern.FindStringSubmatch(header.Get("Link")); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// CloseIssue closes an issue in the repository
+func (c *APIClient) CloseIssue(issueNumber int) error {
+	if err := c.editIssue(issueNumber, map[string]string{"state": "closed"}); err != nil {
+		return fmt.Errorf("failed to close issue #%d with the GitHub API: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// ReopenIssue reopens a closed issue in the repository
+func (c *APIClient) ReopenIssue(issueNumber int) error {
+	if err := c.editIssue(issueNumber, map[string]string{"state": "open"}); err != nil {
+		return fmt.Errorf("failed to reopen issue #%d with the GitHub API: %w", issueNumber, err)
+	}
+	return nil
+}
+
+func (c *APIClient) editIssue(issueNumber int, fields map[string]string) error {
+	repo, err := c.Repository()
+	if err != nil {
+		return err
+	}
+	_, err = c.request(http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repo, issueNumber), fields, nil)
+	return err
+}
+
+// AddIssueLabel adds a label to an issue in the repository
+func (c *APIClient) AddIssueLabel(issueNumber int, label string) error {
+	repo, err := c.Repository()
+	if err != nil {
+		return err
+	}
+	body := map[string][]string{"labels": {label}}
+	if _, err := c.request(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/labels", repo, issueNumber), body, nil); err != nil {
+		return fmt.Errorf("failed to add label %q to issue #%d with the GitHub API: %w", label, issueNumber, err)
+	}
+	return nil
+}
+
+// RemoveIssueLabel removes a label from an issue in the repository
+func (c *APIClient) RemoveIssueLabel(issueNumber int, label string) error {
+	repo, err := c.Repository()
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/labels/%s", repo, issueNumber, url.PathEscape(label))
+	if _, err := c.request(http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to remove label %q from issue #%d with the GitHub API: %w", label, issueNumber, err)
+	}
+	return nil
+}
+
+// AddIssueComment posts a comment on an issue in the repository
+func (c *APIClient) AddIssueComment(issueNumber int, body string) error {
+	repo, err := c.Repository()
+	if err != nil {
+		return err
+	}
+	comment := map[string]string{"body": body}
+	if _, err := c.request(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, issueNumber), comment, nil); err != nil {
+		return fmt.Errorf("failed to comment on issue #%d with the GitHub API: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// CheckAccess verifies that the client has a token that can read the
+// repository, returning the repository's "owner/name"
+func (c *APIClient) CheckAccess() (string, error) {
+	repo, err := c.Repository()
+	if err != nil {
+		return "", err
+	}
+	if c.token == "" {
+		return repo, fmt.Errorf("github_client is %q but no token is set; set github_token, GH_TOKEN or GITHUB_TOKEN", ClientAPI)
+	}
+	if _, err := c.request(http.MethodGet, "/repos/"+repo, nil, nil); err != nil {
+		return repo, fmt.Errorf("GitHub API cannot read %s: %w", repo, err)
+	}
+	return repo, nil
+}
+
+// request sends a request to path (relative to the API root, or a full URL
+// from a Link header), JSON-encoding body and decoding the response into
+// out. Requests are logged like external commands and bounded by the
+// "github" command timeout.
+func (c *APIClient) request(method, path string, body, out interface{}) (http.Header, error) {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = c.baseURL + path
+	}
+	logPath := strings.TrimPrefix(target, c.baseURL)
+
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	args := []string{method, logPath}
+	logCtx := cmdlog.LogCommandGlobal(apiTimeoutTool, args, cmdlog.GetCaller())
+	ctx, cancel, timeout := cmdtimeout.ContextFrom(context.Background(), apiTimeoutTool)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, target, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		err = cmdtimeout.Check(ctx, apiTimeoutTool, args, timeout, err)
+		logCtx.LogCompletion(false, -1, err.Error(), duration)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		err = cmdtimeout.Check(ctx, apiTimeoutTool, args, timeout, err)
+		logCtx.LogCompletion(false, resp.StatusCode, err.Error(), duration)
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{
+			Method:      method,
+			Path:        logPath,
+			StatusCode:  resp.StatusCode,
+			RateLimited: resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"),
+		}
+		var message struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &message) == nil {
+			apiErr.Message = message.Message
+		}
+		logCtx.LogCompletion(false, resp.StatusCode, apiErr.Error(), duration)
+		return resp.Header, apiErr
+	}
+	logCtx.LogCompletion(true, 0, "", duration)
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.Header, fmt.Errorf("failed to parse GitHub API response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+var (
+	_ Client = (*APIClient)(nil)
+	_ Client = (*GitHubClient)(nil)
+)
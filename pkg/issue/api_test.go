@@ -0,0 +1,212 @@
+package issue
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPIClient returns a client for owner/repo talking to handler
+func newTestAPIClient(t *testing.T, handler http.HandlerFunc) *APIClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewAPIClient(ClientConfig{Backend: ClientAPI, Token: "secret", APIURL: server.URL + "/"}).
+		WithRepository("owner/repo").
+		WithHTTPClient(server.Client())
+}
+
+func TestParseRepository(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/owner/repo.git":          "owner/repo",
+		"https://github.com/owner/repo":              "owner/repo",
+		"https://token@github.com/owner/repo/":       "owner/repo",
+		"git@github.com:owner/repo.git":              "owner/repo",
+		"ssh://git@github.com:22/owner/repo.git":     "owner/repo",
+		"https://github.example.com/org/project.git": "org/project",
+	}
+	for remote, expected := range tests {
+		repo, err := ParseRepository(remote)
+		require.NoError(t, err, remote)
+		assert.Equal(t, expected, repo, remote)
+	}
+
+	for _, remote := range []string{"", "/local/path", "https://github.com/owner"} {
+		_, err := ParseRepository(remote)
+		assert.Error(t, err, remote)
+	}
+}
+
+func TestAPIClient_GetIssue(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/42":
+			fmt.Fprint(w, `{"number": 42, "title": "Fix login", "state": "open", "html_url": "https://github.com/owner/repo/issues/42", "body": "Steps to reproduce", "labels": [{"name": "in-progress"}]}`)
+		case "/repos/owner/repo/issues/43":
+			fmt.Fprint(w, `{"number": 43, "title": "A PR", "state": "open", "pull_request": {}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	issue, err := client.GetIssue(42)
+	require.NoError(t, err)
+	assert.Equal(t, &Issue{Number: 42, Title: "Fix login", State: "OPEN", URL: "https://github.com/owner/repo/issues/42", Body: "Steps to reproduce", Labels: []string{"in-progress"}}, issue)
+
+	_, err = client.GetIssue(43)
+	assert.ErrorContains(t, err, "is a pull request")
+
+	_, err = client.GetIssue(99)
+	assert.EqualError(t, err, "issue #99 not found")
+}
+
+func TestAPIClient_ListIssuesPaginates(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/owner/repo/issues", r.URL.Path)
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/issues?state=open&per_page=2&page=%d>; rel="next", <%s/last>; rel="last"`, server.URL, page+1, server.URL))
+		}
+		items := []map[string]interface{}{
+			{"number": page * 10, "title": fmt.Sprintf("Issue %d", page*10), "state": "open"},
+			{"number": page*10 + 1, "title": "A PR", "state": "open", "pull_request": map[string]string{}},
+		}
+		json.NewEncoder(w).Encode(items)
+	}))
+	t.Cleanup(server.Close)
+	client := NewAPIClient(ClientConfig{APIURL: server.URL}).WithRepository("owner/repo").WithHTTPClient(server.Client())
+
+	issues, err := client.ListIssues("", 2)
+	require.NoError(t, err)
+	require.Len(t, issues, 2, "pull requests are skipped and pages followed until the limit")
+	assert.Equal(t, 10, issues[0].Number)
+	assert.Equal(t, 20, issues[1].Number)
+
+	issues, err = client.ListIssues("", 10)
+	require.NoError(t, err)
+	assert.Len(t, issues, 3, "listing stops at the last page")
+}
+
+func TestAPIClient_ListIssuesSearch(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/search/issues", r.URL.Path)
+		assert.Equal(t, "login bug repo:owner/repo is:issue is:open", r.URL.Query().Get("q"))
+		assert.Equal(t, "5", r.URL.Query().Get("per_page"))
+		fmt.Fprint(w, `{"total_count": 1, "items": [{"number": 7, "title": "Login bug", "state": "open", "html_url": "u"}]}`)
+	})
+
+	issues, err := client.ListIssues("login bug", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []Issue{{Number: 7, Title: "Login bug", State: "OPEN", URL: "u"}}, issues)
+}
+
+func TestAPIClient_UpdatesIssues(t *testing.T) {
+	var requests []string
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		fmt.Fprint(w, `{}`)
+	})
+
+	require.NoError(t, client.CloseIssue(1))
+	require.NoError(t, client.ReopenIssue(1))
+	require.NoError(t, client.AddIssueLabel(1, "in progress"))
+	require.NoError(t, client.RemoveIssueLabel(1, "in progress"))
+	require.NoError(t, client.AddIssueComment(1, "Started work"))
+
+	assert.Equal(t, []string{
+		`PATCH /repos/owner/repo/issues/1 {"state":"closed"}`,
+		`PATCH /repos/owner/repo/issues/1 {"state":"open"}`,
+		`POST /repos/owner/repo/issues/1/labels {"labels":["in progress"]}`,
+		`DELETE /repos/owner/repo/issues/1/labels/in progress `,
+		`POST /repos/owner/repo/issues/1/comments {"body":"Started work"}`,
+	}, requests)
+}
+
+func TestAPIClient_Errors(t *testing.T) {
+	t.Run("bad_credentials", func(t *testing.T) {
+		client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Bad credentials"}`)
+		})
+		err := client.CloseIssue(1)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		assert.ErrorContains(t, err, "Bad credentials (check github_token or GITHUB_TOKEN)")
+
+		_, err = client.CheckAccess()
+		assert.ErrorContains(t, err, "cannot read owner/repo")
+	})
+
+	t.Run("rate_limited", func(t *testing.T) {
+		client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+		})
+		_, err := client.ListIssues("", 5)
+		assert.ErrorContains(t, err, "rate limit exceeded; a github_token raises the limit")
+	})
+
+	t.Run("no_token", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GITHUB_TOKEN", "")
+		client := NewAPIClient(ClientConfig{Backend: ClientAPI}).WithRepository("owner/repo")
+		_, err := client.CheckAccess()
+		assert.ErrorContains(t, err, "no token is set")
+	})
+}
+
+func TestNewClient_SelectsBackend(t *testing.T) {
+	t.Cleanup(func() { SetClientConfig(ClientConfig{}) })
+
+	SetClientConfig(ClientConfig{})
+	assert.IsType(t, &GitHubClient{}, NewClient())
+
+	SetClientConfig(ClientConfig{Backend: ClientAPI, Token: "secret"})
+	client, ok := NewClient().(*APIClient)
+	require.True(t, ok)
+	assert.Equal(t, DefaultAPIURL, client.baseURL)
+	assert.Equal(t, "secret", client.token)
+	assert.NoError(t, CheckClientInstalled(), "the API client needs no gh")
+}
+
+func TestAPIClient_WithDirectory(t *testing.T) {
+	t.Setenv("GH_REPO", "")
+	dir := t.TempDir()
+	for _, args := range [][]string{{"init", "-q"}, {"remote", "add", "origin", "git@github.com:other/project.git"}} {
+		require.NoError(t, exec.Command("git", append([]string{"-C", dir}, args...)...).Run())
+	}
+
+	repo, err := NewAPIClient(ClientConfig{}).WithDirectory(dir).Repository()
+	require.NoError(t, err)
+	assert.Equal(t, "other/project", repo, "the repository comes from dir's origin, not the current directory's")
+}
+
+func TestCurrentRepository_GHRepo(t *testing.T) {
+	t.Setenv("GH_REPO", "github.example.com/org/project")
+	repo, err := currentRepository("")
+	require.NoError(t, err)
+	assert.Equal(t, "org/project", repo)
+
+	t.Setenv("GH_REPO", "project")
+	_, err = currentRepository("")
+	assert.Error(t, err)
+}
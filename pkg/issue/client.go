@@ -0,0 +1,88 @@
+package issue
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// GitHub client backends selectable with github_client
+const (
+	ClientGH  = "gh"  // Shell out to the GitHub CLI
+	ClientAPI = "api" // Call the GitHub REST API directly with a token
+)
+
+// ErrNotFound is wrapped by the errors for issues that don't exist
+var ErrNotFound = errors.New("not found")
+
+// Client is the GitHub functionality sbs uses, implemented by GitHubClient
+// (gh) and APIClient (REST API)
+type Client interface {
+	GetIssue(issueNumber int) (*Issue, error)
+	ListIssues(searchQuery string, limit int) ([]Issue, error)
+	CloseIssue(issueNumber int) error
+	ReopenIssue(issueNumber int) error
+	AddIssueLabel(issueNumber int, label string) error
+	RemoveIssueLabel(issueNumber int, label string) error
+	AddIssueComment(issueNumber int, body string) error
+}
+
+// ClientConfig selects and configures the GitHub client
+type ClientConfig struct {
+	Backend string // ClientGH or ClientAPI; empty means ClientGH
+	Token   string // Token for the API client; GH_TOKEN and GITHUB_TOKEN are used when empty
+	APIURL  string // REST API root for the API client; empty means DefaultAPIURL
+}
+
+// UsesAPI reports whether the config selects the REST API client
+func (c ClientConfig) UsesAPI() bool {
+	return strings.TrimSpace(c.Backend) == ClientAPI
+}
+
+var (
+	clientConfig      ClientConfig
+	clientConfigMutex sync.RWMutex
+)
+
+// SetClientConfig sets the client NewClient builds
+func SetClientConfig(cfg ClientConfig) {
+	clientConfigMutex.Lock()
+	defer clientConfigMutex.Unlock()
+	clientConfig = cfg
+}
+
+// CurrentClientConfig returns the client config set with SetClientConfig
+func CurrentClientConfig() ClientConfig {
+	clientConfigMutex.RLock()
+	defer clientConfigMutex.RUnlock()
+	return clientConfig
+}
+
+// NewClient creates the GitHub client selected by github_client: the REST
+// API client for "api", the gh CLI client otherwise
+func NewClient() Client {
+	cfg := CurrentClientConfig()
+	if cfg.UsesAPI() {
+		return NewAPIClient(cfg)
+	}
+	return NewGitHubClient()
+}
+
+// NewClientIn creates the client NewClient would, acting on the repository
+// checked out at dir rather than the current directory's
+func NewClientIn(dir string) Client {
+	cfg := CurrentClientConfig()
+	if cfg.UsesAPI() {
+		return NewAPIClient(cfg).WithDirectory(dir)
+	}
+	return NewGitHubClientIn(dir)
+}
+
+// CheckClientInstalled verifies the tools the selected client needs: gh for
+// the CLI client, nothing for the API client
+func CheckClientInstalled() error {
+	if CurrentClientConfig().UsesAPI() {
+		return nil
+	}
+	return CheckGHInstalled()
+}
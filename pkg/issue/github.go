@@ -16,13 +16,17 @@ type commandExecutor interface {
 	executeCommand(name string, args ...string) ([]byte, error)
 }
 
-// realCommandExecutor implements commandExecutor using os/exec
-type realCommandExecutor struct{}
+// realCommandExecutor implements commandExecutor using os/exec, running
+// commands in dir (the current directory when empty)
+type realCommandExecutor struct {
+	dir string
+}
 
 func (r *realCommandExecutor) executeCommand(name string, args ...string) ([]byte, error) {
 	ctx := cmdlog.LogCommandGlobal(name, args, cmdlog.GetCaller())
 
 	cmd := exec.Command(name, args...)
+	cmd.Dir = r.dir
 	start := time.Now()
 	output, err := cmd.Output()
 	duration := time.Since(start)
@@ -41,10 +45,12 @@ type GitHubClient struct {
 }
 
 type Issue struct {
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	State  string `json:"state"`
-	URL    string `json:"url"`
+	Number int      `json:"number"`
+	Title  string   `json:"title"`
+	State  string   `json:"state"`
+	URL    string   `json:"url"`
+	Body   string   `json:"body,omitempty"`   // Only fetched by GetIssue
+	Labels []string `json:"labels,omitempty"` // Only fetched by GetIssue
 }
 
 type ghIssueJSON struct {
@@ -52,22 +58,32 @@ type ghIssueJSON struct {
 	Title  string `json:"title"`
 	State  string `json:"state"`
 	URL    string `json:"url"`
+	Body   string `json:"body"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
 }
 
 func NewGitHubClient() *GitHubClient {
+	return NewGitHubClientIn("")
+}
+
+// NewGitHubClientIn creates a gh client acting on the repository checked out
+// at dir, as gh does when run there
+func NewGitHubClientIn(dir string) *GitHubClient {
 	return &GitHubClient{
-		executor: &realCommandExecutor{},
+		executor: &realCommandExecutor{dir: dir},
 	}
 }
 
 func (g *GitHubClient) GetIssue(issueNumber int) (*Issue, error) {
 	// Use gh command to fetch issue data
-	output, err := g.executor.executeCommand("gh", "issue", "view", strconv.Itoa(issueNumber), "--json", "number,title,state,url")
+	output, err := g.executor.executeCommand("gh", "issue", "view", strconv.Itoa(issueNumber), "--json", "number,title,state,url,body,labels")
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			stderr := string(exitErr.Stderr)
 			if strings.Contains(stderr, "Could not resolve to an Issue") {
-				return nil, fmt.Errorf("issue #%d not found", issueNumber)
+				return nil, fmt.Errorf("issue #%d %w", issueNumber, ErrNotFound)
 			}
 		}
 		return nil, fmt.Errorf("failed to fetch issue #%d with gh command: %w", issueNumber, err)
@@ -78,11 +94,17 @@ func (g *GitHubClient) GetIssue(issueNumber int) (*Issue, error) {
 		return nil, fmt.Errorf("failed to parse gh command output: %w", err)
 	}
 
+	var labels []string
+	for _, label := range ghIssue.Labels {
+		labels = append(labels, label.Name)
+	}
 	return &Issue{
 		Number: ghIssue.Number,
 		Title:  ghIssue.Title,
 		State:  ghIssue.State,
 		URL:    ghIssue.URL,
+		Body:   ghIssue.Body,
+		Labels: labels,
 	}, nil
 }
 
@@ -134,6 +156,51 @@ func (g *GitHubClient) ListIssues(searchQuery string, limit int) ([]Issue, error
 	return issues, nil
 }
 
+// CloseIssue closes an issue in the current repository
+func (g *GitHubClient) CloseIssue(issueNumber int) error {
+	_, err := g.executor.executeCommand("gh", "issue", "close", strconv.Itoa(issueNumber))
+	if err != nil {
+		return fmt.Errorf("failed to close issue #%d with gh command: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// ReopenIssue reopens a closed issue in the current repository
+func (g *GitHubClient) ReopenIssue(issueNumber int) error {
+	_, err := g.executor.executeCommand("gh", "issue", "reopen", strconv.Itoa(issueNumber))
+	if err != nil {
+		return fmt.Errorf("failed to reopen issue #%d with gh command: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// AddIssueLabel adds a label to an issue in the current repository
+func (g *GitHubClient) AddIssueLabel(issueNumber int, label string) error {
+	_, err := g.executor.executeCommand("gh", "issue", "edit", strconv.Itoa(issueNumber), "--add-label", label)
+	if err != nil {
+		return fmt.Errorf("failed to add label %q to issue #%d with gh command: %w", label, issueNumber, err)
+	}
+	return nil
+}
+
+// RemoveIssueLabel removes a label from an issue in the current repository
+func (g *GitHubClient) RemoveIssueLabel(issueNumber int, label string) error {
+	_, err := g.executor.executeCommand("gh", "issue", "edit", strconv.Itoa(issueNumber), "--remove-label", label)
+	if err != nil {
+		return fmt.Errorf("failed to remove label %q from issue #%d with gh command: %w", label, issueNumber, err)
+	}
+	return nil
+}
+
+// AddIssueComment posts a comment on an issue in the current repository
+func (g *GitHubClient) AddIssueComment(issueNumber int, body string) error {
+	_, err := g.executor.executeCommand("gh", "issue", "comment", strconv.Itoa(issueNumber), "--body", body)
+	if err != nil {
+		return fmt.Errorf("failed to comment on issue #%d with gh command: %w", issueNumber, err)
+	}
+	return nil
+}
+
 // CheckGHInstalled verifies that the gh command is available
 func CheckGHInstalled() error {
 	ctx := cmdlog.LogCommandGlobal("gh", []string{"--version"}, cmdlog.GetCaller())
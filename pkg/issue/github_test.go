@@ -2,6 +2,8 @@ package issue
 
 import (
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -249,7 +251,9 @@ func TestGitHubClient_GetIssue_ExistingFunctionality(t *testing.T) {
 			"number": 123,
 			"title": "Fix authentication bug",
 			"state": "open",
-			"url": "https://github.com/owner/repo/issues/123"
+			"url": "https://github.com/owner/repo/issues/123",
+			"body": "Login fails after the redirect",
+			"labels": [{"id": "L1", "name": "bug"}, {"id": "L2", "name": "in-review"}]
 		}`
 
 		mockExec := &mockCommandExecutor{
@@ -264,9 +268,83 @@ func TestGitHubClient_GetIssue_ExistingFunctionality(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 123, issue.Number)
 		assert.Equal(t, "Fix authentication bug", issue.Title)
+		assert.Equal(t, "Login fails after the redirect", issue.Body)
+		assert.Equal(t, []string{"bug", "in-review"}, issue.Labels)
 
 		// Verify correct command was called
-		expectedCmd := []string{"gh", "issue", "view", "123", "--json", "number,title,state,url"}
+		expectedCmd := []string{"gh", "issue", "view", "123", "--json", "number,title,state,url,body,labels"}
 		assert.Equal(t, expectedCmd, mockExec.actualCommands[0])
 	})
 }
+
+func TestGitHubClient_StateChanges(t *testing.T) {
+	t.Run("close_issue", func(t *testing.T) {
+		mockExec := &mockCommandExecutor{}
+		client := &GitHubClient{executor: mockExec}
+
+		err := client.CloseIssue(123)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gh", "issue", "close", "123"}, mockExec.actualCommands[0])
+	})
+
+	t.Run("reopen_issue", func(t *testing.T) {
+		mockExec := &mockCommandExecutor{}
+		client := &GitHubClient{executor: mockExec}
+
+		err := client.ReopenIssue(42)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gh", "issue", "reopen", "42"}, mockExec.actualCommands[0])
+	})
+
+	t.Run("add_label", func(t *testing.T) {
+		mockExec := &mockCommandExecutor{}
+		client := &GitHubClient{executor: mockExec}
+
+		err := client.AddIssueLabel(7, "in-review")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gh", "issue", "edit", "7", "--add-label", "in-review"}, mockExec.actualCommands[0])
+	})
+
+	t.Run("remove_label", func(t *testing.T) {
+		mockExec := &mockCommandExecutor{}
+		client := &GitHubClient{executor: mockExec}
+
+		err := client.RemoveIssueLabel(7, "in-progress")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gh", "issue", "edit", "7", "--remove-label", "in-progress"}, mockExec.actualCommands[0])
+	})
+
+	t.Run("add_comment", func(t *testing.T) {
+		mockExec := &mockCommandExecutor{}
+		client := &GitHubClient{executor: mockExec}
+
+		err := client.AddIssueComment(12, "Nightly checks passed")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gh", "issue", "comment", "12", "--body", "Nightly checks passed"}, mockExec.actualCommands[0])
+	})
+
+	t.Run("close_issue_error", func(t *testing.T) {
+		mockExec := &mockCommandExecutor{mockError: assert.AnError}
+		client := &GitHubClient{executor: mockExec}
+
+		err := client.CloseIssue(123)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to close issue #123")
+	})
+}
+
+func TestNewGitHubClientIn_RunsInDirectory(t *testing.T) {
+	dir := t.TempDir()
+	client := NewGitHubClientIn(dir)
+	output, err := client.executor.executeCommand("pwd")
+	require.NoError(t, err)
+	resolved, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.Equal(t, resolved, strings.TrimSpace(string(output)))
+}
@@ -9,12 +9,12 @@ import (
 )
 
 type Tracker struct {
-	githubClient *GitHubClient
+	githubClient Client
 	config       *config.Config
 }
 
 func NewTracker(cfg *config.Config) *Tracker {
-	githubClient := NewGitHubClient()
+	githubClient := NewClient()
 
 	return &Tracker{
 		githubClient: githubClient,
@@ -0,0 +1,124 @@
+package logarchive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+)
+
+// Environment variables passed to loghook_archive_command
+const (
+	EnvWorkItem = "SBS_WORK_ITEM"
+	EnvSource   = "SBS_LOGHOOK_SOURCE"
+	EnvMode     = "SBS_LOGHOOK_MODE"
+	EnvTime     = "SBS_ARCHIVED_AT"
+)
+
+// TimeoutTool is the command_timeouts key bounding loghook_archive_command
+// for snapshots. Follow streams run as long as the stream does.
+const TimeoutTool = "loghook_archive"
+
+// CommandStore pipes each entry to a shell command run with sh -c. The
+// command gets the output on stdin and the entry in SBS_WORK_ITEM,
+// SBS_LOGHOOK_SOURCE, SBS_LOGHOOK_MODE and SBS_ARCHIVED_AT (RFC 3339).
+type CommandStore struct {
+	command string
+}
+
+// NewCommandStore creates a store running command for each entry
+func NewCommandStore(command string) *CommandStore {
+	return &CommandStore{command: command}
+}
+
+// Create starts the command; closing the writer ends its input and waits
+// for it to exit
+func (s *CommandStore) Create(entry Entry) (io.WriteCloser, error) {
+	ctx, cancel, timeout := context.Background(), context.CancelFunc(func() {}), time.Duration(0)
+	if entry.Mode != ModeFollow {
+		ctx, cancel, timeout = cmdtimeout.ContextFrom(context.Background(), TimeoutTool)
+	}
+
+	args := []string{"-c", s.command}
+	cmd := exec.CommandContext(ctx, "sh", args...)
+	cmd.Env = append(os.Environ(),
+		EnvWorkItem+"="+entry.WorkItem,
+		EnvSource+"="+entry.Source,
+		EnvMode+"="+entry.Mode,
+		EnvTime+"="+entry.Time.UTC().Format(time.RFC3339),
+	)
+	var output strings.Builder
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start loghook_archive_command: %w", err)
+	}
+	logCtx := cmdlog.LogCommandGlobal("sh", args, cmdlog.GetCaller())
+	if err := cmd.Start(); err != nil {
+		cancel()
+		logCtx.LogCompletion(false, -1, err.Error(), 0)
+		return nil, fmt.Errorf("failed to start loghook_archive_command: %w", err)
+	}
+	return &commandWriter{
+		WriteCloser: stdin,
+		cmd:         cmd,
+		args:        args,
+		output:      &output,
+		logCtx:      logCtx,
+		started:     time.Now(),
+		ctx:         ctx,
+		cancel:      cancel,
+		timeout:     timeout,
+	}, nil
+}
+
+// commandWriter feeds the command's stdin
+type commandWriter struct {
+	io.WriteCloser
+	cmd     *exec.Cmd
+	args    []string
+	output  *strings.Builder
+	logCtx  cmdlog.CommandContext
+	started time.Time
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+func (w *commandWriter) Close() error {
+	defer w.cancel()
+	w.WriteCloser.Close()
+	err := w.cmd.Wait()
+	duration := time.Since(w.started)
+	if err != nil {
+		err = cmdtimeout.Check(w.ctx, TimeoutTool, w.args, w.timeout, err)
+		message := strings.TrimSpace(w.output.String())
+		w.logCtx.LogCompletion(false, w.cmd.ProcessState.ExitCode(), message, duration)
+		if message != "" {
+			return fmt.Errorf("loghook_archive_command failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("loghook_archive_command failed: %w", err)
+	}
+	w.logCtx.LogCompletion(true, 0, "", duration)
+	return nil
+}
+
+// List can't read entries back from a command
+func (s *CommandStore) List(workItem string) ([]Entry, error) {
+	return nil, ErrNotBrowsable
+}
+
+// Open can't read entries back from a command
+func (s *CommandStore) Open(entry Entry) (io.ReadCloser, error) {
+	return nil, ErrNotBrowsable
+}
@@ -0,0 +1,147 @@
+package logarchive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"sbs/pkg/paths"
+)
+
+// timeLayout names entry files so they sort chronologically
+const timeLayout = "20060102T150405.000000000Z"
+
+// unsafeName matches characters kept out of directory and file names
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// LocalStore keeps entries as files in one directory per work item:
+// <dir>/<work item>/<time>_<mode>_<source>.log. After each entry is written
+// the oldest are removed until at most keep remain and they total at most
+// maxBytes; the newest entry is always kept.
+type LocalStore struct {
+	dir      string
+	keep     int
+	maxBytes int64
+}
+
+// NewLocalStore creates a store under dir
+func NewLocalStore(dir string, keep int, maxBytes int64) *LocalStore {
+	return &LocalStore{dir: dir, keep: keep, maxBytes: maxBytes}
+}
+
+func (s *LocalStore) sessionDir(workItem string) string {
+	return filepath.Join(s.dir, unsafeName.ReplaceAllString(workItem, "_"))
+}
+
+// Create starts an entry file
+func (s *LocalStore) Create(entry Entry) (io.WriteCloser, error) {
+	dir := s.sessionDir(entry.WorkItem)
+	if err := os.MkdirAll(dir, paths.PrivateDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create loghook archive directory: %w", err)
+	}
+	name := fmt.Sprintf("%s_%s_%s.log", entry.Time.UTC().Format(timeLayout), entry.Mode, unsafeName.ReplaceAllString(entry.Source, "_"))
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, paths.PrivateFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create loghook archive entry: %w", err)
+	}
+	return &localWriter{File: file, store: s, workItem: entry.WorkItem}, nil
+}
+
+// localWriter rotates the session's archive once its entry is complete
+type localWriter struct {
+	*os.File
+	store    *LocalStore
+	workItem string
+}
+
+func (w *localWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		return fmt.Errorf("failed to write loghook archive entry: %w", err)
+	}
+	return w.store.rotate(w.workItem)
+}
+
+// List returns the work item's entries, newest first
+func (s *LocalStore) List(workItem string) ([]Entry, error) {
+	dir := s.sessionDir(workItem)
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loghook archive: %w", err)
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		entry, ok := parseEntryName(file.Name())
+		if !ok || !file.Type().IsRegular() {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entry.WorkItem = workItem
+		entry.Size = info.Size()
+		entry.path = filepath.Join(dir, file.Name())
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// parseEntryName reads the time, mode and source back from an entry file name
+func parseEntryName(name string) (Entry, bool) {
+	base, found := strings.CutSuffix(name, ".log")
+	if !found {
+		return Entry{}, false
+	}
+	parts := strings.SplitN(base, "_", 3)
+	if len(parts) != 3 {
+		return Entry{}, false
+	}
+	at, err := time.Parse(timeLayout, parts[0])
+	if err != nil {
+		return Entry{}, false
+	}
+	return Entry{Time: at, Mode: parts[1], Source: parts[2]}, true
+}
+
+// Open reads a listed entry
+func (s *LocalStore) Open(entry Entry) (io.ReadCloser, error) {
+	if entry.path == "" {
+		return nil, fmt.Errorf("loghook archive entry from %s was not listed", entry.Time.Format(time.RFC3339))
+	}
+	file, err := os.Open(entry.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loghook archive entry: %w", err)
+	}
+	return file, nil
+}
+
+// rotate removes the oldest entries beyond the count and size limits
+func (s *LocalStore) rotate(workItem string) error {
+	entries, err := s.List(workItem)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for i, entry := range entries {
+		total += entry.Size
+		if i == 0 || (i < s.keep && total <= s.maxBytes) {
+			continue
+		}
+		if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate loghook archive: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package logarchive keeps loghook output that would otherwise be lost
+// between refreshes. Each snapshot that differs from the last one archived,
+// and each follow-mode stream, becomes an entry in a per-session archive, so
+// 'sbs log <id> --history' can show what a session printed before it failed.
+//
+// Where entries go is pluggable through Store. The "local" store keeps them
+// as files under loghook-archive/ in the state directory, rotated per session
+// by count and size. The "command" store pipes each entry to
+// loghook_archive_command, e.g. to upload it elsewhere; it can't be browsed.
+package logarchive
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+// Modes an entry was captured in, matching the loghook modes
+const (
+	ModeSnapshot = "snapshot"
+	ModeFollow   = "follow"
+)
+
+// Defaults for loghook_archive_keep and loghook_archive_max_bytes
+const (
+	DefaultKeep     = 100
+	DefaultMaxBytes = 50 * 1024 * 1024
+)
+
+// DirName is the local store's directory in the state directory
+const DirName = "loghook-archive"
+
+// ErrNotBrowsable is returned by stores that only write entries
+var ErrNotBrowsable = errors.New("the loghook archive store can't be browsed")
+
+// Entry is one archived loghook output
+type Entry struct {
+	WorkItem string
+	Source   string // Loghook source name, "default" for .sbs/loghook
+	Mode     string // ModeSnapshot or ModeFollow
+	Time     time.Time
+	Size     int64
+	path     string // Where the local store keeps it
+}
+
+// Store keeps archived entries
+type Store interface {
+	// Create starts an entry; it is complete once the writer is closed
+	Create(entry Entry) (io.WriteCloser, error)
+	// List returns a work item's entries, newest first
+	List(workItem string) ([]Entry, error)
+	// Open reads a listed entry
+	Open(entry Entry) (io.ReadCloser, error)
+}
+
+// FromConfig returns the store loghook_archive selects, or nil when
+// archiving is off
+func FromConfig(cfg *config.Config) (Store, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	switch cfg.LoghookArchive {
+	case "":
+		return nil, nil
+	case "local":
+		dir, err := paths.StatePath(DirName)
+		if err != nil {
+			return nil, err
+		}
+		keep, maxBytes := cfg.LoghookArchiveKeep, cfg.LoghookArchiveMaxBytes
+		if keep <= 0 {
+			keep = DefaultKeep
+		}
+		if maxBytes <= 0 {
+			maxBytes = DefaultMaxBytes
+		}
+		return NewLocalStore(dir, keep, maxBytes), nil
+	case "command":
+		if strings.TrimSpace(cfg.LoghookArchiveCommand) == "" {
+			return nil, fmt.Errorf("loghook_archive_command is required with loghook_archive \"command\"")
+		}
+		return NewCommandStore(cfg.LoghookArchiveCommand), nil
+	default:
+		return nil, fmt.Errorf("unknown loghook_archive store %q", cfg.LoghookArchive)
+	}
+}
+
+// Archiver writes loghook output to a store, skipping snapshots identical to
+// the last one archived for the same session and source. A nil Archiver
+// archives nothing. It is safe for concurrent use.
+type Archiver struct {
+	store Store
+	mu    sync.Mutex
+	last  map[string][sha256.Size]byte
+}
+
+// NewArchiver creates an archiver writing to store, or returns nil when
+// store is nil
+func NewArchiver(store Store) *Archiver {
+	if store == nil {
+		return nil
+	}
+	return &Archiver{store: store, last: make(map[string][sha256.Size]byte)}
+}
+
+// Store returns the store entries are written to
+func (a *Archiver) Store() Store {
+	if a == nil {
+		return nil
+	}
+	return a.store
+}
+
+// Snapshot archives a snapshot's output unless it is empty or unchanged
+func (a *Archiver) Snapshot(workItem, source, output string, now time.Time) error {
+	if a == nil || output == "" {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := workItem + "\x00" + source
+	sum := sha256.Sum256([]byte(output))
+	previous, known := a.last[key]
+	if !known {
+		previous, known = a.latestSum(workItem, source)
+	}
+	if known && previous == sum {
+		a.last[key] = sum
+		return nil
+	}
+
+	w, err := a.store.Create(Entry{WorkItem: workItem, Source: source, Mode: ModeSnapshot, Time: now})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		// A store that stopped reading explains why when closed
+		if closeErr := w.Close(); closeErr != nil {
+			return closeErr
+		}
+		return fmt.Errorf("failed to archive loghook output: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	a.last[key] = sum
+	return nil
+}
+
+// latestSum hashes the newest stored snapshot of a source, so a new process
+// doesn't archive the same output again
+func (a *Archiver) latestSum(workItem, source string) ([sha256.Size]byte, bool) {
+	entries, err := a.store.List(workItem)
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	for _, entry := range entries {
+		if entry.Source != source || entry.Mode != ModeSnapshot {
+			continue
+		}
+		r, err := a.store.Open(entry)
+		if err != nil {
+			return [sha256.Size]byte{}, false
+		}
+		defer r.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, r); err != nil {
+			return [sha256.Size]byte{}, false
+		}
+		var sum [sha256.Size]byte
+		copy(sum[:], hash.Sum(nil))
+		return sum, true
+	}
+	return [sha256.Size]byte{}, false
+}
+
+// Follow starts archiving a follow-mode stream; the entry is complete once
+// the writer is closed. Writes never fail, so the stream can be teed to the
+// archive without a broken store stopping it; Close reports the first error.
+func (a *Archiver) Follow(workItem, source string, now time.Time) (io.WriteCloser, error) {
+	if a == nil {
+		return nil, fmt.Errorf("loghook archiving is off")
+	}
+	w, err := a.store.Create(Entry{WorkItem: workItem, Source: source, Mode: ModeFollow, Time: now})
+	if err != nil {
+		return nil, err
+	}
+	return &followWriter{w: w}, nil
+}
+
+// followWriter keeps accepting a stream after the store failed
+type followWriter struct {
+	w   io.WriteCloser
+	err error
+}
+
+func (f *followWriter) Write(p []byte) (int, error) {
+	if f.err == nil {
+		if _, err := f.w.Write(p); err != nil {
+			f.err = fmt.Errorf("failed to archive loghook output: %w", err)
+		}
+	}
+	return len(p), nil
+}
+
+func (f *followWriter) Close() error {
+	return errors.Join(f.err, f.w.Close())
+}
@@ -0,0 +1,161 @@
+package logarchive
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/config"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func readEntry(t *testing.T, store Store, entry Entry) string {
+	t.Helper()
+	r, err := store.Open(entry)
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestArchiver_SkipsUnchangedSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	archiver := NewArchiver(NewLocalStore(dir, DefaultKeep, DefaultMaxBytes))
+
+	require.NoError(t, archiver.Snapshot("github:1", "default", "building\n", now))
+	require.NoError(t, archiver.Snapshot("github:1", "default", "building\n", now.Add(time.Second)))
+	require.NoError(t, archiver.Snapshot("github:1", "tests", "building\n", now.Add(2*time.Second)))
+	require.NoError(t, archiver.Snapshot("github:1", "default", "", now.Add(3*time.Second)))
+	require.NoError(t, archiver.Snapshot("github:1", "default", "FAIL\n", now.Add(4*time.Second)))
+
+	// A new process compares against the newest stored snapshot
+	restarted := NewArchiver(NewLocalStore(dir, DefaultKeep, DefaultMaxBytes))
+	require.NoError(t, restarted.Snapshot("github:1", "default", "FAIL\n", now.Add(5*time.Second)))
+
+	entries, err := archiver.Store().List("github:1")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "default", entries[0].Source, "newest first")
+	assert.Equal(t, ModeSnapshot, entries[0].Mode)
+	assert.True(t, entries[0].Time.Equal(now.Add(4*time.Second)))
+	assert.Equal(t, int64(5), entries[0].Size)
+	assert.Equal(t, "FAIL\n", readEntry(t, archiver.Store(), entries[0]))
+	assert.Equal(t, "tests", entries[1].Source)
+
+	info, err := os.Stat(filepath.Join(dir, "github_1"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+}
+
+func TestArchiver_Follow(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), DefaultKeep, DefaultMaxBytes)
+	w, err := NewArchiver(store).Follow("github:1", "default", now)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "line 1\n")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "line 2\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	entries, err := store.List("github:1")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ModeFollow, entries[0].Mode)
+	assert.Equal(t, "line 1\nline 2\n", readEntry(t, store, entries[0]))
+}
+
+func TestArchiver_NilArchivesNothing(t *testing.T) {
+	var archiver *Archiver
+	assert.Nil(t, NewArchiver(nil))
+	assert.NoError(t, archiver.Snapshot("github:1", "default", "output", now))
+	assert.Nil(t, archiver.Store())
+}
+
+func TestLocalStore_Rotates(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), 3, 1<<20)
+	archiver := NewArchiver(store)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, archiver.Snapshot("github:1", "default", strings.Repeat("x", i+1), now.Add(time.Duration(i)*time.Second)))
+	}
+	entries, err := store.List("github:1")
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "rotated to loghook_archive_keep")
+	assert.Equal(t, int64(5), entries[0].Size)
+	assert.Equal(t, int64(3), entries[2].Size)
+
+	// The size limit drops older entries, but never the newest
+	store = NewLocalStore(t.TempDir(), 10, 8)
+	archiver = NewArchiver(store)
+	require.NoError(t, archiver.Snapshot("github:1", "default", "12345", now))
+	require.NoError(t, archiver.Snapshot("github:1", "default", "abcdefghijk", now.Add(time.Second)))
+	entries, err = store.List("github:1")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "abcdefghijk", readEntry(t, store, entries[0]))
+}
+
+func TestCommandStore(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "archived")
+	store := NewCommandStore(`{ echo "$SBS_WORK_ITEM $SBS_LOGHOOK_SOURCE $SBS_LOGHOOK_MODE $SBS_ARCHIVED_AT"; cat; } > ` + out)
+	require.NoError(t, NewArchiver(store).Snapshot("github:1", "tests", "FAIL\n", now))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "github:1 tests snapshot 2026-03-01T12:00:00Z\nFAIL\n", string(data))
+
+	_, err = store.List("github:1")
+	assert.ErrorIs(t, err, ErrNotBrowsable)
+
+	err = NewArchiver(NewCommandStore("echo nope >&2; exit 3")).Snapshot("github:1", "default", "output", now)
+	assert.ErrorContains(t, err, "loghook_archive_command failed: exit status 3: nope")
+}
+
+func TestCommandStore_EarlyExitIsReported(t *testing.T) {
+	// Output larger than a pipe buffer makes the write fail once the
+	// command has exited without reading it
+	output := strings.Repeat("line of loghook output\n", 10000)
+	err := NewArchiver(NewCommandStore("echo rejected >&2; exit 3")).Snapshot("github:1", "default", output, now)
+
+	assert.EqualError(t, err, "loghook_archive_command failed: exit status 3: rejected")
+}
+
+func TestCommandStore_Timeout(t *testing.T) {
+	original := cmdtimeout.GetGlobalConfig()
+	defer cmdtimeout.SetGlobalConfig(original)
+	cmdtimeout.SetGlobalConfig(cmdtimeout.Config{PerTool: map[string]time.Duration{TimeoutTool: 100 * time.Millisecond}})
+
+	store := NewCommandStore("cat >/dev/null; sleep 5")
+	err := NewArchiver(store).Snapshot("github:1", "default", "output", now)
+	assert.True(t, cmdtimeout.IsTimeout(err), "snapshot archiving is bounded: %v", err)
+
+	// Follow streams aren't bounded
+	w, err := NewCommandStore("cat >/dev/null").Create(Entry{WorkItem: "github:1", Source: "default", Mode: ModeFollow, Time: now})
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+	_, err = io.WriteString(w, "still streaming")
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := FromConfig(&config.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, store)
+
+	store, err = FromConfig(&config.Config{LoghookArchive: "local"})
+	require.NoError(t, err)
+	assert.IsType(t, &LocalStore{}, store)
+
+	_, err = FromConfig(&config.Config{LoghookArchive: "command"})
+	assert.ErrorContains(t, err, "loghook_archive_command is required")
+}
@@ -0,0 +1,193 @@
+// Package loghook defines the contract between sbs and repository loghook scripts.
+//
+// A loghook is an executable at .sbs/loghook in a session's worktree. sbs runs it
+// from the worktree directory as:
+//
+//	.sbs/loghook <mode> [loghook_args...]
+//
+// where mode is "snapshot" or "follow" and loghook_args come from the global or
+// repository configuration. The following variables are added to the environment:
+//
+//	SBS_LOGHOOK_MODE   snapshot or follow (same as the first argument)
+//	SBS_WORK_ITEM      namespaced work item ID, e.g. "github:123"
+//	SBS_BRANCH         session branch name
+//	SBS_TMUX_SESSION   tmux session name
+//	SBS_WORKTREE       worktree path
+//	SBS_SANDBOX        sandbox name
+//
+// In snapshot mode the script should print recent output and exit; sbs enforces a
+// timeout and an output size limit. In follow mode the script may stream output
+// until it is interrupted.
+package loghook
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"sbs/pkg/config"
+)
+
+// Mode selects how the loghook script should produce output
+type Mode string
+
+const (
+	ModeSnapshot Mode = "snapshot" // Print current output and exit
+	ModeFollow   Mode = "follow"   // Stream output until interrupted
+)
+
+// Environment variables passed to loghook scripts
+const (
+	EnvMode        = "SBS_LOGHOOK_MODE"
+	EnvWorkItem    = "SBS_WORK_ITEM"
+	EnvBranch      = "SBS_BRANCH"
+	EnvTmuxSession = "SBS_TMUX_SESSION"
+	EnvWorktree    = "SBS_WORKTREE"
+	EnvSandbox     = "SBS_SANDBOX"
+)
+
+// MaxArgs is the maximum number of configured arguments passed to a loghook
+const MaxArgs = 32
+
+// Invocation describes a single loghook run
+type Invocation struct {
+	ScriptPath string
+	Session    config.SessionMetadata
+	Mode       Mode
+	Args       []string
+}
+
+// ScriptPath returns the loghook location for a worktree
+func ScriptPath(worktreePath string) string {
+	return filepath.Join(worktreePath, ".sbs", "loghook")
+}
+
+// ParseMode converts a string into a Mode
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case ModeSnapshot, ModeFollow:
+		return Mode(mode), nil
+	default:
+		return "", fmt.Errorf("invalid loghook mode %q (expected %s or %s)", mode, ModeSnapshot, ModeFollow)
+	}
+}
+
+// ValidateArgs checks configured loghook arguments
+func ValidateArgs(args []string) error {
+	if len(args) > MaxArgs {
+		return fmt.Errorf("too many loghook arguments: %d (maximum %d)", len(args), MaxArgs)
+	}
+	for i, arg := range args {
+		if strings.ContainsAny(arg, "\x00\n\r") {
+			return fmt.Errorf("loghook argument %d contains a control character", i)
+		}
+	}
+	return nil
+}
+
+// NewInvocation validates the mode and arguments for a loghook run
+func NewInvocation(session config.SessionMetadata, scriptPath string, mode Mode, args []string) (*Invocation, error) {
+	if _, err := ParseMode(string(mode)); err != nil {
+		return nil, err
+	}
+	if err := ValidateArgs(args); err != nil {
+		return nil, err
+	}
+
+	return &Invocation{
+		ScriptPath: scriptPath,
+		Session:    session,
+		Mode:       mode,
+		Args:       append([]string(nil), args...),
+	}, nil
+}
+
+// CommandArgs returns the arguments passed to the script (excluding the script itself)
+func (inv *Invocation) CommandArgs() []string {
+	return append([]string{string(inv.Mode)}, inv.Args...)
+}
+
+// Env returns the contract environment variables as KEY=value pairs
+func (inv *Invocation) Env() []string {
+	workItem := inv.Session.NamespacedID
+	if workItem == "" && inv.Session.IssueNumber > 0 {
+		workItem = fmt.Sprintf("github:%d", inv.Session.IssueNumber)
+	}
+
+	return []string{
+		EnvMode + "=" + string(inv.Mode),
+		EnvWorkItem + "=" + workItem,
+		EnvBranch + "=" + inv.Session.Branch,
+		EnvTmuxSession + "=" + inv.Session.TmuxSession,
+		EnvWorktree + "=" + inv.Session.WorktreePath,
+		EnvSandbox + "=" + inv.Session.SandboxName,
+	}
+}
+
+// Command builds the exec.Cmd for the invocation, run from the session worktree
+func (inv *Invocation) Command(ctx context.Context) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, inv.ScriptPath, inv.CommandArgs()...)
+	cmd.Dir = inv.Session.WorktreePath
+	cmd.Env = append(os.Environ(), inv.Env()...)
+	return cmd
+}
+
+// ResolveArgs returns the loghook arguments for a session, with the
+// worktree's .sbs/config.json taking precedence over the repository's, and
+// that over the global config. When global is nil the global configuration
+// is loaded from disk.
+func ResolveArgs(global *config.Config, repoRoot, worktreePath string) []string {
+	if global == nil {
+		loaded, err := config.LoadConfig()
+		if err != nil {
+			loaded = config.DefaultConfig()
+		}
+		global = loaded
+	}
+
+	merged := global
+	if repoRoot != "" {
+		if repoConfig, err := config.LoadRepositoryConfig(repoRoot); err == nil {
+			merged = config.MergeConfig(merged, repoConfig)
+		}
+		if worktreeConfig, err := config.LoadWorktreeConfig(repoRoot, worktreePath); err == nil && worktreeConfig != nil {
+			merged = config.MergeConfig(merged, worktreeConfig)
+		}
+	}
+
+	return append([]string(nil), merged.LoghookArgs...)
+}
+
+// ValidateScript performs security checks on a loghook script before it is run
+func ValidateScript(scriptPath string) error {
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat script at %s: %w", scriptPath, err)
+	}
+
+	// Check if it's a regular file (not a symlink, device, etc.)
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("script at %s is not a regular file", scriptPath)
+	}
+
+	// Check if script is executable
+	if info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("permission denied: script at %s is not executable", scriptPath)
+	}
+
+	// Check file ownership (should be owned by current user for security)
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		currentUID := os.Getuid()
+		if int(stat.Uid) != currentUID {
+			log.Printf("Warning: loghook script at %s is not owned by current user (uid=%d, script_uid=%d)",
+				scriptPath, currentUID, stat.Uid)
+		}
+	}
+
+	return nil
+}
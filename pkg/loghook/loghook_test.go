@@ -0,0 +1,179 @@
+package loghook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func testSession(worktreePath string) config.SessionMetadata {
+	return config.SessionMetadata{
+		NamespacedID: "github:42",
+		Branch:       "issue-github-42-fix-login",
+		TmuxSession:  "sbs-repo-github-42",
+		SandboxName:  "sbs-repo-github-42",
+		WorktreePath: worktreePath,
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Mode
+		wantErr bool
+	}{
+		{"snapshot", ModeSnapshot, false},
+		{"follow", ModeFollow, false},
+		{"tail", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			mode, err := ParseMode(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mode)
+		})
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	assert.NoError(t, ValidateArgs(nil))
+	assert.NoError(t, ValidateArgs([]string{"--lines", "200", "--service=api"}))
+
+	err := ValidateArgs([]string{"ok", "bad\narg"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "argument 1")
+
+	assert.Error(t, ValidateArgs([]string{"nul\x00"}))
+	assert.Error(t, ValidateArgs(make([]string, MaxArgs+1)))
+}
+
+func TestInvocation(t *testing.T) {
+	t.Run("args_and_env", func(t *testing.T) {
+		inv, err := NewInvocation(testSession("/tmp/wt"), "/tmp/wt/.sbs/loghook", ModeSnapshot, []string{"--lines", "50"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"snapshot", "--lines", "50"}, inv.CommandArgs())
+		assert.Equal(t, []string{
+			"SBS_LOGHOOK_MODE=snapshot",
+			"SBS_WORK_ITEM=github:42",
+			"SBS_BRANCH=issue-github-42-fix-login",
+			"SBS_TMUX_SESSION=sbs-repo-github-42",
+			"SBS_WORKTREE=/tmp/wt",
+			"SBS_SANDBOX=sbs-repo-github-42",
+		}, inv.Env())
+	})
+
+	t.Run("legacy_session_work_item", func(t *testing.T) {
+		session := config.SessionMetadata{IssueNumber: 7}
+		inv, err := NewInvocation(session, "/x/.sbs/loghook", ModeFollow, nil)
+		require.NoError(t, err)
+
+		assert.Contains(t, inv.Env(), "SBS_WORK_ITEM=github:7")
+	})
+
+	t.Run("args_are_copied", func(t *testing.T) {
+		args := []string{"a"}
+		inv, err := NewInvocation(testSession("/tmp/wt"), "/tmp/wt/.sbs/loghook", ModeSnapshot, args)
+		require.NoError(t, err)
+
+		args[0] = "changed"
+		assert.Equal(t, []string{"a"}, inv.Args)
+	})
+
+	t.Run("invalid_mode", func(t *testing.T) {
+		_, err := NewInvocation(testSession("/tmp/wt"), "/tmp/wt/.sbs/loghook", Mode("bogus"), nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid_args", func(t *testing.T) {
+		_, err := NewInvocation(testSession("/tmp/wt"), "/tmp/wt/.sbs/loghook", ModeSnapshot, []string{"a\nb"})
+		assert.Error(t, err)
+	})
+}
+
+func TestInvocation_Command(t *testing.T) {
+	worktree := t.TempDir()
+	scriptPath := ScriptPath(worktree)
+	require.NoError(t, os.MkdirAll(filepath.Dir(scriptPath), 0755))
+	script := "#!/bin/sh\necho \"$1 $2 $SBS_LOGHOOK_MODE $SBS_WORK_ITEM $SBS_BRANCH $SBS_TMUX_SESSION\"\npwd\n"
+	require.NoError(t, os.WriteFile(scriptPath, []byte(script), 0755))
+
+	inv, err := NewInvocation(testSession(worktree), scriptPath, ModeFollow, []string{"--since=5m"})
+	require.NoError(t, err)
+
+	output, err := inv.Command(context.Background()).Output()
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "follow --since=5m follow github:42 issue-github-42-fix-login sbs-repo-github-42", lines[0])
+
+	resolvedWorktree, err := filepath.EvalSymlinks(worktree)
+	require.NoError(t, err)
+	assert.Equal(t, resolvedWorktree, lines[1])
+}
+
+func TestValidateScript(t *testing.T) {
+	dir := t.TempDir()
+
+	executable := filepath.Join(dir, "loghook")
+	require.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\n"), 0755))
+	assert.NoError(t, ValidateScript(executable))
+
+	notExecutable := filepath.Join(dir, "plain")
+	require.NoError(t, os.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0644))
+	err := ValidateScript(notExecutable)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not executable")
+
+	assert.Error(t, ValidateScript(dir), "directories are not valid scripts")
+	assert.Error(t, ValidateScript(filepath.Join(dir, "missing")))
+}
+
+func TestResolveArgs(t *testing.T) {
+	global := config.DefaultConfig()
+	global.LoghookArgs = []string{"--global"}
+
+	t.Run("global_only", func(t *testing.T) {
+		assert.Equal(t, []string{"--global"}, ResolveArgs(global, "", ""))
+		assert.Equal(t, []string{"--global"}, ResolveArgs(global, t.TempDir(), ""))
+	})
+
+	t.Run("repository_override", func(t *testing.T) {
+		repoRoot := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, ".sbs"), 0755))
+		data, err := json.Marshal(map[string]interface{}{"loghook_args": []string{"--service", "api"}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(repoRoot, ".sbs", "config.json"), data, 0644))
+
+		assert.Equal(t, []string{"--service", "api"}, ResolveArgs(global, repoRoot, ""))
+		assert.Equal(t, []string{"--global"}, global.LoghookArgs, "global config should not be modified")
+	})
+
+	t.Run("worktree_override", func(t *testing.T) {
+		repoRoot, worktreePath := t.TempDir(), t.TempDir()
+		for dir, args := range map[string][]string{repoRoot: {"--repo"}, worktreePath: {"--worktree"}} {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, ".sbs"), 0755))
+			data, err := json.Marshal(map[string]interface{}{"loghook_args": args})
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ".sbs", "config.json"), data, 0644))
+		}
+
+		assert.Equal(t, []string{"--worktree"}, ResolveArgs(global, repoRoot, worktreePath))
+		assert.Equal(t, []string{"--repo"}, ResolveArgs(global, repoRoot, t.TempDir()), "worktrees without a config use the repository's")
+	})
+}
@@ -0,0 +1,113 @@
+package loghook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sbs/pkg/config"
+)
+
+// SettingsFile is the sidecar in .sbs/ where a worktree states how its loghooks
+// should be refreshed
+const SettingsFile = "loghook.json"
+
+// Default bounds applied to loghook settings when the config sets none
+const (
+	DefaultMinIntervalSecs = 2
+	DefaultMaxIntervalSecs = 120
+	DefaultMaxOutputBytes  = 1048576 // 1MB
+	MinOutputBytes         = 1024
+)
+
+// Settings are a worktree's preferred loghook refresh interval and output size,
+// read from .sbs/loghook.json. Zero values leave the configured defaults in place.
+type Settings struct {
+	RefreshIntervalSecs int            `json:"refresh_interval_seconds,omitempty"`
+	MaxOutputBytes      int            `json:"max_output_bytes,omitempty"`
+	SourceIntervals     map[string]int `json:"source_intervals_seconds,omitempty"` // Per named loghook in .sbs/loghooks/
+}
+
+// Limits bound the values a worktree may request in its settings
+type Limits struct {
+	MinInterval    time.Duration
+	MaxInterval    time.Duration
+	MaxOutputBytes int
+}
+
+// SettingsPath returns the settings sidecar location for a worktree
+func SettingsPath(worktreePath string) string {
+	return filepath.Join(worktreePath, ".sbs", SettingsFile)
+}
+
+// LoadSettings reads a worktree's .sbs/loghook.json; a missing file yields
+// empty settings
+func LoadSettings(worktreePath string) (Settings, error) {
+	var settings Settings
+	data, err := os.ReadFile(SettingsPath(worktreePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, fmt.Errorf("failed to read %s: %w", SettingsFile, err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse %s: %w", SettingsFile, err)
+	}
+	return settings, nil
+}
+
+// LimitsFromConfig returns the loghook limits set in the config, falling back
+// to the defaults for unset values
+func LimitsFromConfig(cfg *config.Config) Limits {
+	limits := Limits{
+		MinInterval:    DefaultMinIntervalSecs * time.Second,
+		MaxInterval:    DefaultMaxIntervalSecs * time.Second,
+		MaxOutputBytes: DefaultMaxOutputBytes,
+	}
+	if cfg == nil {
+		return limits
+	}
+	if cfg.LoghookMinIntervalSecs > 0 {
+		limits.MinInterval = time.Duration(cfg.LoghookMinIntervalSecs) * time.Second
+	}
+	if cfg.LoghookMaxIntervalSecs > 0 {
+		limits.MaxInterval = time.Duration(cfg.LoghookMaxIntervalSecs) * time.Second
+	}
+	if limits.MaxInterval < limits.MinInterval {
+		limits.MaxInterval = limits.MinInterval
+	}
+	if cfg.LoghookMaxOutputBytes > 0 {
+		limits.MaxOutputBytes = cfg.LoghookMaxOutputBytes
+	}
+	return limits
+}
+
+// ClampInterval keeps an interval within the limits
+func (l Limits) ClampInterval(interval time.Duration) time.Duration {
+	return min(max(interval, l.MinInterval), l.MaxInterval)
+}
+
+// Interval returns the refresh interval requested for a loghook source, bounded
+// by the limits. A named source's own interval takes precedence over the
+// worktree-wide one; zero means the settings request none.
+func (s Settings) Interval(source string, limits Limits) time.Duration {
+	secs := s.RefreshIntervalSecs
+	if named, exists := s.SourceIntervals[source]; exists && named > 0 {
+		secs = named
+	}
+	if secs <= 0 {
+		return 0
+	}
+	return limits.ClampInterval(time.Duration(secs) * time.Second)
+}
+
+// OutputLimit returns the maximum loghook output size in bytes, bounded by the limits
+func (s Settings) OutputLimit(limits Limits) int {
+	if s.MaxOutputBytes <= 0 {
+		return min(DefaultMaxOutputBytes, limits.MaxOutputBytes)
+	}
+	return min(max(s.MaxOutputBytes, MinOutputBytes), limits.MaxOutputBytes)
+}
@@ -0,0 +1,72 @@
+package loghook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func writeSettings(t *testing.T, worktree, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".sbs"), 0755))
+	require.NoError(t, os.WriteFile(SettingsPath(worktree), []byte(content), 0644))
+}
+
+func TestLoadSettings(t *testing.T) {
+	t.Run("missing_file", func(t *testing.T) {
+		settings, err := LoadSettings(t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, Settings{}, settings)
+	})
+
+	t.Run("valid_file", func(t *testing.T) {
+		worktree := t.TempDir()
+		writeSettings(t, worktree, `{"refresh_interval_seconds": 2, "max_output_bytes": 65536, "source_intervals_seconds": {"tests": 60}}`)
+
+		settings, err := LoadSettings(worktree)
+		require.NoError(t, err)
+		assert.Equal(t, 2, settings.RefreshIntervalSecs)
+		assert.Equal(t, 65536, settings.MaxOutputBytes)
+		assert.Equal(t, map[string]int{"tests": 60}, settings.SourceIntervals)
+	})
+
+	t.Run("invalid_json", func(t *testing.T) {
+		worktree := t.TempDir()
+		writeSettings(t, worktree, `{"refresh_interval_seconds": "fast"}`)
+
+		_, err := LoadSettings(worktree)
+		assert.ErrorContains(t, err, SettingsFile)
+	})
+}
+
+func TestSettings_Interval(t *testing.T) {
+	limits := LimitsFromConfig(nil)
+	settings := Settings{RefreshIntervalSecs: 10, SourceIntervals: map[string]int{"tests": 60, "build": 1, "agent": 600}}
+
+	assert.Equal(t, 10*time.Second, settings.Interval(DefaultSourceName, limits))
+	assert.Equal(t, 60*time.Second, settings.Interval("tests", limits), "a source's own interval wins")
+	assert.Equal(t, 2*time.Second, settings.Interval("build", limits), "raised to the minimum")
+	assert.Equal(t, 120*time.Second, settings.Interval("agent", limits), "lowered to the maximum")
+	assert.Equal(t, time.Duration(0), Settings{}.Interval("tests", limits))
+
+	custom := LimitsFromConfig(&config.Config{LoghookMinIntervalSecs: 5, LoghookMaxIntervalSecs: 30})
+	assert.Equal(t, 30*time.Second, settings.Interval("tests", custom))
+	assert.Equal(t, 5*time.Second, settings.Interval("build", custom))
+}
+
+func TestSettings_OutputLimit(t *testing.T) {
+	limits := LimitsFromConfig(nil)
+	assert.Equal(t, DefaultMaxOutputBytes, Settings{}.OutputLimit(limits))
+	assert.Equal(t, 65536, Settings{MaxOutputBytes: 65536}.OutputLimit(limits))
+	assert.Equal(t, MinOutputBytes, Settings{MaxOutputBytes: 10}.OutputLimit(limits))
+	assert.Equal(t, DefaultMaxOutputBytes, Settings{MaxOutputBytes: 50 << 20}.OutputLimit(limits))
+
+	small := LimitsFromConfig(&config.Config{LoghookMaxOutputBytes: 4096})
+	assert.Equal(t, 4096, Settings{}.OutputLimit(small))
+}
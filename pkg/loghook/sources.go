@@ -0,0 +1,70 @@
+package loghook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SourcesDir is the directory under .sbs holding named loghook scripts
+const SourcesDir = "loghooks"
+
+// DefaultSourceName names the single .sbs/loghook script when listed alongside named sources
+const DefaultSourceName = "default"
+
+// MaxSources is the maximum number of loghook sources shown (one per number key)
+const MaxSources = 9
+
+// Source is a named loghook script
+type Source struct {
+	Name string
+	Path string
+}
+
+// SourcesPath returns the named loghooks directory for a worktree
+func SourcesPath(worktreePath string) string {
+	return filepath.Join(worktreePath, ".sbs", SourcesDir)
+}
+
+// DiscoverSources lists the loghook scripts available in a worktree: .sbs/loghook
+// (named "default") followed by the files in .sbs/loghooks/ sorted by name.
+// Hidden files and directories are skipped and at most MaxSources are returned.
+func DiscoverSources(worktreePath string) ([]Source, error) {
+	var sources []Source
+
+	if info, err := os.Stat(ScriptPath(worktreePath)); err == nil && info.Mode().IsRegular() {
+		sources = append(sources, Source{Name: DefaultSourceName, Path: ScriptPath(worktreePath)})
+	}
+
+	entries, err := os.ReadDir(SourcesPath(worktreePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sources, nil
+		}
+		return sources, fmt.Errorf("failed to read loghooks directory: %w", err)
+	}
+
+	var named []Source
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || entry.IsDir() {
+			continue
+		}
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		named = append(named, Source{Name: name, Path: filepath.Join(SourcesPath(worktreePath), name)})
+	}
+
+	sort.Slice(named, func(i, j int) bool {
+		return named[i].Name < named[j].Name
+	})
+	sources = append(sources, named...)
+
+	if len(sources) > MaxSources {
+		sources = sources[:MaxSources]
+	}
+	return sources, nil
+}
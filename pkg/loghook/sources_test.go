@@ -0,0 +1,77 @@
+package loghook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho ok\n"), 0755))
+}
+
+func sourceNames(sources []Source) []string {
+	var names []string
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+	return names
+}
+
+func TestDiscoverSources(t *testing.T) {
+	t.Run("no_loghooks", func(t *testing.T) {
+		sources, err := DiscoverSources(t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, sources)
+	})
+
+	t.Run("default_only", func(t *testing.T) {
+		worktree := t.TempDir()
+		writeScript(t, ScriptPath(worktree))
+
+		sources, err := DiscoverSources(worktree)
+		require.NoError(t, err)
+		assert.Equal(t, []Source{{Name: DefaultSourceName, Path: ScriptPath(worktree)}}, sources)
+	})
+
+	t.Run("named_sources_sorted_after_default", func(t *testing.T) {
+		worktree := t.TempDir()
+		writeScript(t, ScriptPath(worktree))
+		writeScript(t, filepath.Join(SourcesPath(worktree), "tests"))
+		writeScript(t, filepath.Join(SourcesPath(worktree), "agent"))
+		writeScript(t, filepath.Join(SourcesPath(worktree), "build"))
+
+		sources, err := DiscoverSources(worktree)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default", "agent", "build", "tests"}, sourceNames(sources))
+		assert.Equal(t, filepath.Join(SourcesPath(worktree), "agent"), sources[1].Path)
+	})
+
+	t.Run("skips_hidden_files_and_directories", func(t *testing.T) {
+		worktree := t.TempDir()
+		writeScript(t, filepath.Join(SourcesPath(worktree), "build"))
+		writeScript(t, filepath.Join(SourcesPath(worktree), ".swp"))
+		require.NoError(t, os.MkdirAll(filepath.Join(SourcesPath(worktree), "lib"), 0755))
+
+		sources, err := DiscoverSources(worktree)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"build"}, sourceNames(sources))
+	})
+
+	t.Run("limits_to_max_sources", func(t *testing.T) {
+		worktree := t.TempDir()
+		for i := 0; i < MaxSources+3; i++ {
+			writeScript(t, filepath.Join(SourcesPath(worktree), fmt.Sprintf("hook%02d", i)))
+		}
+
+		sources, err := DiscoverSources(worktree)
+		require.NoError(t, err)
+		assert.Len(t, sources, MaxSources)
+	})
+}
@@ -0,0 +1,68 @@
+package messages
+
+// Message IDs, grouped by the command or view that shows them
+const (
+	NoSessions      ID = "sessions.none"
+	SessionNotFound ID = "sessions.not_found"
+
+	StartSelectionCancelled ID = "start.selection_cancelled"
+	StartSelected           ID = "start.selected"
+	StartFoundExisting      ID = "start.found_existing"
+	StartAttaching          ID = "start.attaching"
+	StartRecreating         ID = "start.recreating"
+	StartWorkingOn          ID = "start.working_on"
+	StartNothingToRestart   ID = "start.nothing_to_restart"
+	StartWrongRepository    ID = "start.wrong_repository"
+	StartConfirmAttachOther ID = "start.confirm_attach_other"
+	StartReady              ID = "start.ready"
+	StartNotReady           ID = "start.not_ready"
+
+	StopConfirmSandbox ID = "stop.confirm_sandbox"
+
+	CleanConfirm           ID = "clean.confirm"
+	CleanCancelled         ID = "clean.cancelled"
+	CleanConfirmBranches   ID = "clean.confirm_branches"
+	CleanBranchesCancelled ID = "clean.branches_cancelled"
+
+	TUICleanConfirm ID = "tui.clean_confirm"
+	TUICleanHint    ID = "tui.clean_hint"
+	TUICleanNothing ID = "tui.clean_nothing_selected"
+)
+
+// defaultTerms are the nouns messages use, keyed by the name templates
+// refer to them by
+var defaultTerms = map[string]string{
+	"work_item":  "work item",
+	"work_items": "work items",
+	"session":    "session",
+	"sessions":   "sessions",
+}
+
+// defaults are the default templates of every message
+var defaults = map[ID]string{
+	NoSessions:      `No active work {{term "sessions"}} found.`,
+	SessionNotFound: `no {{term "session"}} found for {{term "work_item"}} {{.ID}}`,
+
+	StartSelectionCancelled: `{{Term "work_item"}} selection cancelled.`,
+	StartSelected:           `Selected {{term "work_item"}} {{.ID}}: {{.Title}}`,
+	StartFoundExisting:      `Found existing {{term "session"}} for {{term "work_item"}} {{.ID}}`,
+	StartAttaching:          `Attaching to existing tmux session: {{.TmuxSession}}`,
+	StartRecreating:         `Tmux session not found, recreating...`,
+	StartWorkingOn:          `Working on {{term "work_item"}} {{.ID}}: {{.Title}}`,
+	StartNothingToRestart:   `{{term "work_item"}} {{.ID}} has no {{term "session"}} command to restart`,
+	StartWrongRepository:    `{{.ID}} already has a {{term "session"}} in repository {{.Repository}} ({{.RepositoryRoot}}), not {{.CurrentRepository}}.`,
+	StartConfirmAttachOther: `Attach to the existing {{term "session"}} in {{.Repository}} instead? (Y/n): `,
+	StartReady:              `Work environment ready! Use 'sbs attach {{.ID}}' to connect.`,
+	StartNotReady:           `Work environment started but not ready. Use 'sbs attach {{.ID}}' to investigate.`,
+
+	StopConfirmSandbox: `Delete sandbox {{.Sandbox}}? (y/N): `,
+
+	CleanConfirm:           `Proceed with cleanup? (y/N): `,
+	CleanCancelled:         `Cleanup cancelled.`,
+	CleanConfirmBranches:   `Proceed with branch cleanup? (y/N): `,
+	CleanBranchesCancelled: `Branch cleanup cancelled.`,
+
+	TUICleanConfirm: `Clean {{.Count}} stale {{plural .Count "session"}}?`,
+	TUICleanHint:    `(y/n) Press y to clean the selected {{term "sessions"}}, n to cancel`,
+	TUICleanNothing: `No {{term "sessions"}} selected; nothing cleaned`,
+}
@@ -0,0 +1,191 @@
+// Package messages renders sbs's user-facing text from a catalog of
+// text/template strings, so wording can be changed in one place and
+// customized without touching the commands that print it.
+//
+// Each message has an ID ("start.ready") and a default template in English.
+// The messages setting replaces templates by ID, and terms renames the
+// nouns sbs uses ("work item", "session"), e.g. {"work_item": "ticket"}.
+// Templates refer to terms with {{term "work_item"}}, {{Term "work_item"}}
+// (capitalized) and {{plural .Count "session"}} (singular or plural by
+// count), so a renamed term applies to every message.
+package messages
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ID names a message in the catalog
+type ID string
+
+// Args are the values a message template refers to, e.g. {{.ID}}
+type Args map[string]any
+
+// Catalog holds the templates of every message and the terms they use
+type Catalog struct {
+	templates map[ID]*template.Template
+	terms     map[string]string
+}
+
+// New builds a catalog from the defaults with templates replaced by
+// overrides (by message ID) and terms renamed by terms
+func New(overrides, terms map[string]string) (*Catalog, error) {
+	if err := Validate(overrides, terms); err != nil {
+		return nil, err
+	}
+	c := &Catalog{templates: make(map[ID]*template.Template, len(defaults)), terms: make(map[string]string, len(defaultTerms))}
+	for term, text := range defaultTerms {
+		c.terms[term] = text
+	}
+	for term, text := range terms {
+		c.terms[term] = text
+	}
+	for id, text := range defaults {
+		if override, ok := overrides[string(id)]; ok {
+			text = override
+		}
+		// Validate parsed every template already
+		c.templates[id] = template.Must(parse(id, text, c.terms))
+	}
+	return c, nil
+}
+
+// Default returns the catalog of default messages and terms
+func Default() *Catalog {
+	c, _ := New(nil, nil)
+	return c
+}
+
+// Validate checks the messages and terms settings: overrides must name known
+// messages and parse as templates, and terms must rename known terms
+func Validate(overrides, terms map[string]string) error {
+	var problems []error
+	for _, term := range sortedKeys(terms) {
+		if _, ok := defaultTerms[term]; !ok {
+			problems = append(problems, fmt.Errorf("terms has unknown term %q (expected one of %s)", term, strings.Join(sortedKeys(defaultTerms), ", ")))
+		} else if strings.TrimSpace(terms[term]) == "" {
+			problems = append(problems, fmt.Errorf("terms.%s cannot be empty", term))
+		}
+	}
+	for _, id := range sortedKeys(overrides) {
+		if _, ok := defaults[ID(id)]; !ok {
+			problems = append(problems, fmt.Errorf("messages has unknown message %q", id))
+			continue
+		}
+		if _, err := parse(ID(id), overrides[id], defaultTerms); err != nil {
+			problems = append(problems, fmt.Errorf("messages.%s is not a valid template: %w", id, err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// Render returns a message with args filled in. A template that fails to
+// execute (e.g. an override referring to a missing argument) falls back to
+// the default wording.
+func (c *Catalog) Render(id ID, args Args) string {
+	tmpl, ok := c.templates[id]
+	if !ok {
+		return string(id)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, args); err != nil {
+		fallback, parseErr := parse(id, defaults[id], c.terms)
+		if parseErr != nil {
+			return string(id)
+		}
+		out.Reset()
+		if fallback.Execute(&out, args) != nil {
+			return string(id)
+		}
+	}
+	return out.String()
+}
+
+// Term returns the configured wording of a term such as "work_item"
+func (c *Catalog) Term(term string) string {
+	if text, ok := c.terms[term]; ok {
+		return text
+	}
+	return term
+}
+
+// parse parses a message template with the term functions bound to terms
+func parse(id ID, text string, terms map[string]string) (*template.Template, error) {
+	lookup := func(term string) (string, error) {
+		text, ok := terms[term]
+		if !ok {
+			return "", fmt.Errorf("unknown term %q", term)
+		}
+		return text, nil
+	}
+	funcs := template.FuncMap{
+		"term": lookup,
+		"Term": func(term string) (string, error) {
+			text, err := lookup(term)
+			return capitalize(text), err
+		},
+		"plural": func(count int, term string) (string, error) {
+			if count == 1 {
+				return lookup(term)
+			}
+			return lookup(term + "s")
+		},
+	}
+	return template.New(string(id)).Option("missingkey=error").Funcs(funcs).Parse(text)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var (
+	current      = Default()
+	currentMutex sync.RWMutex
+)
+
+// SetCatalog sets the catalog Render uses; nil restores the defaults
+func SetCatalog(c *Catalog) {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	if c == nil {
+		c = Default()
+	}
+	current = c
+}
+
+// Current returns the catalog Render uses
+func Current() *Catalog {
+	currentMutex.RLock()
+	defer currentMutex.RUnlock()
+	return current
+}
+
+// Render renders a message from the current catalog
+func Render(id ID, args Args) string {
+	return Current().Render(id, args)
+}
+
+// Error returns a message from the current catalog as an error
+func Error(id ID, args Args) error {
+	return errors.New(Render(id, args))
+}
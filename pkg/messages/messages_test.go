@@ -0,0 +1,79 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMessages(t *testing.T) {
+	c := Default()
+
+	assert.Equal(t, "no session found for work item github:1", c.Render(SessionNotFound, Args{"ID": "github:1"}))
+	assert.Equal(t, "Work item selection cancelled.", c.Render(StartSelectionCancelled, nil))
+	assert.Equal(t, "Clean 1 stale session?", c.Render(TUICleanConfirm, Args{"Count": 1}))
+	assert.Equal(t, "Clean 3 stale sessions?", c.Render(TUICleanConfirm, Args{"Count": 3}))
+}
+
+func TestDefaultsRender(t *testing.T) {
+	args := Args{"ID": "test:1", "Title": "t", "TmuxSession": "s", "Repository": "r", "RepositoryRoot": "/r",
+		"CurrentRepository": "c", "Sandbox": "sb", "Count": 2}
+	c := Default()
+	for id := range defaults {
+		assert.NotEqual(t, string(id), c.Render(id, args), "%s should render", id)
+	}
+}
+
+func TestTermsAndOverrides(t *testing.T) {
+	c, err := New(
+		map[string]string{string(StartReady): "Ready: sbs attach {{.ID}}"},
+		map[string]string{"work_item": "ticket", "work_items": "tickets"},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "no session found for ticket PROJ-1", c.Render(SessionNotFound, Args{"ID": "PROJ-1"}))
+	assert.Equal(t, "Ticket selection cancelled.", c.Render(StartSelectionCancelled, nil))
+	assert.Equal(t, "Ready: sbs attach PROJ-1", c.Render(StartReady, Args{"ID": "PROJ-1"}))
+	assert.Equal(t, "ticket", c.Term("work_item"))
+}
+
+func TestRender_FallsBackToDefault(t *testing.T) {
+	c, err := New(map[string]string{string(StartReady): "Ready after {{.Missing}}"}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Work environment ready! Use 'sbs attach x' to connect.", c.Render(StartReady, Args{"ID": "x"}))
+	assert.Equal(t, "no.such.message", c.Render("no.such.message", nil))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(nil, nil))
+
+	err := Validate(map[string]string{"start.bogus": "x"}, nil)
+	assert.ErrorContains(t, err, `unknown message "start.bogus"`)
+
+	err = Validate(map[string]string{string(StartReady): "{{.ID"}, nil)
+	assert.ErrorContains(t, err, "messages.start.ready is not a valid template")
+
+	err = Validate(nil, map[string]string{"ticket": "x"})
+	assert.ErrorContains(t, err, `unknown term "ticket"`)
+
+	err = Validate(nil, map[string]string{"session": " "})
+	assert.ErrorContains(t, err, "terms.session cannot be empty")
+
+	_, err = New(map[string]string{"start.bogus": "x"}, nil)
+	assert.Error(t, err)
+}
+
+func TestSetCatalog(t *testing.T) {
+	c, err := New(nil, map[string]string{"session": "workspace", "sessions": "workspaces"})
+	require.NoError(t, err)
+	SetCatalog(c)
+	defer SetCatalog(nil)
+
+	assert.Equal(t, "No active work workspaces found.", Render(NoSessions, nil))
+	assert.EqualError(t, Error(SessionNotFound, Args{"ID": "1"}), "no workspace found for work item 1")
+
+	SetCatalog(nil)
+	assert.Equal(t, "No active work sessions found.", Render(NoSessions, nil))
+}
@@ -0,0 +1,112 @@
+// Package naming builds the names sbs gives tmux sessions and sandboxes.
+//
+// Names start with "sbs-", optionally followed by a scope (a username or a
+// custom prefix set with name_scope) so users sharing a host don't collide:
+// sbs-<scope>-<repo>-<source>-<id>.
+package naming
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Prefix starts every name sbs creates
+const Prefix = "sbs-"
+
+// ScopeUser is the name_scope value that scopes names by the current username
+const ScopeUser = "user"
+
+var (
+	scope      string
+	scopeMutex sync.RWMutex
+)
+
+// SetScope sets the scope folded into every name; "" disables scoping.
+// The scope must already be resolved with ResolveScope.
+func SetScope(s string) {
+	scopeMutex.Lock()
+	defer scopeMutex.Unlock()
+	scope = s
+}
+
+// Scope returns the scope folded into every name
+func Scope() string {
+	scopeMutex.RLock()
+	defer scopeMutex.RUnlock()
+	return scope
+}
+
+var (
+	invalidScopeChars = regexp.MustCompile(`[^a-z0-9-]+`)
+	customScope       = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+)
+
+// ValidateScopeSetting checks a name_scope value without resolving it
+func ValidateScopeSetting(setting string) error {
+	if setting == "" || setting == ScopeUser || customScope.MatchString(setting) {
+		return nil
+	}
+	return fmt.Errorf("name_scope must be %q or letters, digits, '-' and '_' (got %q)", ScopeUser, setting)
+}
+
+// ResolveScope turns a name_scope setting into the scope used in names:
+// "" means no scope, "user" the current username, anything else is used as
+// given. Scopes are lowercased and reduced to letters, digits and dashes.
+func ResolveScope(setting string) (string, error) {
+	if err := ValidateScopeSetting(setting); err != nil {
+		return "", err
+	}
+	if setting == "" {
+		return "", nil
+	}
+	if setting == ScopeUser {
+		username, err := currentUsername()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine username for name_scope: %w", err)
+		}
+		setting = username
+	}
+
+	resolved := strings.Trim(invalidScopeChars.ReplaceAllString(strings.ToLower(setting), "-"), "-")
+	if resolved == "" {
+		return "", fmt.Errorf("name_scope %q leaves nothing usable in names", setting)
+	}
+	return resolved, nil
+}
+
+func currentUsername() (string, error) {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username, nil
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name, nil
+	}
+	return "", fmt.Errorf("no username available")
+}
+
+// ScopedPrefix returns "sbs-" followed by the scope, if any
+func ScopedPrefix() string {
+	if s := Scope(); s != "" {
+		return Prefix + s + "-"
+	}
+	return Prefix
+}
+
+// TmuxSession returns the tmux session name for a work item
+func TmuxSession(repoName, source, id string) string {
+	return fmt.Sprintf("%s%s-%s-%s", ScopedPrefix(), repoName, source, id)
+}
+
+// Sandbox returns the sandbox name for a work item
+func Sandbox(repoName, source, id string) string {
+	return fmt.Sprintf("%s%s-%s-%s", ScopedPrefix(), repoName, source, id)
+}
+
+// PoolPrefix starts the names of prewarmed pool sandboxes
+func PoolPrefix() string {
+	return ScopedPrefix() + "pool-"
+}
@@ -0,0 +1,59 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withScope(t *testing.T, s string) {
+	t.Helper()
+	previous := Scope()
+	SetScope(s)
+	t.Cleanup(func() { SetScope(previous) })
+}
+
+func TestNames_Unscoped(t *testing.T) {
+	withScope(t, "")
+
+	assert.Equal(t, "sbs-myrepo-github-123", TmuxSession("myrepo", "github", "123"))
+	assert.Equal(t, "sbs-myrepo-github-123", Sandbox("myrepo", "github", "123"))
+	assert.Equal(t, "sbs-pool-", PoolPrefix())
+}
+
+func TestNames_Scoped(t *testing.T) {
+	withScope(t, "alice")
+
+	assert.Equal(t, "sbs-alice-myrepo-github-123", TmuxSession("myrepo", "github", "123"))
+	assert.Equal(t, "sbs-alice-myrepo-test-quick", Sandbox("myrepo", "test", "quick"))
+	assert.Equal(t, "sbs-alice-pool-", PoolPrefix())
+}
+
+func TestResolveScope(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		scope, err := ResolveScope("")
+		require.NoError(t, err)
+		assert.Equal(t, "", scope)
+	})
+
+	t.Run("custom prefix is normalized", func(t *testing.T) {
+		scope, err := ResolveScope("Team_A")
+		require.NoError(t, err)
+		assert.Equal(t, "team-a", scope)
+	})
+
+	t.Run("user", func(t *testing.T) {
+		scope, err := ResolveScope(ScopeUser)
+		require.NoError(t, err)
+		assert.NotEmpty(t, scope)
+		assert.Regexp(t, `^[a-z0-9-]+$`, scope)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, setting := range []string{"team a", "-lead", "a/b"} {
+			_, err := ResolveScope(setting)
+			assert.Error(t, err, setting)
+		}
+	})
+}
@@ -0,0 +1,321 @@
+// Package notify tells the notifiers in the global config about events sbs
+// users want to hear of while away: an agent left waiting for input, a
+// cleanup that removed sessions, a start that failed while provisioning.
+//
+// Webhook notifiers receive the event as JSON, with message holding the
+// rendered text:
+//
+//	{"type":"start-failed","time":"...","work_item":"github:123","step":"branch","error":"...","message":"..."}
+//
+// Slack notifiers post {"text": message} to an incoming webhook. Command
+// notifiers run with sh -c, the event as JSON on stdin and these variables
+// added to the environment:
+//
+//	SBS_EVENT       event type, e.g. "cleanup"
+//	SBS_WORK_ITEM   namespaced work item ID, when the event has one
+//	SBS_MESSAGE     the rendered message
+//
+// A notifier's template is a Go text/template over Event, e.g.
+// "{{.WorkItem}} needs you: {{.Prompt}}". Requests and commands run under the
+// "notify" command timeout.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/config"
+)
+
+// Event types, as listed in config.NotifierEvents
+const (
+	SessionWaiting = "session-waiting" // An agent has waited for input longer than notify_waiting_minutes
+	CleanupRemoved = "cleanup"         // sbs clean removed sessions
+	StartFailed    = "start-failed"    // sbs start failed while provisioning
+)
+
+// TimeoutTool is the command timeout name bounding each notification
+const TimeoutTool = "notify"
+
+// Environment variables passed to command notifiers
+const (
+	EnvEvent    = "SBS_EVENT"
+	EnvWorkItem = "SBS_WORK_ITEM"
+	EnvMessage  = "SBS_MESSAGE"
+)
+
+// Event is something notifiers are told about
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	WorkItem   string    `json:"work_item,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Repository string    `json:"repository,omitempty"`
+	Waiting    string    `json:"waiting,omitempty"`    // How long the agent has waited, e.g. "45m"
+	Prompt     string    `json:"prompt,omitempty"`     // What the agent is waiting for, when reported
+	Count      int       `json:"count,omitempty"`      // Sessions a cleanup removed
+	WorkItems  []string  `json:"work_items,omitempty"` // Work items a cleanup removed
+	Step       string    `json:"step,omitempty"`       // Provisioning step a start failed at
+	Error      string    `json:"error,omitempty"`
+	Message    string    `json:"message"` // Default text; notifiers with a template replace it
+}
+
+// WaitingEvent reports an agent that has waited for input since since
+func WaitingEvent(session config.SessionMetadata, since time.Time, prompt string, now time.Time) Event {
+	waited := now.Sub(since).Round(time.Minute)
+	message := fmt.Sprintf("%s has been waiting for input for %s", describe(session.NamespacedID, session.IssueTitle), formatWait(waited))
+	if prompt != "" {
+		message += ": " + prompt
+	}
+	return Event{
+		Type:       SessionWaiting,
+		Time:       now,
+		WorkItem:   session.NamespacedID,
+		Title:      session.IssueTitle,
+		Repository: session.RepositoryName,
+		Waiting:    formatWait(waited),
+		Prompt:     prompt,
+		Message:    message,
+	}
+}
+
+// CleanupEvent reports the sessions a cleanup removed
+func CleanupEvent(sessions []config.SessionMetadata, now time.Time) Event {
+	workItems := make([]string, len(sessions))
+	for i, session := range sessions {
+		workItems[i] = session.NamespacedID
+	}
+	return Event{
+		Type:      CleanupRemoved,
+		Time:      now,
+		Count:     len(sessions),
+		WorkItems: workItems,
+		Message:   fmt.Sprintf("sbs clean removed %d session(s): %s", len(sessions), strings.Join(workItems, ", ")),
+	}
+}
+
+// StartFailedEvent reports a start that failed at step with err
+func StartFailedEvent(session config.SessionMetadata, step string, err error, now time.Time) Event {
+	message := fmt.Sprintf("Starting %s failed", describe(session.NamespacedID, session.IssueTitle))
+	if step != "" {
+		message += " at " + step
+	}
+	return Event{
+		Type:       StartFailed,
+		Time:       now,
+		WorkItem:   session.NamespacedID,
+		Title:      session.IssueTitle,
+		Repository: session.RepositoryName,
+		Step:       step,
+		Error:      err.Error(),
+		Message:    message + ": " + err.Error(),
+	}
+}
+
+func describe(workItem, title string) string {
+	if title == "" {
+		return workItem
+	}
+	return workItem + " (" + title + ")"
+}
+
+// formatWait renders a wait as "45m", "2h" or "2h5m"
+func formatWait(d time.Duration) string {
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+// Dispatcher sends events to the configured notifiers. A nil Dispatcher, or
+// one without notifiers, sends nothing.
+type Dispatcher struct {
+	notifiers  []config.NotifierConfig
+	httpClient *http.Client
+}
+
+// NewDispatcher creates a dispatcher for notifiers, or returns nil when there
+// are none
+func NewDispatcher(notifiers []config.NotifierConfig) *Dispatcher {
+	if len(notifiers) == 0 {
+		return nil
+	}
+	return &Dispatcher{notifiers: notifiers, httpClient: http.DefaultClient}
+}
+
+// WithHTTPClient returns a copy of the dispatcher posting with client
+func (d *Dispatcher) WithHTTPClient(client *http.Client) *Dispatcher {
+	clone := *d
+	clone.httpClient = client
+	return &clone
+}
+
+// Send delivers event to every notifier subscribed to it. All are tried;
+// the failures are returned joined.
+func (d *Dispatcher) Send(event Event) error {
+	if d == nil {
+		return nil
+	}
+	var failures []error
+	for _, notifier := range d.notifiers {
+		if len(notifier.Events) > 0 && !contains(notifier.Events, event.Type) {
+			continue
+		}
+		if err := d.send(notifier, event); err != nil {
+			failures = append(failures, fmt.Errorf("%s notifier failed for %s: %w", notifier.Type, event.Type, err))
+		}
+	}
+	return errors.Join(failures...)
+}
+
+func (d *Dispatcher) send(notifier config.NotifierConfig, event Event) error {
+	message, err := render(notifier.Template, event)
+	if err != nil {
+		return err
+	}
+	event.Message = message
+
+	switch notifier.Type {
+	case "webhook":
+		return d.post(notifier.URL, event)
+	case "slack":
+		return d.post(notifier.URL, map[string]string{"text": message})
+	case "command":
+		return runCommand(notifier.Command, event)
+	default:
+		return fmt.Errorf("unknown notifier type %q", notifier.Type)
+	}
+}
+
+// render applies tmpl to event, or returns the event's message without one
+func render(tmpl string, event Event) (string, error) {
+	if tmpl == "" {
+		return event.Message, nil
+	}
+	parsed, err := template.New(event.Type).Option("missingkey=zero").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var out strings.Builder
+	if err := parsed.Execute(&out, event); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// post sends body as JSON to url. Only the host is logged, since webhook
+// URLs embed their secret.
+func (d *Dispatcher) post(url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	args := []string{"POST", redactURL(url)}
+	logCtx := cmdlog.LogCommandGlobal(TimeoutTool, args, cmdlog.GetCaller())
+	ctx, cancel, timeout := cmdtimeout.ContextFrom(context.Background(), TimeoutTool)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := d.httpClient.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		err = cmdtimeout.Check(ctx, TimeoutTool, args, timeout, err)
+		logCtx.LogCompletion(false, -1, err.Error(), duration)
+		return err
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logCtx.LogCompletion(false, resp.StatusCode, strings.TrimSpace(string(reply)), duration)
+		return fmt.Errorf("%s returned %s", redactURL(url), resp.Status)
+	}
+	logCtx.LogCompletion(true, resp.StatusCode, "", duration)
+	return nil
+}
+
+// redactURL keeps the scheme and host of url
+func redactURL(url string) string {
+	scheme, rest, found := strings.Cut(url, "://")
+	if !found {
+		return "(invalid URL)"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host + "/..."
+}
+
+// runCommand runs a command notifier with the event on stdin
+func runCommand(command string, event Event) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	args := []string{"-c", command}
+	logCtx := cmdlog.LogCommandGlobal("sh", args, cmdlog.GetCaller())
+	ctx, cancel, timeout := cmdtimeout.ContextFrom(context.Background(), TimeoutTool)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		EnvEvent+"="+event.Type,
+		EnvWorkItem+"="+event.WorkItem,
+		EnvMessage+"="+event.Message,
+	)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	duration := time.Since(start)
+	if err != nil {
+		err = cmdtimeout.Check(ctx, TimeoutTool, args, timeout, err)
+		logCtx.LogCompletion(false, -1, strings.TrimSpace(string(output)), duration)
+		if trimmed := strings.TrimSpace(string(output)); trimmed != "" {
+			return fmt.Errorf("%w: %s", err, lastLine(trimmed))
+		}
+		return err
+	}
+	logCtx.LogCompletion(true, 0, "", duration)
+	return nil
+}
+
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
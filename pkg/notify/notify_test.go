@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// postServer records the bodies posted to it
+func postServer(t *testing.T, status int) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestEvents(t *testing.T) {
+	session := config.SessionMetadata{NamespacedID: "github:12", IssueTitle: "Fix login", RepositoryName: "web"}
+
+	waiting := WaitingEvent(session, now.Add(-95*time.Minute), "Claude needs your permission to use Bash", now)
+	assert.Equal(t, SessionWaiting, waiting.Type)
+	assert.Equal(t, "1h35m", waiting.Waiting)
+	assert.Equal(t, "github:12 (Fix login) has been waiting for input for 1h35m: Claude needs your permission to use Bash", waiting.Message)
+
+	cleanup := CleanupEvent([]config.SessionMetadata{session, {NamespacedID: "jira:OPS-3"}}, now)
+	assert.Equal(t, 2, cleanup.Count)
+	assert.Equal(t, "sbs clean removed 2 session(s): github:12, jira:OPS-3", cleanup.Message)
+
+	failed := StartFailedEvent(session, "branch", errors.New("branch exists"), now)
+	assert.Equal(t, "Starting github:12 (Fix login) failed at branch: branch exists", failed.Message)
+	assert.Equal(t, "web", failed.Repository)
+
+	assert.Equal(t, "30m", formatWait(30*time.Minute))
+	assert.Equal(t, "2h", formatWait(2*time.Hour))
+}
+
+func TestDispatcher_Send(t *testing.T) {
+	webhook, webhookBodies := postServer(t, http.StatusOK)
+	slack, slackBodies := postServer(t, http.StatusOK)
+	out := filepath.Join(t.TempDir(), "command.out")
+
+	dispatcher := NewDispatcher([]config.NotifierConfig{
+		{Type: "webhook", URL: webhook.URL + "/hooks/secret"},
+		{Type: "slack", URL: slack.URL, Events: []string{StartFailed}, Template: ":x: {{.WorkItem}} failed at {{.Step}}"},
+		{Type: "command", Command: `printf '%s|%s|%s|' "$SBS_EVENT" "$SBS_WORK_ITEM" "$SBS_MESSAGE" > ` + out + `; cat >> ` + out},
+	})
+
+	event := StartFailedEvent(config.SessionMetadata{NamespacedID: "github:12"}, "branch", errors.New("branch exists"), now)
+	require.NoError(t, dispatcher.Send(event))
+
+	require.Len(t, *webhookBodies, 1)
+	var posted Event
+	require.NoError(t, json.Unmarshal([]byte((*webhookBodies)[0]), &posted))
+	assert.Equal(t, event, posted)
+
+	assert.Equal(t, []string{`{"text":":x: github:12 failed at branch"}`}, *slackBodies)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "start-failed|github:12|Starting github:12 failed at branch: branch exists|{")
+
+	// Notifiers only hear of the events they subscribe to
+	require.NoError(t, dispatcher.Send(CleanupEvent([]config.SessionMetadata{{NamespacedID: "github:1"}}, now)))
+	assert.Len(t, *webhookBodies, 2)
+	assert.Len(t, *slackBodies, 1)
+}
+
+func TestDispatcher_SendFailures(t *testing.T) {
+	failing, _ := postServer(t, http.StatusNotFound)
+	working, bodies := postServer(t, http.StatusOK)
+
+	dispatcher := NewDispatcher([]config.NotifierConfig{
+		{Type: "slack", URL: failing.URL + "/services/T000/B000/secret"},
+		{Type: "command", Command: "echo no route >&2; exit 3"},
+		{Type: "slack", URL: working.URL},
+	})
+
+	err := dispatcher.Send(CleanupEvent(nil, now))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slack notifier failed for cleanup: "+failing.URL+"/... returned 404 Not Found")
+	assert.NotContains(t, err.Error(), "secret", "webhook URLs are redacted")
+	assert.Contains(t, err.Error(), "command notifier failed for cleanup: exit status 3: no route")
+	assert.Len(t, *bodies, 1, "one failing notifier doesn't stop the others")
+}
+
+func TestDispatcher_Nil(t *testing.T) {
+	assert.Nil(t, NewDispatcher(nil))
+	var dispatcher *Dispatcher
+	assert.NoError(t, dispatcher.Send(CleanupEvent(nil, now)))
+}
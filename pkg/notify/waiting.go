@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+// WaitingNoticesFile records the waits already announced, in the state directory
+const WaitingNoticesFile = "waiting-notices.json"
+
+// DefaultWaitingNoticesPath returns WaitingNoticesFile in the state directory
+func DefaultWaitingNoticesPath() (string, error) {
+	return paths.StatePath(WaitingNoticesFile)
+}
+
+// Waiter is a session whose agent is waiting for input
+type Waiter struct {
+	Session config.SessionMetadata
+	Since   time.Time // When the agent started waiting
+	Prompt  string    // What it is waiting for, when reported
+}
+
+// WaitingNotifier sends session-waiting once per wait that outlasts a
+// threshold. What was announced is kept in a JSON file, so a restarted
+// watcher doesn't repeat itself. It is safe for concurrent use.
+type WaitingNotifier struct {
+	path  string
+	after time.Duration
+	send  func(Event) error
+	mu    sync.Mutex
+}
+
+// NewWaitingNotifier creates a notifier announcing waits longer than after
+// with send, remembering them in the file at path
+func NewWaitingNotifier(path string, after time.Duration, send func(Event) error) *WaitingNotifier {
+	return &WaitingNotifier{path: path, after: after, send: send}
+}
+
+// Notify sends session-waiting for each waiter past the threshold and not
+// yet announced for its current wait, returning their IDs. Sessions no longer
+// waiting are forgotten, so their next wait is announced anew. Waits whose
+// notification fails are retried next time.
+func (n *WaitingNotifier) Notify(waiters []Waiter, now time.Time) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	announced, err := n.load()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string)
+	var notified []string
+	var failures []error
+	for _, waiter := range waiters {
+		id := waiter.Session.NamespacedID
+		since := waiter.Since.UTC().Format(time.RFC3339)
+		if announced[id] == since {
+			current[id] = since
+			continue
+		}
+		if now.Sub(waiter.Since) < n.after {
+			continue
+		}
+		if err := n.send(WaitingEvent(waiter.Session, waiter.Since, waiter.Prompt, now)); err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		current[id] = since
+		notified = append(notified, id)
+	}
+
+	if len(notified) > 0 || len(current) != len(announced) {
+		if err := n.save(current); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return notified, errors.Join(failures...)
+}
+
+func (n *WaitingNotifier) load() (map[string]string, error) {
+	announced := make(map[string]string)
+	data, err := os.ReadFile(n.path)
+	if os.IsNotExist(err) {
+		return announced, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waiting notices: %w", err)
+	}
+	if err := json.Unmarshal(data, &announced); err != nil {
+		// Forgetting announcements only repeats them
+		return make(map[string]string), nil
+	}
+	return announced, nil
+}
+
+func (n *WaitingNotifier) save(announced map[string]string) error {
+	data, err := json.Marshal(announced)
+	if err != nil {
+		return fmt.Errorf("failed to encode waiting notices: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(n.path), paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create waiting notices directory: %w", err)
+	}
+	tmp := n.path + ".tmp"
+	if err := os.WriteFile(tmp, data, paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write waiting notices: %w", err)
+	}
+	if err := os.Rename(tmp, n.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write waiting notices: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+// recorder collects sent events
+type recorder struct {
+	events []Event
+	err    error
+}
+
+func (r *recorder) send(event Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func waiter(id string, waited time.Duration) Waiter {
+	return Waiter{Session: config.SessionMetadata{NamespacedID: id}, Since: now.Add(-waited)}
+}
+
+func TestWaitingNotifier_AnnouncesEachWaitOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), WaitingNoticesFile)
+	sent := &recorder{}
+	notifier := NewWaitingNotifier(path, 30*time.Minute, sent.send)
+
+	waiters := []Waiter{waiter("github:1", 10*time.Minute), waiter("github:2", 45*time.Minute)}
+	notified, err := notifier.Notify(waiters, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github:2"}, notified)
+	require.Len(t, sent.events, 1)
+	assert.Equal(t, "github:2 has been waiting for input for 45m", sent.events[0].Message)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A restarted watcher doesn't repeat it; the other wait passes the threshold later
+	later := now.Add(25 * time.Minute)
+	notified, err = NewWaitingNotifier(path, 30*time.Minute, sent.send).Notify(waiters, later)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github:1"}, notified)
+	assert.Len(t, sent.events, 2)
+
+	// Once answered and waiting again, a session is announced anew
+	_, err = notifier.Notify([]Waiter{waiters[0]}, later)
+	require.NoError(t, err)
+	waiters[1].Since = later.Add(-time.Hour)
+	notified, err = notifier.Notify(waiters, later)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github:2"}, notified)
+}
+
+func TestWaitingNotifier_RetriesFailures(t *testing.T) {
+	sent := &recorder{err: errors.New("webhook down")}
+	notifier := NewWaitingNotifier(filepath.Join(t.TempDir(), WaitingNoticesFile), time.Minute, sent.send)
+	waiters := []Waiter{waiter("github:1", time.Hour)}
+
+	notified, err := notifier.Notify(waiters, now)
+	assert.EqualError(t, err, "webhook down")
+	assert.Empty(t, notified)
+
+	sent.err = nil
+	notified, err = notifier.Notify(waiters, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github:1"}, notified)
+}
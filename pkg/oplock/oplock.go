@@ -0,0 +1,199 @@
+// Package oplock coordinates sbs processes working on the same work item.
+//
+// Each start, stop or clean of a work item holds an operation lock: an
+// exclusive flock on locks/<work item>.lock in the state directory, with the
+// holder's operation, PID and start time written into the file so a
+// conflicting process can say who it is waiting for. The kernel drops the flock when the
+// holder exits, so a crashed sbs never leaves a work item locked.
+package oplock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sbs/pkg/paths"
+	"syscall"
+	"time"
+)
+
+// Operation is what a lock holder is doing to the work item
+type Operation string
+
+const (
+	OpStart Operation = "start"
+	OpStop  Operation = "stop"
+	OpClean Operation = "clean"
+	OpMove  Operation = "move"
+)
+
+// pollInterval is how often Wait retries a held lock
+const pollInterval = 200 * time.Millisecond
+
+// Holder describes the process holding a lock
+type Holder struct {
+	WorkItem  string    `json:"work_item"`
+	Operation Operation `json:"operation"`
+	PID       int       `json:"pid"`
+	Started   time.Time `json:"started"`
+}
+
+// ConflictError reports a work item locked by another process
+type ConflictError struct {
+	WorkItem string
+	Holder   Holder // Zero when the holder couldn't be read
+}
+
+func (e *ConflictError) Error() string {
+	if e.Holder.PID == 0 {
+		return fmt.Sprintf("work item %s is busy in another sbs process", e.WorkItem)
+	}
+	return fmt.Sprintf("work item %s is busy: %s in progress in another sbs process (pid %d, since %s)",
+		e.WorkItem, e.Holder.Operation, e.Holder.PID, e.Holder.Started.Format("15:04:05"))
+}
+
+// Lock is a held operation lock
+type Lock struct {
+	file *os.File
+	path string
+}
+
+// Dir returns the directory holding operation locks, in the state directory
+func Dir() (string, error) {
+	return paths.StatePath("locks")
+}
+
+// unsafeFileChars are replaced in lock file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// Path returns the lock file of a work item in dir
+func Path(dir, workItem string) string {
+	return filepath.Join(dir, unsafeFileChars.ReplaceAllString(workItem, "_")+".lock")
+}
+
+// Acquire takes the work item's operation lock, or returns a *ConflictError
+// at once when another process holds it
+func Acquire(workItem string, op Operation) (*Lock, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate lock directory: %w", err)
+	}
+	return AcquireIn(dir, workItem, op)
+}
+
+// AcquireIn is Acquire with an explicit lock directory
+func AcquireIn(dir, workItem string, op Operation) (*Lock, error) {
+	if err := os.MkdirAll(dir, paths.PrivateDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	path := Path(dir, workItem)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, paths.PrivateFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			holder, _ := ReadHolder(path)
+			return nil, &ConflictError{WorkItem: workItem, Holder: holder}
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	holder := Holder{WorkItem: workItem, Operation: op, PID: os.Getpid(), Started: time.Now()}
+	if err := writeHolder(file, holder); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to record lock holder: %w", err)
+	}
+	return &Lock{file: file, path: path}, nil
+}
+
+// Wait takes the work item's operation lock, retrying while another process
+// holds it until ctx is done. notify, if set, is called once with the
+// conflict when the first attempt finds the lock held.
+func Wait(ctx context.Context, workItem string, op Operation, notify func(*ConflictError)) (*Lock, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate lock directory: %w", err)
+	}
+	return WaitIn(ctx, dir, workItem, op, notify)
+}
+
+// WaitIn is Wait with an explicit lock directory
+func WaitIn(ctx context.Context, dir, workItem string, op Operation, notify func(*ConflictError)) (*Lock, error) {
+	notified := false
+	for {
+		lock, err := AcquireIn(dir, workItem, op)
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) {
+			return lock, err
+		}
+		if notify != nil && !notified {
+			notify(conflict)
+			notified = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting: %w", conflict)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release drops the lock. Releasing twice, or a nil lock, is a no-op.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	// Clear the holder first so a reader never sees a released lock's holder
+	_ = l.file.Truncate(0)
+	err := l.file.Close() // Closing the descriptor drops the flock
+	l.file = nil
+	return err
+}
+
+// ReadHolder reads who holds (or last held) a lock file
+func ReadHolder(path string) (Holder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Holder{}, err
+	}
+	var holder Holder
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return Holder{}, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	return holder, nil
+}
+
+// writeHolder replaces the lock file's contents with the holder
+func writeHolder(file *os.File, holder Holder) error {
+	data, err := json.Marshal(holder)
+	if err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	_, err = file.WriteAt(data, 0)
+	return err
+}
+
+// File locks an arbitrary file path exclusively, waiting for other holders,
+// and returns the function that unlocks it. It guards read-modify-write
+// cycles such as updating sessions.json.
+func File(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, paths.PrivateFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() { file.Close() }, nil
+}
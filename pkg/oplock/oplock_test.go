@@ -0,0 +1,179 @@
+package oplock
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// holderProcessEnv makes the test binary act as another sbs process holding a lock
+const holderProcessEnv = "SBS_OPLOCK_TEST_HOLDER"
+
+func TestMain(m *testing.M) {
+	if spec := os.Getenv(holderProcessEnv); spec != "" {
+		os.Exit(runHolderProcess(spec))
+	}
+	os.Exit(m.Run())
+}
+
+// runHolderProcess takes the lock named by "dir|workItem|operation", reports
+// "locked" on stdout and holds it until stdin closes
+func runHolderProcess(spec string) int {
+	parts := strings.SplitN(spec, "|", 3)
+	lock, err := AcquireIn(parts[0], parts[1], Operation(parts[2]))
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	fmt.Println("locked")
+	_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	lock.Release()
+	return 0
+}
+
+// startHolderProcess runs a separate process holding a lock and returns it
+// once the lock is taken
+func startHolderProcess(t *testing.T, dir, workItem string, op Operation) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), holderProcessEnv+"="+dir+"|"+workItem+"|"+string(op))
+	stdin, err := cmd.StdinPipe()
+	require.NoError(t, err)
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		stdin.Close()
+		_ = cmd.Wait()
+	})
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "locked\n", line)
+	return cmd
+}
+
+func TestAcquire_ConflictReportsHolder(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireIn(dir, "github:123", OpStart)
+	require.NoError(t, err)
+
+	_, err = AcquireIn(dir, "github:123", OpClean)
+	var conflict *ConflictError
+	require.True(t, errors.As(err, &conflict), "expected a conflict, got %v", err)
+	assert.Equal(t, "github:123", conflict.WorkItem)
+	assert.Equal(t, OpStart, conflict.Holder.Operation)
+	assert.Equal(t, os.Getpid(), conflict.Holder.PID)
+	assert.Contains(t, err.Error(), "start in progress")
+
+	// Other work items are independent
+	other, err := AcquireIn(dir, "github:124", OpStart)
+	require.NoError(t, err)
+	require.NoError(t, other.Release())
+
+	require.NoError(t, lock.Release())
+	require.NoError(t, lock.Release(), "releasing twice is a no-op")
+
+	again, err := AcquireIn(dir, "github:123", OpClean)
+	require.NoError(t, err)
+	require.NoError(t, again.Release())
+}
+
+func TestPath_SanitizesWorkItem(t *testing.T) {
+	assert.Equal(t, "/locks/jira_PROJ-1.lock", Path("/locks", "jira:PROJ-1"))
+	assert.Equal(t, "/locks/.._x.lock", Path("/locks", "../x"))
+}
+
+func TestWait_AcquiresOnceReleased(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireIn(dir, "test:a", OpStop)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(3 * pollInterval)
+		lock.Release()
+	}()
+
+	var notified *ConflictError
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	waited, err := WaitIn(ctx, dir, "test:a", OpStart, func(c *ConflictError) { notified = c })
+	require.NoError(t, err)
+	defer waited.Release()
+
+	require.NotNil(t, notified)
+	assert.Equal(t, OpStop, notified.Holder.Operation)
+}
+
+func TestWait_GivesUpWhenContextEnds(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireIn(dir, "test:a", OpClean)
+	require.NoError(t, err)
+	defer lock.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*pollInterval)
+	defer cancel()
+	_, err = WaitIn(ctx, dir, "test:a", OpStart, nil)
+
+	var conflict *ConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Contains(t, err.Error(), "gave up waiting")
+}
+
+func TestAcquire_ConflictWithAnotherProcess(t *testing.T) {
+	dir := t.TempDir()
+	holder := startHolderProcess(t, dir, "github:7", OpClean)
+
+	_, err := AcquireIn(dir, "github:7", OpStart)
+	var conflict *ConflictError
+	require.True(t, errors.As(err, &conflict), "expected a conflict, got %v", err)
+	assert.Equal(t, holder.Process.Pid, conflict.Holder.PID)
+	assert.Equal(t, OpClean, conflict.Holder.Operation)
+
+	// A holder that dies without releasing frees the lock
+	require.NoError(t, holder.Process.Kill())
+	_ = holder.Wait()
+
+	lock, err := AcquireIn(dir, "github:7", OpStart)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestFile_SerializesHolders(t *testing.T) {
+	path := t.TempDir() + "/sessions.json.lock"
+
+	unlock, err := File(path)
+	require.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		second, err := File(path)
+		if err == nil {
+			close(acquired)
+			second()
+		}
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second holder acquired a held file lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second holder never acquired the released file lock")
+	}
+}
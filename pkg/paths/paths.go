@@ -0,0 +1,191 @@
+// Package paths locates the files sbs keeps on disk. The config file lives in
+// the config directory: --config-dir, $XDG_CONFIG_HOME/sbs or ~/.config/sbs.
+// State (sessions and their archive, status history, progress and locks)
+// lives there too, unless xdg_state moves it under $XDG_STATE_HOME/sbs.
+// Disposable caches live under $XDG_CACHE_HOME/sbs (~/.cache/sbs).
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Modes of the files and directories sbs creates for the user alone: session
+// metadata, logs and config can hold tokens, work item titles and paths
+const (
+	PrivateFileMode os.FileMode = 0600
+	PrivateDirMode  os.FileMode = 0700
+)
+
+// StateFiles are the names of the files and directories sbs keeps in StateDir
+var StateFiles = []string{
+	"sessions.json",
+	"sessions-archive.json",
+	"status-history.json",
+	"progress",
+	"locks",
+	"expiry-notices.json",
+	"waiting-notices.json",
+	"palette-history",
+	"timings.json",
+	"loghook-archive",
+	"aliases.json",
+}
+
+// overrides are the locations chosen by flags and config rather than the
+// environment
+type overrides struct {
+	configDir  string
+	configFile string
+	xdgState   bool
+}
+
+var (
+	current      overrides
+	currentMutex sync.RWMutex
+)
+
+// SetConfigDir sets the config directory from --config-dir; empty restores
+// the XDG default
+func SetConfigDir(dir string) {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current.configDir = dir
+}
+
+// SetConfigFile sets the config file from --config; empty restores
+// config.json in ConfigDir
+func SetConfigFile(path string) {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current.configFile = path
+}
+
+// ConfigFileOverride returns the config file set with SetConfigFile, if any
+func ConfigFileOverride() string {
+	currentMutex.RLock()
+	defer currentMutex.RUnlock()
+	return current.configFile
+}
+
+// SetXDGState sets whether state is kept under $XDG_STATE_HOME/sbs instead
+// of the config directory
+func SetXDGState(enabled bool) {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current.xdgState = enabled
+}
+
+// XDGState reports whether state is kept under $XDG_STATE_HOME/sbs
+func XDGState() bool {
+	currentMutex.RLock()
+	defer currentMutex.RUnlock()
+	return current.xdgState
+}
+
+// xdgDir returns $<envVar>/sbs, or ~/<fallback>/sbs when the variable is
+// unset or not absolute, as the XDG spec requires
+func xdgDir(envVar, fallback string) (string, error) {
+	if base := os.Getenv(envVar); filepath.IsAbs(base) {
+		return filepath.Join(base, "sbs"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, fallback, "sbs"), nil
+}
+
+// ConfigDir returns the directory holding config.json: --config-dir, or
+// $XDG_CONFIG_HOME/sbs (~/.config/sbs). While $XDG_CONFIG_HOME/sbs doesn't
+// exist, an existing ~/.config/sbs is used instead, so config and sessions
+// kept there before sbs honoured XDG_CONFIG_HOME aren't lost.
+func ConfigDir() (string, error) {
+	currentMutex.RLock()
+	dir := current.configDir
+	currentMutex.RUnlock()
+	if dir != "" {
+		return filepath.Abs(dir)
+	}
+	dir, err := XDGConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if legacy := LegacyConfigDir(); legacy != "" && legacy != dir {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+				return legacy, nil
+			}
+		}
+	}
+	return dir, nil
+}
+
+// XDGConfigDir returns $XDG_CONFIG_HOME/sbs (~/.config/sbs), whether or not
+// ConfigDir falls back from it
+func XDGConfigDir() (string, error) {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// LegacyConfigDir returns ~/.config/sbs, where sbs kept its config whatever
+// XDG_CONFIG_HOME said, or "" without a home directory
+func LegacyConfigDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "sbs")
+}
+
+// ConfigFile returns the global config file: --config, or config.json in
+// ConfigDir
+func ConfigFile() (string, error) {
+	if file := ConfigFileOverride(); file != "" {
+		return filepath.Abs(file)
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// StateDir returns the directory holding sbs's state: ConfigDir, or with
+// xdg_state $XDG_STATE_HOME/sbs (~/.local/state/sbs)
+func StateDir() (string, error) {
+	if !XDGState() {
+		return ConfigDir()
+	}
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CacheDir returns the directory holding disposable caches:
+// $XDG_CACHE_HOME/sbs (~/.cache/sbs)
+func CacheDir() (string, error) {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// StatePath returns the path of a file in StateDir
+func StatePath(name string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// CachePath returns the path of a file in CacheDir
+func CachePath(name string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// TooOpen reports whether a file's mode lets users other than its owner read
+// or write it
+func TooOpen(mode os.FileMode) bool {
+	return mode.Perm()&0077 != 0
+}
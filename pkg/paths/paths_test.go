@@ -0,0 +1,181 @@
+package paths
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Cleanup(func() { SetXDGState(false) })
+
+	configDir, err := ConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "sbs"), configDir)
+
+	stateDir, err := StateDir()
+	require.NoError(t, err)
+	assert.Equal(t, configDir, stateDir, "state stays with the config by default")
+
+	SetXDGState(true)
+	stateDir, err = StateDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "sbs"), stateDir)
+
+	t.Setenv("XDG_STATE_HOME", "/var/state")
+	sessionsPath, err := StatePath("sessions.json")
+	require.NoError(t, err)
+	assert.Equal(t, "/var/state/sbs/sessions.json", sessionsPath)
+
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+	stateDir, err = StateDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "sbs"), stateDir, "relative XDG_STATE_HOME is ignored")
+}
+
+func TestConfigDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	t.Cleanup(func() {
+		SetConfigDir("")
+		SetConfigFile("")
+	})
+
+	configFile, err := ConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "xdg", "sbs", "config.json"), configFile)
+
+	SetConfigDir(filepath.Join(home, "isolated"))
+	configFile, err = ConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "isolated", "config.json"), configFile)
+	stateDir, err := StateDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "isolated"), stateDir, "state follows --config-dir")
+
+	SetConfigFile("/etc/sbs.json")
+	configFile, err = ConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/sbs.json", configFile)
+	assert.Equal(t, "/etc/sbs.json", ConfigFileOverride())
+}
+
+func TestConfigDir_LegacyFallback(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	xdgConfig := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdgConfig)
+	legacy := filepath.Join(home, ".config", "sbs")
+	require.NoError(t, os.MkdirAll(legacy, 0700))
+
+	configDir, err := ConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, legacy, configDir, "existing ~/.config/sbs is kept until $XDG_CONFIG_HOME/sbs exists")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(xdgConfig, "sbs"), 0700))
+	configDir, err = ConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(xdgConfig, "sbs"), configDir)
+}
+
+func TestCacheDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", "")
+	cachePath, err := CachePath("diffstat-cache.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".cache", "sbs", "diffstat-cache.json"), cachePath)
+
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	cacheDir, err := CacheDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/xdg/cache/sbs", cacheDir)
+}
+
+func TestTooOpen(t *testing.T) {
+	assert.False(t, TooOpen(PrivateFileMode))
+	assert.False(t, TooOpen(0400))
+	assert.True(t, TooOpen(0644))
+	assert.True(t, TooOpen(0620))
+	assert.True(t, TooOpen(os.ModeDir|0755))
+}
+
+func TestStateFiles_CoverStatePaths(t *testing.T) {
+	// Every name passed to StatePath in the tree must be a state file, or
+	// xdg_state moves and doctor's permission check miss it
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+	constants := make(map[string]string) // "<dir>.<name>" -> value
+	var calls []string                   // string literals or "<dir>.<name>"
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && path != root {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(path)
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if i < len(node.Values) {
+						if literal, ok := node.Values[i].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+							constants[dir+"."+name.Name], _ = strconv.Unquote(literal.Value)
+						}
+					}
+				}
+			case *ast.CallExpr:
+				selector, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || selector.Sel.Name != "StatePath" || len(node.Args) != 1 {
+					return true
+				}
+				if pkg, ok := selector.X.(*ast.Ident); !ok || pkg.Name != "paths" {
+					return true
+				}
+				switch arg := node.Args[0].(type) {
+				case *ast.BasicLit:
+					name, _ := strconv.Unquote(arg.Value)
+					calls = append(calls, name)
+				case *ast.Ident:
+					calls = append(calls, dir+"."+arg.Name)
+				default:
+					t.Errorf("%s: StatePath argument must be a string literal or constant", fset.Position(node.Pos()))
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, calls)
+
+	for _, call := range calls {
+		name := call
+		if value, ok := constants[call]; ok {
+			name = value
+		}
+		assert.Contains(t, StateFiles, name, "paths.StatePath(%s) is missing from StateFiles", call)
+	}
+}
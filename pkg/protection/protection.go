@@ -0,0 +1,114 @@
+// Package protection decides which branches and worktrees sbs must never
+// delete. main and master are always protected; protected_branches and
+// protected_worktrees add glob patterns for long-lived branches (integration
+// branches managed through sbs) and worktrees that cleanup should leave be.
+package protection
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"sbs/pkg/config"
+)
+
+// BuiltinBranches are protected regardless of configuration
+var BuiltinBranches = []string{"main", "master"}
+
+// Rules holds the protected branch and worktree patterns. The zero value
+// protects only the built-in branches.
+type Rules struct {
+	branches  []string
+	worktrees []string
+}
+
+// New creates rules from branch patterns (path.Match globs such as
+// "release/*") and worktree patterns (paths or filepath.Match globs; "~/"
+// expands to the home directory)
+func New(branches, worktrees []string) Rules {
+	rules := Rules{branches: append([]string(nil), branches...)}
+	for _, pattern := range worktrees {
+		rules.worktrees = append(rules.worktrees, expandHome(pattern))
+	}
+	return rules
+}
+
+// FromConfig creates rules from protected_branches and protected_worktrees
+func FromConfig(cfg *config.Config) Rules {
+	if cfg == nil {
+		return Rules{}
+	}
+	return New(cfg.ProtectedBranches, cfg.ProtectedWorktrees)
+}
+
+// ForRepository creates rules from the global config merged with the
+// repository's .sbs/config.json; without a readable config only the built-in
+// branches are protected
+func ForRepository(repoRoot string) Rules {
+	cfg, err := config.LoadConfigWithRepository(repoRoot)
+	if err != nil {
+		return Rules{}
+	}
+	return FromConfig(cfg)
+}
+
+// Error reports a refused deletion of a protected branch or worktree
+type Error struct {
+	Kind    string // "branch" or "worktree"
+	Target  string
+	Setting string // Config setting with the matching pattern; "" for main and master
+	Pattern string
+}
+
+func (e *Error) Error() string {
+	if e.Setting == "" {
+		return fmt.Sprintf("%s %s is protected", e.Kind, e.Target)
+	}
+	return fmt.Sprintf("%s %s is protected (matches %s pattern %q)", e.Kind, e.Target, e.Setting, e.Pattern)
+}
+
+// CheckBranch returns an *Error when the branch is protected
+func (r Rules) CheckBranch(branch string) error {
+	for _, builtin := range BuiltinBranches {
+		if branch == builtin {
+			return &Error{Kind: "branch", Target: branch, Pattern: builtin}
+		}
+	}
+	for _, pattern := range r.branches {
+		if matched, _ := path.Match(pattern, branch); matched || pattern == branch {
+			return &Error{Kind: "branch", Target: branch, Setting: "protected_branches", Pattern: pattern}
+		}
+	}
+	return nil
+}
+
+// CheckWorktree returns an *Error when the worktree is protected: it matches
+// a pattern or lies inside a protected directory
+func (r Rules) CheckWorktree(worktreePath string) error {
+	if worktreePath == "" {
+		return nil
+	}
+	cleaned := filepath.Clean(worktreePath)
+	for _, pattern := range r.worktrees {
+		pattern = filepath.Clean(pattern)
+		matched, _ := filepath.Match(pattern, cleaned)
+		if matched || cleaned == pattern || strings.HasPrefix(cleaned, pattern+string(filepath.Separator)) {
+			return &Error{Kind: "worktree", Target: worktreePath, Setting: "protected_worktrees", Pattern: pattern}
+		}
+	}
+	return nil
+}
+
+// expandHome replaces a leading "~/" with the home directory
+func expandHome(pattern string) string {
+	if !strings.HasPrefix(pattern, "~/") {
+		return pattern
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return pattern
+	}
+	return filepath.Join(homeDir, pattern[2:])
+}
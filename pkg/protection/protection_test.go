@@ -0,0 +1,50 @@
+package protection
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestRules_CheckBranch(t *testing.T) {
+	rules := New([]string{"release/*", "integration"}, nil)
+
+	assert.NoError(t, rules.CheckBranch("issue-github-1-fix"))
+	assert.NoError(t, rules.CheckBranch("release/1.0/hotfix"), "globs don't cross slashes")
+
+	err := rules.CheckBranch("release/1.0")
+	var protectedErr *Error
+	require.True(t, errors.As(err, &protectedErr))
+	assert.Equal(t, "release/*", protectedErr.Pattern)
+	assert.Equal(t, `branch release/1.0 is protected (matches protected_branches pattern "release/*")`, err.Error())
+
+	assert.Error(t, rules.CheckBranch("integration"))
+	assert.EqualError(t, Rules{}.CheckBranch("main"), "branch main is protected")
+	assert.Error(t, Rules{}.CheckBranch("master"))
+}
+
+func TestRules_CheckWorktree(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	rules := New(nil, []string{"~/.sbs-worktrees/*integration*", "/srv/keep"})
+
+	assert.Error(t, rules.CheckWorktree("/home/dev/.sbs-worktrees/issue-github-7-integration"))
+	assert.Error(t, rules.CheckWorktree("/srv/keep"))
+	assert.Error(t, rules.CheckWorktree("/srv/keep/issue-1/"), "worktrees inside a protected directory are protected")
+	assert.NoError(t, rules.CheckWorktree("/srv/keeper"))
+	assert.NoError(t, rules.CheckWorktree(filepath.Join("/home/dev/.sbs-worktrees", "issue-github-8-fix")))
+	assert.NoError(t, rules.CheckWorktree(""))
+
+	err := rules.CheckWorktree("/srv/keep")
+	assert.Contains(t, err.Error(), `matches protected_worktrees pattern "/srv/keep"`)
+}
+
+func TestFromConfig(t *testing.T) {
+	rules := FromConfig(&config.Config{ProtectedBranches: []string{"develop"}})
+	assert.Error(t, rules.CheckBranch("develop"))
+	assert.NoError(t, FromConfig(nil).CheckBranch("develop"))
+}
@@ -0,0 +1,121 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sbs/pkg/paths"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProgressStaleAfter is how long an entry may go without updates before the
+// start that wrote it is assumed to have died
+const ProgressStaleAfter = 30 * time.Second
+
+// Progress is the published state of a session that is still being started
+type Progress struct {
+	WorkItem  string    `json:"work_item"`
+	Step      string    `json:"step"`
+	Phase     string    `json:"phase,omitempty"` // e.g. "Updating files"
+	Current   int       `json:"current,omitempty"`
+	Total     int       `json:"total,omitempty"`
+	Percent   int       `json:"percent,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProgressBoard publishes the progress of in-flight starts as one small JSON
+// file per work item so other sbs processes, such as the TUI, can show it
+type ProgressBoard struct {
+	dir string
+}
+
+// NewProgressBoard creates a board stored in dir
+func NewProgressBoard(dir string) *ProgressBoard {
+	return &ProgressBoard{dir: dir}
+}
+
+// DefaultProgressDir returns the progress directory in the state directory
+func DefaultProgressDir() (string, error) {
+	return paths.StatePath("progress")
+}
+
+func (b *ProgressBoard) path(workItem string) string {
+	return filepath.Join(b.dir, strings.NewReplacer("/", "_", ":", "_").Replace(workItem)+".json")
+}
+
+// Update publishes p, replacing any earlier progress for the same work item
+func (b *ProgressBoard) Update(p Progress) error {
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = time.Now()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode start progress: %w", err)
+	}
+	if err := os.MkdirAll(b.dir, paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create progress directory: %w", err)
+	}
+	path := b.path(p.WorkItem)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write start progress: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write start progress: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the work item's progress once its start has finished
+func (b *ProgressBoard) Clear(workItem string) error {
+	if err := os.Remove(b.path(workItem)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear start progress: %w", err)
+	}
+	return nil
+}
+
+// Active returns the progress of starts updated within ProgressStaleAfter of
+// now, sorted by work item. Stale and unreadable entries are removed.
+func (b *ProgressBoard) Active(now time.Time) ([]Progress, error) {
+	entries, err := os.ReadDir(b.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read progress directory: %w", err)
+	}
+
+	var active []Progress
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(b.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var p Progress
+		if err := json.Unmarshal(data, &p); err != nil || now.Sub(p.UpdatedAt) > ProgressStaleAfter {
+			os.Remove(path)
+			continue
+		}
+		active = append(active, p)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].WorkItem < active[j].WorkItem })
+	return active, nil
+}
+
+// String describes the progress for a status line, e.g.
+// "github:123 worktree: Updating files 45% (1234/2742)"
+func (p Progress) String() string {
+	s := p.WorkItem + " " + p.Step
+	if p.Phase != "" {
+		s += fmt.Sprintf(": %s %d%% (%d/%d)", p.Phase, p.Percent, p.Current, p.Total)
+	}
+	return s
+}
@@ -0,0 +1,40 @@
+package provisioning
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressBoard(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "progress")
+	board := NewProgressBoard(dir)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	active, err := board.Active(now)
+	require.NoError(t, err)
+	assert.Empty(t, active, "a missing directory means nothing is starting")
+
+	require.NoError(t, board.Update(Progress{WorkItem: "github:2", Step: "worktree", Phase: "Updating files", Current: 1234, Total: 2742, Percent: 45, UpdatedAt: now}))
+	require.NoError(t, board.Update(Progress{WorkItem: "test:1", Step: "branch", UpdatedAt: now}))
+	require.NoError(t, board.Update(Progress{WorkItem: "github:9", Step: "worktree", UpdatedAt: now.Add(-time.Minute)}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644))
+
+	active, err = board.Active(now)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, "github:2 worktree: Updating files 45% (1234/2742)", active[0].String())
+	assert.Equal(t, "test:1 branch", active[1].String())
+	assert.NoFileExists(t, filepath.Join(dir, "github_9.json"), "stale entries are removed")
+	assert.NoFileExists(t, filepath.Join(dir, "broken.json"))
+
+	require.NoError(t, board.Clear("github:2"))
+	require.NoError(t, board.Clear("github:2"), "clearing twice is harmless")
+	active, err = board.Active(now)
+	require.NoError(t, err)
+	assert.Len(t, active, 1)
+}
@@ -0,0 +1,183 @@
+// Package provisioning runs the steps that set up a session as a dependency
+// graph, starting each step as soon as the steps it depends on have finished
+// so independent work (for example tmux setup and copying files into a fresh
+// worktree) overlaps.
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Step is one unit of provisioning work
+type Step struct {
+	Name      string
+	DependsOn []string                        // steps that must succeed before this one starts
+	Run       func(ctx context.Context) error // ctx is cancelled once any step fails
+}
+
+// Result reports how a step went
+type Result struct {
+	Step     string
+	Duration time.Duration
+	Err      error
+	Skipped  bool // not run because a dependency failed or the run was cancelled
+}
+
+// ErrSkipped is the error of a step that never ran
+var ErrSkipped = errors.New("skipped")
+
+// Observer is told about steps as they run; either hook may be nil. Hooks are
+// called from the steps' goroutines, so they must be safe for concurrent use.
+type Observer struct {
+	Started  func(step string)
+	Finished func(result Result) // Also called for skipped steps
+}
+
+// Run executes steps concurrently as their dependencies allow and returns a
+// result per step in the order given. The returned error is that of the first
+// failed step in that order, unwrapped so callers see the step's own message;
+// steps that depend on a failed step are skipped.
+func Run(ctx context.Context, steps ...Step) ([]Result, error) {
+	return RunObserved(ctx, Observer{}, steps...)
+}
+
+// RunObserved is Run reporting each step's start and end to observer
+func RunObserved(ctx context.Context, observer Observer, steps ...Step) ([]Result, error) {
+	if err := validate(steps); err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	done := make(map[string]chan struct{}, len(steps))
+	for _, step := range steps {
+		done[step.Name] = make(chan struct{})
+	}
+	results := make([]Result, len(steps))
+	failed := make(map[string]bool, len(steps))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step Step) {
+			defer wg.Done()
+			defer close(done[step.Name])
+			result := Result{Step: step.Name}
+			defer func() {
+				mu.Lock()
+				results[i] = result
+				if result.Err != nil {
+					failed[step.Name] = true
+				}
+				mu.Unlock()
+				if observer.Finished != nil {
+					observer.Finished(result)
+				}
+			}()
+
+			for _, dep := range step.DependsOn {
+				<-done[dep]
+			}
+			mu.Lock()
+			blocked := false
+			for _, dep := range step.DependsOn {
+				blocked = blocked || failed[dep]
+			}
+			mu.Unlock()
+			if blocked || ctx.Err() != nil {
+				result.Skipped = true
+				result.Err = ErrSkipped
+				return
+			}
+
+			if observer.Started != nil {
+				observer.Started(step.Name)
+			}
+			start := time.Now()
+			result.Err = step.Run(ctx)
+			result.Duration = time.Since(start)
+			if result.Err != nil {
+				cancel()
+			}
+		}(i, step)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result.Err != nil && !result.Skipped {
+			return results, result.Err
+		}
+	}
+	// Without a failure, steps are only skipped when the caller cancelled
+	for _, result := range results {
+		if result.Skipped {
+			return results, fmt.Errorf("provisioning interrupted: %w", parent.Err())
+		}
+	}
+	return results, nil
+}
+
+// validate rejects unnamed or duplicate steps, unknown dependencies and cycles
+func validate(steps []Step) error {
+	index := make(map[string]Step, len(steps))
+	for _, step := range steps {
+		if step.Name == "" {
+			return fmt.Errorf("provisioning step has no name")
+		}
+		if step.Run == nil {
+			return fmt.Errorf("provisioning step %s has nothing to run", step.Name)
+		}
+		if _, ok := index[step.Name]; ok {
+			return fmt.Errorf("duplicate provisioning step %s", step.Name)
+		}
+		index[step.Name] = step
+	}
+	for _, step := range steps {
+		for _, dep := range step.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("provisioning step %s depends on unknown step %s", step.Name, dep)
+			}
+		}
+	}
+
+	// Depth-first search for cycles
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(steps))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("provisioning steps form a cycle: %s -> %s", strings.Join(path, " -> "), name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range index[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, step := range steps {
+		if err := visit(step.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
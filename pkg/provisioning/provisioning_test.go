@@ -0,0 +1,126 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_RespectsDependenciesAndOverlaps(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) { mu.Lock(); order = append(order, name); mu.Unlock() }
+
+	// slow and fast have no dependency on each other, so fast must finish
+	// while slow is still waiting for it to start
+	fastDone := make(chan struct{})
+	steps := []Step{
+		{Name: "branch", Run: func(ctx context.Context) error { record("branch"); return nil }},
+		{Name: "worktree", DependsOn: []string{"branch"}, Run: func(ctx context.Context) error { record("worktree"); return nil }},
+		{Name: "slow", DependsOn: []string{"worktree"}, Run: func(ctx context.Context) error {
+			select {
+			case <-fastDone:
+			case <-time.After(5 * time.Second):
+				return errors.New("independent step did not overlap")
+			}
+			record("slow")
+			return nil
+		}},
+		{Name: "fast", Run: func(ctx context.Context) error { record("fast"); close(fastDone); return nil }},
+	}
+
+	results, err := Run(context.Background(), steps...)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, "branch", results[0].Step)
+	assert.Less(t, indexOf(order, "branch"), indexOf(order, "worktree"))
+	assert.Less(t, indexOf(order, "worktree"), indexOf(order, "slow"))
+	assert.Less(t, indexOf(order, "fast"), indexOf(order, "slow"))
+}
+
+func TestRun_FailureSkipsDependents(t *testing.T) {
+	boom := errors.New("failed to create worktree")
+	var cancelled bool
+	started := make(chan struct{})
+	results, err := Run(context.Background(),
+		Step{Name: "worktree", Run: func(ctx context.Context) error { <-started; return boom }},
+		Step{Name: "tmux", DependsOn: []string{"worktree"}, Run: func(ctx context.Context) error { return nil }},
+		Step{Name: "sandbox", Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			cancelled = true
+			return nil
+		}},
+	)
+
+	assert.Equal(t, boom, err, "the step's own error is returned")
+	assert.True(t, results[1].Skipped)
+	assert.ErrorIs(t, results[1].Err, ErrSkipped)
+	assert.True(t, cancelled, "running steps see the context cancelled")
+}
+
+func TestRun_CallerCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := Run(ctx, Step{Name: "branch", Run: func(ctx context.Context) error { return nil }})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, results[0].Skipped)
+}
+
+func TestRunObserved_ReportsStartsAndFinishes(t *testing.T) {
+	var mu sync.Mutex
+	var started []string
+	finished := map[string]Result{}
+	observer := Observer{
+		Started:  func(step string) { mu.Lock(); started = append(started, step); mu.Unlock() },
+		Finished: func(result Result) { mu.Lock(); finished[result.Step] = result; mu.Unlock() },
+	}
+
+	boom := errors.New("boom")
+	_, err := RunObserved(context.Background(), observer,
+		Step{Name: "branch", Run: func(ctx context.Context) error { return boom }},
+		Step{Name: "worktree", DependsOn: []string{"branch"}, Run: func(ctx context.Context) error { return nil }},
+	)
+	require.Equal(t, boom, err)
+
+	assert.Equal(t, []string{"branch"}, started, "skipped steps never start")
+	require.Len(t, finished, 2)
+	assert.Equal(t, boom, finished["branch"].Err)
+	assert.True(t, finished["worktree"].Skipped)
+}
+
+func TestRun_InvalidGraphs(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+	tests := []struct {
+		name  string
+		steps []Step
+		err   string
+	}{
+		{name: "unknown_dependency", steps: []Step{{Name: "a", DependsOn: []string{"b"}, Run: noop}}, err: "unknown step b"},
+		{name: "duplicate", steps: []Step{{Name: "a", Run: noop}, {Name: "a", Run: noop}}, err: "duplicate"},
+		{name: "cycle", steps: []Step{{Name: "a", DependsOn: []string{"b"}, Run: noop}, {Name: "b", DependsOn: []string{"a"}, Run: noop}}, err: "cycle: a -> b -> a"},
+		{name: "no_run", steps: []Step{{Name: "a"}}, err: "nothing to run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Run(context.Background(), tt.steps...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,119 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Remote statuses recorded on a session for its pull request's checks
+const (
+	StatusPending  = "checks pending"
+	StatusPassing  = "checks passed"
+	StatusFailing  = "checks failed"
+	StatusNoChecks = "open"
+)
+
+// Check is one check run or status reported on a pull request. Bucket
+// groups gh's states: pass, fail, pending, skipping or cancel.
+type Check struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`
+	Bucket string `json:"bucket"`
+	Link   string `json:"link"`
+}
+
+// Summary is where a pull request's checks stand. When the base branch
+// requires checks only those decide the outcome; a required check not
+// reported yet counts as pending.
+type Summary struct {
+	Checks   []Check
+	Required []string
+	Passed   int
+	Failed   []string // Names of the deciding checks that failed or were cancelled
+	Pending  []string // Names of the deciding checks still running or not reported
+}
+
+// Summarize tallies checks, deciding on the required ones when there are any
+func Summarize(checks []Check, required []string) Summary {
+	summary := Summary{Checks: checks, Required: required}
+	reported := map[string]bool{}
+	for _, check := range checks {
+		reported[check.Name] = true
+		if len(required) > 0 && !contains(required, check.Name) {
+			continue
+		}
+		switch check.Bucket {
+		case "pass", "skipping":
+			summary.Passed++
+		case "fail", "cancel":
+			summary.Failed = append(summary.Failed, check.Name)
+		default:
+			summary.Pending = append(summary.Pending, check.Name)
+		}
+	}
+	for _, name := range required {
+		if !reported[name] {
+			summary.Pending = append(summary.Pending, name)
+		}
+	}
+	return summary
+}
+
+// Status is the remote status recorded for the summary
+func (s Summary) Status() string {
+	switch {
+	case len(s.Failed) > 0:
+		return StatusFailing
+	case len(s.Pending) > 0:
+		return StatusPending
+	case s.Passed > 0:
+		return StatusPassing
+	}
+	return StatusNoChecks
+}
+
+// Done reports whether waiting longer can't change the outcome: a deciding
+// check failed, or none is pending
+func (s Summary) Done() bool {
+	return len(s.Failed) > 0 || len(s.Pending) == 0
+}
+
+// String describes the tally, e.g. "3 passed, 1 failed (lint), 2 pending"
+func (s Summary) String() string {
+	if s.Passed == 0 && len(s.Failed) == 0 && len(s.Pending) == 0 {
+		return "no checks reported"
+	}
+	parts := []string{fmt.Sprintf("%d passed", s.Passed)}
+	if len(s.Failed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed (%s)", len(s.Failed), strings.Join(s.Failed, ", ")))
+	}
+	if len(s.Pending) > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", len(s.Pending)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Wait polls checks every interval until they are done or ctx ends,
+// calling onPoll with each summary. It returns the last summary, and
+// ctx's error when ctx ended first.
+func Wait(ctx context.Context, interval time.Duration, poll func() (Summary, error), onPoll func(Summary)) (Summary, error) {
+	for {
+		summary, err := poll()
+		if err != nil {
+			return summary, err
+		}
+		if onPoll != nil {
+			onPoll(summary)
+		}
+		if summary.Done() {
+			return summary, nil
+		}
+		select {
+		case <-ctx.Done():
+			return summary, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
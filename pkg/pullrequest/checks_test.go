@@ -0,0 +1,90 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	checks := []Check{
+		{Name: "build", Bucket: "pass"},
+		{Name: "lint", Bucket: "fail"},
+		{Name: "docs", Bucket: "skipping"},
+		{Name: "e2e", Bucket: "pending"},
+	}
+
+	t.Run("all checks decide without required ones", func(t *testing.T) {
+		summary := Summarize(checks, nil)
+		assert.Equal(t, 2, summary.Passed)
+		assert.Equal(t, []string{"lint"}, summary.Failed)
+		assert.Equal(t, []string{"e2e"}, summary.Pending)
+		assert.Equal(t, StatusFailing, summary.Status())
+		assert.True(t, summary.Done(), "a failure can't be undone by waiting")
+		assert.Equal(t, "2 passed, 1 failed (lint), 1 pending", summary.String())
+	})
+
+	t.Run("only required checks decide", func(t *testing.T) {
+		summary := Summarize(checks, []string{"build", "e2e"})
+		assert.Equal(t, 1, summary.Passed)
+		assert.Empty(t, summary.Failed)
+		assert.Equal(t, StatusPending, summary.Status())
+		assert.False(t, summary.Done())
+	})
+
+	t.Run("required checks not reported yet are pending", func(t *testing.T) {
+		summary := Summarize(checks[:1], []string{"build", "security"})
+		assert.Equal(t, []string{"security"}, summary.Pending)
+		assert.Equal(t, StatusPending, summary.Status())
+	})
+
+	t.Run("passing", func(t *testing.T) {
+		summary := Summarize(checks, []string{"build", "docs"})
+		assert.Equal(t, StatusPassing, summary.Status())
+		assert.True(t, summary.Done())
+	})
+
+	t.Run("no checks", func(t *testing.T) {
+		summary := Summarize(nil, nil)
+		assert.Equal(t, StatusNoChecks, summary.Status())
+		assert.True(t, summary.Done())
+		assert.Equal(t, "no checks reported", summary.String())
+	})
+}
+
+func TestWait(t *testing.T) {
+	pending := Summarize([]Check{{Name: "build", Bucket: "pending"}}, nil)
+	passed := Summarize([]Check{{Name: "build", Bucket: "pass"}}, nil)
+
+	t.Run("polls until done", func(t *testing.T) {
+		results := []Summary{pending, pending, passed}
+		var seen []string
+		summary, err := Wait(context.Background(), time.Millisecond, func() (Summary, error) {
+			next := results[0]
+			results = results[1:]
+			return next, nil
+		}, func(s Summary) { seen = append(seen, s.Status()) })
+		require.NoError(t, err)
+		assert.Equal(t, StatusPassing, summary.Status())
+		assert.Equal(t, []string{StatusPending, StatusPending, StatusPassing}, seen)
+	})
+
+	t.Run("stops when the context ends", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		summary, err := Wait(ctx, time.Millisecond, func() (Summary, error) { return pending, nil }, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, StatusPending, summary.Status())
+	})
+
+	t.Run("poll errors end the wait", func(t *testing.T) {
+		_, err := Wait(context.Background(), time.Millisecond, func() (Summary, error) {
+			return Summary{}, errors.New("HTTP 502")
+		}, nil)
+		assert.EqualError(t, err, "HTTP 502")
+	})
+}
@@ -0,0 +1,187 @@
+// Package pullrequest opens a session branch's pull request with the GitHub
+// CLI and follows the checks its base branch requires.
+package pullrequest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+)
+
+// commandExecutor runs gh in a directory; replaced in tests
+type commandExecutor interface {
+	executeCommand(dir, name string, args ...string) ([]byte, error)
+}
+
+// realCommandExecutor implements commandExecutor using os/exec
+type realCommandExecutor struct{}
+
+func (r *realCommandExecutor) executeCommand(dir, name string, args ...string) ([]byte, error) {
+	ctx := cmdlog.LogCommandGlobal(name, args, cmdlog.GetCaller())
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	start := time.Now()
+	output, err := cmd.Output()
+	duration := time.Since(start)
+
+	if err != nil {
+		exitCode := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+		ctx.LogCompletion(false, exitCode, err.Error(), duration)
+		return output, err
+	}
+
+	ctx.LogCompletion(true, 0, "", duration)
+	return output, nil
+}
+
+// PullRequest is a pull request as gh pr view reports it
+type PullRequest struct {
+	Number      int    `json:"number"`
+	URL         string `json:"url"`
+	State       string `json:"state"`
+	BaseRefName string `json:"baseRefName"`
+	HeadRefName string `json:"headRefName"`
+}
+
+// Client runs gh for the repository checked out in a directory, usually a
+// session worktree
+type Client struct {
+	dir      string
+	executor commandExecutor
+}
+
+// NewClient returns a client running gh in dir
+func NewClient(dir string) *Client {
+	return &Client{dir: dir, executor: &realCommandExecutor{}}
+}
+
+// Find returns the pull request of branch, or nil when it has none
+func (c *Client) Find(branch string) (*PullRequest, error) {
+	output, err := c.executor.executeCommand(c.dir, "gh", "pr", "view", branch, "--json", "number,url,state,baseRefName,headRefName")
+	if err != nil {
+		if strings.Contains(stderr(err), "no pull requests found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to look up the pull request of %s: %w", branch, ghError(err))
+	}
+	var pr PullRequest
+	if err := json.Unmarshal(output, &pr); err != nil {
+		return nil, fmt.Errorf("failed to parse gh pr view output: %w", err)
+	}
+	return &pr, nil
+}
+
+// Create opens a pull request for branch, titled and described from its
+// commits, against base or the repository's default branch when base is ""
+func (c *Client) Create(branch, base string, draft bool) (*PullRequest, error) {
+	args := []string{"pr", "create", "--head", branch, "--fill"}
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+	if draft {
+		args = append(args, "--draft")
+	}
+	if _, err := c.executor.executeCommand(c.dir, "gh", args...); err != nil {
+		return nil, fmt.Errorf("failed to create a pull request for %s: %w", branch, ghError(err))
+	}
+	pr, err := c.Find(branch)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, fmt.Errorf("gh created no pull request for %s", branch)
+	}
+	return pr, nil
+}
+
+// RequiredChecks returns the status checks branch protection requires on
+// base before merging; none when base isn't protected or requires none
+func (c *Client) RequiredChecks(base string) ([]string, error) {
+	output, err := c.executor.executeCommand(c.dir, "gh", "api", "repos/{owner}/{repo}/branches/"+base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the protection of %s: %w", base, ghError(err))
+	}
+	var branch struct {
+		Protected  bool `json:"protected"`
+		Protection struct {
+			RequiredStatusChecks struct {
+				Contexts []string `json:"contexts"`
+				Checks   []struct {
+					Context string `json:"context"`
+				} `json:"checks"`
+			} `json:"required_status_checks"`
+		} `json:"protection"`
+	}
+	if err := json.Unmarshal(output, &branch); err != nil {
+		return nil, fmt.Errorf("failed to parse the protection of %s: %w", base, err)
+	}
+	if !branch.Protected {
+		return nil, nil
+	}
+
+	required := branch.Protection.RequiredStatusChecks
+	names := append([]string(nil), required.Contexts...)
+	for _, check := range required.Checks {
+		if !contains(names, check.Context) {
+			names = append(names, check.Context)
+		}
+	}
+	return names, nil
+}
+
+// Checks returns the checks reported on a pull request so far
+func (c *Client) Checks(number int) ([]Check, error) {
+	output, err := c.executor.executeCommand(c.dir, "gh", "pr", "checks", strconv.Itoa(number), "--json", "name,state,bucket,link")
+	if err != nil {
+		// gh exits non-zero while checks are pending or failing, still
+		// printing them; with none reported yet it prints nothing
+		if strings.Contains(stderr(err), "no checks reported") {
+			return nil, nil
+		}
+		if len(output) == 0 {
+			return nil, fmt.Errorf("failed to read the checks of #%d: %w", number, ghError(err))
+		}
+	}
+	var checks []Check
+	if err := json.Unmarshal(output, &checks); err != nil {
+		return nil, fmt.Errorf("failed to parse gh pr checks output: %w", err)
+	}
+	return checks, nil
+}
+
+// stderr returns what a failed gh command printed on stderr
+func stderr(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(exitErr.Stderr)
+	}
+	return ""
+}
+
+// ghError adds gh's message to the error of a failed gh command
+func ghError(err error) error {
+	if message := strings.TrimSpace(stderr(err)); message != "" {
+		return fmt.Errorf("%w: %s", err, message)
+	}
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package pullrequest
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ghResponse is a scripted result of one gh command
+type ghResponse struct {
+	output string
+	stderr string // makes the command fail with this on stderr
+}
+
+// fakeExecutor answers gh commands by their arguments
+type fakeExecutor struct {
+	responses map[string]ghResponse
+	commands  []string
+}
+
+func (f *fakeExecutor) executeCommand(dir, name string, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+	response, ok := f.responses[command]
+	if !ok {
+		return nil, errors.New("unexpected command: " + command)
+	}
+	if response.stderr != "" {
+		return []byte(response.output), &exec.ExitError{Stderr: []byte(response.stderr)}
+	}
+	return []byte(response.output), nil
+}
+
+func newFakeClient(responses map[string]ghResponse) (*Client, *fakeExecutor) {
+	executor := &fakeExecutor{responses: responses}
+	return &Client{dir: "/worktree", executor: executor}, executor
+}
+
+const viewArgs = "pr view issue-12 --json number,url,state,baseRefName,headRefName"
+
+func TestClient_Find(t *testing.T) {
+	client, _ := newFakeClient(map[string]ghResponse{
+		viewArgs: {output: `{"number": 7, "url": "https://github.com/o/r/pull/7", "state": "OPEN", "baseRefName": "main", "headRefName": "issue-12"}`},
+		"pr view issue-40 --json number,url,state,baseRefName,headRefName": {stderr: `no pull requests found for branch "issue-40"`},
+	})
+
+	pr, err := client.Find("issue-12")
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequest{Number: 7, URL: "https://github.com/o/r/pull/7", State: "OPEN", BaseRefName: "main", HeadRefName: "issue-12"}, pr)
+
+	pr, err = client.Find("issue-40")
+	require.NoError(t, err)
+	assert.Nil(t, pr)
+}
+
+func TestClient_Create(t *testing.T) {
+	client, executor := newFakeClient(map[string]ghResponse{
+		"pr create --head issue-12 --fill --base release --draft": {output: "https://github.com/o/r/pull/7\n"},
+		viewArgs: {output: `{"number": 7, "url": "https://github.com/o/r/pull/7", "state": "OPEN", "baseRefName": "release"}`},
+	})
+
+	pr, err := client.Create("issue-12", "release", true)
+	require.NoError(t, err)
+	assert.Equal(t, 7, pr.Number)
+	assert.Len(t, executor.commands, 2)
+
+	client, _ = newFakeClient(map[string]ghResponse{
+		"pr create --head issue-12 --fill": {stderr: "pull request create failed: GraphQL: No commits between main and issue-12"},
+	})
+	_, err = client.Create("issue-12", "", false)
+	assert.ErrorContains(t, err, "No commits between main and issue-12")
+}
+
+func TestClient_RequiredChecks(t *testing.T) {
+	client, _ := newFakeClient(map[string]ghResponse{
+		"api repos/{owner}/{repo}/branches/main": {output: `{"name": "main", "protected": true, "protection": {"required_status_checks":
+			{"contexts": ["build", "lint"], "checks": [{"context": "build", "app_id": 15368}, {"context": "e2e", "app_id": null}]}}}`},
+		"api repos/{owner}/{repo}/branches/dev": {output: `{"name": "dev", "protected": false, "protection": {"enabled": false}}`},
+	})
+
+	required, err := client.RequiredChecks("main")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build", "lint", "e2e"}, required)
+
+	required, err = client.RequiredChecks("dev")
+	require.NoError(t, err)
+	assert.Empty(t, required)
+}
+
+func TestClient_Checks(t *testing.T) {
+	checksJSON := `[{"name": "build", "state": "SUCCESS", "bucket": "pass"}, {"name": "lint", "state": "IN_PROGRESS", "bucket": "pending"}]`
+	client, _ := newFakeClient(map[string]ghResponse{
+		// gh exits 8 while checks are pending but still prints them
+		"pr checks 7 --json name,state,bucket,link": {output: checksJSON, stderr: " "},
+		"pr checks 8 --json name,state,bucket,link": {stderr: "no checks reported on the 'issue-40' branch"},
+		"pr checks 9 --json name,state,bucket,link": {stderr: "HTTP 502"},
+	})
+
+	checks, err := client.Checks(7)
+	require.NoError(t, err)
+	assert.Equal(t, []Check{{Name: "build", State: "SUCCESS", Bucket: "pass"}, {Name: "lint", State: "IN_PROGRESS", Bucket: "pending"}}, checks)
+
+	checks, err = client.Checks(8)
+	require.NoError(t, err)
+	assert.Empty(t, checks)
+
+	_, err = client.Checks(9)
+	assert.ErrorContains(t, err, "HTTP 502")
+}
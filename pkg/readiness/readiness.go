@@ -0,0 +1,241 @@
+// Package readiness probes a freshly started session before sbs start
+// declares it ready.
+//
+// A repository lists its probes under readiness_checks in .sbs/config.json.
+// Each probe is retried until it passes or its timeout runs out:
+//
+//	command  a shell command run in the worktree exits 0
+//	port     a TCP port accepts connections
+//	file     a file exists, relative to the worktree
+//
+// All probes run concurrently. While they run, the session's tmux session is
+// watched too, so a command that crashed and took the session with it fails
+// the remaining probes at once instead of after their timeouts.
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sbs/pkg/config"
+)
+
+// Environment variables passed to command probes
+const (
+	EnvWorkItem    = "SBS_WORK_ITEM"
+	EnvTmuxSession = "SBS_TMUX_SESSION"
+	EnvWorktree    = "SBS_WORKTREE"
+)
+
+// DefaultHost is the host of port probes that name none
+const DefaultHost = "127.0.0.1"
+
+// pollInterval is how often failing probes are retried
+const pollInterval = 500 * time.Millisecond
+
+// ErrSessionGone reports that the tmux session exited while probing
+var ErrSessionGone = errors.New("tmux session exited")
+
+// Target is the session being probed
+type Target struct {
+	WorkItem     string
+	TmuxSession  string
+	WorktreePath string
+
+	// SessionAlive reports whether the tmux session still exists; nil skips
+	// watching it
+	SessionAlive func() (bool, error)
+}
+
+// Result is the outcome of one probe
+type Result struct {
+	Check   config.ReadinessCheck
+	Ready   bool
+	Elapsed time.Duration
+	Err     error // Last failure when not ready
+}
+
+// String describes the result for sbs start output
+func (r Result) String() string {
+	if r.Ready {
+		return fmt.Sprintf("%s: ready after %s", Describe(r.Check), r.Elapsed.Round(100*time.Millisecond))
+	}
+	return fmt.Sprintf("%s: not ready after %s: %v", Describe(r.Check), r.Elapsed.Round(100*time.Millisecond), r.Err)
+}
+
+// Report is the outcome of all probes of a session
+type Report struct {
+	Results []Result
+}
+
+// Ready reports whether every probe passed
+func (r Report) Ready() bool {
+	for _, result := range r.Results {
+		if !result.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the probes that did not pass
+func (r Report) Failed() []Result {
+	var failed []Result
+	for _, result := range r.Results {
+		if !result.Ready {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Describe names a probe: its configured name, or what it checks
+func Describe(check config.ReadinessCheck) string {
+	switch {
+	case check.Name != "":
+		return check.Name
+	case check.Command != "":
+		return fmt.Sprintf("command %q", check.Command)
+	case check.Port != 0:
+		return "port " + address(check)
+	default:
+		return "file " + check.File
+	}
+}
+
+// Timeout returns how long a probe keeps retrying
+func Timeout(check config.ReadinessCheck) time.Duration {
+	if check.TimeoutSecs > 0 {
+		return time.Duration(check.TimeoutSecs) * time.Second
+	}
+	return config.DefaultReadinessTimeoutSecs * time.Second
+}
+
+// Run probes the target with every check and waits for all of them
+func Run(ctx context.Context, target Target, checks []config.ReadinessCheck) Report {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	if target.SessionAlive != nil {
+		go watchSession(ctx, target.SessionAlive, cancel)
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check config.ReadinessCheck) {
+			defer wg.Done()
+			results[i] = probe(ctx, target, check)
+		}(i, check)
+	}
+	wg.Wait()
+	return Report{Results: results}
+}
+
+// watchSession cancels probing with ErrSessionGone once the session exits.
+// Failures to check are ignored; the probes' own timeouts still apply.
+func watchSession(ctx context.Context, alive func() (bool, error), cancel context.CancelCauseFunc) {
+	for {
+		if exists, err := alive(); err == nil && !exists {
+			cancel(ErrSessionGone)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// probe retries one check until it passes, times out or probing is cancelled
+func probe(ctx context.Context, target Target, check config.ReadinessCheck) Result {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, Timeout(check))
+	defer cancel()
+
+	for {
+		err := attempt(ctx, target, check)
+		if err == nil {
+			return Result{Check: check, Ready: true, Elapsed: time.Since(start)}
+		}
+
+		select {
+		case <-ctx.Done():
+			if cause := context.Cause(ctx); errors.Is(cause, ErrSessionGone) {
+				err = cause
+			}
+			return Result{Check: check, Elapsed: time.Since(start), Err: err}
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// attempt runs a check once
+func attempt(ctx context.Context, target Target, check config.ReadinessCheck) error {
+	switch {
+	case check.Command != "":
+		return runCommand(ctx, target, check.Command)
+	case check.Port != 0:
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address(check))
+		if err != nil {
+			return fmt.Errorf("port %s not accepting connections", address(check))
+		}
+		return conn.Close()
+	default:
+		path := check.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(target.WorktreePath, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%s does not exist", path)
+		}
+		return nil
+	}
+}
+
+// runCommand runs a command probe in the worktree
+func runCommand(ctx context.Context, target Target, command string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = target.WorktreePath
+	cmd.Env = append(os.Environ(),
+		EnvWorkItem+"="+target.WorkItem,
+		EnvTmuxSession+"="+target.TmuxSession,
+		EnvWorktree+"="+target.WorktreePath,
+	)
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if detail := lastLine(string(output)); detail != "" {
+		return fmt.Errorf("%w: %s", err, detail)
+	}
+	return err
+}
+
+// address returns the host:port of a port probe
+func address(check config.ReadinessCheck) string {
+	host := check.Host
+	if host == "" {
+		host = DefaultHost
+	}
+	return net.JoinHostPort(host, strconv.Itoa(check.Port))
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,81 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestRun_AllProbesPass(t *testing.T) {
+	worktree := t.TempDir()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	// The file appears after the first attempt
+	go func() {
+		time.Sleep(pollInterval / 2)
+		os.WriteFile(filepath.Join(worktree, "ready"), nil, 0644)
+	}()
+
+	report := Run(context.Background(), Target{WorkItem: "test:1", WorktreePath: worktree}, []config.ReadinessCheck{
+		{Command: `test "$SBS_WORK_ITEM" = test:1 && test "$PWD" = "$SBS_WORKTREE"`},
+		{Port: port},
+		{File: "ready", TimeoutSecs: 5},
+	})
+
+	require.Len(t, report.Results, 3)
+	assert.True(t, report.Ready(), "%v", report.Results)
+	assert.Empty(t, report.Failed())
+}
+
+func TestRun_ReportsFailuresAfterTimeout(t *testing.T) {
+	check := config.ReadinessCheck{Name: "health", Command: "echo connection refused; exit 7", TimeoutSecs: 1}
+	report := Run(context.Background(), Target{WorktreePath: t.TempDir()}, []config.ReadinessCheck{check})
+
+	require.False(t, report.Ready())
+	failed := report.Failed()
+	require.Len(t, failed, 1)
+	assert.GreaterOrEqual(t, failed[0].Elapsed, time.Second)
+	assert.Contains(t, failed[0].Err.Error(), "connection refused")
+	assert.Contains(t, failed[0].String(), "health: not ready after")
+}
+
+func TestRun_StopsWhenSessionExits(t *testing.T) {
+	var calls atomic.Int32
+	alive := func() (bool, error) {
+		return calls.Add(1) < 2, nil
+	}
+
+	start := time.Now()
+	report := Run(context.Background(), Target{WorktreePath: t.TempDir(), SessionAlive: alive},
+		[]config.ReadinessCheck{{File: "never", TimeoutSecs: 30}})
+
+	assert.Less(t, time.Since(start), 10*time.Second, "probing stops once the session is gone")
+	require.False(t, report.Ready())
+	assert.True(t, errors.Is(report.Results[0].Err, ErrSessionGone))
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "db", Describe(config.ReadinessCheck{Name: "db", Port: 5432}))
+	assert.Equal(t, `command "make ready"`, Describe(config.ReadinessCheck{Command: "make ready"}))
+	assert.Equal(t, "port 127.0.0.1:3000", Describe(config.ReadinessCheck{Port: 3000}))
+	assert.Equal(t, "port db:5432", Describe(config.ReadinessCheck{Port: 5432, Host: "db"}))
+	assert.Equal(t, "file tmp/ready", Describe(config.ReadinessCheck{File: "tmp/ready"}))
+}
+
+func TestTimeout(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Timeout(config.ReadinessCheck{}))
+	assert.Equal(t, 5*time.Second, Timeout(config.ReadinessCheck{TimeoutSecs: 5}))
+}
@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runGit runs a git command in dir, failing the test on error
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+	)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, output)
+}
+
+// newTestRepo creates a repository with one commit
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	root := filepath.Join(dir, "main")
+	runGit(t, dir, "init", "-q", "main")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "initial")
+	return root
+}
+
+func TestManager_DetectRepository(t *testing.T) {
+	manager := NewManager()
+
+	t.Run("main_checkout", func(t *testing.T) {
+		root := newTestRepo(t)
+
+		repository, err := manager.DetectRepository(root)
+		require.NoError(t, err)
+		assert.Equal(t, root, repository.Root)
+		assert.Equal(t, "main", repository.Name)
+		assert.Empty(t, repository.WorktreeRoot)
+	})
+
+	t.Run("linked_worktree_resolves_parent_repository", func(t *testing.T) {
+		root := newTestRepo(t)
+		worktree := filepath.Join(filepath.Dir(root), "issue-1")
+		runGit(t, root, "worktree", "add", "-q", "-b", "issue/1", worktree)
+
+		repository, err := manager.DetectRepository(worktree)
+		require.NoError(t, err)
+		assert.Equal(t, root, repository.Root)
+		assert.Equal(t, "main", repository.Name)
+		assert.Equal(t, worktree, repository.WorktreeRoot)
+	})
+
+	t.Run("not_a_repository", func(t *testing.T) {
+		_, err := manager.DetectRepository(t.TempDir())
+		assert.Error(t, err)
+	})
+}
+
+func TestResolveRoots(t *testing.T) {
+	tests := []struct {
+		name             string
+		topLevel         string
+		commonDir        string
+		expectedRoot     string
+		expectedWorktree string
+	}{
+		{
+			name:         "main_checkout",
+			topLevel:     "/src/project",
+			commonDir:    "/src/project/.git",
+			expectedRoot: "/src/project",
+		},
+		{
+			name:             "linked_worktree",
+			topLevel:         "/home/user/.sbs-worktrees/project/issue-7",
+			commonDir:        "/src/project/.git",
+			expectedRoot:     "/src/project",
+			expectedWorktree: "/home/user/.sbs-worktrees/project/issue-7",
+		},
+		{
+			name:         "submodule_is_its_own_repository",
+			topLevel:     "/src/project/vendor/lib",
+			commonDir:    "/src/project/.git/modules/vendor/lib",
+			expectedRoot: "/src/project/vendor/lib",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, worktree, err := resolveRoots(tt.topLevel, tt.commonDir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRoot, root)
+			assert.Equal(t, tt.expectedWorktree, worktree)
+		})
+	}
+}
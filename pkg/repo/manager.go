@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -15,12 +16,17 @@ import (
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 	"sbs/pkg/cmdlog"
+	sbsgit "sbs/pkg/git"
 )
 
 type Repository struct {
 	Name   string // Short name like "myproject"
 	Root   string // Full path to repository root
 	Remote string // Git remote URL if available
+
+	// WorktreeRoot is the linked worktree containing the working directory
+	// (e.g. an sbs session worktree). Empty when in the main checkout.
+	WorktreeRoot string
 }
 
 type Manager struct{}
@@ -31,8 +37,19 @@ func NewManager() *Manager {
 
 // DetectCurrentRepository detects the current git repository context
 func (m *Manager) DetectCurrentRepository() (*Repository, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	return m.DetectRepository(currentDir)
+}
+
+// DetectRepository detects the git repository containing dir. Inside a linked
+// worktree the main repository is returned, with WorktreeRoot set to the
+// worktree, so commands act on the repository that owns the session.
+func (m *Manager) DetectRepository(dir string) (*Repository, error) {
 	// Find git repository root
-	repoRoot, err := m.findGitRoot()
+	repoRoot, worktreeRoot, err := m.findGitRoot(dir)
 	if err != nil {
 		return nil, fmt.Errorf("not in a git repository: %w", err)
 	}
@@ -44,9 +61,10 @@ func (m *Manager) DetectCurrentRepository() (*Repository, error) {
 	remoteURL := m.getRemoteURL(repoRoot)
 
 	return &Repository{
-		Name:   repoName,
-		Root:   repoRoot,
-		Remote: remoteURL,
+		Name:         repoName,
+		Root:         repoRoot,
+		Remote:       remoteURL,
+		WorktreeRoot: worktreeRoot,
 	}, nil
 }
 
@@ -66,15 +84,57 @@ func (r *Repository) GetWorktreePath(issueNumber int) string {
 	return filepath.Join(homeDir, ".sbs-worktrees", r.Name, fmt.Sprintf("issue-%d", issueNumber))
 }
 
-// findGitRoot finds the root directory of the current git repository
-func (m *Manager) findGitRoot() (string, error) {
-	currentDir, err := os.Getwd()
+// findGitRoot finds the root of the repository containing dir. When dir is in
+// a linked worktree, the main repository root is returned along with the
+// worktree's own root.
+func (m *Manager) findGitRoot(dir string) (string, string, error) {
+	output, err := m.runGitCommand(dir, []string{"rev-parse", "--path-format=absolute", "--show-toplevel", "--git-common-dir"})
 	if err != nil {
-		return "", err
+		// Older git without --path-format, or git unavailable
+		root, openErr := m.openGitRoot(dir)
+		return root, "", openErr
 	}
 
-	// Use go-git to find repository root
-	repo, err := git.PlainOpenWithOptions(currentDir, &git.PlainOpenOptions{
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 2 {
+		return "", "", fmt.Errorf("unexpected git rev-parse output: %q", string(output))
+	}
+
+	return resolveRoots(lines[0], lines[1])
+}
+
+// resolveRoots maps the working tree top level and the git common directory
+// reported by git to the main repository root and, for linked worktrees, the
+// worktree root. Submodules keep their common directory under the
+// superproject's .git/modules, so they resolve to their own top level.
+func resolveRoots(topLevel, commonDir string) (string, string, error) {
+	topLevel = canonicalPath(topLevel)
+	commonDir = canonicalPath(commonDir)
+
+	if filepath.Base(commonDir) != ".git" {
+		return topLevel, "", nil
+	}
+
+	mainRoot := filepath.Dir(commonDir)
+	if mainRoot == topLevel {
+		return topLevel, "", nil
+	}
+
+	return mainRoot, topLevel, nil
+}
+
+// canonicalPath cleans a path and resolves symlinks where possible so paths
+// reported by git compare equal to paths stored in session metadata
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// openGitRoot finds the repository root with go-git
+func (m *Manager) openGitRoot(dir string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
 		DetectDotGit: true,
 	})
 	if err != nil {
@@ -214,8 +274,7 @@ func (m *Manager) normalizeUnicode(input string) string {
 func (m *Manager) runGitCommand(dir string, args []string) ([]byte, error) {
 	ctx := cmdlog.LogCommandGlobal("git", args, cmdlog.GetCaller())
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
+	cmd := sbsgit.Command(context.Background(), dir, args...)
 	start := time.Now()
 	output, err := cmd.Output()
 	duration := time.Since(start)
@@ -0,0 +1,285 @@
+// Package report aggregates session activity over a period into a summary
+// suitable for weekly updates.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sbs/pkg/config"
+)
+
+// Formats accepted by Render
+const (
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+)
+
+// WorkItem is one session touched during the report period
+type WorkItem struct {
+	ID         string
+	Title      string
+	Repository string
+	Branch     string
+	Created    bool          // session was started during the period
+	Merged     bool          // branch is merged into the repository's base branch
+	Cleaned    bool          // session was removed by sbs clean during the period
+	ActiveTime time.Duration // span between creation and last activity within the period
+}
+
+// Cleanup is a session removed by sbs clean during the period
+type Cleanup struct {
+	ID    string
+	Title string
+	At    time.Time
+}
+
+// Report summarizes session activity between Since and Until
+type Report struct {
+	Since     time.Time
+	Until     time.Time
+	WorkItems []WorkItem // most active time first
+	Cleanups  []Cleanup  // oldest first
+}
+
+// BranchesCreated counts work items whose session started in the period
+func (r Report) BranchesCreated() int {
+	count := 0
+	for _, item := range r.WorkItems {
+		if item.Created && item.Branch != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// BranchesMerged counts work items whose branch is merged
+func (r Report) BranchesMerged() int {
+	count := 0
+	for _, item := range r.WorkItems {
+		if item.Merged {
+			count++
+		}
+	}
+	return count
+}
+
+// TotalActiveTime sums the active time of all work items
+func (r Report) TotalActiveTime() time.Duration {
+	var total time.Duration
+	for _, item := range r.WorkItems {
+		total += item.ActiveTime
+	}
+	return total
+}
+
+// Build aggregates sessions and archived sessions into a report for the
+// period [since, until]. merged reports whether a session's branch has been
+// merged; it may be nil.
+func Build(sessions []config.SessionMetadata, archived []config.ArchivedSession, since, until time.Time, merged func(config.SessionMetadata) bool) Report {
+	r := Report{Since: since, Until: until}
+	inPeriod := func(t time.Time) bool {
+		return !t.IsZero() && !t.Before(since) && !t.After(until)
+	}
+
+	items := make(map[string]*WorkItem)
+	var order []string
+	add := func(session config.SessionMetadata) *WorkItem {
+		created := parseTime(session.CreatedAt)
+		lastActivity := parseTime(session.LastActivity)
+		if !inPeriod(created) && !inPeriod(lastActivity) {
+			return nil
+		}
+		if item, ok := items[session.NamespacedID]; ok {
+			return item
+		}
+		item := &WorkItem{
+			ID:         session.NamespacedID,
+			Title:      session.IssueTitle,
+			Repository: session.RepositoryName,
+			Branch:     session.Branch,
+			Created:    inPeriod(created),
+			ActiveTime: overlap(created, lastActivity, since, until),
+		}
+		if merged != nil && session.Branch != "" {
+			item.Merged = merged(session)
+		}
+		items[session.NamespacedID] = item
+		order = append(order, session.NamespacedID)
+		return item
+	}
+
+	for _, session := range sessions {
+		add(session)
+	}
+	for _, entry := range archived {
+		if entry.Reason != config.ArchiveReasonCleaned {
+			continue
+		}
+		at := parseTime(entry.ArchivedAt)
+		if !inPeriod(at) {
+			continue
+		}
+		r.Cleanups = append(r.Cleanups, Cleanup{ID: entry.Session.NamespacedID, Title: entry.Session.IssueTitle, At: at})
+		if item := add(entry.Session); item != nil {
+			item.Cleaned = true
+		}
+	}
+
+	for _, id := range order {
+		r.WorkItems = append(r.WorkItems, *items[id])
+	}
+	sort.SliceStable(r.WorkItems, func(i, j int) bool {
+		return r.WorkItems[i].ActiveTime > r.WorkItems[j].ActiveTime
+	})
+	sort.SliceStable(r.Cleanups, func(i, j int) bool {
+		return r.Cleanups[i].At.Before(r.Cleanups[j].At)
+	})
+	return r
+}
+
+// overlap returns how much of [start, end] falls within [since, until]
+func overlap(start, end, since, until time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	if start.Before(since) {
+		start = since
+	}
+	if end.After(until) {
+		end = until
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+func parseTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ParseSince parses a --since value relative to now: a number of days or
+// weeks ("7d", "2w"), a Go duration ("36h") or a date ("2025-08-01")
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty --since value")
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return time.Time{}, fmt.Errorf("invalid --since value %q", value)
+			}
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since value %q (use e.g. 7d, 2w, 36h or 2025-08-01)", value)
+	}
+	return now.Add(-d), nil
+}
+
+// Render renders the report in the given format
+func Render(r Report, format string) (string, error) {
+	switch format {
+	case FormatMarkdown, "md", "":
+		return r.Markdown(), nil
+	case FormatText:
+		return r.Text(), nil
+	default:
+		return "", fmt.Errorf("unknown report format %q (valid: %s, %s)", format, FormatMarkdown, FormatText)
+	}
+}
+
+// Markdown renders the report as a Markdown document
+func (r Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Activity report: %s – %s\n\n", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02"))
+
+	b.WriteString("## Overview\n\n")
+	fmt.Fprintf(&b, "- Sessions touched: %d\n", len(r.WorkItems))
+	fmt.Fprintf(&b, "- Branches created: %d\n", r.BranchesCreated())
+	fmt.Fprintf(&b, "- Branches merged: %d\n", r.BranchesMerged())
+	fmt.Fprintf(&b, "- Active time: %s\n", FormatDuration(r.TotalActiveTime()))
+	fmt.Fprintf(&b, "- Cleanups: %d\n", len(r.Cleanups))
+
+	if len(r.WorkItems) > 0 {
+		b.WriteString("\n## Work items\n\n")
+		b.WriteString("| Work item | Title | Repository | Branch | Active | Status |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, item := range r.WorkItems {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(item.ID), markdownCell(item.Title), markdownCell(item.Repository),
+				markdownCell(item.Branch), FormatDuration(item.ActiveTime), item.state())
+		}
+	}
+
+	if len(r.Cleanups) > 0 {
+		b.WriteString("\n## Cleanups\n\n")
+		for _, cleanup := range r.Cleanups {
+			fmt.Fprintf(&b, "- %s %s: %s\n", cleanup.At.Format("2006-01-02"), cleanup.ID, cleanup.Title)
+		}
+	}
+	return b.String()
+}
+
+// Text renders the report as plain text
+func (r Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Activity %s to %s\n", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02"))
+	fmt.Fprintf(&b, "%d session(s) touched, %d branch(es) created, %d merged, %s active, %d cleanup(s)\n",
+		len(r.WorkItems), r.BranchesCreated(), r.BranchesMerged(), FormatDuration(r.TotalActiveTime()), len(r.Cleanups))
+	for _, item := range r.WorkItems {
+		fmt.Fprintf(&b, "  %-20s %-8s %-14s %s\n", item.ID, FormatDuration(item.ActiveTime), item.state(), item.Title)
+	}
+	return b.String()
+}
+
+// state describes a work item for the status column
+func (item WorkItem) state() string {
+	var parts []string
+	if item.Created {
+		parts = append(parts, "new")
+	}
+	if item.Merged {
+		parts = append(parts, "merged")
+	}
+	if item.Cleaned {
+		parts = append(parts, "cleaned")
+	}
+	if len(parts) == 0 {
+		return "in progress"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDuration renders d as hours and minutes, e.g. "3h 05m"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", hours, minutes)
+}
+
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{value: "7d", expected: now.AddDate(0, 0, -7)},
+		{value: "2w", expected: now.AddDate(0, 0, -14)},
+		{value: "36h", expected: now.Add(-36 * time.Hour)},
+		{value: "2025-08-01", expected: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "", expectError: true},
+		{value: "xd", expectError: true},
+		{value: "-3h", expectError: true},
+		{value: "soon", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			since, err := ParseSince(tt.value, now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, since)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	until := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+	since := until.AddDate(0, 0, -7)
+	at := func(d time.Duration) string { return until.Add(-d).Format(time.RFC3339) }
+
+	sessions := []config.SessionMetadata{
+		// Started before the period, active within it
+		{NamespacedID: "github:1", IssueTitle: "Old feature", Branch: "issue-1", CreatedAt: at(10 * 24 * time.Hour), LastActivity: at(6 * 24 * time.Hour)},
+		// Started and merged within the period
+		{NamespacedID: "github:2", IssueTitle: "Fix | pipe", Branch: "issue-2", CreatedAt: at(3 * time.Hour), LastActivity: at(time.Hour)},
+		// Untouched during the period
+		{NamespacedID: "github:3", IssueTitle: "Ancient", Branch: "issue-3", CreatedAt: at(30 * 24 * time.Hour), LastActivity: at(20 * 24 * time.Hour)},
+	}
+	archived := []config.ArchivedSession{
+		{ArchivedAt: at(2 * time.Hour), Reason: config.ArchiveReasonCleaned, Session: config.SessionMetadata{NamespacedID: "github:4", IssueTitle: "Done", CreatedAt: at(5 * time.Hour), LastActivity: at(4 * time.Hour)}},
+		{ArchivedAt: at(2 * time.Hour), Reason: config.ArchiveReasonDuplicate, Session: sessions[2]},
+		{ArchivedAt: at(40 * 24 * time.Hour), Reason: config.ArchiveReasonCleaned, Session: sessions[2]},
+	}
+	merged := func(session config.SessionMetadata) bool { return session.Branch == "issue-2" }
+
+	r := Build(sessions, archived, since, until, merged)
+
+	require.Len(t, r.WorkItems, 3)
+	assert.Equal(t, "github:1", r.WorkItems[0].ID, "most active first")
+	assert.Equal(t, 24*time.Hour, r.WorkItems[0].ActiveTime, "time before the period is not counted")
+	assert.False(t, r.WorkItems[0].Created)
+	assert.Equal(t, "github:2", r.WorkItems[1].ID)
+	assert.True(t, r.WorkItems[1].Created)
+	assert.True(t, r.WorkItems[1].Merged)
+	assert.True(t, r.WorkItems[2].Cleaned)
+
+	assert.Equal(t, 1, r.BranchesCreated(), "github:4 was created in the period but has no branch recorded")
+	assert.Equal(t, 1, r.BranchesMerged())
+	assert.Equal(t, 27*time.Hour, r.TotalActiveTime())
+	require.Len(t, r.Cleanups, 1)
+	assert.Equal(t, "github:4", r.Cleanups[0].ID)
+
+	markdown := r.Markdown()
+	assert.Contains(t, markdown, "# Activity report: 2025-08-01 – 2025-08-08")
+	assert.Contains(t, markdown, "- Sessions touched: 3")
+	assert.Contains(t, markdown, "- Active time: 27h 00m")
+	assert.Contains(t, markdown, "| github:2 | Fix \\| pipe |  | issue-2 | 2h 00m | new, merged |")
+	assert.Contains(t, markdown, "- 2025-08-08 github:4: Done")
+
+	text, err := Render(r, FormatText)
+	require.NoError(t, err)
+	assert.Contains(t, text, "3 session(s) touched, 1 branch(es) created")
+
+	_, err = Render(r, "html")
+	assert.Error(t, err)
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "0m", FormatDuration(0))
+	assert.Equal(t, "45m", FormatDuration(45*time.Minute))
+	assert.Equal(t, "3h 05m", FormatDuration(3*time.Hour+5*time.Minute))
+}
@@ -0,0 +1,302 @@
+// Package resmon measures the CPU, memory and IO of process trees by reading
+// /proc, so sbs top can show which session is using the machine.
+//
+// A Monitor takes successive snapshots of the process table. Memory is read
+// from each snapshot directly; CPU and IO are rates, computed from the change
+// since the previous snapshot, and are zero in the first one.
+package resmon
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicksPerSecond is USER_HZ, the unit of CPU times in /proc/<pid>/stat.
+// It is 100 on every Linux architecture sbs runs on.
+const clockTicksPerSecond = 100
+
+// Process is one process read from /proc
+type Process struct {
+	PID        int
+	PPID       int
+	Name       string
+	Args       []string
+	StartTime  uint64 // Clock ticks after boot; tells a reused PID apart
+	CPUTicks   uint64 // User plus system time
+	RSSBytes   uint64
+	ReadBytes  uint64 // Zero when /proc/<pid>/io is not readable
+	WriteBytes uint64
+}
+
+// Usage is the combined resource use of a set of processes
+type Usage struct {
+	Processes   int
+	CPUPercent  float64 // 100 is one fully used core
+	MemoryBytes uint64
+	ReadRate    float64 // Bytes per second
+	WriteRate   float64
+}
+
+// Monitor takes snapshots of the process table
+type Monitor struct {
+	procRoot string
+	pageSize uint64
+	previous map[int]Process
+	taken    time.Time
+	now      func() time.Time
+}
+
+// NewMonitor creates a monitor reading /proc
+func NewMonitor() *Monitor {
+	return NewMonitorWithRoot("/proc")
+}
+
+// NewMonitorWithRoot creates a monitor reading a proc filesystem mounted at procRoot
+func NewMonitorWithRoot(procRoot string) *Monitor {
+	return &Monitor{
+		procRoot: procRoot,
+		pageSize: uint64(os.Getpagesize()),
+		now:      time.Now,
+	}
+}
+
+// Snapshot reads every process and returns a snapshot whose rates cover the
+// time since the previous call
+func (m *Monitor) Snapshot() (*Snapshot, error) {
+	processes, err := m.readProcesses()
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	snapshot := &Snapshot{processes: processes, previous: m.previous}
+	if !m.taken.IsZero() {
+		snapshot.elapsed = now.Sub(m.taken)
+	}
+	m.previous = processes
+	m.taken = now
+	return snapshot, nil
+}
+
+// readProcesses reads all numeric entries of the proc root. Processes that
+// exit while being read are skipped.
+func (m *Monitor) readProcesses() (map[int]Process, error) {
+	entries, err := os.ReadDir(m.procRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.procRoot, err)
+	}
+	processes := make(map[int]Process, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		process, err := m.readProcess(pid)
+		if err != nil {
+			continue
+		}
+		processes[pid] = process
+	}
+	return processes, nil
+}
+
+// readProcess reads one process's stat, cmdline and io files
+func (m *Monitor) readProcess(pid int) (Process, error) {
+	dir := filepath.Join(m.procRoot, strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return Process{}, err
+	}
+	process, err := parseStat(string(stat), m.pageSize)
+	if err != nil {
+		return Process{}, fmt.Errorf("failed to parse %s/stat: %w", dir, err)
+	}
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		process.Args = parseCmdline(cmdline)
+	}
+	if io, err := os.ReadFile(filepath.Join(dir, "io")); err == nil {
+		process.ReadBytes, process.WriteBytes = parseIO(string(io))
+	}
+	return process, nil
+}
+
+// parseStat parses /proc/<pid>/stat. The command name is in parentheses and
+// may itself contain spaces and parentheses, so fields are counted from the
+// last closing parenthesis.
+func parseStat(stat string, pageSize uint64) (Process, error) {
+	open := strings.IndexByte(stat, '(')
+	closing := strings.LastIndexByte(stat, ')')
+	if open < 0 || closing < open {
+		return Process{}, fmt.Errorf("malformed stat line")
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(stat[:open]))
+	if err != nil {
+		return Process{}, fmt.Errorf("invalid pid: %w", err)
+	}
+
+	// fields[0] is field 3 (state) in proc(5) numbering
+	fields := strings.Fields(stat[closing+1:])
+	if len(fields) < 22 {
+		return Process{}, fmt.Errorf("stat line has %d fields after the name", len(fields))
+	}
+	field := func(n int) uint64 {
+		value, _ := strconv.ParseUint(fields[n-3], 10, 64)
+		return value
+	}
+
+	return Process{
+		PID:       pid,
+		PPID:      int(field(4)),
+		Name:      stat[open+1 : closing],
+		CPUTicks:  field(14) + field(15),
+		StartTime: field(22),
+		RSSBytes:  field(24) * pageSize,
+	}, nil
+}
+
+// parseCmdline splits a NUL-separated argument list
+func parseCmdline(cmdline []byte) []string {
+	cmdline = bytes.TrimRight(cmdline, "\x00")
+	if len(cmdline) == 0 {
+		return nil
+	}
+	return strings.Split(string(cmdline), "\x00")
+}
+
+// parseIO reads the bytes a process caused to be read from and written to storage
+func parseIO(io string) (read, write uint64) {
+	scanner := bufio.NewScanner(strings.NewReader(io))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		n, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "read_bytes":
+			read = n
+		case "write_bytes":
+			write = n
+		}
+	}
+	return read, write
+}
+
+// Snapshot is the process table at one point in time
+type Snapshot struct {
+	processes map[int]Process
+	previous  map[int]Process
+	elapsed   time.Duration
+}
+
+// Process returns a process from the snapshot
+func (s *Snapshot) Process(pid int) (Process, bool) {
+	process, exists := s.processes[pid]
+	return process, exists
+}
+
+// Tree returns the roots that are running and all of their descendants
+func (s *Snapshot) Tree(roots []int) []int {
+	children := make(map[int][]int)
+	for pid, process := range s.processes {
+		children[process.PPID] = append(children[process.PPID], pid)
+	}
+
+	seen := make(map[int]bool)
+	var pids []int
+	queue := append([]int(nil), roots...)
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue
+		}
+		if _, exists := s.processes[pid]; !exists {
+			continue
+		}
+		seen[pid] = true
+		pids = append(pids, pid)
+		queue = append(queue, children[pid]...)
+	}
+	return pids
+}
+
+// WithArgs returns the processes whose arguments contain want as a
+// consecutive sequence, e.g. "--name", "sbs-repo-123" for a sandbox
+func (s *Snapshot) WithArgs(want ...string) []int {
+	if len(want) == 0 {
+		return nil
+	}
+	var pids []int
+	for pid, process := range s.processes {
+		if containsSequence(process.Args, want) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// containsSequence reports whether want occurs in args as consecutive elements
+func containsSequence(args, want []string) bool {
+	for i := 0; i+len(want) <= len(args); i++ {
+		match := true
+		for j := range want {
+			if args[i+j] != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage sums the resource use of a set of processes. PIDs are counted once
+// even when listed twice, and PIDs that are not running are ignored.
+func (s *Snapshot) Usage(pids []int) Usage {
+	var usage Usage
+	var cpuTicks, readBytes, writeBytes uint64
+	seen := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		process, exists := s.processes[pid]
+		if !exists || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		usage.Processes++
+		usage.MemoryBytes += process.RSSBytes
+
+		// A process absent from the previous snapshot started since, so all
+		// of its counters were accumulated in the interval
+		var before Process
+		if previous, existed := s.previous[pid]; existed && previous.StartTime == process.StartTime {
+			before = previous
+		}
+		cpuTicks += delta(process.CPUTicks, before.CPUTicks)
+		readBytes += delta(process.ReadBytes, before.ReadBytes)
+		writeBytes += delta(process.WriteBytes, before.WriteBytes)
+	}
+
+	if seconds := s.elapsed.Seconds(); seconds > 0 {
+		usage.CPUPercent = float64(cpuTicks) / clockTicksPerSecond / seconds * 100
+		usage.ReadRate = float64(readBytes) / seconds
+		usage.WriteRate = float64(writeBytes) / seconds
+	}
+	return usage
+}
+
+// delta returns after-before, or 0 if the counter went backwards
+func delta(after, before uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
+}
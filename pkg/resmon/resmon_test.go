@@ -0,0 +1,133 @@
+package resmon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProcess writes a fake /proc/<pid> directory
+func writeProcess(t *testing.T, root string, pid, ppid int, name string, ticks, start, rssPages, readBytes, writeBytes uint64, args ...string) {
+	t.Helper()
+	dir := filepath.Join(root, strconv.Itoa(pid))
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	// Fields 3-24 of proc(5); utime is field 14, stime 15, starttime 22, rss 24
+	fields := make([]string, 22)
+	for i := range fields {
+		fields[i] = "0"
+	}
+	fields[0] = "S"
+	fields[4-3] = strconv.Itoa(ppid)
+	fields[14-3] = strconv.FormatUint(ticks, 10)
+	fields[22-3] = strconv.FormatUint(start, 10)
+	fields[24-3] = strconv.FormatUint(rssPages, 10)
+	stat := fmt.Sprintf("%d (%s) %s\n", pid, name, strings.Join(fields, " "))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+
+	cmdline := strings.Join(args, "\x00") + "\x00"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644))
+
+	io := fmt.Sprintf("rchar: 1\nwchar: 2\nread_bytes: %d\nwrite_bytes: %d\n", readBytes, writeBytes)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "io"), []byte(io), 0644))
+}
+
+func newTestMonitor(root string, clock *time.Time) *Monitor {
+	m := NewMonitorWithRoot(root)
+	m.pageSize = 4096
+	m.now = func() time.Time { return *clock }
+	return m
+}
+
+func TestParseStat_NameWithSpacesAndParens(t *testing.T) {
+	fields := make([]string, 22)
+	for i := range fields {
+		fields[i] = "0"
+	}
+	fields[1] = "7"   // ppid
+	fields[11] = "30" // utime
+	fields[12] = "12" // stime
+	fields[19] = "555"
+	fields[21] = "10"
+	stat := "42 (tmux: server (1)) " + strings.Join(fields, " ")
+
+	process, err := parseStat(stat, 4096)
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, process.PID)
+	assert.Equal(t, 7, process.PPID)
+	assert.Equal(t, "tmux: server (1)", process.Name)
+	assert.Equal(t, uint64(42), process.CPUTicks)
+	assert.Equal(t, uint64(555), process.StartTime)
+	assert.Equal(t, uint64(40960), process.RSSBytes)
+}
+
+func TestParseStat_Malformed(t *testing.T) {
+	_, err := parseStat("42 bash S 1", 4096)
+	assert.Error(t, err)
+
+	_, err = parseStat("42 (bash) S 1 2", 4096)
+	assert.Error(t, err)
+}
+
+func TestSnapshot_TreeAndWithArgs(t *testing.T) {
+	root := t.TempDir()
+	writeProcess(t, root, 1, 0, "init", 0, 1, 1, 0, 0, "/sbin/init")
+	writeProcess(t, root, 100, 1, "bash", 0, 2, 1, 0, 0, "bash")
+	writeProcess(t, root, 101, 100, "claude", 0, 3, 1, 0, 0, "claude")
+	writeProcess(t, root, 102, 101, "node", 0, 4, 1, 0, 0, "node")
+	writeProcess(t, root, 200, 1, "bash", 0, 5, 1, 0, 0, "bash")
+	writeProcess(t, root, 300, 1, "sandbox", 0, 6, 1, 0, 0, "sandbox", "--name", "sbs-repo-1", "claude")
+	clock := time.Unix(1000, 0)
+
+	snapshot, err := newTestMonitor(root, &clock).Snapshot()
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []int{100, 101, 102}, snapshot.Tree([]int{100, 999}))
+	assert.Equal(t, []int{300}, snapshot.WithArgs("--name", "sbs-repo-1"))
+	assert.Empty(t, snapshot.WithArgs("--name", "sbs-repo-2"))
+	assert.Empty(t, snapshot.WithArgs())
+}
+
+func TestSnapshot_UsageRates(t *testing.T) {
+	root := t.TempDir()
+	writeProcess(t, root, 100, 1, "bash", 100, 2, 10, 1000, 2000, "bash")
+	writeProcess(t, root, 101, 100, "make", 50, 3, 20, 0, 0, "make")
+	clock := time.Unix(1000, 0)
+	monitor := newTestMonitor(root, &clock)
+
+	first, err := monitor.Snapshot()
+	require.NoError(t, err)
+	usage := first.Usage([]int{100, 101, 101})
+	assert.Equal(t, 2, usage.Processes)
+	assert.Equal(t, uint64(30*4096), usage.MemoryBytes)
+	assert.Zero(t, usage.CPUPercent, "the first snapshot has no rates")
+
+	// Two seconds later: bash used 1 second of CPU, make exited and a new
+	// process with a reused PID started and used 0.5 seconds
+	writeProcess(t, root, 100, 1, "bash", 200, 2, 10, 3000, 6000, "bash")
+	writeProcess(t, root, 101, 100, "cc", 50, 99, 5, 0, 0, "cc")
+	clock = clock.Add(2 * time.Second)
+
+	second, err := monitor.Snapshot()
+	require.NoError(t, err)
+	usage = second.Usage([]int{100, 101})
+	assert.Equal(t, 2, usage.Processes)
+	assert.Equal(t, uint64(15*4096), usage.MemoryBytes)
+	assert.InDelta(t, 75.0, usage.CPUPercent, 0.001)
+	assert.InDelta(t, 1000.0, usage.ReadRate, 0.001)
+	assert.InDelta(t, 2000.0, usage.WriteRate, 0.001)
+}
+
+func TestParseIO(t *testing.T) {
+	read, write := parseIO("rchar: 10\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 0\n")
+	assert.Equal(t, uint64(4096), read)
+	assert.Equal(t, uint64(8192), write)
+}
@@ -2,11 +2,16 @@ package sandbox
 
 import (
 	"bytes"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
 )
 
 func TestSandboxManager_CommandLogging(t *testing.T) {
@@ -285,6 +290,43 @@ func TestSandboxCommandHelpers(t *testing.T) {
 	})
 }
 
+func TestSandboxCommand_Timeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+
+	// Put a hanging sandbox binary first in PATH
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nsleep 5\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "sandbox"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	originalTimeouts := cmdtimeout.GetGlobalConfig()
+	cmdtimeout.SetGlobalConfig(cmdtimeout.Config{
+		Default: time.Minute,
+		PerTool: map[string]time.Duration{"sandbox": 100 * time.Millisecond},
+	})
+	defer cmdtimeout.SetGlobalConfig(originalTimeouts)
+
+	var buf bytes.Buffer
+	originalLogger := cmdlog.GetGlobalLogger()
+	cmdlog.SetGlobalLogger(cmdlog.NewCommandLogger(cmdlog.Config{
+		Enabled: true,
+		Level:   "info",
+		Output:  &buf,
+	}))
+	defer cmdlog.SetGlobalLogger(originalLogger)
+
+	manager := NewManager()
+	start := time.Now()
+	_, err := manager.ListSandboxes()
+
+	require.Error(t, err)
+	assert.True(t, cmdtimeout.IsTimeout(err), "expected a timeout error, got: %v", err)
+	assert.Less(t, time.Since(start), 4*time.Second, "command should be killed at the timeout")
+	assert.Contains(t, buf.String(), "timed out after 100ms")
+}
+
 func TestGetExitCode_Sandbox(t *testing.T) {
 	t.Run("nil_process_state", func(t *testing.T) {
 		cmd := &exec.Cmd{}
@@ -1,20 +1,82 @@
 package sandbox
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
 
 	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
 )
 
-type Manager struct{}
+type Manager struct {
+	ctx      context.Context // bound with WithContext; nil means context.Background()
+	required bool            // sandbox_required: failures are errors instead of "no sandbox"
+}
+
+// RequiredError is returned when sandbox_required is set and the sandbox
+// command fails in a way that would otherwise be treated as "no sandbox"
+type RequiredError struct {
+	Op      string // e.g. "list"
+	Sandbox string // empty for operations on all sandboxes
+	Err     error
+}
+
+func (e *RequiredError) Error() string {
+	if e.Sandbox == "" {
+		return fmt.Sprintf("sandbox_required is set but 'sandbox %s' failed: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("sandbox_required is set but 'sandbox %s' failed for %s: %v", e.Op, e.Sandbox, e.Err)
+}
+
+func (e *RequiredError) Unwrap() error {
+	return e.Err
+}
 
 func NewManager() *Manager {
 	return &Manager{}
 }
 
+// WithContext returns a copy of the manager whose commands are cancelled
+// when ctx is done
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	c := *m
+	c.ctx = ctx
+	return &c
+}
+
+// WithRequired returns a copy of the manager that reports sandbox command
+// failures as *RequiredError instead of assuming no sandbox exists
+func (m *Manager) WithRequired(required bool) *Manager {
+	c := *m
+	c.required = required
+	return &c
+}
+
+// Required reports whether the manager is in sandbox_required mode
+func (m *Manager) Required() bool {
+	return m.required
+}
+
+// CheckAvailable verifies that sandboxes can be listed, which every other
+// sandbox operation depends on
+func (m *Manager) CheckAvailable() error {
+	if _, err := m.runSandboxCommand([]string{"list"}); err != nil {
+		return &RequiredError{Op: "list", Err: err}
+	}
+	return nil
+}
+
+// baseContext returns the context commands run under
+func (m *Manager) baseContext() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
 // GetSandboxName returns the expected sandbox name for an issue (legacy method)
 func (m *Manager) GetSandboxName(issueNumber int) string {
 	return fmt.Sprintf("sbs-%d", issueNumber)
@@ -31,6 +93,9 @@ func (m *Manager) SandboxExists(sandboxName string) (bool, error) {
 	if err != nil {
 		// If sandbox command fails, assume sandboxes don't exist
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() != 0 {
+			if m.required {
+				return false, &RequiredError{Op: "list", Sandbox: sandboxName, Err: err}
+			}
 			return false, nil
 		}
 		return false, fmt.Errorf("failed to list sandboxes: %w", err)
@@ -73,6 +138,9 @@ func (m *Manager) ListSandboxes() ([]string, error) {
 	if err != nil {
 		// If sandbox command fails, return empty list
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() != 0 {
+			if m.required {
+				return nil, &RequiredError{Op: "list", Err: err}
+			}
 			return []string{}, nil
 		}
 		return nil, fmt.Errorf("failed to list sandboxes: %w", err)
@@ -114,12 +182,17 @@ func CheckSandboxInstalled() error {
 
 // runSandboxCommand executes a sandbox command with logging
 func (m *Manager) runSandboxCommand(args []string) ([]byte, error) {
-	ctx := cmdlog.LogCommandGlobal("sandbox", args, cmdlog.GetCaller())
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "sandbox", args, cmdlog.GetCaller())
+
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "sandbox")
+	defer cancel()
 
-	cmd := exec.Command("sandbox", args...)
+	cmd := exec.CommandContext(timeoutCtx, "sandbox", args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
 	start := time.Now()
 	output, err := cmd.Output()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "sandbox", args, timeout, err)
 
 	if err != nil {
 		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
@@ -132,12 +205,17 @@ func (m *Manager) runSandboxCommand(args []string) ([]byte, error) {
 
 // runSandboxCommandRun executes a sandbox command without capturing output, with logging
 func (m *Manager) runSandboxCommandRun(args []string) error {
-	ctx := cmdlog.LogCommandGlobal("sandbox", args, cmdlog.GetCaller())
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "sandbox", args, cmdlog.GetCaller())
 
-	cmd := exec.Command("sandbox", args...)
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "sandbox")
+	defer cancel()
+
+	cmd := exec.CommandContext(timeoutCtx, "sandbox", args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
 	start := time.Now()
 	err := cmd.Run()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "sandbox", args, timeout, err)
 
 	if err != nil {
 		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
@@ -169,6 +247,26 @@ func (m *Manager) ReadFileFromSandbox(sandboxName, filePath string) ([]byte, err
 	return output, nil
 }
 
+// CommandExists reports whether an executable is on the PATH inside a sandbox
+func (m *Manager) CommandExists(sandboxName, executable string) (bool, error) {
+	exists, err := m.SandboxExists(sandboxName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if sandbox exists: %w", err)
+	}
+	if !exists {
+		return false, fmt.Errorf("sandbox %s does not exist", sandboxName)
+	}
+
+	err = m.runSandboxCommandRun([]string{"--name", sandboxName, "sh", "-c", `command -v "$1" >/dev/null`, "sh", executable})
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to look up %s in sandbox %s: %w", executable, sandboxName, err)
+	}
+	return true, nil
+}
+
 // getExitCode extracts exit code from exec.Cmd
 func getExitCode(cmd *exec.Cmd) int {
 	if cmd.ProcessState != nil {
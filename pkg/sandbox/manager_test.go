@@ -0,0 +1,54 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installBrokenSandbox puts a sandbox binary first in PATH whose every
+// command fails, like a sandbox daemon that isn't running
+func installBrokenSandbox(t *testing.T) {
+	t.Helper()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho 'cannot connect to sandbox daemon' >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "sandbox"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestManager_FailuresDegradeUnlessRequired(t *testing.T) {
+	installBrokenSandbox(t)
+
+	lenient := NewManager()
+	exists, err := lenient.SandboxExists("sbs-app-github-1")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.NoError(t, lenient.DeleteSandbox("sbs-app-github-1"), "deleting is a no-op")
+	sandboxes, err := lenient.ListSandboxes()
+	assert.NoError(t, err)
+	assert.Empty(t, sandboxes)
+
+	strict := lenient.WithRequired(true)
+	assert.True(t, strict.Required())
+	assert.False(t, lenient.Required(), "WithRequired returns a copy")
+
+	_, err = strict.SandboxExists("sbs-app-github-1")
+	var requiredErr *RequiredError
+	require.True(t, errors.As(err, &requiredErr))
+	assert.Equal(t, "sbs-app-github-1", requiredErr.Sandbox)
+	assert.Contains(t, err.Error(), "sandbox_required is set but 'sandbox list' failed for sbs-app-github-1")
+
+	assert.ErrorAs(t, strict.DeleteSandbox("sbs-app-github-1"), &requiredErr)
+	_, err = strict.ListSandboxes()
+	assert.ErrorAs(t, err, &requiredErr)
+	assert.ErrorAs(t, lenient.CheckAvailable(), &requiredErr)
+}
+
+func TestManager_CheckAvailable(t *testing.T) {
+	installFakeSandbox(t, false)
+	assert.NoError(t, NewManager().WithRequired(true).CheckAvailable())
+}
@@ -0,0 +1,101 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sbs/pkg/naming"
+)
+
+// ErrPoolEmpty is returned by ClaimPoolSandbox when no warm sandbox is available
+var ErrPoolEmpty = errors.New("sandbox pool is empty")
+
+// PoolSandboxName returns the name of the n-th pool sandbox
+func PoolSandboxName(n int) string {
+	return naming.PoolPrefix() + strconv.Itoa(n)
+}
+
+// IsPoolSandbox reports whether name belongs to this user's prewarm pool
+func IsPoolSandbox(name string) bool {
+	return strings.HasPrefix(name, naming.PoolPrefix())
+}
+
+// PoolSandboxes returns the warm sandboxes currently in the pool, sorted by name
+func (m *Manager) PoolSandboxes() ([]string, error) {
+	sandboxes, err := m.ListSandboxes()
+	if err != nil {
+		return nil, err
+	}
+	var pool []string
+	for _, name := range sandboxes {
+		if IsPoolSandbox(name) {
+			pool = append(pool, name)
+		}
+	}
+	sort.Strings(pool)
+	return pool, nil
+}
+
+// FillPool creates generic sandboxes until the pool holds size of them and
+// returns the names it created. Creation stops at the first failure.
+func (m *Manager) FillPool(size int) ([]string, error) {
+	pool, err := m.PoolSandboxes()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(pool))
+	for _, name := range pool {
+		existing[name] = true
+	}
+
+	var created []string
+	for n := 1; len(pool)+len(created) < size; n++ {
+		name := PoolSandboxName(n)
+		if existing[name] {
+			continue
+		}
+		// Running a no-op command is what creates a sandbox
+		if err := m.runSandboxCommandRun([]string{"--name", name, "true"}); err != nil {
+			return created, fmt.Errorf("failed to create pool sandbox %s: %w", name, err)
+		}
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+// ClaimPoolSandbox renames a warm pool sandbox to target so the session's
+// first sandbox command reuses it, and returns the pool name it claimed.
+// It returns ErrPoolEmpty when the pool has nothing to offer; any other error
+// means every candidate failed to rename (for example because the installed
+// sandbox CLI has no rename command), and callers should create the sandbox
+// on demand as usual.
+func (m *Manager) ClaimPoolSandbox(target string) (string, error) {
+	pool, err := m.PoolSandboxes()
+	if err != nil {
+		return "", err
+	}
+	if len(pool) == 0 {
+		return "", ErrPoolEmpty
+	}
+
+	var lastErr error
+	for _, name := range pool {
+		// Another sbs start may claim the same sandbox first; its rename wins
+		// and ours fails, so move on to the next candidate
+		if err := m.RenameSandbox(name, target); err != nil {
+			lastErr = err
+			continue
+		}
+		return name, nil
+	}
+	return "", fmt.Errorf("failed to claim pool sandbox for %s: %w", target, lastErr)
+}
+
+// RenameSandbox renames a sandbox. Not every sandbox CLI supports renaming,
+// so callers must cope with an error by keeping the old name.
+func (m *Manager) RenameSandbox(oldName, newName string) error {
+	return m.runSandboxCommandRun([]string{"rename", oldName, newName})
+}
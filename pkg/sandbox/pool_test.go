@@ -0,0 +1,86 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installFakeSandbox puts a sandbox binary first in PATH that keeps its
+// sandboxes, one name per line, in the returned state file
+func installFakeSandbox(t *testing.T, withRename bool) string {
+	t.Helper()
+	binDir := t.TempDir()
+	state := filepath.Join(binDir, "sandboxes")
+	require.NoError(t, os.WriteFile(state, nil, 0644))
+
+	rename := `grep -qx "$2" "$state" || exit 1
+    grep -vx "$2" "$state" > "$state.tmp"; echo "$3" >> "$state.tmp"; mv "$state.tmp" "$state" ;;`
+	if !withRename {
+		rename = "exit 2 ;;"
+	}
+	script := `#!/bin/sh
+state="` + state + `"
+case "$1" in
+  list) cat "$state" ;;
+  --name) grep -qx "$2" "$state" || echo "$2" >> "$state" ;;
+  rename) ` + rename + `
+  *) exit 1 ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "sandbox"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return state
+}
+
+func readSandboxes(t *testing.T, state string) []string {
+	t.Helper()
+	data, err := os.ReadFile(state)
+	require.NoError(t, err)
+	return strings.Fields(string(data))
+}
+
+func TestManager_FillAndClaimPool(t *testing.T) {
+	state := installFakeSandbox(t, true)
+	require.NoError(t, os.WriteFile(state, []byte("sbs-myrepo-github-1\nsbs-pool-2\n"), 0644))
+	manager := NewManager()
+
+	created, err := manager.FillPool(3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sbs-pool-1", "sbs-pool-3"}, created, "existing pool sandboxes count towards the size")
+
+	created, err = manager.FillPool(3)
+	require.NoError(t, err)
+	assert.Empty(t, created, "a full pool is left alone")
+
+	claimed, err := manager.ClaimPoolSandbox("sbs-myrepo-github-2")
+	require.NoError(t, err)
+	assert.Equal(t, "sbs-pool-1", claimed)
+	assert.ElementsMatch(t, []string{"sbs-myrepo-github-1", "sbs-pool-2", "sbs-pool-3", "sbs-myrepo-github-2"}, readSandboxes(t, state))
+
+	pool, err := manager.PoolSandboxes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sbs-pool-2", "sbs-pool-3"}, pool)
+}
+
+func TestManager_ClaimPoolSandbox_Fallbacks(t *testing.T) {
+	t.Run("empty_pool", func(t *testing.T) {
+		installFakeSandbox(t, true)
+		_, err := NewManager().ClaimPoolSandbox("sbs-myrepo-github-1")
+		assert.True(t, errors.Is(err, ErrPoolEmpty))
+	})
+
+	t.Run("rename_unsupported", func(t *testing.T) {
+		state := installFakeSandbox(t, false)
+		require.NoError(t, os.WriteFile(state, []byte("sbs-pool-1\n"), 0644))
+		_, err := NewManager().ClaimPoolSandbox("sbs-myrepo-github-1")
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrPoolEmpty))
+		assert.Equal(t, []string{"sbs-pool-1"}, readSandboxes(t, state), "the warm sandbox stays in the pool")
+	})
+}
@@ -0,0 +1,54 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSnapshotsUnsupported is returned when the installed sandbox CLI has no
+// snapshot commands
+var ErrSnapshotsUnsupported = errors.New("the installed sandbox CLI does not support snapshots")
+
+// Snapshotter is the snapshot support of a sandbox provider. Snapshots are
+// kept by the provider, so a sandbox deleted after a snapshot can be
+// restored from it.
+type Snapshotter interface {
+	SupportsSnapshots() bool
+	Snapshot(sandboxName, snapshot string) error
+	RestoreSnapshot(sandboxName, snapshot string) error
+}
+
+// SnapshotName returns the name sbs gives a snapshot taken at t
+func SnapshotName(t time.Time) string {
+	return "sbs-" + t.UTC().Format("20060102-150405")
+}
+
+// SupportsSnapshots reports whether the sandbox CLI has the snapshot command
+func (m *Manager) SupportsSnapshots() bool {
+	_, err := m.runSandboxCommand([]string{"snapshot", "--help"})
+	return err == nil
+}
+
+// Snapshot saves the state of a sandbox under the snapshot name
+func (m *Manager) Snapshot(sandboxName, snapshot string) error {
+	if err := m.runSandboxCommandRun([]string{"snapshot", sandboxName, snapshot}); err != nil {
+		if !m.SupportsSnapshots() {
+			return ErrSnapshotsUnsupported
+		}
+		return fmt.Errorf("failed to snapshot sandbox %s: %w", sandboxName, err)
+	}
+	return nil
+}
+
+// RestoreSnapshot returns a sandbox to a snapshot, recreating the sandbox
+// if it was deleted
+func (m *Manager) RestoreSnapshot(sandboxName, snapshot string) error {
+	if err := m.runSandboxCommandRun([]string{"restore", sandboxName, snapshot}); err != nil {
+		if !m.SupportsSnapshots() {
+			return ErrSnapshotsUnsupported
+		}
+		return fmt.Errorf("failed to restore sandbox %s from snapshot %s: %w", sandboxName, snapshot, err)
+	}
+	return nil
+}
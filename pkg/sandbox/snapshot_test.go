@@ -0,0 +1,66 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installSnapshotSandbox puts a sandbox binary first in PATH that records
+// snapshot and restore calls in the returned log file, or that has no
+// snapshot commands at all
+func installSnapshotSandbox(t *testing.T, withSnapshots bool) string {
+	t.Helper()
+	binDir := t.TempDir()
+	log := filepath.Join(binDir, "calls")
+	script := `#!/bin/sh
+case "$1" in
+  snapshot|restore)
+    [ "$2" = "--help" ] && exit 0
+    [ "$2" = "broken" ] && exit 3
+    echo "$*" >> "` + log + `" ;;
+  *) exit 1 ;;
+esac
+`
+	if !withSnapshots {
+		script = "#!/bin/sh\necho \"unknown command: $1\" >&2\nexit 2\n"
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "sandbox"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestManager_Snapshots(t *testing.T) {
+	log := installSnapshotSandbox(t, true)
+	manager := NewManager()
+
+	assert.True(t, manager.SupportsSnapshots())
+	require.NoError(t, manager.Snapshot("sbs-web-12", "sbs-20260304-120000"))
+	require.NoError(t, manager.RestoreSnapshot("sbs-web-12", "sbs-20260304-120000"))
+
+	calls, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot sbs-web-12 sbs-20260304-120000\nrestore sbs-web-12 sbs-20260304-120000\n", string(calls))
+
+	err = manager.Snapshot("broken", "s1")
+	assert.ErrorContains(t, err, "failed to snapshot sandbox broken")
+	assert.NotErrorIs(t, err, ErrSnapshotsUnsupported)
+}
+
+func TestManager_SnapshotsUnsupported(t *testing.T) {
+	installSnapshotSandbox(t, false)
+	manager := NewManager()
+
+	assert.False(t, manager.SupportsSnapshots())
+	assert.ErrorIs(t, manager.Snapshot("sbs-web-12", "s1"), ErrSnapshotsUnsupported)
+	assert.ErrorIs(t, manager.RestoreSnapshot("sbs-web-12", "s1"), ErrSnapshotsUnsupported)
+}
+
+func TestSnapshotName(t *testing.T) {
+	at := time.Date(2026, 3, 4, 7, 5, 9, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, "sbs-20260304-060509", SnapshotName(at))
+}
@@ -0,0 +1,165 @@
+// Package sessiondoc writes .sbs/SESSION.md into session worktrees: a short
+// description of the work item and the sbs commands for the session, so
+// anyone (or any agent) landing in the directory knows what it is for.
+package sessiondoc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+	"sbs/pkg/inbox"
+	"sbs/pkg/trace"
+)
+
+// RelativePath is where the document lives inside a worktree
+const RelativePath = ".sbs/SESSION.md"
+
+// excludePatterns keep the document and the status inbox out of git status
+var excludePatterns = []string{"/" + RelativePath, "/" + inbox.RelativeDir + "/"}
+
+// Path returns the document's path inside a worktree
+func Path(worktreePath string) string {
+	return filepath.Join(worktreePath, filepath.FromSlash(RelativePath))
+}
+
+// Render returns the document for a session
+func Render(session config.SessionMetadata) string {
+	var b strings.Builder
+	id := session.NamespacedID
+
+	title := id
+	if session.IssueTitle != "" {
+		title = fmt.Sprintf("%s: %s", id, session.IssueTitle)
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "This worktree is the sbs session for work item %s.\n\n", id)
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", name, value)
+		}
+	}
+	field("URL", session.WorkItemURL)
+	field("Branch", session.Branch)
+	field("Repository", repository(session))
+	field("Tmux session", session.TmuxSession)
+	field("Sandbox", session.SandboxName)
+
+	b.WriteString("\n## Commands\n\n```sh\n")
+	fmt.Fprintf(&b, "sbs attach %s    # Open the tmux session\n", id)
+	fmt.Fprintf(&b, "sbs log %s       # Show the session's recent output\n", id)
+	fmt.Fprintf(&b, "sbs show %s      # Show the session's details\n", id)
+	fmt.Fprintf(&b, "sbs stop %s      # Stop the session\n", id)
+	b.WriteString("```\n\n")
+	b.WriteString("Generated by sbs and rewritten when the session changes; edits are lost.\n")
+	return b.String()
+}
+
+// repository describes where the session's repository lives
+func repository(session config.SessionMetadata) string {
+	switch {
+	case session.RepositoryName != "" && session.RepositoryRoot != "":
+		return fmt.Sprintf("%s (%s)", session.RepositoryName, session.RepositoryRoot)
+	case session.RepositoryRoot != "":
+		return session.RepositoryRoot
+	default:
+		return session.RepositoryName
+	}
+}
+
+// Write creates or updates the document in the session's worktree and keeps
+// it, and the status inbox, out of git status. An up to date document is left untouched.
+func Write(session config.SessionMetadata) error {
+	if session.WorktreePath == "" {
+		return fmt.Errorf("session %s has no worktree", session.NamespacedID)
+	}
+	path := Path(session.WorktreePath)
+	content := []byte(Render(session))
+
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	// Write atomically so readers never see a partial document
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	trace.StateChange("write session doc", path, session.NamespacedID)
+
+	if err := excludeFromGit(session.WorktreePath); err != nil {
+		return fmt.Errorf("failed to exclude %s from git: %w", RelativePath, err)
+	}
+	return nil
+}
+
+// Update rewrites the document after the session changed, e.g. its branch was
+// renamed. Sessions whose worktree is gone are skipped.
+func Update(session config.SessionMetadata) error {
+	if session.WorktreePath == "" {
+		return nil
+	}
+	if _, err := os.Stat(session.WorktreePath); err != nil {
+		return nil
+	}
+	return Write(session)
+}
+
+// excludeFromGit adds the document and the status inbox to the repository's
+// info/exclude, which all of its worktrees share
+func excludeFromGit(worktreePath string) error {
+	output, err := exec.Command(git.Executable(), "-C", worktreePath, "rev-parse", "--git-path", "info/exclude").Output()
+	if err != nil {
+		return fmt.Errorf("failed to locate info/exclude: %w", err)
+	}
+	excludePath := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(excludePath) {
+		excludePath = filepath.Join(worktreePath, excludePath)
+	}
+
+	existing, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var entry string
+	for _, pattern := range excludePatterns {
+		if !present[pattern] {
+			entry += pattern + "\n"
+		}
+	}
+	if entry == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		entry = "\n" + entry
+	}
+	_, err = file.WriteString(entry)
+	return err
+}
@@ -0,0 +1,93 @@
+package sessiondoc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+// newWorktree creates a repository with a linked worktree and returns both
+func newWorktree(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	repoRoot := filepath.Join(dir, "repo")
+	worktree := filepath.Join(dir, "issue-github-42")
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	run("init", "-q", repoRoot)
+	run("-C", repoRoot, "commit", "-q", "--allow-empty", "-m", "init")
+	run("-C", repoRoot, "worktree", "add", "-q", "-b", "issue-github-42-fix", worktree)
+	return repoRoot, worktree
+}
+
+func testSession(worktree string) config.SessionMetadata {
+	return config.SessionMetadata{
+		NamespacedID:   "github:42",
+		IssueTitle:     "Fix login redirect",
+		WorkItemURL:    "https://github.com/acme/app/issues/42",
+		Branch:         "issue-github-42-fix",
+		WorktreePath:   worktree,
+		TmuxSession:    "sbs-app-github-42",
+		RepositoryName: "app",
+		RepositoryRoot: "/src/app",
+	}
+}
+
+func TestRender(t *testing.T) {
+	doc := Render(testSession("/w"))
+	assert.True(t, strings.HasPrefix(doc, "# github:42: Fix login redirect\n"))
+	assert.Contains(t, doc, "- URL: https://github.com/acme/app/issues/42\n")
+	assert.Contains(t, doc, "- Branch: issue-github-42-fix\n")
+	assert.Contains(t, doc, "- Repository: app (/src/app)\n")
+	assert.Contains(t, doc, "sbs attach github:42")
+	assert.Contains(t, doc, "sbs stop github:42")
+	assert.NotContains(t, doc, "Sandbox:", "empty fields are left out")
+}
+
+func TestWrite_KeepsDocumentOutOfGitStatus(t *testing.T) {
+	repoRoot, worktree := newWorktree(t)
+	session := testSession(worktree)
+
+	require.NoError(t, Write(session))
+	content, err := os.ReadFile(Path(worktree))
+	require.NoError(t, err)
+	assert.Equal(t, Render(session), string(content))
+
+	status, err := exec.Command("git", "-C", worktree, "status", "--porcelain").Output()
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(string(status)))
+
+	// Writing again doesn't duplicate the exclude entry
+	require.NoError(t, Write(session))
+	exclude, err := os.ReadFile(filepath.Join(repoRoot, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(exclude), "/.sbs/SESSION.md"))
+	assert.Equal(t, 1, strings.Count(string(exclude), "/.sbs/inbox/"))
+}
+
+func TestUpdate(t *testing.T) {
+	_, worktree := newWorktree(t)
+	session := testSession(worktree)
+	require.NoError(t, Write(session))
+
+	session.Branch = "renamed-branch"
+	require.NoError(t, Update(session))
+	content, err := os.ReadFile(Path(worktree))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "- Branch: renamed-branch\n")
+
+	session.WorktreePath = filepath.Join(t.TempDir(), "gone")
+	assert.NoError(t, Update(session), "removed worktrees are skipped")
+	assert.NoFileExists(t, Path(session.WorktreePath))
+}
@@ -0,0 +1,133 @@
+// Package stalehook runs a repository's custom stale detector.
+//
+// A stalehook is an executable at .sbs/stalehook in the repository root. sbs
+// runs it from the repository root once per session it considers for cleanup,
+// with the session metadata as JSON on stdin and these variables added to the
+// environment:
+//
+//	SBS_WORK_ITEM      namespaced work item ID, e.g. "jira:PROJ-123"
+//	SBS_BRANCH         session branch name
+//	SBS_TMUX_SESSION   tmux session name
+//	SBS_WORKTREE       worktree path
+//
+// Exit status 0 marks the session stale and 1 marks it active, overriding the
+// built-in rule (stale when its tmux session is gone). Any other exit status,
+// or a failure to run the script, leaves the decision to the built-in rule.
+// The script runs under the "stalehook" command timeout.
+package stalehook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/config"
+)
+
+// ScriptName is the stalehook's file name in the repository's .sbs directory
+const ScriptName = "stalehook"
+
+// Environment variables passed to stalehook scripts
+const (
+	EnvWorkItem    = "SBS_WORK_ITEM"
+	EnvBranch      = "SBS_BRANCH"
+	EnvTmuxSession = "SBS_TMUX_SESSION"
+	EnvWorktree    = "SBS_WORKTREE"
+)
+
+// Verdict is a stalehook's decision about a session
+type Verdict int
+
+const (
+	Undecided Verdict = iota // No hook, or the hook left the decision to sbs
+	Stale                    // Exit status 0
+	Active                   // Exit status 1
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Stale:
+		return "stale"
+	case Active:
+		return "active"
+	default:
+		return "undecided"
+	}
+}
+
+// ScriptPath returns the stalehook location for a repository
+func ScriptPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".sbs", ScriptName)
+}
+
+// Find returns the repository's stalehook, or "" if it has none
+func Find(repoRoot string) string {
+	if repoRoot == "" {
+		return ""
+	}
+	path := ScriptPath(repoRoot)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return ""
+	}
+	return path
+}
+
+// Run asks the session's repository stalehook whether the session is stale.
+// Sessions in repositories without a stalehook are Undecided.
+func Run(ctx context.Context, session config.SessionMetadata) (Verdict, error) {
+	scriptPath := Find(session.RepositoryRoot)
+	if scriptPath == "" {
+		return Undecided, nil
+	}
+
+	input, err := json.Marshal(session)
+	if err != nil {
+		return Undecided, fmt.Errorf("failed to encode session for stalehook: %w", err)
+	}
+
+	args := []string{session.NamespacedID}
+	logCtx := cmdlog.LogCommandContext(ctx, scriptPath, args, cmdlog.GetCaller())
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(ctx, ScriptName)
+	defer cancel()
+
+	cmd := exec.CommandContext(timeoutCtx, scriptPath)
+	cmd.Dir = session.RepositoryRoot
+	cmd.Stdin = strings.NewReader(string(input))
+	cmd.Env = append(os.Environ(),
+		EnvWorkItem+"="+session.NamespacedID,
+		EnvBranch+"="+session.Branch,
+		EnvTmuxSession+"="+session.TmuxSession,
+		EnvWorktree+"="+session.WorktreePath,
+	)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, ScriptName, args, timeout, err)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		logCtx.LogCompletion(true, 0, "", duration)
+		return Stale, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		logCtx.LogCompletion(true, 1, "", duration)
+		return Active, nil
+	case errors.As(err, &exitErr):
+		logCtx.LogCompletion(true, exitErr.ExitCode(), strings.TrimSpace(string(output)), duration)
+		return Undecided, nil
+	default:
+		logCtx.LogCompletion(false, -1, err.Error(), duration)
+		return Undecided, fmt.Errorf("stalehook for %s failed: %w", session.NamespacedID, err)
+	}
+}
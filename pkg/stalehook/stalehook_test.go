@@ -0,0 +1,98 @@
+package stalehook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+// writeHook installs a stalehook script in a new repository root
+func writeHook(t *testing.T, script string) string {
+	t.Helper()
+	repoRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, ".sbs"), 0755))
+	require.NoError(t, os.WriteFile(ScriptPath(repoRoot), []byte("#!/bin/sh\n"+script), 0755))
+	return repoRoot
+}
+
+func TestRun_Verdicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected Verdict
+	}{
+		{"exit 0 is stale", "exit 0\n", Stale},
+		{"exit 1 is active", "exit 1\n", Active},
+		{"other status is undecided", "echo 'tracker unreachable' >&2; exit 3\n", Undecided},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoRoot := writeHook(t, tt.script)
+			verdict, err := Run(context.Background(), config.SessionMetadata{NamespacedID: "jira:PROJ-1", RepositoryRoot: repoRoot})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, verdict)
+		})
+	}
+}
+
+func TestRun_ReceivesSession(t *testing.T) {
+	repoRoot := writeHook(t, `cat > stdin.json
+printf '%s|%s|%s|%s' "$SBS_WORK_ITEM" "$SBS_BRANCH" "$SBS_TMUX_SESSION" "$SBS_WORKTREE" > env.txt
+exit 1
+`)
+	session := config.SessionMetadata{
+		NamespacedID:   "jira:PROJ-7",
+		Branch:         "issue-jira-PROJ-7-login",
+		TmuxSession:    "sbs-jira-PROJ-7",
+		WorktreePath:   "/tmp/wt",
+		RepositoryRoot: repoRoot,
+	}
+
+	verdict, err := Run(context.Background(), session)
+	require.NoError(t, err)
+	assert.Equal(t, Active, verdict)
+
+	data, err := os.ReadFile(filepath.Join(repoRoot, "stdin.json"))
+	require.NoError(t, err)
+	var received config.SessionMetadata
+	require.NoError(t, json.Unmarshal(data, &received))
+	assert.Equal(t, session.NamespacedID, received.NamespacedID)
+	assert.Equal(t, session.Branch, received.Branch)
+
+	env, err := os.ReadFile(filepath.Join(repoRoot, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "jira:PROJ-7|issue-jira-PROJ-7-login|sbs-jira-PROJ-7|/tmp/wt", string(env))
+}
+
+func TestRun_NoHook(t *testing.T) {
+	verdict, err := Run(context.Background(), config.SessionMetadata{RepositoryRoot: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, Undecided, verdict)
+
+	verdict, err = Run(context.Background(), config.SessionMetadata{})
+	require.NoError(t, err)
+	assert.Equal(t, Undecided, verdict)
+
+	// A hook that isn't executable is ignored
+	repoRoot := writeHook(t, "exit 0\n")
+	require.NoError(t, os.Chmod(ScriptPath(repoRoot), 0644))
+	verdict, err = Run(context.Background(), config.SessionMetadata{RepositoryRoot: repoRoot})
+	require.NoError(t, err)
+	assert.Equal(t, Undecided, verdict)
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	repoRoot := writeHook(t, "sleep 5\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	verdict, err := Run(ctx, config.SessionMetadata{NamespacedID: "test:1", RepositoryRoot: repoRoot})
+	assert.Error(t, err)
+	assert.Equal(t, Undecided, verdict)
+}
@@ -0,0 +1,63 @@
+package status
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// stopFileEntry is the parsed result of a stop.json as of its modification time and size
+type stopFileEntry struct {
+	modTime time.Time
+	size    int64
+	info    StopFileInfo
+	err     error
+}
+
+// stopFileCache remembers parsed stop.json files so unchanged files cost a
+// stat instead of a read and parse on every status tick
+type stopFileCache struct {
+	mu      sync.Mutex
+	entries map[string]stopFileEntry
+}
+
+func newStopFileCache() *stopFileCache {
+	return &stopFileCache{entries: make(map[string]stopFileEntry)}
+}
+
+// parse returns the contents of the stop.json at path, re-reading it only
+// when its modification time or size changed. Validation errors are cached
+// like contents; read errors are not.
+func (c *stopFileCache) parse(path string) (StopFileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		c.forget(path)
+		return StopFileInfo{}, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.info, entry.err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.forget(path)
+		return StopFileInfo{}, err
+	}
+	entry = stopFileEntry{modTime: info.ModTime(), size: info.Size()}
+	entry.info, entry.err = ValidateStopFile(data)
+
+	c.mu.Lock()
+	c.entries[path] = entry
+	c.mu.Unlock()
+	return entry.info, entry.err
+}
+
+func (c *stopFileCache) forget(path string) {
+	c.mu.Lock()
+	delete(c.entries, path)
+	c.mu.Unlock()
+}
@@ -0,0 +1,52 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopFileCache_RereadsOnlyChangedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stop.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"timestamp": "2025-08-01T10:15:30Z"}`), 0644))
+	modTime := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	cache := newStopFileCache()
+	first, err := cache.parse(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2025, first.Timestamp.Year())
+
+	// Same size and modification time: the cached result is used
+	require.NoError(t, os.WriteFile(path, []byte(`{"timestamp": "2026-08-01T10:15:30Z"}`), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	cached, err := cache.parse(path)
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+
+	// A new modification time triggers a re-read
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now()))
+	updated, err := cache.parse(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2026, updated.Timestamp.Year())
+}
+
+func TestStopFileCache_CachesValidationErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stop.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"timestamp": `), 0644))
+
+	cache := newStopFileCache()
+	_, err := cache.parse(path)
+	assert.ErrorIs(t, err, ErrCorruptStopFile)
+	_, err = cache.parse(path)
+	assert.ErrorIs(t, err, ErrCorruptStopFile)
+
+	require.NoError(t, os.Remove(path))
+	_, err = cache.parse(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, cache.entries, "missing files are forgotten")
+}
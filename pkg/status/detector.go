@@ -1,20 +1,24 @@
 package status
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"sbs/pkg/clock"
 	"sbs/pkg/config"
 )
 
 // SessionStatus represents the status of a work session
 type SessionStatus struct {
-	Status     string     // active, stopped, stale, unknown
+	Status     string     // active, waiting, stopped, stale, repo missing, unknown
 	LastChange *time.Time // timestamp when status last changed
 	TimeDelta  string     // human-readable time since last change
+	Warning    string     // problem found while detecting status (e.g. corrupt stop.json)
+	Message    string     // what a waiting agent needs from the user, when reported
 }
 
 // TmuxManager interface for tmux operations (for dependency injection/testing)
@@ -33,6 +37,9 @@ type Detector struct {
 	tmuxManager    TmuxManager
 	sandboxManager SandboxManager
 	timeFormatter  *TimeFormatter
+	stopFiles      *stopFileCache
+	timeouts       *TimeoutResolver
+	clock          clock.Clock
 }
 
 // NewDetector creates a new status detector
@@ -41,11 +48,55 @@ func NewDetector(tmuxManager TmuxManager, sandboxManager SandboxManager) *Detect
 		tmuxManager:    tmuxManager,
 		sandboxManager: sandboxManager,
 		timeFormatter:  NewTimeFormatter(),
+		stopFiles:      newStopFileCache(),
 	}
 }
 
+// SetTimeouts bounds each session's status detection by its repository's
+// status timeout; without it detection is bounded only by the caller's context
+func (d *Detector) SetTimeouts(timeouts *TimeoutResolver) {
+	d.timeouts = timeouts
+}
+
+// SetClock sets the clock status deltas are measured with; without it the
+// clock set with config.SetClock is used
+func (d *Detector) SetClock(c clock.Clock) {
+	d.clock = c
+}
+
+func (d *Detector) now() time.Time {
+	if d.clock == nil {
+		return config.Now()
+	}
+	return d.clock.Now()
+}
+
 // DetectSessionStatus determines the current status of a session
 func (d *Detector) DetectSessionStatus(session config.SessionMetadata) SessionStatus {
+	return d.DetectSessionStatusContext(context.Background(), session)
+}
+
+// DetectSessionStatusContext is like DetectSessionStatus but gives up once ctx
+// is done, reporting the status as unknown
+func (d *Detector) DetectSessionStatusContext(ctx context.Context, session config.SessionMetadata) SessionStatus {
+	if d.timeouts != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeouts.Timeout(session.RepositoryRoot))
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return cancelledStatus(err)
+	}
+
+	// A deleted repository leaves nothing to resume, whatever tmux says
+	if session.RepositoryMissing() {
+		return SessionStatus{
+			Status:    config.StatusRepoMissing,
+			TimeDelta: "unknown",
+			Warning:   fmt.Sprintf("repository %s no longer exists; run 'sbs clean --repo-missing'", session.RepositoryRoot),
+		}
+	}
+
 	// Check if tmux session exists
 	tmuxExists := false
 	if session.TmuxSession != "" {
@@ -55,32 +106,58 @@ func (d *Detector) DetectSessionStatus(session config.SessionMetadata) SessionSt
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return cancelledStatus(err)
+	}
+
 	// Check for stop.json file in sandbox/.sbs/ first, then fallback to direct file access
-	var stopTime time.Time
+	stopFilePath := filepath.Join(session.WorktreePath, ".sbs", "stop.json")
+	var stopInfo StopFileInfo
 	var err error
 	if session.SandboxName != "" {
-		stopTime, err = d.ParseStopJsonFromSandbox(session.SandboxName, ".sbs/stop.json")
+		stopInfo, err = d.readStopFileFromSandbox(session.SandboxName, ".sbs/stop.json")
 		// If sandbox reading fails, fallback to direct file access
 		if err != nil {
-			stopFilePath := filepath.Join(session.WorktreePath, ".sbs", "stop.json")
-			stopTime, err = d.ParseStopJsonFile(stopFilePath)
+			stopInfo, err = d.stopFiles.parse(stopFilePath)
 		}
 	} else {
 		// No sandbox name available, use direct file access (for backward compatibility)
-		stopFilePath := filepath.Join(session.WorktreePath, ".sbs", "stop.json")
-		stopTime, err = d.ParseStopJsonFile(stopFilePath)
+		stopInfo, err = d.stopFiles.parse(stopFilePath)
 	}
 
-	now := time.Now()
+	now := d.now()
 
-	if err == nil && !stopTime.IsZero() {
-		// stop.json exists and is valid - session is stopped
-		timeDelta := d.timeFormatter.FormatTimeDelta(stopTime, now)
-		return SessionStatus{
+	if err == nil && !stopInfo.Timestamp.IsZero() {
+		// stop.json exists and is valid - the agent is waiting on the user or stopped
+		stopTime := stopInfo.Timestamp
+		sessionStatus := SessionStatus{
 			Status:     "stopped",
 			LastChange: &stopTime,
-			TimeDelta:  timeDelta,
+			TimeDelta:  d.timeFormatter.FormatTimeDelta(stopTime, now),
+		}
+		if stopInfo.WaitingForInput {
+			sessionStatus.Status = "waiting"
+			sessionStatus.Message = stopInfo.Message
 		}
+		return sessionStatus
+	}
+
+	// Move corrupt status files aside so they are not re-read, and surface a warning
+	warning := ""
+	if errors.Is(err, ErrCorruptStopFile) {
+		if _, statErr := os.Stat(stopFilePath); statErr == nil {
+			if badPath, quarantineErr := QuarantineStopFile(stopFilePath); quarantineErr == nil {
+				warning = fmt.Sprintf("corrupt status file moved to %s: %v", badPath, err)
+			} else {
+				warning = fmt.Sprintf("corrupt status file: %v", err)
+			}
+		} else {
+			warning = fmt.Sprintf("corrupt status file: %v", err)
+		}
+	} else if errors.Is(err, ErrUnsupportedStopFileVersion) {
+		warning = err.Error()
+	} else if _, statErr := os.Stat(stopFilePath + QuarantineSuffix); statErr == nil {
+		warning = fmt.Sprintf("status file was corrupt and quarantined at %s", stopFilePath+QuarantineSuffix)
 	}
 
 	if tmuxExists {
@@ -88,14 +165,17 @@ func (d *Detector) DetectSessionStatus(session config.SessionMetadata) SessionSt
 		return SessionStatus{
 			Status:    "active",
 			TimeDelta: "now",
+			Warning:   warning,
 		}
 	}
 
 	if err != nil && !os.IsNotExist(err) {
-		// Stop file exists but can't be parsed - unknown status
+		// Stop file exists but can't be used and tmux can't confirm activity - unknown status.
+		// A file quarantined earlier is gone, so it only leaves its warning behind.
 		return SessionStatus{
 			Status:    "unknown",
 			TimeDelta: "unknown",
+			Warning:   warning,
 		}
 	}
 
@@ -117,81 +197,41 @@ func (d *Detector) DetectSessionStatus(session config.SessionMetadata) SessionSt
 		Status:     "stale",
 		LastChange: &lastActivity,
 		TimeDelta:  timeDelta,
+		Warning:    warning,
 	}
 }
 
-// ParseStopJsonFile parses a stop.json file and extracts the timestamp
-func (d *Detector) ParseStopJsonFile(filePath string) (time.Time, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return time.Time{}, err
-	}
-
-	if len(data) == 0 {
-		return time.Time{}, fmt.Errorf("empty stop.json file")
-	}
-
-	var stopData map[string]interface{}
-	if err := json.Unmarshal(data, &stopData); err != nil {
-		return time.Time{}, fmt.Errorf("invalid JSON in stop.json: %w", err)
-	}
-
-	// Try to extract timestamp from different formats
-	timestamp := extractTimestamp(stopData)
-	if timestamp.IsZero() {
-		return time.Time{}, fmt.Errorf("no valid timestamp found in stop.json")
+func cancelledStatus(err error) SessionStatus {
+	return SessionStatus{
+		Status:    "unknown",
+		TimeDelta: "unknown",
+		Warning:   fmt.Sprintf("status detection stopped: %v", err),
 	}
+}
 
-	return timestamp, nil
+// ParseStopJsonFile parses and validates a stop.json file and extracts the
+// timestamp. Results are cached until the file's modification time or size changes.
+func (d *Detector) ParseStopJsonFile(filePath string) (time.Time, error) {
+	info, err := d.stopFiles.parse(filePath)
+	return info.Timestamp, err
 }
 
 // ParseStopJsonFromSandbox parses a stop.json file from within a sandbox and extracts the timestamp
 func (d *Detector) ParseStopJsonFromSandbox(sandboxName, filePath string) (time.Time, error) {
+	info, err := d.readStopFileFromSandbox(sandboxName, filePath)
+	return info.Timestamp, err
+}
+
+// readStopFileFromSandbox reads and validates a stop.json file from within a sandbox
+func (d *Detector) readStopFileFromSandbox(sandboxName, filePath string) (StopFileInfo, error) {
 	data, err := d.sandboxManager.ReadFileFromSandbox(sandboxName, filePath)
 	if err != nil {
-		return time.Time{}, err
+		return StopFileInfo{}, err
 	}
-
-	if len(data) == 0 {
-		return time.Time{}, fmt.Errorf("empty stop.json file")
-	}
-
-	var stopData map[string]interface{}
-	if err := json.Unmarshal(data, &stopData); err != nil {
-		return time.Time{}, fmt.Errorf("invalid JSON in stop.json: %w", err)
-	}
-
-	// Try to extract timestamp from different formats
-	timestamp := extractTimestamp(stopData)
-	if timestamp.IsZero() {
-		return time.Time{}, fmt.Errorf("no valid timestamp found in stop.json")
-	}
-
-	return timestamp, nil
+	return ValidateStopFile(data)
 }
 
 // CalculateTimeDelta calculates human-readable time delta from a timestamp
 func (d *Detector) CalculateTimeDelta(timestamp time.Time) string {
-	return d.timeFormatter.FormatTimeDelta(timestamp, time.Now())
-}
-
-// extractTimestamp extracts timestamp from various stop.json formats
-func extractTimestamp(data map[string]interface{}) time.Time {
-	// Try Claude Code hook format first
-	if hookData, ok := data["claude_code_hook"].(map[string]interface{}); ok {
-		if timestampStr, ok := hookData["timestamp"].(string); ok {
-			if timestamp, err := time.Parse(time.RFC3339, timestampStr); err == nil {
-				return timestamp
-			}
-		}
-	}
-
-	// Try direct timestamp field
-	if timestampStr, ok := data["timestamp"].(string); ok {
-		if timestamp, err := time.Parse(time.RFC3339, timestampStr); err == nil {
-			return timestamp
-		}
-	}
-
-	return time.Time{}
+	return d.timeFormatter.FormatTimeDelta(timestamp, d.now())
 }
@@ -1,6 +1,7 @@
 package status
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"sbs/pkg/clock"
 	"sbs/pkg/config"
 )
 
@@ -227,6 +229,21 @@ func TestStatusDetector_CalculateTimeDelta(t *testing.T) {
 	}
 }
 
+func TestStatusDetector_SetClock(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	detector := NewDetector(&MockTmuxManager{}, &MockSandboxManager{})
+	detector.SetClock(clock.NewFake(now))
+
+	assert.Equal(t, "3h ago", detector.CalculateTimeDelta(now.Add(-3*time.Hour)))
+
+	status := detector.DetectSessionStatus(config.SessionMetadata{
+		WorktreePath: t.TempDir(),
+		LastActivity: now.Add(-50 * time.Hour).Format(time.RFC3339),
+	})
+	assert.Equal(t, "stale", status.Status)
+	assert.Equal(t, "2d ago", status.TimeDelta)
+}
+
 func TestStatusDetector_HandleMissingStopFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	worktreePath := filepath.Join(tmpDir, "issue-123")
@@ -248,6 +265,35 @@ func TestStatusDetector_HandleMissingStopFile(t *testing.T) {
 	assert.Nil(t, status.LastChange)
 }
 
+func TestStatusDetector_RepositoryMissing(t *testing.T) {
+	mockTmux := &MockTmuxManager{}
+	mockTmux.SetSessionExists("sbs-123", true)
+	detector := NewDetector(mockTmux, &MockSandboxManager{})
+	missing := filepath.Join(t.TempDir(), "deleted-repo")
+
+	status := detector.DetectSessionStatus(config.SessionMetadata{
+		TmuxSession:    "sbs-123",
+		WorktreePath:   t.TempDir(),
+		RepositoryRoot: missing,
+	})
+
+	assert.Equal(t, config.StatusRepoMissing, status.Status, "a running tmux session doesn't bring the repository back")
+	assert.Contains(t, status.Warning, missing)
+}
+
+func TestStatusDetector_CancelledContext(t *testing.T) {
+	mockTmux := &MockTmuxManager{}
+	mockTmux.SetSessionExists("sbs-123", true)
+	detector := NewDetector(mockTmux, &MockSandboxManager{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	status := detector.DetectSessionStatusContext(ctx, config.SessionMetadata{TmuxSession: "sbs-123"})
+	assert.Equal(t, "unknown", status.Status)
+	assert.Contains(t, status.Warning, "context canceled")
+}
+
 func TestStatusDetector_HandlePermissionErrors(t *testing.T) {
 	if os.Getuid() == 0 {
 		t.Skip("Skipping permission test as root user")
@@ -0,0 +1,203 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+const (
+	// HistoryCapacity is the number of samples kept per session
+	HistoryCapacity = 60
+	// HistoryInterval is the minimum spacing between recorded samples, so the
+	// ring covers roughly the last hour
+	HistoryInterval = time.Minute
+	// HistoryRetention drops sessions that have not been sampled for this long
+	HistoryRetention = 24 * time.Hour
+)
+
+// Sample is one observed status of a session
+type Sample struct {
+	Time   time.Time `json:"t"`
+	Status string    `json:"s"`
+}
+
+// HistoryStore persists a small ring buffer of status samples per session in
+// a single JSON file keyed by namespaced ID. It is safe for concurrent use.
+type HistoryStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewHistoryStore creates a store backed by the file at path
+func NewHistoryStore(path string) *HistoryStore {
+	return &HistoryStore{path: path}
+}
+
+// DefaultHistoryPath returns status-history.json in the state directory
+func DefaultHistoryPath() (string, error) {
+	return paths.StatePath("status-history.json")
+}
+
+// Load returns the recorded samples; a missing file yields an empty history
+func (s *HistoryStore) Load() (map[string][]Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *HistoryStore) load() (map[string][]Sample, error) {
+	history := make(map[string][]Sample)
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status history: %w", err)
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse status history %s: %w", s.path, err)
+	}
+	return history, nil
+}
+
+// Record adds a sample for each session in statuses (namespaced ID to status)
+// unless one was recorded less than HistoryInterval ago, and returns the
+// updated history. A corrupt history file is replaced rather than reported.
+func (s *HistoryStore) Record(statuses map[string]string, now time.Time) (map[string][]Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.load()
+	if err != nil {
+		history = make(map[string][]Sample)
+	}
+
+	for id, current := range statuses {
+		samples := history[id]
+		if !SampleDue(samples, now) {
+			continue
+		}
+		samples = append(samples, Sample{Time: now, Status: current})
+		if len(samples) > HistoryCapacity {
+			samples = samples[len(samples)-HistoryCapacity:]
+		}
+		history[id] = samples
+	}
+
+	for id, samples := range history {
+		if len(samples) == 0 || now.Sub(samples[len(samples)-1].Time) > HistoryRetention {
+			delete(history, id)
+		}
+	}
+
+	if err := s.save(history); err != nil {
+		return history, err
+	}
+	return history, nil
+}
+
+// SampleDue reports whether a new sample should be recorded after samples,
+// letting callers skip status detection for sessions sampled recently
+func SampleDue(samples []Sample, now time.Time) bool {
+	n := len(samples)
+	return n == 0 || now.Sub(samples[n-1].Time) >= HistoryInterval
+}
+
+func (s *HistoryStore) save(history map[string][]Sample) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to encode status history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create status history directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write status history: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write status history: %w", err)
+	}
+	return nil
+}
+
+// sparkRunes maps statuses to sparkline cells: working sessions stand tall,
+// sessions waiting on the user sit low
+var sparkRunes = map[string]rune{
+	"active":  '▇',
+	"waiting": '▂',
+	"stopped": '▂',
+	"stale":   '▁',
+	"unknown": '·',
+
+	config.StatusRepoMissing: '▁',
+}
+
+// Sparkline renders the samples from the window ending at now as width cells,
+// oldest first. Each cell shows the latest status sampled in its slice of the
+// window; cells without samples are blank.
+func Sparkline(samples []Sample, now time.Time, window time.Duration, width int) string {
+	if width <= 0 || window <= 0 {
+		return ""
+	}
+	cells := []rune(strings.Repeat(" ", width))
+	start := now.Add(-window)
+	bucket := window / time.Duration(width)
+	for _, sample := range samples {
+		if sample.Time.Before(start) || sample.Time.After(now) {
+			continue
+		}
+		i := int(sample.Time.Sub(start) / bucket)
+		if i >= width {
+			i = width - 1
+		}
+		if r, ok := sparkRunes[sample.Status]; ok {
+			cells[i] = r
+		} else {
+			cells[i] = sparkRunes["unknown"]
+		}
+	}
+	return string(cells)
+}
+
+// Trend summarizes the samples from the window ending at now as "working"
+// (active), "waiting" (waiting or stopped) or "idle" (stale or unknown) when at least two
+// thirds of them agree, "mixed" otherwise, and "" without samples
+func Trend(samples []Sample, now time.Time, window time.Duration) string {
+	var total, working, waiting int
+	start := now.Add(-window)
+	for _, sample := range samples {
+		if sample.Time.Before(start) || sample.Time.After(now) {
+			continue
+		}
+		total++
+		switch sample.Status {
+		case "active":
+			working++
+		case "waiting", "stopped":
+			waiting++
+		}
+	}
+	idle := total - working - waiting
+	switch {
+	case total == 0:
+		return ""
+	case working*3 >= total*2:
+		return "working"
+	case waiting*3 >= total*2:
+		return "waiting"
+	case idle*3 >= total*2:
+		return "idle"
+	default:
+		return "mixed"
+	}
+}
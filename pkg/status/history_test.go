@@ -0,0 +1,80 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryStore_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status-history.json")
+	store := NewHistoryStore(path)
+	start := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	history, err := store.Record(map[string]string{"github:1": "active"}, start)
+	require.NoError(t, err)
+	require.Len(t, history["github:1"], 1)
+
+	t.Run("samples_closer_than_interval_are_skipped", func(t *testing.T) {
+		history, err := store.Record(map[string]string{"github:1": "stopped"}, start.Add(10*time.Second))
+		require.NoError(t, err)
+		require.Len(t, history["github:1"], 1)
+		assert.Equal(t, "active", history["github:1"][0].Status)
+	})
+
+	t.Run("ring_is_bounded", func(t *testing.T) {
+		for i := 1; i <= HistoryCapacity+5; i++ {
+			_, err := store.Record(map[string]string{"github:1": "stopped"}, start.Add(time.Duration(i)*HistoryInterval))
+			require.NoError(t, err)
+		}
+		loaded, err := store.Load()
+		require.NoError(t, err)
+		samples := loaded["github:1"]
+		require.Len(t, samples, HistoryCapacity)
+		assert.Equal(t, "stopped", samples[0].Status, "oldest samples are dropped first")
+	})
+
+	t.Run("idle_sessions_expire", func(t *testing.T) {
+		later := start.Add(HistoryRetention + 2*time.Hour)
+		history, err := store.Record(map[string]string{"github:2": "active"}, later)
+		require.NoError(t, err)
+		assert.NotContains(t, history, "github:1")
+		assert.Contains(t, history, "github:2")
+	})
+}
+
+func TestHistoryStore_CorruptFileIsReplaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status-history.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+	store := NewHistoryStore(path)
+
+	_, err := store.Load()
+	assert.Error(t, err)
+
+	history, err := store.Record(map[string]string{"github:1": "active"}, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, history["github:1"], 1)
+}
+
+func TestSparklineAndTrend(t *testing.T) {
+	now := time.Date(2025, 8, 1, 13, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: now.Add(-2 * time.Hour), Status: "stopped"}, // outside the window
+		{Time: now.Add(-55 * time.Minute), Status: "stopped"},
+		{Time: now.Add(-25 * time.Minute), Status: "active"},
+		{Time: now.Add(-5 * time.Minute), Status: "active"},
+		{Time: now, Status: "active"},
+	}
+
+	assert.Equal(t, "▂ ▇▇", Sparkline(samples, now, time.Hour, 4))
+	assert.Equal(t, "", Sparkline(samples, now, time.Hour, 0))
+	assert.Equal(t, "working", Trend(samples, now, time.Hour))
+	assert.Equal(t, "waiting", Trend(samples[:2], now, 3*time.Hour))
+	assert.Equal(t, "mixed", Trend(samples[1:3], now, time.Hour))
+	assert.Equal(t, "idle", Trend([]Sample{{Time: now, Status: "stale"}}, now, time.Hour))
+	assert.Equal(t, "", Trend(nil, now, time.Hour))
+}
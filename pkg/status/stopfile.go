@@ -0,0 +1,145 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// CurrentStopFileVersion is the newest stop.json schema version understood by sbs.
+// Files without a schema_version field are treated as version 1.
+const CurrentStopFileVersion = 1
+
+// QuarantineSuffix is appended to corrupt stop.json files when they are moved aside
+const QuarantineSuffix = ".bad"
+
+// ErrCorruptStopFile indicates a stop.json file that is truncated, malformed or missing required fields
+var ErrCorruptStopFile = errors.New("corrupt stop.json")
+
+// ErrUnsupportedStopFileVersion indicates a stop.json written with a newer schema than sbs understands
+var ErrUnsupportedStopFileVersion = errors.New("unsupported stop.json schema version")
+
+// StopFileInfo holds the validated contents of a stop.json file
+type StopFileInfo struct {
+	Version         int
+	Timestamp       time.Time
+	WaitingForInput bool   // the agent is blocked on the user, e.g. a permission prompt
+	Message         string // what the agent is waiting for, when reported
+}
+
+// ValidateStopFile strictly validates stop.json content against the schema:
+//
+//	{
+//	  "schema_version": 1,                 // optional, defaults to 1
+//	  "timestamp": "<RFC3339>",            // either this ...
+//	  "claude_code_hook": {"timestamp": "<RFC3339>", ...}  // ... or this
+//	}
+//
+// claude_code_hook may also carry "waiting_for_input" (bool) and "message"
+// (string), written when Claude Code notifies that it needs the user.
+//
+// Errors wrap ErrCorruptStopFile or ErrUnsupportedStopFileVersion.
+func ValidateStopFile(data []byte) (StopFileInfo, error) {
+	if len(data) == 0 {
+		return StopFileInfo{}, fmt.Errorf("%w: empty file", ErrCorruptStopFile)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return StopFileInfo{}, fmt.Errorf("%w: invalid JSON: %v", ErrCorruptStopFile, err)
+	}
+	if raw == nil {
+		return StopFileInfo{}, fmt.Errorf("%w: expected a JSON object", ErrCorruptStopFile)
+	}
+
+	info := StopFileInfo{Version: 1}
+	if versionData, exists := raw["schema_version"]; exists {
+		var version int
+		if err := json.Unmarshal(versionData, &version); err != nil || version < 1 {
+			return StopFileInfo{}, fmt.Errorf("%w: schema_version must be a positive integer", ErrCorruptStopFile)
+		}
+		if version > CurrentStopFileVersion {
+			return StopFileInfo{}, fmt.Errorf("%w: %d (newest supported is %d)", ErrUnsupportedStopFileVersion, version, CurrentStopFileVersion)
+		}
+		info.Version = version
+	}
+
+	timestamp, err := stopFileTimestamp(raw)
+	if err != nil {
+		return StopFileInfo{}, err
+	}
+	info.Timestamp = timestamp
+
+	info.WaitingForInput, info.Message, err = stopFileWaiting(raw)
+	if err != nil {
+		return StopFileInfo{}, err
+	}
+
+	return info, nil
+}
+
+// stopFileTimestamp extracts the timestamp from the hook section or the top level
+func stopFileTimestamp(raw map[string]json.RawMessage) (time.Time, error) {
+	if hookData, exists := raw["claude_code_hook"]; exists {
+		var hook map[string]json.RawMessage
+		if err := json.Unmarshal(hookData, &hook); err != nil || hook == nil {
+			return time.Time{}, fmt.Errorf("%w: claude_code_hook must be an object", ErrCorruptStopFile)
+		}
+		if timestampData, exists := hook["timestamp"]; exists {
+			return parseStopTimestamp(timestampData, "claude_code_hook.timestamp")
+		}
+	}
+
+	if timestampData, exists := raw["timestamp"]; exists {
+		return parseStopTimestamp(timestampData, "timestamp")
+	}
+
+	return time.Time{}, fmt.Errorf("%w: no timestamp field", ErrCorruptStopFile)
+}
+
+// stopFileWaiting extracts the waiting-for-input flag and message from the hook section
+func stopFileWaiting(raw map[string]json.RawMessage) (bool, string, error) {
+	var hook struct {
+		WaitingForInput *bool   `json:"waiting_for_input"`
+		Message         *string `json:"message"`
+	}
+	hookData, exists := raw["claude_code_hook"]
+	if !exists {
+		return false, "", nil
+	}
+	if err := json.Unmarshal(hookData, &hook); err != nil {
+		return false, "", fmt.Errorf("%w: claude_code_hook.waiting_for_input must be a boolean and message a string", ErrCorruptStopFile)
+	}
+
+	waiting := hook.WaitingForInput != nil && *hook.WaitingForInput
+	message := ""
+	if waiting && hook.Message != nil {
+		message = *hook.Message
+	}
+	return waiting, message, nil
+}
+
+func parseStopTimestamp(data json.RawMessage, field string) (time.Time, error) {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a string", ErrCorruptStopFile, field)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s is not an RFC3339 timestamp", ErrCorruptStopFile, field)
+	}
+	return timestamp, nil
+}
+
+// QuarantineStopFile moves a corrupt stop.json aside by renaming it with the .bad suffix,
+// replacing any previously quarantined file. It returns the quarantine path.
+func QuarantineStopFile(filePath string) (string, error) {
+	badPath := filePath + QuarantineSuffix
+	if err := os.Rename(filePath, badPath); err != nil {
+		return "", fmt.Errorf("failed to quarantine %s: %w", filePath, err)
+	}
+	return badPath, nil
+}
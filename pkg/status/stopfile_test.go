@@ -0,0 +1,212 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestValidateStopFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantVersion int
+		wantTime    string
+		wantErr     error
+	}{
+		{
+			name:        "hook_format_without_version",
+			content:     `{"claude_code_hook": {"timestamp": "2025-08-01T12:30:45Z", "environment": "sandbox"}}`,
+			wantVersion: 1,
+			wantTime:    "2025-08-01T12:30:45Z",
+		},
+		{
+			name:        "top_level_timestamp_with_version",
+			content:     `{"schema_version": 1, "timestamp": "2025-08-01T10:15:30Z", "status": "stopped"}`,
+			wantVersion: 1,
+			wantTime:    "2025-08-01T10:15:30Z",
+		},
+		{
+			name:        "hook_without_timestamp_uses_top_level",
+			content:     `{"claude_code_hook": {"environment": "sandbox"}, "timestamp": "2025-08-01T10:15:30Z"}`,
+			wantVersion: 1,
+			wantTime:    "2025-08-01T10:15:30Z",
+		},
+		{name: "empty", content: "", wantErr: ErrCorruptStopFile},
+		{name: "truncated", content: `{"timestamp": "2025-08-01T10:15:30Z"`, wantErr: ErrCorruptStopFile},
+		{name: "not_an_object", content: `["2025-08-01T10:15:30Z"]`, wantErr: ErrCorruptStopFile},
+		{name: "null", content: `null`, wantErr: ErrCorruptStopFile},
+		{name: "missing_timestamp", content: `{"status": "stopped"}`, wantErr: ErrCorruptStopFile},
+		{name: "timestamp_wrong_type", content: `{"timestamp": 1722500000}`, wantErr: ErrCorruptStopFile},
+		{name: "timestamp_bad_format", content: `{"timestamp": "yesterday"}`, wantErr: ErrCorruptStopFile},
+		{name: "hook_not_object", content: `{"claude_code_hook": "x"}`, wantErr: ErrCorruptStopFile},
+		{name: "invalid_version", content: `{"schema_version": "one", "timestamp": "2025-08-01T10:15:30Z"}`, wantErr: ErrCorruptStopFile},
+		{name: "zero_version", content: `{"schema_version": 0, "timestamp": "2025-08-01T10:15:30Z"}`, wantErr: ErrCorruptStopFile},
+		{name: "newer_version", content: `{"schema_version": 99, "timestamp": "2025-08-01T10:15:30Z"}`, wantErr: ErrUnsupportedStopFileVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ValidateStopFile([]byte(tt.content))
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, info.Version)
+			assert.Equal(t, tt.wantTime, info.Timestamp.Format(time.RFC3339))
+		})
+	}
+}
+
+func TestQuarantineStopFile(t *testing.T) {
+	dir := t.TempDir()
+	stopFile := filepath.Join(dir, "stop.json")
+	require.NoError(t, os.WriteFile(stopFile, []byte("garbage"), 0644))
+	require.NoError(t, os.WriteFile(stopFile+QuarantineSuffix, []byte("older garbage"), 0644))
+
+	badPath, err := QuarantineStopFile(stopFile)
+	require.NoError(t, err)
+	assert.Equal(t, stopFile+".bad", badPath)
+
+	_, err = os.Stat(stopFile)
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := os.ReadFile(badPath)
+	require.NoError(t, err)
+	assert.Equal(t, "garbage", string(data), "previous quarantine should be replaced")
+
+	_, err = QuarantineStopFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestStatusDetector_CorruptStopFileRecovery(t *testing.T) {
+	setup := func(t *testing.T, content string) (string, string) {
+		worktreePath := t.TempDir()
+		sbsDir := filepath.Join(worktreePath, ".sbs")
+		require.NoError(t, os.MkdirAll(sbsDir, 0755))
+		stopFile := filepath.Join(sbsDir, "stop.json")
+		require.NoError(t, os.WriteFile(stopFile, []byte(content), 0644))
+		return worktreePath, stopFile
+	}
+
+	t.Run("quarantines_and_falls_back_to_tmux", func(t *testing.T) {
+		worktreePath, stopFile := setup(t, `{"timestamp": "2025-08-01T10:15`)
+
+		mockTmux := &MockTmuxManager{}
+		mockTmux.SetSessionExists("sbs-1", true)
+		detector := NewDetector(mockTmux, &MockSandboxManager{})
+		session := config.SessionMetadata{WorktreePath: worktreePath, TmuxSession: "sbs-1"}
+
+		status := detector.DetectSessionStatus(session)
+		assert.Equal(t, "active", status.Status)
+		assert.Contains(t, status.Warning, "corrupt status file moved to")
+
+		_, err := os.Stat(stopFile + QuarantineSuffix)
+		assert.NoError(t, err, "corrupt file should be renamed with .bad suffix")
+		_, err = os.Stat(stopFile)
+		assert.True(t, os.IsNotExist(err))
+
+		// Warning stays visible on later refreshes
+		status = detector.DetectSessionStatus(session)
+		assert.Equal(t, "active", status.Status)
+		assert.Contains(t, status.Warning, "quarantined")
+	})
+
+	t.Run("unknown_without_tmux", func(t *testing.T) {
+		worktreePath, _ := setup(t, `{"status": "stopped"}`)
+
+		detector := NewDetector(&MockTmuxManager{}, &MockSandboxManager{})
+		status := detector.DetectSessionStatus(config.SessionMetadata{WorktreePath: worktreePath})
+
+		assert.Equal(t, "unknown", status.Status)
+		assert.NotEmpty(t, status.Warning)
+		assert.Nil(t, status.LastChange)
+	})
+
+	t.Run("dead_session_with_quarantined_file_is_stale", func(t *testing.T) {
+		worktreePath, stopFile := setup(t, "not json")
+		require.NoError(t, os.Rename(stopFile, stopFile+QuarantineSuffix))
+
+		detector := NewDetector(&MockTmuxManager{}, &MockSandboxManager{})
+		status := detector.DetectSessionStatus(config.SessionMetadata{
+			WorktreePath: worktreePath,
+			TmuxSession:  "sbs-gone",
+			LastActivity: "2025-08-01T10:15:30Z",
+		})
+
+		assert.Equal(t, "stale", status.Status, "a leftover .bad file must not hide that the session is gone")
+		assert.Contains(t, status.Warning, "quarantined at "+stopFile+QuarantineSuffix)
+		require.NotNil(t, status.LastChange)
+		assert.False(t, status.LastChange.IsZero())
+	})
+
+	t.Run("valid_file_after_quarantine_clears_warning", func(t *testing.T) {
+		worktreePath, stopFile := setup(t, "not json")
+
+		detector := NewDetector(&MockTmuxManager{}, &MockSandboxManager{})
+		session := config.SessionMetadata{WorktreePath: worktreePath}
+		detector.DetectSessionStatus(session)
+
+		require.NoError(t, os.WriteFile(stopFile, []byte(`{"schema_version": 1, "timestamp": "2025-08-01T10:15:30Z"}`), 0644))
+		status := detector.DetectSessionStatus(session)
+		assert.Equal(t, "stopped", status.Status)
+		assert.Empty(t, status.Warning)
+	})
+
+	t.Run("newer_schema_is_not_quarantined", func(t *testing.T) {
+		worktreePath, stopFile := setup(t, `{"schema_version": 5, "timestamp": "2025-08-01T10:15:30Z"}`)
+
+		mockTmux := &MockTmuxManager{}
+		mockTmux.SetSessionExists("sbs-2", true)
+		detector := NewDetector(mockTmux, &MockSandboxManager{})
+
+		status := detector.DetectSessionStatus(config.SessionMetadata{WorktreePath: worktreePath, TmuxSession: "sbs-2"})
+		assert.Equal(t, "active", status.Status)
+		assert.Contains(t, status.Warning, "unsupported stop.json schema version")
+
+		_, err := os.Stat(stopFile)
+		assert.NoError(t, err, "files from newer writers should be left in place")
+	})
+}
+
+func TestValidateStopFile_WaitingForInput(t *testing.T) {
+	info, err := ValidateStopFile([]byte(`{"claude_code_hook": {"timestamp": "2025-08-01T12:30:45Z", "hook_type": "Notification", "waiting_for_input": true, "message": "Claude is waiting for your input"}}`))
+	require.NoError(t, err)
+	assert.True(t, info.WaitingForInput)
+	assert.Equal(t, "Claude is waiting for your input", info.Message)
+
+	info, err = ValidateStopFile([]byte(`{"claude_code_hook": {"timestamp": "2025-08-01T12:30:45Z", "message": "ignored without the flag"}}`))
+	require.NoError(t, err)
+	assert.False(t, info.WaitingForInput)
+	assert.Empty(t, info.Message)
+
+	_, err = ValidateStopFile([]byte(`{"claude_code_hook": {"timestamp": "2025-08-01T12:30:45Z", "waiting_for_input": "yes"}}`))
+	assert.ErrorIs(t, err, ErrCorruptStopFile)
+}
+
+func TestStatusDetector_WaitingForInput(t *testing.T) {
+	worktreePath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(worktreePath, ".sbs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, ".sbs", "stop.json"),
+		[]byte(`{"claude_code_hook": {"timestamp": "2025-08-01T12:30:45Z", "waiting_for_input": true, "message": "Claude needs your permission to use Bash"}}`), 0644))
+
+	mockTmux := &MockTmuxManager{}
+	mockTmux.SetSessionExists("sbs-1", true)
+	detector := NewDetector(mockTmux, &MockSandboxManager{})
+
+	status := detector.DetectSessionStatus(config.SessionMetadata{WorktreePath: worktreePath, TmuxSession: "sbs-1"})
+	assert.Equal(t, "waiting", status.Status)
+	assert.Equal(t, "Claude needs your permission to use Bash", status.Message)
+	require.NotNil(t, status.LastChange)
+	assert.Equal(t, 2025, status.LastChange.Year())
+}
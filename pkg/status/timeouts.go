@@ -0,0 +1,69 @@
+package status
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"sbs/pkg/config"
+)
+
+// DefaultStatusTimeout bounds status work when no timeout is configured
+const DefaultStatusTimeout = 10 * time.Second
+
+// repoTimeout is a repository's status timeout as of its config file's modification time
+type repoTimeout struct {
+	modTime time.Time
+	timeout time.Duration
+}
+
+// TimeoutResolver returns the status timeout for a session's repository:
+// status_timeout_seconds from the repository's .sbs/config.json when set,
+// else the default. Repository configs are re-read only when they change.
+type TimeoutResolver struct {
+	defaultTimeout time.Duration
+
+	mu    sync.Mutex
+	repos map[string]repoTimeout
+}
+
+// NewTimeoutResolver creates a resolver falling back to defaultSeconds, or
+// DefaultStatusTimeout when that isn't positive
+func NewTimeoutResolver(defaultSeconds int) *TimeoutResolver {
+	defaultTimeout := DefaultStatusTimeout
+	if defaultSeconds > 0 {
+		defaultTimeout = time.Duration(defaultSeconds) * time.Second
+	}
+	return &TimeoutResolver{
+		defaultTimeout: defaultTimeout,
+		repos:          make(map[string]repoTimeout),
+	}
+}
+
+// Timeout returns the status timeout for the repository at repoRoot
+func (r *TimeoutResolver) Timeout(repoRoot string) time.Duration {
+	if r == nil {
+		return DefaultStatusTimeout
+	}
+	if repoRoot == "" {
+		return r.defaultTimeout
+	}
+
+	info, err := os.Stat(config.RepositoryConfigPath(repoRoot))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		delete(r.repos, repoRoot)
+		return r.defaultTimeout
+	}
+	if cached, ok := r.repos[repoRoot]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.timeout
+	}
+
+	timeout := r.defaultTimeout
+	if repoConfig, err := config.LoadRepositoryConfig(repoRoot); err == nil && repoConfig.StatusTimeoutSeconds > 0 {
+		timeout = time.Duration(repoConfig.StatusTimeoutSeconds) * time.Second
+	}
+	r.repos[repoRoot] = repoTimeout{modTime: info.ModTime(), timeout: timeout}
+	return timeout
+}
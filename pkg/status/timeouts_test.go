@@ -0,0 +1,45 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func writeRepoConfig(t *testing.T, repoRoot, content string, modTime time.Time) {
+	t.Helper()
+	path := config.RepositoryConfigPath(repoRoot)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestTimeoutResolver(t *testing.T) {
+	resolver := NewTimeoutResolver(5)
+	assert.Equal(t, 5*time.Second, resolver.Timeout(""))
+
+	repoRoot := t.TempDir()
+	assert.Equal(t, 5*time.Second, resolver.Timeout(repoRoot), "no repository config")
+
+	modTime := time.Now().Add(-time.Minute)
+	writeRepoConfig(t, repoRoot, `{"status_timeout_seconds": 20}`, modTime)
+	assert.Equal(t, 20*time.Second, resolver.Timeout(repoRoot))
+
+	writeRepoConfig(t, repoRoot, `{"status_timeout_seconds": 2}`, time.Now())
+	assert.Equal(t, 2*time.Second, resolver.Timeout(repoRoot), "changed config is re-read")
+
+	writeRepoConfig(t, repoRoot, `{}`, modTime)
+	assert.Equal(t, 5*time.Second, resolver.Timeout(repoRoot), "unset falls back to the default")
+}
+
+func TestTimeoutResolver_Defaults(t *testing.T) {
+	var resolver *TimeoutResolver
+	assert.Equal(t, DefaultStatusTimeout, resolver.Timeout("/repo"))
+	assert.Equal(t, DefaultStatusTimeout, NewTimeoutResolver(0).Timeout(""))
+}
@@ -0,0 +1,325 @@
+// Package testsupport provides in-memory fakes of the tmux, sandbox and
+// cleanup services so TUI and command behavior can be unit-tested without
+// real tmux servers or sandboxes.
+package testsupport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"sbs/pkg/cleanup"
+	"sbs/pkg/config"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/tmux"
+)
+
+// FakeTmuxManager records tmux operations against an in-memory session set
+type FakeTmuxManager struct {
+	mu       sync.Mutex
+	sessions map[string]bool
+
+	// Errors returned by the corresponding operations when set
+	ExistsErr error
+	KillErr   error
+	ListErr   error
+	AttachErr error
+
+	// Recorded calls
+	Killed   []string
+	Attached []string
+}
+
+// NewFakeTmuxManager creates a fake with the given running sessions
+func NewFakeTmuxManager(sessions ...string) *FakeTmuxManager {
+	f := &FakeTmuxManager{sessions: make(map[string]bool)}
+	for _, name := range sessions {
+		f.sessions[name] = true
+	}
+	return f
+}
+
+// AddSession marks a session as running
+func (f *FakeTmuxManager) AddSession(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions[name] = true
+}
+
+// SessionExists reports whether the session is running
+func (f *FakeTmuxManager) SessionExists(sessionName string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ExistsErr != nil {
+		return false, f.ExistsErr
+	}
+	return f.sessions[sessionName], nil
+}
+
+// KillSession removes the session and records the call
+func (f *FakeTmuxManager) KillSession(sessionName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.KillErr != nil {
+		return f.KillErr
+	}
+	f.Killed = append(f.Killed, sessionName)
+	delete(f.sessions, sessionName)
+	return nil
+}
+
+// ListSessionNames returns running session names in sorted order
+func (f *FakeTmuxManager) ListSessionNames() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ListErr != nil {
+		return nil, f.ListErr
+	}
+	names := make([]string, 0, len(f.sessions))
+	for name := range f.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListSessions returns running sessions in name order
+func (f *FakeTmuxManager) ListSessions() ([]*tmux.Session, error) {
+	names, err := f.ListSessionNames()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*tmux.Session, 0, len(names))
+	for _, name := range names {
+		sessions = append(sessions, &tmux.Session{Name: name})
+	}
+	return sessions, nil
+}
+
+// AttachToSession records the attach request
+func (f *FakeTmuxManager) AttachToSession(sessionName string, env ...map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.AttachErr != nil {
+		return f.AttachErr
+	}
+	if !f.sessions[sessionName] {
+		return fmt.Errorf("tmux session %s does not exist", sessionName)
+	}
+	f.Attached = append(f.Attached, sessionName)
+	return nil
+}
+
+// FakeSandboxManager records sandbox operations against in-memory sandboxes
+type FakeSandboxManager struct {
+	mu        sync.Mutex
+	sandboxes map[string]map[string][]byte // sandbox name -> file path -> content
+
+	// Errors returned by the corresponding operations when set
+	ExistsErr   error
+	DeleteErr   error
+	SnapshotErr error
+
+	// NoSnapshots makes the fake a provider without snapshot support
+	NoSnapshots bool
+
+	// Recorded calls
+	Deleted   []string
+	Snapshots []string // "sandbox@snapshot" per Snapshot call
+	Restored  []string // "sandbox@snapshot" per RestoreSnapshot call
+}
+
+// NewFakeSandboxManager creates a fake with the given existing sandboxes
+func NewFakeSandboxManager(sandboxes ...string) *FakeSandboxManager {
+	f := &FakeSandboxManager{sandboxes: make(map[string]map[string][]byte)}
+	for _, name := range sandboxes {
+		f.sandboxes[name] = make(map[string][]byte)
+	}
+	return f
+}
+
+// SetFile creates the sandbox if needed and stores a file in it
+func (f *FakeSandboxManager) SetFile(sandboxName, filePath string, content []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sandboxes[sandboxName] == nil {
+		f.sandboxes[sandboxName] = make(map[string][]byte)
+	}
+	f.sandboxes[sandboxName][filePath] = content
+}
+
+// SandboxExists reports whether the sandbox exists
+func (f *FakeSandboxManager) SandboxExists(sandboxName string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ExistsErr != nil {
+		return false, f.ExistsErr
+	}
+	_, ok := f.sandboxes[sandboxName]
+	return ok, nil
+}
+
+// ListSandboxes returns the names of the existing sandboxes, sorted
+func (f *FakeSandboxManager) ListSandboxes() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(f.sandboxes))
+	for name := range f.sandboxes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteSandbox removes the sandbox and records the call
+func (f *FakeSandboxManager) DeleteSandbox(sandboxName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.DeleteErr != nil {
+		return f.DeleteErr
+	}
+	f.Deleted = append(f.Deleted, sandboxName)
+	delete(f.sandboxes, sandboxName)
+	return nil
+}
+
+// SupportsSnapshots reports whether the fake offers snapshots
+func (f *FakeSandboxManager) SupportsSnapshots() bool {
+	return !f.NoSnapshots
+}
+
+// Snapshot records the snapshot of an existing sandbox
+func (f *FakeSandboxManager) Snapshot(sandboxName, snapshot string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.NoSnapshots {
+		return sandbox.ErrSnapshotsUnsupported
+	}
+	if f.SnapshotErr != nil {
+		return f.SnapshotErr
+	}
+	if _, ok := f.sandboxes[sandboxName]; !ok {
+		return fmt.Errorf("sandbox %s does not exist", sandboxName)
+	}
+	f.Snapshots = append(f.Snapshots, sandboxName+"@"+snapshot)
+	return nil
+}
+
+// RestoreSnapshot recreates the sandbox and records the call
+func (f *FakeSandboxManager) RestoreSnapshot(sandboxName, snapshot string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.NoSnapshots {
+		return sandbox.ErrSnapshotsUnsupported
+	}
+	if f.sandboxes[sandboxName] == nil {
+		f.sandboxes[sandboxName] = make(map[string][]byte)
+	}
+	f.Restored = append(f.Restored, sandboxName+"@"+snapshot)
+	return nil
+}
+
+// ReadFileFromSandbox returns a file previously stored with SetFile
+func (f *FakeSandboxManager) ReadFileFromSandbox(sandboxName, filePath string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	files, ok := f.sandboxes[sandboxName]
+	if !ok {
+		return nil, fmt.Errorf("sandbox %s does not exist", sandboxName)
+	}
+	content, ok := files[filePath]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found in sandbox %s", filePath, sandboxName)
+	}
+	return content, nil
+}
+
+// FakeSessionCleaner returns canned stale sessions and records cleanups
+type FakeSessionCleaner struct {
+	mu sync.Mutex
+
+	Stale        []config.SessionMetadata
+	StaleReasons map[string]string // Reason by namespaced ID for ExplainStaleSessionsInView
+	IdentifyErr  error
+	CleanupErr   error
+
+	// Recorded calls
+	Cleaned [][]config.SessionMetadata
+}
+
+// IdentifyStaleSessionsInView returns the configured stale sessions
+func (f *FakeSessionCleaner) IdentifyStaleSessionsInView(ctx context.Context, sessions []config.SessionMetadata, viewMode cleanup.ViewMode) ([]config.SessionMetadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Stale, f.IdentifyErr
+}
+
+// ExplainStaleSessionsInView returns the configured stale sessions with
+// StaleReasons, defaulting to the tmux reason
+func (f *FakeSessionCleaner) ExplainStaleSessionsInView(ctx context.Context, sessions []config.SessionMetadata, viewMode cleanup.ViewMode) ([]cleanup.StaleSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var explained []cleanup.StaleSession
+	for _, session := range f.Stale {
+		reason := f.StaleReasons[session.NamespacedID]
+		if reason == "" {
+			reason = cleanup.StaleReasonTmuxGone
+		}
+		explained = append(explained, cleanup.StaleSession{Session: session, Reason: reason})
+	}
+	return explained, f.IdentifyErr
+}
+
+// CleanupSessions records the sessions and reports them all as cleaned
+func (f *FakeSessionCleaner) CleanupSessions(ctx context.Context, sessions []config.SessionMetadata, options cleanup.CleanupOptions) (cleanup.CleanupResults, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.CleanupErr != nil {
+		return cleanup.CleanupResults{}, f.CleanupErr
+	}
+	f.Cleaned = append(f.Cleaned, sessions)
+	results := cleanup.CleanupResults{CleanedSessions: len(sessions)}
+	for _, session := range sessions {
+		results.Actions = append(results.Actions, cleanup.Action{
+			SessionID: session.NamespacedID,
+			Title:     session.IssueTitle,
+			Resource:  cleanup.ResourceSandbox,
+			Target:    session.SandboxName,
+			Outcome:   cleanup.OutcomeRemoved,
+		})
+	}
+	return results, nil
+}
+
+// StartBatch runs CleanupSessions as a batch, reporting every session as
+// cleaned
+func (f *FakeSessionCleaner) StartBatch(ctx context.Context, sessions []config.SessionMetadata, options cleanup.CleanupOptions) *cleanup.Batch {
+	return cleanup.NewBatch(ctx, len(sessions), func(ctx context.Context, finished func(cleanup.SessionResult)) (cleanup.CleanupResults, error) {
+		results, err := f.CleanupSessions(ctx, sessions, options)
+		if err != nil {
+			return results, err
+		}
+		for i, session := range sessions {
+			finished(cleanup.SessionResult{Session: session, Actions: results.Actions[i : i+1], Cleaned: true})
+		}
+		return results, nil
+	})
+}
+
+// BuildTUICleanupOptions returns the TUI defaults
+func (f *FakeSessionCleaner) BuildTUICleanupOptions(viewMode cleanup.ViewMode, silent bool) cleanup.CleanupOptions {
+	return cleanup.CleanupOptions{CleanSandboxes: true, CleanWorktrees: true, ViewMode: viewMode, SilentMode: silent}
+}
+
+// BuildCLICleanupOptions returns the CLI defaults
+func (f *FakeSessionCleaner) BuildCLICleanupOptions(dryRun, force bool, mode cleanup.CleanupMode) cleanup.CleanupOptions {
+	return cleanup.CleanupOptions{CleanSandboxes: true, DryRun: dryRun, Force: force, ViewMode: cleanup.ViewModeGlobal}
+}
+
+// ResolveSandboxName returns the session's recorded sandbox name
+func (f *FakeSessionCleaner) ResolveSandboxName(session config.SessionMetadata) string {
+	return session.SandboxName
+}
+
+var _ cleanup.SessionCleaner = (*FakeSessionCleaner)(nil)
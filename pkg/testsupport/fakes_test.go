@@ -0,0 +1,74 @@
+package testsupport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/cleanup"
+	"sbs/pkg/sandbox"
+	"sbs/pkg/status"
+)
+
+// The fakes plug into the same interfaces as the real managers
+var (
+	_ cleanup.TmuxManager    = (*FakeTmuxManager)(nil)
+	_ cleanup.SandboxManager = (*FakeSandboxManager)(nil)
+	_ status.TmuxManager     = (*FakeTmuxManager)(nil)
+	_ status.SandboxManager  = (*FakeSandboxManager)(nil)
+	_ sandbox.Snapshotter    = (*FakeSandboxManager)(nil)
+)
+
+func TestFakeTmuxManager(t *testing.T) {
+	f := NewFakeTmuxManager("sbs-b", "sbs-a")
+
+	names, err := f.ListSessionNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sbs-a", "sbs-b"}, names)
+
+	exists, err := f.SessionExists("sbs-a")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, f.KillSession("sbs-a"))
+	exists, _ = f.SessionExists("sbs-a")
+	assert.False(t, exists)
+	assert.Equal(t, []string{"sbs-a"}, f.Killed)
+
+	assert.Error(t, f.AttachToSession("sbs-a"))
+	require.NoError(t, f.AttachToSession("sbs-b"))
+	assert.Equal(t, []string{"sbs-b"}, f.Attached)
+}
+
+func TestFakeSandboxManager(t *testing.T) {
+	f := NewFakeSandboxManager("sbs-repo-1")
+	f.SetFile("sbs-repo-2", ".sbs/stop.json", []byte("{}"))
+
+	exists, err := f.SandboxExists("sbs-repo-2")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	content, err := f.ReadFileFromSandbox("sbs-repo-2", ".sbs/stop.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(content))
+
+	_, err = f.ReadFileFromSandbox("sbs-repo-1", ".sbs/stop.json")
+	assert.Error(t, err)
+
+	require.NoError(t, f.DeleteSandbox("sbs-repo-1"))
+	exists, _ = f.SandboxExists("sbs-repo-1")
+	assert.False(t, exists)
+	assert.Equal(t, []string{"sbs-repo-1"}, f.Deleted)
+
+	require.NoError(t, f.Snapshot("sbs-repo-2", "s1"))
+	assert.Error(t, f.Snapshot("sbs-repo-1", "s1"), "deleted sandboxes can't be snapshotted")
+	require.NoError(t, f.RestoreSnapshot("sbs-repo-1", "s1"))
+	exists, _ = f.SandboxExists("sbs-repo-1")
+	assert.True(t, exists, "restoring recreates the sandbox")
+	assert.Equal(t, []string{"sbs-repo-2@s1"}, f.Snapshots)
+	assert.Equal(t, []string{"sbs-repo-1@s1"}, f.Restored)
+
+	f.NoSnapshots = true
+	assert.ErrorIs(t, f.Snapshot("sbs-repo-2", "s2"), sandbox.ErrSnapshotsUnsupported)
+}
@@ -0,0 +1,68 @@
+package timing
+
+import (
+	"context"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/config"
+)
+
+// commandLogger passes external commands on to the command log and records
+// the durations of the operations the store tracks
+type commandLogger struct {
+	next  cmdlog.Logger
+	store *Store
+}
+
+// WrapLogger returns a command logger that records tracked operations in
+// store before handing every command to next. A nil next records only.
+func WrapLogger(next cmdlog.Logger, store *Store) cmdlog.Logger {
+	if next == nil {
+		next = cmdlog.NewCommandLogger(cmdlog.Config{})
+	}
+	return &commandLogger{next: next, store: store}
+}
+
+func (l *commandLogger) LogCommand(command string, args []string, caller string) cmdlog.CommandContext {
+	return l.LogCommandContext(context.Background(), command, args, caller)
+}
+
+func (l *commandLogger) LogCommandContext(ctx context.Context, command string, args []string, caller string) cmdlog.CommandContext {
+	var next cmdlog.CommandContext
+	if contextLogger, ok := l.next.(cmdlog.ContextLogger); ok {
+		next = contextLogger.LogCommandContext(ctx, command, args, caller)
+	} else {
+		next = l.next.LogCommand(command, args, caller)
+	}
+	op := Operation(command, args)
+	if op == "" {
+		return next
+	}
+	return &commandContext{next: next, store: l.store, op: op}
+}
+
+// IsEnabled is always true: tracked operations are timed even when the
+// command log is off
+func (l *commandLogger) IsEnabled() bool {
+	return true
+}
+
+func (l *commandLogger) GetLevel() string {
+	return l.next.GetLevel()
+}
+
+// commandContext records a tracked operation once it completes successfully;
+// failures say nothing about how long the operation normally takes
+type commandContext struct {
+	next  cmdlog.CommandContext
+	store *Store
+	op    string
+}
+
+func (c *commandContext) LogCompletion(success bool, exitCode int, errorMsg string, duration time.Duration) {
+	c.next.LogCompletion(success, exitCode, errorMsg, duration)
+	if success {
+		_ = c.store.Record(c.op, duration, config.Now())
+	}
+}
@@ -0,0 +1,255 @@
+// Package timing records how long slow external commands take and flags the
+// ones that regularly run over their budget, so users can be pointed at the
+// features that avoid them (sparse checkouts, the prewarmed sandbox pool).
+package timing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"sbs/pkg/paths"
+)
+
+// Operations whose durations are recorded
+const (
+	OpWorktreeAdd   = "git worktree add"
+	OpSandboxCreate = "sandbox create"
+)
+
+const (
+	// Capacity is the number of samples kept per operation
+	Capacity = 20
+	// MinSamples is how many samples an operation needs before it is judged
+	MinSamples = 3
+	// HintInterval is the minimum spacing between TUI hints
+	HintInterval = 24 * time.Hour
+)
+
+// DefaultBudgets are the budgets in seconds used when timing_budgets_seconds
+// doesn't set an operation
+var DefaultBudgets = map[string]int{
+	OpWorktreeAdd:   30,
+	OpSandboxCreate: 60,
+}
+
+// hints suggest what to do about an operation that is chronically slow
+var hints = map[string]string{
+	OpWorktreeAdd:   "large checkouts are slow to create; a sparse checkout or 'git maintenance start' in the main repository can help",
+	OpSandboxCreate: "raise sandbox_pool_size and run 'sbs pool watch' so starts claim a warm sandbox instead of waiting for one",
+}
+
+// Operations returns the names of the recorded operations, sorted
+func Operations() []string {
+	ops := make([]string, 0, len(DefaultBudgets))
+	for op := range DefaultBudgets {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// Operation returns the recorded operation an external command performs, or
+// "" when it is not one. Git commands are logged without -C, and running a
+// no-op command under a new name is how the sandbox pool creates sandboxes.
+func Operation(command string, args []string) string {
+	switch command {
+	case "git":
+		if len(args) >= 2 && args[0] == "worktree" && args[1] == "add" {
+			return OpWorktreeAdd
+		}
+	case "sandbox":
+		if len(args) == 3 && args[0] == "--name" && args[2] == "true" {
+			return OpSandboxCreate
+		}
+	}
+	return ""
+}
+
+// Budgets merges configured budgets in seconds over DefaultBudgets. A budget
+// of 0 turns the check off for that operation.
+func Budgets(configured map[string]int) map[string]time.Duration {
+	budgets := make(map[string]time.Duration, len(DefaultBudgets))
+	for op, secs := range DefaultBudgets {
+		budgets[op] = time.Duration(secs) * time.Second
+	}
+	for op, secs := range configured {
+		if secs <= 0 {
+			delete(budgets, op)
+			continue
+		}
+		budgets[op] = time.Duration(secs) * time.Second
+	}
+	return budgets
+}
+
+// Sample is one completed run of an operation
+type Sample struct {
+	Time     time.Time     `json:"t"`
+	Duration time.Duration `json:"d"`
+}
+
+// History is the content of the timings file
+type History struct {
+	Samples map[string][]Sample `json:"samples"`
+	Hinted  time.Time           `json:"hinted,omitempty"` // When the TUI last showed a hint
+}
+
+// Store persists a ring of samples per operation in a single JSON file. It is
+// safe for concurrent use within a process; concurrent sbs processes may
+// lose each other's samples, which only delays a finding.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns timings.json in the state directory
+func DefaultPath() (string, error) {
+	return paths.StatePath("timings.json")
+}
+
+// Load returns the recorded history; a missing file yields an empty one
+func (s *Store) Load() (History, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *Store) load() (History, error) {
+	history := History{Samples: make(map[string][]Sample)}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return history, fmt.Errorf("failed to read command timings: %w", err)
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return History{Samples: make(map[string][]Sample)}, fmt.Errorf("failed to parse command timings %s: %w", s.path, err)
+	}
+	if history.Samples == nil {
+		history.Samples = make(map[string][]Sample)
+	}
+	return history, nil
+}
+
+// Record adds a sample for op, keeping the newest Capacity samples. A
+// corrupt timings file is replaced rather than reported.
+func (s *Store) Record(op string, duration time.Duration, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, _ := s.load()
+	samples := append(history.Samples[op], Sample{Time: now, Duration: duration})
+	if len(samples) > Capacity {
+		samples = samples[len(samples)-Capacity:]
+	}
+	history.Samples[op] = samples
+	return s.save(history)
+}
+
+// ClaimHint reports whether a hint may be shown at now, at most once per
+// HintInterval, and records it as shown when it may
+func (s *Store) ClaimHint(now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	if !history.Hinted.IsZero() && now.Sub(history.Hinted) < HintInterval {
+		return false, nil
+	}
+	history.Hinted = now
+	if err := s.save(history); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) save(history History) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to encode command timings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create command timings directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write command timings: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write command timings: %w", err)
+	}
+	return nil
+}
+
+// Finding is an operation that regularly runs over its budget
+type Finding struct {
+	Operation string
+	Budget    time.Duration
+	Median    time.Duration
+	Over      int // Samples over budget
+	Samples   int
+	Hint      string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s took longer than %s in %d of the last %d runs (median %s)",
+		f.Operation, f.Budget, f.Over, f.Samples, f.Median.Round(time.Second))
+}
+
+// Slow returns the operations with at least MinSamples samples, half or more
+// of them over budget, sorted by operation
+func Slow(history History, budgets map[string]time.Duration) []Finding {
+	var findings []Finding
+	for _, op := range sortedKeys(history.Samples) {
+		budget, ok := budgets[op]
+		samples := history.Samples[op]
+		if !ok || len(samples) < MinSamples {
+			continue
+		}
+		over := 0
+		durations := make([]time.Duration, len(samples))
+		for i, sample := range samples {
+			durations[i] = sample.Duration
+			if sample.Duration > budget {
+				over++
+			}
+		}
+		if over*2 < len(samples) {
+			continue
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		findings = append(findings, Finding{
+			Operation: op,
+			Budget:    budget,
+			Median:    durations[len(durations)/2],
+			Over:      over,
+			Samples:   len(samples),
+			Hint:      hints[op],
+		})
+	}
+	return findings
+}
+
+func sortedKeys(samples map[string][]Sample) []string {
+	keys := make([]string, 0, len(samples))
+	for key := range samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
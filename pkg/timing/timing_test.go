@@ -0,0 +1,119 @@
+package timing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+)
+
+func TestOperation(t *testing.T) {
+	assert.Equal(t, OpWorktreeAdd, Operation("git", []string{"worktree", "add", "/w/issue-1", "issue-1"}))
+	assert.Equal(t, OpSandboxCreate, Operation("sandbox", []string{"--name", "sbs-pool-1", "true"}))
+	assert.Empty(t, Operation("git", []string{"worktree", "list"}))
+	assert.Empty(t, Operation("sandbox", []string{"--name", "sbs-pool-1", "cat", "/tmp/x"}))
+	assert.Empty(t, Operation("tmux", []string{"new-session"}))
+}
+
+func TestBudgets(t *testing.T) {
+	budgets := Budgets(map[string]int{OpWorktreeAdd: 45, OpSandboxCreate: 0})
+	assert.Equal(t, map[string]time.Duration{OpWorktreeAdd: 45 * time.Second}, budgets, "0 turns a default off")
+	assert.Equal(t, 60*time.Second, Budgets(nil)[OpSandboxCreate])
+	assert.Equal(t, []string{OpWorktreeAdd, OpSandboxCreate}, Operations())
+}
+
+func TestStore_Record(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "timings.json"))
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	history, err := store.Load()
+	require.NoError(t, err, "a missing file is an empty history")
+	assert.Empty(t, history.Samples)
+
+	for i := 0; i < Capacity+5; i++ {
+		require.NoError(t, store.Record(OpWorktreeAdd, time.Duration(i)*time.Second, now.Add(time.Duration(i)*time.Minute)))
+	}
+	history, err = store.Load()
+	require.NoError(t, err)
+	samples := history.Samples[OpWorktreeAdd]
+	require.Len(t, samples, Capacity)
+	assert.Equal(t, 5*time.Second, samples[0].Duration, "the oldest samples are dropped")
+
+	require.NoError(t, os.WriteFile(store.path, []byte("{"), 0600))
+	_, err = store.Load()
+	assert.ErrorContains(t, err, "failed to parse command timings")
+	require.NoError(t, store.Record(OpSandboxCreate, time.Second, now), "a corrupt file is replaced")
+	history, err = store.Load()
+	require.NoError(t, err)
+	assert.Len(t, history.Samples[OpSandboxCreate], 1)
+}
+
+func TestStore_ClaimHint(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "timings.json"))
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	claimed, err := store.ClaimHint(now)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = store.ClaimHint(now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, claimed, "one hint per interval")
+
+	claimed, err = store.ClaimHint(now.Add(HintInterval))
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestSlow(t *testing.T) {
+	samples := func(secs ...int) []Sample {
+		var out []Sample
+		for _, s := range secs {
+			out = append(out, Sample{Duration: time.Duration(s) * time.Second})
+		}
+		return out
+	}
+	history := History{Samples: map[string][]Sample{
+		OpWorktreeAdd:   samples(10, 40, 45, 50),
+		OpSandboxCreate: samples(90, 20, 30),
+	}}
+
+	findings := Slow(history, Budgets(nil))
+	require.Len(t, findings, 1, "sandbox create is over budget in only one of three runs")
+	assert.Equal(t, Finding{
+		Operation: OpWorktreeAdd,
+		Budget:    30 * time.Second,
+		Median:    45 * time.Second,
+		Over:      3,
+		Samples:   4,
+		Hint:      hints[OpWorktreeAdd],
+	}, findings[0])
+	assert.Equal(t, "git worktree add took longer than 30s in 3 of the last 4 runs (median 45s)", findings[0].String())
+
+	assert.Empty(t, Slow(history, Budgets(map[string]int{OpWorktreeAdd: 0})), "disabled budgets are not checked")
+	assert.Empty(t, Slow(History{Samples: map[string][]Sample{OpWorktreeAdd: samples(40, 50)}}, Budgets(nil)), "too few samples")
+}
+
+func TestWrapLogger(t *testing.T) {
+	config.SetClock(clock.NewFake(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { config.SetClock(nil) })
+	store := NewStore(filepath.Join(t.TempDir(), "timings.json"))
+	logger := WrapLogger(nil, store)
+	assert.True(t, logger.IsEnabled())
+
+	logger.LogCommand("git", []string{"worktree", "add", "/w/issue-1", "issue-1"}, "test").LogCompletion(true, 0, "", 40*time.Second)
+	logger.LogCommand("git", []string{"worktree", "add", "/w/issue-2", "issue-2"}, "test").LogCompletion(false, 128, "fatal", time.Second)
+	logger.LogCommand("git", []string{"status"}, "test").LogCompletion(true, 0, "", time.Second)
+
+	history, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]Sample{
+		OpWorktreeAdd: {{Time: config.Now(), Duration: 40 * time.Second}},
+	}, history.Samples, "only successful runs of tracked operations are recorded")
+}
@@ -0,0 +1,51 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+)
+
+// DefaultAttachEnvironment are the variables copied into a session on attach
+// when attach_environment is unset: the ones a new SSH connection or X
+// session changes
+var DefaultAttachEnvironment = []string{"DISPLAY", "SSH_AUTH_SOCK", "SSH_CONNECTION", "XAUTHORITY"}
+
+// ResolveAttachEnvironment returns the variables attach_environment selects:
+// the defaults when unset, none for ["none"]
+func ResolveAttachEnvironment(configured []string) []string {
+	switch {
+	case len(configured) == 0:
+		return DefaultAttachEnvironment
+	case len(configured) == 1 && configured[0] == "none":
+		return nil
+	default:
+		return configured
+	}
+}
+
+// WithAttachEnvironment returns a copy of the manager that refreshes names in
+// a session's environment before attaching to it
+func (m *Manager) WithAttachEnvironment(names []string) *Manager {
+	c := *m
+	c.attachEnv = names
+	return &c
+}
+
+// RefreshEnvironment copies names from this process's environment into the
+// session's, so windows and panes opened after a reattach from a new SSH
+// connection get the current agent socket and display. Variables unset here
+// are marked removed in the session rather than left pointing at a closed
+// connection. Processes already running keep their environment; a shell can
+// pick the values up with: eval "$(tmux show-environment -s)".
+func (m *Manager) RefreshEnvironment(sessionName string, names []string) error {
+	for _, name := range names {
+		args := []string{"set-environment", "-t", sessionName, "-r", name}
+		if value, ok := os.LookupEnv(name); ok {
+			args = []string{"set-environment", "-t", sessionName, name, value}
+		}
+		if err := m.runTmuxCommandRun(args); err != nil {
+			return fmt.Errorf("failed to refresh %s in session %s: %w", name, sessionName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package tmux
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAttachEnvironment(t *testing.T) {
+	assert.Equal(t, DefaultAttachEnvironment, ResolveAttachEnvironment(nil))
+	assert.Empty(t, ResolveAttachEnvironment([]string{"none"}))
+	assert.Equal(t, []string{"SSH_AUTH_SOCK"}, ResolveAttachEnvironment([]string{"SSH_AUTH_SOCK"}))
+}
+
+func TestManager_RefreshEnvironment(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available")
+	}
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+	output, err := exec.Command("tmux", "new-session", "-d", "-s", "sbs-env-1").CombinedOutput()
+	require.NoError(t, err, "%s", output)
+	for _, args := range [][]string{
+		{"set-environment", "-t", "sbs-env-1", "SSH_AUTH_SOCK", "/tmp/ssh-old/agent.1"},
+		{"set-environment", "-t", "sbs-env-1", "DISPLAY", "localhost:10.0"},
+	} {
+		output, err := exec.Command("tmux", args...).CombinedOutput()
+		require.NoError(t, err, "%s", output)
+	}
+
+	// The new connection has a fresh agent and no X forwarding
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/ssh-new/agent.2")
+	t.Setenv("DISPLAY", "") // Restored after the test
+	require.NoError(t, os.Unsetenv("DISPLAY"))
+	manager := NewManager()
+	require.NoError(t, manager.RefreshEnvironment("sbs-env-1", []string{"SSH_AUTH_SOCK", "DISPLAY"}))
+
+	show, err := exec.Command("tmux", "show-environment", "-t", "sbs-env-1").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/ssh-new/agent.2", parseEnvironmentValue(string(show), "SSH_AUTH_SOCK"))
+	assert.Contains(t, string(show), "-DISPLAY\n", "a variable the terminal lacks is removed")
+
+	assert.Error(t, manager.RefreshEnvironment("sbs-env-missing", []string{"SSH_AUTH_SOCK"}))
+}
@@ -0,0 +1,467 @@
+package tmux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"sbs/pkg/cmdlog"
+)
+
+// ControlEventType identifies a notification received from a tmux control-mode client
+type ControlEventType string
+
+const (
+	EventSessionsChanged ControlEventType = "sessions-changed" // A session was created or destroyed
+	EventSessionRenamed  ControlEventType = "session-renamed"
+	EventSessionChanged  ControlEventType = "session-changed" // The control client switched sessions
+	EventWindowAdd       ControlEventType = "window-add"
+	EventWindowClose     ControlEventType = "window-close"
+	EventWindowRenamed   ControlEventType = "window-renamed"
+	EventActivity        ControlEventType = "activity" // Output was written to a pane
+	EventExit            ControlEventType = "exit"     // The control client is exiting
+)
+
+// DefaultActivityInterval limits how often activity events are emitted per pane
+const DefaultActivityInterval = time.Second
+
+// ControlEvent is a parsed tmux control-mode notification
+type ControlEvent struct {
+	Type      ControlEventType
+	SessionID string // e.g. "$1"
+	WindowID  string // e.g. "@2"
+	PaneID    string // e.g. "%3"
+	Name      string // Session or window name, when the notification carries one
+	Reason    string // Exit reason for EventExit
+	Session   string // Session the reporting client is attached to, set by ControlMux
+	Time      time.Time
+}
+
+// IsSessionLifecycle reports whether the event may change which sessions exist
+func (e ControlEvent) IsSessionLifecycle() bool {
+	switch e.Type {
+	case EventSessionsChanged, EventSessionRenamed, EventWindowClose, EventExit:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseControlLine parses a single line of control-mode output into an event.
+// Command replies (%begin/%end/%error blocks) and unknown notifications are ignored.
+func ParseControlLine(line string) (ControlEvent, bool) {
+	if !strings.HasPrefix(line, "%") {
+		return ControlEvent{}, false
+	}
+
+	fields := strings.SplitN(line, " ", 3)
+	event := ControlEvent{Time: time.Now()}
+
+	switch fields[0] {
+	case "%sessions-changed":
+		event.Type = EventSessionsChanged
+	case "%session-renamed":
+		if len(fields) < 3 {
+			return ControlEvent{}, false
+		}
+		event.Type = EventSessionRenamed
+		event.SessionID = fields[1]
+		event.Name = fields[2]
+	case "%session-changed":
+		if len(fields) < 3 {
+			return ControlEvent{}, false
+		}
+		event.Type = EventSessionChanged
+		event.SessionID = fields[1]
+		event.Name = fields[2]
+	case "%window-add":
+		if len(fields) < 2 {
+			return ControlEvent{}, false
+		}
+		event.Type = EventWindowAdd
+		event.WindowID = fields[1]
+	case "%window-close", "%unlinked-window-close":
+		if len(fields) < 2 {
+			return ControlEvent{}, false
+		}
+		event.Type = EventWindowClose
+		event.WindowID = fields[1]
+	case "%window-renamed":
+		if len(fields) < 3 {
+			return ControlEvent{}, false
+		}
+		event.Type = EventWindowRenamed
+		event.WindowID = fields[1]
+		event.Name = fields[2]
+	case "%output":
+		if len(fields) < 2 {
+			return ControlEvent{}, false
+		}
+		event.Type = EventActivity
+		event.PaneID = fields[1]
+	case "%exit":
+		event.Type = EventExit
+		if len(fields) > 1 {
+			event.Reason = strings.Join(fields[1:], " ")
+		}
+	default:
+		return ControlEvent{}, false
+	}
+
+	return event, true
+}
+
+// ControlListener streams session and window events from a tmux control-mode client.
+// It replaces polling list-sessions with notifications pushed by the tmux server.
+type ControlListener struct {
+	target           string
+	activityInterval time.Duration
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	events chan ControlEvent
+	mutex  sync.Mutex
+}
+
+// NewControlListener creates a listener that attaches a control-mode client to the target session
+func NewControlListener(target string) *ControlListener {
+	return &ControlListener{
+		target:           target,
+		activityInterval: DefaultActivityInterval,
+	}
+}
+
+// Start launches the control-mode client and returns the event stream.
+// The channel is closed when the client exits or Stop is called.
+func (l *ControlListener) Start() (<-chan ControlEvent, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.cmd != nil {
+		return nil, fmt.Errorf("control listener already started")
+	}
+
+	args := []string{"-C", "attach-session", "-t", l.target}
+	ctx := cmdlog.LogCommandGlobal("tmux", args, cmdlog.GetCaller())
+
+	cmd := exec.Command("tmux", args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control-mode stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control-mode stdout: %w", err)
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		ctx.LogCompletion(false, -1, err.Error(), time.Since(start))
+		return nil, fmt.Errorf("failed to start tmux control mode: %w", err)
+	}
+
+	l.cmd = cmd
+	l.stdin = stdin
+	l.events = make(chan ControlEvent, 64)
+
+	go func() {
+		l.readEvents(stdout, l.events)
+		err := cmd.Wait()
+		if err != nil {
+			ctx.LogCompletion(false, getExitCode(cmd), err.Error(), time.Since(start))
+		} else {
+			ctx.LogCompletion(true, 0, "", time.Since(start))
+		}
+	}()
+
+	return l.events, nil
+}
+
+// Stop detaches the control-mode client, which ends the event stream
+func (l *ControlListener) Stop() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stdin == nil {
+		return nil
+	}
+
+	// Closing stdin makes the control client detach cleanly
+	err := l.stdin.Close()
+	l.stdin = nil
+	return err
+}
+
+// readEvents parses control-mode output, throttles activity events per pane and
+// closes the events channel when the reader is exhausted
+func (l *ControlListener) readEvents(r io.Reader, events chan<- ControlEvent) {
+	defer close(events)
+
+	lastActivity := make(map[string]time.Time)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		event, ok := ParseControlLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		if event.Type == EventActivity {
+			if last, seen := lastActivity[event.PaneID]; seen && event.Time.Sub(last) < l.activityInterval {
+				continue
+			}
+			lastActivity[event.PaneID] = event.Time
+		}
+
+		events <- event
+	}
+}
+
+// controlClient is the part of ControlListener that ControlMux drives
+type controlClient interface {
+	Start() (<-chan ControlEvent, error)
+	Stop() error
+}
+
+// ControlMux watches several sessions with one control-mode client each, since
+// tmux only reports pane output to clients attached to the pane's session, and
+// merges their events into one stream. Notifications every client receives,
+// such as sessions-changed, are forwarded once.
+//
+// The stream reports EventSessionsChanged when the first session is watched
+// and EventExit when the last watched session goes away, so a SessionTracker
+// only trusts its cached view while events can reach it.
+type ControlMux struct {
+	newClient func(target string) controlClient
+	dedupe    time.Duration
+
+	clients map[string]controlClient
+	recent  map[ControlEvent]time.Time
+	events  chan ControlEvent
+	done    chan struct{}
+	stopped bool
+	mutex   sync.Mutex
+}
+
+// NewControlMux creates a multiplexer that watches no sessions yet
+func NewControlMux() *ControlMux {
+	return &ControlMux{
+		newClient: func(target string) controlClient { return NewControlListener(target) },
+		dedupe:    DefaultActivityInterval,
+		clients:   make(map[string]controlClient),
+		recent:    make(map[ControlEvent]time.Time),
+		events:    make(chan ControlEvent, 64),
+		done:      make(chan struct{}),
+	}
+}
+
+// Events returns the merged event stream. It isn't closed by Stop, which
+// only ends delivery.
+func (m *ControlMux) Events() <-chan ControlEvent {
+	return m.events
+}
+
+// Watch attaches a control-mode client to each of the named sessions that
+// isn't watched yet and detaches the clients of sessions no longer named.
+// Sessions whose client fails to start are skipped; the first error is returned.
+func (m *ControlMux) Watch(names []string) error {
+	m.mutex.Lock()
+	if m.stopped {
+		m.mutex.Unlock()
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	for name, client := range m.clients {
+		if !wanted[name] {
+			delete(m.clients, name)
+			_ = client.Stop()
+		}
+	}
+
+	var firstErr error
+	watching := len(m.clients) > 0
+	for _, name := range names {
+		if _, exists := m.clients[name]; exists {
+			continue
+		}
+		client := m.newClient(name)
+		events, err := client.Start()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to watch session %s: %w", name, err)
+			}
+			continue
+		}
+		m.clients[name] = client
+		go m.forward(name, client, events)
+	}
+	lastWatched := watching && len(m.clients) == 0
+	firstWatched := !watching && len(m.clients) > 0
+	m.mutex.Unlock()
+
+	if firstWatched {
+		m.send(ControlEvent{Type: EventSessionsChanged, Time: time.Now()})
+	} else if lastWatched {
+		m.send(ControlEvent{Type: EventExit, Reason: "no sessions watched", Time: time.Now()})
+	}
+	return firstErr
+}
+
+// Stop detaches every control-mode client
+func (m *ControlMux) Stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stopped {
+		return
+	}
+	m.stopped = true
+	close(m.done)
+	for name, client := range m.clients {
+		delete(m.clients, name)
+		_ = client.Stop()
+	}
+}
+
+// forward relays the events of the client watching session until its stream
+// ends, which happens when the session is killed or the client is stopped
+func (m *ControlMux) forward(session string, client controlClient, events <-chan ControlEvent) {
+	for event := range events {
+		// A client exiting only means its own session is gone
+		if event.Type == EventExit || m.duplicate(event) {
+			continue
+		}
+		event.Session = session
+		m.send(event)
+	}
+
+	m.mutex.Lock()
+	lastWatched := false
+	if current, exists := m.clients[session]; exists && current == client {
+		delete(m.clients, session)
+		lastWatched = len(m.clients) == 0 && !m.stopped
+	}
+	m.mutex.Unlock()
+
+	if lastWatched {
+		m.send(ControlEvent{Type: EventExit, Reason: "no sessions watched", Time: time.Now()})
+	}
+}
+
+// duplicate reports whether another client already delivered the same
+// notification. Activity is pane-specific and never a duplicate.
+func (m *ControlMux) duplicate(event ControlEvent) bool {
+	if event.Type == EventActivity {
+		return false
+	}
+
+	key := event
+	key.Time = time.Time{}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if last, seen := m.recent[key]; seen && event.Time.Sub(last) < m.dedupe {
+		return true
+	}
+	for seenKey, last := range m.recent {
+		if event.Time.Sub(last) >= m.dedupe {
+			delete(m.recent, seenKey)
+		}
+	}
+	m.recent[key] = event.Time
+	return false
+}
+
+// send delivers an event unless the multiplexer has been stopped
+func (m *ControlMux) send(event ControlEvent) {
+	select {
+	case <-m.done:
+		return
+	default:
+	}
+	select {
+	case m.events <- event:
+	case <-m.done:
+	}
+}
+
+// sessionNameLister lists the names of all sessions on the tmux server
+type sessionNameLister interface {
+	ListSessionNames() ([]string, error)
+	SessionExists(sessionName string) (bool, error)
+}
+
+// SessionTracker keeps an in-memory view of existing tmux sessions that is
+// updated from control-mode events. Until it has synced, or after the control
+// client exits, lookups fall back to querying tmux directly.
+type SessionTracker struct {
+	source   sessionNameLister
+	sessions map[string]bool
+	synced   bool
+	mutex    sync.RWMutex
+}
+
+// NewSessionTracker creates a tracker backed by the given tmux manager
+func NewSessionTracker(source sessionNameLister) *SessionTracker {
+	return &SessionTracker{
+		source:   source,
+		sessions: make(map[string]bool),
+	}
+}
+
+// Sync reloads the set of sessions from tmux
+func (t *SessionTracker) Sync() error {
+	names, err := t.source.ListSessionNames()
+	if err != nil {
+		t.mutex.Lock()
+		t.synced = false
+		t.mutex.Unlock()
+		return err
+	}
+
+	sessions := make(map[string]bool, len(names))
+	for _, name := range names {
+		sessions[name] = true
+	}
+
+	t.mutex.Lock()
+	t.sessions = sessions
+	t.synced = true
+	t.mutex.Unlock()
+	return nil
+}
+
+// Apply updates the tracker from a control-mode event
+func (t *SessionTracker) Apply(event ControlEvent) {
+	switch event.Type {
+	case EventExit:
+		// Without a control client we can no longer trust the cached view
+		t.mutex.Lock()
+		t.synced = false
+		t.mutex.Unlock()
+	case EventSessionsChanged, EventSessionRenamed, EventWindowClose:
+		_ = t.Sync()
+	}
+}
+
+// SessionExists reports whether a session exists, using the cached view when synced
+func (t *SessionTracker) SessionExists(sessionName string) (bool, error) {
+	t.mutex.RLock()
+	synced := t.synced
+	exists := t.sessions[sessionName]
+	t.mutex.RUnlock()
+
+	if synced {
+		return exists, nil
+	}
+	return t.source.SessionExists(sessionName)
+}
@@ -0,0 +1,373 @@
+package tmux
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseControlLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		ok       bool
+		expected ControlEvent
+	}{
+		{
+			name:     "sessions_changed",
+			line:     "%sessions-changed",
+			ok:       true,
+			expected: ControlEvent{Type: EventSessionsChanged},
+		},
+		{
+			name:     "session_renamed",
+			line:     "%session-renamed $1 sbs-myrepo-github-42",
+			ok:       true,
+			expected: ControlEvent{Type: EventSessionRenamed, SessionID: "$1", Name: "sbs-myrepo-github-42"},
+		},
+		{
+			name:     "session_changed",
+			line:     "%session-changed $2 work",
+			ok:       true,
+			expected: ControlEvent{Type: EventSessionChanged, SessionID: "$2", Name: "work"},
+		},
+		{
+			name:     "window_add",
+			line:     "%window-add @4",
+			ok:       true,
+			expected: ControlEvent{Type: EventWindowAdd, WindowID: "@4"},
+		},
+		{
+			name:     "window_close",
+			line:     "%window-close @4",
+			ok:       true,
+			expected: ControlEvent{Type: EventWindowClose, WindowID: "@4"},
+		},
+		{
+			name:     "unlinked_window_close",
+			line:     "%unlinked-window-close @7",
+			ok:       true,
+			expected: ControlEvent{Type: EventWindowClose, WindowID: "@7"},
+		},
+		{
+			name:     "window_renamed_with_spaces",
+			line:     "%window-renamed @1 my window",
+			ok:       true,
+			expected: ControlEvent{Type: EventWindowRenamed, WindowID: "@1", Name: "my window"},
+		},
+		{
+			name:     "output",
+			line:     `%output %3 hello\015\012`,
+			ok:       true,
+			expected: ControlEvent{Type: EventActivity, PaneID: "%3"},
+		},
+		{
+			name:     "exit_with_reason",
+			line:     "%exit server exited",
+			ok:       true,
+			expected: ControlEvent{Type: EventExit, Reason: "server exited"},
+		},
+		{name: "command_reply_begin", line: "%begin 1700000000 12 1", ok: false},
+		{name: "plain_output", line: "sbs-repo-1: 1 windows", ok: false},
+		{name: "truncated_notification", line: "%session-renamed $1", ok: false},
+		{name: "unknown_notification", line: "%layout-change @1 abcd", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := ParseControlLine(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+
+			assert.False(t, event.Time.IsZero())
+			event.Time = time.Time{}
+			assert.Equal(t, tt.expected, event)
+		})
+	}
+}
+
+func TestControlEvent_IsSessionLifecycle(t *testing.T) {
+	assert.True(t, ControlEvent{Type: EventSessionsChanged}.IsSessionLifecycle())
+	assert.True(t, ControlEvent{Type: EventWindowClose}.IsSessionLifecycle())
+	assert.True(t, ControlEvent{Type: EventExit}.IsSessionLifecycle())
+	assert.False(t, ControlEvent{Type: EventActivity}.IsSessionLifecycle())
+	assert.False(t, ControlEvent{Type: EventWindowAdd}.IsSessionLifecycle())
+}
+
+func TestControlListener_ReadEvents(t *testing.T) {
+	t.Run("streams_events_and_closes", func(t *testing.T) {
+		input := strings.Join([]string{
+			"%begin 1 1 0",
+			"%end 1 1 0",
+			"%sessions-changed",
+			"%window-add @2",
+			"%exit",
+		}, "\n")
+
+		listener := NewControlListener("sbs-test")
+		events := make(chan ControlEvent, 10)
+		listener.readEvents(strings.NewReader(input), events)
+
+		var types []ControlEventType
+		for event := range events {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []ControlEventType{EventSessionsChanged, EventWindowAdd, EventExit}, types)
+	})
+
+	t.Run("throttles_activity_per_pane", func(t *testing.T) {
+		input := strings.Join([]string{
+			"%output %1 a",
+			"%output %1 b",
+			"%output %2 c",
+			"%output %1 d",
+		}, "\n")
+
+		listener := NewControlListener("sbs-test")
+		listener.activityInterval = time.Hour
+		events := make(chan ControlEvent, 10)
+		listener.readEvents(strings.NewReader(input), events)
+
+		var panes []string
+		for event := range events {
+			panes = append(panes, event.PaneID)
+		}
+		assert.Equal(t, []string{"%1", "%2"}, panes)
+	})
+}
+
+func TestControlListener_StopBeforeStart(t *testing.T) {
+	listener := NewControlListener("sbs-test")
+	assert.NoError(t, listener.Stop())
+}
+
+// fakeControlClient streams whatever the test sends and ends when stopped
+type fakeControlClient struct {
+	events   chan ControlEvent
+	startErr error
+	stopped  bool
+}
+
+func (f *fakeControlClient) Start() (<-chan ControlEvent, error) {
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+	return f.events, nil
+}
+
+func (f *fakeControlClient) Stop() error {
+	if !f.stopped {
+		f.stopped = true
+		close(f.events)
+	}
+	return nil
+}
+
+func newTestMux(clients map[string]*fakeControlClient) *ControlMux {
+	mux := NewControlMux()
+	mux.dedupe = time.Hour
+	mux.newClient = func(target string) controlClient {
+		client := &fakeControlClient{events: make(chan ControlEvent, 10)}
+		if existing, ok := clients[target]; ok {
+			client = existing
+		}
+		clients[target] = client
+		return client
+	}
+	return mux
+}
+
+func nextEvent(t *testing.T, mux *ControlMux) ControlEvent {
+	t.Helper()
+	select {
+	case event := <-mux.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event from the control mux")
+		return ControlEvent{}
+	}
+}
+
+func TestControlMux(t *testing.T) {
+	t.Run("reports_activity_of_every_session", func(t *testing.T) {
+		clients := make(map[string]*fakeControlClient)
+		mux := newTestMux(clients)
+		defer mux.Stop()
+
+		require.NoError(t, mux.Watch([]string{"sbs-a", "sbs-b"}))
+		assert.Equal(t, EventSessionsChanged, nextEvent(t, mux).Type, "the first watched session lets a tracker sync")
+
+		clients["sbs-b"].events <- ControlEvent{Type: EventActivity, PaneID: "%2", Time: time.Now()}
+		event := nextEvent(t, mux)
+		assert.Equal(t, EventActivity, event.Type)
+		assert.Equal(t, "sbs-b", event.Session)
+
+		clients["sbs-a"].events <- ControlEvent{Type: EventActivity, PaneID: "%1", Time: time.Now()}
+		assert.Equal(t, "sbs-a", nextEvent(t, mux).Session)
+	})
+
+	t.Run("forwards_shared_notifications_once", func(t *testing.T) {
+		clients := make(map[string]*fakeControlClient)
+		mux := newTestMux(clients)
+		defer mux.Stop()
+
+		require.NoError(t, mux.Watch([]string{"sbs-a", "sbs-b"}))
+		nextEvent(t, mux)
+
+		now := time.Now()
+		clients["sbs-a"].events <- ControlEvent{Type: EventSessionsChanged, Time: now}
+		clients["sbs-b"].events <- ControlEvent{Type: EventSessionsChanged, Time: now}
+		clients["sbs-b"].events <- ControlEvent{Type: EventWindowAdd, WindowID: "@3", Time: now}
+
+		assert.Equal(t, EventSessionsChanged, nextEvent(t, mux).Type)
+		assert.Equal(t, EventWindowAdd, nextEvent(t, mux).Type)
+	})
+
+	t.Run("detaches_sessions_no_longer_watched", func(t *testing.T) {
+		clients := make(map[string]*fakeControlClient)
+		mux := newTestMux(clients)
+		defer mux.Stop()
+
+		require.NoError(t, mux.Watch([]string{"sbs-a", "sbs-b"}))
+		nextEvent(t, mux)
+		require.NoError(t, mux.Watch([]string{"sbs-b"}))
+
+		assert.True(t, clients["sbs-a"].stopped)
+		assert.False(t, clients["sbs-b"].stopped)
+	})
+
+	t.Run("exits_when_the_last_session_ends", func(t *testing.T) {
+		clients := make(map[string]*fakeControlClient)
+		mux := newTestMux(clients)
+		defer mux.Stop()
+
+		require.NoError(t, mux.Watch([]string{"sbs-a"}))
+		nextEvent(t, mux)
+
+		// A killed session's client reports its own exit, which isn't forwarded
+		clients["sbs-a"].events <- ControlEvent{Type: EventExit, Time: time.Now()}
+		clients["sbs-a"].Stop()
+
+		event := nextEvent(t, mux)
+		assert.Equal(t, EventExit, event.Type)
+		assert.Equal(t, "no sessions watched", event.Reason)
+	})
+
+	t.Run("skips_sessions_that_fail_to_attach", func(t *testing.T) {
+		clients := map[string]*fakeControlClient{"sbs-a": {startErr: errors.New("no such session")}}
+		mux := newTestMux(clients)
+		defer mux.Stop()
+
+		err := mux.Watch([]string{"sbs-a", "sbs-b"})
+		assert.EqualError(t, err, "failed to watch session sbs-a: no such session")
+		assert.Equal(t, EventSessionsChanged, nextEvent(t, mux).Type)
+	})
+
+	t.Run("stop_detaches_every_client", func(t *testing.T) {
+		clients := make(map[string]*fakeControlClient)
+		mux := newTestMux(clients)
+
+		require.NoError(t, mux.Watch([]string{"sbs-a", "sbs-b"}))
+		mux.Stop()
+
+		assert.True(t, clients["sbs-a"].stopped)
+		assert.True(t, clients["sbs-b"].stopped)
+		assert.NoError(t, mux.Watch([]string{"sbs-c"}))
+		assert.NotContains(t, clients, "sbs-c")
+	})
+}
+
+type fakeSessionSource struct {
+	names       []string
+	listErr     error
+	listCalls   int
+	existsCalls int
+}
+
+func (f *fakeSessionSource) ListSessionNames() ([]string, error) {
+	f.listCalls++
+	return f.names, f.listErr
+}
+
+func (f *fakeSessionSource) SessionExists(sessionName string) (bool, error) {
+	f.existsCalls++
+	for _, name := range f.names {
+		if name == sessionName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestSessionTracker(t *testing.T) {
+	t.Run("falls_back_before_sync", func(t *testing.T) {
+		source := &fakeSessionSource{names: []string{"sbs-a"}}
+		tracker := NewSessionTracker(source)
+
+		exists, err := tracker.SessionExists("sbs-a")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, 1, source.existsCalls)
+	})
+
+	t.Run("uses_cache_after_sync", func(t *testing.T) {
+		source := &fakeSessionSource{names: []string{"sbs-a"}}
+		tracker := NewSessionTracker(source)
+		require.NoError(t, tracker.Sync())
+
+		exists, err := tracker.SessionExists("sbs-a")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = tracker.SessionExists("sbs-b")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Equal(t, 0, source.existsCalls)
+	})
+
+	t.Run("resyncs_on_sessions_changed", func(t *testing.T) {
+		source := &fakeSessionSource{names: []string{"sbs-a"}}
+		tracker := NewSessionTracker(source)
+		require.NoError(t, tracker.Sync())
+
+		source.names = []string{"sbs-a", "sbs-b"}
+		tracker.Apply(ControlEvent{Type: EventActivity})
+		exists, _ := tracker.SessionExists("sbs-b")
+		assert.False(t, exists, "activity events should not trigger a resync")
+
+		tracker.Apply(ControlEvent{Type: EventSessionsChanged})
+		exists, _ = tracker.SessionExists("sbs-b")
+		assert.True(t, exists)
+		assert.Equal(t, 2, source.listCalls)
+	})
+
+	t.Run("exit_reverts_to_fallback", func(t *testing.T) {
+		source := &fakeSessionSource{names: []string{"sbs-a"}}
+		tracker := NewSessionTracker(source)
+		require.NoError(t, tracker.Sync())
+
+		tracker.Apply(ControlEvent{Type: EventExit})
+		_, err := tracker.SessionExists("sbs-a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, source.existsCalls)
+	})
+
+	t.Run("sync_error_reverts_to_fallback", func(t *testing.T) {
+		source := &fakeSessionSource{names: []string{"sbs-a"}}
+		tracker := NewSessionTracker(source)
+		require.NoError(t, tracker.Sync())
+
+		source.listErr = errors.New("server gone")
+		assert.Error(t, tracker.Sync())
+
+		_, err := tracker.SessionExists("sbs-a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, source.existsCalls)
+	})
+}
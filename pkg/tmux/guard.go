@@ -0,0 +1,100 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// WorktreeEnv is the session environment variable that marks the worktree an
+// sbs tmux session was created for
+const WorktreeEnv = "SBS_WORKTREE"
+
+// ErrForeignSession is wrapped by ForeignSessionError
+var ErrForeignSession = errors.New("tmux session belongs to another worktree")
+
+// ForeignSessionError reports a tmux session that has the name sbs expects
+// but was created for something else, for example by a user who reused the
+// name, so sbs must not type commands into it
+type ForeignSessionError struct {
+	Session  string
+	Worktree string // The worktree sbs expected
+	Actual   string // The session's worktree marker or start directory
+}
+
+func (e *ForeignSessionError) Error() string {
+	return fmt.Sprintf("tmux session %s belongs to %s, not %s; refusing to send commands into it (rename or kill that tmux session and try again)",
+		e.Session, e.Actual, e.Worktree)
+}
+
+func (e *ForeignSessionError) Unwrap() error {
+	return ErrForeignSession
+}
+
+// VerifySession checks that the tmux session of target, a session name or
+// pane ID, belongs to worktreePath before sbs sends keys to it. Sessions sbs
+// created carry a WorktreeEnv marker; older ones must have been started in
+// the worktree.
+func (m *Manager) VerifySession(target, worktreePath string) error {
+	marker := ""
+	if output, err := m.runTmuxCommand([]string{"show-environment", "-t", target, WorktreeEnv}); err == nil {
+		marker = parseEnvironmentValue(string(output), WorktreeEnv)
+	}
+	sessionPath := ""
+	if marker == "" {
+		output, err := m.runTmuxCommand([]string{"display-message", "-p", "-t", target, "#{session_path}"})
+		if err != nil {
+			return fmt.Errorf("failed to inspect tmux session %s: %w", target, err)
+		}
+		sessionPath = strings.TrimSpace(string(output))
+	}
+	return checkSessionOwner(target, worktreePath, marker, sessionPath)
+}
+
+// checkSessionOwner compares a session's marker, or without one its start
+// directory, with the worktree it should belong to
+func checkSessionOwner(target, worktreePath, marker, sessionPath string) error {
+	worktreePath = filepath.Clean(worktreePath)
+	if marker != "" {
+		if filepath.Clean(marker) != worktreePath {
+			return &ForeignSessionError{Session: target, Worktree: worktreePath, Actual: marker}
+		}
+		return nil
+	}
+	if sessionPath == "" || !isWithin(filepath.Clean(sessionPath), worktreePath) {
+		if sessionPath == "" {
+			sessionPath = "an unknown directory"
+		}
+		return &ForeignSessionError{Session: target, Worktree: worktreePath, Actual: sessionPath}
+	}
+	return nil
+}
+
+// parseEnvironmentValue returns the value of name in show-environment
+// output; a removed variable ("-NAME") has none
+func parseEnvironmentValue(output, name string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// isWithin reports whether path is dir or beneath it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// withWorktreeMarker returns env with the worktree marker added, leaving the
+// caller's map alone
+func withWorktreeMarker(env map[string]string, worktreePath string) map[string]string {
+	marked := make(map[string]string, len(env)+1)
+	for key, value := range env {
+		marked[key] = value
+	}
+	marked[WorktreeEnv] = worktreePath
+	return marked
+}
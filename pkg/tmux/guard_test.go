@@ -0,0 +1,56 @@
+package tmux
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSessionOwner(t *testing.T) {
+	assert.NoError(t, checkSessionOwner("sbs-web-1", "/w/issue-1", "/w/issue-1/", ""))
+	assert.NoError(t, checkSessionOwner("sbs-web-1", "/w/issue-1", "", "/w/issue-1/src"), "older sessions started in the worktree pass")
+
+	err := checkSessionOwner("sbs-web-1", "/w/issue-1", "/w/issue-2", "")
+	var foreign *ForeignSessionError
+	require.True(t, errors.As(err, &foreign))
+	assert.Equal(t, "/w/issue-2", foreign.Actual)
+	assert.ErrorIs(t, err, ErrForeignSession)
+	assert.ErrorContains(t, err, "tmux session sbs-web-1 belongs to /w/issue-2, not /w/issue-1; refusing to send commands into it")
+
+	assert.ErrorIs(t, checkSessionOwner("sbs-web-1", "/w/issue-1", "", "/home/dev"), ErrForeignSession)
+	assert.ErrorIs(t, checkSessionOwner("sbs-web-1", "/w/issue-1", "", "/w/issue-10"), ErrForeignSession)
+	assert.ErrorIs(t, checkSessionOwner("sbs-web-1", "/w/issue-1", "", ""), ErrForeignSession)
+}
+
+func TestParseEnvironmentValue(t *testing.T) {
+	assert.Equal(t, "/w/issue-1", parseEnvironmentValue("SBS_WORKTREE=/w/issue-1\n", WorktreeEnv))
+	assert.Empty(t, parseEnvironmentValue("-SBS_WORKTREE\n", WorktreeEnv), "removed variables have no value")
+	assert.Empty(t, parseEnvironmentValue("", WorktreeEnv))
+}
+
+func TestManager_VerifySession(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available")
+	}
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	// Talk to tmux directly until the server runs: "error connecting" output
+	// would use up the manager's one recovery attempt in this process
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+	// A session the user created under an sbs name has no marker
+	output, err := exec.Command("tmux", "new-session", "-d", "-s", "sbs-guard-2", "-c", t.TempDir()).CombinedOutput()
+	require.NoError(t, err, "%s", output)
+	manager := NewManager()
+
+	worktree := t.TempDir()
+	_, err = manager.CreateSession(0, worktree, "sbs-guard-1", CreateTmuxEnvironment("guard"))
+	require.NoError(t, err)
+	assert.NoError(t, manager.VerifySession("sbs-guard-1", worktree))
+	assert.ErrorIs(t, manager.VerifySession("sbs-guard-1", t.TempDir()), ErrForeignSession)
+
+	_, err = manager.CreateSession(0, worktree, "sbs-guard-2", nil)
+	assert.ErrorIs(t, err, ErrForeignSession, "sbs doesn't take over a foreign session")
+}
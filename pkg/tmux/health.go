@@ -0,0 +1,195 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"sbs/pkg/cmdtimeout"
+)
+
+// ServerState classifies the tmux server behind the default socket
+type ServerState string
+
+const (
+	ServerRunning      ServerState = "running"
+	ServerNotRunning   ServerState = "not running"
+	ServerDeadSocket   ServerState = "dead socket"  // socket file left behind by a server that exited
+	ServerUnresponsive ServerState = "unresponsive" // server accepts connections but doesn't answer
+	ServerInaccessible ServerState = "inaccessible" // socket can't be reached, e.g. permissions
+)
+
+// serverProbeTimeout bounds how long a health check waits for the server
+const serverProbeTimeout = 3 * time.Second
+
+// ServerHealth is the result of checking the tmux server
+type ServerHealth struct {
+	State  ServerState
+	Socket string
+	Detail string
+}
+
+// Healthy reports whether tmux commands can be expected to work
+func (h ServerHealth) Healthy() bool {
+	return h.State == ServerRunning || h.State == ServerNotRunning
+}
+
+func (h ServerHealth) String() string {
+	s := fmt.Sprintf("tmux server %s (socket %s)", h.State, h.Socket)
+	if h.Detail != "" {
+		s += ": " + h.Detail
+	}
+	return s
+}
+
+// ServerError wraps a tmux failure caused by an unhealthy server
+type ServerError struct {
+	Health ServerHealth
+	Err    error
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("%v (%s; run 'sbs doctor --fix')", e.Err, e.Health)
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultSocketPath returns the socket tmux uses without -L or -S: the one
+// named in $TMUX when run inside tmux, else $TMUX_TMPDIR (or /tmp)/tmux-<uid>/default
+func DefaultSocketPath() string {
+	if current := os.Getenv("TMUX"); current != "" {
+		if socket, _, _ := strings.Cut(current, ","); socket != "" {
+			return socket
+		}
+	}
+	dir := os.Getenv("TMUX_TMPDIR")
+	if dir == "" {
+		dir = "/tmp"
+	}
+	return filepath.Join(dir, fmt.Sprintf("tmux-%d", os.Getuid()), "default")
+}
+
+// probeSocket classifies the socket at path by connecting to it, without
+// running tmux
+func probeSocket(path string) (ServerState, string) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return ServerNotRunning, ""
+	}
+	if err != nil {
+		return ServerInaccessible, err.Error()
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return ServerInaccessible, "not a socket"
+	}
+
+	conn, err := net.DialTimeout("unix", path, serverProbeTimeout)
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return ServerDeadSocket, "nothing is listening on it"
+		}
+		return ServerInaccessible, err.Error()
+	}
+	conn.Close()
+	return ServerRunning, ""
+}
+
+// CheckServer checks the tmux server behind the default socket. A server
+// that accepts connections must also answer list-sessions within a few
+// seconds to count as running.
+func (m *Manager) CheckServer() ServerHealth {
+	health := ServerHealth{Socket: DefaultSocketPath()}
+	health.State, health.Detail = probeSocket(health.Socket)
+	if health.State != ServerRunning {
+		return health
+	}
+
+	ctx, cancel := context.WithTimeout(m.baseContext(), serverProbeTimeout)
+	defer cancel()
+	_, err := m.WithContext(ctx).runTmuxCommandOnce([]string{"list-sessions"})
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case cmdtimeout.IsTimeout(err):
+		health.State = ServerUnresponsive
+		health.Detail = fmt.Sprintf("no answer within %s", serverProbeTimeout)
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		// The server exited between the probe and the command
+		health.State, health.Detail = probeSocket(health.Socket)
+	}
+	return health
+}
+
+// RecoverServer tries to make tmux usable again and describes what it did.
+// A dead socket is removed. An unresponsive server is stopped with
+// kill-server, which ends every session on it, so only do that when the
+// user asked for it.
+func (m *Manager) RecoverServer(health ServerHealth) (string, error) {
+	switch health.State {
+	case ServerDeadSocket:
+		// Nothing listens on the socket, so removing it can't affect a server
+		if err := os.Remove(health.Socket); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to remove dead tmux socket %s: %w", health.Socket, err)
+		}
+		return fmt.Sprintf("removed dead tmux socket %s", health.Socket), nil
+	case ServerUnresponsive:
+		ctx, cancel := context.WithTimeout(m.baseContext(), serverProbeTimeout)
+		defer cancel()
+		if _, err := m.WithContext(ctx).runTmuxCommandOnce([]string{"kill-server"}); err != nil {
+			return "", fmt.Errorf("tmux server on %s did not stop (%v); kill it manually, e.g. pkill -u %d tmux", health.Socket, err, os.Getuid())
+		}
+		return fmt.Sprintf("stopped unresponsive tmux server on %s", health.Socket), nil
+	case ServerInaccessible:
+		return "", fmt.Errorf("tmux socket %s needs manual attention: %s", health.Socket, health.Detail)
+	}
+	return "", nil
+}
+
+// serverRecovery makes sure automatic recovery is attempted at most once per process
+var serverRecovery sync.Once
+
+// recoverFromFailure is called when a tmux command fails in a way that can
+// mean an unhealthy server. Dead sockets are removed automatically and
+// retry reports that the command should run again; other unhealthy states
+// are reported through a ServerError.
+func (m *Manager) recoverFromFailure(err error, stderr string) (retry bool, wrapped error) {
+	if !cmdtimeout.IsTimeout(err) && !looksLikeServerFailure(stderr) {
+		return false, err
+	}
+
+	wrapped = err
+	serverRecovery.Do(func() {
+		health := m.CheckServer()
+		switch {
+		case health.State == ServerDeadSocket:
+			if action, recoverErr := m.RecoverServer(health); recoverErr == nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s; retrying\n", action)
+				retry = true
+			}
+		case !health.Healthy():
+			wrapped = &ServerError{Health: health, Err: err}
+		}
+	})
+	return retry, wrapped
+}
+
+// looksLikeServerFailure reports whether tmux's error output points at the
+// server rather than at the command
+func looksLikeServerFailure(stderr string) bool {
+	for _, marker := range []string{"error connecting to", "server exited unexpectedly", "lost server", "Connection refused"} {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
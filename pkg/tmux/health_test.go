@@ -0,0 +1,104 @@
+package tmux
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeDeadSocket leaves a socket file at path with nothing listening on it,
+// as a crashed tmux server does
+func makeDeadSocket(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	listener.SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+}
+
+func TestDefaultSocketPath(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/work,1234,0")
+	assert.Equal(t, "/tmp/tmux-1000/work", DefaultSocketPath(), "inside tmux the current server's socket is used")
+
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", "/run/user/1000")
+	assert.Equal(t, fmt.Sprintf("/run/user/1000/tmux-%d/default", os.Getuid()), DefaultSocketPath())
+}
+
+func TestProbeSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	state, _ := probeSocket(filepath.Join(dir, "missing"))
+	assert.Equal(t, ServerNotRunning, state)
+
+	plain := filepath.Join(dir, "plain")
+	require.NoError(t, os.WriteFile(plain, nil, 0600))
+	state, detail := probeSocket(plain)
+	assert.Equal(t, ServerInaccessible, state)
+	assert.Equal(t, "not a socket", detail)
+
+	dead := filepath.Join(dir, "dead")
+	makeDeadSocket(t, dead)
+	state, _ = probeSocket(dead)
+	assert.Equal(t, ServerDeadSocket, state)
+
+	live := filepath.Join(dir, "live")
+	listener, err := net.Listen("unix", live)
+	require.NoError(t, err)
+	defer listener.Close()
+	state, _ = probeSocket(live)
+	assert.Equal(t, ServerRunning, state)
+}
+
+func TestManager_CheckAndRecoverDeadSocket(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	socket := DefaultSocketPath()
+	makeDeadSocket(t, socket)
+
+	manager := NewManager()
+	health := manager.CheckServer()
+	assert.Equal(t, ServerDeadSocket, health.State)
+	assert.False(t, health.Healthy())
+	assert.Contains(t, health.String(), "tmux server dead socket (socket "+socket+")")
+
+	action, err := manager.RecoverServer(health)
+	require.NoError(t, err)
+	assert.Contains(t, action, "removed dead tmux socket")
+	assert.NoFileExists(t, socket)
+
+	health = manager.CheckServer()
+	assert.Equal(t, ServerNotRunning, health.State)
+	assert.True(t, health.Healthy())
+}
+
+func TestLooksLikeServerFailure(t *testing.T) {
+	assert.True(t, looksLikeServerFailure("error connecting to /tmp/tmux-0/default (Connection refused)\n"))
+	assert.True(t, looksLikeServerFailure("server exited unexpectedly"))
+	assert.False(t, looksLikeServerFailure("can't find session: sbs-1"))
+	assert.False(t, looksLikeServerFailure("no server running on /tmp/tmux-0/default"))
+}
+
+func TestManager_RecoverFromFailure(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	manager := NewManager()
+
+	commandErr := fmt.Errorf("exit status 1")
+	retry, err := manager.recoverFromFailure(commandErr, "can't find session: sbs-1")
+	assert.False(t, retry, "ordinary command failures are left alone")
+	assert.Equal(t, commandErr, err)
+
+	socket := DefaultSocketPath()
+	makeDeadSocket(t, socket)
+	retry, err = manager.recoverFromFailure(commandErr, "error connecting to "+socket+" (Connection refused)")
+	assert.True(t, retry, "the command is retried once the dead socket is gone")
+	assert.Equal(t, commandErr, err)
+	assert.NoFileExists(t, socket)
+}
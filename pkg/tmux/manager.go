@@ -1,6 +1,8 @@
 package tmux
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +12,8 @@ import (
 	"time"
 
 	"sbs/pkg/cmdlog"
+	"sbs/pkg/cmdtimeout"
+	"sbs/pkg/faultinject"
 )
 
 type Session struct {
@@ -21,12 +25,31 @@ type Session struct {
 	Status       string // "active", "stopped"
 }
 
-type Manager struct{}
+type Manager struct {
+	ctx       context.Context // bound with WithContext; nil means context.Background()
+	attachEnv []string        // refreshed on attach, set with WithAttachEnvironment
+}
 
 func NewManager() *Manager {
 	return &Manager{}
 }
 
+// WithContext returns a copy of the manager whose commands are cancelled
+// when ctx is done
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	c := *m
+	c.ctx = ctx
+	return &c
+}
+
+// baseContext returns the context commands run under
+func (m *Manager) baseContext() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
 func (m *Manager) CreateSession(issueNumber int, workingDir, sessionName string, env ...map[string]string) (*Session, error) {
 	// sessionName is now provided by the caller (repository-aware)
 
@@ -36,17 +59,27 @@ func (m *Manager) CreateSession(issueNumber int, workingDir, sessionName string,
 		return nil, fmt.Errorf("failed to check if session exists: %w", err)
 	}
 
+	// Mark the session with its worktree so later commands can check it
+	var sessionEnv map[string]string
+	if len(env) > 0 {
+		sessionEnv = env[0]
+	}
+	sessionEnv = withWorktreeMarker(sessionEnv, workingDir)
+
 	if exists {
+		// Never type into a session someone else created under this name
+		if err := m.VerifySession(sessionName, workingDir); err != nil {
+			return nil, err
+		}
+
 		// Session exists, update its working directory
 		if err := m.setWorkingDirectory(sessionName, workingDir); err != nil {
 			return nil, fmt.Errorf("failed to update session working directory: %w", err)
 		}
 
-		// Set environment variables if provided
-		if len(env) > 0 && env[0] != nil {
-			if err := m.setEnvironmentVariables(sessionName, env[0]); err != nil {
-				return nil, fmt.Errorf("failed to set environment variables: %w", err)
-			}
+		// Set environment variables, including the marker older sessions lack
+		if err := m.setEnvironmentVariables(sessionName, sessionEnv); err != nil {
+			return nil, fmt.Errorf("failed to set environment variables: %w", err)
 		}
 
 		return &Session{
@@ -59,17 +92,19 @@ func (m *Manager) CreateSession(issueNumber int, workingDir, sessionName string,
 		}, nil
 	}
 
+	if err := faultinject.Check(faultinject.StepTmuxCreate); err != nil {
+		return nil, fmt.Errorf("failed to create tmux session %s: %w", sessionName, err)
+	}
+
 	// Create new detached session with environment variables
 	args := []string{"new-session", "-d", "-s", sessionName, "-c", workingDir}
-	if err := m.runTmuxCommandWithEnv(args, env...); err != nil {
+	if err := m.runTmuxCommandWithEnv(args, sessionEnv); err != nil {
 		return nil, fmt.Errorf("failed to create tmux session %s: %w", sessionName, err)
 	}
 
 	// Set environment variables in the session after creation
-	if len(env) > 0 && env[0] != nil {
-		if err := m.setEnvironmentVariables(sessionName, env[0]); err != nil {
-			return nil, fmt.Errorf("failed to set environment variables: %w", err)
-		}
+	if err := m.setEnvironmentVariables(sessionName, sessionEnv); err != nil {
+		return nil, fmt.Errorf("failed to set environment variables: %w", err)
 	}
 
 	now := time.Now()
@@ -96,6 +131,26 @@ func (m *Manager) SessionExists(sessionName string) (bool, error) {
 	return true, nil
 }
 
+// ListSessionNames returns the names of all sessions on the tmux server
+func (m *Manager) ListSessionNames() ([]string, error) {
+	output, err := m.runTmuxCommand([]string{"list-sessions", "-F", "#{session_name}"})
+	if err != nil {
+		// No server running or no sessions exist
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+
+	names := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
 func (m *Manager) AttachToSession(sessionName string, env ...map[string]string) error {
 	// Find tmux executable path
 	tmuxPath, err := exec.LookPath("tmux")
@@ -103,6 +158,12 @@ func (m *Manager) AttachToSession(sessionName string, env ...map[string]string)
 		return fmt.Errorf("tmux command not found: %w", err)
 	}
 
+	// Bring the session's agent socket and display up to date with this
+	// terminal; a stale value is worth a warning, not a failed attach
+	if err := m.RefreshEnvironment(sessionName, m.attachEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	// Set environment variables in the session before attaching
 	if len(env) > 0 && env[0] != nil {
 		if err := m.setEnvironmentVariables(sessionName, env[0]); err != nil {
@@ -130,6 +191,36 @@ func (m *Manager) AttachToSession(sessionName string, env ...map[string]string)
 	return nil
 }
 
+// InsideTmux reports whether sbs is running inside a tmux client
+func InsideTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// SwitchClient moves the current tmux client to another session. Use it
+// instead of AttachToSession when already inside tmux, where attaching would
+// nest sessions.
+func (m *Manager) SwitchClient(sessionName string) error {
+	args := []string{"switch-client", "-t", sessionName}
+	if err := m.runTmuxCommandRun(args); err != nil {
+		return fmt.Errorf("failed to switch to session %s: %w", sessionName, err)
+	}
+	return nil
+}
+
+// NewWindow opens a window called name in a session, running command (the
+// default shell when empty) in workingDir, and makes it the session's
+// current window
+func (m *Manager) NewWindow(sessionName, name, workingDir, command string) error {
+	args := []string{"new-window", "-t", sessionName + ":", "-n", name, "-c", workingDir}
+	if command != "" {
+		args = append(args, command)
+	}
+	if err := m.runTmuxCommandRun(args); err != nil {
+		return fmt.Errorf("failed to open window %s in session %s: %w", name, sessionName, err)
+	}
+	return nil
+}
+
 func (m *Manager) KillSession(sessionName string) error {
 	args := []string{"kill-session", "-t", sessionName}
 	if err := m.runTmuxCommandRun(args); err != nil {
@@ -138,6 +229,15 @@ func (m *Manager) KillSession(sessionName string) error {
 	return nil
 }
 
+// RenameSession renames a running tmux session; clients attached to it stay attached
+func (m *Manager) RenameSession(oldName, newName string) error {
+	args := []string{"rename-session", "-t", oldName, newName}
+	if err := m.runTmuxCommandRun(args); err != nil {
+		return fmt.Errorf("failed to rename session %s to %s: %w", oldName, newName, err)
+	}
+	return nil
+}
+
 func (m *Manager) ListSessions() ([]*Session, error) {
 	args := []string{"list-sessions", "-F", "#{session_name}|#{session_created}|#{session_last_attached}"}
 	output, err := m.runTmuxCommand(args)
@@ -206,7 +306,7 @@ func (m *Manager) StartWorkIssue(sessionName string, issueNumber int, workIssueS
 	}
 
 	// Send command to run work-issue script in the session
-	command := fmt.Sprintf("%s %d", workIssueScript, issueNumber)
+	command := fmt.Sprintf("%s %d", ShellQuote(workIssueScript), issueNumber)
 	args := []string{"send-keys", "-t", sessionName, command, "Enter"}
 
 	if err := m.runTmuxCommandRun(args); err != nil {
@@ -234,21 +334,8 @@ func (m *Manager) ExecuteCommandWithSubstitution(sessionName, command string, ar
 		}
 	}
 
-	// Build the full command string
-	var fullCommand string
-	if len(args) > 0 {
-		// Apply substitutions to args if provided
-		processedArgs := make([]string, len(args))
-		for i, arg := range args {
-			processedArgs[i] = m.substituteParameters(arg, substitutions)
-		}
-
-		// Use command + processed args
-		cmdParts := append([]string{command}, processedArgs...)
-		fullCommand = strings.Join(cmdParts, " ")
-	} else {
-		fullCommand = command
-	}
+	// Build the full command string, quoting each argument
+	fullCommand := CommandLine(command, args, substitutions)
 
 	// Send command to the session
 	tmuxArgs := []string{"send-keys", "-t", sessionName, fullCommand, "Enter"}
@@ -260,22 +347,9 @@ func (m *Manager) ExecuteCommandWithSubstitution(sessionName, command string, ar
 	return nil
 }
 
-// substituteParameters replaces parameter placeholders in a string
-func (m *Manager) substituteParameters(input string, substitutions map[string]string) string {
-	if substitutions == nil {
-		return input
-	}
-
-	result := input
-	for placeholder, value := range substitutions {
-		result = strings.ReplaceAll(result, placeholder, value)
-	}
-	return result
-}
-
 func (m *Manager) setWorkingDirectory(sessionName, workingDir string) error {
 	// Send cd command to the session
-	args := []string{"send-keys", "-t", sessionName, fmt.Sprintf("cd %s", workingDir), "Enter"}
+	args := []string{"send-keys", "-t", sessionName, "cd " + ShellQuote(workingDir), "Enter"}
 	return m.runTmuxCommandRun(args)
 }
 
@@ -430,14 +504,37 @@ func normalizeTitle(title string) string {
 	return result.String()
 }
 
-// runTmuxCommand executes a tmux command with logging and returns output
+// runTmuxCommand executes a tmux command with logging and returns output,
+// recovering from a dead server socket once if needed
 func (m *Manager) runTmuxCommand(args []string) ([]byte, error) {
-	ctx := cmdlog.LogCommandGlobal("tmux", args, cmdlog.GetCaller())
+	output, err := m.runTmuxCommandOnce(args)
+	if err == nil {
+		return output, nil
+	}
+	var stderr string
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		stderr = string(exitErr.Stderr)
+	}
+	retry, err := m.recoverFromFailure(err, stderr)
+	if retry {
+		return m.runTmuxCommandOnce(args)
+	}
+	return output, err
+}
+
+// runTmuxCommandOnce executes a tmux command with logging and returns output
+func (m *Manager) runTmuxCommandOnce(args []string) ([]byte, error) {
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "tmux", args, cmdlog.GetCaller())
+
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "tmux")
+	defer cancel()
 
-	cmd := exec.Command("tmux", args...)
+	cmd := exec.CommandContext(timeoutCtx, "tmux", args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
 	start := time.Now()
 	output, err := cmd.Output()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "tmux", args, timeout, err)
 
 	if err != nil {
 		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
@@ -448,29 +545,55 @@ func (m *Manager) runTmuxCommand(args []string) ([]byte, error) {
 	return output, nil
 }
 
-// runTmuxCommandRun executes a tmux command with logging without capturing output
+// runTmuxCommandRun executes a tmux command with logging without capturing
+// output, recovering from a dead server socket once if needed
 func (m *Manager) runTmuxCommandRun(args []string) error {
-	ctx := cmdlog.LogCommandGlobal("tmux", args, cmdlog.GetCaller())
+	stderr, err := m.runTmuxCommandRunOnce(args)
+	if err == nil {
+		return nil
+	}
+	retry, err := m.recoverFromFailure(err, stderr)
+	if retry {
+		_, err = m.runTmuxCommandRunOnce(args)
+	}
+	return err
+}
+
+// runTmuxCommandRunOnce executes a tmux command with logging, returning only
+// its error output
+func (m *Manager) runTmuxCommandRunOnce(args []string) (string, error) {
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "tmux", args, cmdlog.GetCaller())
 
-	cmd := exec.Command("tmux", args...)
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "tmux")
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(timeoutCtx, "tmux", args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
+	cmd.Stderr = &stderr
 	start := time.Now()
 	err := cmd.Run()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "tmux", args, timeout, err)
 
 	if err != nil {
 		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
-		return err
+		return stderr.String(), err
 	}
 
 	ctx.LogCompletion(true, 0, "", duration)
-	return nil
+	return "", nil
 }
 
 // runTmuxCommandWithEnv executes a tmux command with custom environment variables
 func (m *Manager) runTmuxCommandWithEnv(args []string, env ...map[string]string) error {
-	ctx := cmdlog.LogCommandGlobal("tmux", args, cmdlog.GetCaller())
+	ctx := cmdlog.LogCommandContext(m.baseContext(), "tmux", args, cmdlog.GetCaller())
+
+	timeoutCtx, cancel, timeout := cmdtimeout.ContextFrom(m.baseContext(), "tmux")
+	defer cancel()
 
-	cmd := exec.Command("tmux", args...)
+	cmd := exec.CommandContext(timeoutCtx, "tmux", args...)
+	cmd.WaitDelay = cmdtimeout.WaitDelay
 
 	// Set environment variables for the tmux command
 	if len(env) > 0 && env[0] != nil {
@@ -484,6 +607,7 @@ func (m *Manager) runTmuxCommandWithEnv(args []string, env ...map[string]string)
 	start := time.Now()
 	err := cmd.Run()
 	duration := time.Since(start)
+	err = cmdtimeout.Check(timeoutCtx, "tmux", args, timeout, err)
 
 	if err != nil {
 		ctx.LogCompletion(false, getExitCode(cmd), err.Error(), duration)
@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -377,3 +378,18 @@ func TestManager_GenerateFriendlyTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_WithContext(t *testing.T) {
+	base := NewManager()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bound := base.WithContext(ctx)
+	assert.NotSame(t, base, bound)
+	assert.Equal(t, context.Background(), base.baseContext(), "the original manager is unchanged")
+	assert.Equal(t, ctx, bound.baseContext())
+
+	_, err := bound.runTmuxCommand([]string{"-V"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
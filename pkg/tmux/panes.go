@@ -0,0 +1,99 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Pane describes a tmux pane
+type Pane struct {
+	ID      string // Pane ID such as %3, usable as a target
+	Dead    bool   // The pane's process exited and the pane was kept (remain-on-exit)
+	Command string // Name of the process in the foreground of the pane
+}
+
+// shells are the programs a pane is back at once the command typed into it exits
+var shells = map[string]bool{"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true, "ksh": true, "tcsh": true, "csh": true}
+
+// Idle reports whether nothing runs in the pane any more: it is dead, or its
+// shell is back in the foreground because the command sent to it exited
+func (p Pane) Idle() bool {
+	if p.Dead {
+		return true
+	}
+	command := strings.TrimPrefix(p.Command, "-") // login shells
+	return shells[command] || command == filepath.Base(os.Getenv("SHELL"))
+}
+
+// PanePIDs returns the PIDs of the processes running in each session's panes
+// (usually shells), keyed by session name, using a single tmux call
+func (m *Manager) PanePIDs() (map[string][]int, error) {
+	output, err := m.runTmuxCommand([]string{"list-panes", "-a", "-F", "#{session_name}\t#{pane_pid}"})
+	if err != nil {
+		// No server running or no sessions exist
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return map[string][]int{}, nil
+		}
+		return nil, fmt.Errorf("failed to list tmux panes: %w", err)
+	}
+	return parsePanePIDs(string(output)), nil
+}
+
+// parsePanePIDs parses "session<TAB>pid" lines, skipping malformed ones
+func parsePanePIDs(output string) map[string][]int {
+	pids := make(map[string][]int)
+	for _, line := range strings.Split(output, "\n") {
+		name, pidText, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found {
+			continue
+		}
+		pid, err := strconv.Atoi(pidText)
+		if err != nil || pid <= 0 {
+			continue
+		}
+		pids[name] = append(pids[name], pid)
+	}
+	return pids
+}
+
+// PrimaryPane returns the first pane of a session's first window, where sbs
+// start types the session command
+func (m *Manager) PrimaryPane(sessionName string) (Pane, error) {
+	output, err := m.runTmuxCommand([]string{"list-panes", "-s", "-t", sessionName, "-F", "#{pane_id}\t#{pane_dead}\t#{pane_current_command}"})
+	if err != nil {
+		return Pane{}, fmt.Errorf("failed to list panes of session %s: %w", sessionName, err)
+	}
+	pane, ok := parsePrimaryPane(string(output))
+	if !ok {
+		return Pane{}, fmt.Errorf("session %s has no panes", sessionName)
+	}
+	return pane, nil
+}
+
+// parsePrimaryPane parses the first "id<TAB>dead<TAB>command" line; tmux lists
+// a session's panes in window and pane order
+func parsePrimaryPane(output string) (Pane, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) != 3 || fields[0] == "" {
+			continue
+		}
+		return Pane{ID: fields[0], Dead: fields[1] == "1", Command: fields[2]}, true
+	}
+	return Pane{}, false
+}
+
+// RespawnPane replaces whatever runs in a pane, dead or not, with a fresh
+// copy of the program it was created with (the shell, for sbs sessions)
+// started in workingDir
+func (m *Manager) RespawnPane(paneID, workingDir string) error {
+	args := []string{"respawn-pane", "-k", "-t", paneID, "-c", workingDir}
+	if err := m.runTmuxCommandRun(args); err != nil {
+		return fmt.Errorf("failed to respawn pane %s: %w", paneID, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package tmux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePanePIDs(t *testing.T) {
+	output := "work-issue-repo-1\t100\nwork-issue-repo-1\t101\nother\t200\n\nbroken line\nbad\tpid\n"
+
+	pids := parsePanePIDs(output)
+
+	assert.Equal(t, map[string][]int{
+		"work-issue-repo-1": {100, 101},
+		"other":             {200},
+	}, pids)
+}
+
+func TestParsePrimaryPane(t *testing.T) {
+	pane, ok := parsePrimaryPane("\n%3\t0\tclaude\n%4\t1\tbash\n")
+	assert.True(t, ok)
+	assert.Equal(t, Pane{ID: "%3", Command: "claude"}, pane)
+
+	pane, ok = parsePrimaryPane("%7\t1\tbash\n")
+	assert.True(t, ok)
+	assert.True(t, pane.Dead)
+
+	_, ok = parsePrimaryPane("malformed\n")
+	assert.False(t, ok)
+}
+
+func TestPane_Idle(t *testing.T) {
+	t.Setenv("SHELL", "/usr/local/bin/nu")
+
+	assert.True(t, Pane{Dead: true, Command: "claude"}.Idle(), "a dead pane runs nothing")
+	assert.True(t, Pane{Command: "bash"}.Idle(), "the shell is back once the command exits")
+	assert.True(t, Pane{Command: "-zsh"}.Idle())
+	assert.True(t, Pane{Command: "nu"}.Idle(), "the user's $SHELL counts as a shell")
+	assert.False(t, Pane{Command: "claude"}.Idle())
+	assert.False(t, Pane{Command: "sandbox"}.Idle())
+}
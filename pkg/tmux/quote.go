@@ -0,0 +1,59 @@
+package tmux
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// unsafeShellChars matches anything that needs quoting in a POSIX shell word
+var unsafeShellChars = regexp.MustCompile(`[^A-Za-z0-9_@%+=:,./-]`)
+
+// ShellQuote quotes s as a single POSIX shell word. Strings made only of safe
+// characters are returned unchanged.
+func ShellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !unsafeShellChars.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// CommandLine joins a command with its arguments for typing into a session.
+// The command is used verbatim, as it comes from trusted configuration; each
+// argument is substituted and then quoted as one word, so values such as issue
+// titles can't split into several arguments or run shell syntax.
+func CommandLine(command string, args []string, substitutions map[string]string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, command)
+	for _, arg := range args {
+		parts = append(parts, ShellQuote(substituteParameters(arg, substitutions)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// substituteParameters replaces parameter placeholders in a string in a single
+// pass, so a substituted value is never itself substituted
+func substituteParameters(input string, substitutions map[string]string) string {
+	if len(substitutions) == 0 {
+		return input
+	}
+	// Longer placeholders go first so "$10" isn't read as "$1" followed by "0"
+	placeholders := make([]string, 0, len(substitutions))
+	for placeholder := range substitutions {
+		placeholders = append(placeholders, placeholder)
+	}
+	sort.Slice(placeholders, func(i, j int) bool {
+		if len(placeholders[i]) != len(placeholders[j]) {
+			return len(placeholders[i]) > len(placeholders[j])
+		}
+		return placeholders[i] < placeholders[j]
+	})
+	pairs := make([]string, 0, len(substitutions)*2)
+	for _, placeholder := range placeholders {
+		pairs = append(pairs, placeholder, substitutions[placeholder])
+	}
+	return strings.NewReplacer(pairs...).Replace(input)
+}
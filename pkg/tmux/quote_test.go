@@ -0,0 +1,97 @@
+package tmux
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"safe word", "issue-123", "issue-123"},
+		{"path", "/home/user/.sbs/start", "/home/user/.sbs/start"},
+		{"empty", "", "''"},
+		{"spaces", "Fix login page", "'Fix login page'"},
+		{"single quote", "Don't panic", `'Don'\''t panic'`},
+		{"double quotes", `say "hi"`, `'say "hi"'`},
+		{"command substitution", "$(rm -rf ~)", "'$(rm -rf ~)'"},
+		{"backticks", "`id`", "'`id`'"},
+		{"separators", "a; b && c | d", "'a; b && c | d'"},
+		{"glob", "*.go", "'*.go'"},
+		{"newline", "line1\nline2", "'line1\nline2'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ShellQuote(tt.input))
+		})
+	}
+}
+
+func TestShellQuote_RoundTripsThroughShell(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	values := []string{
+		"plain",
+		"Fix login page",
+		"Don't break 'quoted' titles",
+		`Handle "double" quotes and \backslashes\`,
+		"$HOME $(whoami) `id` ${PATH}",
+		"semi; colon && and || pipe | redirect > out < in &",
+		"glob * ? [a-z] ~ # comment",
+		"tab\tand\nnewline",
+		"",
+	}
+	for _, value := range values {
+		output, err := exec.Command("sh", "-c", "printf '%s' "+ShellQuote(value)).Output()
+		require.NoError(t, err, value)
+		assert.Equal(t, value, string(output))
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	t.Run("no args leaves the command verbatim", func(t *testing.T) {
+		assert.Equal(t, "claude --continue", CommandLine("claude --continue", nil, nil))
+	})
+
+	t.Run("substituted values are quoted", func(t *testing.T) {
+		line := CommandLine("work-issue.sh", []string{"$1", "--title", "$2"}, map[string]string{
+			"$1": "42",
+			"$2": "Don't crash; rm -rf $HOME",
+		})
+		assert.Equal(t, `work-issue.sh 42 --title 'Don'\''t crash; rm -rf $HOME'`, line)
+	})
+
+	t.Run("arguments with spaces stay one word", func(t *testing.T) {
+		line := CommandLine("echo", []string{"hello world", "--flag=a b"}, nil)
+		assert.Equal(t, "echo 'hello world' '--flag=a b'", line)
+	})
+
+	t.Run("longer placeholders win", func(t *testing.T) {
+		line := CommandLine("run", []string{"$10", "$1"}, map[string]string{"$1": "one", "$10": "ten"})
+		assert.Equal(t, "run ten one", line)
+	})
+
+	t.Run("substituted values are not substituted again", func(t *testing.T) {
+		line := CommandLine("run", []string{"$1"}, map[string]string{"$1": "$2", "$2": "oops"})
+		assert.Equal(t, "run '$2'", line)
+	})
+
+	t.Run("shell sees the original words", func(t *testing.T) {
+		if _, err := exec.LookPath("sh"); err != nil {
+			t.Skip("sh not available")
+		}
+		title := `Title with "quotes", 'apostrophes' and $(subshell)`
+		line := CommandLine("printf '%s\\n'", []string{"$1", "$2"}, map[string]string{"$1": "123", "$2": title})
+		output, err := exec.Command("sh", "-c", line).Output()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"123", title}, strings.Split(strings.TrimSuffix(string(output), "\n"), "\n"))
+	})
+}
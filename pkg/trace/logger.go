@@ -0,0 +1,71 @@
+package trace
+
+import (
+	"context"
+	"time"
+
+	"sbs/pkg/cmdlog"
+)
+
+// commandLogger passes external commands on to the command log and records
+// them in the running trace, whatever the command log's level
+type commandLogger struct {
+	next cmdlog.Logger
+}
+
+// WrapLogger returns a command logger that records every command in the
+// running trace before handing it to next. A nil next records only.
+func WrapLogger(next cmdlog.Logger) cmdlog.Logger {
+	if next == nil {
+		next = cmdlog.NewCommandLogger(cmdlog.Config{})
+	}
+	return &commandLogger{next: next}
+}
+
+func (l *commandLogger) LogCommand(command string, args []string, caller string) cmdlog.CommandContext {
+	return l.LogCommandContext(context.Background(), command, args, caller)
+}
+
+func (l *commandLogger) LogCommandContext(ctx context.Context, command string, args []string, caller string) cmdlog.CommandContext {
+	var next cmdlog.CommandContext
+	if contextLogger, ok := l.next.(cmdlog.ContextLogger); ok {
+		next = contextLogger.LogCommandContext(ctx, command, args, caller)
+	} else {
+		next = l.next.LogCommand(command, args, caller)
+	}
+	return &commandContext{
+		next:          next,
+		command:       command,
+		args:          append([]string(nil), args...),
+		caller:        caller,
+		correlationID: cmdlog.CorrelationID(ctx),
+	}
+}
+
+// IsEnabled is always true: commands are traced even when the command log is off
+func (l *commandLogger) IsEnabled() bool {
+	return true
+}
+
+func (l *commandLogger) GetLevel() string {
+	return l.next.GetLevel()
+}
+
+// commandContext records a command in the trace once it completes
+type commandContext struct {
+	next          cmdlog.CommandContext
+	command       string
+	args          []string
+	caller        string
+	correlationID string
+}
+
+func (c *commandContext) LogCompletion(success bool, exitCode int, errorMsg string, duration time.Duration) {
+	c.next.LogCompletion(success, exitCode, errorMsg, duration)
+	if r := recorder(); r != nil {
+		if success {
+			errorMsg = ""
+		}
+		r.Command(c.command, c.args, c.caller, c.correlationID, exitCode, errorMsg, duration)
+	}
+}
@@ -0,0 +1,233 @@
+// Package trace records a transcript of one sbs invocation for bug reports:
+// the external commands it ran, the decisions it made along the way (which
+// config values were used, which templates rendered to what) and the state it
+// changed. The transcript is a single JSON Lines file, one record per line,
+// starting with an invocation record and ending with a result record.
+//
+//	if err := trace.Start(path, os.Args); err != nil { ... }
+//	defer trace.Stop(err)
+//	trace.Decision("branch", branch, "template "+branchname.Template())
+//	trace.StateChange("sessions", sessionsPath, "added github:123")
+//
+// Every package-level function does nothing while no trace is running, so
+// callers never need to check Enabled first.
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Kinds of trace records
+const (
+	KindInvocation = "invocation"
+	KindCommand    = "command"
+	KindDecision   = "decision"
+	KindState      = "state"
+	KindResult     = "result"
+)
+
+// Record is one line of a trace. Which fields are set depends on Kind.
+type Record struct {
+	Time string `json:"time"`
+	Kind string `json:"kind"`
+
+	// Invocation records
+	Version    string `json:"version,omitempty"`
+	GoVersion  string `json:"go_version,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+	WorkingDir string `json:"working_dir,omitempty"`
+
+	// Command records; Args also holds the invocation's arguments
+	Command       string   `json:"command,omitempty"`
+	Args          []string `json:"args,omitempty"`
+	Caller        string   `json:"caller,omitempty"`
+	CorrelationID string   `json:"op,omitempty"`
+	ExitCode      *int     `json:"exit_code,omitempty"`
+	Duration      string   `json:"duration,omitempty"`
+
+	// Decision records
+	Name   string `json:"name,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Source string `json:"source,omitempty"`
+
+	// State change records
+	Action string `json:"action,omitempty"`
+	Target string `json:"target,omitempty"`
+	Detail string `json:"detail,omitempty"`
+
+	// Command and result records
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder writes trace records to a writer. It is safe for concurrent use.
+type Recorder struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+	err     error
+	now     func() time.Time
+}
+
+// NewRecorder creates a recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w), now: time.Now}
+}
+
+// Create creates a recorder writing to a new file at path. The file is only
+// readable by the user, as command arguments and config values may be private.
+func Create(path string) (*Recorder, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create trace directory: %w", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace file: %w", err)
+	}
+	recorder := NewRecorder(file)
+	recorder.closer = file
+	return recorder, nil
+}
+
+// write stamps and appends a record. The first write error is kept for
+// Close; tracing never fails the traced operation.
+func (r *Recorder) write(record Record) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return
+	}
+	record.Time = r.now().Format(time.RFC3339Nano)
+	if err := r.encoder.Encode(record); err != nil {
+		r.err = fmt.Errorf("failed to write trace: %w", err)
+	}
+}
+
+// Invocation records what was run, by which build, and where
+func (r *Recorder) Invocation(args []string, version string) {
+	workingDir, _ := os.Getwd()
+	r.write(Record{
+		Kind:       KindInvocation,
+		Args:       args,
+		Version:    version,
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		WorkingDir: workingDir,
+	})
+}
+
+// Command records a finished external command
+func (r *Recorder) Command(command string, args []string, caller, correlationID string, exitCode int, errorMsg string, duration time.Duration) {
+	r.write(Record{
+		Kind:          KindCommand,
+		Command:       command,
+		Args:          args,
+		Caller:        caller,
+		CorrelationID: correlationID,
+		ExitCode:      &exitCode,
+		Duration:      duration.String(),
+		Error:         errorMsg,
+	})
+}
+
+// Decision records a value sbs settled on and where it came from, e.g. a
+// config setting and the file it was read from
+func (r *Recorder) Decision(name, value, source string) {
+	r.write(Record{Kind: KindDecision, Name: name, Value: value, Source: source})
+}
+
+// StateChange records a change sbs made outside its own process
+func (r *Recorder) StateChange(action, target, detail string) {
+	r.write(Record{Kind: KindState, Action: action, Target: target, Detail: detail})
+}
+
+// Close records the invocation's result and closes the trace file. It
+// returns the first error writing the trace hit.
+func (r *Recorder) Close(result error) error {
+	record := Record{Kind: KindResult}
+	if result != nil {
+		record.Error = result.Error()
+	}
+	r.write(record)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	err := r.err
+	if r.closer != nil {
+		if closeErr := r.closer.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close trace: %w", closeErr)
+		}
+		r.closer = nil
+	}
+	return err
+}
+
+var (
+	globalRecorder *Recorder
+	globalMutex    sync.RWMutex
+)
+
+// Start begins tracing this process to the file at path, recording the
+// invocation's arguments and sbs version first
+func Start(path string, args []string, version string) error {
+	recorder, err := Create(path)
+	if err != nil {
+		return err
+	}
+	recorder.Invocation(args, version)
+	SetGlobalRecorder(recorder)
+	return nil
+}
+
+// Stop records the invocation's result and closes the running trace, if any
+func Stop(result error) error {
+	globalMutex.Lock()
+	recorder := globalRecorder
+	globalRecorder = nil
+	globalMutex.Unlock()
+	if recorder == nil {
+		return nil
+	}
+	return recorder.Close(result)
+}
+
+// SetGlobalRecorder sets the recorder the package-level functions write to;
+// nil stops recording without closing the previous recorder
+func SetGlobalRecorder(recorder *Recorder) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	globalRecorder = recorder
+}
+
+// Enabled reports whether a trace is running
+func Enabled() bool {
+	return recorder() != nil
+}
+
+func recorder() *Recorder {
+	globalMutex.RLock()
+	defer globalMutex.RUnlock()
+	return globalRecorder
+}
+
+// Decision records a decision in the running trace
+func Decision(name, value, source string) {
+	if r := recorder(); r != nil {
+		r.Decision(name, value, source)
+	}
+}
+
+// StateChange records a state change in the running trace
+func StateChange(action, target, detail string) {
+	if r := recorder(); r != nil {
+		r.StateChange(action, target, detail)
+	}
+}
@@ -0,0 +1,156 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sbs/pkg/cmdlog"
+)
+
+// readRecords parses a trace, one record per line
+func readRecords(t *testing.T, data []byte) []Record {
+	t.Helper()
+	var records []Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record), "line: %s", scanner.Text())
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestRecorder_WritesOneRecordPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf)
+
+	recorder.Invocation([]string{"sbs", "start", "123"}, "v1.0.0")
+	recorder.Command("git", []string{"worktree", "add"}, "start.go:10", "op-1", 0, "", 20*time.Millisecond)
+	recorder.Decision("branch", "issue-github-123-fix", "branch_template issue-{source}-{id}-{title}")
+	recorder.StateChange("save sessions", "/tmp/sessions.json", "added github:123")
+	require.NoError(t, recorder.Close(errors.New("boom")))
+
+	records := readRecords(t, buf.Bytes())
+	require.Len(t, records, 5)
+
+	assert.Equal(t, KindInvocation, records[0].Kind)
+	assert.Equal(t, []string{"sbs", "start", "123"}, records[0].Args)
+	assert.Equal(t, "v1.0.0", records[0].Version)
+	assert.NotEmpty(t, records[0].GoVersion)
+	assert.NotEmpty(t, records[0].Time)
+
+	assert.Equal(t, KindCommand, records[1].Kind)
+	assert.Equal(t, "git", records[1].Command)
+	assert.Equal(t, "op-1", records[1].CorrelationID)
+	require.NotNil(t, records[1].ExitCode)
+	assert.Equal(t, 0, *records[1].ExitCode)
+	assert.Equal(t, "20ms", records[1].Duration)
+
+	assert.Equal(t, Record{Time: records[2].Time, Kind: KindDecision, Name: "branch",
+		Value: "issue-github-123-fix", Source: "branch_template issue-{source}-{id}-{title}"}, records[2])
+	assert.Equal(t, Record{Time: records[3].Time, Kind: KindState, Action: "save sessions",
+		Target: "/tmp/sessions.json", Detail: "added github:123"}, records[3])
+
+	assert.Equal(t, KindResult, records[4].Kind)
+	assert.Equal(t, "boom", records[4].Error)
+}
+
+func TestCreate_FileIsPrivate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "trace.jsonl")
+	recorder, err := Create(path)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close(nil))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	records := readRecords(t, data)
+	require.Len(t, records, 1)
+	assert.Equal(t, KindResult, records[0].Kind)
+	assert.Empty(t, records[0].Error)
+}
+
+func TestGlobal_NoOpWithoutTrace(t *testing.T) {
+	SetGlobalRecorder(nil)
+
+	assert.False(t, Enabled())
+	assert.NotPanics(t, func() {
+		Decision("branch", "x", "y")
+		StateChange("save sessions", "x", "y")
+	})
+	assert.NoError(t, Stop(nil))
+}
+
+func TestStartStop_RecordsInvocation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	require.NoError(t, Start(path, []string{"sbs", "clean"}, "v1.0.0"))
+	t.Cleanup(func() { SetGlobalRecorder(nil) })
+
+	assert.True(t, Enabled())
+	Decision("name scope", "", "name_scope ")
+	StateChange("cleanup worktree", "/tmp/wt", "Removed worktree: /tmp/wt")
+	require.NoError(t, Stop(nil))
+	assert.False(t, Enabled())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	records := readRecords(t, data)
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{KindInvocation, KindDecision, KindState, KindResult},
+		[]string{records[0].Kind, records[1].Kind, records[2].Kind, records[3].Kind})
+}
+
+func TestWrapLogger_TracesAndForwardsCommands(t *testing.T) {
+	var buf bytes.Buffer
+	SetGlobalRecorder(NewRecorder(&buf))
+	t.Cleanup(func() { SetGlobalRecorder(nil) })
+
+	mock := cmdlog.NewMockLogger(cmdlog.Config{Enabled: true, Level: "info"})
+	logger := WrapLogger(mock)
+	assert.True(t, logger.IsEnabled())
+	assert.Equal(t, "info", logger.GetLevel())
+
+	ctx := cmdlog.WithCorrelationID(context.Background(), "op-7")
+	logger.(cmdlog.ContextLogger).LogCommandContext(ctx, "tmux", []string{"new-session"}, "tmux.go:1").
+		LogCompletion(false, 1, "duplicate session", time.Second)
+	logger.LogCommand("git", []string{"status"}, "git.go:2").LogCompletion(true, 0, "ignored output", time.Millisecond)
+
+	entries := mock.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "op-7", entries[0].CorrelationID)
+
+	records := readRecords(t, buf.Bytes())
+	require.Len(t, records, 2)
+	assert.Equal(t, "tmux", records[0].Command)
+	assert.Equal(t, "op-7", records[0].CorrelationID)
+	assert.Equal(t, "duplicate session", records[0].Error)
+	assert.Equal(t, 1, *records[0].ExitCode)
+	assert.Equal(t, "git", records[1].Command)
+	assert.Empty(t, records[1].Error, "successful commands don't record their output")
+}
+
+func TestWrapLogger_TracesWithCommandLogOff(t *testing.T) {
+	var buf bytes.Buffer
+	SetGlobalRecorder(NewRecorder(&buf))
+	t.Cleanup(func() { SetGlobalRecorder(nil) })
+
+	logger := WrapLogger(cmdlog.NewCommandLogger(cmdlog.Config{Enabled: false}))
+	logger.LogCommand("gh", []string{"auth", "status"}, "").LogCompletion(true, 0, "", time.Millisecond)
+
+	records := readRecords(t, buf.Bytes())
+	require.Len(t, records, 1)
+	assert.Equal(t, "gh", records[0].Command)
+}
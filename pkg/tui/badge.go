@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"sbs/pkg/config"
+	"sbs/pkg/inputsource"
+)
+
+// SourceBadges renders the work item source badges of the ID column, as
+// configured by source_badges and source_badge_style
+type SourceBadges struct {
+	style     string
+	overrides map[string]config.SourceBadge
+}
+
+// NewSourceBadges creates a badge renderer from the config (nil for defaults)
+func NewSourceBadges(cfg *config.Config) SourceBadges {
+	if cfg == nil {
+		return SourceBadges{}
+	}
+	return SourceBadges{style: cfg.SourceBadgeStyle, overrides: cfg.SourceBadges}
+}
+
+// enabled reports whether badges are shown at all
+func (b SourceBadges) enabled() bool {
+	return b.style != "none"
+}
+
+// Label prefixes a work item ID with its source's icon, e.g. "GH github:123"
+func (b SourceBadges) Label(source, id string) string {
+	if !b.enabled() {
+		return id
+	}
+	return inputsource.SourceBadge(source, b.overrides).Icon + " " + id
+}
+
+// Colorize colors the source icon at the start of text, a Label that may have
+// been padded or truncated since. Text without the icon is returned unchanged.
+func (b SourceBadges) Colorize(text, source string) string {
+	if !b.enabled() || b.style == "icon" {
+		return text
+	}
+	badge := inputsource.SourceBadge(source, b.overrides)
+	if !strings.HasPrefix(text, badge.Icon+" ") {
+		return text
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(badge.Color))
+	return style.Render(badge.Icon) + text[len(badge.Icon):]
+}
+
+// sourceBadges returns the badge renderer for the current config
+func (m Model) sourceBadges() SourceBadges {
+	return NewSourceBadges(m.config)
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"sbs/pkg/config"
+)
+
+func TestSourceBadges_Label(t *testing.T) {
+	assert.Equal(t, "GH github:1", NewSourceBadges(nil).Label("github", "github:1"))
+
+	cfg := &config.Config{SourceBadges: map[string]config.SourceBadge{"test": {Icon: "TST"}}}
+	assert.Equal(t, "TST test:a", NewSourceBadges(cfg).Label("test", "test:a"))
+
+	cfg = &config.Config{SourceBadgeStyle: "none"}
+	assert.Equal(t, "github:1", NewSourceBadges(cfg).Label("github", "github:1"))
+}
+
+func TestSourceBadges_Colorize(t *testing.T) {
+	row := "GH github:1   Fix login"
+
+	colored := NewSourceBadges(nil).Colorize(row, "github")
+	assert.Equal(t, row, ansi.Strip(colored), "only styling is added")
+
+	iconOnly := NewSourceBadges(&config.Config{SourceBadgeStyle: "icon"})
+	assert.Equal(t, row, iconOnly.Colorize(row, "github"))
+
+	assert.Equal(t, "github:1", NewSourceBadges(nil).Colorize("github:1", "github"), "text without the icon is unchanged")
+}
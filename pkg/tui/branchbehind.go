@@ -0,0 +1,18 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"sbs/pkg/behind"
+	"sbs/pkg/config"
+)
+
+// behindMarker follows the status of sessions whose branch is far behind its base
+const behindMarker = "↓"
+
+// formatBranchBehind describes how far a branch is behind and how to catch up
+func formatBranchBehind(session config.SessionMetadata, stat behind.Stat, now time.Time) string {
+	return fmt.Sprintf("%s Branch is %s (run 'sbs sync %s' to rebase before it gets harder)",
+		behindMarker, stat.Describe(now), session.NamespacedID)
+}
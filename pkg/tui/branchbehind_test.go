@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/behind"
+	"sbs/pkg/config"
+)
+
+// fakeBehindRepository reports issue-1 far behind main and every other branch current
+type fakeBehindRepository struct {
+	now time.Time
+}
+
+func (fakeBehindRepository) DefaultBaseBranch() (string, error) { return "main", nil }
+
+func (fakeBehindRepository) BranchTips(branch, base string) (string, string, error) {
+	return "tip-" + branch, "tip-" + base, nil
+}
+
+func (r fakeBehindRepository) CommitsBehind(branch, base string) (int, time.Time, error) {
+	if branch == "issue-1" {
+		return 63, r.now.Add(-12 * 24 * time.Hour), nil
+	}
+	return 2, r.now.Add(-time.Hour), nil
+}
+
+func TestBranchBehindWarning(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 2)
+	for i := range model.sessions {
+		model.sessions[i].RepositoryRoot = "/repo"
+	}
+	now := config.Now()
+	model.behindCache = behind.NewCache(filepath.Join(t.TempDir(), "behind-cache.json")).
+		WithOpener(func(string) (behind.Repository, error) { return fakeBehindRepository{now: now}, nil })
+
+	stats := model.refreshBehind(model.sessions)
+	require.Len(t, stats, 2)
+
+	updated, _ := model.Update(refreshMsg{sessions: model.sessions, behind: stats})
+	model = updated.(Model)
+
+	view := model.View()
+	assert.Equal(t, 2, strings.Count(view, behindMarker), "the flagged row and the note under the table")
+	assert.Contains(t, view, "Branch is 63 commits behind main, the oldest from 12 days ago (run 'sbs sync github:1' to rebase before it gets harder)")
+
+	model.cursor = 1
+	assert.NotContains(t, model.View(), "Branch is", "branches close to their base aren't flagged")
+
+	model.config = &config.Config{BranchBehindCommits: -1, BranchBehindDays: -1}
+	assert.Nil(t, model.refreshBehind(model.sessions), "off when both thresholds are disabled")
+}
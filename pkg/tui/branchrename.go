@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+
+	"sbs/pkg/config"
+	"sbs/pkg/git"
+)
+
+// branchRenames finds sessions whose branch was renamed outside sbs, mapping
+// namespaced IDs to the new branch names. HEAD is read from disk, so git is
+// only consulted for worktrees whose HEAD no longer matches the session.
+func branchRenames(sessions []config.SessionMetadata) map[string]string {
+	renamed := make(map[string]string)
+	managers := make(map[string]*git.Manager)
+	for _, session := range sessions {
+		if session.NamespacedID == "" || session.WorktreePath == "" || session.Branch == "" {
+			continue
+		}
+		head, err := git.WorktreeHead(session.WorktreePath)
+		if err != nil || head == "" || head == session.Branch {
+			continue
+		}
+
+		manager, opened := managers[session.RepositoryRoot]
+		if !opened {
+			manager, _ = git.NewManager(session.RepositoryRoot)
+			managers[session.RepositoryRoot] = manager
+		}
+		if manager == nil {
+			continue
+		}
+		if branch, err := manager.DetectBranchRename(session.Branch, session.WorktreePath); err == nil && branch != "" {
+			renamed[session.NamespacedID] = branch
+		}
+	}
+	return renamed
+}
+
+// formatBranchRename describes a renamed branch and how to record the new name
+func formatBranchRename(session config.SessionMetadata, newBranch string) string {
+	return fmt.Sprintf("Branch renamed: %s → %s (run 'sbs repair-branch %s' to update the session)",
+		session.Branch, newBranch, session.NamespacedID)
+}
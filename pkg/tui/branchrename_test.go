@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func TestBranchRenames(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	repoDir := filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+	run := func(cwd string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cwd
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	run(repoDir, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "README"), []byte("hi\n"), 0644))
+	run(repoDir, "add", ".")
+	run(repoDir, "commit", "-q", "-m", "initial")
+	run(repoDir, "worktree", "add", "-q", "-b", "issue-test-1-old", filepath.Join(dir, "one"))
+	run(repoDir, "worktree", "add", "-q", "-b", "issue-test-2-kept", filepath.Join(dir, "two"))
+	run(filepath.Join(dir, "one"), "branch", "-m", "issue-test-1-renamed")
+
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:1", Branch: "issue-test-1-old", WorktreePath: filepath.Join(dir, "one"), RepositoryRoot: repoDir},
+		{NamespacedID: "test:2", Branch: "issue-test-2-kept", WorktreePath: filepath.Join(dir, "two"), RepositoryRoot: repoDir},
+		{NamespacedID: "test:3", Branch: "issue-test-3", WorktreePath: filepath.Join(dir, "gone"), RepositoryRoot: repoDir},
+	}
+
+	renamed := branchRenames(sessions)
+	assert.Equal(t, map[string]string{"test:1": "issue-test-1-renamed"}, renamed)
+	assert.Equal(t, "Branch renamed: issue-test-1-old → issue-test-1-renamed (run 'sbs repair-branch test:1' to update the session)",
+		formatBranchRename(sessions[0], renamed["test:1"]))
+}
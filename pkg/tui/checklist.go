@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checklistItem is one row of a checklist
+type checklistItem struct {
+	label   string
+	detail  string // Shown muted after the label, e.g. why the item is listed
+	checked bool
+}
+
+// checklist is a list of items ticked and unticked with the arrow keys and
+// space. It pages like the session table when it has more rows than fit.
+type checklist struct {
+	items    []checklistItem
+	cursor   int
+	pageSize int
+}
+
+// newChecklist returns a checklist showing pageSize rows at a time (0 for all)
+func newChecklist(items []checklistItem, pageSize int) *checklist {
+	return &checklist{items: items, pageSize: pageSize}
+}
+
+// handleKey moves the cursor or toggles items and reports whether the key was used
+func (c *checklist) handleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "up", "k":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "down", "j":
+		if c.cursor < len(c.items)-1 {
+			c.cursor++
+		}
+	case " ", "x":
+		if len(c.items) > 0 {
+			c.items[c.cursor].checked = !c.items[c.cursor].checked
+		}
+	case "a":
+		// Check everything, or uncheck everything when all are checked
+		all := c.checkedCount() == len(c.items)
+		for i := range c.items {
+			c.items[i].checked = !all
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// checked returns the indexes of the checked items
+func (c *checklist) checked() []int {
+	var indexes []int
+	for i, item := range c.items {
+		if item.checked {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// checkedCount returns how many items are checked
+func (c *checklist) checkedCount() int {
+	return len(c.checked())
+}
+
+// view renders the page of items containing the cursor
+func (c *checklist) view() string {
+	var b strings.Builder
+	start, end := visibleRange(len(c.items), c.cursor, c.pageSize)
+	for i := start; i < end; i++ {
+		item := c.items[i]
+		box := "[ ]"
+		if item.checked {
+			box = "[x]"
+		}
+		pointer := "  "
+		if i == c.cursor {
+			pointer = "> "
+		}
+		row := pointer + box + " " + item.label
+		if i == c.cursor {
+			row = selectedItemStyle.Render(row)
+		}
+		if item.detail != "" {
+			row += " " + mutedStyle.Render("("+item.detail+")")
+		}
+		b.WriteString(row + "\n")
+	}
+	if end-start < len(c.items) {
+		pages := (len(c.items) + c.pageSize - 1) / c.pageSize
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("Showing %d-%d of %d (page %d/%d)",
+			start+1, end, len(c.items), start/c.pageSize+1, pages)) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/cleanup"
+	"sbs/pkg/config"
+)
+
+func keyRunes(s string) tea.KeyMsg {
+	if s == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestChecklist(t *testing.T) {
+	list := newChecklist([]checklistItem{
+		{label: "one", checked: true},
+		{label: "two", checked: true},
+		{label: "three", checked: true},
+	}, 2)
+
+	assert.True(t, list.handleKey(tea.KeyMsg{Type: tea.KeyDown}))
+	assert.True(t, list.handleKey(keyRunes(" ")))
+	assert.Equal(t, []int{0, 2}, list.checked())
+
+	// The cursor stops at the ends
+	list.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	list.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 2, list.cursor)
+	list.handleKey(keyRunes("k"))
+	assert.Equal(t, 1, list.cursor)
+
+	// "a" checks everything, then unchecks everything
+	list.handleKey(keyRunes("a"))
+	assert.Equal(t, 3, list.checkedCount())
+	list.handleKey(keyRunes("a"))
+	assert.Equal(t, 0, list.checkedCount())
+
+	assert.False(t, list.handleKey(keyRunes("y")), "unrelated keys are left to the caller")
+
+	// Only the page with the cursor is shown
+	view := list.view()
+	assert.Contains(t, view, "[ ] one")
+	assert.Contains(t, view, "two")
+	assert.NotContains(t, view, "three")
+	assert.Contains(t, view, "Showing 1-2 of 3 (page 1/2)")
+}
+
+func TestCleanConfirmation_Checklist(t *testing.T) {
+	model := setupTestModel()
+	model.sessions = []config.SessionMetadata{
+		{NamespacedID: "test:1", IssueTitle: "First", TmuxSession: "sbs-test-1"},
+		{NamespacedID: "test:2", IssueTitle: "Second", TmuxSession: "sbs-test-2"},
+	}
+
+	model = model.showCleanConfirmation()
+	require.True(t, model.showConfirmationDialog)
+	require.NotNil(t, model.cleanChecklist)
+	assert.Contains(t, model.confirmationMessage, "[x] Work Item test:1: First")
+	assert.Contains(t, model.confirmationMessage, cleanup.StaleReasonTmuxGone)
+	assert.Contains(t, model.confirmationMessage, "2 of 2 selected")
+
+	// Exclude the first session
+	updated, _ := model.Update(keyRunes(" "))
+	model = updated.(Model)
+	assert.True(t, model.showConfirmationDialog)
+	assert.Contains(t, model.confirmationMessage, "[ ] Work Item test:1: First")
+	assert.Contains(t, model.confirmationMessage, "1 of 2 selected")
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	assert.False(t, model.showConfirmationDialog)
+	assert.Nil(t, model.cleanChecklist)
+	require.NotNil(t, cmd)
+	require.Len(t, model.pendingCleanSessions, 1)
+	assert.Equal(t, "test:2", model.pendingCleanSessions[0].NamespacedID)
+}
+
+func TestCleanConfirmation_NothingSelected(t *testing.T) {
+	model := setupTestModel()
+	model = model.showCleanConfirmation()
+	require.NotNil(t, model.cleanChecklist)
+
+	updated, _ := model.Update(keyRunes("a"))
+	model = updated.(Model)
+	assert.Contains(t, model.confirmationMessage, "0 of 2 selected")
+
+	updated, _ = model.Update(keyRunes("y"))
+	model = updated.(Model)
+	assert.False(t, model.showConfirmationDialog)
+	assert.Empty(t, model.pendingCleanSessions, "nothing is cleaned")
+	assert.Equal(t, "No sessions selected; nothing cleaned", model.notice)
+}
+
+func TestCleanConfirmation_Cancel(t *testing.T) {
+	model := setupTestModel()
+	model = model.showCleanConfirmation()
+
+	updated, cmd := model.Update(keyRunes("n"))
+	model = updated.(Model)
+	assert.Nil(t, cmd)
+	assert.False(t, model.showConfirmationDialog)
+	assert.Nil(t, model.cleanChecklist)
+	assert.Empty(t, model.pendingCleanSessions)
+}
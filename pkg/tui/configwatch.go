@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/config"
+)
+
+// noticeDuration is how long a successful reload notice stays on the status line
+const noticeDuration = 5 * time.Second
+
+// configWatchPaths returns the config files that affect this TUI instance
+func (m Model) configWatchPaths() []string {
+	var paths []string
+	if globalPath, err := config.GlobalConfigPath(); err == nil {
+		paths = append(paths, globalPath)
+	}
+	if m.currentRepo != nil {
+		paths = append(paths, config.RepositoryConfigPath(m.currentRepo.Root))
+	}
+	return paths
+}
+
+// startConfigWatch begins watching the config files in the background
+func (m Model) startConfigWatch() tea.Cmd {
+	if m.configWatcher != nil {
+		return nil
+	}
+
+	paths := m.configWatchPaths()
+	return func() tea.Msg {
+		watcher, err := config.NewWatcher(paths...)
+		if err != nil {
+			// Hot reload is best-effort; the TUI works without it
+			return nil
+		}
+		return configWatchStartedMsg{watcher: watcher}
+	}
+}
+
+// waitForConfigChange waits for the next change to a watched config file
+func (m Model) waitForConfigChange() tea.Cmd {
+	if m.configWatcher == nil {
+		return nil
+	}
+
+	changes := m.configWatcher.Changes()
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return configChangedMsg{closed: true}
+		}
+		return configChangedMsg{}
+	}
+}
+
+// reloadConfig loads and validates the global and repository config
+func (m Model) reloadConfig() tea.Cmd {
+	repoRoot := ""
+	if m.currentRepo != nil {
+		repoRoot = m.currentRepo.Root
+	}
+
+	return func() tea.Msg {
+		cfg, err := config.ReloadConfig(repoRoot)
+		return configReloadedMsg{config: cfg, err: err}
+	}
+}
+
+// applyConfig applies the settings that are safe to change while running:
+// refresh intervals, log display options, theme, key bindings, source badges and
+// repository groups. Other settings (paths, commands, tmux control mode,
+// timeouts) take effect on restart.
+func (m Model) applyConfig(updated *config.Config) Model {
+	applied := *m.config
+	applied.StatusTracking = updated.StatusTracking
+	applied.StatusRefreshIntervalSecs = updated.StatusRefreshIntervalSecs
+	applied.LogRefreshIntervalSecs = updated.LogRefreshIntervalSecs
+	applied.LoghookIntervals = updated.LoghookIntervals
+	applied.LoghookMinIntervalSecs = updated.LoghookMinIntervalSecs
+	applied.LoghookMaxIntervalSecs = updated.LoghookMaxIntervalSecs
+	applied.LoghookMaxOutputBytes = updated.LoghookMaxOutputBytes
+	applied.LogHighlightRules = updated.LogHighlightRules
+	applied.Theme = updated.Theme
+	applied.KeyBindings = updated.KeyBindings
+	applied.SourceBadges = updated.SourceBadges
+	applied.SourceBadgeStyle = updated.SourceBadgeStyle
+	applied.RepositoryGroups = updated.RepositoryGroups
+	m.config = &applied
+	if _, ok := applied.RepositoryGroups[m.repoGroup]; !ok {
+		m.repoGroup = "" // The group the view was scoped to is gone
+	}
+
+	ApplyTheme(applied.Theme)
+	ApplyKeyBindings(applied.KeyBindings)
+	m.logHighlighter = buildLogHighlighter(&applied)
+	m.rowCache.clear() // Cached rows carry the old theme's styles
+
+	// Pick up new loghook intervals and limits for an open log view
+	m.applyLogSettings()
+
+	return m
+}
+
+// clearNoticeAfter clears the notice after a delay unless it has been replaced
+func clearNoticeAfter(notice string, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return clearNoticeMsg{notice: notice}
+	})
+}
+
+// renderNotice renders the status-line notice
+func (m Model) renderNotice() string {
+	if m.noticeIsError {
+		return errorStyle.Render(m.notice)
+	}
+	return statusActiveStyle.Render(m.notice)
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/repo"
+	"sbs/pkg/testsupport"
+)
+
+func newConfigTestModel(t *testing.T) Model {
+	t.Helper()
+	t.Cleanup(func() {
+		ApplyTheme(nil)
+		ApplyKeyBindings(nil)
+	})
+	return NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    testsupport.NewFakeTmuxManager(),
+		Sandbox: testsupport.NewFakeSandboxManager(),
+		Cleanup: &testsupport.FakeSessionCleaner{},
+	})
+}
+
+func TestConfigReload_AppliesSafeSettings(t *testing.T) {
+	model := newConfigTestModel(t)
+	originalWorktreeBase := model.config.WorktreeBasePath
+
+	updated := config.DefaultConfig()
+	updated.StatusRefreshIntervalSecs = 15
+	updated.LogRefreshIntervalSecs = 2
+	updated.LoghookIntervals = map[string]int{"tests": 30}
+	updated.Theme = map[string]string{"primary": "#123456"}
+	updated.KeyBindings = map[string][]string{"refresh": {"f5"}}
+	updated.WorktreeBasePath = "/elsewhere" // Requires restart
+
+	result, cmd := model.Update(configReloadedMsg{config: updated})
+	model = result.(Model)
+
+	assert.Equal(t, 15, model.config.StatusRefreshIntervalSecs)
+	assert.Equal(t, 2, model.config.LogRefreshIntervalSecs)
+	assert.Equal(t, map[string]int{"tests": 30}, model.config.LoghookIntervals)
+	assert.Equal(t, originalWorktreeBase, model.config.WorktreeBasePath, "unsafe settings are not applied live")
+	assert.Equal(t, lipgloss.Color("#123456"), primaryColor)
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyF5}, keys.Refresh))
+	assert.False(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}}, keys.Refresh))
+
+	assert.Equal(t, "Config reloaded", model.notice)
+	assert.False(t, model.noticeIsError)
+	assert.NotNil(t, cmd, "successful reload schedules clearing the notice")
+	assert.Contains(t, model.View(), "Config reloaded")
+}
+
+func TestConfigReload_InvalidKeepsPreviousConfig(t *testing.T) {
+	model := newConfigTestModel(t)
+	previous := model.config
+
+	result, _ := model.Update(configReloadedMsg{err: errors.New("theme.primary must be a hex color")})
+	model = result.(Model)
+
+	assert.Same(t, previous, model.config)
+	assert.True(t, model.noticeIsError)
+	assert.Contains(t, model.notice, "Config invalid")
+	assert.Contains(t, model.View(), "theme.primary must be a hex color")
+}
+
+func TestConfigReload_ClearNotice(t *testing.T) {
+	model := newConfigTestModel(t)
+	model.notice = "Config reloaded"
+
+	// A stale clear message doesn't remove a newer notice
+	result, _ := model.Update(clearNoticeMsg{notice: "something else"})
+	model = result.(Model)
+	assert.Equal(t, "Config reloaded", model.notice)
+
+	result, _ = model.Update(clearNoticeMsg{notice: "Config reloaded"})
+	model = result.(Model)
+	assert.Empty(t, model.notice)
+}
+
+func TestConfigReload_UpdatesOpenLogTabs(t *testing.T) {
+	model := newConfigTestModel(t)
+	model.logView = &LogView{tabs: []logTab{{}, {}}}
+	model.logView.tabs[0].source.Name = "tests"
+	model.logView.tabs[1].source.Name = "build"
+
+	updated := config.DefaultConfig()
+	updated.LoghookIntervals = map[string]int{"tests": 12}
+	model = model.applyConfig(updated)
+
+	assert.Equal(t, 12*time.Second, model.logView.tabs[0].interval)
+	assert.Zero(t, model.logView.tabs[1].interval)
+}
+
+func TestConfigWatchPaths(t *testing.T) {
+	t.Setenv("HOME", "/home/tester")
+	model := newConfigTestModel(t)
+
+	assert.Equal(t, []string{"/home/tester/.config/sbs/config.json"}, model.configWatchPaths())
+
+	model.currentRepo = &repo.Repository{Root: "/src/project"}
+	assert.Equal(t, []string{"/home/tester/.config/sbs/config.json", "/src/project/.sbs/config.json"}, model.configWatchPaths())
+}
+
+func TestConfigChangedMsg_Closed(t *testing.T) {
+	model := newConfigTestModel(t)
+	model.configWatcher = &config.Watcher{}
+
+	result, cmd := model.Update(configChangedMsg{closed: true})
+	assert.Nil(t, result.(Model).configWatcher)
+	assert.Nil(t, cmd)
+}
+
+func TestApplyKeyBindings(t *testing.T) {
+	t.Cleanup(func() { ApplyKeyBindings(nil) })
+
+	ApplyKeyBindings(map[string][]string{"quit": {"x", "ctrl+q"}, "unknown": {"z"}, "stop": {}})
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}, keys.Quit))
+	assert.Equal(t, "x/ctrl+q", keys.Quit.Help().Key)
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}, keys.Stop), "empty lists keep the default")
+
+	ApplyKeyBindings(nil)
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}, keys.Quit))
+	require.False(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}, keys.Quit))
+}
+
+func TestApplyTheme(t *testing.T) {
+	t.Cleanup(func() { ApplyTheme(nil) })
+
+	ApplyTheme(map[string]string{"accent": "42"})
+	assert.Equal(t, lipgloss.Color("42"), accentColor)
+	assert.Equal(t, defaultThemeColors["primary"], primaryColor)
+
+	ApplyTheme(nil)
+	assert.Equal(t, defaultThemeColors["accent"], accentColor)
+}
@@ -0,0 +1,408 @@
+package tui
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/config"
+	"sbs/pkg/status"
+)
+
+const (
+	// maxDashboardEvents bounds the event feed history
+	maxDashboardEvents = 100
+
+	// dashboardDiskUsageInterval is how often worktree disk usage is recalculated
+	dashboardDiskUsageInterval = 5 * time.Minute
+)
+
+// DashboardEventKind describes what happened to a session
+type DashboardEventKind string
+
+const (
+	DashboardEventStarted DashboardEventKind = "started"
+	DashboardEventRemoved DashboardEventKind = "removed"
+	DashboardEventStatus  DashboardEventKind = "status"
+	DashboardEventRemote  DashboardEventKind = "remote" // New remote status, from sbs pr or the worktree inbox
+)
+
+// DashboardEvent is one entry in the dashboard event feed
+type DashboardEvent struct {
+	Time       time.Time
+	Kind       DashboardEventKind
+	SessionID  string
+	Repository string
+	From       string
+	To         string
+}
+
+// dashboardRow is a cached, fully-resolved session row so the dashboard
+// renders from the last snapshot instead of re-detecting status on every frame
+type dashboardRow struct {
+	session config.SessionMetadata
+	status  status.SessionStatus
+}
+
+// dashboardState holds the cumulative data shown by the dashboard view
+type dashboardState struct {
+	rows       []dashboardRow
+	statuses   map[string]string // Last observed status by session key
+	remotes    map[string]string // Last observed remote status by session key
+	seeded     bool              // First observation only records a baseline
+	events     []DashboardEvent  // Newest last
+	diskUsage  map[string]int64  // Bytes used by each worktree path
+	diskLoaded time.Time         // When disk usage was last calculated
+	diskBusy   bool
+}
+
+func newDashboardState() *dashboardState {
+	return &dashboardState{
+		statuses:  make(map[string]string),
+		remotes:   make(map[string]string),
+		diskUsage: make(map[string]int64),
+	}
+}
+
+// dashboardDiskUsageMsg delivers recalculated worktree disk usage
+type dashboardDiskUsageMsg struct {
+	usage map[string]int64
+}
+
+// dashboardSessionKey returns a stable key for a session across refreshes
+func dashboardSessionKey(session config.SessionMetadata) string {
+	if session.NamespacedID != "" {
+		return session.RepositoryRoot + "|" + session.NamespacedID
+	}
+	return fmt.Sprintf("%s|%d", session.RepositoryRoot, session.IssueNumber)
+}
+
+// dashboardSessionLabel returns the work item ID shown for a session
+func dashboardSessionLabel(session config.SessionMetadata) string {
+	if session.NamespacedID != "" {
+		return session.NamespacedID
+	}
+	return fmt.Sprintf("#%d", session.IssueNumber)
+}
+
+// observe records a new snapshot of sessions, appending feed events for
+// sessions that appeared, disappeared, changed status or got a new remote
+// status since the last snapshot
+func (d *dashboardState) observe(sessions []config.SessionMetadata, detect func(config.SessionMetadata) status.SessionStatus, now time.Time) {
+	rows := make([]dashboardRow, 0, len(sessions))
+	current := make(map[string]string, len(sessions))
+	remotes := make(map[string]string, len(sessions))
+
+	for _, session := range sessions {
+		sessionStatus := detect(session)
+		rows = append(rows, dashboardRow{session: session, status: sessionStatus})
+
+		key := dashboardSessionKey(session)
+		current[key] = sessionStatus.Status
+		remotes[key] = session.RemoteStatus
+
+		if !d.seeded {
+			continue
+		}
+
+		previous, known := d.statuses[key]
+		switch {
+		case !known:
+			d.addEvent(DashboardEvent{
+				Time:       now,
+				Kind:       DashboardEventStarted,
+				SessionID:  dashboardSessionLabel(session),
+				Repository: session.RepositoryName,
+				To:         sessionStatus.Status,
+			})
+		case previous != sessionStatus.Status:
+			d.addEvent(DashboardEvent{
+				Time:       now,
+				Kind:       DashboardEventStatus,
+				SessionID:  dashboardSessionLabel(session),
+				Repository: session.RepositoryName,
+				From:       previous,
+				To:         sessionStatus.Status,
+			})
+		}
+		if known && session.RemoteStatus != "" && session.RemoteStatus != d.remotes[key] {
+			d.addEvent(DashboardEvent{
+				Time:       now,
+				Kind:       DashboardEventRemote,
+				SessionID:  dashboardSessionLabel(session),
+				Repository: session.RepositoryName,
+				From:       d.remotes[key],
+				To:         session.RemoteStatus,
+			})
+		}
+	}
+
+	if d.seeded {
+		// Sessions that are gone from the metadata were cleaned or removed
+		var removed []string
+		for key := range d.statuses {
+			if _, ok := current[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+		sort.Strings(removed)
+		for _, key := range removed {
+			session := d.sessionForKey(key)
+			d.addEvent(DashboardEvent{
+				Time:       now,
+				Kind:       DashboardEventRemoved,
+				SessionID:  dashboardSessionLabel(session),
+				Repository: session.RepositoryName,
+				From:       d.statuses[key],
+			})
+		}
+	}
+
+	d.rows = rows
+	d.statuses = current
+	d.remotes = remotes
+	d.seeded = true
+}
+
+// sessionForKey finds the last known metadata for a session key
+func (d *dashboardState) sessionForKey(key string) config.SessionMetadata {
+	for _, row := range d.rows {
+		if dashboardSessionKey(row.session) == key {
+			return row.session
+		}
+	}
+	return config.SessionMetadata{}
+}
+
+func (d *dashboardState) addEvent(event DashboardEvent) {
+	d.events = append(d.events, event)
+	if len(d.events) > maxDashboardEvents {
+		d.events = d.events[len(d.events)-maxDashboardEvents:]
+	}
+}
+
+// diskUsageDue reports whether worktree disk usage should be recalculated
+func (d *dashboardState) diskUsageDue(now time.Time) bool {
+	return !d.diskBusy && now.Sub(d.diskLoaded) >= dashboardDiskUsageInterval
+}
+
+// DashboardStats summarizes sessions across all repositories
+type DashboardStats struct {
+	Total          int
+	Active         int
+	Waiting        int
+	Stopped        int
+	Stale          int
+	Unknown        int
+	SessionsByRepo map[string]int
+	DiskUsage      int64
+}
+
+// stats computes cumulative statistics from the last snapshot
+func (d *dashboardState) stats() DashboardStats {
+	stats := DashboardStats{SessionsByRepo: make(map[string]int)}
+	for _, row := range d.rows {
+		stats.Total++
+		switch row.status.Status {
+		case "active":
+			stats.Active++
+		case "waiting":
+			stats.Waiting++
+		case "stopped":
+			stats.Stopped++
+		case "stale", config.StatusRepoMissing:
+			stats.Stale++
+		default:
+			stats.Unknown++
+		}
+
+		repoName := row.session.RepositoryName
+		if repoName == "" {
+			repoName = "(unknown)"
+		}
+		stats.SessionsByRepo[repoName]++
+		stats.DiskUsage += d.diskUsage[row.session.WorktreePath]
+	}
+	return stats
+}
+
+// calculateDiskUsage sums file sizes under each worktree path
+func calculateDiskUsage(paths []string) map[string]int64 {
+	usage := make(map[string]int64, len(paths))
+	for _, root := range paths {
+		if root == "" {
+			continue
+		}
+		var total int64
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if entry.Type().IsRegular() {
+				if info, infoErr := entry.Info(); infoErr == nil {
+					total += info.Size()
+				}
+			}
+			return nil
+		})
+		usage[root] = total
+	}
+	return usage
+}
+
+// refreshDiskUsage recalculates worktree disk usage in the background
+func (m Model) refreshDiskUsage() tea.Cmd {
+	if m.dashboard == nil || len(m.dashboard.rows) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(m.dashboard.rows))
+	for _, row := range m.dashboard.rows {
+		paths = append(paths, row.session.WorktreePath)
+	}
+
+	return func() tea.Msg {
+		return dashboardDiskUsageMsg{usage: calculateDiskUsage(paths)}
+	}
+}
+
+// formatBytes renders a byte count using binary units
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// formatDashboardEvent renders one event feed line
+func formatDashboardEvent(event DashboardEvent) string {
+	subject := event.SessionID
+	if event.Repository != "" {
+		subject = event.Repository + "/" + event.SessionID
+	}
+
+	var text string
+	switch event.Kind {
+	case DashboardEventStarted:
+		text = fmt.Sprintf("%s started (%s)", subject, event.To)
+	case DashboardEventRemoved:
+		text = fmt.Sprintf("%s removed", subject)
+	case DashboardEventRemote:
+		text = fmt.Sprintf("%s reported %s", subject, event.To)
+	default:
+		text = fmt.Sprintf("%s %s → %s", subject, event.From, event.To)
+	}
+
+	return mutedStyle.Render(event.Time.Format("15:04:05")) + " " + text
+}
+
+// dashboardTableRows returns how many session rows the dashboard shows,
+// leaving about half of the screen below the stats for the event feed
+func (m Model) dashboardTableRows() int {
+	if m.height <= 0 {
+		return maxRenderedRows
+	}
+	return max(3, min(maxRenderedRows, (m.height-dashboardChromeLines)/2))
+}
+
+// dashboardChromeLines is the space used by the title, stats and headers
+const dashboardChromeLines = 14
+
+// renderDashboard renders the global session table, cumulative stats and event feed
+func (m Model) renderDashboard() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Work Issue Orchestrator (Dashboard)") + "\n\n")
+
+	d := m.dashboard
+	if d == nil {
+		d = newDashboardState()
+	}
+
+	// Stats
+	stats := d.stats()
+	b.WriteString(headerStyle.Render("Stats") + "\n")
+	b.WriteString(fmt.Sprintf("Sessions: %d  %s active  %s waiting  %s stopped  %s stale  %d unknown\n",
+		stats.Total,
+		statusActiveStyle.Render(fmt.Sprintf("%d", stats.Active)),
+		statusWaitingStyle.Render(fmt.Sprintf("%d", stats.Waiting)),
+		statusStoppedStyle.Render(fmt.Sprintf("%d", stats.Stopped)),
+		statusStaleStyle.Render(fmt.Sprintf("%d", stats.Stale)),
+		stats.Unknown,
+	))
+
+	diskText := "calculating..."
+	if !d.diskLoaded.IsZero() {
+		diskText = formatBytes(stats.DiskUsage)
+	}
+	b.WriteString(fmt.Sprintf("Disk usage: %s\n", diskText))
+
+	if len(stats.SessionsByRepo) > 0 {
+		repoNames := make([]string, 0, len(stats.SessionsByRepo))
+		for name := range stats.SessionsByRepo {
+			repoNames = append(repoNames, name)
+		}
+		sort.Strings(repoNames)
+
+		parts := make([]string, 0, len(repoNames))
+		for _, name := range repoNames {
+			parts = append(parts, fmt.Sprintf("%s: %d", name, stats.SessionsByRepo[name]))
+		}
+		b.WriteString("By repository: " + strings.Join(parts, ", ") + "\n")
+	}
+	b.WriteString("\n")
+
+	// Session table
+	if len(d.rows) == 0 {
+		b.WriteString(mutedStyle.Render("No active work sessions found.") + "\n")
+	} else {
+		widths := CalculateGlobalViewWidths(m.width)
+		b.WriteString(tableHeaderStyle.Render(FormatGlobalViewHeader(widths)) + "\n")
+
+		pageSize := m.dashboardTableRows()
+		start, end := visibleRange(len(d.rows), m.cursor, pageSize)
+		for i := start; i < end; i++ {
+			row := d.rows[i]
+			line := m.formatSessionRow(widths, true, dashboardSessionLabel(row.session), row.session, row.status, false, "", "", "", i == m.cursor)
+			b.WriteString(line + "\n")
+		}
+		if end-start < len(d.rows) {
+			b.WriteString(mutedStyle.Render(formatPageIndicator(start, end, len(d.rows), pageSize)) + "\n")
+		}
+	}
+	b.WriteString("\n")
+
+	// Event feed, newest first, limited to the remaining screen space
+	b.WriteString(headerStyle.Render("Recent Events") + "\n")
+	if len(d.events) == 0 {
+		b.WriteString(mutedStyle.Render("No events yet.") + "\n")
+	} else {
+		limit := len(d.events)
+		if m.height > 0 {
+			used := strings.Count(b.String(), "\n") + 3
+			limit = min(limit, maxInt(1, m.height-used))
+		}
+		for i := len(d.events) - 1; i >= len(d.events)-limit; i-- {
+			b.WriteString(formatDashboardEvent(d.events[i]) + "\n")
+		}
+	}
+
+	if m.notice != "" {
+		b.WriteString("\n" + m.renderNotice() + "\n")
+	}
+
+	b.WriteString(helpStyle.Render("\nPress enter: attach, l: logs, r: refresh, D/ESC: exit dashboard, q: quit"))
+
+	return b.String()
+}
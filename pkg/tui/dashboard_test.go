@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/app"
+	"sbs/pkg/config"
+	"sbs/pkg/status"
+)
+
+// fixedStatuses returns a detector func that reports statuses from a map keyed by work item ID
+func fixedStatuses(statuses map[string]string) func(config.SessionMetadata) status.SessionStatus {
+	return func(session config.SessionMetadata) status.SessionStatus {
+		return status.SessionStatus{Status: statuses[session.NamespacedID], TimeDelta: "now"}
+	}
+}
+
+func TestDashboardState_Observe(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	sessionA := config.SessionMetadata{NamespacedID: "github:1", RepositoryName: "alpha", RepositoryRoot: "/r/alpha"}
+	sessionB := config.SessionMetadata{NamespacedID: "github:2", RepositoryName: "beta", RepositoryRoot: "/r/beta"}
+
+	t.Run("first_snapshot_is_baseline", func(t *testing.T) {
+		d := newDashboardState()
+		d.observe([]config.SessionMetadata{sessionA}, fixedStatuses(map[string]string{"github:1": "active"}), now)
+
+		assert.Empty(t, d.events)
+		assert.Len(t, d.rows, 1)
+	})
+
+	t.Run("records_start_status_change_and_removal", func(t *testing.T) {
+		d := newDashboardState()
+		d.observe([]config.SessionMetadata{sessionA}, fixedStatuses(map[string]string{"github:1": "active"}), now)
+
+		d.observe([]config.SessionMetadata{sessionA, sessionB},
+			fixedStatuses(map[string]string{"github:1": "stopped", "github:2": "active"}), now.Add(time.Minute))
+		require.Len(t, d.events, 2)
+		assert.Equal(t, DashboardEventStatus, d.events[0].Kind)
+		assert.Equal(t, "active", d.events[0].From)
+		assert.Equal(t, "stopped", d.events[0].To)
+		assert.Equal(t, DashboardEventStarted, d.events[1].Kind)
+		assert.Equal(t, "github:2", d.events[1].SessionID)
+		assert.Equal(t, "beta", d.events[1].Repository)
+
+		d.observe([]config.SessionMetadata{sessionB},
+			fixedStatuses(map[string]string{"github:2": "active"}), now.Add(2*time.Minute))
+		require.Len(t, d.events, 3)
+		assert.Equal(t, DashboardEventRemoved, d.events[2].Kind)
+		assert.Equal(t, "github:1", d.events[2].SessionID)
+		assert.Equal(t, "alpha", d.events[2].Repository)
+	})
+
+	t.Run("records_new_remote_status", func(t *testing.T) {
+		d := newDashboardState()
+		detect := fixedStatuses(map[string]string{"github:1": "active"})
+		d.observe([]config.SessionMetadata{sessionA}, detect, now)
+
+		pushed := sessionA
+		pushed.RemoteStatus = "ci failed"
+		d.observe([]config.SessionMetadata{pushed}, detect, now.Add(time.Minute))
+		d.observe([]config.SessionMetadata{pushed}, detect, now.Add(2*time.Minute))
+		require.Len(t, d.events, 1, "an unchanged remote status is reported once")
+		assert.Equal(t, DashboardEventRemote, d.events[0].Kind)
+		assert.Equal(t, "ci failed", d.events[0].To)
+		assert.Contains(t, formatDashboardEvent(d.events[0]), "alpha/github:1 reported ci failed")
+	})
+
+	t.Run("unchanged_snapshot_adds_no_events", func(t *testing.T) {
+		d := newDashboardState()
+		detect := fixedStatuses(map[string]string{"github:1": "active"})
+		d.observe([]config.SessionMetadata{sessionA}, detect, now)
+		d.observe([]config.SessionMetadata{sessionA}, detect, now.Add(time.Minute))
+
+		assert.Empty(t, d.events)
+	})
+
+	t.Run("feed_is_bounded", func(t *testing.T) {
+		d := newDashboardState()
+		for i := 0; i < maxDashboardEvents+10; i++ {
+			d.addEvent(DashboardEvent{Time: now, Kind: DashboardEventStarted, SessionID: "x"})
+		}
+		assert.Len(t, d.events, maxDashboardEvents)
+	})
+}
+
+func TestDashboardState_Stats(t *testing.T) {
+	d := newDashboardState()
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "github:1", RepositoryName: "alpha", WorktreePath: "/w/1"},
+		{NamespacedID: "github:2", RepositoryName: "alpha", WorktreePath: "/w/2"},
+		{NamespacedID: "github:3", RepositoryName: "beta", WorktreePath: "/w/3"},
+		{NamespacedID: "github:4", WorktreePath: "/w/4"},
+	}
+	d.observe(sessions, fixedStatuses(map[string]string{
+		"github:1": "active",
+		"github:2": "stale",
+		"github:3": "stale",
+		"github:4": "stopped",
+	}), time.Now())
+	d.diskUsage = map[string]int64{"/w/1": 100, "/w/3": 50}
+
+	stats := d.stats()
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 1, stats.Active)
+	assert.Equal(t, 1, stats.Stopped)
+	assert.Equal(t, 2, stats.Stale)
+	assert.Equal(t, map[string]int{"alpha": 2, "beta": 1, "(unknown)": 1}, stats.SessionsByRepo)
+	assert.Equal(t, int64(150), stats.DiskUsage)
+}
+
+func TestCalculateDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), make([]byte, 100), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), make([]byte, 23), 0644))
+
+	usage := calculateDiskUsage([]string{dir, filepath.Join(dir, "missing"), ""})
+	assert.Equal(t, int64(123), usage[dir])
+	assert.Equal(t, int64(0), usage[filepath.Join(dir, "missing")])
+	assert.NotContains(t, usage, "")
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatBytes(tt.bytes))
+		})
+	}
+}
+
+func TestDashboard_KeyHandling(t *testing.T) {
+	model := NewModel()
+	model.viewMode = ViewModeRepository
+	model.width = 100
+	model.height = 30
+
+	model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	assert.Equal(t, ViewModeDashboard, model.viewMode)
+	assert.Equal(t, ViewModeRepository, model.dashboardReturnMode)
+
+	// Global/repo toggle is disabled while the dashboard is open
+	model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	assert.Equal(t, ViewModeDashboard, model.viewMode)
+
+	model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewModeRepository, model.viewMode)
+
+	model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	assert.Equal(t, ViewModeRepository, model.viewMode)
+}
+
+func TestDashboard_RefreshUpdatesFeed(t *testing.T) {
+	model := NewModel()
+	model.viewMode = ViewModeDashboard
+	model.width = 120
+	model.height = 40
+	model.dashboard.diskLoaded = time.Now() // Skip the background disk scan
+
+	sessions := []config.SessionMetadata{{NamespacedID: "github:7", IssueTitle: "Dashboard", RepositoryName: "alpha", WorktreePath: t.TempDir()}}
+
+	updated, _ := model.Update(refreshMsg{sessions: sessions, dashboard: true})
+	model = updated.(Model)
+	require.Len(t, model.dashboard.rows, 1)
+	assert.Empty(t, model.dashboard.events)
+
+	// Refreshes for other views do not touch the dashboard snapshot
+	updated, _ = model.Update(refreshMsg{sessions: nil, dashboard: false})
+	model = updated.(Model)
+	assert.Len(t, model.dashboard.rows, 1)
+
+	updated, _ = model.Update(refreshMsg{sessions: nil, dashboard: true})
+	model = updated.(Model)
+	require.Len(t, model.dashboard.events, 1)
+	assert.Equal(t, DashboardEventRemoved, model.dashboard.events[0].Kind)
+
+	view := model.View()
+	assert.Contains(t, view, "Dashboard")
+	assert.Contains(t, view, "Recent Events")
+	assert.Contains(t, view, "alpha/github:7 removed")
+}
+
+func TestDashboard_DiskUsageMsg(t *testing.T) {
+	model := NewModel()
+	model.viewMode = ViewModeDashboard
+	model.dashboard.diskBusy = true
+
+	updated, _ := model.Update(dashboardDiskUsageMsg{usage: map[string]int64{"/w": 2048}})
+	model = updated.(Model)
+
+	assert.False(t, model.dashboard.diskBusy)
+	assert.False(t, model.dashboard.diskLoaded.IsZero())
+	assert.Equal(t, int64(2048), model.dashboard.diskUsage["/w"])
+}
+
+func TestNewDashboardModel(t *testing.T) {
+	model := NewDashboardModel(app.NewContainer(config.DefaultConfig()))
+	assert.Equal(t, ViewModeDashboard, model.viewMode)
+	assert.NotNil(t, model.dashboard)
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"context"
+	"os/exec"
+
+	"sbs/pkg/behind"
+	"sbs/pkg/cleanup"
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+	"sbs/pkg/diffstat"
+	"sbs/pkg/expiry"
+	"sbs/pkg/logarchive"
+	"sbs/pkg/provisioning"
+	"sbs/pkg/repo"
+	"sbs/pkg/status"
+	"sbs/pkg/timing"
+	"sbs/pkg/tmux"
+)
+
+// TmuxManager is the tmux functionality used by the TUI
+type TmuxManager interface {
+	SessionExists(sessionName string) (bool, error)
+	KillSession(sessionName string) error
+	ListSessions() ([]*tmux.Session, error)
+	ListSessionNames() ([]string, error)
+	AttachToSession(sessionName string, env ...map[string]string) error
+}
+
+// SandboxManager is the sandbox functionality used by the TUI
+type SandboxManager interface {
+	SandboxExists(sandboxName string) (bool, error)
+	DeleteSandbox(sandboxName string) error
+	ReadFileFromSandbox(sandboxName, filePath string) ([]byte, error)
+}
+
+// Dependencies are the services a Model is built from
+type Dependencies struct {
+	// Context cancels background work such as cleanup and status detection;
+	// nil means context.Background()
+	Context     context.Context
+	Config      *config.Config
+	CurrentRepo *repo.Repository // nil outside a git repository
+	Tmux        TmuxManager
+	Sandbox     SandboxManager
+	Cleanup     cleanup.SessionCleaner
+
+	// StatusDetector is optional; when nil one is built from Tmux and Sandbox
+	StatusDetector *status.Detector
+
+	// StatusHistory is optional; when set, status samples are recorded on
+	// refresh and shown as a sparkline per row
+	StatusHistory *status.HistoryStore
+
+	// Diffstats is optional; when set and show_diffstat is on, the repository
+	// view shows each session branch's +adds/-dels against the base branch
+	Diffstats *diffstat.Cache
+
+	// Behind is optional; when set, session branches far behind the base
+	// branch are flagged under branch_behind_commits and branch_behind_days
+	Behind *behind.Cache
+
+	// LogArchive is optional; when set, each loghook output shown in the log
+	// view is archived for 'sbs log --history'
+	LogArchive *logarchive.Archiver
+
+	// StartProgress is optional; when set, sessions being started by sbs start
+	// are shown with their progress below the table
+	StartProgress *provisioning.ProgressBoard
+
+	// ExpiryNotifier is optional; when set and expiry_notify is on, sessions
+	// close to expiring under clean_after_days are announced on refresh
+	ExpiryNotifier *expiry.Notifier
+
+	// Timings is optional; when set, an operation that chronically runs over
+	// timing_budgets_seconds is pointed out on the status line at startup,
+	// at most once a day
+	Timings *timing.Store
+
+	// PaletteHistory is the file the : command palette keeps its history in;
+	// empty keeps the history for this run only
+	PaletteHistory string
+
+	// StartCommand builds the sbs start command the palette's :start runs;
+	// nil runs this executable
+	StartCommand func(workItemID string) *exec.Cmd
+
+	// Clock is optional; tests set a clock.Fake so ages and expiry labels
+	// render deterministically. nil uses the clock set with config.SetClock.
+	Clock clock.Clock
+
+	// LoadSessions is optional; nil loads every repository's sessions from
+	// the sessions file
+	LoadSessions func() ([]config.SessionMetadata, error)
+}
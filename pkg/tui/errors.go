@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/cleanup"
+)
+
+// maxErrorHistory is how many errors the error panel keeps
+const maxErrorHistory = 50
+
+// errorEntry is one error shown in the error panel
+type errorEntry struct {
+	at       time.Time
+	message  string
+	sessions []string // namespaced IDs or tmux session names the error affected
+}
+
+// errorHistory keeps the most recent errors, oldest first
+type errorHistory struct {
+	entries []errorEntry
+	unread  int // errors recorded since the panel was last opened
+}
+
+func newErrorHistory() *errorHistory {
+	return &errorHistory{}
+}
+
+// add records an error, dropping the oldest once the history is full
+func (h *errorHistory) add(entry errorEntry) {
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxErrorHistory {
+		h.entries = h.entries[len(h.entries)-maxErrorHistory:]
+	}
+	h.unread++
+}
+
+// reportError records err in the error panel and shows it on the status line
+// until the next successful action. Cleanup failures are recorded per
+// session they affected.
+func (m Model) reportError(err error, sessions ...string) Model {
+	if err == nil {
+		return m
+	}
+	m.error = err
+	if m.errorHistory == nil {
+		return m
+	}
+
+	entry := errorEntry{at: m.now(), message: err.Error(), sessions: sessions}
+	var resourceErr *cleanup.ResourceError
+	if len(sessions) == 0 && errors.As(err, &resourceErr) && resourceErr.SessionID != "" {
+		entry.sessions = []string{resourceErr.SessionID}
+	}
+	m.errorHistory.add(entry)
+	return m
+}
+
+// toast shows a success message on the status line for a few seconds
+func (m Model) toast(message string) (Model, tea.Cmd) {
+	m.notice = message
+	m.noticeIsError = false
+	return m, clearNoticeAfter(message, noticeDuration)
+}
+
+// toggleErrorPanel shows or hides the error panel. Opening it acknowledges
+// the errors, clearing the status-line error.
+func (m Model) toggleErrorPanel() Model {
+	m.showErrors = !m.showErrors
+	if m.showErrors {
+		m.error = nil
+		if m.errorHistory != nil {
+			m.errorHistory.unread = 0
+		}
+	}
+	return m
+}
+
+// renderErrorLine renders the latest error under the session table
+func (m Model) renderErrorLine() string {
+	line := "Error: " + m.error.Error()
+	if m.errorHistory != nil && m.errorHistory.unread > 1 {
+		line += fmt.Sprintf(" (+%d more)", m.errorHistory.unread-1)
+	}
+	return errorStyle.Render(line) + mutedStyle.Render("  e: errors")
+}
+
+// renderErrorPanel renders the error history, newest first
+func (m Model) renderErrorPanel() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Errors") + "\n\n")
+
+	if m.errorHistory == nil || len(m.errorHistory.entries) == 0 {
+		b.WriteString(mutedStyle.Render("No errors so far.") + "\n")
+	} else {
+		// Leave room for the title and help lines
+		limit := len(m.errorHistory.entries)
+		if m.height > 0 {
+			limit = min(limit, maxInt(1, m.height-5))
+		}
+		for i := len(m.errorHistory.entries) - 1; i >= len(m.errorHistory.entries)-limit; i-- {
+			b.WriteString(formatErrorEntry(m.errorHistory.entries[i], m.width) + "\n")
+		}
+	}
+
+	b.WriteString(helpStyle.Render("\nPress e or esc to close"))
+	return b.String()
+}
+
+// formatErrorEntry renders one error as "15:04:05  github:12  message"
+func formatErrorEntry(entry errorEntry, width int) string {
+	prefix := entry.at.Format("15:04:05") + "  "
+	if len(entry.sessions) > 0 {
+		prefix += strings.Join(entry.sessions, ",") + "  "
+	}
+	message := strings.ReplaceAll(entry.message, "\n", " ")
+	if width > len(prefix)+10 {
+		message = TruncateString(message, width-len(prefix))
+	}
+	return mutedStyle.Render(prefix) + errorStyle.Render(message)
+}
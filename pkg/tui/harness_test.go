@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+	"sbs/pkg/testsupport"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the TUI golden files in testdata/")
+
+// harnessNow is the fixed time scripted TUI tests run at
+var harnessNow = time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+const (
+	// harnessCmdTimeout is how long a command may run before the harness
+	// takes it for a timer (tea.Tick) and drops it
+	harnessCmdTimeout = 200 * time.Millisecond
+
+	// harnessMaxMessages stops a script whose messages keep producing more
+	harnessMaxMessages = 200
+)
+
+// tuiHarness drives a Model the way tea.Program would, without a terminal:
+// messages go through Update and the commands they return are run and
+// their messages fed back, until only timers are left. Sessions, tmux,
+// sandboxes, cleanup and the clock are fakes.
+type tuiHarness struct {
+	t        *testing.T
+	model    Model
+	tmux     *testsupport.FakeTmuxManager
+	sandbox  *testsupport.FakeSandboxManager
+	cleaner  *testsupport.FakeSessionCleaner
+	clock    *clock.Fake
+	sessions []config.SessionMetadata
+	messages int
+	quit     bool
+}
+
+// newTUIHarness starts a 120x30 global view of sessions, with tmuxSessions
+// running, and loads the sessions as the TUI's first refresh would
+func newTUIHarness(t *testing.T, sessions []config.SessionMetadata, tmuxSessions ...string) *tuiHarness {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, ".local", "state"))
+
+	h := &tuiHarness{
+		t:        t,
+		tmux:     testsupport.NewFakeTmuxManager(tmuxSessions...),
+		sandbox:  testsupport.NewFakeSandboxManager(),
+		cleaner:  &testsupport.FakeSessionCleaner{},
+		clock:    clock.NewFake(harnessNow),
+		sessions: sessions,
+	}
+	h.model = NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    h.tmux,
+		Sandbox: h.sandbox,
+		Cleanup: h.cleaner,
+		Clock:   h.clock,
+		LoadSessions: func() ([]config.SessionMetadata, error) {
+			return append([]config.SessionMetadata(nil), h.sessions...), nil
+		},
+	})
+	h.send(tea.WindowSizeMsg{Width: 120, Height: 30})
+	h.run(h.model.refreshSessions())
+	return h
+}
+
+// send delivers msg to the model and runs the commands it returns
+func (h *tuiHarness) send(msg tea.Msg) {
+	h.t.Helper()
+	h.messages++
+	require.LessOrEqual(h.t, h.messages, harnessMaxMessages, "the script keeps producing messages")
+	updated, cmd := h.model.Update(msg)
+	h.model = updated.(Model)
+	h.run(cmd)
+}
+
+// run executes cmd and feeds its messages back into the model. Commands
+// that don't finish within harnessCmdTimeout are timers and are dropped.
+func (h *tuiHarness) run(cmd tea.Cmd) {
+	h.t.Helper()
+	if cmd == nil {
+		return
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(harnessCmdTimeout):
+		return
+	}
+
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			h.run(cmd)
+		}
+	case tea.QuitMsg:
+		h.quit = true
+	default:
+		h.send(msg)
+	}
+}
+
+// press sends keys: "enter", "esc", "up", "down" and "tab" by name, and
+// anything else as typed runes
+func (h *tuiHarness) press(keys ...string) {
+	h.t.Helper()
+	named := map[string]tea.KeyType{
+		"enter": tea.KeyEnter,
+		"esc":   tea.KeyEsc,
+		"up":    tea.KeyUp,
+		"down":  tea.KeyDown,
+		"tab":   tea.KeyTab,
+	}
+	for _, k := range keys {
+		if keyType, ok := named[k]; ok {
+			h.send(tea.KeyMsg{Type: keyType})
+			continue
+		}
+		h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+}
+
+// screen returns the rendered view without styling or trailing spaces
+func (h *tuiHarness) screen() string {
+	lines := strings.Split(ansi.Strip(h.model.View()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// assertGolden compares the screen with testdata/<test>/<name>.golden;
+// go test -update rewrites the file
+func (h *tuiHarness) assertGolden(name string) {
+	h.t.Helper()
+	path := filepath.Join("testdata", h.t.Name(), name+".golden")
+	got := h.screen()
+	if *updateGolden {
+		require.NoError(h.t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(h.t, os.WriteFile(path, []byte(got), 0644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(h.t, err, "run go test ./pkg/tui -update to create %s", path)
+	assert.Equal(h.t, string(want), got, "screen differs from %s", path)
+}
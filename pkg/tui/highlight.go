@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"sbs/pkg/config"
+)
+
+// highlightRule pairs a compiled pattern with the style applied to its matches
+type highlightRule struct {
+	pattern *regexp.Regexp
+	style   lipgloss.Style
+}
+
+// highlightSpan is a styled byte range within a log line
+type highlightSpan struct {
+	start int
+	end   int
+	rule  int
+}
+
+// LogHighlighter colorizes log lines by severity keywords, file:line references and URLs
+type LogHighlighter struct {
+	rules []highlightRule
+}
+
+// defaultHighlightRules returns the built-in rules, in priority order
+func defaultHighlightRules() []highlightRule {
+	return []highlightRule{
+		{
+			pattern: regexp.MustCompile(`https?://[^\s"'<>]+`),
+			style:   lipgloss.NewStyle().Foreground(primaryColor).Underline(true),
+		},
+		{
+			pattern: regexp.MustCompile(`\b(?:ERROR|Error|FATAL|Fatal|FAIL(?:ED)?|PANIC|panic)\b:?`),
+			style:   lipgloss.NewStyle().Foreground(errorColor).Bold(true),
+		},
+		{
+			pattern: regexp.MustCompile(`\b(?:WARN(?:ING)?|Warn(?:ing)?)\b:?`),
+			style:   lipgloss.NewStyle().Foreground(warningColor).Bold(true),
+		},
+		{
+			pattern: regexp.MustCompile(`\b(?:INFO|Info)\b:?`),
+			style:   lipgloss.NewStyle().Foreground(accentColor),
+		},
+		{
+			pattern: regexp.MustCompile(`[\w./-]+\.\w+:\d+(?::\d+)?`),
+			style:   lipgloss.NewStyle().Foreground(secondaryColor),
+		},
+	}
+}
+
+// NewLogHighlighter creates a highlighter with the built-in rules plus custom rules.
+// Custom rules take precedence over the built-in ones when matches overlap.
+func NewLogHighlighter(custom []config.LogHighlightRule) (*LogHighlighter, error) {
+	var rules []highlightRule
+	for i, rule := range custom {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log highlight rule %d: %w", i, err)
+		}
+
+		style := lipgloss.NewStyle().Bold(rule.Bold).Underline(rule.Underline)
+		if rule.Color != "" {
+			style = style.Foreground(lipgloss.Color(rule.Color))
+		}
+		if rule.Background != "" {
+			style = style.Background(lipgloss.Color(rule.Background))
+		}
+		rules = append(rules, highlightRule{pattern: pattern, style: style})
+	}
+
+	return &LogHighlighter{rules: append(rules, defaultHighlightRules()...)}, nil
+}
+
+// Highlight returns the line with styled matches. Lines that already contain
+// ANSI escape sequences are returned unchanged to keep the script's own colors.
+func (h *LogHighlighter) Highlight(line string) string {
+	if h == nil || line == "" || strings.Contains(line, "\x1b[") {
+		return line
+	}
+
+	spans := h.spans(line)
+	if len(spans) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, span := range spans {
+		b.WriteString(line[pos:span.start])
+		b.WriteString(h.rules[span.rule].style.Render(line[span.start:span.end]))
+		pos = span.end
+	}
+	b.WriteString(line[pos:])
+	return b.String()
+}
+
+// spans finds non-overlapping matches, preferring earlier rules when matches overlap
+func (h *LogHighlighter) spans(line string) []highlightSpan {
+	var spans []highlightSpan
+	for ruleIndex, rule := range h.rules {
+		for _, loc := range rule.pattern.FindAllStringIndex(line, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			candidate := highlightSpan{start: loc[0], end: loc[1], rule: ruleIndex}
+			if !overlapsAny(candidate, spans) {
+				spans = append(spans, candidate)
+			}
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+	return spans
+}
+
+func overlapsAny(candidate highlightSpan, spans []highlightSpan) bool {
+	for _, span := range spans {
+		if candidate.start < span.end && span.start < candidate.end {
+			return true
+		}
+	}
+	return false
+}
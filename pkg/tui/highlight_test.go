@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+// matchedText returns the text of each highlighted span in order
+func matchedText(h *LogHighlighter, line string) []string {
+	var matches []string
+	for _, span := range h.spans(line) {
+		matches = append(matches, line[span.start:span.end])
+	}
+	return matches
+}
+
+func TestLogHighlighter_DefaultRules(t *testing.T) {
+	highlighter, err := NewLogHighlighter(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+	}{
+		{"error_keyword", "2025-01-01 ERROR: build failed", []string{"ERROR:"}},
+		{"warning_keyword", "WARNING deprecated flag", []string{"WARNING"}},
+		{"info_keyword", "INFO starting server", []string{"INFO"}},
+		{"go_test_fail", "--- FAIL: TestSomething (0.01s)", []string{"FAIL:"}},
+		{"file_line_reference", "pkg/tui/model.go:42:7: undefined: foo", []string{"pkg/tui/model.go:42:7"}},
+		{"url", "see https://example.com/docs?q=1 for details", []string{"https://example.com/docs?q=1"}},
+		{"url_with_port_not_file_ref", "listening on http://localhost:8080/", []string{"http://localhost:8080/"}},
+		{"multiple_matches", "ERROR main.go:10 see https://x.io", []string{"ERROR", "main.go:10", "https://x.io"}},
+		{"no_match", "plain output line", nil},
+		{"keyword_inside_word_ignored", "TERRORS everywhere", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchedText(highlighter, tt.line))
+		})
+	}
+}
+
+func TestLogHighlighter_CustomRules(t *testing.T) {
+	t.Run("custom_rule_matches", func(t *testing.T) {
+		highlighter, err := NewLogHighlighter([]config.LogHighlightRule{
+			{Pattern: `\bPASS\b`, Color: "#04B575", Bold: true},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"PASS"}, matchedText(highlighter, "PASS ok sbs/pkg/tui"))
+	})
+
+	t.Run("custom_rule_takes_precedence", func(t *testing.T) {
+		highlighter, err := NewLogHighlighter([]config.LogHighlightRule{
+			{Pattern: `ERROR.*`, Color: "196"},
+		})
+		require.NoError(t, err)
+
+		spans := highlighter.spans("ERROR main.go:10 broke")
+		require.Len(t, spans, 1)
+		assert.Equal(t, 0, spans[0].rule, "custom rule should win over built-in rules")
+	})
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		_, err := NewLogHighlighter([]config.LogHighlightRule{{Pattern: "("}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid log highlight rule 0")
+	})
+}
+
+func TestLogHighlighter_Highlight(t *testing.T) {
+	highlighter, err := NewLogHighlighter(nil)
+	require.NoError(t, err)
+
+	t.Run("preserves_text", func(t *testing.T) {
+		line := "ERROR main.go:10 failed"
+		assert.Contains(t, highlighter.Highlight(line), "failed")
+		assert.Contains(t, highlighter.Highlight(line), "main.go:10")
+	})
+
+	t.Run("leaves_colored_lines_untouched", func(t *testing.T) {
+		line := "\x1b[31mERROR already colored\x1b[0m"
+		assert.Equal(t, line, highlighter.Highlight(line))
+	})
+
+	t.Run("nil_highlighter", func(t *testing.T) {
+		var nilHighlighter *LogHighlighter
+		assert.Equal(t, "ERROR x", nilHighlighter.Highlight("ERROR x"))
+	})
+}
+
+func TestModel_LogHighlighterFromConfig(t *testing.T) {
+	model := NewModel()
+	require.NotNil(t, model.logHighlighter, "model should always have a highlighter")
+
+	model = newLogViewModel("ERROR main.go:10 failed", 80, 20)
+	assert.Contains(t, model.renderLogView(), "failed")
+}
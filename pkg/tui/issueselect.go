@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"sbs/pkg/config"
 	"sbs/pkg/issue"
 )
 
@@ -257,7 +258,7 @@ func (m *IssueSelectModel) loadingView() string {
 		Height(m.height).
 		AlignHorizontal(lipgloss.Center).
 		AlignVertical(lipgloss.Center).
-		Render("Loading issues...")
+		Render(newProgressView("Loading issues").render(config.Now(), m.width))
 }
 
 // errorView renders the error state
@@ -0,0 +1,233 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/loghook"
+)
+
+// setupNamedLoghooks creates a worktree with scripts in .sbs/loghooks/ that echo their name
+func setupNamedLoghooks(t *testing.T, names ...string) string {
+	t.Helper()
+	worktreePath := filepath.Join(t.TempDir(), "worktree")
+	dir := loghook.SourcesPath(worktreePath)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, name := range names {
+		script := "#!/bin/sh\necho \"output from " + name + "\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+	}
+	return worktreePath
+}
+
+func enterLogView(t *testing.T, worktreePath string) Model {
+	t.Helper()
+	model := NewModel()
+	model.sessions = []config.SessionMetadata{{NamespacedID: "test:tabs", WorktreePath: worktreePath}}
+	model.cursor = 0
+	model.width = 80
+	model.height = 24
+	model.logView = nil
+
+	return pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+}
+
+func TestLogTabs_Setup(t *testing.T) {
+	t.Run("tabs_created_for_named_loghooks", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "tests", "build"))
+
+		require.Len(t, model.logView.tabs, 2)
+		assert.Equal(t, "build", model.logView.tabs[0].source.Name)
+		assert.Equal(t, "tests", model.logView.tabs[1].source.Name)
+		assert.Equal(t, 0, model.logView.activeTab)
+	})
+
+	t.Run("no_tabs_for_single_default_loghook", func(t *testing.T) {
+		worktreePath := setupTestWorktree(t)
+		model := enterLogView(t, worktreePath)
+
+		assert.Empty(t, model.logView.tabs)
+	})
+
+	t.Run("per_tab_interval_from_config", func(t *testing.T) {
+		model := NewModel()
+		model.config = config.DefaultConfig()
+		model.config.LoghookIntervals = map[string]int{"tests": 30}
+
+		tabs := model.buildLogTabs(config.SessionMetadata{WorktreePath: setupNamedLoghooks(t, "build", "tests")}, loghook.Settings{})
+		require.Len(t, tabs, 2)
+		assert.Equal(t, time.Duration(0), tabs[0].interval)
+		assert.Equal(t, 30*time.Second, tabs[1].interval)
+
+		model.logView = &LogView{tabs: tabs, activeTab: 1}
+		assert.Equal(t, 30*time.Second, model.getLogRefreshInterval())
+	})
+}
+
+func TestLogView_WorktreeSettings(t *testing.T) {
+	t.Run("sidecar_sets_interval_and_output_limit", func(t *testing.T) {
+		worktreePath := setupTestWorktree(t)
+		require.NoError(t, os.WriteFile(loghook.SettingsPath(worktreePath), []byte(`{"refresh_interval_seconds": 3, "max_output_bytes": 2048}`), 0644))
+
+		model := enterLogView(t, worktreePath)
+		assert.Equal(t, 3*time.Second, model.getLogRefreshInterval())
+		assert.Equal(t, 2048, model.logView.maxSizeBytes)
+	})
+
+	t.Run("sidecar_bounded_by_config", func(t *testing.T) {
+		worktreePath := setupNamedLoghooks(t, "build", "tests")
+		require.NoError(t, os.WriteFile(loghook.SettingsPath(worktreePath), []byte(`{"source_intervals_seconds": {"tests": 1}}`), 0644))
+
+		model := enterLogView(t, worktreePath)
+		require.Len(t, model.logView.tabs, 2)
+		assert.Equal(t, time.Duration(0), model.logView.tabs[0].interval)
+		assert.Equal(t, 2*time.Second, model.logView.tabs[1].interval)
+
+		updated := *model.config
+		updated.LoghookMinIntervalSecs = 10
+		model = model.applyConfig(&updated)
+		assert.Equal(t, 10*time.Second, model.logView.tabs[1].interval)
+	})
+
+	t.Run("worktree_config_overrides_intervals", func(t *testing.T) {
+		worktreePath := setupNamedLoghooks(t, "build", "tests")
+		require.NoError(t, os.WriteFile(config.RepositoryConfigPath(worktreePath),
+			[]byte(`{"log_refresh_interval_seconds": 20, "loghook_intervals_seconds": {"tests": 40}}`), 0644))
+
+		model := enterLogView(t, worktreePath)
+		require.Len(t, model.logView.tabs, 2)
+		assert.Equal(t, 40*time.Second, model.logView.tabs[1].interval)
+		assert.Equal(t, 20*time.Second, model.getLogRefreshInterval(), "tabs without their own interval use the worktree's")
+		assert.Equal(t, 5, model.config.LogRefreshIntervalSecs, "the running config is left alone")
+	})
+
+	t.Run("invalid_sidecar_reported", func(t *testing.T) {
+		worktreePath := setupTestWorktree(t)
+		require.NoError(t, os.WriteFile(loghook.SettingsPath(worktreePath), []byte(`{`), 0644))
+
+		model := enterLogView(t, worktreePath)
+		require.Error(t, model.error)
+		assert.Contains(t, model.error.Error(), loghook.SettingsFile)
+		assert.Equal(t, loghook.DefaultMaxOutputBytes, model.logView.maxSizeBytes)
+	})
+}
+
+func TestLogTabs_Switching(t *testing.T) {
+	t.Run("number_key_switches_and_preserves_buffers", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+		updated, _ := model.Update(logRefreshResultMsg{content: "agent log", tab: 0})
+		model = updated.(Model)
+		model.logView.scrollOffset = 3
+
+		updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+		model = updated.(Model)
+		require.NotNil(t, cmd, "switching tabs should refresh the new tab")
+		assert.Equal(t, 1, model.logView.activeTab)
+		assert.True(t, model.logView.loading)
+		assert.Equal(t, "", model.logView.content)
+
+		// Refresh result for the new tab
+		msg := cmd()
+		result, ok := msg.(logRefreshResultMsg)
+		require.True(t, ok)
+		assert.Equal(t, 1, result.tab)
+		assert.Contains(t, result.content, "output from build")
+
+		updated, _ = model.Update(result)
+		model = updated.(Model)
+		assert.Contains(t, model.logView.content, "output from build")
+
+		// Switching back restores the first buffer and scroll position
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+		assert.Equal(t, "agent log", model.logView.content)
+		assert.Equal(t, 3, model.logView.scrollOffset)
+		assert.False(t, model.logView.loading)
+	})
+
+	t.Run("out_of_range_key_ignored", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+
+		updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'5'}})
+		assert.Nil(t, cmd)
+		assert.Equal(t, 0, updated.(Model).logView.activeTab)
+	})
+
+	t.Run("background_result_updates_only_its_tab", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+		updated, _ := model.Update(logRefreshResultMsg{content: "agent log", tab: 0})
+		model = updated.(Model)
+
+		updated, _ = model.Update(logRefreshResultMsg{content: "late build log", tab: 1})
+		model = updated.(Model)
+
+		assert.Equal(t, "agent log", model.logView.content)
+		assert.Equal(t, "late build log", model.logView.tabs[1].content)
+		assert.True(t, model.logView.tabs[1].loaded)
+	})
+
+	t.Run("render_shows_tab_bar", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+
+		view := model.renderLogView()
+		assert.Contains(t, view, "1 agent")
+		assert.Contains(t, view, "2 build")
+		assert.Contains(t, view, "1-2: switch log")
+	})
+}
+
+func TestLogTabs_BackgroundRefresh(t *testing.T) {
+	t.Run("every_tab_is_scheduled", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build", "tests"))
+
+		batch, ok := model.startLogAutoRefresh()().(tea.BatchMsg)
+		require.True(t, ok)
+		assert.Len(t, batch, 3)
+	})
+
+	t.Run("tabs_use_their_own_interval", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+		model.logView.tabs[1].interval = 45 * time.Second
+
+		assert.Equal(t, 45*time.Second, model.logTabRefreshInterval(1))
+		assert.Equal(t, model.getLogRefreshInterval(), model.logTabRefreshInterval(0))
+	})
+
+	t.Run("tick_refreshes_background_tab", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+		updated, _ := model.Update(logRefreshResultMsg{content: "agent log", tab: 0})
+		model = updated.(Model)
+
+		updated, cmd := model.Update(logRefreshTickMsg{tab: 1, generation: model.logRefreshGeneration})
+		model = updated.(Model)
+		require.NotNil(t, cmd)
+		assert.True(t, model.logView.tabs[1].refreshing)
+		assert.False(t, model.logView.refreshing, "the displayed tab isn't refreshing")
+
+		// The batch holds the refresh and the tab's next tick
+		batch, ok := cmd().(tea.BatchMsg)
+		require.True(t, ok)
+		result, ok := batch[0]().(logRefreshResultMsg)
+		require.True(t, ok)
+		assert.Equal(t, 1, result.tab)
+
+		updated, _ = model.Update(result)
+		model = updated.(Model)
+		assert.Equal(t, "agent log", model.logView.content)
+		assert.Contains(t, model.logView.tabs[1].content, "output from build")
+		assert.False(t, model.logView.tabs[1].refreshing)
+	})
+
+	t.Run("ticks_from_an_earlier_start_are_dropped", func(t *testing.T) {
+		model := enterLogView(t, setupNamedLoghooks(t, "agent", "build"))
+
+		_, cmd := model.Update(logRefreshTickMsg{tab: 1, generation: model.logRefreshGeneration - 1})
+		assert.Nil(t, cmd)
+	})
+}
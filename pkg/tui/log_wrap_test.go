@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLogViewModel(content string, width, height int) Model {
+	model := NewModel()
+	model.sessions = testSessions
+	model.viewMode = ViewModeLog
+	model.width = width
+	model.height = height
+	model.logView = &LogView{
+		content:      content,
+		maxSizeBytes: 1048576,
+	}
+	return model
+}
+
+func pressKey(t *testing.T, model Model, msg tea.KeyMsg) Model {
+	t.Helper()
+	updated, _ := model.Update(msg)
+	result, ok := updated.(Model)
+	require.True(t, ok)
+	return result
+}
+
+func TestLogView_WrapToggle(t *testing.T) {
+	longLine := strings.Repeat("a", 25)
+
+	t.Run("w_toggles_wrap", func(t *testing.T) {
+		model := newLogViewModel(longLine, 10, 20)
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+		assert.True(t, model.logView.wrap)
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+		assert.False(t, model.logView.wrap)
+	})
+
+	t.Run("wrapped_lines_split_at_width", func(t *testing.T) {
+		model := newLogViewModel(longLine+"\nshort", 10, 20)
+		model.logView.wrap = true
+
+		lines := model.logDisplayLines()
+		assert.Equal(t, []string{"aaaaaaaaaa", "aaaaaaaaaa", "aaaaa", "short"}, lines)
+	})
+
+	t.Run("scroll_bounds_account_for_wrapped_lines", func(t *testing.T) {
+		// 3 logical lines of 30 columns wrap into 9 screen lines; 2 fit on screen
+		content := strings.Repeat("b", 30) + "\n" + strings.Repeat("c", 30) + "\n" + strings.Repeat("d", 30)
+		model := newLogViewModel(content, 10, 8)
+
+		assert.Equal(t, 1, model.maxLogScrollOffset())
+
+		model.logView.wrap = true
+		assert.Equal(t, 7, model.maxLogScrollOffset())
+
+		for i := 0; i < 20; i++ {
+			model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyDown})
+		}
+		assert.Equal(t, 7, model.logView.scrollOffset)
+	})
+
+	t.Run("disabling_wrap_clamps_scroll_offset", func(t *testing.T) {
+		content := strings.Repeat("b", 30) + "\n" + strings.Repeat("c", 30) + "\n" + strings.Repeat("d", 30)
+		model := newLogViewModel(content, 10, 8)
+		model.logView.wrap = true
+		model.logView.scrollOffset = 7
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+		assert.False(t, model.logView.wrap)
+		assert.Equal(t, 1, model.logView.scrollOffset)
+	})
+}
+
+func TestLogView_HorizontalScroll(t *testing.T) {
+	content := "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	t.Run("right_and_left_pan_the_view", func(t *testing.T) {
+		model := newLogViewModel(content, 10, 20)
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRight})
+		assert.Equal(t, logHorizontalScrollStep, model.logView.horizontalOffset)
+		assert.Equal(t, []string{"89abcdefgh"}, model.logDisplayLines())
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyLeft})
+		assert.Equal(t, 0, model.logView.horizontalOffset)
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyLeft})
+		assert.Equal(t, 0, model.logView.horizontalOffset, "offset should not go negative")
+	})
+
+	t.Run("right_stops_at_widest_line", func(t *testing.T) {
+		model := newLogViewModel(content, 10, 20)
+
+		for i := 0; i < 10; i++ {
+			model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRight})
+		}
+		assert.Equal(t, len(content)-10, model.logView.horizontalOffset)
+	})
+
+	t.Run("no_horizontal_scroll_when_wrapping", func(t *testing.T) {
+		model := newLogViewModel(content, 10, 20)
+		model.logView.wrap = true
+
+		model = pressKey(t, model, tea.KeyMsg{Type: tea.KeyRight})
+		assert.Equal(t, 0, model.logView.horizontalOffset)
+	})
+
+	t.Run("ansi_sequences_preserved_when_cut", func(t *testing.T) {
+		model := newLogViewModel("\x1b[31m"+content+"\x1b[0m", 10, 20)
+
+		lines := model.logDisplayLines()
+		require.Len(t, lines, 1)
+		assert.Contains(t, lines[0], "\x1b[31m")
+		assert.Contains(t, lines[0], "0123456789")
+	})
+
+	t.Run("render_shows_column_indicator", func(t *testing.T) {
+		model := newLogViewModel(content, 40, 20)
+		model.width = 10
+		model.logView.horizontalOffset = 8
+
+		assert.Contains(t, model.renderLogView(), "Col 9")
+	})
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -126,7 +127,7 @@ echo "Operation completed"
 		require.NoError(t, os.Chmod(loghookPath, 0755))
 
 		// Test with long-running script (should timeout if timeout is implemented)
-		_, err := executeLoghookScriptWithTimeout(session, 2) // 2 second timeout
+		_, err := executeLoghookScriptWithTimeout(context.Background(), session, 2) // 2 second timeout
 
 		// Verify timeout handling (if implemented)
 		if err != nil {
@@ -278,7 +279,7 @@ done
 		}
 
 		// Test with small size limit (1KB)
-		output, err := executeLoghookScriptWithOptions(session, 10, 1024)
+		output, err := executeLoghookScriptWithOptions(context.Background(), session, 10, 1024)
 		assert.NoError(t, err, "Script should execute successfully")
 		assert.LessOrEqual(t, len(output), 1024+200, "Output should be truncated to size limit (with some buffer for truncation message)")
 		assert.Contains(t, output, "Output truncated", "Should contain truncation message")
@@ -301,7 +302,7 @@ echo "Finished"
 		}
 
 		// Test with short timeout
-		output, err := executeLoghookScriptWithTimeout(session, 1) // 1 second timeout
+		output, err := executeLoghookScriptWithTimeout(context.Background(), session, 1) // 1 second timeout
 		assert.Error(t, err, "Should timeout")
 		assert.Contains(t, err.Error(), "timed out", "Error should indicate timeout")
 		assert.Contains(t, output, "Starting", "Should capture partial output before timeout")
@@ -374,7 +375,7 @@ done
 		}
 
 		// Test with reasonable size limit and longer timeout
-		output, err := executeLoghookScriptWithOptions(session, 30, 8192) // 30s timeout, 8KB limit
+		output, err := executeLoghookScriptWithOptions(context.Background(), session, 30, 8192) // 30s timeout, 8KB limit
 		assert.NoError(t, err, "Should handle large output without memory issues")
 		assert.LessOrEqual(t, len(output), 8192+500, "Output should be properly limited")
 
@@ -411,3 +412,44 @@ done
 		}
 	})
 }
+
+func TestLoghook_Contract(t *testing.T) {
+	t.Run("snapshot_mode_and_environment", func(t *testing.T) {
+		script := `#!/bin/sh
+echo "mode=$1 env_mode=$SBS_LOGHOOK_MODE"
+echo "item=$SBS_WORK_ITEM branch=$SBS_BRANCH tmux=$SBS_TMUX_SESSION"
+`
+		worktreePath, _ := setupTestWorktreeWithCustomScript(t, script)
+
+		session := config.SessionMetadata{
+			NamespacedID: "test:contract",
+			Branch:       "issue-test-contract",
+			TmuxSession:  "sbs-repo-test-contract",
+			WorktreePath: worktreePath,
+		}
+
+		output, err := executeLoghookScript(session)
+		require.NoError(t, err)
+		assert.Contains(t, output, "mode=snapshot env_mode=snapshot")
+		assert.Contains(t, output, "item=test:contract branch=issue-test-contract tmux=sbs-repo-test-contract")
+	})
+
+	t.Run("repository_loghook_args", func(t *testing.T) {
+		worktreePath, _ := setupTestWorktreeWithCustomScript(t, "#!/bin/sh\necho \"args=$*\"\n")
+
+		repoRoot := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(repoRoot, ".sbs"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repoRoot, ".sbs", "config.json"),
+			[]byte(`{"loghook_args": ["--service", "api"]}`), 0644))
+
+		session := config.SessionMetadata{
+			NamespacedID:   "test:args",
+			WorktreePath:   worktreePath,
+			RepositoryRoot: repoRoot,
+		}
+
+		output, err := executeLoghookScript(session)
+		require.NoError(t, err)
+		assert.Contains(t, output, "args=snapshot --service api")
+	})
+}
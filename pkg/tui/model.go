@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,18 +11,30 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
+	"sbs/pkg/app"
+	"sbs/pkg/behind"
 	"sbs/pkg/cleanup"
+	"sbs/pkg/clock"
+	"sbs/pkg/cmdlog"
 	"sbs/pkg/config"
+	"sbs/pkg/diffstat"
+	"sbs/pkg/expiry"
+	"sbs/pkg/inbox"
+	"sbs/pkg/inputsource"
+	"sbs/pkg/logarchive"
+	"sbs/pkg/loghook"
+	"sbs/pkg/messages"
+	"sbs/pkg/provisioning"
 	"sbs/pkg/repo"
-	"sbs/pkg/sandbox"
 	"sbs/pkg/status"
+	"sbs/pkg/timing"
 	"sbs/pkg/tmux"
 )
 
@@ -33,52 +46,131 @@ type keyMap struct {
 	Help       key.Binding
 	Refresh    key.Binding
 	ToggleView key.Binding
+	Group      key.Binding
 	Stop       key.Binding
 	Clean      key.Binding
 	LogView    key.Binding
+	Dashboard  key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+	Errors     key.Binding
+	Recent     key.Binding
+	Palette    key.Binding
 }
 
-var keys = keyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "move up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "move down"),
-	),
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "attach to session"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
-	),
-	Refresh: key.NewBinding(
-		key.WithKeys("r"),
-		key.WithHelp("r", "refresh"),
-	),
-	ToggleView: key.NewBinding(
-		key.WithKeys("g"),
-		key.WithHelp("g", "toggle global/repo view"),
-	),
-	Stop: key.NewBinding(
-		key.WithKeys("s"),
-		key.WithHelp("s", "stop session"),
-	),
-	Clean: key.NewBinding(
-		key.WithKeys("c"),
-		key.WithHelp("c", "clean stale"),
-	),
-	LogView: key.NewBinding(
-		key.WithKeys("l"),
-		key.WithHelp("l", "logs"),
-	),
+// keys holds the active key bindings; defaultKeys with any configured overrides applied
+var keys = defaultKeys()
+
+func defaultKeys() keyMap {
+	return keyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "move down"),
+		),
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "attach to session"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		ToggleView: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "toggle global/repo view"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "cycle repository groups"),
+		),
+		Stop: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "stop session"),
+		),
+		Clean: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "clean stale"),
+		),
+		LogView: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "logs"),
+		),
+		Dashboard: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "dashboard"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", "previous page"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdn", "next page"),
+		),
+		Errors: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "error history"),
+		),
+		Recent: key.NewBinding(
+			key.WithKeys("1", "2", "3"),
+			key.WithHelp("1-3", "attach to a recent session"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command palette"),
+		),
+	}
+}
+
+// bindings maps the key_bindings config action names to bindings
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":          &k.Up,
+		"down":        &k.Down,
+		"enter":       &k.Enter,
+		"quit":        &k.Quit,
+		"help":        &k.Help,
+		"refresh":     &k.Refresh,
+		"toggle_view": &k.ToggleView,
+		"group":       &k.Group,
+		"stop":        &k.Stop,
+		"clean":       &k.Clean,
+		"logs":        &k.LogView,
+		"dashboard":   &k.Dashboard,
+		"page_up":     &k.PageUp,
+		"page_down":   &k.PageDown,
+		"errors":      &k.Errors,
+		"recent":      &k.Recent,
+		"palette":     &k.Palette,
+	}
+}
+
+// ApplyKeyBindings resets the key bindings to the defaults and applies the
+// configured overrides. Unknown actions and empty key lists are ignored.
+func ApplyKeyBindings(overrides map[string][]string) {
+	updated := defaultKeys()
+	bindings := updated.bindings()
+	for action, actionKeys := range overrides {
+		binding, ok := bindings[action]
+		if !ok || len(actionKeys) == 0 {
+			continue
+		}
+		binding.SetKeys(actionKeys...)
+		binding.SetHelp(strings.Join(actionKeys, "/"), binding.Help().Desc)
+	}
+	keys = updated
 }
 
 // ViewMode type for TUI
@@ -88,6 +180,7 @@ const (
 	ViewModeRepository ViewMode = iota // Show only current repo sessions
 	ViewModeGlobal                     // Show all sessions across repos
 	ViewModeLog                        // Show log view for selected session (TUI-specific)
+	ViewModeDashboard                  // Show cross-repo dashboard with stats and event feed
 )
 
 // LogView represents the state of the log display
@@ -99,8 +192,113 @@ type LogView struct {
 	errorMessage string
 	maxLines     int
 	maxSizeBytes int // Maximum content size in bytes
+
+	// Long line handling
+	wrap             bool // Wrap long lines instead of scrolling horizontally
+	horizontalOffset int  // First visible column when wrapping is disabled
+
+	// Named loghook tabs from .sbs/loghooks/ (empty when a single loghook or the tmux fallback is used)
+	tabs      []logTab
+	activeTab int
+
+	// Refresh preferences from the worktree's .sbs/loghook.json
+	settings loghook.Settings
+	interval time.Duration // Zero uses the global log refresh interval
+
+	// Overrides from the worktree's .sbs/config.json (nil when it has none)
+	worktreeConfig *config.Config
+}
+
+// logTab holds the buffer and refresh cadence of one loghook source.
+// The active tab's buffer lives in the LogView fields while it is displayed.
+type logTab struct {
+	source           loghook.Source
+	interval         time.Duration // Zero uses the global log refresh interval
+	content          string
+	errorMessage     string
+	scrollOffset     int
+	horizontalOffset int
+	loaded           bool
+	refreshing       bool
+}
+
+// saveActiveTab stores the displayed buffer back into the active tab
+func (lv *LogView) saveActiveTab() {
+	if lv.activeTab < 0 || lv.activeTab >= len(lv.tabs) {
+		return
+	}
+	tab := &lv.tabs[lv.activeTab]
+	tab.content = lv.content
+	tab.errorMessage = lv.errorMessage
+	tab.scrollOffset = lv.scrollOffset
+	tab.horizontalOffset = lv.horizontalOffset
+	tab.refreshing = lv.refreshing
+}
+
+// tabRefreshing reports whether a refresh of the tab is in flight. Without
+// tabs there is only the displayed buffer.
+func (lv *LogView) tabRefreshing(index int) bool {
+	if index == lv.activeTab || index < 0 || index >= len(lv.tabs) {
+		return lv.refreshing
+	}
+	return lv.tabs[index].refreshing
+}
+
+// setTabRefreshing records whether a refresh of the tab is in flight
+func (lv *LogView) setTabRefreshing(index int, refreshing bool) {
+	if index == lv.activeTab || index < 0 || index >= len(lv.tabs) {
+		lv.refreshing = refreshing
+		return
+	}
+	lv.tabs[index].refreshing = refreshing
+}
+
+// switchTab makes the given tab active, restoring its buffer into the view.
+// It returns false if the index is out of range or already active.
+func (lv *LogView) switchTab(index int) bool {
+	if index < 0 || index >= len(lv.tabs) || index == lv.activeTab {
+		return false
+	}
+
+	lv.saveActiveTab()
+	lv.activeTab = index
+	tab := lv.tabs[index]
+	lv.content = tab.content
+	lv.errorMessage = tab.errorMessage
+	lv.scrollOffset = tab.scrollOffset
+	lv.horizontalOffset = tab.horizontalOffset
+	lv.refreshing = tab.refreshing
+	lv.loading = !tab.loaded
+	return true
+}
+
+// limitContent keeps content within maxSizeBytes, preserving the most recent lines
+func (lv *LogView) limitContent(content string) string {
+	if len(content) <= lv.maxSizeBytes {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	truncatedContent := ""
+
+	// Start from the end and work backwards to preserve recent content
+	for i := len(lines) - 1; i >= 0; i-- {
+		newContent := lines[i] + "\n" + truncatedContent
+		if len(newContent) > lv.maxSizeBytes {
+			break
+		}
+		truncatedContent = newContent
+	}
+
+	if truncatedContent == "" {
+		return content
+	}
+	return "[Content truncated to last " + fmt.Sprintf("%d", lv.maxSizeBytes/1024) + "KB]\n" + truncatedContent
 }
 
+// logHorizontalScrollStep is the number of columns moved per left/right key press
+const logHorizontalScrollStep = 8
+
 type Model struct {
 	sessions               []config.SessionMetadata
 	tmuxSessions           []*tmux.Session
@@ -108,11 +306,24 @@ type Model struct {
 	showHelp               bool
 	viewMode               ViewMode
 	currentRepo            *repo.Repository
-	tmuxManager            *tmux.Manager
-	repoManager            *repo.Manager
-	sandboxManager         *sandbox.Manager
+	tmuxManager            TmuxManager
+	sandboxManager         SandboxManager
 	statusDetector         *status.Detector
-	cleanupManager         *cleanup.CleanupManager
+	statusTimeouts         *status.TimeoutResolver // per-repository status timeouts
+	cleanupManager         cleanup.SessionCleaner
+	ctx                    context.Context // cancels background commands when sbs exits
+	historyStore           *status.HistoryStore
+	statusHistory          map[string][]status.Sample // recorded status samples by namespaced ID
+	diffstatCache          *diffstat.Cache
+	diffstats              map[string]diffstat.Stat // branch diffstats by namespaced ID
+	behindCache            *behind.Cache
+	behindStats            map[string]behind.Stat // how far branches are behind their base, by namespaced ID
+	logArchive             *logarchive.Archiver
+	progressBoard          *provisioning.ProgressBoard
+	startProgress          []provisioning.Progress // sessions currently being started
+	expiryNotifier         *expiry.Notifier
+	timings                *timing.Store
+	repoGroup              string // repository group the global view is scoped to, "" for all
 	config                 *config.Config
 	width                  int
 	height                 int
@@ -124,55 +335,246 @@ type Model struct {
 	logView              *LogView
 	previousViewMode     ViewMode
 	logAutoRefreshActive bool
-	logAutoRefreshMutex  sync.Mutex // Prevent multiple concurrent refreshes
+	logAutoRefreshMutex  *sync.Mutex // Prevent multiple concurrent refreshes; shared by copies of the model
+	logRefreshGeneration int         // Ticks scheduled before auto-refresh last started are dropped
 	pendingCleanSessions []config.SessionMetadata
+	cleanChecklist       *checklist                       // Sessions ticked for cleaning in the confirmation dialog
+	cleanBatch           *cleanup.Batch                   // Clean running in the background; esc cancels it
+	cleanProgress        *cleanup.Progress                // Last session the running clean finished
+	cleanResults         map[string]cleanup.SessionResult // Sessions the running clean finished
+	logHighlighter       *LogHighlighter
+
+	// Tmux control-mode state (nil when control mode is disabled or unavailable)
+	tmuxControl    *tmux.ControlMux
+	tmuxEvents     <-chan tmux.ControlEvent
+	sessionTracker *tmux.SessionTracker
+
+	// Dashboard state
+	dashboard           *dashboardState
+	dashboardReturnMode ViewMode
+
+	// Formatted session rows, reused across frames
+	rowCache *rowCache
+
+	// Config hot-reload state
+	configWatcher *config.Watcher
+	notice        string // Status-line message, e.g. after a config reload
+	noticeIsError bool
+
+	// Error panel state
+	errorHistory *errorHistory
+	showErrors   bool
+
+	// Sessions waiting for input, by namespaced ID; waitingKnown is set after
+	// the first refresh so sessions already waiting at startup don't ring the bell
+	waiting      map[string]bool
+	waitingKnown bool
+
+	// Sessions whose branch was renamed outside sbs, by namespaced ID
+	renamedBranches map[string]string
+
+	// Command palette state; sessions holds listedSessions filtered and
+	// sorted by the palette's :filter and :sort
+	palette            *commandPalette
+	listedSessions     []config.SessionMetadata
+	sessionFilter      sessionFilter
+	sortKey            string
+	paletteHistory     []string
+	paletteHistoryPath string
+	startCommand       func(workItemID string) *exec.Cmd
+
+	// clock and loadSessions stand in for config.Clock and the sessions file in tests
+	clock        clock.Clock
+	loadSessions func() ([]config.SessionMetadata, error)
+}
+
+// now returns the current time from the model's clock
+func (m Model) now() time.Time {
+	if m.clock == nil {
+		return config.Now()
+	}
+	return m.clock.Now()
+}
+
+// baseContext returns the context background commands run under
+func (m Model) baseContext() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
 }
 
 func NewModel() Model {
-	repoManager := repo.NewManager()
-	currentRepo, _ := repoManager.DetectCurrentRepository()
+	return NewModelWithContainer(app.NewContainer(nil))
+}
 
-	// Load configuration
-	cfg, _ := config.LoadConfig()
+// NewModelWithContainer creates a model using services from the given container.
+// Tmux control mode is started asynchronously from Init so it doesn't delay the first frame.
+func NewModelWithContainer(c *app.Container) Model {
+	defer app.Track("init tui model")()
+
+	return NewModelWithDependencies(Dependencies{
+		Context:        c.Context(),
+		Config:         c.Config(),
+		CurrentRepo:    c.CurrentRepository(),
+		Tmux:           c.TmuxManager(),
+		Sandbox:        c.SandboxManager(),
+		Cleanup:        c.CleanupManager(),
+		StatusDetector: c.StatusDetector(),
+		StatusHistory:  c.StatusHistory(),
+		Diffstats:      c.DiffstatCache(),
+		Behind:         c.BehindCache(),
+		LogArchive:     c.LogArchive(),
+		StartProgress:  c.ProgressBoard(),
+		ExpiryNotifier: c.ExpiryNotifier(),
+		Timings:        c.Timings(),
+		PaletteHistory: defaultPaletteHistoryPath(),
+	})
+}
+
+// NewModelWithDependencies creates a model from explicitly provided services,
+// allowing tests to substitute fakes for tmux, sandbox and cleanup operations
+func NewModelWithDependencies(deps Dependencies) Model {
+	cfg := deps.Config
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
 
 	// Default to repository view if in a repo, global otherwise
 	viewMode := ViewModeGlobal
-	if currentRepo != nil {
+	if deps.CurrentRepo != nil {
 		viewMode = ViewModeRepository
 	}
 
-	tmuxManager := tmux.NewManager()
-	sandboxManager := sandbox.NewManager()
-	cleanupManager := cleanup.NewCleanupManager(tmuxManager, sandboxManager, nil, nil)
+	ctx := deps.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	statusDetector := deps.StatusDetector
+	if statusDetector == nil {
+		statusDetector = status.NewDetector(deps.Tmux, deps.Sandbox)
+	}
+	statusTimeouts := status.NewTimeoutResolver(cfg.StatusTimeoutSeconds)
+	statusDetector.SetTimeouts(statusTimeouts)
+	if deps.Clock != nil {
+		statusDetector.SetClock(deps.Clock)
+	}
+
+	ApplyTheme(cfg.Theme)
+	ApplyKeyBindings(cfg.KeyBindings)
+
+	startCommand := deps.StartCommand
+	if startCommand == nil {
+		startCommand = startWorkItemCommand
+	}
+	loadSessions := deps.LoadSessions
+	if loadSessions == nil {
+		loadSessions = config.LoadAllRepositorySessions
+	}
+
 	return Model{
+		ctx:                    ctx,
+		logAutoRefreshMutex:    &sync.Mutex{},
 		sessions:               []config.SessionMetadata{},
 		cursor:                 0,
 		showHelp:               false,
 		viewMode:               viewMode,
-		currentRepo:            currentRepo,
-		tmuxManager:            tmuxManager,
-		repoManager:            repoManager,
-		sandboxManager:         sandboxManager,
-		statusDetector:         status.NewDetector(tmuxManager, sandboxManager),
-		cleanupManager:         cleanupManager,
+		currentRepo:            deps.CurrentRepo,
+		tmuxManager:            deps.Tmux,
+		sandboxManager:         deps.Sandbox,
+		statusDetector:         statusDetector,
+		statusTimeouts:         statusTimeouts,
+		cleanupManager:         deps.Cleanup,
+		historyStore:           deps.StatusHistory,
+		diffstatCache:          deps.Diffstats,
+		behindCache:            deps.Behind,
+		logArchive:             deps.LogArchive,
+		progressBoard:          deps.StartProgress,
+		expiryNotifier:         deps.ExpiryNotifier,
+		timings:                deps.Timings,
 		config:                 cfg,
 		showConfirmationDialog: false,
 		confirmationMessage:    "",
 		pendingCleanSessions:   []config.SessionMetadata{},
+		logHighlighter:         buildLogHighlighter(cfg),
+		dashboard:              newDashboardState(),
+		rowCache:               newRowCache(),
+		errorHistory:           newErrorHistory(),
+		paletteHistory:         loadPaletteHistory(deps.PaletteHistory),
+		paletteHistoryPath:     deps.PaletteHistory,
+		startCommand:           startCommand,
+		clock:                  deps.Clock,
+		loadSessions:           loadSessions,
 	}
 }
 
+// buildLogHighlighter falls back to the built-in highlight rules if custom rules are invalid
+func buildLogHighlighter(cfg *config.Config) *LogHighlighter {
+	logHighlighter, err := NewLogHighlighter(cfg.LogHighlightRules)
+	if err != nil {
+		logHighlighter, _ = NewLogHighlighter(nil)
+	}
+	return logHighlighter
+}
+
+// NewDashboardModel creates a model that starts in the cross-repo dashboard view
+func NewDashboardModel(c *app.Container) Model {
+	m := NewModelWithContainer(c)
+	m.dashboardReturnMode = m.viewMode
+	m.viewMode = ViewModeDashboard
+	return m
+}
+
+// startTmuxControlMode creates the control-mode multiplexer and the session
+// tracker it feeds. Sessions are watched as refreshes find them; until one is,
+// the tracker queries tmux directly.
+func startTmuxControlMode(tmuxManager TmuxManager) (*tmux.ControlMux, *tmux.SessionTracker) {
+	return tmux.NewControlMux(), tmux.NewSessionTracker(tmuxManager)
+}
+
+// watchedTmuxSessions returns the tmux sessions of sessions that are running
+func watchedTmuxSessions(sessions []config.SessionMetadata, tmuxSessions []*tmux.Session) []string {
+	running := make(map[string]bool, len(tmuxSessions))
+	for _, tmuxSession := range tmuxSessions {
+		running[tmuxSession.Name] = true
+	}
+
+	var names []string
+	for _, session := range sessions {
+		if running[session.TmuxSession] {
+			names = append(names, session.TmuxSession)
+		}
+	}
+	return names
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.refreshSessions(),
 		tea.EnterAltScreen,
 		m.tickAutoRefresh(),
+		m.waitForTmuxEvent(),
+		m.connectTmuxControlMode(),
+		m.startConfigWatch(),
+		m.pollStartProgress(),
+		m.checkTimings(),
 	)
 }
 
+// connectTmuxControlMode starts the control-mode listener in the background when enabled
+func (m Model) connectTmuxControlMode() tea.Cmd {
+	if !m.config.TmuxControlMode || m.tmuxEvents != nil {
+		return nil
+	}
+
+	tmuxManager := m.tmuxManager
+	return func() tea.Msg {
+		control, tracker := startTmuxControlMode(tmuxManager)
+		return tmuxControlStartedMsg{control: control, tracker: tracker}
+	}
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -183,30 +585,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		// Handle modal dialog keys first (higher priority)
 		if m.showConfirmationDialog {
+			if m.cleanChecklist != nil && m.cleanChecklist.handleKey(msg) {
+				m.confirmationMessage = m.cleanConfirmationMessage()
+				return m, nil
+			}
 			switch msg.Type {
 			case tea.KeyEsc:
-				m.showConfirmationDialog = false
-				m.confirmationMessage = ""
-				m.pendingCleanSessions = []config.SessionMetadata{}
-				return m, nil
+				return m.cancelClean(), nil
 			case tea.KeyEnter:
-				m.showConfirmationDialog = false
-				return m, m.executeCleanup()
+				return m.confirmClean()
 			case tea.KeyRunes:
 				switch string(msg.Runes) {
 				case "y", "Y":
-					m.showConfirmationDialog = false
-					return m, m.executeCleanup()
+					return m.confirmClean()
 				case "n", "N":
-					m.showConfirmationDialog = false
-					m.confirmationMessage = ""
-					m.pendingCleanSessions = []config.SessionMetadata{}
-					return m, nil
+					return m.cancelClean(), nil
 				}
 			}
 			return m, nil
 		}
 
+		// The command palette takes all keys while open
+		if m.palette != nil {
+			return m.handlePaletteKey(msg)
+		}
+
+		// The error panel covers the session list until closed
+		if m.showErrors {
+			switch {
+			case key.Matches(msg, keys.Quit):
+				return m, m.quit()
+			case msg.Type == tea.KeyEsc, key.Matches(msg, keys.Errors):
+				return m.toggleErrorPanel(), nil
+			}
+			return m, nil
+		}
+
 		// Handle log view keys when in log view mode
 		if m.viewMode == ViewModeLog {
 			switch msg.Type {
@@ -221,15 +635,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case tea.KeyDown:
-				if m.logView != nil {
-					lines := strings.Split(m.logView.content, "\n")
-					maxScroll := len(lines) - m.height + 5 // Leave some space for UI
-					if maxScroll < 0 {
-						maxScroll = 0
-					}
-					if m.logView.scrollOffset < maxScroll {
-						m.logView.scrollOffset++
-					}
+				if m.logView != nil && m.logView.scrollOffset < m.maxLogScrollOffset() {
+					m.logView.scrollOffset++
+				}
+				return m, nil
+			case tea.KeyLeft:
+				if m.logView != nil && !m.logView.wrap {
+					m.logView.horizontalOffset = maxInt(0, m.logView.horizontalOffset-logHorizontalScrollStep)
+				}
+				return m, nil
+			case tea.KeyRight:
+				if m.logView != nil && !m.logView.wrap && m.logView.horizontalOffset < m.maxLogHorizontalOffset() {
+					m.logView.horizontalOffset = min(m.logView.horizontalOffset+logHorizontalScrollStep, m.maxLogHorizontalOffset())
 				}
 				return m, nil
 			case tea.KeyRunes:
@@ -239,6 +656,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.viewMode = m.previousViewMode
 					m.stopLogAutoRefresh()
 					return m, nil
+				case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+					// Switch loghook tab and refresh it
+					if m.logView != nil && m.logView.switchTab(int(msg.Runes[0]-'1')) {
+						m.logView.refreshing = true
+						return m, m.refreshLogContent()
+					}
+					return m, nil
+				case "w":
+					// Toggle line wrapping, keeping the scroll offset within the new line count
+					if m.logView != nil {
+						m.logView.wrap = !m.logView.wrap
+						m.logView.horizontalOffset = 0
+						m.logView.scrollOffset = min(m.logView.scrollOffset, m.maxLogScrollOffset())
+					}
+					return m, nil
 				case "r":
 					// Manual refresh and restart auto-refresh if it was stopped
 					m.logAutoRefreshMutex.Lock()
@@ -247,6 +679,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.logView.refreshing = true
 							// Re-enable auto-refresh when user manually retries
 							m.logAutoRefreshActive = true
+							m.logRefreshGeneration++
 							m.logAutoRefreshMutex.Unlock()
 							return m, tea.Batch(
 								m.refreshLogContent(),
@@ -261,10 +694,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// ESC stops a running clean before its next session
+		if m.cleanBatch != nil && msg.Type == tea.KeyEsc {
+			m.cleanBatch.Cancel()
+			return m, nil
+		}
+
+		// ESC leaves the dashboard
+		if m.viewMode == ViewModeDashboard && msg.Type == tea.KeyEsc {
+			return m.toggleDashboard(), m.refreshSessions()
+		}
+
 		// Normal key handling when modal is not shown
 		switch {
 		case key.Matches(msg, keys.Quit):
-			return m, tea.Quit
+			return m, m.quit()
+
+		case key.Matches(msg, keys.Dashboard):
+			m = m.toggleDashboard()
+			return m, m.refreshSessions()
 
 		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
@@ -278,6 +726,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, keys.PageUp):
+			return m.pageCursor(-1), nil
+
+		case key.Matches(msg, keys.PageDown):
+			return m.pageCursor(1), nil
+
 		case key.Matches(msg, keys.Enter):
 			if len(m.sessions) > 0 && m.cursor < len(m.sessions) {
 				sessionName := m.sessions[m.cursor].TmuxSession
@@ -285,6 +739,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, keys.Recent):
+			if m.viewMode == ViewModeDashboard {
+				return m, nil
+			}
+			return m, m.attachToRecent(msg.String())
+
 		case key.Matches(msg, keys.Stop):
 			if len(m.sessions) > 0 && m.cursor < len(m.sessions) {
 				return m, m.stopSelectedSession()
@@ -292,24 +752,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, keys.Clean):
+			if m.viewMode == ViewModeDashboard {
+				return m, nil
+			}
+			if m.cleanBatch != nil {
+				return m.toast("A clean is already running; press esc to cancel it")
+			}
 			return m.showCleanConfirmation(), nil
 
 		case key.Matches(msg, keys.Help):
 			m.showHelp = !m.showHelp
 			return m, nil
 
+		case key.Matches(msg, keys.Palette):
+			return m.openPalette()
+
+		case key.Matches(msg, keys.Errors):
+			return m.toggleErrorPanel(), nil
+
 		case key.Matches(msg, keys.Refresh):
 			return m, m.refreshSessions()
 
 		case key.Matches(msg, keys.ToggleView):
+			if m.viewMode == ViewModeDashboard {
+				return m, nil
+			}
 			return m.toggleViewMode(), m.refreshSessions()
 
+		case key.Matches(msg, keys.Group):
+			if m.viewMode == ViewModeDashboard {
+				return m, nil
+			}
+			if len(m.config.RepositoryGroups) == 0 {
+				return m.toast("No repository_groups configured")
+			}
+			m = m.cycleRepoGroup()
+			return m, m.refreshSessions()
+
 		case key.Matches(msg, keys.LogView):
 			// Enter log view mode if we have sessions and a valid selection
 			if len(m.sessions) > 0 && m.cursor >= 0 && m.cursor < len(m.sessions) {
 				m.previousViewMode = m.viewMode
 				m.viewMode = ViewModeLog
 				m.logAutoRefreshActive = true
+				m.logRefreshGeneration++
 
 				// Initialize log view if not already initialized
 				if m.logView == nil {
@@ -326,6 +812,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.logView.loading = true
 				}
 
+				// Set up one tab per named loghook, each with its own buffer,
+				// and apply the worktree's refresh preferences
+				m = m.loadLogSettings(m.sessions[m.cursor])
+				m.logView.activeTab = 0
+				if len(m.logView.tabs) > 0 {
+					m.logView.content = ""
+					m.logView.errorMessage = ""
+					m.logView.scrollOffset = 0
+					m.logView.horizontalOffset = 0
+				}
+
 				// Start auto-refresh and initial content load
 				return m, tea.Batch(
 					m.refreshLogContent(),
@@ -336,25 +833,173 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case refreshMsg:
-		m.sessions = msg.sessions
+		if msg.err != nil {
+			// Keep showing the last good session list
+			return m.reportError(fmt.Errorf("refresh failed: %w", msg.err)), nil
+		}
+		m.listedSessions = msg.sessions
+		m.sessions = arrangeSessions(msg.sessions, m.sessionFilter, m.sortKey)
 		m.tmuxSessions = msg.tmuxSessions
-		m.error = msg.err
+		m.renamedBranches = msg.renamed
+		var bell tea.Cmd
+		m, bell = m.updateWaiting(msg.waiting)
+		if msg.history != nil {
+			m.statusHistory = msg.history
+		}
+		if msg.diffstats != nil {
+			m.diffstats = msg.diffstats
+		}
+		if msg.behind != nil {
+			m.behindStats = msg.behind
+		}
+
+		// Update the dashboard snapshot and event feed from global refreshes
+		if msg.dashboard && m.dashboard != nil {
+			now := m.now()
+			m.dashboard.observe(msg.sessions, m.getSessionStatus, now)
+			if m.dashboard.diskUsageDue(now) {
+				m.dashboard.diskBusy = true
+				return m, tea.Batch(bell, m.refreshDiskUsage())
+			}
+		}
+		return m, bell
+
+	case dashboardDiskUsageMsg:
+		if m.dashboard != nil {
+			m.dashboard.diskUsage = msg.usage
+			m.dashboard.diskLoaded = m.now()
+			m.dashboard.diskBusy = false
+		}
 		return m, nil
 
+	case paletteStartMsg:
+		if msg.err != nil {
+			m = m.reportError(fmt.Errorf("sbs start %s failed: %w", msg.workItem, msg.err), msg.workItem)
+		}
+		return m, m.refreshSessions()
+
 	case attachMsg:
 		if msg.err != nil {
-			m.error = msg.err
+			m = m.reportError(msg.err, msg.session)
 		}
 		return m, nil
 
 	case stopSessionMsg:
-		m.error = msg.err
-		return m, m.refreshSessions()
+		if msg.err != nil {
+			return m.reportError(msg.err, msg.session), m.refreshSessions()
+		}
+		m.error = nil
+		var toast tea.Cmd
+		m, toast = m.toast(fmt.Sprintf("Stopped session %s", msg.session))
+		return m, tea.Batch(toast, m.refreshSessions())
+
+	case cleanProgressMsg:
+		m.cleanProgress = &msg.progress
+		if m.cleanResults == nil {
+			m.cleanResults = make(map[string]cleanup.SessionResult)
+		}
+		m.cleanResults[msg.progress.Result.Session.NamespacedID] = msg.progress.Result
+		return m, m.waitForCleanProgress()
 
 	case cleanSessionsMsg:
-		m.error = msg.err
 		m.showConfirmationDialog = false
-		return m, m.refreshSessions()
+		m.cleanChecklist = nil
+		m.cleanBatch = nil
+		m.cleanProgress = nil
+		m.cleanResults = nil
+		for _, failure := range msg.failures {
+			m = m.reportError(failure)
+		}
+		if msg.err != nil && !msg.cancelled {
+			return m.reportError(msg.err), m.refreshSessions()
+		}
+		m.error = nil
+		var toast tea.Cmd
+		notice := fmt.Sprintf("Cleaned %d session(s)", len(msg.cleanedSessions))
+		if msg.cancelled {
+			notice = fmt.Sprintf("Clean cancelled; cleaned %d session(s) first", len(msg.cleanedSessions))
+		}
+		if msg.busy > 0 {
+			notice += fmt.Sprintf("; skipped %d busy in another sbs process", msg.busy)
+		}
+		m, toast = m.toast(notice)
+		return m, tea.Batch(toast, m.refreshSessions())
+
+	case timingHintMsg:
+		m.notice = msg.hint
+		m.noticeIsError = false
+		return m, clearNoticeAfter(msg.hint, timingHintDuration)
+
+	case tmuxControlStartedMsg:
+		// Use control-mode events for session existence checks
+		m.tmuxControl = msg.control
+		m.tmuxEvents = msg.control.Events()
+		m.sessionTracker = msg.tracker
+		m.statusDetector = status.NewDetector(msg.tracker, m.sandboxManager)
+		m.statusDetector.SetTimeouts(m.statusTimeouts)
+
+		// The next refresh watches the running sessions
+		return m, tea.Batch(m.waitForTmuxEvent(), m.refreshSessions())
+
+	case configWatchStartedMsg:
+		m.configWatcher = msg.watcher
+		return m, m.waitForConfigChange()
+
+	case configChangedMsg:
+		if msg.closed {
+			m.configWatcher = nil
+			return m, nil
+		}
+		return m, tea.Batch(m.reloadConfig(), m.waitForConfigChange())
+
+	case configReloadedMsg:
+		if msg.err != nil {
+			// Keep running with the previous configuration
+			m.notice = fmt.Sprintf("Config invalid, keeping previous settings: %v", msg.err)
+			m.noticeIsError = true
+			return m, nil
+		}
+		m = m.applyConfig(msg.config)
+		m.notice = "Config reloaded"
+		m.noticeIsError = false
+		return m, clearNoticeAfter(m.notice, noticeDuration)
+
+	case clearNoticeMsg:
+		if m.notice == msg.notice {
+			m.notice = ""
+			m.noticeIsError = false
+		}
+		return m, nil
+
+	case tmuxEventMsg:
+		if msg.closed {
+			// Control client exited; fall back to polling only
+			m.tmuxEvents = nil
+			if m.sessionTracker != nil {
+				m.sessionTracker.Apply(tmux.ControlEvent{Type: tmux.EventExit})
+			}
+			return m, nil
+		}
+
+		if m.sessionTracker != nil {
+			m.sessionTracker.Apply(msg.event)
+		}
+		if msg.event.IsSessionLifecycle() {
+			return m, tea.Batch(
+				m.refreshSessions(),
+				m.waitForTmuxEvent(),
+			)
+		}
+		return m, m.waitForTmuxEvent()
+
+	case startProgressMsg:
+		finished := len(msg.progress) < len(m.startProgress)
+		m.startProgress = msg.progress
+		if finished {
+			// A start completed, so its session is now in sessions.json
+			return m, tea.Batch(m.refreshSessions(), m.pollStartProgress())
+		}
+		return m, m.pollStartProgress()
 
 	case tickMsg:
 		// Auto-refresh sessions and schedule next tick
@@ -365,7 +1010,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case logRefreshTickMsg:
 		// Handle auto-refresh for log view - prevent race conditions
-		if m.viewMode == ViewModeLog && m.logAutoRefreshActive {
+		if m.viewMode == ViewModeLog && m.logAutoRefreshActive && msg.generation == m.logRefreshGeneration {
 			// Use mutex to prevent multiple concurrent refreshes
 			m.logAutoRefreshMutex.Lock()
 			defer m.logAutoRefreshMutex.Unlock()
@@ -383,15 +1028,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			// Only start refresh if not already refreshing
-			if !m.logView.refreshing {
+			// Only start refresh if the tab isn't already refreshing
+			if !m.logView.tabRefreshing(msg.tab) {
+				m.logView.setTabRefreshing(msg.tab, true)
 				return m, tea.Batch(
-					m.refreshLogContent(),
-					m.startLogAutoRefresh(),
+					m.refreshLogTab(msg.tab),
+					m.scheduleLogRefresh(msg.tab),
 				)
 			} else {
 				// Still schedule next refresh even if currently refreshing
-				return m, m.startLogAutoRefresh()
+				return m, m.scheduleLogRefresh(msg.tab)
 			}
 		}
 		return m, nil
@@ -399,8 +1045,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case logRefreshResultMsg:
 		// Handle log refresh results
 		if m.logView != nil {
+			m.logView.setTabRefreshing(msg.tab, false)
+
+			// Results for a background tab only update that tab's buffer
+			if len(m.logView.tabs) > 0 && msg.tab != m.logView.activeTab {
+				if msg.tab >= 0 && msg.tab < len(m.logView.tabs) {
+					tab := &m.logView.tabs[msg.tab]
+					tab.loaded = true
+					if msg.err != nil {
+						tab.errorMessage = fmt.Sprintf("refresh failed: %v", msg.err)
+					} else {
+						tab.content = m.logView.limitContent(msg.content)
+						tab.errorMessage = ""
+					}
+				}
+				return m, nil
+			}
+
 			m.logView.loading = false
-			m.logView.refreshing = false
+			if len(m.logView.tabs) > 0 {
+				m.logView.tabs[m.logView.activeTab].loaded = true
+			}
 
 			if msg.err != nil {
 				m.logView.errorMessage = fmt.Sprintf("refresh failed: %v", msg.err)
@@ -412,27 +1077,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			} else {
 				// Apply content size limits and rotation
-				content := msg.content
-				if len(content) > m.logView.maxSizeBytes {
-					// Truncate content to last portion that fits within limits
-					lines := strings.Split(content, "\n")
-					truncatedContent := ""
-
-					// Start from the end and work backwards to preserve recent content
-					for i := len(lines) - 1; i >= 0; i-- {
-						newContent := lines[i] + "\n" + truncatedContent
-						if len(newContent) > m.logView.maxSizeBytes {
-							break
-						}
-						truncatedContent = newContent
-					}
-
-					if truncatedContent != "" {
-						content = "[Content truncated to last " + fmt.Sprintf("%d", m.logView.maxSizeBytes/1024) + "KB]\n" + truncatedContent
-					}
-				}
-
-				m.logView.content = content
+				m.logView.content = m.logView.limitContent(msg.content)
 				m.logView.errorMessage = ""
 			}
 		}
@@ -452,8 +1097,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
-	if m.error != nil {
-		return fmt.Sprintf("Error: %v\n\nPress q to quit", m.error)
+	if m.showErrors {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			Render(m.renderErrorPanel())
 	}
 
 	// Handle log view rendering
@@ -461,19 +1109,37 @@ func (m Model) View() string {
 		return m.renderLogView()
 	}
 
+	if m.viewMode == ViewModeDashboard {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			Render(m.renderDashboard())
+	}
+
 	var b strings.Builder
 
 	// Title with view mode indicator
 	var title string
 	if m.currentRepo != nil && m.viewMode == ViewModeRepository {
 		title = titleStyle.Render(fmt.Sprintf("Work Issue Orchestrator (%s)", m.currentRepo.Name))
+	} else if m.repoGroup != "" {
+		title = titleStyle.Render(fmt.Sprintf("Work Issue Orchestrator (Global: %s)", m.repoGroup))
 	} else {
 		title = titleStyle.Render("Work Issue Orchestrator (Global)")
 	}
-	b.WriteString(title + "\n\n")
+	b.WriteString(title + m.renderWaitingCount() + "\n")
+	if recent := m.recentSessions(); len(recent) > 0 {
+		b.WriteString(renderRecentLine(recent, m.width) + "\n")
+	}
+	if arrangement := m.renderArrangement(); arrangement != "" {
+		b.WriteString(arrangement + "\n")
+	}
+	b.WriteString("\n")
 
 	// Sessions list
-	if len(m.sessions) == 0 {
+	if len(m.sessions) == 0 && !m.sessionFilter.empty() && len(m.listedSessions) > 0 {
+		b.WriteString(mutedStyle.Render("No sessions match the filter.") + "\n")
+	} else if len(m.sessions) == 0 {
 		b.WriteString(mutedStyle.Render("No active work sessions found.") + "\n")
 		b.WriteString(mutedStyle.Render("Use 'sbs start <issue-number>' to create a new session.") + "\n")
 	} else {
@@ -481,60 +1147,145 @@ func (m Model) View() string {
 		var widths ColumnWidths
 		var headerRow string
 
+		// Leave room for the status sparkline when history is recorded
+		tableWidth := m.width
+		showTrend := m.historyStore != nil
+		if showTrend {
+			tableWidth -= trendWidth + 1
+		}
+		showDiffstat := m.showDiffstat()
+		if showDiffstat {
+			tableWidth -= diffstatWidth + 1
+		}
+		expiryPolicy := expiry.FromConfig(m.config)
+		if expiryPolicy.Enabled() {
+			tableWidth -= expiresWidth + 1
+		}
+
 		if m.viewMode == ViewModeGlobal {
-			widths = CalculateGlobalViewWidths(m.width)
+			widths = CalculateGlobalViewWidths(tableWidth)
 			headerRow = FormatGlobalViewHeader(widths)
 		} else {
-			widths = CalculateRepositoryViewWidths(m.width)
+			widths = CalculateRepositoryViewWidths(tableWidth)
 			headerRow = FormatRepositoryViewHeader(widths)
 		}
+		if showDiffstat {
+			headerRow += fmt.Sprintf(" %-*s", diffstatWidth, "CHANGES")
+		}
+		if expiryPolicy.Enabled() {
+			headerRow += fmt.Sprintf(" %-*s", expiresWidth, "EXPIRES")
+		}
+		if showTrend {
+			headerRow += " Last hour"
+		}
 
 		b.WriteString(tableHeaderStyle.Render(headerRow) + "\n")
 
-		// Sessions
-		for i, session := range m.sessions {
+		// Only the page containing the cursor is rendered, so status detection
+		// and formatting cost stays bounded with hundreds of sessions
+		pageSize := m.sessionTableRows()
+		start, end := visibleRange(len(m.sessions), m.cursor, pageSize)
+
+		selectedWarning := ""
+		selectedWaiting := ""
+		selectedRename := ""
+		selectedBehind := ""
+		selectedTrend := ""
+		now := m.now()
+		behindThreshold := behind.FromConfig(m.config)
+		for i := start; i < end; i++ {
+			session := m.sessions[i]
+
 			// Determine actual status using status detector
 			sessionStatus := m.getSessionStatus(session)
+			if i == m.cursor {
+				selectedWarning = sessionStatus.Warning
+				if sessionStatus.Status == "waiting" {
+					selectedWaiting = sessionStatus.Message
+				}
+				if newBranch := m.renamedBranches[session.NamespacedID]; newBranch != "" {
+					selectedRename = formatBranchRename(session, newBranch)
+				}
+			}
+			stat, isBehind := m.behindStats[session.NamespacedID]
+			isBehind = isBehind && behindThreshold.Exceeded(stat, now)
+			if isBehind && i == m.cursor {
+				selectedBehind = formatBranchBehind(session, stat, now)
+			}
 
-			// Format row based on view mode using responsive widths
-			var row string
-			if m.viewMode == ViewModeGlobal {
-				row = FormatGlobalViewRow(widths,
-					session.NamespacedID,
-					session.IssueTitle,
-					session.RepositoryName,
-					session.Branch,
-					FormatStatus(sessionStatus.Status),
-					sessionStatus.TimeDelta,
-				)
-			} else {
-				row = FormatRepositoryViewRow(widths,
-					session.NamespacedID,
-					session.IssueTitle,
-					session.Branch,
-					FormatStatus(sessionStatus.Status),
-					sessionStatus.TimeDelta,
-				)
+			sparkline := ""
+			if showTrend {
+				samples := m.statusHistory[session.NamespacedID]
+				sparkline = status.Sparkline(samples, now, trendWindow, trendWidth)
+				if i == m.cursor {
+					selectedTrend = status.Trend(samples, now, trendWindow)
+				}
 			}
 
-			// Apply selection style
-			if i == m.cursor {
-				row = selectedRowStyle.Render(row)
-			} else {
-				row = tableCellStyle.Render(row)
+			changes := ""
+			if showDiffstat {
+				changes = formatDiffstat(m.diffstats, session.NamespacedID)
+			}
+			expires := ""
+			if expiryPolicy.Enabled() {
+				expires = fmt.Sprintf("%-*s", expiresWidth, expiryPolicy.Check(session, now).Label())
 			}
 
+			row := m.formatSessionRow(widths, m.viewMode == ViewModeGlobal, session.NamespacedID, session, sessionStatus, isBehind, changes, expires, sparkline, i == m.cursor)
 			b.WriteString(row + "\n")
 		}
+
+		if end-start < len(m.sessions) {
+			b.WriteString(mutedStyle.Render(formatPageIndicator(start, end, len(m.sessions), pageSize)) + "\n")
+		}
+
+		// Status detection problems for the selected session
+		if selectedWarning != "" {
+			b.WriteString("\n" + warningStyle.Render("Warning: "+selectedWarning) + "\n")
+		}
+		if selectedWaiting != "" {
+			b.WriteString("\n" + statusWaitingStyle.Render("Waiting: "+selectedWaiting) + "\n")
+		}
+		if selectedRename != "" {
+			b.WriteString("\n" + warningStyle.Render(selectedRename) + "\n")
+		}
+		if selectedBehind != "" {
+			b.WriteString("\n" + mutedStyle.Render(selectedBehind) + "\n")
+		}
+		if selectedTrend != "" {
+			b.WriteString(mutedStyle.Render(formatTrend(selectedTrend)) + "\n")
+		}
+	}
+
+	// Sessions being started by sbs start elsewhere
+	if len(m.startProgress) > 0 {
+		b.WriteString("\n" + m.renderStartProgress() + "\n")
+	}
+
+	// Clean running in the background
+	if m.cleanBatch != nil {
+		b.WriteString("\n" + m.cleanProgressView().render(m.now(), m.width) + "\n")
+	}
+
+	// Latest unacknowledged error; the full history is in the error panel
+	if m.error != nil {
+		b.WriteString("\n" + m.renderErrorLine() + "\n")
+	}
+
+	// Status line notice (e.g. config reload)
+	if m.notice != "" {
+		b.WriteString("\n" + m.renderNotice() + "\n")
 	}
 
-	// Help
-	if m.showHelp {
+	// Help, or the command palette while it is open
+	if m.palette != nil {
+		b.WriteString("\n" + m.palette.view(m.width))
+	} else if m.showHelp {
 		b.WriteString("\n" + m.helpView())
 	} else {
-		helpText := "\nPress enter: attach, l: logs, s: stop, c: clean, ?: help, g: toggle, r: refresh, q: quit"
+		helpText := "\nPress enter: attach, l: logs, s: stop, c: clean, ?: help, g: toggle, D: dashboard, :: commands, r: refresh, q: quit"
 		if m.currentRepo == nil && m.viewMode == ViewModeRepository {
-			helpText = "\nNot in git repository - global view. Press enter: attach, l: logs, s: stop, c: clean, ?: help, r: refresh, q: quit"
+			helpText = "\nNot in git repository - global view. Press enter: attach, l: logs, s: stop, c: clean, ?: help, D: dashboard, :: commands, r: refresh, q: quit"
 		}
 		b.WriteString(helpStyle.Render(helpText))
 	}
@@ -571,24 +1322,114 @@ func (m Model) View() string {
 	return content
 }
 
+// formatSessionRow formats one session table row in the global or repository
+// layout, reusing the cached row when nothing shown in it has changed. The
+// diffstat and sparkline columns are appended when not empty, and a branch
+// far behind its base is marked after the status.
+func (m Model) formatSessionRow(widths ColumnWidths, global bool, id string, session config.SessionMetadata, sessionStatus status.SessionStatus, isBehind bool, changes, expires, sparkline string, selected bool) string {
+	statusText := FormatStatusWithWarning(sessionStatus.Status, sessionStatus.Warning)
+	if isBehind {
+		statusText += mutedStyle.Render(behindMarker)
+	}
+	badges := m.sourceBadges()
+	source := inputsource.SessionSource(session)
+	id = badges.Label(source, id)
+
+	cacheKey := rowCacheKey{
+		global:   global,
+		width:    m.width,
+		selected: selected,
+		id:       id,
+		title:    session.IssueTitle,
+		repo:     session.RepositoryName,
+		branch:   session.Branch,
+		status:   statusText,
+		delta:    sessionStatus.TimeDelta,
+		changes:  changes,
+		expires:  expires,
+		trend:    sparkline,
+	}
+
+	return m.rowCache.get(cacheKey, func() string {
+		// Format row based on view mode using responsive widths
+		var row string
+		if global {
+			row = FormatGlobalViewRow(widths,
+				id,
+				session.IssueTitle,
+				session.RepositoryName,
+				session.Branch,
+				statusText,
+				sessionStatus.TimeDelta,
+			)
+		} else {
+			row = FormatRepositoryViewRow(widths,
+				id,
+				session.IssueTitle,
+				session.Branch,
+				statusText,
+				sessionStatus.TimeDelta,
+			)
+		}
+
+		if changes != "" {
+			row += " " + changes
+		}
+		if expires != "" {
+			row += " " + expires
+		}
+		if sparkline != "" {
+			row += " " + sparkline
+		}
+		row = badges.Colorize(row, source)
+
+		// Apply selection style; sessions waiting for input stand out
+		if selected {
+			if sessionStatus.Status == "waiting" {
+				return selectedRowStyle.Foreground(secondaryColor).Render(row)
+			}
+			return selectedRowStyle.Render(row)
+		}
+		if sessionStatus.Status == "waiting" {
+			return waitingRowStyle.Render(row)
+		}
+		return tableCellStyle.Render(row)
+	})
+}
+
+// formatTrend describes a session's status trend for the line under the table
+func formatTrend(trend string) string {
+	switch trend {
+	case "working", "waiting", "idle":
+		return "Last hour: mostly " + trend
+	default:
+		return "Last hour: " + trend
+	}
+}
+
 func (m Model) helpView() string {
 	var help strings.Builder
 	help.WriteString(headerStyle.Render("Help") + "\n")
 	help.WriteString("↑/k    - Move up\n")
 	help.WriteString("↓/j    - Move down\n")
+	help.WriteString("pgup/pgdn - Previous/next page\n")
 	help.WriteString("enter  - Attach to selected session\n")
 	help.WriteString("l      - View logs for selected session\n")
 	help.WriteString("s      - Stop selected session\n")
-	help.WriteString("c      - Clean stale sessions\n")
+	help.WriteString("c      - Clean stale sessions (space to exclude one)\n")
+	help.WriteString("e      - Show error history\n")
 	help.WriteString("g      - Toggle global/repository view\n")
+	help.WriteString("G      - Cycle repository groups in the global view\n")
+	help.WriteString("D      - Toggle cross-repo dashboard\n")
 	help.WriteString("r      - Refresh session list\n")
+	help.WriteString(":      - Command palette (:clean, :start <id>, :filter repo=<name>, :sort activity, ...)\n")
 	help.WriteString("?      - Toggle this help\n")
 	help.WriteString("q      - Quit\n")
 	return helpStyle.Render(help.String())
 }
 
 func (m Model) getSessionStatus(session config.SessionMetadata) status.SessionStatus {
-	return m.statusDetector.DetectSessionStatus(session)
+	return m.statusDetector.DetectSessionStatusContext(m.baseContext(), session)
 }
 
 func (m Model) formatTimeAgo(timeStr string) string {
@@ -597,7 +1438,7 @@ func (m Model) formatTimeAgo(timeStr string) string {
 		return "unknown"
 	}
 
-	duration := time.Since(t)
+	duration := m.now().Sub(t)
 	if duration < time.Minute {
 		return "now"
 	} else if duration < time.Hour {
@@ -612,21 +1453,37 @@ func (m Model) formatTimeAgo(timeStr string) string {
 type refreshMsg struct {
 	sessions     []config.SessionMetadata
 	tmuxSessions []*tmux.Session
+	waiting      map[string]bool   // sessions waiting for input
+	renamed      map[string]string // sessions whose branch was renamed outside sbs
 	err          error
+	dashboard    bool // Refresh was requested for the dashboard (all repositories)
+	history      map[string][]status.Sample
+	diffstats    map[string]diffstat.Stat
+	behind       map[string]behind.Stat
 }
 
 type attachMsg struct {
-	err error
+	err     error
+	session string
 }
 
 type stopSessionMsg struct {
 	err     error
 	success bool
+	session string // namespaced ID of the stopped session
 }
 
 type cleanSessionsMsg struct {
 	err             error
+	cancelled       bool // the clean was stopped with esc; err is context.Canceled
 	cleanedSessions []config.SessionMetadata
+	failures        []error // per-resource failures, recorded in the error panel
+	busy            int     // sessions skipped because another sbs process was working on them
+}
+
+// cleanProgressMsg reports a session the running clean finished
+type cleanProgressMsg struct {
+	progress cleanup.Progress
 }
 
 type confirmationDialogMsg struct {
@@ -636,12 +1493,46 @@ type confirmationDialogMsg struct {
 
 type tickMsg struct{}
 
+// tmuxEventMsg delivers a tmux control-mode event to the TUI
+type tmuxEventMsg struct {
+	event  tmux.ControlEvent
+	closed bool
+}
+
+// Config hot-reload message types
+type configWatchStartedMsg struct {
+	watcher *config.Watcher
+}
+
+type configChangedMsg struct {
+	closed bool
+}
+
+type configReloadedMsg struct {
+	config *config.Config
+	err    error
+}
+
+type clearNoticeMsg struct {
+	notice string
+}
+
+// tmuxControlStartedMsg delivers the control-mode multiplexer once it is created
+type tmuxControlStartedMsg struct {
+	control *tmux.ControlMux
+	tracker *tmux.SessionTracker
+}
+
 // Log view message types
-type logRefreshTickMsg struct{}
+type logRefreshTickMsg struct {
+	tab        int // Index of the loghook tab to refresh
+	generation int // Model.logRefreshGeneration when the tick was scheduled
+}
 
 type logRefreshResultMsg struct {
 	content string
 	err     error
+	tab     int // Index of the loghook tab the content belongs to
 }
 
 type logRefreshErrorMsg struct {
@@ -667,13 +1558,55 @@ func (m Model) toggleViewMode() Model {
 	return m
 }
 
+// cycleRepoGroup scopes the global view to the next repository group, in
+// name order, and back to every repository after the last one
+func (m Model) cycleRepoGroup() Model {
+	names := m.config.RepositoryGroupNames()
+	next := ""
+	if len(names) > 0 {
+		next = names[0]
+	}
+	for i, name := range names {
+		if name == m.repoGroup {
+			next = ""
+			if i+1 < len(names) {
+				next = names[i+1]
+			}
+			break
+		}
+	}
+	m.repoGroup = next
+	if next != "" {
+		m.viewMode = ViewModeGlobal
+	}
+	m.cursor = 0
+	return m
+}
+
+// toggleDashboard enters the dashboard or returns to the view it was opened from
+func (m Model) toggleDashboard() Model {
+	if m.viewMode == ViewModeDashboard {
+		m.viewMode = m.dashboardReturnMode
+	} else {
+		m.dashboardReturnMode = m.viewMode
+		m.viewMode = ViewModeDashboard
+	}
+
+	m.cursor = 0
+	return m
+}
+
 func (m Model) refreshSessions() tea.Cmd {
+	dashboard := m.viewMode == ViewModeDashboard
 	return func() tea.Msg {
 		// Always load from global sessions file
-		allSessions, err := config.LoadAllRepositorySessions()
+		allSessions, err := m.loadSessions()
 		if err != nil {
 			return refreshMsg{err: err}
 		}
+		markMissingRepositories(allSessions)
+		_, _ = inbox.Collect(allSessions) // pushed statuses show up in this refresh
+		m.notifyExpiring(allSessions)
 
 		var sessions []config.SessionMetadata
 
@@ -684,6 +1617,9 @@ func (m Model) refreshSessions() tea.Cmd {
 					sessions = append(sessions, session)
 				}
 			}
+		} else if members, ok := m.config.RepositoryGroups[m.repoGroup]; ok {
+			// Global view scoped to a repository group
+			sessions = config.FilterRepositoryGroup(allSessions, members)
 		} else {
 			// Show all sessions (global view)
 			sessions = allSessions
@@ -693,18 +1629,98 @@ func (m Model) refreshSessions() tea.Cmd {
 		if err != nil {
 			return refreshMsg{err: err}
 		}
+		if m.tmuxControl != nil {
+			// Sessions whose client fails to attach fall back to polling
+			_ = m.tmuxControl.Watch(watchedTmuxSessions(sessions, tmuxSessions))
+		}
 
 		return refreshMsg{
 			sessions:     sessions,
 			tmuxSessions: tmuxSessions,
+			waiting:      m.waitingSessions(sessions),
+			renamed:      branchRenames(sessions),
+			dashboard:    dashboard,
+			history:      m.recordStatusHistory(sessions),
+			diffstats:    m.refreshDiffstats(sessions),
+			behind:       m.refreshBehind(sessions),
+		}
+	}
+}
+
+// markMissingRepositories marks sessions whose repository was deleted (or
+// restored) and saves the change so other commands see it, e.g. 'sbs clean
+// --repo-missing'. A failed save only loses the persisted mark; the status
+// detector reports the status either way.
+func markMissingRepositories(sessions []config.SessionMetadata) {
+	if !config.MarkMissingRepositories(sessions) {
+		return
+	}
+	_ = config.UpdateSessions(func(current []config.SessionMetadata) ([]config.SessionMetadata, error) {
+		config.MarkMissingRepositories(current)
+		return current, nil
+	})
+}
+
+// notifyExpiring announces sessions newly close to expiring, when
+// expiry_notify is on. Failed notifications are retried on the next refresh.
+func (m Model) notifyExpiring(sessions []config.SessionMetadata) {
+	if m.expiryNotifier == nil || !m.config.ExpiryNotify {
+		return
+	}
+	_, _ = m.expiryNotifier.Notify(sessions, expiry.FromConfig(m.config), m.now())
+}
+
+// refreshBehind returns how far session branches are behind their base, or
+// nil when no threshold is set. Only counts older than behind.RefreshInterval
+// run git.
+func (m Model) refreshBehind(sessions []config.SessionMetadata) map[string]behind.Stat {
+	if m.behindCache == nil || !behind.FromConfig(m.config).Enabled() {
+		return nil
+	}
+	stats, _ := m.behindCache.Refresh(sessions, m.now())
+	return stats
+}
+
+// showDiffstat reports whether the repository view shows the diffstat column
+func (m Model) showDiffstat() bool {
+	return m.config.ShowDiffstat && m.diffstatCache != nil && m.viewMode == ViewModeRepository
+}
+
+// refreshDiffstats returns the branch diffstats of sessions, or nil when the
+// column is hidden. Only stats older than diffstat.RefreshInterval run git.
+func (m Model) refreshDiffstats(sessions []config.SessionMetadata) map[string]diffstat.Stat {
+	if !m.showDiffstat() {
+		return nil
+	}
+	stats, _ := m.diffstatCache.Refresh(sessions, m.now())
+	return stats
+}
+
+// recordStatusHistory samples the status of each session into the history
+// store and returns the updated history, or nil when history is disabled.
+// Write failures only cost a sample, so the in-memory history is still used.
+func (m Model) recordStatusHistory(sessions []config.SessionMetadata) map[string][]status.Sample {
+	if m.historyStore == nil {
+		return nil
+	}
+	now := m.now()
+	statuses := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		if session.NamespacedID != "" && status.SampleDue(m.statusHistory[session.NamespacedID], now) {
+			statuses[session.NamespacedID] = m.getSessionStatus(session).Status
 		}
 	}
+	if len(statuses) == 0 {
+		return nil
+	}
+	history, _ := m.historyStore.Record(statuses, now)
+	return history
 }
 
 func (m Model) attachToSession(sessionName string) tea.Cmd {
 	return func() tea.Msg {
 		err := m.tmuxManager.AttachToSession(sessionName)
-		return attachMsg{err: err}
+		return attachMsg{err: err, session: sessionName}
 	}
 }
 
@@ -716,34 +1732,38 @@ func (m Model) stopSelectedSession() tea.Cmd {
 	}
 
 	session := m.sessions[m.cursor]
+	id := session.NamespacedID
+	if id == "" {
+		id = session.TmuxSession
+	}
 	return func() tea.Msg {
 		// Check if tmux session exists
 		exists, err := m.tmuxManager.SessionExists(session.TmuxSession)
 		if err != nil {
-			return stopSessionMsg{err: fmt.Errorf("failed to check tmux session: %w", err), success: false}
+			return stopSessionMsg{err: fmt.Errorf("failed to check tmux session: %w", err), success: false, session: id}
 		}
 
 		// Kill tmux session if it exists
 		if exists {
 			if err := m.tmuxManager.KillSession(session.TmuxSession); err != nil {
-				return stopSessionMsg{err: fmt.Errorf("failed to kill tmux session: %w", err), success: false}
+				return stopSessionMsg{err: fmt.Errorf("failed to kill tmux session: %w", err), success: false, session: id}
 			}
 		}
 
 		// Stop sandbox if it exists
 		sandboxName := session.SandboxName
 		if sandboxName == "" {
-			return stopSessionMsg{err: fmt.Errorf("session missing sandbox name"), success: false}
+			return stopSessionMsg{err: fmt.Errorf("session missing sandbox name"), success: false, session: id}
 		}
 
 		sandboxExists, err := m.sandboxManager.SandboxExists(sandboxName)
 		if err == nil && sandboxExists {
 			if err := m.sandboxManager.DeleteSandbox(sandboxName); err != nil {
-				return stopSessionMsg{err: fmt.Errorf("failed to delete sandbox: %w", err), success: false}
+				return stopSessionMsg{err: fmt.Errorf("failed to delete sandbox: %w", err), success: false, session: id}
 			}
 		}
 
-		return stopSessionMsg{err: nil, success: true}
+		return stopSessionMsg{err: nil, success: true, session: id}
 	}
 }
 
@@ -754,57 +1774,157 @@ func (m Model) showCleanConfirmation() Model {
 		viewMode = cleanup.ViewModeRepository
 	}
 
-	staleSessions, err := m.cleanupManager.IdentifyStaleSessionsInView(m.sessions, viewMode)
+	staleSessions, err := m.cleanupManager.ExplainStaleSessionsInView(m.baseContext(), m.sessions, viewMode)
 	if err != nil || len(staleSessions) == 0 {
 		return m
 	}
 
 	m.showConfirmationDialog = true
-	m.pendingCleanSessions = staleSessions
+	m.pendingCleanSessions = make([]config.SessionMetadata, 0, len(staleSessions))
+	items := make([]checklistItem, 0, len(staleSessions))
+	for _, stale := range staleSessions {
+		session := stale.Session
+		m.pendingCleanSessions = append(m.pendingCleanSessions, session)
 
-	var message strings.Builder
-	if len(staleSessions) == 1 {
-		message.WriteString("Clean 1 stale session?\n")
-	} else {
-		message.WriteString(fmt.Sprintf("Clean %d stale sessions?\n", len(staleSessions)))
+		label := fmt.Sprintf("Issue #%d: %s", session.IssueNumber, session.IssueTitle)
+		if session.NamespacedID != "" {
+			label = fmt.Sprintf("Work Item %s: %s", session.NamespacedID, session.IssueTitle)
+		}
+		items = append(items, checklistItem{label: label, detail: stale.Reason, checked: true})
 	}
+	m.cleanChecklist = newChecklist(items, m.cleanChecklistRows())
+	m.confirmationMessage = m.cleanConfirmationMessage()
+	return m
+}
 
-	for _, session := range staleSessions {
-		if session.NamespacedID != "" {
-			message.WriteString(fmt.Sprintf("Work Item %s: %s\n", session.NamespacedID, session.IssueTitle))
-		} else {
-			message.WriteString(fmt.Sprintf("Issue #%d: %s\n", session.IssueNumber, session.IssueTitle))
+// cleanChecklistRows is how many sessions the clean dialog lists at a time
+func (m Model) cleanChecklistRows() int {
+	if m.height <= 0 {
+		return 10
+	}
+	return max(3, m.height-12)
+}
+
+// cleanConfirmationMessage renders the clean dialog: the stale sessions as a
+// checklist with the reason each is stale
+func (m Model) cleanConfirmationMessage() string {
+	total := len(m.cleanChecklist.items)
+	var message strings.Builder
+	message.WriteString(messages.Render(messages.TUICleanConfirm, messages.Args{"Count": total}) + "\n")
+	message.WriteString(m.cleanChecklist.view())
+	message.WriteString(mutedStyle.Render(fmt.Sprintf("\n%d of %d selected · ↑/↓ move · space toggle · a all", m.cleanChecklist.checkedCount(), total)))
+	message.WriteString("\n" + messages.Render(messages.TUICleanHint, nil))
+	return message.String()
+}
+
+// confirmClean cleans the sessions ticked in the clean dialog
+func (m Model) confirmClean() (Model, tea.Cmd) {
+	m.showConfirmationDialog = false
+	if m.cleanChecklist != nil {
+		var selected []config.SessionMetadata
+		for _, i := range m.cleanChecklist.checked() {
+			selected = append(selected, m.pendingCleanSessions[i])
+		}
+		m.cleanChecklist = nil
+		m.pendingCleanSessions = selected
+		if len(selected) == 0 {
+			m.confirmationMessage = ""
+			return m.toast(messages.Render(messages.TUICleanNothing, nil))
 		}
 	}
-	message.WriteString("\n(y/n) Press y to confirm, n to cancel")
+	return m.executeCleanup()
+}
 
-	m.confirmationMessage = message.String()
+// cancelClean closes the clean dialog without cleaning anything
+func (m Model) cancelClean() Model {
+	m.showConfirmationDialog = false
+	m.confirmationMessage = ""
+	m.pendingCleanSessions = []config.SessionMetadata{}
+	m.cleanChecklist = nil
 	return m
 }
 
-func (m Model) executeCleanup() tea.Cmd {
+// executeCleanup starts cleaning the pending sessions in the background; the
+// running clean reports each session as it finishes
+func (m Model) executeCleanup() (Model, tea.Cmd) {
+	// Convert TUI ViewMode to cleanup ViewMode
+	viewMode := cleanup.ViewModeGlobal
+	if m.viewMode == ViewModeRepository {
+		viewMode = cleanup.ViewModeRepository
+	}
+
+	// Group this cleanup's commands in the command log
+	ctx := cmdlog.WithCorrelationID(m.baseContext(), cmdlog.NewCorrelationID())
+	options := m.cleanupManager.BuildTUICleanupOptions(viewMode, true)
+	m.cleanBatch = m.cleanupManager.StartBatch(ctx, m.pendingCleanSessions, options)
+	m.cleanProgress = nil
+	m.cleanResults = nil
+	return m, m.waitForCleanProgress()
+}
+
+// waitForCleanProgress waits for the running clean's next finished session,
+// or its end
+func (m Model) waitForCleanProgress() tea.Cmd {
+	batch := m.cleanBatch
+	if batch == nil {
+		return nil
+	}
 	sessions := m.pendingCleanSessions
 	return func() tea.Msg {
-		// Convert TUI ViewMode to cleanup ViewMode
-		viewMode := cleanup.ViewModeGlobal
-		if m.viewMode == ViewModeRepository {
-			viewMode = cleanup.ViewModeRepository
+		if progress, ok := <-batch.Progress(); ok {
+			return cleanProgressMsg{progress: progress}
 		}
-
-		options := m.cleanupManager.BuildTUICleanupOptions(viewMode, true)
-		results, err := m.cleanupManager.CleanupSessions(sessions, options)
+		results, err := batch.Wait()
 
 		var cleanupError error
 		if err != nil {
 			cleanupError = err
 		} else if len(results.Errors) > 0 {
-			cleanupError = fmt.Errorf("failed to clean some sessions")
+			cleanupError = fmt.Errorf("failed to clean some sessions (%d error(s))", len(results.Errors))
+		}
+
+		removed := make(map[string]bool)
+		for _, id := range results.Removed() {
+			removed[id] = true
+		}
+		var cleaned []config.SessionMetadata
+		for _, session := range sessions {
+			if removed[session.NamespacedID] {
+				cleaned = append(cleaned, session)
+			}
 		}
 
 		return cleanSessionsMsg{
 			err:             cleanupError,
-			cleanedSessions: sessions[:results.CleanedSessions], // Return the sessions that were cleaned
+			cancelled:       errors.Is(err, context.Canceled),
+			cleanedSessions: cleaned,
+			failures:        results.Errors,
+			busy:            len(results.Busy()),
+		}
+	}
+}
+
+// quit detaches the tmux control-mode clients and exits the program
+func (m Model) quit() tea.Cmd {
+	if m.tmuxControl != nil {
+		m.tmuxControl.Stop()
+	}
+	return tea.Quit
+}
+
+// waitForTmuxEvent creates a command that waits for the next tmux control-mode event
+func (m Model) waitForTmuxEvent() tea.Cmd {
+	if m.tmuxEvents == nil {
+		return nil
+	}
+
+	events := m.tmuxEvents
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return tmuxEventMsg{closed: true}
 		}
+		return tmuxEventMsg{event: event}
 	}
 }
 
@@ -836,11 +1956,16 @@ func (m Model) renderLogView() string {
 	}
 	b.WriteString(titleStyle.Render(sessionTitle) + "\n\n")
 
+	// Tab bar for named loghooks
+	if m.logView != nil && len(m.logView.tabs) > 0 {
+		b.WriteString(m.renderLogTabs() + "\n\n")
+	}
+
 	// Log content area
 	if m.logView == nil {
 		b.WriteString(mutedStyle.Render("No log view initialized") + "\n")
 	} else if m.logView.loading {
-		b.WriteString(mutedStyle.Render("Loading log content...") + "\n")
+		b.WriteString(newProgressView("Loading log content").render(m.now(), m.width) + "\n")
 	} else if m.logView.errorMessage != "" {
 		b.WriteString(errorStyle.Render("Error: "+m.logView.errorMessage) + "\n")
 		if !m.logAutoRefreshActive {
@@ -851,8 +1976,8 @@ func (m Model) renderLogView() string {
 		b.WriteString(mutedStyle.Render("No log content available") + "\n")
 	} else {
 		// Display log content with scrolling
-		lines := strings.Split(m.logView.content, "\n")
-		displayHeight := m.height - 6 // Reserve space for title and help text
+		lines := m.logDisplayLines()
+		displayHeight := m.logDisplayHeight()
 
 		startLine := m.logView.scrollOffset
 		endLine := startLine + displayHeight
@@ -864,14 +1989,22 @@ func (m Model) renderLogView() string {
 		if startLine < len(lines) {
 			visibleLines := lines[startLine:endLine]
 			for _, line := range visibleLines {
-				b.WriteString(line + "\n")
+				b.WriteString(m.logHighlighter.Highlight(line) + "\n")
 			}
 		}
 
 		// Show scroll indicators
+		var scrollParts []string
 		if len(lines) > displayHeight {
-			scrollInfo := fmt.Sprintf("Lines %d-%d of %d", startLine+1, endLine, len(lines))
-			b.WriteString("\n" + mutedStyle.Render(scrollInfo))
+			scrollParts = append(scrollParts, fmt.Sprintf("Lines %d-%d of %d", startLine+1, endLine, len(lines)))
+		}
+		if m.logView.wrap {
+			scrollParts = append(scrollParts, "Wrap: on")
+		} else if m.logView.horizontalOffset > 0 {
+			scrollParts = append(scrollParts, fmt.Sprintf("Col %d", m.logView.horizontalOffset+1))
+		}
+		if len(scrollParts) > 0 {
+			b.WriteString("\n" + mutedStyle.Render(strings.Join(scrollParts, " | ")))
 		}
 	}
 
@@ -890,7 +2023,10 @@ func (m Model) renderLogView() string {
 	}
 
 	// Help text for log view
-	helpText := "\nPress ↑/↓: scroll, r: refresh, ESC/q: exit"
+	helpText := "\nPress ↑/↓: scroll, ←/→: pan, w: wrap, r: refresh, ESC/q: exit"
+	if m.logView != nil && len(m.logView.tabs) > 1 {
+		helpText = fmt.Sprintf("\nPress 1-%d: switch log, ↑/↓: scroll, ←/→: pan, w: wrap, r: refresh, ESC/q: exit", len(m.logView.tabs))
+	}
 	b.WriteString(helpStyle.Render(helpText))
 
 	content := lipgloss.NewStyle().
@@ -901,6 +2037,143 @@ func (m Model) renderLogView() string {
 	return content
 }
 
+// logDisplayHeight returns the number of log lines that fit on screen
+func (m Model) logDisplayHeight() int {
+	height := m.height - 6 // Reserve space for title and help text
+	if m.logView != nil && len(m.logView.tabs) > 0 {
+		height -= 2 // Tab bar
+	}
+	return height
+}
+
+// renderLogTabs renders the loghook tab bar with the active tab highlighted
+func (m Model) renderLogTabs() string {
+	labels := make([]string, len(m.logView.tabs))
+	for i, tab := range m.logView.tabs {
+		label := fmt.Sprintf("%d %s", i+1, tab.source.Name)
+		if i == m.logView.activeTab {
+			labels[i] = selectedItemStyle.Render(label)
+		} else {
+			labels[i] = normalItemStyle.Inherit(mutedStyle).Render(label)
+		}
+	}
+	return strings.Join(labels, " ")
+}
+
+// loadLogSettings reads the session's .sbs/loghook.json and sets up the log
+// view's tabs, refresh interval and output limit. An unreadable file is
+// reported and the configured defaults are used.
+func (m Model) loadLogSettings(session config.SessionMetadata) Model {
+	settings, err := loghook.LoadSettings(session.WorktreePath)
+	if err != nil {
+		m = m.reportError(err, session.NamespacedID)
+	}
+	worktreeConfig, err := config.LoadWorktreeConfig(session.RepositoryRoot, session.WorktreePath)
+	if err != nil {
+		m = m.reportError(err, session.NamespacedID)
+	}
+	m.logView.settings = settings
+	m.logView.worktreeConfig = worktreeConfig
+	m.logView.tabs = m.buildLogTabs(session, settings)
+	m.applyLogSettings()
+	return m
+}
+
+// applyLogSettings bounds the worktree's requested refresh intervals and output
+// size by the configured limits
+func (m Model) applyLogSettings() {
+	if m.logView == nil {
+		return
+	}
+	cfg := m.logConfig()
+	limits := loghook.LimitsFromConfig(cfg)
+	m.logView.interval = m.logView.settings.Interval(loghook.DefaultSourceName, limits)
+	m.logView.maxSizeBytes = m.logView.settings.OutputLimit(limits)
+	for i := range m.logView.tabs {
+		m.logView.tabs[i].interval = loghookTabInterval(cfg, m.logView.settings, m.logView.tabs[i].source.Name)
+	}
+}
+
+// buildLogTabs creates a tab per loghook source when named loghooks exist.
+// A lone .sbs/loghook keeps the single-view behavior, including the tmux fallback.
+func (m Model) buildLogTabs(session config.SessionMetadata, settings loghook.Settings) []logTab {
+	sources, _ := loghook.DiscoverSources(session.WorktreePath)
+	if len(sources) == 0 || (len(sources) == 1 && sources[0].Name == loghook.DefaultSourceName) {
+		return nil
+	}
+
+	tabs := make([]logTab, len(sources))
+	for i, source := range sources {
+		tabs[i] = logTab{source: source, interval: loghookTabInterval(m.logConfig(), settings, source.Name)}
+	}
+	return tabs
+}
+
+// logConfig returns the config for the open log view: the running config
+// with the session worktree's .sbs/config.json overrides on top
+func (m Model) logConfig() *config.Config {
+	if m.logView == nil || m.logView.worktreeConfig == nil || m.config == nil {
+		return m.config
+	}
+	return config.MergeConfig(m.config, m.logView.worktreeConfig)
+}
+
+// loghookTabInterval returns the refresh interval for a named loghook: the
+// worktree's .sbs/loghook.json first, then the config (zero uses the global interval)
+func loghookTabInterval(cfg *config.Config, settings loghook.Settings, name string) time.Duration {
+	if interval := settings.Interval(name, loghook.LimitsFromConfig(cfg)); interval > 0 {
+		return interval
+	}
+	if cfg == nil {
+		return 0
+	}
+	if secs, exists := cfg.LoghookIntervals[name]; exists && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// logDisplayLines splits the log content into screen lines, either wrapped to the
+// terminal width or cut to the visible horizontal window
+func (m Model) logDisplayLines() []string {
+	if m.logView == nil {
+		return nil
+	}
+
+	lines := strings.Split(m.logView.content, "\n")
+	if m.width <= 0 {
+		return lines
+	}
+
+	display := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if m.logView.wrap {
+			display = append(display, strings.Split(ansi.Hardwrap(line, m.width, true), "\n")...)
+		} else {
+			display = append(display, ansi.Cut(line, m.logView.horizontalOffset, m.logView.horizontalOffset+m.width))
+		}
+	}
+	return display
+}
+
+// maxLogScrollOffset returns the largest vertical scroll offset for the current layout
+func (m Model) maxLogScrollOffset() int {
+	return maxInt(0, len(m.logDisplayLines())-m.logDisplayHeight())
+}
+
+// maxLogHorizontalOffset returns the largest horizontal offset that still shows part of the widest line
+func (m Model) maxLogHorizontalOffset() int {
+	if m.logView == nil || m.width <= 0 {
+		return 0
+	}
+
+	widest := 0
+	for _, line := range strings.Split(m.logView.content, "\n") {
+		widest = maxInt(widest, ansi.StringWidth(line))
+	}
+	return maxInt(0, widest-m.width)
+}
+
 // Log view helper functions
 
 // LogExecutionInfo contains information about script execution for audit logging
@@ -944,32 +2217,7 @@ func validateLoghookPath(worktreePath string) (string, error) {
 
 // validateScriptSecurity performs security checks on the loghook script
 func validateScriptSecurity(scriptPath string) error {
-	// Get file info
-	info, err := os.Stat(scriptPath)
-	if err != nil {
-		return fmt.Errorf("failed to stat script at %s: %w", scriptPath, err)
-	}
-
-	// Check if it's a regular file (not a symlink, device, etc.)
-	if !info.Mode().IsRegular() {
-		return fmt.Errorf("script at %s is not a regular file", scriptPath)
-	}
-
-	// Check if script is executable
-	if info.Mode().Perm()&0111 == 0 {
-		return fmt.Errorf("permission denied: script at %s is not executable", scriptPath)
-	}
-
-	// Check file ownership (should be owned by current user for security)
-	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
-		currentUID := os.Getuid()
-		if int(stat.Uid) != currentUID {
-			log.Printf("Warning: loghook script at %s is not owned by current user (uid=%d, script_uid=%d)",
-				scriptPath, currentUID, stat.Uid)
-		}
-	}
-
-	return nil
+	return loghook.ValidateScript(scriptPath)
 }
 
 // logScriptExecution logs script execution for audit trails
@@ -985,7 +2233,7 @@ func logScriptExecution(info LogExecutionInfo) {
 
 // executeLoghookScript executes the .sbs/loghook script with default timeout (10 seconds)
 func executeLoghookScript(session config.SessionMetadata) (string, error) {
-	return executeLoghookScriptWithOptions(session, 10, 1048576) // 10s timeout, 1MB limit
+	return executeLoghookScriptWithOptions(context.Background(), session, 10, 1048576) // 10s timeout, 1MB limit
 }
 
 // ExecuteLoghookScript executes the loghook script for a session (exported wrapper)
@@ -993,13 +2241,14 @@ func ExecuteLoghookScript(session config.SessionMetadata) (string, error) {
 	return executeLoghookScript(session)
 }
 
-// executeLoghookScriptWithTimeout executes the loghook script with a custom timeout
-func executeLoghookScriptWithTimeout(session config.SessionMetadata, timeoutSecs int) (string, error) {
-	return executeLoghookScriptWithOptions(session, timeoutSecs, 1048576) // Custom timeout, 1MB limit
+// executeLoghookScriptWithTimeout executes the loghook script with a custom timeout,
+// stopping early if ctx is cancelled
+func executeLoghookScriptWithTimeout(ctx context.Context, session config.SessionMetadata, timeoutSecs int) (string, error) {
+	return executeLoghookScriptWithOptions(ctx, session, timeoutSecs, 1048576) // Custom timeout, 1MB limit
 }
 
 // executeLoghookScriptWithOptions executes the loghook script with full customization
-func executeLoghookScriptWithOptions(session config.SessionMetadata, timeoutSecs int, maxOutputBytes int) (string, error) {
+func executeLoghookScriptWithOptions(ctx context.Context, session config.SessionMetadata, timeoutSecs int, maxOutputBytes int) (string, error) {
 	startTime := time.Now()
 	var execInfo LogExecutionInfo
 
@@ -1034,7 +2283,7 @@ func executeLoghookScriptWithOptions(session config.SessionMetadata, timeoutSecs
 		logScriptExecution(execInfo)
 
 		// Try to capture tmux pane content as fallback
-		tmuxManager := tmux.NewManager()
+		tmuxManager := tmux.NewManager().WithContext(ctx)
 		paneContent, captureErr := tmuxManager.CapturePane(session.TmuxSession)
 		if captureErr != nil {
 			return "", fmt.Errorf("loghook script not found at %s and failed to capture tmux pane: %w", loghookPath, captureErr)
@@ -1045,6 +2294,18 @@ func executeLoghookScriptWithOptions(session config.SessionMetadata, timeoutSecs
 		return output, nil
 	}
 
+	return runLoghookScript(ctx, session, loghookPath, timeoutSecs, maxOutputBytes)
+}
+
+// runLoghookScript validates and executes a specific loghook script in snapshot mode
+func runLoghookScript(parent context.Context, session config.SessionMetadata, loghookPath string, timeoutSecs int, maxOutputBytes int) (string, error) {
+	startTime := time.Now()
+	execInfo := LogExecutionInfo{
+		ScriptPath:    loghookPath,
+		WorkingDir:    session.WorktreePath,
+		ExecutionTime: startTime,
+	}
+
 	// Perform security validation
 	if err := validateScriptSecurity(loghookPath); err != nil {
 		execInfo.Error = err.Error()
@@ -1053,11 +2314,17 @@ func executeLoghookScriptWithOptions(session config.SessionMetadata, timeoutSecs
 	}
 
 	// Execute script with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSecs)*time.Second)
+	ctx, cancel := context.WithTimeout(parent, time.Duration(timeoutSecs)*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, loghookPath)
-	cmd.Dir = session.WorktreePath // Set working directory to worktree
+	// Build the invocation following the loghook contract (mode argument plus SBS_* environment)
+	invocation, err := loghook.NewInvocation(session, loghookPath, loghook.ModeSnapshot, loghook.ResolveArgs(nil, session.RepositoryRoot, session.WorktreePath))
+	if err != nil {
+		execInfo.Error = err.Error()
+		logScriptExecution(execInfo)
+		return "", fmt.Errorf("invalid loghook invocation for %s: %w", loghookPath, err)
+	}
+	cmd := invocation.Command(ctx) // Runs from the worktree directory
 
 	// Capture output with size limits to prevent memory exhaustion
 	stdout, err := cmd.StdoutPipe()
@@ -1184,33 +2451,66 @@ func executeLoghookScriptWithOptions(session config.SessionMetadata, timeoutSecs
 	return output.String(), nil
 }
 
-// getLogRefreshInterval returns the configured log refresh interval with bounds checking
+// getLogRefreshInterval returns the refresh interval of the displayed log
 func (m Model) getLogRefreshInterval() time.Duration {
-	intervalSecs := m.config.LogRefreshIntervalSecs
+	if m.logView == nil {
+		return m.logTabRefreshInterval(0)
+	}
+	return m.logTabRefreshInterval(m.logView.activeTab)
+}
+
+// logTabRefreshInterval returns the configured refresh interval of a loghook
+// tab with bounds checking
+func (m Model) logTabRefreshInterval(tab int) time.Duration {
+	// Named loghooks may have their own cadence
+	if m.logView != nil && tab >= 0 && tab < len(m.logView.tabs) {
+		if interval := m.logView.tabs[tab].interval; interval > 0 {
+			return interval
+		}
+	}
+
+	// The worktree's .sbs/loghook.json may ask for its own cadence (already bounded)
+	if m.logView != nil && m.logView.interval > 0 {
+		return m.logView.interval
+	}
+
+	cfg := m.logConfig()
+	intervalSecs := cfg.LogRefreshIntervalSecs
 	if intervalSecs == 0 {
 		intervalSecs = 5 // Default to 5 seconds
 	}
 
-	// Enforce bounds (2-120 seconds)
-	if intervalSecs < 2 {
-		intervalSecs = 2
+	// Enforce the configured bounds (2-120 seconds by default)
+	return loghook.LimitsFromConfig(cfg).ClampInterval(time.Duration(intervalSecs) * time.Second)
+}
+
+// startLogAutoRefresh starts the auto-refresh mechanism for log view. Each
+// loghook tab refreshes on its own interval, so background tabs stay current.
+func (m Model) startLogAutoRefresh() tea.Cmd {
+	if m.viewMode != ViewModeLog {
+		return nil
 	}
-	if intervalSecs > 120 {
-		intervalSecs = 120
+	if m.logView == nil || len(m.logView.tabs) == 0 {
+		return m.scheduleLogRefresh(0)
 	}
 
-	return time.Duration(intervalSecs) * time.Second
+	cmds := make([]tea.Cmd, len(m.logView.tabs))
+	for i := range m.logView.tabs {
+		cmds[i] = m.scheduleLogRefresh(i)
+	}
+	return tea.Batch(cmds...)
 }
 
-// startLogAutoRefresh starts the auto-refresh mechanism for log view
-func (m Model) startLogAutoRefresh() tea.Cmd {
+// scheduleLogRefresh schedules the next auto-refresh of a loghook tab
+func (m Model) scheduleLogRefresh(tab int) tea.Cmd {
 	if m.viewMode != ViewModeLog {
 		return nil
 	}
 
-	interval := m.getLogRefreshInterval()
+	interval := m.logTabRefreshInterval(tab)
+	generation := m.logRefreshGeneration
 	return tea.Tick(interval, func(t time.Time) tea.Msg {
-		return logRefreshTickMsg{}
+		return logRefreshTickMsg{tab: tab, generation: generation}
 	})
 }
 
@@ -1219,24 +2519,55 @@ func (m *Model) stopLogAutoRefresh() {
 	m.logAutoRefreshActive = false
 }
 
-// refreshLogContent refreshes the log content for the current session
+// refreshLogContent refreshes the displayed log content for the current session
 func (m Model) refreshLogContent() tea.Cmd {
+	if m.logView == nil {
+		return m.refreshLogTab(0)
+	}
+	return m.refreshLogTab(m.logView.activeTab)
+}
+
+// refreshLogTab refreshes the log content of a loghook tab for the current session
+func (m Model) refreshLogTab(index int) tea.Cmd {
 	if m.viewMode != ViewModeLog || len(m.sessions) == 0 || m.cursor < 0 || m.cursor >= len(m.sessions) {
 		return nil
 	}
 
 	session := m.sessions[m.cursor]
-	return func() tea.Msg {
-		// Use the refactored function with timeout from config or default
-		timeoutSecs := 10 // default timeout
-		if m.config != nil && m.config.StatusTimeoutSeconds > 0 {
-			timeoutSecs = m.config.StatusTimeoutSeconds
+
+	// Named loghook tabs run their own script
+	tab := 0
+	scriptPath := ""
+	sourceName := loghook.DefaultSourceName
+	maxOutputBytes := loghook.DefaultMaxOutputBytes
+	if m.logView != nil {
+		if index >= 0 && index < len(m.logView.tabs) {
+			tab = index
+			scriptPath = m.logView.tabs[tab].source.Path
+			sourceName = m.logView.tabs[tab].source.Name
+		}
+		if m.logView.maxSizeBytes > 0 {
+			maxOutputBytes = m.logView.maxSizeBytes
 		}
+	}
 
-		content, err := executeLoghookScriptWithTimeout(session, timeoutSecs)
+	return func() tea.Msg {
+		// The session's repository may override the status timeout
+		timeoutSecs := int(m.statusTimeouts.Timeout(session.RepositoryRoot) / time.Second)
+
+		var content string
+		var err error
+		if scriptPath != "" {
+			content, err = runLoghookScript(m.baseContext(), session, scriptPath, timeoutSecs, maxOutputBytes)
+		} else {
+			content, err = executeLoghookScriptWithOptions(m.baseContext(), session, timeoutSecs, maxOutputBytes)
+		}
+		// Archiving is best effort; output of a failed run is kept as evidence
+		m.logArchive.Snapshot(session.NamespacedID, sourceName, content, m.now())
 		return logRefreshResultMsg{
 			content: content,
 			err:     err,
+			tab:     tab,
 		}
 	}
 }
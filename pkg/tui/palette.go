@@ -0,0 +1,673 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/config"
+	"sbs/pkg/paths"
+)
+
+// PaletteHistoryFile keeps the commands run from the : palette, in the state
+// directory
+const PaletteHistoryFile = "palette-history"
+
+// paletteHistoryLimit is how many commands the palette history keeps
+const paletteHistoryLimit = 100
+
+// paletteCommand is a command the : palette accepts
+type paletteCommand struct {
+	name  string
+	usage string
+	desc  string
+}
+
+var paletteCommands = []paletteCommand{
+	{"attach", "attach <id>", "attach to a session"},
+	{"clean", "clean [stale]", "clean stale sessions"},
+	{"dashboard", "dashboard", "toggle the cross-repo dashboard"},
+	{"errors", "errors", "show error history"},
+	{"filter", "filter [repo=|source=|status=|branch=<value>] [text]", "show matching sessions; no arguments clears the filter"},
+	{"help", "help", "toggle help"},
+	{"quit", "quit", "quit"},
+	{"refresh", "refresh", "refresh the session list"},
+	{"sort", "sort activity|created|id|title|repo|status", "order the session list"},
+	{"start", "start <id>", "start or attach to a work item's session with sbs start"},
+	{"stop", "stop [id]", "stop a session, the selected one by default"},
+	{"view", "view global|repo", "show all sessions or the current repository's"},
+}
+
+// filterKeys are the fields :filter matches with key=value terms
+var filterKeys = []string{"repo", "source", "status", "branch"}
+
+// sortKeys are the orders :sort accepts
+var sortKeys = []string{"activity", "created", "id", "title", "repo", "status"}
+
+// commandPalette is the : prompt, with tab completion and the history of
+// previous commands (up/down)
+type commandPalette struct {
+	input       textinput.Model
+	history     []string // Oldest first
+	browse      int      // Index into history while browsing; len(history) when not
+	draft       string   // Input typed before browsing the history
+	completions []string // Candidates for the word being completed
+	completed   int      // Index of the candidate last inserted
+	completing  string   // Input the candidates were computed for, up to the completed word
+}
+
+func newCommandPalette(history []string) *commandPalette {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "command (tab completes)"
+	input.Focus()
+	return &commandPalette{input: input, history: history, browse: len(history)}
+}
+
+// handleKey updates the prompt for navigation, completion and typing keys
+func (p *commandPalette) handleKey(msg tea.KeyMsg, sessions []config.SessionMetadata) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyTab:
+		p.complete(sessions)
+		return nil
+	case tea.KeyUp:
+		if p.browse > 0 {
+			if p.browse == len(p.history) {
+				p.draft = p.input.Value()
+			}
+			p.browse--
+			p.setValue(p.history[p.browse])
+		}
+		return nil
+	case tea.KeyDown:
+		if p.browse < len(p.history) {
+			p.browse++
+			if p.browse == len(p.history) {
+				p.setValue(p.draft)
+			} else {
+				p.setValue(p.history[p.browse])
+			}
+		}
+		return nil
+	}
+	p.completions = nil
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+func (p *commandPalette) setValue(value string) {
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	p.completions = nil
+}
+
+// complete completes the word before the cursor. A single candidate is
+// inserted; with several, repeated tabs cycle through them.
+func (p *commandPalette) complete(sessions []config.SessionMetadata) {
+	if len(p.completions) > 0 {
+		p.completed = (p.completed + 1) % len(p.completions)
+		p.insertCompletion()
+		return
+	}
+	value := p.input.Value()
+	candidates := paletteCompletions(value, sessions)
+	if len(candidates) == 0 {
+		return
+	}
+	cut := strings.LastIndex(value, " ") + 1
+	p.completing = value[:cut]
+	p.completions = candidates
+	p.completed = 0
+	p.insertCompletion()
+	if len(candidates) == 1 {
+		if !strings.HasSuffix(candidates[0], "=") {
+			p.input.SetValue(p.input.Value() + " ")
+			p.input.CursorEnd()
+		}
+		p.completions = nil
+	}
+}
+
+func (p *commandPalette) insertCompletion() {
+	p.input.SetValue(p.completing + p.completions[p.completed])
+	p.input.CursorEnd()
+}
+
+// view renders the prompt, the completion candidates and the usage of the
+// command being typed
+func (p *commandPalette) view(width int) string {
+	lines := []string{p.input.View()}
+	if len(p.completions) > 1 {
+		var b strings.Builder
+		for i, candidate := range p.completions {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			if i == p.completed {
+				b.WriteString(selectedRowStyle.Render(candidate))
+			} else {
+				b.WriteString(candidate)
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	name, _, _ := strings.Cut(strings.TrimSpace(p.input.Value()), " ")
+	if command, ok := lookupPaletteCommand(name); ok && name != "" {
+		lines = append(lines, mutedStyle.Render(TruncateString(command.usage+" - "+command.desc, max(width, 20))))
+	} else {
+		lines = append(lines, mutedStyle.Render("enter: run • tab: complete • ↑/↓: history • esc: cancel"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// record adds a command to the history, moving a repeated one to the end
+func (p *commandPalette) record(line string) {
+	history := make([]string, 0, len(p.history)+1)
+	for _, previous := range p.history {
+		if previous != line {
+			history = append(history, previous)
+		}
+	}
+	history = append(history, line)
+	if len(history) > paletteHistoryLimit {
+		history = history[len(history)-paletteHistoryLimit:]
+	}
+	p.history = history
+	p.browse = len(history)
+}
+
+// lookupPaletteCommand finds a command by name or unique prefix, as vim does
+func lookupPaletteCommand(name string) (paletteCommand, bool) {
+	var match paletteCommand
+	matches := 0
+	for _, command := range paletteCommands {
+		if command.name == name {
+			return command, true
+		}
+		if name != "" && strings.HasPrefix(command.name, name) {
+			match = command
+			matches++
+		}
+	}
+	return match, matches == 1
+}
+
+// paletteCompletions returns the candidates for the last word of input:
+// command names for the first word, then the arguments of the command
+func paletteCompletions(input string, sessions []config.SessionMetadata) []string {
+	words := strings.Fields(input)
+	if strings.HasSuffix(input, " ") || len(words) == 0 {
+		words = append(words, "")
+	}
+	word := words[len(words)-1]
+	if len(words) == 1 {
+		var names []string
+		for _, command := range paletteCommands {
+			names = append(names, command.name)
+		}
+		return withPrefix(names, word)
+	}
+
+	command, ok := lookupPaletteCommand(words[0])
+	if !ok {
+		return nil
+	}
+	switch command.name {
+	case "attach", "start", "stop":
+		if len(words) > 2 {
+			return nil
+		}
+		ids := make([]string, 0, len(sessions))
+		for _, session := range sessions {
+			ids = append(ids, session.NamespacedID)
+		}
+		return withPrefix(ids, word)
+	case "clean":
+		return withPrefix([]string{"stale"}, word)
+	case "sort":
+		return withPrefix(sortKeys, word)
+	case "view":
+		return withPrefix([]string{"global", "repo"}, word)
+	case "filter":
+		key, value, found := strings.Cut(word, "=")
+		if !found {
+			var terms []string
+			for _, key := range filterKeys {
+				terms = append(terms, key+"=")
+			}
+			return withPrefix(terms, word)
+		}
+		var values []string
+		for _, session := range sessions {
+			values = append(values, key+"="+filterField(session, key))
+		}
+		return withPrefix(values, key+"="+value)
+	}
+	return nil
+}
+
+// withPrefix returns the distinct non-empty candidates starting with prefix, sorted
+func withPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, candidate := range candidates {
+		if candidate != "" && strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// sessionFilter is the :filter in effect: key=value terms that must all
+// match, and free text matched against the ID and title
+type sessionFilter struct {
+	terms map[string]string
+	text  []string
+}
+
+// parseSessionFilter parses :filter arguments
+func parseSessionFilter(args []string) (sessionFilter, error) {
+	filter := sessionFilter{terms: make(map[string]string)}
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			filter.text = append(filter.text, strings.ToLower(arg))
+			continue
+		}
+		if !containsString(filterKeys, key) {
+			return sessionFilter{}, fmt.Errorf("unknown filter %s (expected %s)", key, strings.Join(filterKeys, ", "))
+		}
+		if value == "" {
+			return sessionFilter{}, fmt.Errorf("filter %s needs a value", key)
+		}
+		filter.terms[key] = strings.ToLower(value)
+	}
+	return filter, nil
+}
+
+// empty reports whether the filter lets every session through
+func (f sessionFilter) empty() bool {
+	return len(f.terms) == 0 && len(f.text) == 0
+}
+
+// String renders the filter as :filter arguments
+func (f sessionFilter) String() string {
+	var parts []string
+	for _, key := range filterKeys {
+		if value, ok := f.terms[key]; ok {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	return strings.Join(append(parts, f.text...), " ")
+}
+
+// matches reports whether a session passes the filter. Repository and
+// branch match by substring, source and status exactly.
+func (f sessionFilter) matches(session config.SessionMetadata) bool {
+	for key, value := range f.terms {
+		field := strings.ToLower(filterField(session, key))
+		switch key {
+		case "repo", "branch":
+			if !strings.Contains(field, value) {
+				return false
+			}
+		default:
+			if field != value {
+				return false
+			}
+		}
+	}
+	haystack := strings.ToLower(session.NamespacedID + " " + session.IssueTitle + " " + session.FriendlyTitle)
+	for _, text := range f.text {
+		if !strings.Contains(haystack, text) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterField returns the session field a filter key matches
+func filterField(session config.SessionMetadata, key string) string {
+	switch key {
+	case "repo":
+		return session.RepositoryName
+	case "source":
+		return session.SourceType
+	case "status":
+		return session.Status
+	case "branch":
+		return session.Branch
+	}
+	return ""
+}
+
+// arrangeSessions applies the filter and sort order to the listed sessions
+func arrangeSessions(sessions []config.SessionMetadata, filter sessionFilter, sortKey string) []config.SessionMetadata {
+	arranged := make([]config.SessionMetadata, 0, len(sessions))
+	for _, session := range sessions {
+		if filter.matches(session) {
+			arranged = append(arranged, session)
+		}
+	}
+	less := sessionLess(sortKey)
+	if less != nil {
+		sort.SliceStable(arranged, func(i, j int) bool { return less(arranged[i], arranged[j]) })
+	}
+	return arranged
+}
+
+// sessionLess orders sessions for a :sort key; nil keeps the listed order
+func sessionLess(sortKey string) func(a, b config.SessionMetadata) bool {
+	switch sortKey {
+	case "activity":
+		return func(a, b config.SessionMetadata) bool { return a.LastUsed().After(b.LastUsed()) }
+	case "created":
+		return func(a, b config.SessionMetadata) bool { return parseTime(a.CreatedAt).After(parseTime(b.CreatedAt)) }
+	case "id":
+		return func(a, b config.SessionMetadata) bool { return a.NamespacedID < b.NamespacedID }
+	case "title":
+		return func(a, b config.SessionMetadata) bool {
+			return strings.ToLower(a.IssueTitle) < strings.ToLower(b.IssueTitle)
+		}
+	case "repo":
+		return func(a, b config.SessionMetadata) bool { return a.RepositoryName < b.RepositoryName }
+	case "status":
+		return func(a, b config.SessionMetadata) bool { return a.Status < b.Status }
+	}
+	return nil
+}
+
+func parseTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPaletteHistoryPath returns PaletteHistoryFile in the state
+// directory, or "" when it can't be resolved
+func defaultPaletteHistoryPath() string {
+	path, err := paths.StatePath(PaletteHistoryFile)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadPaletteHistory reads the palette history, one command per line. A
+// missing or unreadable file starts an empty history.
+func loadPaletteHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var history []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			history = append(history, line)
+		}
+	}
+	if len(history) > paletteHistoryLimit {
+		history = history[len(history)-paletteHistoryLimit:]
+	}
+	return history
+}
+
+// savePaletteHistory writes the palette history privately
+func savePaletteHistory(path string, history []string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), paths.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create palette history directory: %w", err)
+	}
+	data := strings.Join(history, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(data), paths.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to save palette history: %w", err)
+	}
+	return nil
+}
+
+// startWorkItemCommand runs sbs start for a work item; the TUI suspends while
+// it runs and the session it attaches to stays open
+func startWorkItemCommand(workItemID string) *exec.Cmd {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "sbs"
+	}
+	return exec.Command(executable, "start", workItemID)
+}
+
+// paletteStartMsg reports that sbs start launched from the palette exited
+type paletteStartMsg struct {
+	workItem string
+	err      error
+}
+
+// openPalette shows the : prompt
+func (m Model) openPalette() (Model, tea.Cmd) {
+	m.palette = newCommandPalette(m.paletteHistory)
+	return m, textinput.Blink
+}
+
+// handlePaletteKey handles keys while the palette is open: esc closes it,
+// enter runs the command and everything else edits it
+func (m Model) handlePaletteKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.palette = nil
+		return m, nil
+	case tea.KeyEnter:
+		line := strings.TrimSpace(m.palette.input.Value())
+		palette := m.palette
+		m.palette = nil
+		if line == "" {
+			return m, nil
+		}
+		palette.record(line)
+		m.paletteHistory = palette.history
+		if err := savePaletteHistory(m.paletteHistoryPath, palette.history); err != nil {
+			m = m.reportError(err)
+		}
+		return m.runPaletteCommand(line)
+	}
+	return m, m.palette.handleKey(msg, m.listedSessions)
+}
+
+// runPaletteCommand runs one palette command line
+func (m Model) runPaletteCommand(line string) (Model, tea.Cmd) {
+	words := strings.Fields(line)
+	command, ok := lookupPaletteCommand(words[0])
+	if !ok {
+		return m.reportError(fmt.Errorf("unknown command :%s", words[0])), nil
+	}
+	args := words[1:]
+
+	switch command.name {
+	case "attach":
+		session, err := m.paletteSession(command, args)
+		if err != nil {
+			return m.reportError(err), nil
+		}
+		return m, m.attachToSession(session.TmuxSession)
+
+	case "clean":
+		if len(args) > 1 || len(args) == 1 && args[0] != "stale" {
+			return m.reportError(fmt.Errorf("usage: :%s", command.usage)), nil
+		}
+		if m.cleanBatch != nil {
+			return m.toast("A clean is already running; press esc to cancel it")
+		}
+		m = m.showCleanConfirmation()
+		if !m.showConfirmationDialog {
+			return m.toast("No stale sessions to clean")
+		}
+		return m, nil
+
+	case "dashboard":
+		m = m.toggleDashboard()
+		return m, m.refreshSessions()
+
+	case "errors":
+		return m.toggleErrorPanel(), nil
+
+	case "filter":
+		filter, err := parseSessionFilter(args)
+		if err != nil {
+			return m.reportError(err), nil
+		}
+		m.sessionFilter = filter
+		m = m.arrange()
+		m.cursor = 0
+		if filter.empty() {
+			return m.toast("Filter cleared")
+		}
+		return m.toast(fmt.Sprintf("Showing %d of %d sessions matching %s", len(m.sessions), len(m.listedSessions), filter))
+
+	case "help":
+		m.showHelp = !m.showHelp
+		return m, nil
+
+	case "quit":
+		return m, m.quit()
+
+	case "refresh":
+		return m, m.refreshSessions()
+
+	case "sort":
+		if len(args) != 1 || !containsString(sortKeys, args[0]) {
+			return m.reportError(fmt.Errorf("usage: :%s", command.usage)), nil
+		}
+		m.sortKey = args[0]
+		m = m.arrange()
+		m.cursor = 0
+		return m, nil
+
+	case "start":
+		if len(args) != 1 {
+			return m.reportError(fmt.Errorf("usage: :%s", command.usage)), nil
+		}
+		workItem := args[0]
+		return m, tea.ExecProcess(m.startCommand(workItem), func(err error) tea.Msg {
+			return paletteStartMsg{workItem: workItem, err: err}
+		})
+
+	case "stop":
+		if len(args) > 0 {
+			session, err := m.paletteSession(command, args)
+			if err != nil {
+				return m.reportError(err), nil
+			}
+			m = m.selectSession(session.NamespacedID)
+		}
+		if m.cursor >= len(m.sessions) {
+			return m.reportError(fmt.Errorf("no session selected")), nil
+		}
+		return m, m.stopSelectedSession()
+
+	case "view":
+		if len(args) != 1 || args[0] != "global" && args[0] != "repo" {
+			return m.reportError(fmt.Errorf("usage: :%s", command.usage)), nil
+		}
+		want := ViewModeGlobal
+		if args[0] == "repo" {
+			if m.currentRepo == nil {
+				return m.reportError(fmt.Errorf("not in a git repository; only the global view is available")), nil
+			}
+			want = ViewModeRepository
+		}
+		if m.viewMode == ViewModeDashboard {
+			m = m.toggleDashboard()
+		}
+		if m.viewMode != want {
+			m = m.toggleViewMode()
+		}
+		return m, m.refreshSessions()
+	}
+	return m, nil
+}
+
+// paletteSession finds the session an attach or stop command names
+func (m Model) paletteSession(command paletteCommand, args []string) (config.SessionMetadata, error) {
+	if len(args) != 1 {
+		return config.SessionMetadata{}, fmt.Errorf("usage: :%s", command.usage)
+	}
+	for _, session := range m.listedSessions {
+		if session.NamespacedID == args[0] {
+			return session, nil
+		}
+	}
+	var matches []config.SessionMetadata
+	for _, session := range m.listedSessions {
+		if _, id, found := strings.Cut(session.NamespacedID, ":"); found && id == args[0] {
+			matches = append(matches, session)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return config.SessionMetadata{}, fmt.Errorf("no session found for work item %s in this view", args[0])
+}
+
+// selectSession moves the cursor to a session, clearing the filter if it
+// hides the session
+func (m Model) selectSession(id string) Model {
+	for pass := 0; pass < 2; pass++ {
+		for i, session := range m.sessions {
+			if session.NamespacedID == id {
+				m.cursor = i
+				return m
+			}
+		}
+		m.sessionFilter = sessionFilter{}
+		m = m.arrange()
+	}
+	return m
+}
+
+// arrange rebuilds the displayed sessions from the listed ones
+func (m Model) arrange() Model {
+	if m.listedSessions == nil {
+		m.listedSessions = m.sessions
+	}
+	m.sessions = arrangeSessions(m.listedSessions, m.sessionFilter, m.sortKey)
+	if m.cursor >= len(m.sessions) {
+		m.cursor = max(0, len(m.sessions)-1)
+	}
+	return m
+}
+
+// renderArrangement describes the filter and sort order in effect, or ""
+func (m Model) renderArrangement() string {
+	var parts []string
+	if !m.sessionFilter.empty() {
+		parts = append(parts, fmt.Sprintf("filter %s (%d of %d)", m.sessionFilter, len(m.sessions), len(m.listedSessions)))
+	}
+	if m.sortKey != "" {
+		parts = append(parts, "sort "+m.sortKey)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return mutedStyle.Render(":" + strings.Join(parts, " · ") + "  (:filter clears)")
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/testsupport"
+)
+
+var paletteSessions = []config.SessionMetadata{
+	{NamespacedID: "github:12", IssueTitle: "Fix login redirect", RepositoryName: "web", SourceType: "github", Status: "active", LastActivity: "2026-03-01T10:00:00Z"},
+	{NamespacedID: "github:40", IssueTitle: "Speed up CI", RepositoryName: "infra", SourceType: "github", Status: "stopped", LastActivity: "2026-03-03T10:00:00Z"},
+	{NamespacedID: "jira:PROJ-7", IssueTitle: "Audit log export", RepositoryName: "web", SourceType: "jira", Status: "active", LastActivity: "2026-03-02T10:00:00Z"},
+}
+
+func newPaletteModel(t *testing.T) Model {
+	model := NewModelWithDependencies(Dependencies{
+		Config:         config.DefaultConfig(),
+		Tmux:           testsupport.NewFakeTmuxManager(),
+		Sandbox:        testsupport.NewFakeSandboxManager(),
+		Cleanup:        &testsupport.FakeSessionCleaner{},
+		PaletteHistory: filepath.Join(t.TempDir(), PaletteHistoryFile),
+	})
+	model.viewMode = ViewModeGlobal
+	model.width = 120
+	model.height = 30
+	updated, _ := model.Update(refreshMsg{sessions: paletteSessions})
+	return updated.(Model)
+}
+
+func sendKeys(m Model, text string) Model {
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func sendKey(m Model, keyType tea.KeyType) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.KeyMsg{Type: keyType})
+	return updated.(Model), cmd
+}
+
+// runPalette opens the palette, types line and presses enter
+func runPalette(m Model, line string) (Model, tea.Cmd) {
+	m = sendKeys(m, ":"+line)
+	return sendKey(m, tea.KeyEnter)
+}
+
+func sessionIDs(sessions []config.SessionMetadata) []string {
+	var ids []string
+	for _, session := range sessions {
+		ids = append(ids, session.NamespacedID)
+	}
+	return ids
+}
+
+func TestPalette_OpenAndCancel(t *testing.T) {
+	m := sendKeys(newPaletteModel(t), ":")
+	require.NotNil(t, m.palette)
+	assert.Contains(t, m.View(), "tab: complete")
+
+	m = sendKeys(m, "q")
+	assert.NotNil(t, m.palette, "keys go to the palette while it is open")
+
+	m, _ = sendKey(m, tea.KeyEsc)
+	assert.Nil(t, m.palette)
+}
+
+func TestPalette_FilterAndSort(t *testing.T) {
+	m, _ := runPalette(newPaletteModel(t), "filter repo=web")
+	assert.Equal(t, []string{"github:12", "jira:PROJ-7"}, sessionIDs(m.sessions))
+	assert.Contains(t, m.View(), "filter repo=web (2 of 3)")
+
+	m, _ = runPalette(m, "sort activity")
+	assert.Equal(t, []string{"jira:PROJ-7", "github:12"}, sessionIDs(m.sessions))
+
+	// Refreshes keep the filter and order
+	updated, _ := m.Update(refreshMsg{sessions: paletteSessions})
+	m = updated.(Model)
+	assert.Equal(t, []string{"jira:PROJ-7", "github:12"}, sessionIDs(m.sessions))
+
+	m, _ = runPalette(m, "filter source=github login")
+	assert.Equal(t, []string{"github:12"}, sessionIDs(m.sessions))
+
+	m, _ = runPalette(m, "filter status=paused")
+	assert.Empty(t, m.sessions)
+	assert.Contains(t, m.View(), "No sessions match the filter.")
+
+	m, _ = runPalette(m, "filter")
+	assert.Len(t, m.sessions, 3)
+	assert.Equal(t, "activity", m.sortKey, "clearing the filter keeps the order")
+}
+
+func TestPalette_Errors(t *testing.T) {
+	m, _ := runPalette(newPaletteModel(t), "frobnicate")
+	require.Error(t, m.error)
+	assert.Contains(t, m.error.Error(), "unknown command :frobnicate")
+
+	m, _ = runPalette(m, "filter owner=me")
+	assert.Contains(t, m.error.Error(), "unknown filter owner")
+
+	m, _ = runPalette(m, "sort size")
+	assert.Contains(t, m.error.Error(), "usage: :sort")
+
+	m, _ = runPalette(m, "attach github:99")
+	assert.Contains(t, m.error.Error(), "no session found for work item github:99")
+}
+
+func TestPalette_PrefixesAndStop(t *testing.T) {
+	m, cmd := runPalette(newPaletteModel(t), "q")
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd(), ":q quits")
+
+	m, cmd = runPalette(m, "sto 40")
+	require.NotNil(t, cmd, "a unique prefix and bare ID find the session")
+	assert.Equal(t, "github:40", m.sessions[m.cursor].NamespacedID)
+
+	m, _ = runPalette(m, "s")
+	assert.Contains(t, m.error.Error(), "unknown command :s", "ambiguous prefixes are rejected")
+}
+
+func TestPalette_Start(t *testing.T) {
+	var started []string
+	m := newPaletteModel(t)
+	m.startCommand = func(workItemID string) *exec.Cmd {
+		started = append(started, workItemID)
+		return exec.Command("true")
+	}
+
+	_, cmd := runPalette(m, "start github:123")
+	require.NotNil(t, cmd)
+	assert.Equal(t, []string{"github:123"}, started)
+
+	updated, _ := m.Update(paletteStartMsg{workItem: "github:123", err: assert.AnError})
+	assert.Contains(t, updated.(Model).error.Error(), "sbs start github:123 failed")
+}
+
+func TestPalette_CleanWithoutStaleSessions(t *testing.T) {
+	m, _ := runPalette(newPaletteModel(t), "clean stale")
+	assert.False(t, m.showConfirmationDialog)
+	assert.Equal(t, "No stale sessions to clean", m.notice)
+}
+
+func TestPalette_Completion(t *testing.T) {
+	assert.Equal(t, []string{"sort", "start", "stop"}, paletteCompletions("s", nil))
+	assert.Equal(t, []string{"activity"}, paletteCompletions("sort ac", nil))
+	assert.Equal(t, []string{"repo="}, paletteCompletions("filter re", nil))
+	assert.Equal(t, []string{"repo=infra", "repo=web"}, paletteCompletions("filter repo=", paletteSessions))
+	assert.Equal(t, []string{"github:12", "github:40"}, paletteCompletions("start gi", paletteSessions))
+	assert.Nil(t, paletteCompletions("start github:12 x", paletteSessions))
+
+	m := sendKeys(newPaletteModel(t), ":so")
+	m, _ = sendKey(m, tea.KeyTab)
+	assert.Equal(t, "sort ", m.palette.input.Value(), "a single candidate is completed with a space")
+
+	m = sendKeys(m, "ti")
+	m, _ = sendKey(m, tea.KeyTab)
+	assert.Equal(t, "sort title ", m.palette.input.Value())
+
+	m, _ = sendKey(m, tea.KeyEsc)
+	m = sendKeys(m, ":attach github:")
+	m, _ = sendKey(m, tea.KeyTab)
+	assert.Equal(t, "attach github:12", m.palette.input.Value())
+	m, _ = sendKey(m, tea.KeyTab)
+	assert.Equal(t, "attach github:40", m.palette.input.Value(), "tab cycles through several candidates")
+	assert.Contains(t, m.View(), "github:12  ")
+}
+
+func TestPalette_History(t *testing.T) {
+	m := newPaletteModel(t)
+	m, _ = runPalette(m, "sort id")
+	m, _ = runPalette(m, "filter repo=web")
+	m, _ = runPalette(m, "sort id")
+
+	data, err := os.ReadFile(m.paletteHistoryPath)
+	require.NoError(t, err)
+	assert.Equal(t, "filter repo=web\nsort id\n", string(data), "repeats move to the end")
+
+	m = sendKeys(m, ":dra")
+	m, _ = sendKey(m, tea.KeyUp)
+	assert.Equal(t, "sort id", m.palette.input.Value())
+	m, _ = sendKey(m, tea.KeyUp)
+	assert.Equal(t, "filter repo=web", m.palette.input.Value())
+	m, _ = sendKey(m, tea.KeyUp)
+	assert.Equal(t, "filter repo=web", m.palette.input.Value(), "stops at the oldest command")
+	m, _ = sendKey(m, tea.KeyDown)
+	m, _ = sendKey(m, tea.KeyDown)
+	assert.Equal(t, "dra", m.palette.input.Value(), "down past the newest restores the draft")
+
+	// A new TUI picks the history up from the file
+	reopened := NewModelWithDependencies(Dependencies{
+		Tmux:           testsupport.NewFakeTmuxManager(),
+		Sandbox:        testsupport.NewFakeSandboxManager(),
+		Cleanup:        &testsupport.FakeSessionCleaner{},
+		PaletteHistory: m.paletteHistoryPath,
+	})
+	assert.Equal(t, []string{"filter repo=web", "sort id"}, reopened.paletteHistory)
+}
@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"sbs/pkg/cleanup"
+	"sbs/pkg/faultinject"
+	"sbs/pkg/provisioning"
+)
+
+// startProgressInterval is how often the progress of in-flight starts is polled
+const startProgressInterval = time.Second
+
+// progressBarWidth is the width of a progress view's percentage bar
+const progressBarWidth = 20
+
+// progressMaxSteps is how many steps a progress view lists at most
+const progressMaxSteps = 5
+
+// progressSpinner is the spinner drawn in front of a progress view's title
+var progressSpinner = spinner.MiniDot
+
+// startSteps are the steps sbs start publishes to the progress board, in order
+var startSteps = []string{"starting", faultinject.StepWorktreeAdd, faultinject.StepTmuxCreate}
+
+// progressStepState is how far a step of a progress view has got
+type progressStepState int
+
+const (
+	stepPending progressStepState = iota
+	stepRunning
+	stepDone
+	stepFailed
+)
+
+// progressStep is one entry of a progress view's step list
+type progressStep struct {
+	label string
+	state progressStepState
+}
+
+// progressView shows a long running operation the same way everywhere in the
+// TUI: a spinner and title, a percentage bar once the share of work done is
+// known, and the operation's steps
+type progressView struct {
+	title   string
+	percent int    // share of the work done; negative when unknown
+	detail  string // shown after the percentage, e.g. "(1234/2742)"
+	hint    string // e.g. "esc to cancel"
+	steps   []progressStep
+}
+
+// newProgressView creates a view of an operation whose progress is not known yet
+func newProgressView(title string) progressView {
+	return progressView{title: title, percent: -1}
+}
+
+// withCount returns a copy of the view with done of total units of work finished
+func (p progressView) withCount(done, total int) progressView {
+	if total > 0 {
+		p.percent = done * 100 / total
+	}
+	return p
+}
+
+// spinnerFrame returns the spinner frame to draw at now. The frame follows
+// the clock, so every render of a progress view moves the spinner on without
+// a timer of its own.
+func spinnerFrame(now time.Time) string {
+	frames := progressSpinner.Frames
+	frame := int(now.UnixNano()/int64(progressSpinner.FPS)) % len(frames)
+	if frame < 0 {
+		frame += len(frames) // times before 1970, such as the zero time
+	}
+	return frames[frame]
+}
+
+// render draws the view at now, truncating its lines to width when set
+func (p progressView) render(now time.Time, width int) string {
+	var b strings.Builder
+
+	line := spinnerFrame(now) + " " + p.title
+	if p.percent >= 0 {
+		percent := min(max(p.percent, 0), 100)
+		filled := percent * progressBarWidth / 100
+		line += fmt.Sprintf("  %s%s %d%%", strings.Repeat("█", filled), strings.Repeat("░", progressBarWidth-filled), percent)
+	} else if len(p.steps) == 0 {
+		line += "..."
+	}
+	if p.detail != "" {
+		line += " " + p.detail
+	}
+	if p.hint != "" {
+		line += " (" + p.hint + ")"
+	}
+	b.WriteString(warningStyle.Render(truncateProgressLine(line, width)))
+
+	start, end := p.visibleSteps()
+	for _, step := range p.steps[start:end] {
+		b.WriteString("\n" + renderProgressStep(step, width))
+	}
+	if hidden := len(p.steps) - (end - start); hidden > 0 {
+		b.WriteString("\n" + mutedStyle.Render(fmt.Sprintf("    +%d more", hidden)))
+	}
+	return b.String()
+}
+
+// visibleSteps returns the [start, end) window of steps to list: at most
+// progressMaxSteps, starting just before the first unfinished one
+func (p progressView) visibleSteps() (int, int) {
+	if len(p.steps) <= progressMaxSteps {
+		return 0, len(p.steps)
+	}
+	first := len(p.steps)
+	for i, step := range p.steps {
+		if step.state == stepPending || step.state == stepRunning {
+			first = i
+			break
+		}
+	}
+	start := min(max(first-1, 0), len(p.steps)-progressMaxSteps)
+	return start, start + progressMaxSteps
+}
+
+// renderProgressStep draws one step with a marker for its state
+func renderProgressStep(step progressStep, width int) string {
+	switch step.state {
+	case stepDone:
+		return statusActiveStyle.Render(truncateProgressLine("  ✓ "+step.label, width))
+	case stepFailed:
+		return errorStyle.Render(truncateProgressLine("  ✗ "+step.label, width))
+	case stepRunning:
+		return warningStyle.Render(truncateProgressLine("  ● "+step.label, width))
+	default:
+		return mutedStyle.Render(truncateProgressLine("  ○ "+step.label, width))
+	}
+}
+
+func truncateProgressLine(line string, width int) string {
+	if width > 0 {
+		return TruncateString(line, width)
+	}
+	return line
+}
+
+// startProgressMsg carries the progress of sessions being started
+type startProgressMsg struct {
+	progress []provisioning.Progress
+}
+
+// pollStartProgress reads the progress board after startProgressInterval
+func (m Model) pollStartProgress() tea.Cmd {
+	if m.progressBoard == nil {
+		return nil
+	}
+	board := m.progressBoard
+	return tea.Tick(startProgressInterval, func(now time.Time) tea.Msg {
+		progress, _ := board.Active(now)
+		return startProgressMsg{progress: progress}
+	})
+}
+
+// startProgressView shows a session being started: its start steps, with a
+// bar once git reports checkout progress
+func startProgressView(p provisioning.Progress) progressView {
+	view := newProgressView("Starting " + p.WorkItem)
+	if p.Total > 0 {
+		view.percent = p.Percent
+		view.detail = fmt.Sprintf("%s (%d/%d)", p.Phase, p.Current, p.Total)
+	}
+
+	current := -1
+	for i, step := range startSteps {
+		if step == p.Step {
+			current = i
+		}
+	}
+	if current < 0 {
+		// A step this TUI doesn't know; show it alone
+		view.steps = []progressStep{{label: p.Step, state: stepRunning}}
+		return view
+	}
+	for i, step := range startSteps {
+		state := stepPending
+		if i < current {
+			state = stepDone
+		} else if i == current {
+			state = stepRunning
+		}
+		view.steps = append(view.steps, progressStep{label: step, state: state})
+	}
+	return view
+}
+
+// renderStartProgress renders a progress view per session being started
+func (m Model) renderStartProgress() string {
+	views := make([]string, 0, len(m.startProgress))
+	for _, p := range m.startProgress {
+		views = append(views, startProgressView(p).render(m.now(), m.width))
+	}
+	return strings.Join(views, "\n")
+}
+
+// cleanProgressView shows the running clean: one step per session, done or
+// failed once the clean has finished it
+func (m Model) cleanProgressView() progressView {
+	total := len(m.pendingCleanSessions)
+	done := 0
+	if m.cleanProgress != nil {
+		total, done = m.cleanProgress.Total, m.cleanProgress.Done
+	}
+
+	view := newProgressView(fmt.Sprintf("Cleaning %d/%d", done, total)).withCount(done, total)
+	view.hint = "esc to cancel"
+	for _, session := range m.pendingCleanSessions {
+		state := stepPending
+		if result, ok := m.cleanResults[session.NamespacedID]; ok {
+			state = stepDone
+			if cleanResultFailed(result) {
+				state = stepFailed
+			}
+		}
+		view.steps = append(view.steps, progressStep{label: session.NamespacedID, state: state})
+	}
+	return view
+}
+
+// cleanResultFailed reports whether cleaning a session failed for any of its
+// resources
+func cleanResultFailed(result cleanup.SessionResult) bool {
+	for _, action := range result.Actions {
+		if action.Outcome == cleanup.OutcomeFailed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/cleanup"
+	"sbs/pkg/config"
+	"sbs/pkg/provisioning"
+)
+
+func TestStartProgressOverlay(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 2)
+	assert.Nil(t, model.pollStartProgress(), "no board, no polling")
+
+	model.progressBoard = provisioning.NewProgressBoard(filepath.Join(t.TempDir(), "progress"))
+	require.NotNil(t, model.pollStartProgress())
+
+	progress := []provisioning.Progress{
+		{WorkItem: "github:7", Step: "worktree", Phase: "Updating files", Current: 1234, Total: 2742, Percent: 45},
+		{WorkItem: "test:1", Step: "starting"},
+	}
+	updated, cmd := model.Update(startProgressMsg{progress: progress})
+	model = updated.(Model)
+	require.NotNil(t, cmd, "polling continues")
+
+	view := model.View()
+	assert.Contains(t, view, "Starting github:7  █████████░░░░░░░░░░░ 45% Updating files (1234/2742)")
+	assert.Contains(t, view, "✓ starting")
+	assert.Contains(t, view, "● worktree")
+	assert.Contains(t, view, "Starting test:1")
+	assert.Contains(t, view, "● starting")
+
+	updated, _ = model.Update(startProgressMsg{progress: progress[1:]})
+	model = updated.(Model)
+	assert.NotContains(t, model.View(), "github:7")
+}
+
+func TestProgressView_Render(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, spinnerFrame(now)+" Loading issues...", newProgressView("Loading issues").render(now, 0))
+	assert.NotEqual(t, spinnerFrame(now), spinnerFrame(now.Add(progressSpinner.FPS)), "the spinner follows the clock")
+
+	view := newProgressView("Syncing").withCount(1, 4)
+	view.hint = "esc to cancel"
+	view.steps = []progressStep{
+		{label: "github:1", state: stepDone},
+		{label: "github:2", state: stepRunning},
+		{label: "github:3", state: stepFailed},
+		{label: "github:4"},
+	}
+	assert.Equal(t, spinnerFrame(now)+" Syncing  █████░░░░░░░░░░░░░░░ 25% (esc to cancel)\n"+
+		"  ✓ github:1\n  ● github:2\n  ✗ github:3\n  ○ github:4", view.render(now, 0))
+}
+
+func TestProgressView_StepWindow(t *testing.T) {
+	view := newProgressView("Cleaning")
+	for i := 1; i <= 9; i++ {
+		state := stepPending
+		if i <= 6 {
+			state = stepDone
+		}
+		view.steps = append(view.steps, progressStep{label: fmt.Sprintf("github:%d", i), state: state})
+	}
+
+	rendered := view.render(time.Time{}, 0)
+	assert.NotContains(t, rendered, "github:4", "finished steps scroll away")
+	assert.Contains(t, rendered, "  ✓ github:5\n  ✓ github:6\n  ○ github:7\n  ○ github:8\n  ○ github:9\n    +4 more")
+}
+
+func TestCleanProgressView(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 0)
+	model.pendingCleanSessions = []config.SessionMetadata{{NamespacedID: "github:5"}, {NamespacedID: "github:6"}, {NamespacedID: "github:7"}}
+
+	view := model.cleanProgressView()
+	assert.Equal(t, "Cleaning 0/3", view.title)
+	assert.Equal(t, 0, view.percent)
+
+	model.cleanProgress = &cleanup.Progress{Done: 2, Total: 3}
+	model.cleanResults = map[string]cleanup.SessionResult{
+		"github:5": {Cleaned: true},
+		"github:6": {Actions: []cleanup.Action{{Outcome: cleanup.OutcomeFailed, Err: errors.New("busy device")}}},
+	}
+	view = model.cleanProgressView()
+	assert.Equal(t, 66, view.percent)
+	assert.Equal(t, []progressStep{
+		{label: "github:5", state: stepDone},
+		{label: "github:6", state: stepFailed},
+		{label: "github:7", state: stepPending},
+	}, view.steps)
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"sbs/pkg/config"
+)
+
+// RecentSessionCount is how many recent sessions get an attach shortcut
+const RecentSessionCount = 3
+
+// RecentSessions returns up to n sessions that can be attached to, most
+// recently active first. Sessions without a parseable last_activity sort last.
+func RecentSessions(sessions []config.SessionMetadata, n int) []config.SessionMetadata {
+	var candidates []config.SessionMetadata
+	for _, session := range sessions {
+		if session.TmuxSession == "" || session.Status == "stopped" {
+			continue
+		}
+		candidates = append(candidates, session)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lastActivity(candidates[i]).After(lastActivity(candidates[j]))
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// lastActivity parses a session's last_activity, returning the zero time
+// when it is missing or malformed
+func lastActivity(session config.SessionMetadata) time.Time {
+	t, err := time.Parse(time.RFC3339, session.LastActivity)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// recentSessions returns the sessions the 1-3 shortcuts attach to
+func (m Model) recentSessions() []config.SessionMetadata {
+	return RecentSessions(m.sessions, RecentSessionCount)
+}
+
+// renderRecentLine lists the attach shortcuts for the recent sessions,
+// e.g. "Recent: [1] Fix login  [2] Add dark mode"
+func renderRecentLine(recent []config.SessionMetadata, width int) string {
+	shortcuts := keys.Recent.Keys()
+	parts := make([]string, 0, len(recent))
+	for i, session := range recent {
+		if i >= len(shortcuts) {
+			break
+		}
+		label := session.IssueTitle
+		if label == "" {
+			label = session.NamespacedID
+		}
+		parts = append(parts, fmt.Sprintf("[%s] %s", shortcuts[i], label))
+	}
+	line := "Recent: " + strings.Join(parts, "  ")
+	if width > 0 && len(line) > width {
+		line = truncateRecentLine(line, width)
+	}
+	return mutedStyle.Render(line)
+}
+
+// truncateRecentLine shortens a line to width runes, marking the cut
+func truncateRecentLine(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width || width < 4 {
+		return line
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+// attachToRecent attaches to the recent session whose shortcut was pressed:
+// the first key of the recent binding attaches to the most recent session,
+// and so on. It does nothing when there is no such session.
+func (m Model) attachToRecent(pressed string) tea.Cmd {
+	recent := m.recentSessions()
+	for index, shortcut := range keys.Recent.Keys() {
+		if shortcut == pressed && index < len(recent) {
+			return m.attachToSession(recent[index].TmuxSession)
+		}
+	}
+	return nil
+}
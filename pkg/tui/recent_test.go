@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func recentFixture() []config.SessionMetadata {
+	return []config.SessionMetadata{
+		{NamespacedID: "github:1", IssueTitle: "Oldest", TmuxSession: "sbs-1", LastActivity: "2026-01-01T09:00:00Z"},
+		{NamespacedID: "github:2", IssueTitle: "Newest", TmuxSession: "sbs-2", LastActivity: "2026-01-03T09:00:00Z"},
+		{NamespacedID: "github:3", IssueTitle: "Stopped", TmuxSession: "sbs-3", LastActivity: "2026-01-04T09:00:00Z", Status: "stopped"},
+		{NamespacedID: "github:4", IssueTitle: "Unknown", TmuxSession: "sbs-4"},
+		{NamespacedID: "github:5", IssueTitle: "Middle", TmuxSession: "sbs-5", LastActivity: "2026-01-02T09:00:00Z"},
+	}
+}
+
+func TestRecentSessions_OrdersByActivity(t *testing.T) {
+	recent := RecentSessions(recentFixture(), RecentSessionCount)
+
+	var ids []string
+	for _, session := range recent {
+		ids = append(ids, session.NamespacedID)
+	}
+	assert.Equal(t, []string{"github:2", "github:5", "github:1"}, ids, "stopped sessions are skipped, unknown activity sorts last")
+	assert.Len(t, RecentSessions(recentFixture(), 10), 4)
+	assert.Empty(t, RecentSessions(nil, RecentSessionCount))
+}
+
+func TestRenderRecentLine(t *testing.T) {
+	line := renderRecentLine(RecentSessions(recentFixture(), RecentSessionCount), 0)
+	assert.Contains(t, line, "Recent: [1] Newest  [2] Middle  [3] Oldest")
+
+	short := renderRecentLine(RecentSessions(recentFixture(), RecentSessionCount), 20)
+	assert.Contains(t, short, "Recent: [1] Newes...")
+}
+
+func TestAttachToRecent_ShortcutPicksSession(t *testing.T) {
+	model := Model{sessions: recentFixture()}
+
+	assert.NotNil(t, model.attachToRecent("2"))
+	assert.Nil(t, model.attachToRecent("9"), "keys outside the binding are ignored")
+	assert.Nil(t, Model{}.attachToRecent("1"), "no sessions, nothing to attach")
+
+	result, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, model.cursor, result.(Model).cursor, "the selection doesn't move")
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+// scriptedSessions are the sessions scripted TUI tests start from; their
+// activity is relative to harnessNow
+func scriptedSessions() []config.SessionMetadata {
+	at := func(ago time.Duration) string {
+		return harnessNow.Add(-ago).Format(time.RFC3339)
+	}
+	return []config.SessionMetadata{
+		{NamespacedID: "github:12", IssueTitle: "Fix login redirect", Branch: "issue-12-fix-login-redirect", RepositoryName: "web",
+			SourceType: "github", TmuxSession: "sbs-web-12", SandboxName: "sbs-web-12", Status: "active", LastActivity: at(5 * time.Minute)},
+		{NamespacedID: "github:40", IssueTitle: "Speed up CI", Branch: "issue-40-speed-up-ci", RepositoryName: "infra",
+			SourceType: "github", TmuxSession: "sbs-infra-40", SandboxName: "sbs-infra-40", Status: "active", LastActivity: at(3 * time.Hour)},
+		{NamespacedID: "jira:PROJ-7", IssueTitle: "Audit log export", Branch: "issue-proj-7-audit-log-export", RepositoryName: "web",
+			SourceType: "jira", TmuxSession: "sbs-web-proj-7", SandboxName: "sbs-web-proj-7", Status: "stopped", LastActivity: at(50 * time.Hour)},
+	}
+}
+
+func TestScripted_Navigate(t *testing.T) {
+	h := newTUIHarness(t, scriptedSessions(), "sbs-web-12", "sbs-infra-40")
+	h.assertGolden("initial")
+
+	h.press("down", "down")
+	assert.Equal(t, 2, h.model.cursor)
+	h.assertGolden("last_selected")
+
+	h.press("down", "up")
+	assert.Equal(t, 1, h.model.cursor, "the cursor stops at the last session")
+
+	h.press("?")
+	h.assertGolden("help")
+
+	h.press("?", "q")
+	assert.True(t, h.quit)
+}
+
+func TestScripted_Stop(t *testing.T) {
+	h := newTUIHarness(t, scriptedSessions(), "sbs-web-12", "sbs-infra-40")
+
+	h.press("down", "s")
+	assert.Equal(t, []string{"sbs-infra-40"}, h.tmux.Killed)
+	assert.Nil(t, h.model.error)
+	h.assertGolden("stopped")
+}
+
+func TestScripted_CleanConfirm(t *testing.T) {
+	sessions := scriptedSessions()
+	h := newTUIHarness(t, sessions, "sbs-web-12", "sbs-infra-40")
+	h.cleaner.Stale = sessions[2:]
+
+	h.press("c")
+	require.True(t, h.model.showConfirmationDialog)
+	h.assertGolden("confirm")
+
+	h.press("esc")
+	assert.False(t, h.model.showConfirmationDialog)
+	assert.Empty(t, h.cleaner.Cleaned)
+
+	h.sessions = sessions[:2]
+	h.press("c", "y")
+	require.Len(t, h.cleaner.Cleaned, 1)
+	assert.Equal(t, sessions[2:], h.cleaner.Cleaned[0])
+	assert.Len(t, h.model.sessions, 2, "the refresh after cleaning drops the session")
+	h.assertGolden("cleaned")
+}
+
+func TestScripted_LogView(t *testing.T) {
+	worktree := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".sbs"), 0755))
+	script := "#!/bin/sh\necho \"mode: $1\"\necho 'build ok'\necho 'tests: 12 passed'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".sbs", "loghook"), []byte(script), 0755))
+
+	sessions := scriptedSessions()
+	sessions[0].WorktreePath = worktree
+	h := newTUIHarness(t, sessions, "sbs-web-12", "sbs-infra-40")
+
+	h.press("l")
+	require.Equal(t, ViewModeLog, h.model.viewMode)
+	h.assertGolden("log")
+
+	h.press("esc")
+	assert.Equal(t, ViewModeGlobal, h.model.viewMode)
+}
+
+func TestScripted_ExpiryFollowsClock(t *testing.T) {
+	h := newTUIHarness(t, scriptedSessions(), "sbs-web-12", "sbs-infra-40")
+	h.model.config.CleanAfterDays = 3
+
+	h.press("r")
+	assert.Contains(t, h.screen(), "in 22h", "jira:PROJ-7 was last used 50h ago")
+
+	h.clock.Advance(23 * time.Hour)
+	h.press("r")
+	assert.Contains(t, h.screen(), "expired")
+	assert.Contains(t, h.screen(), "3d ago")
+}
+
+func TestScripted_RepositoryGroups(t *testing.T) {
+	h := newTUIHarness(t, scriptedSessions(), "sbs-web-12", "sbs-infra-40")
+
+	h.press("G")
+	assert.Equal(t, "No repository_groups configured", h.model.notice)
+
+	h.model.config.RepositoryGroups = map[string][]string{"frontend": {"web"}, "platform": {"infra"}}
+	h.press("G")
+	assert.Equal(t, "frontend", h.model.repoGroup)
+	require.Len(t, h.model.sessions, 2)
+	assert.Contains(t, h.screen(), "Work Issue Orchestrator (Global: frontend)")
+	assert.NotContains(t, h.screen(), "Speed up CI")
+
+	h.press("G")
+	require.Len(t, h.model.sessions, 1)
+	assert.Equal(t, "github:40", h.model.sessions[0].NamespacedID)
+
+	h.press("G")
+	assert.Empty(t, h.model.repoGroup, "cycling past the last group shows every repository")
+	assert.Len(t, h.model.sessions, 3)
+}
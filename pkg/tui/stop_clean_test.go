@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbletea"
@@ -10,71 +13,32 @@ import (
 
 	"sbs/pkg/cleanup"
 	"sbs/pkg/config"
-	"sbs/pkg/sandbox"
-	"sbs/pkg/tmux"
+	"sbs/pkg/testsupport"
 )
 
-// Mock implementations for testing
-type MockTmuxManager struct {
-	sessions      []string
-	staleSessions []string
-	killError     error
-	existsError   error
-}
-
-func (m *MockTmuxManager) SessionExists(sessionName string) (bool, error) {
-	if m.existsError != nil {
-		return false, m.existsError
-	}
-	for _, session := range m.sessions {
-		if session == sessionName {
-			return true, nil
-		}
-	}
-	return false, nil
-}
-
-func (m *MockTmuxManager) KillSession(sessionName string) error {
-	return m.killError
-}
-
-func (m *MockTmuxManager) ListSessions() ([]*tmux.Session, error) {
-	var sessions []*tmux.Session
-	for _, name := range m.sessions {
-		sessions = append(sessions, &tmux.Session{Name: name})
-	}
-	return sessions, nil
-}
-
-func (m *MockTmuxManager) AttachToSession(sessionName string) error {
-	return nil
-}
-
-type MockSandboxManager struct {
-	sandboxes   map[string]bool
-	removeError error
-}
-
-func (m *MockSandboxManager) SandboxExists(sandboxName string) (bool, error) {
-	if m.sandboxes == nil {
-		return false, nil
-	}
-	exists, ok := m.sandboxes[sandboxName]
-	return exists && ok, nil
-}
-
-func (m *MockSandboxManager) DeleteSandbox(sandboxName string) error {
-	return m.removeError
-}
+// Fakes must satisfy the interfaces the Model depends on
+var (
+	_ TmuxManager            = (*testsupport.FakeTmuxManager)(nil)
+	_ SandboxManager         = (*testsupport.FakeSandboxManager)(nil)
+	_ cleanup.SessionCleaner = (*testsupport.FakeSessionCleaner)(nil)
+)
 
-func (m *MockSandboxManager) GetSandboxName(issueNumber int) string {
-	return sandbox.NewManager().GetSandboxName(issueNumber)
+// newFakeModel creates a model backed by in-memory fakes and returns them for assertions
+func newFakeModel(tmuxSessions []string, sandboxes []string) (Model, *testsupport.FakeTmuxManager, *testsupport.FakeSandboxManager) {
+	fakeTmux := testsupport.NewFakeTmuxManager(tmuxSessions...)
+	fakeSandbox := testsupport.NewFakeSandboxManager(sandboxes...)
+	model := NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    fakeTmux,
+		Sandbox: fakeSandbox,
+		Cleanup: cleanup.NewCleanupManager(fakeTmux, fakeSandbox, nil, nil),
+	})
+	return model, fakeTmux, fakeSandbox
 }
 
-// Test helper function - for now, let's just create a model with real managers
-// and mock at the external level
+// Test helper function - creates a model backed by fakes with no running sessions
 func setupTestModel() Model {
-	model := NewModel()
+	model, _, _ := newFakeModel(nil, nil)
 	model.sessions = []config.SessionMetadata{
 		{
 			IssueNumber:    123,
@@ -101,57 +65,6 @@ func executeCommand(cmd tea.Cmd) tea.Msg {
 	return cmd()
 }
 
-// Interface for dependency injection
-type TmuxManager interface {
-	SessionExists(sessionName string) (bool, error)
-	KillSession(sessionName string) error
-	ListSessions() ([]*tmux.Session, error)
-	AttachToSession(sessionName string) error
-}
-
-type SandboxManager interface {
-	SandboxExists(sandboxName string) (bool, error)
-	DeleteSandbox(sandboxName string) error
-	GetSandboxName(issueNumber int) string
-}
-
-// Wrapper structs to bridge mock interfaces with concrete types
-type tmuxManagerWrapper struct {
-	mock *MockTmuxManager
-}
-
-func (w *tmuxManagerWrapper) SessionExists(sessionName string) (bool, error) {
-	return w.mock.SessionExists(sessionName)
-}
-
-func (w *tmuxManagerWrapper) KillSession(sessionName string) error {
-	return w.mock.KillSession(sessionName)
-}
-
-func (w *tmuxManagerWrapper) ListSessions() ([]*tmux.Session, error) {
-	return w.mock.ListSessions()
-}
-
-func (w *tmuxManagerWrapper) AttachToSession(sessionName string) error {
-	return w.mock.AttachToSession(sessionName)
-}
-
-type sandboxManagerWrapper struct {
-	mock *MockSandboxManager
-}
-
-func (w *sandboxManagerWrapper) SandboxExists(sandboxName string) (bool, error) {
-	return w.mock.SandboxExists(sandboxName)
-}
-
-func (w *sandboxManagerWrapper) DeleteSandbox(sandboxName string) error {
-	return w.mock.DeleteSandbox(sandboxName)
-}
-
-func (w *sandboxManagerWrapper) GetSandboxName(issueNumber int) string {
-	return w.mock.GetSandboxName(issueNumber)
-}
-
 func TestStopCleanKeyBindings(t *testing.T) {
 	t.Run("stop_key_binding_exists", func(t *testing.T) {
 		// This test will fail until we add the Stop key binding
@@ -301,7 +214,7 @@ func TestCleanStaleSessions(t *testing.T) {
 		}
 
 		// Use CleanupManager to identify stale sessions
-		staleSessions, err := model.cleanupManager.IdentifyStaleSessionsInView(model.sessions, cleanup.ViewModeGlobal)
+		staleSessions, err := model.cleanupManager.IdentifyStaleSessionsInView(context.Background(), model.sessions, cleanup.ViewModeGlobal)
 
 		// Since no tmux sessions exist in test environment, all should be stale
 		assert.NoError(t, err)
@@ -315,13 +228,13 @@ func TestCleanStaleSessions(t *testing.T) {
 		}
 
 		// Test the TUI cleanup flow using CleanupManager
-		staleSessions, err := model.cleanupManager.IdentifyStaleSessionsInView(model.sessions, cleanup.ViewModeGlobal)
+		staleSessions, err := model.cleanupManager.IdentifyStaleSessionsInView(context.Background(), model.sessions, cleanup.ViewModeGlobal)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(staleSessions), "Should identify session as stale")
 
 		// Test cleanup using CleanupManager
 		options := model.cleanupManager.BuildTUICleanupOptions(cleanup.ViewModeGlobal, true)
-		results, err := model.cleanupManager.CleanupSessions(staleSessions, options)
+		results, err := model.cleanupManager.CleanupSessions(context.Background(), staleSessions, options)
 
 		// Test the structure is correct
 		assert.NotNil(t, results, "Should have CleanupResults")
@@ -332,7 +245,7 @@ func TestCleanStaleSessions(t *testing.T) {
 		model := setupTestModel()
 		model.sessions = []config.SessionMetadata{}
 
-		staleSessions, err := model.cleanupManager.IdentifyStaleSessionsInView(model.sessions, cleanup.ViewModeGlobal)
+		staleSessions, err := model.cleanupManager.IdentifyStaleSessionsInView(context.Background(), model.sessions, cleanup.ViewModeGlobal)
 		assert.NoError(t, err)
 		assert.Equal(t, 0, len(staleSessions), "Should find no stale sessions in empty list")
 	})
@@ -382,10 +295,10 @@ func TestStopCleanKeyHandling(t *testing.T) {
 		assert.False(t, updatedModel.showConfirmationDialog, "Should hide confirmation dialog")
 		assert.NotNil(t, cmd, "Should return cleanup command")
 
-		// Execute command to verify it returns cleanSessionsMsg
+		// Execute command to verify it reports the cleaned session
 		msg := executeCommand(cmd)
-		_, ok := msg.(cleanSessionsMsg)
-		assert.True(t, ok, "Command should return cleanSessionsMsg")
+		_, ok := msg.(cleanProgressMsg)
+		assert.True(t, ok, "Command should return cleanProgressMsg")
 	})
 
 	t.Run("n_key_cancels_cleanup", func(t *testing.T) {
@@ -447,3 +360,200 @@ func TestMessageHandling(t *testing.T) {
 		assert.NotNil(t, cmd, "Should return refresh command")
 	})
 }
+
+func TestStopSelectedSession_WithFakes(t *testing.T) {
+	t.Run("kills_tmux_and_deletes_sandbox", func(t *testing.T) {
+		model, fakeTmux, fakeSandbox := newFakeModel([]string{"sbs-123"}, []string{"sbs-repo-123"})
+		model.sessions = []config.SessionMetadata{{NamespacedID: "github:123", TmuxSession: "sbs-123", SandboxName: "sbs-repo-123"}}
+
+		msg, ok := executeCommand(model.stopSelectedSession()).(stopSessionMsg)
+		require.True(t, ok)
+		require.NoError(t, msg.err)
+		assert.True(t, msg.success)
+		assert.Equal(t, []string{"sbs-123"}, fakeTmux.Killed)
+		assert.Equal(t, []string{"sbs-repo-123"}, fakeSandbox.Deleted)
+	})
+
+	t.Run("skips_missing_tmux_session", func(t *testing.T) {
+		model, fakeTmux, _ := newFakeModel(nil, nil)
+		model.sessions = []config.SessionMetadata{{TmuxSession: "sbs-9", SandboxName: "sbs-repo-9"}}
+
+		msg := executeCommand(model.stopSelectedSession()).(stopSessionMsg)
+		require.NoError(t, msg.err)
+		assert.Empty(t, fakeTmux.Killed)
+	})
+
+	t.Run("reports_kill_error", func(t *testing.T) {
+		model, fakeTmux, _ := newFakeModel([]string{"sbs-1"}, nil)
+		fakeTmux.KillErr = errors.New("server exited")
+		model.sessions = []config.SessionMetadata{{TmuxSession: "sbs-1", SandboxName: "sbs-repo-1"}}
+
+		msg := executeCommand(model.stopSelectedSession()).(stopSessionMsg)
+		require.Error(t, msg.err)
+		assert.Contains(t, msg.err.Error(), "server exited")
+		assert.False(t, msg.success)
+	})
+
+	t.Run("reports_sandbox_delete_error", func(t *testing.T) {
+		model, _, fakeSandbox := newFakeModel(nil, []string{"sbs-repo-2"})
+		fakeSandbox.DeleteErr = errors.New("busy")
+		model.sessions = []config.SessionMetadata{{TmuxSession: "sbs-2", SandboxName: "sbs-repo-2"}}
+
+		msg := executeCommand(model.stopSelectedSession()).(stopSessionMsg)
+		require.Error(t, msg.err)
+		assert.Contains(t, msg.err.Error(), "failed to delete sandbox")
+	})
+}
+
+func TestCleanStaleSessions_WithFakeCleaner(t *testing.T) {
+	stale := []config.SessionMetadata{{NamespacedID: "github:5", IssueTitle: "Old work"}}
+	cleaner := &testsupport.FakeSessionCleaner{Stale: stale}
+	model := NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    testsupport.NewFakeTmuxManager(),
+		Sandbox: testsupport.NewFakeSandboxManager(),
+		Cleanup: cleaner,
+	})
+	model.sessions = stale
+
+	model = model.showCleanConfirmation()
+	require.True(t, model.showConfirmationDialog)
+	assert.Contains(t, model.confirmationMessage, "Work Item github:5: Old work")
+
+	model, cmd := model.confirmClean()
+	require.NotNil(t, model.cleanBatch)
+	assert.Contains(t, model.View(), "esc to cancel")
+
+	progress, ok := executeCommand(cmd).(cleanProgressMsg)
+	require.True(t, ok, "each finished session is reported before the clean ends")
+	assert.Equal(t, 1, progress.progress.Total)
+	newModel, cmd := model.Update(progress)
+	model = newModel.(Model)
+	view := model.View()
+	assert.Contains(t, view, "Cleaning 1/1  ████████████████████ 100% (esc to cancel)")
+	assert.Contains(t, view, "✓ github:5")
+
+	msg, ok := executeCommand(cmd).(cleanSessionsMsg)
+	require.True(t, ok)
+	assert.NoError(t, msg.err)
+	assert.Equal(t, stale, msg.cleanedSessions)
+	require.Len(t, cleaner.Cleaned, 1)
+	assert.Equal(t, stale, cleaner.Cleaned[0])
+
+	newModel, _ = model.Update(msg)
+	model = newModel.(Model)
+	assert.Nil(t, model.cleanBatch)
+	assert.NotContains(t, model.View(), "esc to cancel")
+}
+
+func TestCleanStaleSessions_EscCancels(t *testing.T) {
+	stale := []config.SessionMetadata{{NamespacedID: "github:5"}, {NamespacedID: "github:6"}}
+	model := NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    testsupport.NewFakeTmuxManager(),
+		Sandbox: testsupport.NewFakeSandboxManager(),
+		Cleanup: &testsupport.FakeSessionCleaner{Stale: stale},
+	})
+	model.pendingCleanSessions = stale
+
+	gate := make(chan struct{})
+	model.cleanBatch = cleanup.NewBatch(context.Background(), len(stale), func(ctx context.Context, finished func(cleanup.SessionResult)) (cleanup.CleanupResults, error) {
+		<-gate
+		return cleanup.CleanupResults{}, ctx.Err()
+	})
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newModel.(Model)
+	close(gate)
+
+	msg, ok := executeCommand(model.waitForCleanProgress()).(cleanSessionsMsg)
+	require.True(t, ok)
+	assert.True(t, msg.cancelled)
+
+	newModel, _ = model.Update(msg)
+	model = newModel.(Model)
+	assert.Nil(t, model.error, "a cancelled clean is not an error")
+	assert.Empty(t, model.errorHistory.entries)
+}
+
+func TestRefreshAndAttach_WithFakes(t *testing.T) {
+	model, fakeTmux, _ := newFakeModel([]string{"sbs-a", "sbs-b"}, nil)
+	t.Setenv("HOME", t.TempDir())
+
+	msg, ok := executeCommand(model.refreshSessions()).(refreshMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.err)
+	require.Len(t, msg.tmuxSessions, 2)
+	assert.Equal(t, "sbs-a", msg.tmuxSessions[0].Name)
+
+	attach := executeCommand(model.attachToSession("sbs-b")).(attachMsg)
+	assert.NoError(t, attach.err)
+	assert.Equal(t, []string{"sbs-b"}, fakeTmux.Attached)
+
+	attach = executeCommand(model.attachToSession("missing")).(attachMsg)
+	assert.Error(t, attach.err)
+}
+
+func TestErrorPanel(t *testing.T) {
+	t.Run("errors_accumulate_across_refreshes", func(t *testing.T) {
+		model := setupTestModel()
+
+		newModel, _ := model.Update(stopSessionMsg{err: errors.New("stop failed"), session: "github:1"})
+		newModel, _ = newModel.(Model).Update(refreshMsg{sessions: model.sessions})
+		newModel, _ = newModel.(Model).Update(attachMsg{err: errors.New("attach failed"), session: "sbs-124"})
+		updatedModel := newModel.(Model)
+
+		require.Len(t, updatedModel.errorHistory.entries, 2)
+		assert.Equal(t, []string{"github:1"}, updatedModel.errorHistory.entries[0].sessions)
+		assert.Equal(t, "attach failed", updatedModel.error.Error())
+		assert.Contains(t, updatedModel.View(), "Error: attach failed (+1 more)")
+		assert.Contains(t, updatedModel.View(), "Test issue 123", "the session list stays visible")
+	})
+
+	t.Run("panel_lists_newest_first_and_acknowledges", func(t *testing.T) {
+		model := setupTestModel()
+		model = model.reportError(errors.New("first"))
+		model = model.reportError(errors.New("second"))
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+		updatedModel := newModel.(Model)
+		require.True(t, updatedModel.showErrors)
+		assert.Nil(t, updatedModel.error)
+
+		view := updatedModel.View()
+		assert.Less(t, strings.Index(view, "second"), strings.Index(view, "first"))
+
+		newModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.False(t, newModel.(Model).showErrors)
+	})
+
+	t.Run("history_is_bounded", func(t *testing.T) {
+		model := setupTestModel()
+		for i := 0; i < maxErrorHistory+5; i++ {
+			model = model.reportError(fmt.Errorf("error %d", i))
+		}
+		assert.Len(t, model.errorHistory.entries, maxErrorHistory)
+		assert.Equal(t, "error 5", model.errorHistory.entries[0].message)
+	})
+
+	t.Run("cleanup_failures_are_recorded_per_session", func(t *testing.T) {
+		model := setupTestModel()
+		failure := &cleanup.ResourceError{SessionID: "github:7", Resource: cleanup.ResourceSandbox, Target: "sbs-repo-7", Op: "remove", Err: errors.New("busy")}
+
+		newModel, _ := model.Update(cleanSessionsMsg{err: errors.New("failed to clean some sessions (1 error(s))"), failures: []error{failure}})
+		entries := newModel.(Model).errorHistory.entries
+		require.Len(t, entries, 2)
+		assert.Equal(t, []string{"github:7"}, entries[0].sessions)
+	})
+
+	t.Run("success_shows_toast", func(t *testing.T) {
+		model := setupTestModel()
+		model = model.reportError(errors.New("earlier"))
+
+		newModel, _ := model.Update(cleanSessionsMsg{cleanedSessions: []config.SessionMetadata{{}, {}, {}}})
+		updatedModel := newModel.(Model)
+		assert.Nil(t, updatedModel.error)
+		assert.Equal(t, "Cleaned 3 session(s)", updatedModel.notice)
+		assert.Len(t, updatedModel.errorHistory.entries, 1, "history survives the success")
+	})
+}
@@ -3,109 +3,193 @@ package tui
 import (
 	"fmt"
 	"github.com/charmbracelet/lipgloss"
+	"sbs/pkg/config"
 )
 
+// defaultThemeColors are the built-in colors, overridable with the theme config
+var defaultThemeColors = map[string]lipgloss.Color{
+	"primary":   lipgloss.Color("#7D56F4"),
+	"secondary": lipgloss.Color("#F25D94"),
+	"accent":    lipgloss.Color("#04B575"),
+	"warning":   lipgloss.Color("#FF8C00"),
+	"error":     lipgloss.Color("#FF6B6B"),
+	"muted":     lipgloss.Color("#6C7086"),
+}
+
 var (
 	// Colors
-	primaryColor   = lipgloss.Color("#7D56F4")
-	secondaryColor = lipgloss.Color("#F25D94")
-	accentColor    = lipgloss.Color("#04B575")
-	warningColor   = lipgloss.Color("#FF8C00")
-	errorColor     = lipgloss.Color("#FF6B6B")
-	mutedColor     = lipgloss.Color("#6C7086")
+	primaryColor   = defaultThemeColors["primary"]
+	secondaryColor = defaultThemeColors["secondary"]
+	accentColor    = defaultThemeColors["accent"]
+	warningColor   = defaultThemeColors["warning"]
+	errorColor     = defaultThemeColors["error"]
+	mutedColor     = defaultThemeColors["muted"]
 
 	// Base styles
+	titleStyle         lipgloss.Style
+	headerStyle        lipgloss.Style
+	selectedItemStyle  lipgloss.Style
+	normalItemStyle    lipgloss.Style
+	statusActiveStyle  lipgloss.Style
+	statusStoppedStyle lipgloss.Style
+	statusStaleStyle   lipgloss.Style
+	statusWaitingStyle lipgloss.Style
+	mutedStyle         lipgloss.Style
+	errorStyle         lipgloss.Style
+	warningStyle       lipgloss.Style
+	helpStyle          lipgloss.Style
+
+	// Table styles
+	tableHeaderStyle lipgloss.Style
+	tableCellStyle   lipgloss.Style
+	selectedRowStyle lipgloss.Style
+	waitingRowStyle  lipgloss.Style // rows of sessions waiting for input
+
+	// Modal dialog styles
+	modalBackgroundStyle  lipgloss.Style
+	modalContentStyle     lipgloss.Style
+	confirmationTextStyle lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+}
+
+// ApplyTheme sets the TUI colors from the theme config, using the built-in
+// color for any slot that isn't overridden, and rebuilds all styles
+func ApplyTheme(theme map[string]string) {
+	color := func(name string) lipgloss.Color {
+		if value, ok := theme[name]; ok && value != "" {
+			return lipgloss.Color(value)
+		}
+		return defaultThemeColors[name]
+	}
+
+	primaryColor = color("primary")
+	secondaryColor = color("secondary")
+	accentColor = color("accent")
+	warningColor = color("warning")
+	errorColor = color("error")
+	mutedColor = color("muted")
+
+	buildStyles()
+}
+
+// buildStyles derives all styles from the current colors
+func buildStyles() {
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(primaryColor).
+		Padding(0, 1)
 
 	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(primaryColor).
-			Padding(0, 1).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(primaryColor).
+		Padding(0, 1).
+		MarginBottom(1)
 
 	selectedItemStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FAFAFA")).
-				Background(primaryColor).
-				Padding(0, 1)
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(primaryColor).
+		Padding(0, 1)
 
 	normalItemStyle = lipgloss.NewStyle().
-			Padding(0, 1)
+		Padding(0, 1)
 
 	statusActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(accentColor)
+		Bold(true).
+		Foreground(accentColor)
 
 	statusStoppedStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(warningColor)
+		Bold(true).
+		Foreground(warningColor)
 
 	statusStaleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(errorColor)
+		Bold(true).
+		Foreground(errorColor)
+
+	statusWaitingStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(secondaryColor)
 
 	mutedStyle = lipgloss.NewStyle().
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Bold(true)
+		Foreground(errorColor).
+		Bold(true)
+
+	warningStyle = lipgloss.NewStyle().
+		Foreground(warningColor).
+		Bold(true)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginTop(1)
+		Foreground(mutedColor).
+		MarginTop(1)
 
-	// Table styles
 	tableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FAFAFA")).
-				Background(primaryColor).
-				Padding(0, 1).
-				AlignHorizontal(lipgloss.Left)
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(primaryColor).
+		Padding(0, 1).
+		AlignHorizontal(lipgloss.Left)
 
 	tableCellStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			AlignHorizontal(lipgloss.Left)
+		Padding(0, 1).
+		AlignHorizontal(lipgloss.Left)
 
 	selectedRowStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#44475A")).
-				Bold(true)
+		Background(lipgloss.Color("#44475A")).
+		Bold(true)
+
+	waitingRowStyle = tableCellStyle.
+		Foreground(secondaryColor).
+		Bold(true)
 
-	// Modal dialog styles
 	modalBackgroundStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#282828")).
-				Foreground(lipgloss.Color("#F8F8F2"))
+		Background(lipgloss.Color("#282828")).
+		Foreground(lipgloss.Color("#F8F8F2"))
 
 	modalContentStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#44475A")).
-				Foreground(lipgloss.Color("#F8F8F2")).
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor).
-				Padding(1, 2).
-				Bold(true)
+		Background(lipgloss.Color("#44475A")).
+		Foreground(lipgloss.Color("#F8F8F2")).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Bold(true)
 
 	confirmationTextStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#F8F8F2")).
-				Bold(true)
-)
+		Foreground(lipgloss.Color("#F8F8F2")).
+		Bold(true)
+}
 
 func FormatStatus(status string) string {
 	switch status {
 	case "active":
 		return statusActiveStyle.Render("●")
+	case "waiting":
+		return statusWaitingStyle.Render("●")
 	case "stopped":
 		return statusStoppedStyle.Render("●")
 	case "stale":
 		return statusStaleStyle.Render("●")
+	case config.StatusRepoMissing:
+		return errorStyle.Render("●")
 	default:
 		return mutedStyle.Render("●")
 	}
 }
 
+// FormatStatusWithWarning renders the status indicator followed by a warning marker when needed
+func FormatStatusWithWarning(status, warning string) string {
+	if warning == "" {
+		return FormatStatus(status)
+	}
+	return FormatStatus(status) + warningStyle.Render("!")
+}
+
 func TruncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/config"
+)
+
+// switcherChromeLines is the space taken by the prompt, counter and help line
+const switcherChromeLines = 4
+
+// SwitchModel is a minimal fuzzy finder over sessions used by sbs switch. It
+// only works with session metadata so it opens instantly.
+type SwitchModel struct {
+	sessions []config.SessionMetadata
+	matches  []int // Indexes into sessions, best match first
+	cursor   int
+	input    textinput.Model
+	height   int
+	selected *config.SessionMetadata
+	quit     bool
+}
+
+// NewSwitchModel creates a switcher over sessions with an optional initial query
+func NewSwitchModel(sessions []config.SessionMetadata, query string) *SwitchModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to filter sessions"
+	input.SetValue(query)
+	input.Focus()
+
+	m := &SwitchModel{sessions: sessions, input: input}
+	m.filter()
+	return m
+}
+
+// Init starts the cursor blinking
+func (m *SwitchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles navigation, selection and filter input
+func (m *SwitchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			m.quit = true
+			return m, tea.Quit
+		case tea.KeyEnter:
+			if len(m.matches) > 0 {
+				m.selected = &m.sessions[m.matches[m.cursor]]
+				return m, tea.Quit
+			}
+			return m, nil
+		case tea.KeyUp, tea.KeyCtrlP, tea.KeyCtrlK:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case tea.KeyDown, tea.KeyCtrlN, tea.KeyCtrlJ, tea.KeyTab:
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.filter()
+	}
+	return m, cmd
+}
+
+// View renders the prompt and the matching sessions, best match first
+func (m *SwitchModel) View() string {
+	if m.selected != nil || m.quit {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(m.input.View() + "\n")
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.sessions))) + "\n")
+
+	limit := len(m.matches)
+	if m.height > switcherChromeLines && limit > m.height-switcherChromeLines {
+		limit = m.height - switcherChromeLines
+	}
+	start := 0
+	if m.cursor >= limit {
+		start = m.cursor - limit + 1
+	}
+	for i := start; i < start+limit && i < len(m.matches); i++ {
+		line := switchLabel(m.sessions[m.matches[i]])
+		if i == m.cursor {
+			b.WriteString(selectedRowStyle.Render("▸ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	b.WriteString(helpStyle.Render("enter: switch • ↑/↓: move • esc: cancel"))
+	return b.String()
+}
+
+// Selected returns the chosen session, or nil when cancelled
+func (m *SwitchModel) Selected() *config.SessionMetadata {
+	return m.selected
+}
+
+// Matches returns the sessions matching the current query, best first
+func (m *SwitchModel) Matches() []config.SessionMetadata {
+	matches := make([]config.SessionMetadata, 0, len(m.matches))
+	for _, i := range m.matches {
+		matches = append(matches, m.sessions[i])
+	}
+	return matches
+}
+
+// filter ranks the sessions against the current query
+func (m *SwitchModel) filter() {
+	query := m.input.Value()
+	type scored struct {
+		index int
+		score int
+	}
+	var results []scored
+	for i, session := range m.sessions {
+		if score, ok := fuzzyScore(query, switchLabel(session)); ok {
+			results = append(results, scored{index: i, score: score})
+		}
+	}
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].score > results[b].score
+	})
+
+	m.matches = m.matches[:0]
+	for _, result := range results {
+		m.matches = append(m.matches, result.index)
+	}
+	m.cursor = 0
+}
+
+// switchLabel is the text a session is matched and shown as
+func switchLabel(session config.SessionMetadata) string {
+	label := session.NamespacedID
+	if session.IssueTitle != "" {
+		label += "  " + session.IssueTitle
+	}
+	if session.RepositoryName != "" {
+		label += "  (" + session.RepositoryName + ")"
+	}
+	return label
+}
+
+// fuzzyScore matches pattern as a case-insensitive subsequence of text, like
+// fzf. Consecutive characters and matches at word starts score higher.
+func fuzzyScore(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	patternRunes := []rune(strings.ToLower(pattern))
+	textRunes := []rune(strings.ToLower(text))
+
+	score, p, previous := 0, 0, -2
+	for i, r := range textRunes {
+		if p == len(patternRunes) {
+			break
+		}
+		if r != patternRunes[p] {
+			continue
+		}
+
+		score++
+		if i == previous+1 {
+			score += 3
+		}
+		if i == 0 || !unicode.IsLetter(textRunes[i-1]) && !unicode.IsDigit(textRunes[i-1]) {
+			score += 2
+		}
+		previous = i
+		p++
+	}
+
+	if p < len(patternRunes) {
+		return 0, false
+	}
+	return score, true
+}
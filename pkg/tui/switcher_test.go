@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+var switchSessions = []config.SessionMetadata{
+	{NamespacedID: "github:12", IssueTitle: "Fix login redirect", RepositoryName: "web"},
+	{NamespacedID: "github:40", IssueTitle: "Speed up CI", RepositoryName: "infra"},
+	{NamespacedID: "test:logs", IssueTitle: "Log view experiments", RepositoryName: "sbs"},
+}
+
+func switchIDs(m *SwitchModel) []string {
+	var ids []string
+	for _, session := range m.Matches() {
+		ids = append(ids, session.NamespacedID)
+	}
+	return ids
+}
+
+func typeText(m *SwitchModel, text string) {
+	for _, r := range text {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := fuzzyScore("gh12", "github:12  Fix login")
+	assert.True(t, ok)
+
+	_, ok = fuzzyScore("xyz", "github:12")
+	assert.False(t, ok)
+
+	score, ok := fuzzyScore("", "anything")
+	assert.True(t, ok)
+	assert.Zero(t, score)
+
+	consecutive, _ := fuzzyScore("log", "test:logs")
+	scattered, _ := fuzzyScore("log", "Fix login redirect glob")
+	wordStart, _ := fuzzyScore("ci", "Speed up CI")
+	midWord, _ := fuzzyScore("ci", "precision")
+	assert.Greater(t, wordStart, midWord)
+	assert.GreaterOrEqual(t, consecutive, scattered)
+}
+
+func TestSwitchModel_Filtering(t *testing.T) {
+	m := NewSwitchModel(switchSessions, "")
+	assert.Equal(t, []string{"github:12", "github:40", "test:logs"}, switchIDs(m), "no query keeps session order")
+
+	typeText(m, "ci")
+	assert.Equal(t, "github:40", switchIDs(m)[0])
+
+	m = NewSwitchModel(switchSessions, "infra")
+	assert.Equal(t, []string{"github:40"}, switchIDs(m), "initial query filters immediately")
+
+	m = NewSwitchModel(switchSessions, "zzz")
+	assert.Empty(t, m.Matches())
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, m.Selected(), "enter does nothing without matches")
+}
+
+func TestSwitchModel_Selection(t *testing.T) {
+	m := NewSwitchModel(switchSessions, "")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown}) // already at the end
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	require.NotNil(t, m.Selected())
+	assert.Equal(t, "github:40", m.Selected().NamespacedID)
+	assert.NotNil(t, cmd, "selecting quits the program")
+	assert.Empty(t, m.View())
+}
+
+func TestSwitchModel_Cancel(t *testing.T) {
+	m := NewSwitchModel(switchSessions, "")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	assert.Nil(t, m.Selected())
+	assert.NotNil(t, cmd)
+}
+
+func TestSwitchModel_View(t *testing.T) {
+	m := NewSwitchModel(switchSessions, "")
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 6})
+
+	view := m.View()
+	assert.Contains(t, view, "3/3")
+	assert.Contains(t, view, "github:12  Fix login redirect  (web)")
+	assert.NotContains(t, view, "test:logs", "rows beyond the window height are not rendered")
+}
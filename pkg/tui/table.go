@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"sbs/pkg/diffstat"
+)
+
+const (
+	// maxRenderedRows caps the rows rendered per frame, also when the terminal
+	// size is not yet known
+	maxRenderedRows = 100
+
+	// sessionViewChromeLines is the space used by the title, table header,
+	// page indicator, warning and help line around the session table
+	sessionViewChromeLines = 10
+
+	// trendWidth is the width of the status sparkline column, which covers
+	// the last trendWindow of recorded samples
+	trendWidth  = 12
+	trendWindow = time.Hour
+
+	// diffstatWidth is the width of the +adds/-dels column
+	diffstatWidth = 13
+
+	// expiresWidth is the width of the column flagging sessions close to
+	// expiring under clean_after_days
+	expiresWidth = 9
+)
+
+// rowCacheKey identifies a formatted table row. Rows are re-formatted only
+// when something shown in them changes.
+type rowCacheKey struct {
+	global   bool
+	width    int
+	selected bool
+	id       string
+	title    string
+	repo     string
+	branch   string
+	status   string
+	delta    string
+	changes  string
+	expires  string
+	trend    string
+}
+
+// maxCachedRows bounds the row cache; it is cleared when exceeded
+const maxCachedRows = 2000
+
+// rowCache memoizes formatted session rows across frames. It is shared by
+// copies of the Model, and only used from the Bubble Tea event loop.
+type rowCache struct {
+	rows map[rowCacheKey]string
+}
+
+func newRowCache() *rowCache {
+	return &rowCache{rows: make(map[rowCacheKey]string)}
+}
+
+// get returns the cached row for key, formatting it with render on a miss.
+// A nil cache formats every time.
+func (c *rowCache) get(key rowCacheKey, render func() string) string {
+	if c == nil {
+		return render()
+	}
+	if row, ok := c.rows[key]; ok {
+		return row
+	}
+	if len(c.rows) >= maxCachedRows {
+		c.clear()
+	}
+	row := render()
+	c.rows[key] = row
+	return row
+}
+
+// clear drops all cached rows, e.g. after the theme changes
+func (c *rowCache) clear() {
+	if c != nil {
+		c.rows = make(map[rowCacheKey]string)
+	}
+}
+
+// formatDiffstat renders a session's diffstat padded to the column width;
+// sessions without a stat yet show a placeholder
+func formatDiffstat(stats map[string]diffstat.Stat, id string) string {
+	stat, ok := stats[id]
+	if !ok {
+		return fmt.Sprintf("%-*s", diffstatWidth, "-")
+	}
+	return fmt.Sprintf("%-*s", diffstatWidth, TruncateString(stat.String(), diffstatWidth))
+}
+
+// sessionTableRows returns how many session rows fit on screen
+func (m Model) sessionTableRows() int {
+	if m.height <= 0 {
+		return maxRenderedRows
+	}
+
+	chrome := sessionViewChromeLines
+	if len(m.recentSessions()) > 0 {
+		chrome++ // recent sessions line under the title
+	}
+	if m.notice != "" {
+		chrome += 2
+	}
+	if m.error != nil {
+		chrome += 2 // latest error line
+	}
+	if m.historyStore != nil {
+		chrome++ // trend line under the table
+	}
+	if len(m.startProgress) > 0 {
+		chrome += lipgloss.Height(m.renderStartProgress()) + 1 // sessions being started
+	}
+	if m.cleanBatch != nil {
+		chrome += lipgloss.Height(m.cleanProgressView().render(m.now(), m.width)) + 1 // clean running in the background
+	}
+	if m.showHelp {
+		// The help view replaces the one-line help text
+		chrome += lipgloss.Height(m.helpView()) - 1
+	}
+
+	return max(1, min(maxRenderedRows, m.height-chrome))
+}
+
+// visibleRange returns the [start, end) window of rows to render: the page
+// of pageSize rows containing the cursor
+func visibleRange(total, cursor, pageSize int) (int, int) {
+	if total <= 0 {
+		return 0, 0
+	}
+	if pageSize <= 0 || total <= pageSize {
+		return 0, total
+	}
+
+	cursor = max(0, min(cursor, total-1))
+	start := (cursor / pageSize) * pageSize
+	return start, min(total, start+pageSize)
+}
+
+// formatPageIndicator describes which rows of a paged table are shown
+func formatPageIndicator(start, end, total, pageSize int) string {
+	pages := (total + pageSize - 1) / pageSize
+	return fmt.Sprintf("Showing %d-%d of %d sessions (page %d/%d, pgup/pgdn to page)",
+		start+1, end, total, start/pageSize+1, pages)
+}
+
+// pageCursor moves the cursor by a page, clamped to the session list
+func (m Model) pageCursor(direction int) Model {
+	if len(m.sessions) == 0 {
+		return m
+	}
+	pageSize := m.sessionTableRows()
+	if m.viewMode == ViewModeDashboard {
+		pageSize = m.dashboardTableRows()
+	}
+	m.cursor = max(0, min(len(m.sessions)-1, m.cursor+direction*pageSize))
+	return m
+}
@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/diffstat"
+	"sbs/pkg/expiry"
+	"sbs/pkg/status"
+	"sbs/pkg/testsupport"
+)
+
+// countingTmuxManager counts status lookups to verify only visible rows are detected
+type countingTmuxManager struct {
+	*testsupport.FakeTmuxManager
+	lookups int
+}
+
+func (c *countingTmuxManager) SessionExists(name string) (bool, error) {
+	c.lookups++
+	return c.FakeTmuxManager.SessionExists(name)
+}
+
+func manySessions(n int) []config.SessionMetadata {
+	sessions := make([]config.SessionMetadata, n)
+	for i := range sessions {
+		sessions[i] = config.SessionMetadata{
+			NamespacedID:   fmt.Sprintf("github:%d", i+1),
+			IssueTitle:     fmt.Sprintf("Issue %d", i+1),
+			RepositoryName: fmt.Sprintf("repo-%d", i%7),
+			Branch:         fmt.Sprintf("issue-%d", i+1),
+			TmuxSession:    fmt.Sprintf("sbs-%d", i+1),
+			WorktreePath:   fmt.Sprintf("/nonexistent/worktrees/issue-%d", i+1),
+		}
+	}
+	return sessions
+}
+
+func newLargeGlobalModel(t testing.TB, n int) (Model, *countingTmuxManager) {
+	tmuxManager := &countingTmuxManager{FakeTmuxManager: testsupport.NewFakeTmuxManager()}
+	model := NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    tmuxManager,
+		Sandbox: testsupport.NewFakeSandboxManager(),
+		Cleanup: &testsupport.FakeSessionCleaner{},
+	})
+	model.viewMode = ViewModeGlobal
+	model.sessions = manySessions(n)
+	model.width = 140
+	model.height = 40
+	return model, tmuxManager
+}
+
+func TestVisibleRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		cursor        int
+		pageSize      int
+		expectedStart int
+		expectedEnd   int
+	}{
+		{name: "empty", total: 0, cursor: 0, pageSize: 10, expectedStart: 0, expectedEnd: 0},
+		{name: "fits_on_one_page", total: 5, cursor: 4, pageSize: 10, expectedStart: 0, expectedEnd: 5},
+		{name: "first_page", total: 500, cursor: 3, pageSize: 30, expectedStart: 0, expectedEnd: 30},
+		{name: "second_page", total: 500, cursor: 30, pageSize: 30, expectedStart: 30, expectedEnd: 60},
+		{name: "last_partial_page", total: 500, cursor: 499, pageSize: 30, expectedStart: 480, expectedEnd: 500},
+		{name: "cursor_out_of_range", total: 50, cursor: 80, pageSize: 30, expectedStart: 30, expectedEnd: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := visibleRange(tt.total, tt.cursor, tt.pageSize)
+			assert.Equal(t, tt.expectedStart, start)
+			assert.Equal(t, tt.expectedEnd, end)
+		})
+	}
+}
+
+func TestFormatPageIndicator(t *testing.T) {
+	assert.Equal(t, "Showing 31-60 of 500 sessions (page 2/17, pgup/pgdn to page)", formatPageIndicator(30, 60, 500, 30))
+}
+
+func TestRowCache(t *testing.T) {
+	cache := newRowCache()
+	renders := 0
+	render := func() string {
+		renders++
+		return "row"
+	}
+
+	key := rowCacheKey{id: "github:1", status: "active"}
+	assert.Equal(t, "row", cache.get(key, render))
+	assert.Equal(t, "row", cache.get(key, render))
+	assert.Equal(t, 1, renders, "second lookup is served from the cache")
+
+	key.status = "stopped"
+	cache.get(key, render)
+	assert.Equal(t, 2, renders, "a status change re-formats the row")
+
+	cache.clear()
+	cache.get(key, render)
+	assert.Equal(t, 3, renders)
+
+	var nilCache *rowCache
+	assert.Equal(t, "row", nilCache.get(key, render))
+}
+
+func TestView_LargeGlobalViewRendersOnlyVisibleRows(t *testing.T) {
+	model, tmuxManager := newLargeGlobalModel(t, 500)
+
+	view := model.View()
+
+	pageSize := model.sessionTableRows()
+	require.Less(t, pageSize, 500)
+	assert.Equal(t, pageSize, tmuxManager.lookups, "status is only detected for visible rows")
+	assert.Contains(t, view, "github:1 ")
+	assert.NotContains(t, view, fmt.Sprintf("github:%d ", pageSize+1))
+	assert.Contains(t, view, fmt.Sprintf("Showing 1-%d of 500 sessions", pageSize))
+	assert.LessOrEqual(t, strings.Count(view, "\n")+1, model.height)
+}
+
+func TestView_PagingFollowsCursor(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 500)
+	pageSize := model.sessionTableRows()
+
+	result, _ := model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	model = result.(Model)
+	assert.Equal(t, pageSize, model.cursor)
+
+	view := model.View()
+	assert.Contains(t, view, fmt.Sprintf("Showing %d-%d of 500 sessions (page 2/", pageSize+1, 2*pageSize))
+	assert.NotContains(t, view, "github:1 ")
+
+	result, _ = model.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	model = result.(Model)
+	assert.Equal(t, 0, model.cursor)
+
+	// Paging past the end clamps to the last session
+	model.cursor = 495
+	result, _ = model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	assert.Equal(t, 499, result.(Model).cursor)
+}
+
+func TestView_SmallSessionListHasNoPageIndicator(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 5)
+	assert.NotContains(t, model.View(), "Showing")
+}
+
+func BenchmarkView_GlobalView500Sessions(b *testing.B) {
+	model, _ := newLargeGlobalModel(b, 500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = model.View()
+	}
+}
+
+func TestStatusHistorySparkline(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 3)
+	model.historyStore = status.NewHistoryStore(filepath.Join(t.TempDir(), "status-history.json"))
+
+	history := model.recordStatusHistory(model.sessions)
+	require.Len(t, history, 3)
+
+	updated, _ := model.Update(refreshMsg{sessions: model.sessions, history: history})
+	model = updated.(Model)
+
+	view := model.View()
+	assert.Contains(t, view, "Last hour")
+	assert.Contains(t, view, "▁", "stale sessions are drawn at the bottom of the sparkline")
+	assert.Contains(t, view, "Last hour: mostly idle")
+
+	model.statusHistory = map[string][]status.Sample{}
+	assert.Nil(t, model.recordStatusHistory(nil), "nothing due means nothing recorded")
+}
+
+// fakeDiffRepository reports the same diffstat for every branch but "gone"
+type fakeDiffRepository struct{}
+
+func (fakeDiffRepository) DefaultBaseBranch() (string, error) { return "main", nil }
+
+func (fakeDiffRepository) BranchTips(branch, base string) (string, string, error) {
+	if branch == "gone" {
+		return "", "", fmt.Errorf("unknown revision %s", branch)
+	}
+	return "tip-" + branch, "tip-" + base, nil
+}
+
+func (fakeDiffRepository) DiffStat(branch, base string) (int, int, error) { return 12, 3, nil }
+
+func TestDiffstatColumn(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 2)
+	model.sessions[1].Branch = "gone"
+	for i := range model.sessions {
+		model.sessions[i].RepositoryRoot = "/repo"
+	}
+	model.diffstatCache = diffstat.NewCache(filepath.Join(t.TempDir(), "diffstat-cache.json")).
+		WithOpener(func(string) (diffstat.Repository, error) { return fakeDiffRepository{}, nil })
+
+	assert.Nil(t, model.refreshDiffstats(model.sessions), "off unless show_diffstat is set")
+
+	model.config = &config.Config{ShowDiffstat: true}
+	assert.Nil(t, model.refreshDiffstats(model.sessions), "the global view has no diffstat column")
+
+	model.viewMode = ViewModeRepository
+	stats := model.refreshDiffstats(model.sessions)
+	require.Len(t, stats, 1)
+
+	updated, _ := model.Update(refreshMsg{sessions: model.sessions, diffstats: stats})
+	model = updated.(Model)
+
+	view := model.View()
+	assert.Contains(t, view, "CHANGES")
+	assert.Contains(t, view, "+12/-3")
+	assert.Equal(t, "-", strings.TrimSpace(formatDiffstat(stats, "github:2")), "branches without a stat show a placeholder")
+}
+
+func TestExpiresColumn(t *testing.T) {
+	model, _ := newLargeGlobalModel(t, 2)
+	model.sessions[0].LastActivity = time.Now().Add(-(12*24 + 12) * time.Hour).Format(time.RFC3339)
+	model.sessions[1].LastActivity = time.Now().Format(time.RFC3339)
+
+	assert.NotContains(t, model.View(), "EXPIRES", "off unless clean_after_days is set")
+
+	model.config = &config.Config{CleanAfterDays: 14}
+	view := model.View()
+	assert.Contains(t, view, "EXPIRES")
+	assert.Contains(t, view, "in 1d")
+	assert.Equal(t, 1, strings.Count(view, "in 1d"), "sessions far from expiring are left blank")
+}
+
+func TestNotifyExpiring(t *testing.T) {
+	var posted []string
+	notifier := expiry.NewNotifier(filepath.Join(t.TempDir(), expiry.NoticesFile), func(title, message string) error {
+		posted = append(posted, message)
+		return nil
+	})
+	model := NewModelWithDependencies(Dependencies{
+		Config:         &config.Config{CleanAfterDays: 14},
+		Tmux:           testsupport.NewFakeTmuxManager(),
+		Sandbox:        testsupport.NewFakeSandboxManager(),
+		Cleanup:        &testsupport.FakeSessionCleaner{},
+		ExpiryNotifier: notifier,
+	})
+	sessions := []config.SessionMetadata{{NamespacedID: "github:1", LastActivity: time.Now().Add(-13 * 24 * time.Hour).Format(time.RFC3339)}}
+
+	model.notifyExpiring(sessions)
+	assert.Empty(t, posted, "notifications need expiry_notify")
+
+	model.config = &config.Config{CleanAfterDays: 14, ExpiryNotify: true}
+	model.notifyExpiring(sessions)
+	model.notifyExpiring(sessions)
+	assert.Len(t, posted, 1, "each expiry is announced once")
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/timing"
+)
+
+// timingHintDuration is how long a slow command hint stays on the status line
+const timingHintDuration = 15 * time.Second
+
+// timingHintMsg carries a hint about a chronically slow command
+type timingHintMsg struct {
+	hint string
+}
+
+// checkTimings looks for an operation that chronically runs over its budget
+// and, at most once per timing.HintInterval across sbs runs, returns a hint
+// for the status line
+func (m Model) checkTimings() tea.Cmd {
+	if m.timings == nil {
+		return nil
+	}
+	store, budgets, now := m.timings, timing.Budgets(m.config.TimingBudgets), m.now()
+	return func() tea.Msg {
+		history, err := store.Load()
+		if err != nil {
+			return nil
+		}
+		findings := timing.Slow(history, budgets)
+		if len(findings) == 0 {
+			return nil
+		}
+		if claimed, err := store.ClaimHint(now); err != nil || !claimed {
+			return nil
+		}
+		return timingHintMsg{hint: formatTimingHint(findings[0])}
+	}
+}
+
+// formatTimingHint words a finding for the status line
+func formatTimingHint(finding timing.Finding) string {
+	hint := fmt.Sprintf("%s is often slow (median %s)", finding.Operation, finding.Median.Round(time.Second))
+	if finding.Hint != "" {
+		hint += ": " + finding.Hint
+	}
+	return hint + " — see sbs doctor"
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+	"sbs/pkg/testsupport"
+	"sbs/pkg/timing"
+)
+
+func TestCheckTimings(t *testing.T) {
+	start := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	store := timing.NewStore(filepath.Join(t.TempDir(), "timings.json"))
+	model := NewModelWithDependencies(Dependencies{
+		Config:  config.DefaultConfig(),
+		Tmux:    testsupport.NewFakeTmuxManager(),
+		Sandbox: testsupport.NewFakeSandboxManager(),
+		Cleanup: &testsupport.FakeSessionCleaner{},
+		Timings: store,
+		Clock:   fake,
+	})
+
+	assert.Nil(t, model.checkTimings()(), "no hint without slow commands")
+
+	for i := 0; i < timing.MinSamples; i++ {
+		require.NoError(t, store.Record(timing.OpWorktreeAdd, 42*time.Second, start))
+	}
+	msg := model.checkTimings()()
+	require.IsType(t, timingHintMsg{}, msg)
+	assert.Contains(t, msg.(timingHintMsg).hint, "git worktree add is often slow (median 42s): ")
+	assert.Contains(t, msg.(timingHintMsg).hint, "see sbs doctor")
+
+	result, cmd := model.Update(msg)
+	model = result.(Model)
+	assert.Equal(t, msg.(timingHintMsg).hint, model.notice)
+	assert.NotNil(t, cmd, "the hint is cleared after a while")
+
+	assert.Nil(t, model.checkTimings()(), "hints are shown at most once a day")
+	fake.Advance(timing.HintInterval)
+	assert.IsType(t, timingHintMsg{}, model.checkTimings()())
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/tmux"
+)
+
+func TestModel_TmuxControlEvents(t *testing.T) {
+	t.Run("no_wait_command_without_control_mode", func(t *testing.T) {
+		model := NewModel()
+		model.tmuxEvents = nil
+
+		assert.Nil(t, model.waitForTmuxEvent())
+	})
+
+	t.Run("wait_command_delivers_event", func(t *testing.T) {
+		events := make(chan tmux.ControlEvent, 1)
+		events <- tmux.ControlEvent{Type: tmux.EventSessionsChanged}
+
+		model := NewModel()
+		model.tmuxEvents = events
+
+		cmd := model.waitForTmuxEvent()
+		require.NotNil(t, cmd)
+
+		msg, ok := cmd().(tmuxEventMsg)
+		require.True(t, ok)
+		assert.False(t, msg.closed)
+		assert.Equal(t, tmux.EventSessionsChanged, msg.event.Type)
+	})
+
+	t.Run("wait_command_reports_closed_stream", func(t *testing.T) {
+		events := make(chan tmux.ControlEvent)
+		close(events)
+
+		model := NewModel()
+		model.tmuxEvents = events
+
+		msg, ok := model.waitForTmuxEvent()().(tmuxEventMsg)
+		require.True(t, ok)
+		assert.True(t, msg.closed)
+	})
+
+	t.Run("closed_stream_disables_control_mode", func(t *testing.T) {
+		model := NewModel()
+		model.tmuxEvents = make(chan tmux.ControlEvent)
+
+		updated, cmd := model.Update(tmuxEventMsg{closed: true})
+
+		assert.Nil(t, updated.(Model).tmuxEvents)
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("lifecycle_event_triggers_refresh", func(t *testing.T) {
+		model := NewModel()
+		model.tmuxEvents = make(chan tmux.ControlEvent)
+
+		_, cmd := model.Update(tmuxEventMsg{event: tmux.ControlEvent{Type: tmux.EventSessionsChanged}})
+		assert.NotNil(t, cmd)
+	})
+
+	t.Run("activity_event_keeps_listening", func(t *testing.T) {
+		model := NewModel()
+		model.tmuxEvents = make(chan tmux.ControlEvent)
+
+		updated, cmd := model.Update(tmuxEventMsg{event: tmux.ControlEvent{Type: tmux.EventActivity, PaneID: "%1"}})
+		assert.NotNil(t, cmd)
+		assert.NotNil(t, updated.(Model).tmuxEvents)
+	})
+}
+
+func TestModel_TmuxControlMux(t *testing.T) {
+	t.Run("started_mux_feeds_events_and_refreshes", func(t *testing.T) {
+		model := NewModel()
+		control := tmux.NewControlMux()
+		defer control.Stop()
+
+		updated, cmd := model.Update(tmuxControlStartedMsg{control: control, tracker: tmux.NewSessionTracker(model.tmuxManager)})
+
+		assert.NotNil(t, cmd, "a refresh watches the running sessions")
+		assert.Same(t, control, updated.(Model).tmuxControl)
+		assert.NotNil(t, updated.(Model).tmuxEvents)
+		assert.NotNil(t, updated.(Model).sessionTracker)
+	})
+
+	t.Run("quit_stops_the_mux", func(t *testing.T) {
+		model := NewModel()
+		model.tmuxControl = tmux.NewControlMux()
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		require.NotNil(t, cmd)
+		assert.IsType(t, tea.QuitMsg{}, cmd())
+
+		// A stopped mux attaches nothing, so watching reports no event
+		require.NoError(t, model.tmuxControl.Watch([]string{"sbs-quit-test"}))
+		assert.Empty(t, model.tmuxControl.Events())
+	})
+}
+
+func TestWatchedTmuxSessions(t *testing.T) {
+	sessions := []config.SessionMetadata{
+		{TmuxSession: "sbs-a"},
+		{TmuxSession: "sbs-stopped"},
+		{TmuxSession: "sbs-b"},
+	}
+	tmuxSessions := []*tmux.Session{{Name: "sbs-b"}, {Name: "sbs-a"}, {Name: "other"}}
+
+	assert.Equal(t, []string{"sbs-a", "sbs-b"}, watchedTmuxSessions(sessions, tmuxSessions))
+	assert.Empty(t, watchedTmuxSessions(sessions, nil))
+}
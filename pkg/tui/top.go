@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/clock"
+	"sbs/pkg/config"
+	"sbs/pkg/resmon"
+)
+
+const (
+	// DefaultTopInterval is how often sbs top samples processes
+	DefaultTopInterval = 2 * time.Second
+
+	// topDiskUsageInterval is how often sbs top recalculates worktree disk
+	// usage, which walks every file and is much slower than sampling processes
+	topDiskUsageInterval = time.Minute
+
+	// topChromeLines is the space taken by the title, header and help line
+	topChromeLines = 6
+)
+
+// TopSortKey is the column sbs top sorts sessions by
+type TopSortKey string
+
+const (
+	TopSortCPU    TopSortKey = "cpu"
+	TopSortMemory TopSortKey = "memory"
+	TopSortIO     TopSortKey = "io"
+	TopSortDisk   TopSortKey = "disk"
+	TopSortName   TopSortKey = "name"
+)
+
+// topSortKeys maps keys to the column they sort by
+var topSortKeys = map[string]TopSortKey{
+	"c": TopSortCPU,
+	"m": TopSortMemory,
+	"i": TopSortIO,
+	"d": TopSortDisk,
+	"n": TopSortName,
+}
+
+// TopSources are where sbs top reads sessions and processes from
+type TopSources struct {
+	Sessions func() ([]config.SessionMetadata, error)
+	PanePIDs func() (map[string][]int, error) // Pane process PIDs by tmux session name
+	Monitor  *resmon.Monitor
+	Interval time.Duration // DefaultTopInterval when zero
+	Clock    clock.Clock   // config.Clock() when nil
+}
+
+// topRow is one session's measured resource use
+type topRow struct {
+	session config.SessionMetadata
+	usage   resmon.Usage
+}
+
+// topSampleMsg delivers a new measurement of every session
+type topSampleMsg struct {
+	rows []topRow
+	err  error
+}
+
+// topTickMsg triggers the next sample
+type topTickMsg struct{}
+
+// topDiskUsageMsg delivers recalculated worktree disk usage
+type topDiskUsageMsg struct {
+	usage map[string]int64
+}
+
+// TopModel is the htop-like resource monitor behind sbs top. It shows, per
+// session, the processes in its tmux panes and sandbox and its worktree size.
+type TopModel struct {
+	sources    TopSources
+	rows       []topRow
+	err        error
+	sortKey    TopSortKey
+	reverse    bool
+	cursor     int
+	width      int
+	height     int
+	disk       map[string]int64 // Bytes used by each worktree path
+	diskLoaded time.Time
+	diskBusy   bool
+}
+
+// NewTopModel creates a resource monitor sorted by CPU use
+func NewTopModel(sources TopSources) *TopModel {
+	if sources.Interval <= 0 {
+		sources.Interval = DefaultTopInterval
+	}
+	if sources.Clock == nil {
+		sources.Clock = config.Clock()
+	}
+	return &TopModel{sources: sources, sortKey: TopSortCPU, disk: make(map[string]int64)}
+}
+
+// Init takes the first sample; CPU and IO rates appear from the second one
+func (m *TopModel) Init() tea.Cmd {
+	return m.sample()
+}
+
+// Update handles samples, the refresh timer and sorting keys
+func (m *TopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case topTickMsg:
+		return m, m.sample()
+
+	case topSampleMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.rows = msg.rows
+			m.sortRows()
+		}
+		tick := tea.Tick(m.sources.Interval, func(time.Time) tea.Msg { return topTickMsg{} })
+		if m.diskUsageDue(m.sources.Clock.Now()) {
+			return m, tea.Batch(tick, m.refreshDiskUsage())
+		}
+		return m, tick
+
+	case topDiskUsageMsg:
+		m.disk = msg.usage
+		m.diskLoaded = m.sources.Clock.Now()
+		m.diskBusy = false
+		m.sortRows()
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// handleKey moves the cursor, changes the sort order or quits
+func (m *TopModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if sortKey, exists := topSortKeys[key]; exists {
+		m.sortKey = sortKey
+		m.reverse = false
+		m.sortRows()
+		return m, nil
+	}
+
+	switch key {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.reverse = !m.reverse
+		m.sortRows()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	}
+	return m, nil
+}
+
+// sample measures every session in the background
+func (m *TopModel) sample() tea.Cmd {
+	sources := m.sources
+	return func() tea.Msg {
+		sessions, err := sources.Sessions()
+		if err != nil {
+			return topSampleMsg{err: fmt.Errorf("failed to load sessions: %w", err)}
+		}
+		panes, err := sources.PanePIDs()
+		if err != nil {
+			return topSampleMsg{err: err}
+		}
+		snapshot, err := sources.Monitor.Snapshot()
+		if err != nil {
+			return topSampleMsg{err: err}
+		}
+		return topSampleMsg{rows: measureSessions(sessions, panes, snapshot)}
+	}
+}
+
+// measureSessions sums, per session, the process trees under its tmux panes
+// and its sandbox (a sandbox started from a pane is counted once)
+func measureSessions(sessions []config.SessionMetadata, panes map[string][]int, snapshot *resmon.Snapshot) []topRow {
+	rows := make([]topRow, 0, len(sessions))
+	for _, session := range sessions {
+		roots := append([]int(nil), panes[session.TmuxSession]...)
+		if session.SandboxName != "" {
+			roots = append(roots, snapshot.WithArgs("--name", session.SandboxName)...)
+		}
+		rows = append(rows, topRow{session: session, usage: snapshot.Usage(snapshot.Tree(roots))})
+	}
+	return rows
+}
+
+// diskUsageDue reports whether worktree disk usage should be recalculated
+func (m *TopModel) diskUsageDue(now time.Time) bool {
+	return !m.diskBusy && len(m.rows) > 0 && now.Sub(m.diskLoaded) >= topDiskUsageInterval
+}
+
+// refreshDiskUsage recalculates worktree disk usage in the background
+func (m *TopModel) refreshDiskUsage() tea.Cmd {
+	m.diskBusy = true
+	paths := make([]string, 0, len(m.rows))
+	for _, row := range m.rows {
+		paths = append(paths, row.session.WorktreePath)
+	}
+	return func() tea.Msg {
+		return topDiskUsageMsg{usage: calculateDiskUsage(paths)}
+	}
+}
+
+// sortRows orders rows by the sort column, largest first (names A-Z), ties by
+// name, and keeps the cursor within range
+func (m *TopModel) sortRows() {
+	value := func(row topRow) float64 {
+		switch m.sortKey {
+		case TopSortMemory:
+			return float64(row.usage.MemoryBytes)
+		case TopSortIO:
+			return row.usage.ReadRate + row.usage.WriteRate
+		case TopSortDisk:
+			return float64(m.disk[row.session.WorktreePath])
+		case TopSortName:
+			return 0
+		default:
+			return row.usage.CPUPercent
+		}
+	}
+
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		a, b := m.rows[i], m.rows[j]
+		if m.reverse {
+			a, b = b, a
+		}
+		if va, vb := value(a), value(b); va != vb {
+			return va > vb
+		}
+		return dashboardSessionLabel(a.session) < dashboardSessionLabel(b.session)
+	})
+
+	if m.cursor >= len(m.rows) {
+		m.cursor = max(0, len(m.rows)-1)
+	}
+}
+
+// View renders the session table
+func (m *TopModel) View() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("Work Issue Orchestrator (Top) — every %s, sorted by %s", m.sources.Interval, m.sortKey)
+	if m.reverse {
+		title += " (reversed)"
+	}
+	b.WriteString(titleStyle.Render(title) + "\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("Error: "+m.err.Error()) + "\n\n")
+	}
+
+	if len(m.rows) == 0 {
+		b.WriteString(mutedStyle.Render("No work sessions found.") + "\n")
+	} else {
+		b.WriteString(tableHeaderStyle.Render(formatTopHeader()) + "\n")
+
+		pageSize := 0
+		if m.height > topChromeLines {
+			pageSize = m.height - topChromeLines
+		}
+		start, end := visibleRange(len(m.rows), m.cursor, pageSize)
+		for i := start; i < end; i++ {
+			line := m.formatTopRow(m.rows[i])
+			if i == m.cursor {
+				line = selectedRowStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		if end-start < len(m.rows) {
+			b.WriteString(mutedStyle.Render(formatPageIndicator(start, end, len(m.rows), pageSize)) + "\n")
+		}
+	}
+
+	b.WriteString(helpStyle.Render("\nsort: c cpu • m memory • i io • d disk • n name • r reverse • q quit"))
+	return b.String()
+}
+
+// topColumns is the layout of the table: label, procs, cpu, mem, read, write, disk
+const topColumns = "%-28s %5s %7s %10s %11s %11s %10s"
+
+func formatTopHeader() string {
+	return fmt.Sprintf(topColumns, "SESSION", "PROCS", "CPU%", "MEM", "READ/s", "WRITE/s", "DISK")
+}
+
+// formatTopRow renders one session's measurements
+func (m *TopModel) formatTopRow(row topRow) string {
+	label := dashboardSessionLabel(row.session)
+	if row.session.RepositoryName != "" {
+		label = row.session.RepositoryName + "/" + label
+	}
+	if len(label) > 28 {
+		label = label[:25] + "..."
+	}
+
+	disk := "…"
+	if !m.diskLoaded.IsZero() {
+		disk = formatBytes(m.disk[row.session.WorktreePath])
+	}
+
+	usage := row.usage
+	return fmt.Sprintf(topColumns,
+		label,
+		fmt.Sprintf("%d", usage.Processes),
+		fmt.Sprintf("%.1f", usage.CPUPercent),
+		formatBytes(int64(usage.MemoryBytes)),
+		formatBytes(int64(usage.ReadRate)),
+		formatBytes(int64(usage.WriteRate)),
+		disk,
+	)
+}
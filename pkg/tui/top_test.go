@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+	"sbs/pkg/resmon"
+)
+
+// writeFakeProcess writes a minimal /proc/<pid> entry with a parent and arguments
+func writeFakeProcess(t *testing.T, root, pid, ppid, rssPages string, args ...string) {
+	t.Helper()
+	dir := filepath.Join(root, pid)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	fields := make([]string, 22)
+	for i := range fields {
+		fields[i] = "0"
+	}
+	fields[1] = ppid
+	fields[21] = rssPages
+	stat := pid + " (proc) S " + strings.Join(fields[1:], " ")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cmdline"), []byte(strings.Join(args, "\x00")), 0644))
+}
+
+func TestMeasureSessions_CountsPanesAndSandbox(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProcess(t, root, "10", "1", "1", "bash")
+	writeFakeProcess(t, root, "11", "10", "1", "sandbox", "--name", "sbs-repo-1", "claude")
+	writeFakeProcess(t, root, "12", "11", "1", "claude")
+	writeFakeProcess(t, root, "20", "1", "1", "sandbox", "--name", "sbs-repo-2")
+	writeFakeProcess(t, root, "30", "1", "1", "bash")
+
+	snapshot, err := resmon.NewMonitorWithRoot(root).Snapshot()
+	require.NoError(t, err)
+
+	sessions := []config.SessionMetadata{
+		{NamespacedID: "test:one", TmuxSession: "work-one", SandboxName: "sbs-repo-1"},
+		{NamespacedID: "test:two", TmuxSession: "work-two", SandboxName: "sbs-repo-2"},
+		{NamespacedID: "test:gone", TmuxSession: "work-gone"},
+	}
+	panes := map[string][]int{"work-one": {10}, "work-two": {30}}
+
+	rows := measureSessions(sessions, panes, snapshot)
+
+	require.Len(t, rows, 3)
+	assert.Equal(t, 3, rows[0].usage.Processes, "the sandbox under the pane is counted once")
+	assert.Equal(t, 2, rows[1].usage.Processes, "a sandbox outside the panes is found by name")
+	assert.Equal(t, 0, rows[2].usage.Processes)
+}
+
+func newTestTopModel(rows ...topRow) *TopModel {
+	m := NewTopModel(TopSources{})
+	m.rows = rows
+	m.sortRows()
+	return m
+}
+
+func topLabels(m *TopModel) []string {
+	labels := make([]string, 0, len(m.rows))
+	for _, row := range m.rows {
+		labels = append(labels, row.session.NamespacedID)
+	}
+	return labels
+}
+
+func TestTopModel_Sorting(t *testing.T) {
+	m := newTestTopModel(
+		topRow{session: config.SessionMetadata{NamespacedID: "test:a", WorktreePath: "/a"}, usage: resmon.Usage{CPUPercent: 5, MemoryBytes: 300}},
+		topRow{session: config.SessionMetadata{NamespacedID: "test:b", WorktreePath: "/b"}, usage: resmon.Usage{CPUPercent: 50, MemoryBytes: 100, WriteRate: 10}},
+		topRow{session: config.SessionMetadata{NamespacedID: "test:c", WorktreePath: "/c"}, usage: resmon.Usage{CPUPercent: 20, MemoryBytes: 200, ReadRate: 99}},
+	)
+	m.disk = map[string]int64{"/a": 10, "/b": 30, "/c": 20}
+
+	assert.Equal(t, []string{"test:b", "test:c", "test:a"}, topLabels(m), "CPU is the default sort")
+
+	press := func(key string) {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	press("m")
+	assert.Equal(t, []string{"test:a", "test:c", "test:b"}, topLabels(m))
+	press("i")
+	assert.Equal(t, []string{"test:c", "test:b", "test:a"}, topLabels(m))
+	press("d")
+	assert.Equal(t, []string{"test:b", "test:c", "test:a"}, topLabels(m))
+	press("n")
+	assert.Equal(t, []string{"test:a", "test:b", "test:c"}, topLabels(m))
+	press("r")
+	assert.Equal(t, []string{"test:c", "test:b", "test:a"}, topLabels(m))
+	assert.Contains(t, m.View(), "sorted by name (reversed)")
+}
+
+func TestTopModel_SampleErrorKeepsRows(t *testing.T) {
+	m := newTestTopModel(topRow{session: config.SessionMetadata{NamespacedID: "test:a"}})
+	m.diskBusy = true
+
+	_, cmd := m.Update(topSampleMsg{err: assert.AnError})
+
+	assert.NotNil(t, cmd, "sampling continues after an error")
+	assert.Len(t, m.rows, 1)
+	view := m.View()
+	assert.Contains(t, view, "Error: "+assert.AnError.Error())
+	assert.Contains(t, view, "test:a")
+}
+
+func TestTopModel_QuitKeys(t *testing.T) {
+	m := newTestTopModel()
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.Contains(t, m.View(), "No work sessions found.")
+}
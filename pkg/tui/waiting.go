@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sbs/pkg/config"
+)
+
+// waitingSessions returns the namespaced IDs of sessions whose agent is
+// waiting for input. It runs in the background refresh, so every session is
+// checked, not just the page on screen.
+func (m Model) waitingSessions(sessions []config.SessionMetadata) map[string]bool {
+	waiting := make(map[string]bool)
+	for _, session := range sessions {
+		if session.NamespacedID != "" && m.getSessionStatus(session).Status == "waiting" {
+			waiting[session.NamespacedID] = true
+		}
+	}
+	return waiting
+}
+
+// updateWaiting records the sessions waiting for input and, when waiting_bell
+// is set, rings the terminal bell if any started waiting since the last refresh
+func (m Model) updateWaiting(waiting map[string]bool) (Model, tea.Cmd) {
+	newlyWaiting := false
+	for id := range waiting {
+		if !m.waiting[id] {
+			newlyWaiting = true
+			break
+		}
+	}
+	known := m.waitingKnown
+	m.waiting = waiting
+	m.waitingKnown = true
+
+	if newlyWaiting && known && m.config != nil && m.config.WaitingBell {
+		return m, ringBell
+	}
+	return m, nil
+}
+
+// ringBell writes the terminal bell. It goes to stderr so it can't interleave
+// with a frame being drawn on stdout.
+func ringBell() tea.Msg {
+	fmt.Fprint(os.Stderr, "\a")
+	return nil
+}
+
+// renderWaitingCount renders the title bar's count of sessions waiting for input
+func (m Model) renderWaitingCount() string {
+	if len(m.waiting) == 0 {
+		return ""
+	}
+	return " " + statusWaitingStyle.Render(fmt.Sprintf("%d waiting for input", len(m.waiting)))
+}
@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateWaiting(t *testing.T) {
+	model := setupTestModel()
+	model.config.WaitingBell = true
+
+	// Sessions already waiting when the TUI starts don't ring
+	model, cmd := model.updateWaiting(map[string]bool{"github:1": true})
+	assert.Nil(t, cmd)
+	assert.Contains(t, model.View(), "1 waiting for input")
+
+	model, cmd = model.updateWaiting(map[string]bool{"github:1": true})
+	assert.Nil(t, cmd, "no change")
+
+	model, cmd = model.updateWaiting(map[string]bool{"github:1": true, "github:2": true})
+	assert.NotNil(t, cmd, "a newly waiting session rings the bell")
+	assert.Contains(t, model.View(), "2 waiting for input")
+
+	model.config.WaitingBell = false
+	model, cmd = model.updateWaiting(map[string]bool{"github:3": true})
+	assert.Nil(t, cmd, "bell disabled")
+
+	model, _ = model.updateWaiting(map[string]bool{})
+	assert.NotContains(t, model.View(), "waiting for input")
+}
@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"sbs/pkg/cmdlog"
+	"sbs/pkg/issue"
+)
+
+// GitHubHost is the host whose authentication the github input source needs
+const GitHubHost = "github.com"
+
+// RequiredGitHubScopes are the token scopes sbs's GitHub operations need:
+// reading issues in private repositories and closing, labelling and
+// commenting on them
+var RequiredGitHubScopes = []string{"repo"}
+
+// GitHubAuth is what gh auth status reports for one host
+type GitHubAuth struct {
+	Host     string
+	LoggedIn bool
+	Account  string
+	Source   string   // Where the token comes from, e.g. "keyring" or "GH_TOKEN"
+	Scopes   []string // Empty when gh doesn't list scopes (e.g. fine-grained tokens)
+	Problem  string   // gh's explanation when not logged in
+}
+
+// MissingScopes returns the required scopes the token lacks. Tokens whose
+// scopes gh doesn't list are assumed to have them.
+func (a GitHubAuth) MissingScopes() []string {
+	if len(a.Scopes) == 0 {
+		return nil
+	}
+	var missing []string
+	for _, scope := range RequiredGitHubScopes {
+		if !containsScope(a.Scopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// String describes a working login, e.g. "octocat on github.com (keyring)"
+func (a GitHubAuth) String() string {
+	description := a.Host
+	if a.Account != "" {
+		description = a.Account + " on " + a.Host
+	}
+	if a.Source != "" {
+		description += " (" + a.Source + ")"
+	}
+	return description
+}
+
+// loggedInPattern matches both the current ("account NAME") and older ("as
+// NAME") forms of gh's login line, capturing host, account and token source
+var loggedInPattern = regexp.MustCompile(`Logged in to (\S+) (?:account|as) (\S+)(?: \(([^)]*)\))?`)
+
+// ParseGHAuthStatus extracts host's entry from gh auth status output
+func ParseGHAuthStatus(output, host string) GitHubAuth {
+	auth := GitHubAuth{Host: host}
+	inHost := false
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			// Unindented lines name the host the following lines describe
+			inHost = strings.TrimSpace(line) == host
+			continue
+		}
+		if !inHost {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if match := loggedInPattern.FindStringSubmatch(trimmed); match != nil {
+			auth.LoggedIn = true
+			auth.Account = match[2]
+			auth.Source = match[3]
+			continue
+		}
+		if _, scopes, found := strings.Cut(trimmed, "Token scopes:"); found {
+			for _, scope := range strings.Split(scopes, ",") {
+				scope = strings.Trim(strings.TrimSpace(scope), "'")
+				if scope != "" && scope != "none" {
+					auth.Scopes = append(auth.Scopes, scope)
+				}
+			}
+			continue
+		}
+		if auth.Problem == "" && (strings.HasPrefix(trimmed, "X ") || strings.HasPrefix(trimmed, "- The token")) {
+			auth.Problem = strings.TrimPrefix(trimmed, "X ")
+		}
+	}
+	return auth
+}
+
+// CheckGitHubAuth verifies that gh is logged in to GitHub with a token
+// carrying the required scopes, returning the login or an actionable error
+func CheckGitHubAuth() (GitHubAuth, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return GitHubAuth{Host: GitHubHost}, fmt.Errorf("gh command not found. Please install GitHub CLI: https://cli.github.com/")
+	}
+
+	args := []string{"auth", "status", "--hostname", GitHubHost}
+	ctx := cmdlog.LogCommandGlobal("gh", args, cmdlog.GetCaller())
+
+	cmd := exec.Command("gh", args...)
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	duration := time.Since(start)
+	ctx.LogCompletion(err == nil, getExitCode(cmd), strings.TrimSpace(string(output)), duration)
+	return evaluateGitHubAuth(ParseGHAuthStatus(string(output), GitHubHost), err)
+}
+
+// evaluateGitHubAuth turns gh auth status results into an actionable error.
+// gh's exit status decides whether it is logged in; output it can't parse
+// from a successful run is trusted.
+func evaluateGitHubAuth(auth GitHubAuth, statusErr error) (GitHubAuth, error) {
+	if statusErr != nil {
+		auth.LoggedIn = false
+		switch {
+		case auth.Source == "GH_TOKEN" || auth.Source == "GITHUB_TOKEN" || strings.Contains(auth.Problem, "_TOKEN"):
+			return auth, fmt.Errorf("GitHub token is invalid or expired (%s); replace the token in GH_TOKEN/GITHUB_TOKEN or unset it to use 'gh auth login'", describeProblem(auth))
+		case auth.Problem != "":
+			return auth, fmt.Errorf("GitHub CLI login for %s no longer works (%s); run 'gh auth login --hostname %s'", auth.Host, auth.Problem, auth.Host)
+		default:
+			return auth, fmt.Errorf("GitHub CLI is not logged in to %s; run 'gh auth login --hostname %s -s %s'", auth.Host, auth.Host, strings.Join(RequiredGitHubScopes, ","))
+		}
+	}
+	auth.LoggedIn = true
+
+	if missing := auth.MissingScopes(); len(missing) > 0 {
+		return auth, fmt.Errorf("GitHub token for %s lacks the %s scope(s) sbs needs to read and update issues; run 'gh auth refresh --hostname %s -s %s'",
+			auth, strings.Join(missing, ", "), auth.Host, strings.Join(missing, ","))
+	}
+	return auth, nil
+}
+
+// CheckInputSourceAuth verifies the credentials an input source needs.
+// Sources without credentials (such as test) always pass.
+func CheckInputSourceAuth(sourceType string) error {
+	if sourceType != "github" {
+		return nil
+	}
+	if cfg := issue.CurrentClientConfig(); cfg.UsesAPI() {
+		_, err := issue.NewAPIClient(cfg).CheckAccess()
+		return err
+	}
+	_, err := CheckGitHubAuth()
+	return err
+}
+
+// describeProblem returns gh's explanation, or a generic one
+func describeProblem(auth GitHubAuth) string {
+	if auth.Problem != "" {
+		return auth.Problem
+	}
+	return "gh auth status failed"
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
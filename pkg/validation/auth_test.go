@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGH puts a gh on PATH that prints output and exits with status
+func fakeGH(t *testing.T, output string, status int) {
+	t.Helper()
+	binDir := t.TempDir()
+	outputFile := filepath.Join(binDir, "status.txt")
+	require.NoError(t, os.WriteFile(outputFile, []byte(output), 0644))
+	script := fmt.Sprintf("#!/bin/sh\ncat '%s' >&2\nexit %d\n", outputFile, status)
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+}
+
+const currentStatusOutput = `github.com
+  ✓ Logged in to github.com account octocat (keyring)
+  - Active account: true
+  - Git operations protocol: https
+  - Token: gho_************************************
+  - Token scopes: 'gist', 'read:org', 'repo', 'workflow'
+`
+
+func TestParseGHAuthStatus(t *testing.T) {
+	auth := ParseGHAuthStatus(currentStatusOutput, "github.com")
+	assert.True(t, auth.LoggedIn)
+	assert.Equal(t, "octocat", auth.Account)
+	assert.Equal(t, "keyring", auth.Source)
+	assert.Equal(t, []string{"gist", "read:org", "repo", "workflow"}, auth.Scopes)
+	assert.Equal(t, "octocat on github.com (keyring)", auth.String())
+
+	older := "github.com\n  ✓ Logged in to github.com as octocat (/home/octocat/.config/gh/hosts.yml)\n  ✓ Token scopes: gist, read:org\n"
+	auth = ParseGHAuthStatus(older, "github.com")
+	assert.Equal(t, "octocat", auth.Account)
+	assert.Equal(t, []string{"gist", "read:org"}, auth.Scopes)
+	assert.Equal(t, []string{"repo"}, auth.MissingScopes())
+
+	other := "ghe.example.com\n  ✓ Logged in to ghe.example.com account someone (keyring)\n"
+	assert.False(t, ParseGHAuthStatus(other, "github.com").LoggedIn, "other hosts are ignored")
+
+	invalid := "github.com\n  X Failed to log in to github.com using token (GH_TOKEN)\n  - Active account: true\n  - The token in GH_TOKEN is invalid.\n"
+	auth = ParseGHAuthStatus(invalid, "github.com")
+	assert.False(t, auth.LoggedIn)
+	assert.Equal(t, "Failed to log in to github.com using token (GH_TOKEN)", auth.Problem)
+}
+
+func TestCheckGitHubAuth(t *testing.T) {
+	t.Run("logged in with repo scope", func(t *testing.T) {
+		fakeGH(t, currentStatusOutput, 0)
+		auth, err := CheckGitHubAuth()
+		require.NoError(t, err)
+		assert.Equal(t, "octocat", auth.Account)
+	})
+
+	t.Run("unlisted scopes are trusted", func(t *testing.T) {
+		fakeGH(t, "github.com\n  ✓ Logged in to github.com account octocat (GH_TOKEN)\n", 0)
+		_, err := CheckGitHubAuth()
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing repo scope", func(t *testing.T) {
+		fakeGH(t, "github.com\n  ✓ Logged in to github.com account octocat (keyring)\n  - Token scopes: 'gist'\n", 0)
+		_, err := CheckGitHubAuth()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lacks the repo scope")
+		assert.Contains(t, err.Error(), "gh auth refresh --hostname github.com -s repo")
+	})
+
+	t.Run("not logged in", func(t *testing.T) {
+		fakeGH(t, "You are not logged into any GitHub hosts. To log in, run: gh auth login\n", 1)
+		_, err := CheckGitHubAuth()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "gh auth login --hostname github.com")
+	})
+
+	t.Run("invalid environment token", func(t *testing.T) {
+		fakeGH(t, "github.com\n  X Failed to log in to github.com using token (GH_TOKEN)\n  - The token in GH_TOKEN is invalid.\n", 1)
+		_, err := CheckGitHubAuth()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GH_TOKEN/GITHUB_TOKEN")
+	})
+}
+
+func TestCheckInputSourceAuth_SkipsSourcesWithoutCredentials(t *testing.T) {
+	fakeGH(t, "", 1)
+	assert.NoError(t, CheckInputSourceAuth("test"))
+	assert.Error(t, CheckInputSourceAuth("github"))
+}
@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Where a tmux command's executable was found
+const (
+	CommandFoundLocal   = "local"
+	CommandFoundSandbox = "sandbox"
+	CommandFoundShell   = "shell" // Shell builtin or keyword
+)
+
+// shellBuiltins are command words the session's shell handles itself
+var shellBuiltins = map[string]bool{
+	".": true, ":": true, "cd": true, "echo": true, "eval": true, "exec": true,
+	"export": true, "source": true, "test": true, "true": true, "[": true,
+	"if": true, "for": true, "while": true, "{": true, "(": true,
+}
+
+// CommandLookup locates executables for CheckTmuxCommand
+type CommandLookup struct {
+	LookPath  func(file string) (string, error)     // PATH lookup; exec.LookPath when nil
+	InSandbox func(executable string) (bool, error) // Sandbox lookup; nil when there is no sandbox to check
+}
+
+// CommandCheck is the result of checking a tmux command
+type CommandCheck struct {
+	Command    string
+	Executable string // First word of the command after VAR=value assignments
+	Location   string // One of the CommandFound constants, or "" when not found
+	Path       string // Resolved path for local executables
+	Sandboxed  bool   // Whether the sandbox was searched
+	Err        error  // Sandbox lookup failure, if any
+}
+
+// Found reports whether the command's executable exists
+func (c CommandCheck) Found() bool {
+	return c.Location != ""
+}
+
+// Problem describes why the command would not run, or "" if it was found
+func (c CommandCheck) Problem() string {
+	switch {
+	case c.Found():
+		return ""
+	case c.Executable == "":
+		return "tmux_command is empty"
+	case c.Err != nil:
+		return fmt.Sprintf("%q was not found locally and the sandbox could not be checked: %v", c.Executable, c.Err)
+	case c.Sandboxed:
+		return fmt.Sprintf("%q was not found locally or in the sandbox", c.Executable)
+	default:
+		return fmt.Sprintf("%q was not found on PATH", c.Executable)
+	}
+}
+
+// CommandExecutable returns the executable a shell command line runs, skipping
+// leading VAR=value assignments
+func CommandExecutable(command string) string {
+	for _, word := range strings.Fields(command) {
+		name, _, isAssignment := strings.Cut(word, "=")
+		if isAssignment && name != "" && !strings.ContainsAny(name, "/.-") {
+			continue
+		}
+		return strings.Trim(word, `"'`)
+	}
+	return ""
+}
+
+// CheckTmuxCommand checks that the executable a tmux command runs exists: as a
+// path relative to dir, on PATH, or in the sandbox
+func CheckTmuxCommand(command, dir string, lookup CommandLookup) CommandCheck {
+	check := CommandCheck{Command: command, Executable: CommandExecutable(command)}
+	exe := check.Executable
+	if exe == "" {
+		return check
+	}
+	if shellBuiltins[exe] {
+		check.Location = CommandFoundShell
+		return check
+	}
+
+	if strings.Contains(exe, "/") {
+		if path, ok := executablePath(exe, dir); ok {
+			check.Location, check.Path = CommandFoundLocal, path
+			return check
+		}
+	} else {
+		lookPath := lookup.LookPath
+		if lookPath == nil {
+			lookPath = exec.LookPath
+		}
+		if path, err := lookPath(exe); err == nil {
+			check.Location, check.Path = CommandFoundLocal, path
+			return check
+		}
+	}
+
+	if lookup.InSandbox != nil {
+		check.Sandboxed = true
+		found, err := lookup.InSandbox(exe)
+		if err != nil {
+			check.Err = err
+		} else if found {
+			check.Location = CommandFoundSandbox
+		}
+	}
+	return check
+}
+
+// executablePath resolves a command path against dir and the home directory
+// and reports whether it is an executable file
+func executablePath(exe, dir string) (string, bool) {
+	path := exe
+	if rest, isHome := strings.CutPrefix(exe, "~/"); isHome {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		path = filepath.Join(home, rest)
+	} else if !filepath.IsAbs(exe) {
+		path = filepath.Join(dir, exe)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	return path, true
+}
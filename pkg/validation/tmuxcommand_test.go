@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandExecutable(t *testing.T) {
+	tests := map[string]string{
+		"claude --continue":              "claude",
+		"FOO=1 BAR=two ./scripts/dev.sh": "./scripts/dev.sh",
+		`"my tool" arg`:                  "my",
+		"  ":                             "",
+		"--flag=value":                   "--flag=value",
+	}
+	for command, expected := range tests {
+		assert.Equal(t, expected, CommandExecutable(command), command)
+	}
+}
+
+func TestCheckTmuxCommand(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "dev.sh"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "notes.txt"), []byte("notes"), 0644))
+
+	lookPath := func(file string) (string, error) {
+		if file == "claude" {
+			return "/usr/bin/claude", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	t.Run("found on PATH", func(t *testing.T) {
+		check := CheckTmuxCommand("claude --continue", dir, CommandLookup{LookPath: lookPath})
+		assert.True(t, check.Found())
+		assert.Equal(t, CommandFoundLocal, check.Location)
+		assert.Equal(t, "/usr/bin/claude", check.Path)
+		assert.Empty(t, check.Problem())
+	})
+
+	t.Run("relative script in the worktree", func(t *testing.T) {
+		check := CheckTmuxCommand("./scripts/dev.sh", dir, CommandLookup{LookPath: lookPath})
+		assert.Equal(t, CommandFoundLocal, check.Location)
+		assert.Equal(t, filepath.Join(dir, "scripts", "dev.sh"), check.Path)
+	})
+
+	t.Run("non-executable file", func(t *testing.T) {
+		check := CheckTmuxCommand("scripts/notes.txt", dir, CommandLookup{LookPath: lookPath})
+		assert.False(t, check.Found())
+		assert.Contains(t, check.Problem(), "not found on PATH")
+	})
+
+	t.Run("shell builtin", func(t *testing.T) {
+		check := CheckTmuxCommand("cd src && make", dir, CommandLookup{LookPath: lookPath})
+		assert.Equal(t, CommandFoundShell, check.Location)
+	})
+
+	t.Run("found in sandbox", func(t *testing.T) {
+		var searched string
+		check := CheckTmuxCommand("aider", dir, CommandLookup{
+			LookPath:  lookPath,
+			InSandbox: func(exe string) (bool, error) { searched = exe; return true, nil },
+		})
+		assert.Equal(t, "aider", searched)
+		assert.Equal(t, CommandFoundSandbox, check.Location)
+	})
+
+	t.Run("missing everywhere", func(t *testing.T) {
+		check := CheckTmuxCommand("claud", dir, CommandLookup{
+			LookPath:  lookPath,
+			InSandbox: func(string) (bool, error) { return false, nil },
+		})
+		assert.False(t, check.Found())
+		assert.Equal(t, `"claud" was not found locally or in the sandbox`, check.Problem())
+	})
+
+	t.Run("sandbox lookup fails", func(t *testing.T) {
+		check := CheckTmuxCommand("claud", dir, CommandLookup{
+			LookPath:  lookPath,
+			InSandbox: func(string) (bool, error) { return false, errors.New("sandbox unavailable") },
+		})
+		assert.False(t, check.Found())
+		assert.Contains(t, check.Problem(), "sandbox unavailable")
+	})
+}
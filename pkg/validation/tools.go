@@ -3,35 +3,42 @@ package validation
 import (
 	"fmt"
 	"os/exec"
+	"sync"
 	"time"
 
 	"sbs/pkg/cmdlog"
+	"sbs/pkg/git"
 	"sbs/pkg/issue"
 	"sbs/pkg/sandbox"
 )
 
-// CheckRequiredTools validates that all required external tools are available
+// CheckRequiredTools validates that all required external tools are available.
+// The checks run concurrently since each one spawns a process.
 func CheckRequiredTools() error {
-	var errors []string
-
-	// Check tmux
-	if err := checkTmux(); err != nil {
-		errors = append(errors, err.Error())
-	}
-
-	// Check git
-	if err := checkGit(); err != nil {
-		errors = append(errors, err.Error())
+	checks := []func() error{
+		checkTmux,
+		checkGit,
+		issue.CheckClientInstalled, // GitHub CLI, unless github_client is api
+		sandbox.CheckSandboxInstalled,
 	}
 
-	// Check gh (GitHub CLI)
-	if err := issue.CheckGHInstalled(); err != nil {
-		errors = append(errors, err.Error())
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check func() error) {
+			defer wg.Done()
+			results[i] = check()
+		}(i, check)
 	}
+	wg.Wait()
 
-	// Check sandbox
-	if err := sandbox.CheckSandboxInstalled(); err != nil {
-		errors = append(errors, err.Error())
+	// Report missing tools in a stable order
+	var errors []string
+	for _, err := range results {
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
 	}
 
 	if len(errors) > 0 {
@@ -50,7 +57,11 @@ func checkTmux() error {
 }
 
 func checkGit() error {
-	return runValidationCommand("git", []string{"--version"}, "git not found. Please install git")
+	executable := git.Executable()
+	if executable != git.DefaultExecutable {
+		return runValidationCommand(executable, []string{"--version"}, fmt.Sprintf("git_executable %s cannot be run", executable))
+	}
+	return runValidationCommand(executable, []string{"--version"}, "git not found. Please install git")
 }
 
 // runValidationCommand executes a command with logging for validation purposes
@@ -0,0 +1,233 @@
+// Package worktreefiles provisions files that git doesn't track, such as
+// .env files or dependency caches, from the main checkout into new worktrees.
+package worktreefiles
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sbs/pkg/config"
+)
+
+// ActionKind describes what happens to one copy_from_main entry
+type ActionKind string
+
+const (
+	ActionCopy    ActionKind = "copy"
+	ActionSymlink ActionKind = "symlink"
+	ActionSkip    ActionKind = "skip"
+)
+
+// ReasonExists is the skip reason for paths already present in the worktree,
+// e.g. when a session is resumed
+const ReasonExists = "already exists in worktree"
+
+// Action is the planned provisioning of one copy_from_main entry
+type Action struct {
+	Path   string // Relative to the repository root
+	Source string
+	Target string
+	Kind   ActionKind
+	Size   int64  // Bytes copied (copies only)
+	Reason string // Why the entry is skipped
+}
+
+// Plan works out how each entry is provisioned from mainRoot into worktreeRoot.
+// Missing sources, existing targets and copies larger than maxBytes are skipped;
+// a maxBytes of zero uses config.DefaultCopyFromMainMaxBytes.
+func Plan(mainRoot, worktreeRoot string, entries []config.CopyFromMainEntry, maxBytes int64) []Action {
+	if maxBytes <= 0 {
+		maxBytes = config.DefaultCopyFromMainMaxBytes
+	}
+
+	actions := make([]Action, 0, len(entries))
+	for _, entry := range entries {
+		path := filepath.Clean(entry.Path)
+		action := Action{
+			Path:   path,
+			Source: filepath.Join(mainRoot, path),
+			Target: filepath.Join(worktreeRoot, path),
+			Kind:   ActionCopy,
+		}
+		if entry.Symlink {
+			action.Kind = ActionSymlink
+		}
+
+		switch {
+		case filepath.IsAbs(entry.Path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)):
+			action.skip("path is outside the repository")
+		case !exists(action.Source):
+			action.skip("not found in main checkout")
+		case exists(action.Target):
+			action.skip(ReasonExists)
+		case action.Kind == ActionCopy:
+			size, err := treeSize(action.Source)
+			if err != nil {
+				action.skip(fmt.Sprintf("cannot read: %v", err))
+			} else if size > maxBytes {
+				action.skip(fmt.Sprintf("%s exceeds the %s limit (set \"symlink\": true to link it instead)", FormatSize(size), FormatSize(maxBytes)))
+			} else {
+				action.Size = size
+			}
+		}
+
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func (a *Action) skip(reason string) {
+	a.Kind = ActionSkip
+	a.Reason = reason
+}
+
+// Apply performs the planned actions, continuing past failures. It returns
+// the number of entries provisioned and an error describing any failures.
+func Apply(actions []Action) (int, error) {
+	var failures []string
+	applied := 0
+
+	for _, action := range actions {
+		var err error
+		switch action.Kind {
+		case ActionCopy:
+			err = copyTree(action.Source, action.Target)
+		case ActionSymlink:
+			err = symlink(action.Source, action.Target)
+		default:
+			continue
+		}
+
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", action.Path, err))
+			continue
+		}
+		applied++
+	}
+
+	if len(failures) > 0 {
+		return applied, fmt.Errorf("failed to provision %d path(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+	return applied, nil
+}
+
+// Describe renders one action for the dry-run view and progress output
+func (a Action) Describe() string {
+	switch a.Kind {
+	case ActionCopy:
+		return fmt.Sprintf("copy     %s (%s)", a.Path, FormatSize(a.Size))
+	case ActionSymlink:
+		return fmt.Sprintf("symlink  %s -> %s", a.Path, a.Source)
+	default:
+		return fmt.Sprintf("skip     %s: %s", a.Path, a.Reason)
+	}
+}
+
+// FormatSize renders a byte count using binary units
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// treeSize sums regular file sizes under path
+func treeSize(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+func symlink(source, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(source, target)
+}
+
+// copyTree copies a file or directory, preserving permissions and symlinks
+func copyTree(source, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(target, rel)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(dest, info.Mode().Perm())
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			return os.Symlink(link, dest)
+		case entry.Type().IsRegular():
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			return copyFile(path, dest, info.Mode().Perm())
+		default:
+			return nil // Sockets, devices and pipes are not copied
+		}
+	})
+}
+
+func copyFile(source, target string, mode fs.FileMode) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,124 @@
+package worktreefiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sbs/pkg/config"
+)
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), mode))
+}
+
+func TestPlan(t *testing.T) {
+	mainRoot := t.TempDir()
+	worktree := t.TempDir()
+
+	writeFile(t, filepath.Join(mainRoot, ".env"), "TOKEN=abc\n", 0600)
+	writeFile(t, filepath.Join(mainRoot, "node_modules", "pkg", "index.js"), "module.exports = {}\n", 0644)
+	writeFile(t, filepath.Join(mainRoot, "certs", "big.pem"), string(make([]byte, 2048)), 0644)
+	writeFile(t, filepath.Join(worktree, "local.json"), "{}", 0644)
+	writeFile(t, filepath.Join(mainRoot, "local.json"), "{}", 0644)
+
+	entries := []config.CopyFromMainEntry{
+		{Path: ".env"},
+		{Path: "node_modules", Symlink: true},
+		{Path: "certs"},
+		{Path: "missing"},
+		{Path: "local.json"},
+		{Path: "../outside"},
+	}
+
+	actions := Plan(mainRoot, worktree, entries, 1024)
+	require.Len(t, actions, 6)
+
+	assert.Equal(t, ActionCopy, actions[0].Kind)
+	assert.Equal(t, int64(10), actions[0].Size)
+	assert.Equal(t, filepath.Join(worktree, ".env"), actions[0].Target)
+
+	assert.Equal(t, ActionSymlink, actions[1].Kind)
+
+	assert.Equal(t, ActionSkip, actions[2].Kind)
+	assert.Contains(t, actions[2].Reason, "exceeds the 1.0 KiB limit")
+
+	assert.Equal(t, ActionSkip, actions[3].Kind)
+	assert.Equal(t, "not found in main checkout", actions[3].Reason)
+
+	assert.Equal(t, ActionSkip, actions[4].Kind)
+	assert.Equal(t, ReasonExists, actions[4].Reason)
+
+	assert.Equal(t, ActionSkip, actions[5].Kind)
+	assert.Equal(t, "path is outside the repository", actions[5].Reason)
+}
+
+func TestPlan_DefaultSizeLimit(t *testing.T) {
+	mainRoot := t.TempDir()
+	writeFile(t, filepath.Join(mainRoot, "cache", "data"), string(make([]byte, 4096)), 0644)
+
+	actions := Plan(mainRoot, t.TempDir(), []config.CopyFromMainEntry{{Path: "cache"}}, 0)
+	require.Len(t, actions, 1)
+	assert.Equal(t, ActionCopy, actions[0].Kind)
+}
+
+func TestApply(t *testing.T) {
+	mainRoot := t.TempDir()
+	worktree := t.TempDir()
+
+	writeFile(t, filepath.Join(mainRoot, ".env"), "TOKEN=abc\n", 0600)
+	writeFile(t, filepath.Join(mainRoot, "config", "dev", "settings.yaml"), "debug: true\n", 0644)
+	require.NoError(t, os.Symlink("settings.yaml", filepath.Join(mainRoot, "config", "dev", "current.yaml")))
+	writeFile(t, filepath.Join(mainRoot, "node_modules", "index.js"), "x", 0644)
+
+	actions := Plan(mainRoot, worktree, []config.CopyFromMainEntry{
+		{Path: ".env"},
+		{Path: "config/dev"},
+		{Path: "node_modules", Symlink: true},
+		{Path: "missing"},
+	}, 0)
+
+	applied, err := Apply(actions)
+	require.NoError(t, err)
+	assert.Equal(t, 3, applied)
+
+	data, err := os.ReadFile(filepath.Join(worktree, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "TOKEN=abc\n", string(data))
+
+	info, err := os.Stat(filepath.Join(worktree, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "permissions are preserved")
+
+	link, err := os.Readlink(filepath.Join(worktree, "config", "dev", "current.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "settings.yaml", link, "symlinks inside copies are preserved")
+
+	target, err := os.Readlink(filepath.Join(worktree, "node_modules"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(mainRoot, "node_modules"), target)
+
+	// Applying the same plan again fails for existing targets, while a fresh plan skips them
+	again := Plan(mainRoot, worktree, []config.CopyFromMainEntry{{Path: ".env"}}, 0)
+	assert.Equal(t, ReasonExists, again[0].Reason)
+	applied, err = Apply(again)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+}
+
+func TestAction_Describe(t *testing.T) {
+	assert.Equal(t, "copy     .env (10 B)", Action{Path: ".env", Kind: ActionCopy, Size: 10}.Describe())
+	assert.Equal(t, "symlink  node_modules -> /src/node_modules", Action{Path: "node_modules", Source: "/src/node_modules", Kind: ActionSymlink}.Describe())
+	assert.Equal(t, "skip     big: too large", Action{Path: "big", Kind: ActionSkip, Reason: "too large"}.Describe())
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KiB", FormatSize(1536))
+	assert.Equal(t, "100.0 MiB", FormatSize(config.DefaultCopyFromMainMaxBytes))
+}
@@ -2,7 +2,9 @@
 
 # Claude Code Stop Hook
 # This script captures Claude Code Stop hook data and writes it to .sbs/stop.json
-# It reads Stop hook JSON data from stdin and stores it in the project directory
+# It reads Stop hook JSON data from stdin and stores it in the project directory.
+# Notification hook data (Claude waiting for input or permission) is recorded
+# the same way, flagged with waiting_for_input so sbs can alert the user.
 
 set -euo pipefail
 
@@ -69,7 +71,18 @@ main() {
 
     # Try to extract session_id to validate this is Stop hook data
     local session_id=""
+    local hook_type="Stop"
+    local waiting_for_input="false"
+    local message=""
     if command -v jq >/dev/null 2>&1; then
+        # Notification events mean Claude is blocked on the user
+        if [[ "$(echo "${hook_data}" | jq -r '.hook_event_name // empty' 2>/dev/null || echo "")" == "Notification" ]]; then
+            hook_type="Notification"
+            waiting_for_input="true"
+            message=$(echo "${hook_data}" | jq -r '.message // empty' 2>/dev/null || echo "")
+            log_info "Claude is waiting for input: ${message}"
+        fi
+
         session_id=$(echo "${hook_data}" | jq -r '.session_id // empty' 2>/dev/null || echo "")
         if [[ -n "${session_id}" ]]; then
             log_info "Processing Stop hook data for session: ${session_id}"
@@ -96,8 +109,10 @@ main() {
     enhanced_data=$(cat <<EOF
 {
   "claude_code_hook": {
-    "hook_type": "Stop",
+    "hook_type": "${hook_type}",
     "timestamp": "${timestamp}",
+    "waiting_for_input": ${waiting_for_input},
+    "message": $(jq -n --arg message "${message}" '$message' 2>/dev/null || echo '""'),
     "environment": "${environment_type}",
     "project_directory": "${project_dir}",
     "hook_script": "$0",